@@ -19,11 +19,14 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/utils/metrics"
 )
 
 // GetAlertControllerHandler setup the routes of the router using the handler in the controller as http handler
 func (ctrl *AlertQueueController) GetAlertControllerHandler() http.Handler {
 	router := mux.NewRouter()
+	router.Use(metrics.Middleware("alert-service"))
 
 	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		if _, err := w.Write([]byte("Pong")); err != nil {
@@ -41,4 +44,5 @@ func (ctrl *AlertQueueController) GetAlertControllerHandler() http.Handler {
 
 func (ctrl *AlertQueueController) setupProtectedRoutes(router *mux.Router) {
 	router.HandleFunc("/alerts", ctrl.ThrowNewAlert).Methods("POST")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
 }
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const alertGroupCollection = "alert_groups"
+
+// AddAlertToGroup add the alert to the pending group with the key, opening it with flushAt if it doesn't exist.
+// It return true if the group has been opened
+func (md *MongoDatabase) AddAlertToGroup(key string, alert model.Alert, flushAt time.Time) (bool, error) {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertGroupCollection).
+		UpdateOne(context.TODO(),
+			bson.M{"_id": key},
+			bson.M{
+				"$push":        bson.M{"alerts": alert},
+				"$setOnInsert": bson.M{"flushAt": flushAt},
+			},
+			options.Update().SetUpsert(true))
+	if err != nil {
+		return false, utils.NewError(err, "DB ERROR")
+	}
+
+	return res.UpsertedCount > 0, nil
+}
+
+// FindAlertGroups return the pending alert groups
+func (md *MongoDatabase) FindAlertGroups() ([]model.AlertGroup, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertGroupCollection).
+		Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	groups := make([]model.AlertGroup, 0)
+
+	if err := cur.All(context.TODO(), &groups); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return groups, nil
+}
+
+// DeleteAlertGroup delete the pending alert group with the key and return it, or nil if it doesn't exist
+func (md *MongoDatabase) DeleteAlertGroup(key string) (*model.AlertGroup, error) {
+	var group model.AlertGroup
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertGroupCollection).
+		FindOneAndDelete(context.TODO(), bson.M{"_id": key}).Decode(&group)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	return &group, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestAlertGroups() {
+	defer m.db.Client.Database(m.dbname).Collection(alertGroupCollection).DeleteMany(context.TODO(), bson.M{})
+
+	key := "NEW_SERVER/myhost"
+	flushAt := utils.P("2019-11-05T19:02:03Z")
+
+	opened, err := m.db.AddAlertToGroup(key, alert1, flushAt)
+	require.NoError(m.T(), err)
+	assert.True(m.T(), opened)
+
+	opened, err = m.db.AddAlertToGroup(key, alert1, utils.P("2019-11-05T20:02:03Z"))
+	require.NoError(m.T(), err)
+	assert.False(m.T(), opened)
+
+	expected := model.AlertGroup{
+		Key:     key,
+		Alerts:  []model.Alert{alert1, alert1},
+		FlushAt: flushAt,
+	}
+
+	groups, err := m.db.FindAlertGroups()
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), []model.AlertGroup{expected}, groups)
+
+	group, err := m.db.DeleteAlertGroup(key)
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), &expected, group)
+
+	group, err = m.db.DeleteAlertGroup(key)
+	require.NoError(m.T(), err)
+	assert.Nil(m.T(), group)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const alertRoutingRuleCollection = "alert_routing_rules"
+
+// GetEnabledAlertRoutingRules return the enabled alert routing rules
+func (md *MongoDatabase) GetEnabledAlertRoutingRules() ([]model.AlertRoutingRule, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertRoutingRuleCollection).
+		Find(context.TODO(), bson.M{"enabled": true})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	rules := make([]model.AlertRoutingRule, 0)
+
+	if err := cur.All(context.TODO(), &rules); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return rules, nil
+}
@@ -17,6 +17,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -53,3 +54,75 @@ func (md *MongoDatabase) ExistNoDataAlertByHost(hostname string) (bool, error) {
 	//Return true if the count > 0
 	return val > 0, nil
 }
+
+// ExistUnacknowledgedAlert return true if there is another new or snoozed alert with the same code, host and database of alert
+func (md *MongoDatabase) ExistUnacknowledgedAlert(alert model.Alert) (bool, error) {
+	filter := bson.M{
+		"_id":         bson.M{"$ne": alert.ID},
+		"alertCode":   alert.AlertCode,
+		"alertStatus": bson.M{"$in": []string{model.AlertStatusNew, model.AlertStatusSnoozed}},
+	}
+
+	for _, key := range []string{"hostname", "dbname"} {
+		if val, ok := alert.OtherInfo[key]; ok {
+			filter["otherInfo."+key] = val
+		} else {
+			filter["otherInfo."+key] = bson.M{"$exists": false}
+		}
+	}
+
+	val, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("alerts").CountDocuments(context.TODO(), filter, &options.CountOptions{
+		Limit: utils.Intptr(1),
+	})
+	if err != nil {
+		return false, utils.NewError(err, "DB ERROR")
+	}
+
+	return val > 0, nil
+}
+
+// FindNewAlertsFrom return the new alerts raised since from
+func (md *MongoDatabase) FindNewAlertsFrom(from time.Time) ([]model.Alert, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("alerts").Find(context.TODO(),
+		bson.M{
+			"alertStatus": model.AlertStatusNew,
+			"date":        bson.M{"$gte": from},
+		},
+		options.Find().SetSort(bson.D{{Key: "alertCategory", Value: 1}, {Key: "date", Value: 1}}))
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	alerts := make([]model.Alert, 0)
+
+	if err := cur.All(context.TODO(), &alerts); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return alerts, nil
+}
+
+// WakeUpSnoozedAlerts set back to new the snoozed alerts whose snooze is expired at now
+func (md *MongoDatabase) WakeUpSnoozedAlerts(now time.Time) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("alerts").UpdateMany(context.TODO(),
+		bson.M{
+			"alertStatus":  model.AlertStatusSnoozed,
+			"snoozedUntil": bson.M{"$lte": now},
+		},
+		bson.M{
+			"$set":   bson.M{"alertStatus": model.AlertStatusNew},
+			"$unset": bson.M{"snoozedUntil": ""},
+			"$push": bson.M{"history": model.AlertHistoryEntry{
+				Date:     now,
+				Username: model.AlertSystemUsername,
+				Action:   model.AlertActionStatusChange,
+				Status:   model.AlertStatusNew,
+				Comment:  "Snooze expired",
+			}},
+		})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
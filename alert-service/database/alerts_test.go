@@ -17,6 +17,7 @@ package database
 
 import (
 	"context"
+	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -90,3 +91,91 @@ func (m *MongodbSuite) TestExistNoDataAlert_SuccessExist() {
 
 	assert.True(m.T(), exist)
 }
+
+func (m *MongodbSuite) TestExistUnacknowledgedAlert() {
+	_, err := m.db.InsertAlert(alert1)
+	defer m.db.Client.Database(m.dbname).Collection("alerts").DeleteMany(context.TODO(), bson.M{})
+	require.NoError(m.T(), err)
+
+	m.T().Run("Same alert", func(t *testing.T) {
+		exist, err := m.db.ExistUnacknowledgedAlert(alert1)
+		require.NoError(t, err)
+
+		assert.False(t, exist)
+	})
+
+	m.T().Run("Repeated alert", func(t *testing.T) {
+		repeated := alert1
+		repeated.ID = utils.Str2oid("5dd40bfb12f54dfda7b1c292")
+
+		exist, err := m.db.ExistUnacknowledgedAlert(repeated)
+		require.NoError(t, err)
+
+		assert.True(t, exist)
+	})
+
+	m.T().Run("Other host", func(t *testing.T) {
+		other := alert1
+		other.ID = utils.Str2oid("5dd40bfb12f54dfda7b1c292")
+		other.OtherInfo = map[string]interface{}{"hostname": "otherhost"}
+
+		exist, err := m.db.ExistUnacknowledgedAlert(other)
+		require.NoError(t, err)
+
+		assert.False(t, exist)
+	})
+}
+
+func (m *MongodbSuite) TestFindNewAlertsFrom() {
+	_, err := m.db.InsertAlert(alert1)
+	defer m.db.Client.Database(m.dbname).Collection("alerts").DeleteMany(context.TODO(), bson.M{})
+	require.NoError(m.T(), err)
+
+	m.T().Run("Found", func(t *testing.T) {
+		alerts, err := m.db.FindNewAlertsFrom(utils.P("2019-11-05T00:00:00Z"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []model.Alert{alert1}, alerts)
+	})
+
+	m.T().Run("Too old", func(t *testing.T) {
+		alerts, err := m.db.FindNewAlertsFrom(utils.P("2019-11-06T00:00:00Z"))
+		require.NoError(t, err)
+
+		assert.Empty(t, alerts)
+	})
+}
+
+func (m *MongodbSuite) TestWakeUpSnoozedAlerts() {
+	snoozedUntil := utils.P("2019-11-06T00:00:00Z")
+	snoozed := alert1
+	snoozed.AlertStatus = model.AlertStatusSnoozed
+	snoozed.SnoozedUntil = &snoozedUntil
+
+	_, err := m.db.InsertAlert(snoozed)
+	defer m.db.Client.Database(m.dbname).Collection("alerts").DeleteMany(context.TODO(), bson.M{})
+	require.NoError(m.T(), err)
+
+	m.T().Run("Snooze not expired", func(t *testing.T) {
+		require.NoError(t, m.db.WakeUpSnoozedAlerts(utils.P("2019-11-05T23:00:00Z")))
+
+		var out model.Alert
+		require.NoError(t, m.db.Client.Database(m.dbname).Collection("alerts").
+			FindOne(context.TODO(), bson.M{"_id": snoozed.ID}).Decode(&out))
+
+		assert.Equal(t, snoozed, out)
+	})
+
+	m.T().Run("Snooze expired", func(t *testing.T) {
+		require.NoError(t, m.db.WakeUpSnoozedAlerts(utils.P("2019-11-06T01:00:00Z")))
+
+		var out model.Alert
+		require.NoError(t, m.db.Client.Database(m.dbname).Collection("alerts").
+			FindOne(context.TODO(), bson.M{"_id": snoozed.ID}).Decode(&out))
+
+		assert.Equal(t, model.AlertStatusNew, out.AlertStatus)
+		assert.Nil(t, out.SnoozedUntil)
+		require.Len(t, out.History, 1)
+		assert.Equal(t, model.AlertSystemUsername, out.History[0].Username)
+	})
+}
@@ -43,6 +43,23 @@ type MongoDatabaseInterface interface {
 	InsertAlert(alert model.Alert) (*mongo.InsertOneResult, error)
 	// ExistNoDataAlertByHost return true if the host has associated a new NO_DATA alert
 	ExistNoDataAlertByHost(hostname string) (bool, error)
+	// ExistUnacknowledgedAlert return true if there is another new or snoozed alert with the same code, host and database of alert
+	ExistUnacknowledgedAlert(alert model.Alert) (bool, error)
+	// AddAlertToGroup add the alert to the pending group with the key, opening it with flushAt if it doesn't exist.
+	// It return true if the group has been opened
+	AddAlertToGroup(key string, alert model.Alert, flushAt time.Time) (bool, error)
+	// FindAlertGroups return the pending alert groups
+	FindAlertGroups() ([]model.AlertGroup, error)
+	// DeleteAlertGroup delete the pending alert group with the key and return it, or nil if it doesn't exist
+	DeleteAlertGroup(key string) (*model.AlertGroup, error)
+	// FindNewAlertsFrom return the new alerts raised since from
+	FindNewAlertsFrom(from time.Time) ([]model.Alert, error)
+	// WakeUpSnoozedAlerts set back to new the snoozed alerts whose snooze is expired at now
+	WakeUpSnoozedAlerts(now time.Time) error
+	// GetEnabledAlertRoutingRules return the enabled alert routing rules
+	GetEnabledAlertRoutingRules() ([]model.AlertRoutingRule, error)
+	// FindCurrentHostLocationAndEnvironment return the location and the environment of the current host
+	FindCurrentHostLocationAndEnvironment(hostname string) (string, string, error)
 }
 
 // MongoDatabase is a implementation
@@ -22,6 +22,8 @@ import (
 	"github.com/amreo/mu"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
@@ -81,3 +83,29 @@ func (md *MongoDatabase) FindMostRecentHostDataOlderThan(hostname string, t time
 
 	return out, nil
 }
+
+// FindCurrentHostLocationAndEnvironment return the location and the environment of the current host
+func (md *MongoDatabase) FindCurrentHostLocationAndEnvironment(hostname string) (string, string, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").FindOne(context.TODO(),
+		bson.M{
+			"hostname": hostname,
+			"archived": false,
+		},
+		options.FindOne().SetProjection(bson.M{"location": 1, "environment": 1}))
+	if res.Err() == mongo.ErrNoDocuments {
+		return "", "", utils.NewError(utils.ErrHostNotFound, "DB ERROR")
+	} else if res.Err() != nil {
+		return "", "", utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var out struct {
+		Location    string `bson:"location"`
+		Environment string `bson:"environment"`
+	}
+
+	if err := res.Decode(&out); err != nil {
+		return "", "", utils.NewError(err, "Decode ERROR")
+	}
+
+	return out.Location, out.Environment, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/alert-service/database"
+	"github.com/ercole-io/ercole/v2/alert-service/emailer"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// DigestJob is the job used to send a summary of the new alerts
+type DigestJob struct {
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Database contains the database layer
+	Database database.MongoDatabaseInterface
+	// Emailer contains the emailer layer
+	Emailer emailer.Emailer
+	// Config contains the dataservice global configuration
+	Config config.Configuration
+	// Log contains logger formatted
+	Log logger.Logger
+}
+
+// Run sends the digest of the alerts raised in the last DigestJob.HourThreshold hours that are still new
+func (job *DigestJob) Run() {
+	hours := job.Config.AlertService.DigestJob.HourThreshold
+	if hours <= 0 {
+		hours = 24
+	}
+
+	alerts, err := job.Database.FindNewAlertsFrom(job.TimeNow().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	if len(alerts) == 0 {
+		return
+	}
+
+	subject, text := BuildDigest(alerts, hours)
+
+	if err := job.Emailer.SendEmail(subject, text, job.Config.AlertService.DigestJob.To); err != nil {
+		job.Log.Error(err)
+	}
+}
+
+// BuildDigest return the subject and the text of the digest of alerts, summarised by category and code
+func BuildDigest(alerts []model.Alert, hours int) (subject, text string) {
+	byCategory := make(map[string][]model.Alert)
+
+	for _, alert := range alerts {
+		byCategory[alert.AlertCategory] = append(byCategory[alert.AlertCategory], alert)
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+
+	sort.Strings(categories)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%d new alert(s) in the last %d hour(s)\n", len(alerts), hours)
+
+	for _, category := range categories {
+		categoryAlerts := byCategory[category]
+
+		byCode := make(map[string]int)
+		for _, alert := range categoryAlerts {
+			byCode[alert.AlertCode]++
+		}
+
+		codes := make([]string, 0, len(byCode))
+		for code := range byCode {
+			codes = append(codes, code)
+		}
+
+		sort.Strings(codes)
+
+		fmt.Fprintf(&sb, "\n%s: %d\n", category, len(categoryAlerts))
+
+		for _, code := range codes {
+			fmt.Fprintf(&sb, "  %s: %d\n", code, byCode[code])
+		}
+
+		for _, alert := range categoryAlerts {
+			fmt.Fprintf(&sb, "  - %s %s %s\n", alert.Date.Format(time.RFC3339), alert.AlertSeverity, alert.Description)
+		}
+	}
+
+	subject = fmt.Sprintf("Ercole digest: %d new alert(s)", len(alerts))
+
+	return subject, sb.String()
+}
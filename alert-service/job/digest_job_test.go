@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package job
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+var digestAlerts = []model.Alert{
+	{
+		AlertCategory: model.AlertCategoryAgent,
+		AlertCode:     model.AlertCodeNoData,
+		AlertSeverity: model.AlertSeverityCritical,
+		Date:          utils.P("2019-11-05T10:00:00Z"),
+		Description:   "No data received from the host pippo in the last 2 day(s)",
+	},
+	{
+		AlertCategory: model.AlertCategoryLicense,
+		AlertCode:     model.AlertCodeNewOption,
+		AlertSeverity: model.AlertSeverityCritical,
+		Date:          utils.P("2019-11-05T11:00:00Z"),
+		Description:   "The database ERCOLE on pluto has enabled new features (Partitioning) on server",
+	},
+	{
+		AlertCategory: model.AlertCategoryLicense,
+		AlertCode:     model.AlertCodeNewDatabase,
+		AlertSeverity: model.AlertSeverityInfo,
+		Date:          utils.P("2019-11-05T12:00:00Z"),
+		Description:   "The database 'ERCOLE2' was created on the server pluto",
+	},
+}
+
+func TestBuildDigest(t *testing.T) {
+	subject, text := BuildDigest(digestAlerts, 24)
+
+	assert.Equal(t, "Ercole digest: 3 new alert(s)", subject)
+	assert.Equal(t, `3 new alert(s) in the last 24 hour(s)
+
+AGENT: 1
+  NO_DATA: 1
+  - 2019-11-05T10:00:00Z CRITICAL No data received from the host pippo in the last 2 day(s)
+
+LICENSE: 2
+  NEW_DATABASE: 1
+  NEW_OPTION: 1
+  - 2019-11-05T11:00:00Z CRITICAL The database ERCOLE on pluto has enabled new features (Partitioning) on server
+  - 2019-11-05T12:00:00Z INFO The database 'ERCOLE2' was created on the server pluto
+`, text)
+}
+
+func TestDigestJobRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	em := NewMockEmailer(mockCtrl)
+
+	job := DigestJob{
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:00:00Z")),
+		Database: db,
+		Emailer:  em,
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				DigestJob: config.AlertDigestJob{
+					HourThreshold: 1,
+					To:            []string{"digest@ercole.test"},
+				},
+			},
+		},
+		Log: logger.NewLogger("TEST"),
+	}
+
+	t.Run("Send digest", func(t *testing.T) {
+		db.EXPECT().FindNewAlertsFrom(utils.P("2019-11-05T13:00:00Z")).Return(digestAlerts, nil)
+		em.EXPECT().SendEmail("Ercole digest: 3 new alert(s)", gomock.Any(), []string{"digest@ercole.test"}).Return(nil)
+
+		job.Run()
+	})
+
+	t.Run("No new alerts", func(t *testing.T) {
+		db.EXPECT().FindNewAlertsFrom(utils.P("2019-11-05T13:00:00Z")).Return([]model.Alert{}, nil)
+
+		job.Run()
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().FindNewAlertsFrom(utils.P("2019-11-05T13:00:00Z")).Return(nil, aerrMock)
+
+		job.Run()
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package job contains the jobs scheduled by the alert service
+package job
+
+import (
+	"time"
+
+	"github.com/bamzi/jobrunner"
+
+	"github.com/ercole-io/ercole/v2/alert-service/database"
+	"github.com/ercole-io/ercole/v2/alert-service/emailer"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+)
+
+type JobInterface interface {
+	Init()
+}
+
+type Job struct {
+	Config   config.Configuration
+	Database database.MongoDatabaseInterface
+	Emailer  emailer.Emailer
+	TimeNow  func() time.Time
+	Log      logger.Logger
+}
+
+// snoozeExpirationCrontab is the schedule used to check the expiration of the snoozed alerts
+const snoozeExpirationCrontab = "@every 1m"
+
+func (j *Job) Init() {
+	jobrunner.Start()
+
+	snoozeExpirationJob := &SnoozeExpirationJob{
+		TimeNow:  j.TimeNow,
+		Database: j.Database,
+		Log:      j.Log,
+	}
+	if err := jobrunner.Schedule(snoozeExpirationCrontab, snoozeExpirationJob); err != nil {
+		j.Log.Errorf("Something went wrong scheduling SnoozeExpirationJob: %v", err)
+	}
+
+	if !j.Config.AlertService.DigestJob.Enabled {
+		return
+	}
+
+	digestJob := &DigestJob{
+		TimeNow:  j.TimeNow,
+		Database: j.Database,
+		Emailer:  j.Emailer,
+		Config:   j.Config,
+		Log:      j.Log,
+	}
+	if err := jobrunner.Schedule(j.Config.AlertService.DigestJob.Crontab, digestJob); err != nil {
+		j.Log.Errorf("Something went wrong scheduling DigestJob: %v", err)
+	}
+}
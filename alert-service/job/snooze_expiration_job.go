@@ -0,0 +1,40 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package job
+
+import (
+	"time"
+
+	"github.com/ercole-io/ercole/v2/alert-service/database"
+	"github.com/ercole-io/ercole/v2/logger"
+)
+
+// SnoozeExpirationJob is the job used to set back to new the alerts whose snooze is expired
+type SnoozeExpirationJob struct {
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Database contains the database layer
+	Database database.MongoDatabaseInterface
+	// Log contains logger formatted
+	Log logger.Logger
+}
+
+// Run set back to new the alerts whose snooze is expired
+func (job *SnoozeExpirationJob) Run() {
+	if err := job.Database.WakeUpSnoozedAlerts(job.TimeNow()); err != nil {
+		job.Log.Error(err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// Chat message formats
+const (
+	ChatFormatSlack string = "slack"
+	ChatFormatTeams string = "teams"
+)
+
+// ChatNotifier send the alerts to a Slack or Teams style incoming webhook
+type ChatNotifier struct {
+	config config.ChatNotifier
+	client *http.Client
+}
+
+// NewChatNotifier return a new ChatNotifier
+func NewChatNotifier(conf config.ChatNotifier) *ChatNotifier {
+	return &ChatNotifier{
+		config: conf,
+		client: &http.Client{},
+	}
+}
+
+// Name return the name of the notifier
+func (cn *ChatNotifier) Name() string {
+	return cn.config.Name
+}
+
+// Notify send the alert to the chat webhook
+func (cn *ChatNotifier) Notify(alert model.Alert) error {
+	message, err := BuildChatMessage(cn.config.Format, alert)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return utils.NewError(err, "CHAT_NOTIFIER")
+	}
+
+	return postJSON(cn.client, http.MethodPost, cn.config.URL, map[string]string{"Content-Type": "application/json"}, body, cn.config.Timeout)
+}
+
+// BuildChatMessage return the message of the alert in the chat format
+func BuildChatMessage(format string, alert model.Alert) (map[string]interface{}, error) {
+	subject, text := FormatAlert(alert)
+
+	switch format {
+	case ChatFormatSlack, "":
+		return map[string]interface{}{
+			"text": fmt.Sprintf("*%s*\n%s", subject, text),
+		}, nil
+	case ChatFormatTeams:
+		return map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    subject,
+			"title":      subject,
+			"themeColor": severityColor(alert.AlertSeverity),
+			"text":       text,
+		}, nil
+	default:
+		return nil, utils.NewErrorf("Unknown chat format: %q", format)
+	}
+}
+
+func severityColor(severity string) string {
+	switch severity {
+	case model.AlertSeverityCritical:
+		return "D70000"
+	case model.AlertSeverityWarning:
+		return "FFA500"
+	default:
+		return "0078D7"
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package notifier contains the channels used to notify the alerts
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// Notifier contains the interface of a alert notification channel
+type Notifier interface {
+	// Name return the name of the notifier
+	Name() string
+	// Notify send the alert to the channel
+	Notify(alert model.Alert) error
+}
+
+// BuildNotifiers return the enabled notifiers described in the configuration, each wrapped with its retry policy
+func BuildNotifiers(conf config.Notifiers, log logger.Logger) []Notifier {
+	notifiers := make([]Notifier, 0)
+
+	for _, c := range conf.Webhooks {
+		if c.Enabled {
+			notifiers = append(notifiers, WithRetry(NewWebhookNotifier(c), c.Retry, log))
+		}
+	}
+
+	for _, c := range conf.Chats {
+		if c.Enabled {
+			notifiers = append(notifiers, WithRetry(NewChatNotifier(c), c.Retry, log))
+		}
+	}
+
+	for _, c := range conf.Syslogs {
+		if c.Enabled {
+			notifiers = append(notifiers, WithRetry(NewSyslogNotifier(c), c.Retry, log))
+		}
+	}
+
+	return notifiers
+}
+
+// FormatAlert return the subject and the text that describe the alert
+func FormatAlert(alert model.Alert) (subject, text string) {
+	if val, ok := alert.OtherInfo["hostname"]; ok {
+		subject = fmt.Sprintf("%s %s on %s", alert.AlertSeverity, alert.Description, val)
+		text = fmt.Sprintf("Date: %s\nSeverity: %s\nHost: %s\nCode: %s\n%s", alert.Date, alert.AlertSeverity, val, alert.AlertCode, alert.Description)
+	} else {
+		subject = fmt.Sprintf("%s %s", alert.AlertSeverity, alert.Description)
+		text = fmt.Sprintf("Date: %s\nSeverity: %s\nCode: %s\n%s", alert.Date, alert.AlertSeverity, alert.AlertCode, alert.Description)
+	}
+
+	return subject, text
+}
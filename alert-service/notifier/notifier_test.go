@@ -0,0 +1,261 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+var testAlert = model.Alert{
+	ID:                      utils.Str2oid("5dc3f534db7e81a98b726a52"),
+	AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
+	AlertCategory:           model.AlertCategoryLicense,
+	AlertCode:               model.AlertCodeNewLicense,
+	AlertSeverity:           model.AlertSeverityCritical,
+	AlertStatus:             model.AlertStatusNew,
+	Description:             `A new Enterprise license has been enabled to "test-db"`,
+	Date:                    utils.P("2019-09-02T10:25:28Z"),
+	OtherInfo:               map[string]interface{}{"hostname": "test-db"},
+}
+
+func TestBuildNotifiers(t *testing.T) {
+	conf := config.Notifiers{
+		Webhooks: []config.WebhookNotifier{{Name: "hook", Enabled: true}, {Name: "disabled"}},
+		Chats:    []config.ChatNotifier{{Name: "chat", Enabled: true}},
+		Syslogs:  []config.SyslogNotifier{{Name: "syslog", Enabled: true}},
+	}
+
+	notifiers := BuildNotifiers(conf, logger.NewLogger("TEST"))
+
+	require.Len(t, notifiers, 3)
+	assert.Equal(t, "hook", notifiers[0].Name())
+	assert.Equal(t, "chat", notifiers[1].Name())
+	assert.Equal(t, "syslog", notifiers[2].Name())
+}
+
+func TestWebhookNotifier_Template(t *testing.T) {
+	var received map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(raw, &received))
+	}))
+	defer ts.Close()
+
+	wn := NewWebhookNotifier(config.WebhookNotifier{
+		Name:         "hook",
+		URL:          ts.URL,
+		Method:       http.MethodPut,
+		Headers:      map[string]string{"X-Token": "secret"},
+		BodyTemplate: `{"summary": {{json .Subject}}, "code": {{json .Alert.AlertCode}}, "host": {{json (index .Alert.OtherInfo "hostname")}}}`,
+	})
+
+	require.NoError(t, wn.Notify(testAlert))
+	assert.Equal(t, map[string]interface{}{
+		"summary": `CRITICAL A new Enterprise license has been enabled to "test-db" on test-db`,
+		"code":    "NEW_LICENSE",
+		"host":    "test-db",
+	}, received)
+}
+
+func TestWebhookNotifier_DefaultBodyAndError(t *testing.T) {
+	var received model.Alert
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	wn := NewWebhookNotifier(config.WebhookNotifier{Name: "hook", URL: ts.URL})
+
+	err := wn.Notify(testAlert)
+	assert.Error(t, err)
+	assert.Equal(t, testAlert.ID, received.ID)
+	assert.Equal(t, testAlert.AlertCode, received.AlertCode)
+}
+
+func TestBuildChatMessage(t *testing.T) {
+	slack, err := BuildChatMessage(ChatFormatSlack, testAlert)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"text": "*CRITICAL A new Enterprise license has been enabled to \"test-db\" on test-db*\n" +
+			"Date: 2019-09-02 10:25:28 +0000 UTC\nSeverity: CRITICAL\nHost: test-db\nCode: NEW_LICENSE\n" +
+			"A new Enterprise license has been enabled to \"test-db\"",
+	}, slack)
+
+	teams, err := BuildChatMessage(ChatFormatTeams, testAlert)
+	require.NoError(t, err)
+	assert.Equal(t, "MessageCard", teams["@type"])
+	assert.Equal(t, "D70000", teams["themeColor"])
+
+	_, err = BuildChatMessage("irc", testAlert)
+	assert.Error(t, err)
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	msg := FormatSyslogMessage(config.SyslogNotifier{}, "ercole-server", testAlert)
+
+	assert.Equal(t, `<130>1 2019-09-02T10:25:28Z ercole-server ercole - NEW_LICENSE `+
+		`[ercole@32473 category="LICENSE" code="NEW_LICENSE" hostname="test-db" id="5dc3f534db7e81a98b726a52" severity="CRITICAL" technology="Oracle/Database"] `+
+		`CRITICAL A new Enterprise license has been enabled to "test-db" on test-db`, msg)
+
+	alert := testAlert
+	alert.AlertSeverity = model.AlertSeverityInfo
+	alert.OtherInfo = map[string]interface{}{"hostname": `a"b]c`}
+	alert.ID = primitive.NilObjectID
+
+	facility := 1
+	msg = FormatSyslogMessage(config.SyslogNotifier{Facility: &facility, AppName: "app"}, "-", alert)
+	assert.Contains(t, msg, "<14>1 ")
+	assert.Contains(t, msg, ` - app - `)
+	assert.Contains(t, msg, `hostname="a\"b\]c"`)
+
+	kern := 0
+	msg = FormatSyslogMessage(config.SyslogNotifier{Facility: &kern}, "-", alert)
+	assert.Contains(t, msg, "<6>1 ")
+
+	invalid := 24
+	msg = FormatSyslogMessage(config.SyslogNotifier{Facility: &invalid}, "-", alert)
+	assert.Contains(t, msg, "<134>1 ")
+}
+
+func TestSyslogNotifier_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		raw, _ := io.ReadAll(conn)
+		received <- string(raw)
+	}()
+
+	sn := NewSyslogNotifier(config.SyslogNotifier{Name: "syslog", Network: "tcp", Address: ln.Addr().String()})
+	sn.hostname = "ercole-server"
+
+	require.NoError(t, sn.Notify(testAlert))
+
+	expected := FormatSyslogMessage(sn.config, "ercole-server", testAlert)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, expected, msg[len(msg)-len(expected):])
+		assert.Regexp(t, `^\d+ <130>1 `, msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog message not received")
+	}
+}
+
+type fakeNotifier struct {
+	errs  []error
+	calls int
+}
+
+func (fn *fakeNotifier) Name() string {
+	return "fake"
+}
+
+func (fn *fakeNotifier) Notify(alert model.Alert) error {
+	fn.calls++
+	if len(fn.errs) == 0 {
+		return nil
+	}
+
+	err := fn.errs[0]
+	fn.errs = fn.errs[1:]
+
+	return err
+}
+
+func TestRetryNotifier(t *testing.T) {
+	errFail := errors.New("failure")
+
+	t.Run("Succeed after retries", func(t *testing.T) {
+		fn := &fakeNotifier{errs: []error{errFail, errFail}}
+		sleeps := make([]time.Duration, 0)
+
+		rn := WithRetry(fn, config.NotifierRetry{MaxAttempts: 5, InitialBackoff: 100, MaxBackoff: 150, BackoffMultiplier: 2}, logger.NewLogger("TEST"))
+		rn.Sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+		assert.NoError(t, rn.Notify(testAlert))
+		rn.wait()
+		assert.Equal(t, 3, fn.calls)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 150 * time.Millisecond}, sleeps)
+	})
+
+	t.Run("Attempts exhausted", func(t *testing.T) {
+		fn := &fakeNotifier{errs: []error{errFail, errFail, errFail}}
+
+		rn := WithRetry(fn, config.NotifierRetry{MaxAttempts: 2}, logger.NewLogger("TEST"))
+		rn.Sleep = func(d time.Duration) {}
+
+		assert.NoError(t, rn.Notify(testAlert), "the retries must be in background")
+		rn.wait()
+		assert.Equal(t, 2, fn.calls)
+	})
+
+	t.Run("Retries don't block the caller", func(t *testing.T) {
+		fn := &fakeNotifier{errs: []error{errFail}}
+		release := make(chan struct{})
+
+		rn := WithRetry(fn, config.NotifierRetry{MaxAttempts: 2}, logger.NewLogger("TEST"))
+		rn.Sleep = func(d time.Duration) { <-release }
+
+		assert.NoError(t, rn.Notify(testAlert))
+		assert.Equal(t, 1, fn.calls)
+
+		close(release)
+		rn.wait()
+		assert.Equal(t, 2, fn.calls)
+	})
+
+	t.Run("No retry policy", func(t *testing.T) {
+		fn := &fakeNotifier{errs: []error{errFail}}
+
+		rn := WithRetry(fn, config.NotifierRetry{}, logger.NewLogger("TEST"))
+
+		assert.ErrorIs(t, rn.Notify(testAlert), errFail)
+		assert.Equal(t, 1, fn.calls)
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package notifier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// RetryNotifier wraps a notifier retrying the failed notifications with an exponential backoff.
+// The retries are made in background, so a failing channel doesn't block the queue of the alerts
+type RetryNotifier struct {
+	// Notifier contains the wrapped notifier
+	Notifier Notifier
+	// Retry contains the retry policy
+	Retry config.NotifierRetry
+	// Sleep contains a function that waits for the duration d
+	Sleep func(d time.Duration)
+	// Log contains logger formatted
+	Log logger.Logger
+
+	// retries contains the retries in progress
+	retries sync.WaitGroup
+}
+
+// WithRetry wraps the notifier n with the retry policy
+func WithRetry(n Notifier, retry config.NotifierRetry, log logger.Logger) *RetryNotifier {
+	return &RetryNotifier{
+		Notifier: n,
+		Retry:    retry,
+		Sleep:    time.Sleep,
+		Log:      log,
+	}
+}
+
+// Name return the name of the wrapped notifier
+func (rn *RetryNotifier) Name() string {
+	return rn.Notifier.Name()
+}
+
+// Notify send the alert to the wrapped notifier. If the first attempt fails and the policy allows more attempts,
+// the alert is sent again in background until it succeeds or the attempts are exhausted, and nil is returned
+func (rn *RetryNotifier) Notify(alert model.Alert) error {
+	attempts := rn.Retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	err := rn.Notifier.Notify(alert)
+	if err == nil || attempts == 1 {
+		return err
+	}
+
+	rn.Log.Warnf("Notifier %s failed attempt %d/%d: %s", rn.Name(), 1, attempts, err)
+
+	rn.retries.Add(1)
+
+	go func() {
+		defer rn.retries.Done()
+
+		if err := rn.retry(alert, attempts); err != nil {
+			rn.Log.Errorf("Notifier %s: %s", rn.Name(), err)
+		}
+	}()
+
+	return nil
+}
+
+// retry send the alert again after the backoffs, from the second attempt
+func (rn *RetryNotifier) retry(alert model.Alert, attempts int) error {
+	backoff := time.Duration(rn.Retry.InitialBackoff) * time.Millisecond
+	maxBackoff := time.Duration(rn.Retry.MaxBackoff) * time.Millisecond
+
+	multiplier := rn.Retry.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	var err error
+
+	for i := 2; i <= attempts; i++ {
+		rn.Sleep(backoff)
+
+		if err = rn.Notifier.Notify(alert); err == nil {
+			return nil
+		}
+
+		if i < attempts {
+			rn.Log.Warnf("Notifier %s failed attempt %d/%d: %s", rn.Name(), i, attempts, err)
+		}
+
+		backoff = time.Duration(float64(backoff) * multiplier)
+		if maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return err
+}
+
+// wait waits the end of the retries in progress
+func (rn *RetryNotifier) wait() {
+	rn.retries.Wait()
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package notifier
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const (
+	defaultSyslogFacility = 16 // local0
+	maxSyslogFacility     = 23 // local7
+	defaultSyslogAppName  = "ercole"
+	// syslogSDID is the SD-ID of the structured data element, 32473 is the enterprise number reserved for documentation
+	syslogSDID = "ercole@32473"
+)
+
+// SyslogNotifier send the alerts to a RFC 5424 syslog receiver
+type SyslogNotifier struct {
+	config   config.SyslogNotifier
+	hostname string
+	dial     func(network, address string, timeout time.Duration) (net.Conn, error)
+}
+
+// NewSyslogNotifier return a new SyslogNotifier
+func NewSyslogNotifier(conf config.SyslogNotifier) *SyslogNotifier {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &SyslogNotifier{
+		config:   conf,
+		hostname: hostname,
+		dial:     net.DialTimeout,
+	}
+}
+
+// Name return the name of the notifier
+func (sn *SyslogNotifier) Name() string {
+	return sn.config.Name
+}
+
+// Notify send the alert to the syslog receiver
+func (sn *SyslogNotifier) Notify(alert model.Alert) error {
+	network := sn.config.Network
+	if network == "" {
+		network = "udp"
+	}
+
+	timeout := defaultTimeout
+	if sn.config.Timeout > 0 {
+		timeout = time.Duration(sn.config.Timeout) * time.Second
+	}
+
+	conn, err := sn.dial(network, sn.config.Address, timeout)
+	if err != nil {
+		return utils.NewError(err, "SYSLOG_NOTIFIER")
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return utils.NewError(err, "SYSLOG_NOTIFIER")
+	}
+
+	msg := FormatSyslogMessage(sn.config, sn.hostname, alert)
+
+	if strings.HasPrefix(network, "tcp") {
+		// octet counting framing, RFC 6587
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		return utils.NewError(err, "SYSLOG_NOTIFIER")
+	}
+
+	return nil
+}
+
+// FormatSyslogMessage return the RFC 5424 message of the alert
+func FormatSyslogMessage(conf config.SyslogNotifier, hostname string, alert model.Alert) string {
+	facility := defaultSyslogFacility
+	if conf.Facility != nil && *conf.Facility >= 0 && *conf.Facility <= maxSyslogFacility {
+		facility = *conf.Facility
+	}
+
+	appName := conf.AppName
+	if appName == "" {
+		appName = defaultSyslogAppName
+	}
+
+	params := map[string]string{
+		"id":       alert.ID.Hex(),
+		"category": alert.AlertCategory,
+		"code":     alert.AlertCode,
+		"severity": alert.AlertSeverity,
+	}
+
+	if alert.AlertAffectedTechnology != nil {
+		params["technology"] = *alert.AlertAffectedTechnology
+	}
+
+	if val, ok := alert.OtherInfo["hostname"]; ok {
+		params["hostname"] = fmt.Sprint(val)
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var sd strings.Builder
+
+	sd.WriteString("[" + syslogSDID)
+
+	for _, k := range keys {
+		fmt.Fprintf(&sd, " %s=\"%s\"", k, escapeSDParam(params[k]))
+	}
+
+	sd.WriteString("]")
+
+	subject, _ := FormatAlert(alert)
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		facility*8+syslogSeverity(alert.AlertSeverity),
+		alert.Date.UTC().Format(time.RFC3339),
+		hostname,
+		appName,
+		alert.AlertCode,
+		sd.String(),
+		subject)
+}
+
+func syslogSeverity(severity string) int {
+	switch severity {
+	case model.AlertSeverityCritical:
+		return 2
+	case model.AlertSeverityWarning:
+		return 4
+	default:
+		return 6
+	}
+}
+
+var sdParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+func escapeSDParam(s string) string {
+	return sdParamEscaper.Replace(s)
+}
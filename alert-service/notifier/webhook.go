@@ -0,0 +1,148 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// WebhookTemplateData contains the values available to the body template of a webhook
+type WebhookTemplateData struct {
+	Alert   model.Alert
+	Subject string
+	Text    string
+}
+
+// WebhookNotifier send the alerts to a generic HTTP endpoint
+type WebhookNotifier struct {
+	config config.WebhookNotifier
+	client *http.Client
+}
+
+// NewWebhookNotifier return a new WebhookNotifier
+func NewWebhookNotifier(conf config.WebhookNotifier) *WebhookNotifier {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.DisableSSLCertificateValidation {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &WebhookNotifier{
+		config: conf,
+		client: &http.Client{Transport: transport},
+	}
+}
+
+// Name return the name of the notifier
+func (wn *WebhookNotifier) Name() string {
+	return wn.config.Name
+}
+
+// Notify send the alert to the webhook
+func (wn *WebhookNotifier) Notify(alert model.Alert) error {
+	body, err := wn.buildBody(alert)
+	if err != nil {
+		return utils.NewError(err, "WEBHOOK_NOTIFIER")
+	}
+
+	method := wn.config.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	for k, v := range wn.config.Headers {
+		headers[k] = v
+	}
+
+	return postJSON(wn.client, method, wn.config.URL, headers, body, wn.config.Timeout)
+}
+
+func (wn *WebhookNotifier) buildBody(alert model.Alert) ([]byte, error) {
+	if wn.config.BodyTemplate == "" {
+		return json.Marshal(alert)
+	}
+
+	tmpl, err := template.New(wn.config.Name).
+		Funcs(template.FuncMap{"json": toJSON}).
+		Parse(wn.config.BodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, text := FormatAlert(alert)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, WebhookTemplateData{Alert: alert, Subject: subject, Text: text}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+func postJSON(client *http.Client, method, url string, headers map[string]string, body []byte, timeout int) error {
+	t := defaultTimeout
+	if timeout > 0 {
+		t = time.Duration(timeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return utils.NewError(err, "WEBHOOK_NOTIFIER")
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return utils.NewError(err, "WEBHOOK_NOTIFIER")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return utils.NewError(fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody)), "WEBHOOK_NOTIFIER")
+	}
+
+	return nil
+}
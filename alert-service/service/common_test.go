@@ -23,6 +23,7 @@ import (
 
 //go:generate mockgen -source ../database/database.go -destination=fake_database_test.go -package=service
 //go:generate mockgen -source ../emailer/emailer.go -destination=fake_emailer_test.go -package=service
+//go:generate mockgen -source ../notifier/notifier.go -destination=fake_notifier_test.go -package=service
 
 //Common data
 var errMock error = errors.New("MockError")
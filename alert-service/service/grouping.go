@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// alertGroupKey return the key used to group the alert, made by the code and the host
+func alertGroupKey(alert model.Alert) string {
+	return fmt.Sprintf("%s/%v", alert.AlertCode, alert.OtherInfo["hostname"])
+}
+
+// alertGroupRetryDelay is the delay before retrying to flush a group that can't be read from the database
+const alertGroupRetryDelay = time.Minute
+
+// groupAlert keeps the alert in its pending group until the end of the grouping window of its key.
+// The groups are stored in the database, so they survive the restarts of the service
+func (as *AlertService) groupAlert(alert model.Alert) {
+	key := alertGroupKey(alert)
+	window := time.Duration(as.Config.AlertService.Grouping.Window) * time.Second
+
+	opened, err := as.Database.AddAlertToGroup(key, alert, as.TimeNow().Add(window))
+	if err != nil {
+		as.Log.Error(err)
+		as.sendAlert(alert)
+
+		return
+	}
+
+	if opened {
+		as.scheduleAlertGroupFlush(key, window)
+	}
+}
+
+// restoreAlertGroups schedules the flush of the groups left pending by the previous run of the service
+func (as *AlertService) restoreAlertGroups() {
+	groups, err := as.Database.FindAlertGroups()
+	if err != nil {
+		as.Log.Error(err)
+		return
+	}
+
+	now := as.TimeNow()
+
+	for _, group := range groups {
+		as.scheduleAlertGroupFlush(group.Key, group.FlushAt.Sub(now))
+	}
+}
+
+func (as *AlertService) scheduleAlertGroupFlush(key string, delay time.Duration) {
+	time.AfterFunc(delay, func() { as.flushAlertGroup(key) })
+}
+
+// flushAlertGroup sends the alerts grouped by key as a single notification
+func (as *AlertService) flushAlertGroup(key string) {
+	group, err := as.Database.DeleteAlertGroup(key)
+	if err != nil {
+		as.Log.Error(err)
+		as.scheduleAlertGroupFlush(key, alertGroupRetryDelay)
+
+		return
+	}
+
+	if group == nil || len(group.Alerts) == 0 {
+		return
+	}
+
+	as.sendAlert(mergeAlerts(group.Alerts))
+}
+
+// mergeAlerts return an alert that summarises alerts, with the highest severity among them
+func mergeAlerts(alerts []model.Alert) model.Alert {
+	if len(alerts) == 1 {
+		return alerts[0]
+	}
+
+	merged := alerts[0]
+
+	merged.OtherInfo = make(map[string]interface{}, len(alerts[0].OtherInfo)+1)
+	for k, v := range alerts[0].OtherInfo {
+		merged.OtherInfo[k] = v
+	}
+
+	for _, alert := range alerts[1:] {
+		if severityRank(alert.AlertSeverity) > severityRank(merged.AlertSeverity) {
+			merged.AlertSeverity = alert.AlertSeverity
+		}
+	}
+
+	merged.Description = fmt.Sprintf("%s (and %d more %s alerts)", alerts[0].Description, len(alerts)-1, merged.AlertCode)
+	merged.OtherInfo["groupedAlerts"] = len(alerts)
+
+	return merged
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case model.AlertSeverityCritical:
+		return 2
+	case model.AlertSeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestMergeAlerts(t *testing.T) {
+	alerts := []model.Alert{
+		{
+			AlertCode:     model.AlertCodeNewOption,
+			AlertSeverity: model.AlertSeverityWarning,
+			Description:   "The database ERCOLE on pluto has enabled new features (Partitioning) on server",
+			OtherInfo:     map[string]interface{}{"hostname": "pluto"},
+		},
+		{
+			AlertCode:     model.AlertCodeNewOption,
+			AlertSeverity: model.AlertSeverityCritical,
+			Description:   "The database ERCOLE2 on pluto has enabled new features (Partitioning) on server",
+			OtherInfo:     map[string]interface{}{"hostname": "pluto"},
+		},
+	}
+
+	t.Run("Single alert", func(t *testing.T) {
+		assert.Equal(t, alerts[0], mergeAlerts(alerts[:1]))
+	})
+
+	t.Run("Many alerts", func(t *testing.T) {
+		expected := model.Alert{
+			AlertCode:     model.AlertCodeNewOption,
+			AlertSeverity: model.AlertSeverityCritical,
+			Description:   "The database ERCOLE on pluto has enabled new features (Partitioning) on server (and 1 more NEW_OPTION alerts)",
+			OtherInfo:     map[string]interface{}{"hostname": "pluto", "groupedAlerts": 2},
+		}
+
+		assert.Equal(t, expected, mergeAlerts(alerts))
+		assert.NotContains(t, alerts[0].OtherInfo, "groupedAlerts")
+	})
+}
+
+func TestProcessAlertInsertion_Grouping(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	as := AlertService{
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Queue:    hub.New(),
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				Emailer: config.Emailer{
+					To: []string{"test@ercole.test"},
+				},
+				Grouping: config.AlertGrouping{
+					Enabled: true,
+					Window:  3600,
+				},
+			},
+		},
+	}
+
+	alert := model.Alert{
+		AlertCategory: model.AlertCategoryLicense,
+		AlertCode:     model.AlertCodeNewOption,
+		AlertSeverity: model.AlertSeverityCritical,
+		Description:   "The database ERCOLE on pluto has enabled new features (Partitioning) on server",
+		OtherInfo:     map[string]interface{}{"hostname": "pluto"},
+	}
+
+	key := alertGroupKey(alert)
+	flushAt := utils.P("2019-11-05T17:02:03Z")
+
+	t.Run("Grouped", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().AddAlertToGroup(key, alert, flushAt).Return(true, nil),
+			db.EXPECT().AddAlertToGroup(key, alert, flushAt).Return(false, nil).Times(2),
+		)
+
+		for i := 0; i < 3; i++ {
+			as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+		}
+
+		db.EXPECT().DeleteAlertGroup(key).
+			Return(&model.AlertGroup{Key: key, Alerts: []model.Alert{alert, alert, alert}, FlushAt: flushAt}, nil)
+		db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
+		emailer.EXPECT().SendEmail(
+			"CRITICAL The database ERCOLE on pluto has enabled new features (Partitioning) on server (and 2 more NEW_OPTION alerts) on pluto",
+			gomock.Any(),
+			as.Config.AlertService.Emailer.To,
+		).Return(nil)
+
+		as.flushAlertGroup(key)
+	})
+
+	t.Run("Already flushed", func(t *testing.T) {
+		db.EXPECT().DeleteAlertGroup(key).Return(nil, nil)
+
+		as.flushAlertGroup(key)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().AddAlertToGroup(key, alert, flushAt).Return(false, aerrMock)
+		db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
+		emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), as.Config.AlertService.Emailer.To).Return(nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+}
+
+func TestRestoreAlertGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	as := AlertService{
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				Emailer: config.Emailer{
+					To: []string{"test@ercole.test"},
+				},
+			},
+		},
+	}
+
+	alert := model.Alert{
+		AlertCategory: model.AlertCategoryAgent,
+		AlertCode:     model.AlertCodeNoData,
+		AlertSeverity: model.AlertSeverityCritical,
+		Description:   "No data received from the host pippo in the last 2 day(s)",
+		OtherInfo:     map[string]interface{}{"hostname": "pippo"},
+	}
+	group := model.AlertGroup{
+		Key:     alertGroupKey(alert),
+		Alerts:  []model.Alert{alert},
+		FlushAt: utils.P("2019-11-05T16:00:00Z"),
+	}
+
+	sent := make(chan struct{})
+
+	db.EXPECT().FindAlertGroups().Return([]model.AlertGroup{group}, nil)
+	db.EXPECT().DeleteAlertGroup(group.Key).Return(&group, nil)
+	db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
+	emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), as.Config.AlertService.Emailer.To).
+		DoAndReturn(func(subject, text string, to []string) error {
+			close(sent)
+			return nil
+		})
+
+	as.restoreAlertGroups()
+
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The expired group hasn't been flushed")
+	}
+}
+
+func TestProcessAlertInsertion_SuppressUnacknowledgedRepeats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	as := AlertService{
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Queue:    hub.New(),
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				Emailer: config.Emailer{
+					To: []string{"test@ercole.test"},
+				},
+				Grouping: config.AlertGrouping{
+					SuppressUnacknowledgedRepeats: true,
+				},
+			},
+		},
+	}
+
+	alert := model.Alert{
+		AlertCategory: model.AlertCategoryAgent,
+		AlertCode:     model.AlertCodeNoData,
+		AlertSeverity: model.AlertSeverityCritical,
+		Description:   "No data received from the host pippo in the last 2 day(s)",
+		OtherInfo:     map[string]interface{}{"hostname": "pippo"},
+	}
+
+	t.Run("Suppressed", func(t *testing.T) {
+		db.EXPECT().ExistUnacknowledgedAlert(alert).Return(true, nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+
+	t.Run("Not suppressed", func(t *testing.T) {
+		db.EXPECT().ExistUnacknowledgedAlert(alert).Return(false, nil)
+		db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
+		emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), as.Config.AlertService.Emailer.To).Return(nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+}
@@ -18,15 +18,16 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"sync"
 	"time"
 
 	"github.com/ercole-io/ercole/v2/alert-service/database"
 	"github.com/ercole-io/ercole/v2/alert-service/emailer"
+	"github.com/ercole-io/ercole/v2/alert-service/notifier"
 	"github.com/ercole-io/ercole/v2/logger"
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/metrics"
 
 	"github.com/ercole-io/ercole/v2/config"
 
@@ -66,6 +67,8 @@ type AlertService struct {
 	Log logger.Logger
 	// Emailer contains the emailer layer
 	Emailer emailer.Emailer
+	// Notifiers contains the other alert notification channels
+	Notifiers []notifier.Notifier
 }
 
 // Init initializes the service and database
@@ -73,9 +76,15 @@ func (as *AlertService) Init(ctx context.Context, wg *sync.WaitGroup) {
 	//Create a new queue
 	as.Queue = hub.New()
 
+	as.restoreAlertGroups()
+
 	//Subscribe the alert-service
 	sub := as.Queue.Subscribe(as.Config.AlertService.QueueBufferSize, model.TopicHostDataInsertion, model.TopicAlertInsertion)
 
+	metrics.RegisterGaugeFunc("alert_queue_depth", "Number of messages waiting in the alert-service queue", func() float64 {
+		return float64(len(sub.Receiver))
+	})
+
 	wg.Add(1)
 
 	go func(s hub.Subscription) {
@@ -126,21 +135,132 @@ func (as *AlertService) ProcessMsg(msg hub.Message) {
 func (as *AlertService) ProcessAlertInsertion(params hub.Fields) {
 	alert := params["alert"].(model.Alert)
 
-	//Create the subject and message
-	var subject, message string
+	if as.Config.AlertService.Grouping.SuppressUnacknowledgedRepeats {
+		exist, err := as.Database.ExistUnacknowledgedAlert(alert)
+		if err != nil {
+			as.Log.Error(err)
+		} else if exist {
+			as.Log.Debugf("Notification of alert %s suppressed, an identical alert is still unacknowledged", alert.ID.Hex())
+			return
+		}
+	}
 
-	if val, ok := alert.OtherInfo["hostname"]; ok {
-		subject = fmt.Sprintf("%s %s on %s", alert.AlertSeverity, alert.Description, val)
-		message = fmt.Sprintf("Date: %s\nSeverity: %s\nHost: %s\nCode: %s\n%s", alert.Date, alert.AlertSeverity, val, alert.AlertCode, alert.Description)
-	} else {
-		subject = fmt.Sprintf("%s %s", alert.AlertSeverity, alert.Description)
-		message = fmt.Sprintf("Date: %s\nSeverity: %s\nCode: %s\n%s", alert.Date, alert.AlertSeverity, alert.AlertCode, alert.Description)
+	if as.Config.AlertService.Grouping.Enabled && as.Config.AlertService.Grouping.Window > 0 {
+		as.groupAlert(alert)
+		return
 	}
 
+	as.sendAlert(alert)
+}
+
+// sendAlert sends the alert to its email recipients and notifiers
+func (as *AlertService) sendAlert(alert model.Alert) {
+	recipients, notifiers := as.routeAlert(alert)
+
+	subject, message := notifier.FormatAlert(alert)
+
 	// Send the email
-	err := as.Emailer.SendEmail(subject, message, as.Config.AlertService.Emailer.To)
+	if len(recipients) > 0 {
+		err := as.Emailer.SendEmail(subject, message, recipients)
+		if err != nil {
+			as.Log.Error(err)
+		}
+	}
+
+	as.notify(alert, notifiers)
+}
+
+// routeAlert return the email recipients and the notifiers of the alert, collected from the matching routing rules.
+// When no rule matches, the alert is sent to the default recipients and to all the notifiers
+func (as *AlertService) routeAlert(alert model.Alert) ([]string, []notifier.Notifier) {
+	rules, err := as.Database.GetEnabledAlertRoutingRules()
 	if err != nil {
 		as.Log.Error(err)
-		return
+		return as.Config.AlertService.Emailer.To, as.Notifiers
 	}
+
+	hostname, _ := alert.OtherInfo["hostname"].(string)
+	hostInfoLoaded := false
+
+	var location, environment string
+
+	recipients := make([]string, 0)
+	notifierNames := make([]string, 0)
+	matched := false
+
+	for _, rule := range rules {
+		if rule.NeedsHostInfo() && !hostInfoLoaded && hostname != "" {
+			location, environment, err = as.Database.FindCurrentHostLocationAndEnvironment(hostname)
+			if err != nil {
+				as.Log.Warnf("Can't find location and environment of host %s: %s", hostname, err)
+			}
+
+			hostInfoLoaded = true
+		}
+
+		if !rule.Matches(alert, location, environment) {
+			continue
+		}
+
+		matched = true
+
+		for _, r := range rule.EmailRecipients {
+			if !utils.Contains(recipients, r) {
+				recipients = append(recipients, r)
+			}
+		}
+
+		for _, n := range rule.Notifiers {
+			if !utils.Contains(notifierNames, n) {
+				notifierNames = append(notifierNames, n)
+			}
+		}
+	}
+
+	if !matched {
+		return as.Config.AlertService.Emailer.To, as.Notifiers
+	}
+
+	notifiers := make([]notifier.Notifier, 0, len(notifierNames))
+
+	for _, name := range notifierNames {
+		n := as.getNotifier(name)
+		if n == nil {
+			as.Log.Warnf("Alert routing rules refer to the unknown notifier %q", name)
+			continue
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	return recipients, notifiers
+}
+
+func (as *AlertService) getNotifier(name string) notifier.Notifier {
+	for _, n := range as.Notifiers {
+		if n.Name() == name {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// notify sends the alert to the notifiers concurrently, so a slow channel doesn't delay the others
+func (as *AlertService) notify(alert model.Alert, notifiers []notifier.Notifier) {
+	var wg sync.WaitGroup
+
+	for _, n := range notifiers {
+		wg.Add(1)
+
+		go func(n notifier.Notifier) {
+			defer wg.Done()
+
+			if err := n.Notify(alert); err != nil {
+				as.Log.Errorf("Notifier %s: %s", n.Name(), err)
+			}
+		}(n)
+	}
+
+	wg.Wait()
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/leandro-lugaresi/hub"
 
+	"github.com/ercole-io/ercole/v2/alert-service/notifier"
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
 	"github.com/ercole-io/ercole/v2/model"
@@ -33,12 +34,14 @@ func TestProcessMsg_AlertInsertion(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
 
 	as := AlertService{
-		Emailer: emailer,
-		TimeNow: utils.Btc(utils.P("2019-11-05T16:02:03Z")),
-		Log:     logger.NewLogger("TEST"),
-		Queue:   hub.New(),
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Queue:    hub.New(),
 		Config: config.Configuration{
 			AlertService: config.AlertService{
 				Emailer: config.Emailer{
@@ -48,6 +51,7 @@ func TestProcessMsg_AlertInsertion(t *testing.T) {
 		},
 	}
 
+	db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
 	emailer.EXPECT().SendEmail(
 		"CRITICAL This is just an alert test to a mocked emailer. on TestHostname",
 		`Date: 2019-09-02 10:25:28 +0000 UTC
@@ -95,12 +99,14 @@ func TestProcessAlertInsertion_WithHostname(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
 
 	as := AlertService{
-		Emailer: emailer,
-		TimeNow: utils.Btc(utils.P("2019-11-05T16:02:03Z")),
-		Log:     logger.NewLogger("TEST"),
-		Queue:   hub.New(),
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Queue:    hub.New(),
 		Config: config.Configuration{
 			AlertService: config.AlertService{
 				Emailer: config.Emailer{
@@ -110,6 +116,7 @@ func TestProcessAlertInsertion_WithHostname(t *testing.T) {
 		},
 	}
 
+	db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
 	emailer.EXPECT().SendEmail(
 		"CRITICAL This is just an alert test to a mocked emailer. on TestHostname",
 		`Date: 2019-09-02 10:25:28 +0000 UTC
@@ -138,12 +145,14 @@ func TestProcessAlertInsertion_WithoutHostname(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
 
 	as := AlertService{
-		Emailer: emailer,
-		TimeNow: utils.Btc(utils.P("2019-11-05T16:02:03Z")),
-		Log:     logger.NewLogger("TEST"),
-		Queue:   hub.New(),
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Queue:    hub.New(),
 		Config: config.Configuration{
 			AlertService: config.AlertService{
 				Emailer: config.Emailer{
@@ -153,6 +162,7 @@ func TestProcessAlertInsertion_WithoutHostname(t *testing.T) {
 		},
 	}
 
+	db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
 	emailer.EXPECT().SendEmail(
 		"CRITICAL This is just an alert test to a mocked emailer.",
 		`Date: 2019-09-02 10:25:28 +0000 UTC
@@ -180,12 +190,14 @@ func TestProcessAlertInsertion_EmailerError(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
 
 	as := AlertService{
-		Emailer: emailer,
-		TimeNow: utils.Btc(utils.P("2019-11-05T16:02:03Z")),
-		Log:     logger.NewLogger("TEST"),
-		Queue:   hub.New(),
+		Database: db,
+		Emailer:  emailer,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+		Queue:    hub.New(),
 		Config: config.Configuration{
 			AlertService: config.AlertService{
 				Emailer: config.Emailer{
@@ -195,6 +207,7 @@ func TestProcessAlertInsertion_EmailerError(t *testing.T) {
 		},
 	}
 
+	db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
 	emailer.EXPECT().SendEmail(
 		"CRITICAL This is just an alert test to a mocked emailer.",
 		`Date: 2019-09-02 10:25:28 +0000 UTC
@@ -217,3 +230,156 @@ This is just an alert test to a mocked emailer.`,
 
 	as.ProcessAlertInsertion(params)
 }
+
+func TestProcessAlertInsertion_Notifiers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	webhook := NewMockNotifier(mockCtrl)
+	syslog := NewMockNotifier(mockCtrl)
+
+	as := AlertService{
+		Database:  db,
+		Emailer:   emailer,
+		Notifiers: []notifier.Notifier{webhook, syslog},
+		TimeNow:   utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:       logger.NewLogger("TEST"),
+		Queue:     hub.New(),
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				Emailer: config.Emailer{
+					To: []string{"test@ercole.test"},
+				},
+			},
+		},
+	}
+
+	alert := model.Alert{
+		AlertAffectedTechnology: nil,
+		AlertCategory:           model.AlertCategoryAgent,
+		OtherInfo:               map[string]interface{}{"hostname": "TestHostname"},
+		AlertSeverity:           model.AlertSeverityCritical,
+		Description:             "No data received from the host TestHostname in the last 1 day(s)",
+		Date:                    utils.P("2019-09-02T10:25:28Z"),
+		AlertCode:               model.AlertCodeNoData,
+	}
+
+	db.EXPECT().GetEnabledAlertRoutingRules().Return([]model.AlertRoutingRule{}, nil)
+	emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), as.Config.AlertService.Emailer.To).
+		Return(utils.NewError(fmt.Errorf("test error from emailer"), "test EMAILER"))
+	webhook.EXPECT().Notify(alert).Return(nil)
+	syslog.EXPECT().Notify(alert).Return(aerrMock)
+	syslog.EXPECT().Name().Return("syslog")
+
+	params := make(hub.Fields, 1)
+	params["alert"] = alert
+
+	as.ProcessAlertInsertion(params)
+}
+
+func TestProcessAlertInsertion_RoutingRules(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	emailer := NewMockEmailer(mockCtrl)
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	licensing := NewMockNotifier(mockCtrl)
+	dbaTeam := NewMockNotifier(mockCtrl)
+
+	licensing.EXPECT().Name().Return("licensing").AnyTimes()
+	dbaTeam.EXPECT().Name().Return("dba-italy").AnyTimes()
+
+	as := AlertService{
+		Database:  db,
+		Emailer:   emailer,
+		Notifiers: []notifier.Notifier{licensing, dbaTeam},
+		TimeNow:   utils.Btc(utils.P("2019-11-05T16:02:03Z")),
+		Log:       logger.NewLogger("TEST"),
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				Emailer: config.Emailer{
+					To: []string{"test@ercole.test"},
+				},
+			},
+		},
+	}
+
+	rules := []model.AlertRoutingRule{
+		{
+			Name:            "licensing",
+			Enabled:         true,
+			Categories:      []string{model.AlertCategoryLicense},
+			Notifiers:       []string{"licensing"},
+			EmailRecipients: []string{"licensing@ercole.test"},
+		},
+		{
+			Name:       "dba italy",
+			Enabled:    true,
+			Categories: []string{model.AlertCategoryAgent, model.AlertCategoryEngine},
+			Locations:  []string{"Italy"},
+			Notifiers:  []string{"dba-italy", "missing"},
+		},
+	}
+
+	t.Run("License alert", func(t *testing.T) {
+		alert := model.Alert{
+			AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
+			AlertCategory:           model.AlertCategoryLicense,
+			AlertCode:               model.AlertCodeNewLicense,
+			AlertSeverity:           model.AlertSeverityCritical,
+			OtherInfo:               map[string]interface{}{"hostname": "TestHostname"},
+		}
+
+		db.EXPECT().GetEnabledAlertRoutingRules().Return(rules, nil)
+		db.EXPECT().FindCurrentHostLocationAndEnvironment("TestHostname").Return("Germany", "PRD", nil)
+		emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), []string{"licensing@ercole.test"})
+		licensing.EXPECT().Notify(alert).Return(nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+
+	t.Run("Agent alert in Italy", func(t *testing.T) {
+		alert := model.Alert{
+			AlertCategory: model.AlertCategoryAgent,
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			OtherInfo:     map[string]interface{}{"hostname": "TestHostname"},
+		}
+
+		db.EXPECT().GetEnabledAlertRoutingRules().Return(rules, nil)
+		db.EXPECT().FindCurrentHostLocationAndEnvironment("TestHostname").Return("Italy", "PRD", nil)
+		dbaTeam.EXPECT().Notify(alert).Return(nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+
+	t.Run("No matching rule", func(t *testing.T) {
+		alert := model.Alert{
+			AlertCategory: model.AlertCategoryAgent,
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			OtherInfo:     map[string]interface{}{"hostname": "TestHostname"},
+		}
+
+		db.EXPECT().GetEnabledAlertRoutingRules().Return(rules, nil)
+		db.EXPECT().FindCurrentHostLocationAndEnvironment("TestHostname").Return("", "", utils.ErrHostNotFound)
+		emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), []string{"test@ercole.test"})
+		licensing.EXPECT().Notify(alert).Return(nil)
+		dbaTeam.EXPECT().Notify(alert).Return(nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+
+	t.Run("Error loading rules", func(t *testing.T) {
+		alert := model.Alert{AlertCode: model.AlertCodeNewServer}
+
+		db.EXPECT().GetEnabledAlertRoutingRules().Return(nil, aerrMock)
+		emailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), []string{"test@ercole.test"})
+		licensing.EXPECT().Notify(alert).Return(nil)
+		dbaTeam.EXPECT().Notify(alert).Return(nil)
+
+		as.ProcessAlertInsertion(hub.Fields{"alert": alert})
+	})
+}
@@ -19,12 +19,15 @@ import (
 	"crypto/rsa"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
 	"github.com/ercole-io/ercole/v2/utils"
+	cr "github.com/ercole-io/ercole/v2/utils/crypto"
 	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/context"
 
 	"github.com/ercole-io/ercole/v2/api-service/dto"
 	apiservice_service "github.com/ercole-io/ercole/v2/api-service/service"
@@ -33,6 +36,7 @@ import (
 const (
 	BasicType = "basic"
 	LdapType  = "ldap"
+	OidcType  = "oidc"
 )
 
 // AuthenticationProvider is a interface that wrap methods used to authenticate users
@@ -57,9 +61,9 @@ type ErcoleClaims struct {
 // BuildAuthenticationProvider return a authentication provider that match what is requested in the configuration
 // It's initialized
 func BuildAuthenticationProvider(conf config.AuthenticationProviderConfig, service apiservice_service.APIService, timeNow func() time.Time, log logger.Logger) []AuthenticationProvider {
-	provs := make([]AuthenticationProvider, 0, 2)
+	provs := make([]AuthenticationProvider, 0, 3)
 
-	if len(conf.Types) == 0 || (!utils.Contains(conf.Types, BasicType) && !utils.Contains(conf.Types, LdapType)) {
+	if len(conf.Types) == 0 || (!utils.Contains(conf.Types, BasicType) && !utils.Contains(conf.Types, LdapType) && !utils.Contains(conf.Types, OidcType)) {
 		panic("The AuthenticationProvider type wasn't recognized or supported")
 	}
 
@@ -83,6 +87,16 @@ func BuildAuthenticationProvider(conf config.AuthenticationProviderConfig, servi
 		provs = append(provs, prov)
 	}
 
+	if utils.Contains(conf.Types, OidcType) {
+		prov := new(OIDCAuthenticationProvider)
+		prov.Config = conf
+		prov.Log = log
+		prov.TimeNow = timeNow
+		prov.Service = service
+
+		provs = append(provs, prov)
+	}
+
 	return provs
 }
 
@@ -121,6 +135,22 @@ func parsePrivateKey(raw []byte) (*rsa.PrivateKey, *rsa.PublicKey, error) {
 	return privateKey, &privateKey.PublicKey, nil
 }
 
+func isAPIToken(tokenString string) bool {
+	return strings.HasPrefix(tokenString, "Bearer "+cr.APITokenPrefix)
+}
+
+// authenticateAPIToken sets in the request the user impersonated by the API token
+func authenticateAPIToken(service *apiservice_service.APIService, r *http.Request, tokenString string) error {
+	user, err := service.AuthenticateAPIToken(tokenString[len("Bearer "):])
+	if err != nil {
+		return err
+	}
+
+	context.Set(r, "user", *user)
+
+	return nil
+}
+
 func validateBearerToken(tokenString string, timeNow func() time.Time, publicKey *rsa.PublicKey) (*ErcoleClaims, error) {
 	tokenString = tokenString[len("Bearer "):]
 	jwt.TimeFunc = timeNow
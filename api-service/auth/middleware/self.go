@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+)
+
+// SelfOrAdmin rejects the requests about a username other than the one of the logged user, unless the user is an admin
+func SelfOrAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := context.Get(r, "user").(model.User)
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if !user.IsAdmin() && user.Username != mux.Vars(r)["username"] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
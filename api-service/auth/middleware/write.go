@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/gorilla/context"
+)
+
+// Write rejects the requests without an authorization that grants the write permission
+// on the locations of the hosts they refer to
+func Write(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authorization, ok := context.Get(r, "authorization").(model.UserAuthorization)
+		if !ok || !authorization.CanWrite() {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		hostLocations, _ := context.Get(r, "hostLocations").([]string)
+		for _, location := range hostLocations {
+			if !authorization.CanWriteLocation(location) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,614 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/context"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/api-service/service"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// oidcLoginTimeout is the time allowed to the users to complete the login on the identity provider
+const oidcLoginTimeout = 10 * time.Minute
+
+// OIDCAuthenticationProvider is the concrete implementation of AuthenticationProvider that authenticates the users
+// with an OpenID Connect identity provider, using the authorization code flow with PKCE
+type OIDCAuthenticationProvider struct {
+	// Config contains the dataservice global configuration
+	Config config.AuthenticationProviderConfig
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Log contains logger formatted
+	Log logger.Logger
+	// privateKey contains the private key used to sign the JWT tokens
+	privateKey *rsa.PrivateKey
+	// publicKey contains the public key used to check the JWT tokens
+	publicKey *rsa.PublicKey
+	// Client contains the http client used to call the identity provider
+	Client *http.Client
+	// Service contains the underlying service used to perform various logical and store operations
+	Service service.APIService
+
+	mutex sync.Mutex
+	// discovery contains the endpoints of the identity provider
+	discovery *oidcDiscovery
+	// keys contains the keys of the identity provider by key id
+	keys map[string]interface{}
+	// pendingLogins contains the logins started and not yet completed, by state
+	pendingLogins map[string]oidcPendingLogin
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcPendingLogin struct {
+	codeVerifier string
+	nonce        string
+	expiresAt    time.Time
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Init initializes the provider and discovers the endpoints of the identity provider
+func (ap *OIDCAuthenticationProvider) Init() {
+	raw, err := os.ReadFile(ap.Config.PrivateKey)
+	if err != nil {
+		ap.Log.Panic(err)
+	}
+
+	ap.privateKey, ap.publicKey, err = parsePrivateKey(raw)
+	if err != nil {
+		ap.Log.Panic(utils.NewErrorf("Unable to parse the private key: %s", err))
+	}
+
+	if ap.Client == nil {
+		ap.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	ap.pendingLogins = make(map[string]oidcPendingLogin)
+
+	if _, err := ap.getDiscovery(); err != nil {
+		ap.Log.Errorf("Unable to discover the OpenID Connect provider, it will be retried at the first login: %s", err)
+	}
+}
+
+// GetUserInfoIfCredentialsAreCorrect isn't supported, the users authenticate on the identity provider
+func (ap *OIDCAuthenticationProvider) GetUserInfoIfCredentialsAreCorrect(username string, password string) (*dto.User, error) {
+	return nil, utils.NewError(errors.New("The OpenID Connect provider doesn't support the login with username and password"), http.StatusText(http.StatusUnauthorized))
+}
+
+// Login redirects the user to the identity provider to start the authorization code flow
+func (ap *OIDCAuthenticationProvider) Login(w http.ResponseWriter, r *http.Request) {
+	discovery, err := ap.getDiscovery()
+	if err != nil {
+		utils.WriteAndLogError(ap.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	login := oidcPendingLogin{expiresAt: ap.TimeNow().Add(oidcLoginTimeout)}
+
+	var state string
+
+	for _, s := range []*string{&state, &login.codeVerifier, &login.nonce} {
+		if *s, err = randomURLSafeString(); err != nil {
+			utils.WriteAndLogError(ap.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	ap.addPendingLogin(state, login)
+
+	u, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		utils.WriteAndLogError(ap.Log, w, http.StatusInternalServerError, utils.NewError(err, "Invalid authorization endpoint"))
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(login.codeVerifier))
+
+	params := u.Query()
+	params.Set("response_type", "code")
+	params.Set("client_id", ap.Config.OIDCClientID)
+	params.Set("redirect_uri", ap.Config.OIDCRedirectURL)
+	params.Set("scope", strings.Join(ap.scopes(), " "))
+	params.Set("state", state)
+	params.Set("nonce", login.nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+	u.RawQuery = params.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// GetToken completes the authorization code flow started by Login and return the token of the user
+func (ap *OIDCAuthenticationProvider) GetToken(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	if params.Get("error") != "" {
+		utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized,
+			utils.NewErrorf("Failed to login: %s %s", params.Get("error"), params.Get("error_description")))
+		return
+	}
+
+	login, ok := ap.popPendingLogin(params.Get("state"))
+	if !ok {
+		utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewError(errors.New("Invalid or expired login state"), http.StatusText(http.StatusUnauthorized)))
+		return
+	}
+
+	claims, err := ap.exchangeCode(params.Get("code"), login.codeVerifier)
+	if err != nil {
+		utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if nonce, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(login.nonce)) == 0 {
+		utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewError(errors.New("Invalid nonce"), http.StatusText(http.StatusUnauthorized)))
+		return
+	}
+
+	userInfo := ap.getUserInfo(claims)
+
+	token, err := buildToken(ap.TimeNow(), ap.Config.TokenValidityTimeout, userInfo, ap.privateKey)
+	if err != nil {
+		ap.Log.Errorf("Unable to get signed token: %s", err)
+		utils.WriteAndLogError(ap.Log, w, http.StatusInternalServerError, fmt.Errorf("Unable to get signed token"))
+
+		return
+	}
+
+	if _, err := w.Write([]byte(token)); err != nil {
+		utils.WriteAndLogError(ap.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+}
+
+// AuthenticateMiddleware return the middleware used to check if the users are authenticated.
+// Besides the ercole tokens, it accepts the ID tokens issued by the identity provider
+func (ap *OIDCAuthenticationProvider) AuthenticateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := r.Header.Get("Authorization")
+		if tokenString == "" {
+			utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewError(errors.New("You don't have setted the authorization header"), http.StatusText(http.StatusUnauthorized)))
+			return
+		}
+
+		if strings.HasPrefix(tokenString, "Basic ") {
+			tokenString = tokenString[len("Basic "):]
+			val, err := base64.StdEncoding.DecodeString(tokenString)
+			if err != nil {
+				utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewError(err, http.StatusText(http.StatusUnauthorized)))
+				return
+			}
+
+			if !bytes.ContainsAny(val, ":") {
+				utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewError(errors.New("A : is missing in the auth header"), http.StatusText(http.StatusUnauthorized)))
+				return
+			}
+
+			user := val[:bytes.IndexRune(val, ':')]
+			password := val[bytes.IndexRune(val, ':')+1:]
+
+			if subtle.ConstantTimeCompare(user, []byte(ap.Config.Username)) == 0 || subtle.ConstantTimeCompare(password, []byte(ap.Config.Password)) == 0 {
+				utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewError(errors.New("Invalid credentials"), http.StatusText(http.StatusUnauthorized)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if isAPIToken(tokenString) {
+			if err := authenticateAPIToken(&ap.Service, r, tokenString); err != nil {
+				ap.Log.Debugf("Invalid API token: %s", err)
+				utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.ErrInvalidToken)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if strings.HasPrefix(tokenString, "Bearer ") {
+			if claims, err := validateBearerToken(tokenString, ap.TimeNow, ap.publicKey); err == nil && claims != nil {
+				ercoleGroups := ap.Service.GetMatchedGroupsName(claims.Groups)
+
+				context.Set(r, "user", model.User{Username: claims.Subject, Groups: ercoleGroups})
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			idTokenClaims, err := ap.validateIDToken(tokenString[len("Bearer "):])
+			if err != nil {
+				ap.Log.Debugf("Invalid token: %s", err)
+				utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.ErrInvalidToken)
+				return
+			}
+
+			userInfo := ap.getUserInfo(idTokenClaims)
+
+			context.Set(r, "user", model.User{Username: userInfo.Username, Groups: userInfo.Groups})
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.NewErrorf("The authorization header value doesn't begin with Basic or Bearer"))
+	})
+}
+
+func (ap *OIDCAuthenticationProvider) GetType() string {
+	return OidcType
+}
+
+func (ap *OIDCAuthenticationProvider) scopes() []string {
+	if utils.Contains(ap.Config.OIDCScopes, "openid") {
+		return ap.Config.OIDCScopes
+	}
+
+	return append([]string{"openid"}, ap.Config.OIDCScopes...)
+}
+
+func (ap *OIDCAuthenticationProvider) addPendingLogin(state string, login oidcPendingLogin) {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	now := ap.TimeNow()
+
+	for s, l := range ap.pendingLogins {
+		if now.After(l.expiresAt) {
+			delete(ap.pendingLogins, s)
+		}
+	}
+
+	ap.pendingLogins[state] = login
+}
+
+func (ap *OIDCAuthenticationProvider) popPendingLogin(state string) (oidcPendingLogin, bool) {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	login, ok := ap.pendingLogins[state]
+	if !ok || state == "" {
+		return oidcPendingLogin{}, false
+	}
+
+	delete(ap.pendingLogins, state)
+
+	if ap.TimeNow().After(login.expiresAt) {
+		return oidcPendingLogin{}, false
+	}
+
+	return login, true
+}
+
+// getDiscovery return the discovery document of the identity provider, retrieving it the first time
+func (ap *OIDCAuthenticationProvider) getDiscovery() (*oidcDiscovery, error) {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	if ap.discovery != nil {
+		return ap.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(ap.Config.OIDCIssuer, "/")
+
+	var discovery oidcDiscovery
+	if err := ap.getJSON(issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, utils.NewErrorf("The issuer %q of the discovery document doesn't match %q", discovery.Issuer, ap.Config.OIDCIssuer)
+	}
+
+	ap.discovery = &discovery
+
+	return ap.discovery, nil
+}
+
+// getKey return the key of the identity provider with the kid, refreshing the keys if it's unknown
+func (ap *OIDCAuthenticationProvider) getKey(jwksURI, kid string) (interface{}, error) {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	if key, ok := ap.findKey(kid); ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := ap.getJSON(jwksURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	ap.keys = make(map[string]interface{}, len(jwks.Keys))
+
+	for _, k := range jwks.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			ap.Log.Warnf("Ignoring the key %q of the identity provider: %s", k.Kid, err)
+			continue
+		}
+
+		ap.keys[k.Kid] = key
+	}
+
+	if key, ok := ap.findKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, utils.NewErrorf("Unknown key %q", kid)
+}
+
+func (ap *OIDCAuthenticationProvider) findKey(kid string) (interface{}, bool) {
+	if kid == "" && len(ap.keys) == 1 {
+		for _, key := range ap.keys {
+			return key, true
+		}
+	}
+
+	key, ok := ap.keys[kid]
+
+	return key, ok
+}
+
+func (ap *OIDCAuthenticationProvider) getJSON(url string, out interface{}) error {
+	resp, err := ap.Client.Get(url)
+	if err != nil {
+		return utils.NewError(err, "OIDC")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return utils.NewErrorf("OIDC: unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return utils.NewError(err, "OIDC")
+	}
+
+	return nil
+}
+
+// exchangeCode exchanges the authorization code for the tokens and return the claims of the validated ID token
+func (ap *OIDCAuthenticationProvider) exchangeCode(code, codeVerifier string) (jwt.MapClaims, error) {
+	discovery, err := ap.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", ap.Config.OIDCRedirectURL)
+	form.Set("client_id", ap.Config.OIDCClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, utils.NewError(err, "OIDC")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if ap.Config.OIDCClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(ap.Config.OIDCClientID), url.QueryEscape(ap.Config.OIDCClientSecret))
+	}
+
+	resp, err := ap.Client.Do(req)
+	if err != nil {
+		return nil, utils.NewError(err, "OIDC")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, utils.NewErrorf("OIDC: unexpected status %d from the token endpoint", resp.StatusCode)
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, utils.NewError(err, "OIDC")
+	}
+
+	if tokens.IDToken == "" {
+		return nil, utils.NewErrorf("OIDC: the token endpoint didn't return an ID token")
+	}
+
+	return ap.validateIDToken(tokens.IDToken)
+}
+
+// validateIDToken checks the signature, the issuer, the audience and the validity of the ID token
+func (ap *OIDCAuthenticationProvider) validateIDToken(idToken string) (jwt.MapClaims, error) {
+	discovery, err := ap.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	jwt.TimeFunc = ap.TimeNow
+	token, err := jwt.Parse(idToken, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return ap.getKey(discovery.JWKSURI, kid)
+	}, jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, utils.ErrInvalidToken
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, utils.NewErrorf("%w: missing expiration", utils.ErrInvalidToken)
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, utils.NewErrorf("%w: invalid issuer", utils.ErrInvalidToken)
+	}
+
+	if !claims.VerifyAudience(ap.Config.OIDCClientID, true) {
+		return nil, utils.NewErrorf("%w: invalid audience", utils.ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// getUserInfo return the user of the claims, with the ercole groups matching the groups claim
+func (ap *OIDCAuthenticationProvider) getUserInfo(claims jwt.MapClaims) dto.User {
+	usernameClaim := ap.Config.OIDCUsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "preferred_username"
+	}
+
+	username, _ := getClaim(claims, usernameClaim).(string)
+	if username == "" {
+		username, _ = claims["sub"].(string)
+	}
+
+	groupsClaim := ap.Config.OIDCGroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	tags := make([]string, 0)
+
+	switch value := getClaim(claims, groupsClaim).(type) {
+	case string:
+		tags = append(tags, value)
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+	}
+
+	return dto.User{Username: username, Groups: ap.Service.GetMatchedGroupsName(tags)}
+}
+
+// getClaim return the value of the claim, following the dots of the nested claims
+func getClaim(claims map[string]interface{}, name string) interface{} {
+	head, tail, nested := strings.Cut(name, ".")
+
+	value, ok := claims[head]
+	if !ok || !nested {
+		return value
+	}
+
+	if inner, ok := value.(map[string]interface{}); ok {
+		return getClaim(inner, tail)
+	}
+
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func randomURLSafeString() (string, error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiservice_service "github.com/ercole-io/ercole/v2/api-service/service"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// mockIdP is a minimal OpenID Connect identity provider used by the tests
+type mockIdP struct {
+	*httptest.Server
+	key           *rsa.PrivateKey
+	now           time.Time
+	codeChallenge string
+	nonce         string
+}
+
+func newMockIdP(t *testing.T, now time.Time) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, now: now}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteJSONResponse(w, http.StatusOK, map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteJSONResponse(w, http.StatusOK, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		verifier := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+
+		if r.FormValue("code") != "the-code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != idp.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		utils.WriteJSONResponse(w, http.StatusOK, map[string]string{
+			"id_token": idp.idToken(t, jwt.MapClaims{"nonce": idp.nonce}),
+		})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+
+	return idp
+}
+
+func (idp *mockIdP) idToken(t *testing.T, extraClaims jwt.MapClaims) string {
+	claims := jwt.MapClaims{
+		"iss":                idp.URL,
+		"sub":                "0123",
+		"aud":                "ercole",
+		"iat":                idp.now.Unix(),
+		"exp":                idp.now.Add(time.Hour).Unix(),
+		"preferred_username": "pippo",
+		"realm_access":       map[string]interface{}{"roles": []string{"dba", "unknown"}},
+	}
+
+	for k, v := range extraClaims {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+
+	signed, err := token.SignedString(idp.key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func newTestOIDCProvider(t *testing.T, idp *mockIdP, db *MockMongoDatabaseInterface) *OIDCAuthenticationProvider {
+	f, err := os.CreateTemp("", "ercole-*")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(testRSAPrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	ap := &OIDCAuthenticationProvider{
+		Config: config.AuthenticationProviderConfig{
+			PrivateKey:           f.Name(),
+			TokenValidityTimeout: 60,
+			OIDCIssuer:           idp.URL,
+			OIDCClientID:         "ercole",
+			OIDCRedirectURL:      "https://ercole.example.com/oidc/callback",
+			OIDCGroupsClaim:      "realm_access.roles",
+		},
+		TimeNow: utils.Btc(idp.now),
+		Log:     logger.NewLogger("TEST"),
+		Service: apiservice_service.APIService{Database: db},
+	}
+	ap.Init()
+
+	return ap
+}
+
+func TestOIDCLogin(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	idp := newMockIdP(t, utils.P("2019-11-05T14:02:03Z"))
+	defer idp.Close()
+
+	ap := newTestOIDCProvider(t, idp, db)
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/oidc/login", nil)
+	require.NoError(t, err)
+
+	http.HandlerFunc(ap.Login).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusFound, rr.Code)
+
+	location, err := url.Parse(rr.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+	params := location.Query()
+	assert.Equal(t, "code", params.Get("response_type"))
+	assert.Equal(t, "ercole", params.Get("client_id"))
+	assert.Equal(t, "openid", params.Get("scope"))
+	assert.Equal(t, "S256", params.Get("code_challenge_method"))
+	assert.NotEmpty(t, params.Get("code_challenge"))
+	assert.NotEmpty(t, params.Get("nonce"))
+
+	idp.codeChallenge = params.Get("code_challenge")
+	idp.nonce = params.Get("nonce")
+
+	db.EXPECT().GetGroupByTag("dba").Return(&model.Group{Name: "DBA"}, nil)
+	db.EXPECT().GetGroupByTag("unknown").Return(nil, utils.ErrGroupNotFound)
+
+	t.Run("Callback", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/oidc/callback?code=the-code&state="+params.Get("state"), nil)
+		require.NoError(t, err)
+
+		http.HandlerFunc(ap.GetToken).ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		claims, err := validateBearerToken("Bearer "+rr.Body.String(), ap.TimeNow, ap.publicKey)
+		require.NoError(t, err)
+		assert.Equal(t, "pippo", claims.Subject)
+		assert.Equal(t, []string{"DBA"}, claims.Groups)
+	})
+
+	t.Run("State already used", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/oidc/callback?code=the-code&state="+params.Get("state"), nil)
+		require.NoError(t, err)
+
+		http.HandlerFunc(ap.GetToken).ServeHTTP(rr, req)
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestOIDCAuthenticateMiddleware_IDToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	idp := newMockIdP(t, utils.P("2019-11-05T14:02:03Z"))
+	defer idp.Close()
+
+	ap := newTestOIDCProvider(t, idp, db)
+
+	var user model.User
+
+	handler := ap.AuthenticateMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = context.Get(r, "user").(model.User)
+	}))
+
+	t.Run("Valid", func(t *testing.T) {
+		db.EXPECT().GetGroupByTag("dba").Return(&model.Group{Name: "DBA"}, nil)
+		db.EXPECT().GetGroupByTag("unknown").Return(nil, utils.ErrGroupNotFound)
+
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+idp.idToken(t, nil))
+
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, model.User{Username: "pippo", Groups: []string{"DBA"}}, user)
+	})
+
+	t.Run("Wrong audience", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+idp.idToken(t, jwt.MapClaims{"aud": "another-client"}))
+
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+idp.idToken(t, jwt.MapClaims{"exp": idp.now.Add(-time.Minute).Unix()}))
+
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestGetClaim(t *testing.T) {
+	var claims map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"groups": ["a"], "realm_access": {"roles": ["b"]}}`), &claims))
+
+	assert.Equal(t, []interface{}{"a"}, getClaim(claims, "groups"))
+	assert.Equal(t, []interface{}{"b"}, getClaim(claims, "realm_access.roles"))
+	assert.Nil(t, getClaim(claims, "groups.roles"))
+	assert.Nil(t, getClaim(claims, "missing"))
+}
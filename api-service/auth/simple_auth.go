@@ -159,6 +159,17 @@ func (ap *BasicAuthenticationProvider) AuthenticateMiddleware(next http.Handler)
 			return
 		}
 
+		if isAPIToken(tokenString) {
+			if err := authenticateAPIToken(&ap.Service, r, tokenString); err != nil {
+				ap.Log.Debugf("Invalid API token: %s", err)
+				utils.WriteAndLogError(ap.Log, w, http.StatusUnauthorized, utils.ErrInvalidToken)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		if strings.HasPrefix(tokenString, "Bearer ") {
 			claims, err := validateBearerToken(tokenString, ap.TimeNow, ap.publicKey)
 			if err != nil {
@@ -68,6 +68,7 @@ func (c *Client) doRequest(ctx context.Context, path, method string, body []byte
 	}
 
 	req = req.WithContext(ctx)
+	utils.SetAPIToken(req, c.config.AuthenticationProvider.APIToken)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -91,6 +92,7 @@ func (c *Client) doRequestWithParams(ctx context.Context, path, method string, b
 	}
 
 	req = req.WithContext(ctx)
+	utils.SetAPIToken(req, c.config.AuthenticationProvider.APIToken)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -0,0 +1,204 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestAddAlertRoutingRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	rule := model.AlertRoutingRule{
+		Name:       "licensing",
+		Enabled:    true,
+		Categories: []string{model.AlertCategoryLicense},
+		Notifiers:  []string{"licensing"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		added := rule
+		added.ID = utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+
+		as.EXPECT().AddAlertRoutingRule(rule).Return(&added, nil)
+
+		raw, err := json.Marshal(rule)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.AddAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(added), rr.Body.String())
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		as.EXPECT().AddAlertRoutingRule(rule).Return(nil, utils.NewError(utils.ErrInvalidAlertRoutingRule, "Invalid rule"))
+
+		raw, err := json.Marshal(rule)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.AddAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Read only", func(t *testing.T) {
+		ac.Config.APIService.ReadOnly = true
+		defer func() { ac.Config.APIService.ReadOnly = false }()
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader([]byte("{}")))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.AddAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
+
+func TestUpdateAlertRoutingRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	rule := model.AlertRoutingRule{
+		ID:              utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+		Name:            "dba",
+		EmailRecipients: []string{"dba@ercole.test"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		as.EXPECT().UpdateAlertRoutingRule(rule).Return(&rule, nil)
+
+		raw, err := json.Marshal(rule)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("PUT", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": "aaaaaaaaaaaaaaaaaaaaaaaa"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(rule), rr.Body.String())
+	})
+
+	t.Run("Different ID", func(t *testing.T) {
+		raw, err := json.Marshal(rule)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("PUT", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": "bbbbbbbbbbbbbbbbbbbbbbbb"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		as.EXPECT().UpdateAlertRoutingRule(rule).Return(nil, utils.ErrNotFound)
+
+		raw, err := json.Marshal(rule)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("PUT", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": "aaaaaaaaaaaaaaaaaaaaaaaa"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestDeleteAlertRoutingRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		as.EXPECT().DeleteAlertRoutingRule(utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")).Return(nil)
+
+		req, err := http.NewRequest("DELETE", "", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": "aaaaaaaaaaaaaaaaaaaaaaaa"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.DeleteAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": "pippo"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.DeleteAlertRoutingRule).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/golang/gddo/httputil"
 	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/ercole-io/ercole/v2/api-service/dto"
@@ -79,7 +80,7 @@ func (ctrl *APIController) SearchAlerts(w http.ResponseWriter, r *http.Request)
 	}
 
 	status = r.URL.Query().Get("status")
-	if status != "" && status != model.AlertStatusNew && status != model.AlertStatusAck && status != model.AlertStatusDismissed {
+	if status != "" && !model.IsValidAlertStatus(status) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, utils.NewError(errors.New("invalid status"), "Invalid  status"))
 		return
 	}
@@ -151,6 +152,39 @@ func (ctrl *APIController) searchAlertsJSON(w http.ResponseWriter, r *http.Reque
 	filters := filter.New()
 	filters.Page = pageNumber
 
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListAlerts(filter.Alert{
+			Mode:        mode,
+			Keywords:    strings.Split(search, " "),
+			Location:    location,
+			Environment: environment,
+			Severity:    severity,
+			Status:      status,
+			Category:    category,
+			Code:        code,
+			Description: description,
+			Hostname:    hostname,
+			From:        from,
+			To:          to,
+			OlderThan:   olderThan,
+			Filter:      filters,
+		}, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	if pageSize > 0 {
 		filters.Limit = pageSize
 
@@ -241,7 +275,7 @@ func (ctrl *APIController) AckAlerts(w http.ResponseWriter, r *http.Request) {
 		filter.IDs = body.Ids
 	}
 
-	err := ctrl.Service.AckAlerts(filter)
+	err := ctrl.auditedService(r).AckAlerts(filter, requestUsername(r))
 	if errors.Is(err, utils.ErrAlertNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 	} else if errors.Is(err, utils.ErrInvalidAck) {
@@ -253,3 +287,127 @@ func (ctrl *APIController) AckAlerts(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetAlert return the alert with its history
+func (ctrl *APIController) GetAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, fmt.Errorf("Can't decode id: %w", err))
+		return
+	}
+
+	alert, err := ctrl.Service.GetAlert(id)
+	if errors.Is(err, utils.ErrAlertNotFound) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, alert)
+}
+
+// UpdateAlertStatus change the status of the alert specified in the request
+func (ctrl *APIController) UpdateAlertStatus(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, fmt.Errorf("Can't decode id: %w", err))
+		return
+	}
+
+	body := struct {
+		Status       string     `json:"status"`
+		SnoozedUntil *time.Time `json:"snoozedUntil"`
+		Comment      string     `json:"comment"`
+	}{}
+
+	if err := utils.Decode(r.Body, &body); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	alert, err := ctrl.auditedService(r).UpdateAlertStatus(id, body.Status, body.SnoozedUntil, body.Comment, requestUsername(r))
+	ctrl.writeAlertLifecycleResponse(w, alert, err)
+}
+
+// AssignAlert change the assignee of the alert specified in the request
+func (ctrl *APIController) AssignAlert(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, fmt.Errorf("Can't decode id: %w", err))
+		return
+	}
+
+	body := struct {
+		Assignee string `json:"assignee"`
+	}{}
+
+	if err := utils.Decode(r.Body, &body); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	alert, err := ctrl.auditedService(r).AssignAlert(id, body.Assignee, requestUsername(r))
+	ctrl.writeAlertLifecycleResponse(w, alert, err)
+}
+
+// CommentAlert add a comment to the history of the alert specified in the request
+func (ctrl *APIController) CommentAlert(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, fmt.Errorf("Can't decode id: %w", err))
+		return
+	}
+
+	body := struct {
+		Comment string `json:"comment"`
+	}{}
+
+	if err := utils.Decode(r.Body, &body); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	alert, err := ctrl.auditedService(r).CommentAlert(id, body.Comment, requestUsername(r))
+	ctrl.writeAlertLifecycleResponse(w, alert, err)
+}
+
+func (ctrl *APIController) writeAlertLifecycleResponse(w http.ResponseWriter, alert *model.Alert, err error) {
+	switch {
+	case errors.Is(err, utils.ErrAlertNotFound):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+	case errors.Is(err, utils.ErrInvalidAlertStatusChange),
+		errors.Is(err, utils.ErrInvalidAlertComment),
+		errors.Is(err, utils.ErrInvalidAck):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+	case err != nil:
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+	default:
+		utils.WriteJSONResponse(w, http.StatusOK, alert)
+	}
+}
+
+// requestUsername return the username of the user that made the request
+func requestUsername(r *http.Request) string {
+	if user, ok := context.Get(r, "user").(model.User); ok {
+		return user.Username
+	}
+
+	return ""
+}
@@ -26,6 +26,8 @@ import (
 
 	"github.com/360EntSecGroup-Skylar/excelize"
 	gomock "github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -636,6 +638,7 @@ func TestAckAlerts_FailNotFound(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -651,7 +654,7 @@ func TestAckAlerts_FailNotFound(t *testing.T) {
 		IDs: []primitive.ObjectID{utils.Str2oid("5dc3f534db7e81a98b726a52")},
 	}
 
-	as.EXPECT().AckAlerts(a).
+	as.EXPECT().AckAlerts(a, "").
 		Return(utils.ErrAlertNotFound)
 
 	rr := httptest.NewRecorder()
@@ -673,6 +676,7 @@ func TestAckAlerts_FailInternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -688,7 +692,7 @@ func TestAckAlerts_FailInternalServerError(t *testing.T) {
 		IDs: []primitive.ObjectID{utils.Str2oid("5dc3f534db7e81a98b726a52")},
 	}
 
-	as.EXPECT().AckAlerts(a).
+	as.EXPECT().AckAlerts(a, "").
 		Return(aerrMock)
 
 	rr := httptest.NewRecorder()
@@ -712,6 +716,7 @@ func TestAckAlerts_Success(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
 		as := NewMockAPIServiceInterface(mockCtrl)
+		as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 		ac := APIController{
 			TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 			Service: as,
@@ -732,7 +737,7 @@ func TestAckAlerts_Success(t *testing.T) {
 				"host": "pippo",
 			},
 		}
-		as.EXPECT().AckAlerts(a).Return(nil)
+		as.EXPECT().AckAlerts(a, "").Return(nil)
 
 		rr := httptest.NewRecorder()
 		handler := http.HandlerFunc(ac.AckAlerts)
@@ -749,3 +754,188 @@ func TestAckAlerts_Success(t *testing.T) {
 		require.Equal(t, http.StatusNoContent, rr.Code)
 	})
 }
+
+func TestGetAlert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+	alert := model.Alert{
+		ID:          id,
+		AlertCode:   model.AlertCodeNewServer,
+		AlertStatus: model.AlertStatusNew,
+		Assignee:    "dba",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		as.EXPECT().GetAlert(id).Return(&alert, nil)
+
+		req, err := http.NewRequest("GET", "", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.GetAlert).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(alert), rr.Body.String())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		as.EXPECT().GetAlert(id).Return(nil, utils.NewError(utils.ErrAlertNotFound, "DB ERROR"))
+
+		req, err := http.NewRequest("GET", "", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.GetAlert).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestUpdateAlertStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+	snoozedUntil := utils.P("2019-11-06T14:02:03Z")
+	alert := model.Alert{
+		ID:           id,
+		AlertCode:    model.AlertCodeNewServer,
+		AlertStatus:  model.AlertStatusSnoozed,
+		SnoozedUntil: &snoozedUntil,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		as.EXPECT().UpdateAlertStatus(id, model.AlertStatusSnoozed, &snoozedUntil, "tomorrow", "pippo").Return(&alert, nil)
+
+		req, err := http.NewRequest("PUT", "", bytes.NewReader([]byte(`{"status":"SNOOZED","snoozedUntil":"2019-11-06T14:02:03Z","comment":"tomorrow"}`)))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+		context.Set(req, "user", model.User{Username: "pippo"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateAlertStatus).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(alert), rr.Body.String())
+	})
+
+	t.Run("Invalid status change", func(t *testing.T) {
+		as.EXPECT().UpdateAlertStatus(id, model.AlertStatusNew, nil, "", "").
+			Return(nil, utils.NewError(utils.ErrInvalidAlertStatusChange, "test"))
+
+		req, err := http.NewRequest("PUT", "", bytes.NewReader([]byte(`{"status":"NEW"}`)))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateAlertStatus).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Read only", func(t *testing.T) {
+		ac.Config.APIService.ReadOnly = true
+		defer func() { ac.Config.APIService.ReadOnly = false }()
+
+		req, err := http.NewRequest("PUT", "", bytes.NewReader([]byte(`{"status":"NEW"}`)))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateAlertStatus).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
+
+func TestAssignAlert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+	alert := model.Alert{ID: id, Assignee: "dba"}
+
+	as.EXPECT().AssignAlert(id, "dba", "pippo").Return(&alert, nil)
+
+	req, err := http.NewRequest("PUT", "", bytes.NewReader([]byte(`{"assignee":"dba"}`)))
+	require.NoError(t, err)
+	req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+	context.Set(req, "user", model.User{Username: "pippo"})
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ac.AssignAlert).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(alert), rr.Body.String())
+}
+
+func TestCommentAlert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+
+	t.Run("Empty comment", func(t *testing.T) {
+		as.EXPECT().CommentAlert(id, " ", "pippo").
+			Return(nil, utils.NewError(utils.ErrInvalidAlertComment, "test"))
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader([]byte(`{"comment":" "}`)))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+		context.Set(req, "user", model.User{Username: "pippo"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.CommentAlert).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		as.EXPECT().CommentAlert(id, "checked", "pippo").
+			Return(nil, utils.NewError(utils.ErrAlertNotFound, "DB ERROR"))
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader([]byte(`{"comment":"checked"}`)))
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+		context.Set(req, "user", model.User{Username: "pippo"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.CommentAlert).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang/gddo/httputil"
+	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/api-service/service"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// auditedService return the service that records in the audit log the changes requested by r
+func (ctrl *APIController) auditedService(r *http.Request) service.APIServiceInterface {
+	return ctrl.Service.WithAuditActor(requestAuditActor(r, ctrl.Config.APIService.TrustedProxies))
+}
+
+// requestAuditActor return who made the request and from where
+func requestAuditActor(r *http.Request, trustedProxies []string) model.AuditActor {
+	endpoint := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			endpoint = template
+		}
+	}
+
+	return model.AuditActor{
+		Username: requestUsername(r),
+		SourceIP: requestSourceIP(r, trustedProxies),
+		Method:   r.Method,
+		Endpoint: endpoint,
+	}
+}
+
+// requestSourceIP return the address of the client. The X-Forwarded-For header is honoured only when
+// the request comes from a trusted proxy, and the client is the last address not of a trusted proxy
+func requestSourceIP(r *http.Request, trustedProxies []string) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+
+	forwarded := r.Header.Get("X-Forwarded-For")
+	if forwarded == "" || !isTrustedProxy(remote, trustedProxies) {
+		return remote
+	}
+
+	addresses := strings.Split(forwarded, ",")
+	for i := len(addresses) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(addresses[i])
+		if address != "" && (i == 0 || !isTrustedProxy(address, trustedProxies)) {
+			return address
+		}
+	}
+
+	return remote
+}
+
+// isTrustedProxy return true if the address is one of the trusted proxies, given as addresses or CIDRs
+func isTrustedProxy(address string, trustedProxies []string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range trustedProxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SearchAuditLog search the audit log entries using the filters in the request
+func (ctrl *APIController) SearchAuditLog(w http.ResponseWriter, r *http.Request) {
+	choice := httputil.NegotiateContentType(r, []string{"application/json", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}, "application/json")
+
+	filter, err := dto.GetAuditLogFilter(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	switch choice {
+	case "application/json":
+		ctrl.SearchAuditLogJSON(w, r, *filter)
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		ctrl.SearchAuditLogXLSX(w, r, *filter)
+	}
+}
+
+// SearchAuditLogJSON search the audit log entries using the filters in the request returning them in JSON
+func (ctrl *APIController) SearchAuditLogJSON(w http.ResponseWriter, r *http.Request, filter dto.AuditLogFilter) {
+	entries, err := ctrl.Service.SearchAuditLog(filter)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, entries)
+}
+
+// SearchAuditLogXLSX search the audit log entries using the filters in the request returning them in XLSX
+func (ctrl *APIController) SearchAuditLogXLSX(w http.ResponseWriter, r *http.Request, filter dto.AuditLogFilter) {
+	file, err := ctrl.Service.SearchAuditLogAsXLSX(filter)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteXLSXResponse(w, file)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestSearchAuditLog(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		filter := dto.AuditLogFilter{
+			Username:   "mario",
+			EntityType: model.AuditEntityGroup,
+			From:       utils.P("2023-05-01T00:00:00Z"),
+			To:         utils.MAX_TIME,
+			PageNumber: -1,
+			PageSize:   -1,
+		}
+		entries := []model.AuditLogEntry{
+			{
+				ID:         utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+				Date:       utils.P("2023-05-02T00:00:00Z"),
+				AuditActor: model.AuditActor{Username: "mario"},
+				EntityType: model.AuditEntityGroup,
+				EntityID:   "dba",
+				Action:     model.AuditActionDelete,
+				Changes:    []model.AuditChange{{Field: "name", Before: `"dba"`}},
+			},
+		}
+
+		as.EXPECT().SearchAuditLog(filter).Return(entries, nil)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.SearchAuditLog)
+		req, err := http.NewRequest("GET", "/audit-log?username=mario&entity-type=GROUP&from=2023-05-01T00:00:00Z", nil)
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(entries), rr.Body.String())
+	})
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.SearchAuditLog)
+		req, err := http.NewRequest("GET", "/audit-log?from=yesterday", nil)
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+}
+
+func TestRequestAuditActor(t *testing.T) {
+	var actual model.AuditActor
+
+	trustedProxies := []string{"10.0.0.1", "172.16.0.0/12"}
+
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			context.Set(r, "user", model.User{Username: "mario"})
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.HandleFunc("/groups/{name}", func(w http.ResponseWriter, r *http.Request) {
+		actual = requestAuditActor(r, trustedProxies)
+	}).Methods("DELETE")
+
+	req, err := http.NewRequest("DELETE", "/groups/dba", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "10.0.0.1:54321"
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, model.AuditActor{
+		Username: "mario",
+		SourceIP: "10.0.0.1",
+		Method:   "DELETE",
+		Endpoint: "/groups/{name}",
+	}, actual)
+
+	req.Header.Set("X-Forwarded-For", "192.168.1.1, 172.16.0.5")
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "192.168.1.1", actual.SourceIP)
+
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 192.168.1.1, 172.16.0.5")
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "192.168.1.1", actual.SourceIP)
+
+	req.RemoteAddr = "192.168.1.2:54321"
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "192.168.1.2", actual.SourceIP)
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// unscopedRoutes contains the routes that don't expose the data of the hosts,
+// so they are available also to the users without any granted location
+var unscopedRoutes = []string{
+	"/version",
+	userGroup + "/info",
+	userGroup + "/{username}/change-password",
+}
+
+// resourceRoutes are the routes of the resources whose access is restricted to the locations of their hosts
+var resourceRoutes = map[string]string{
+	"/alerts/{id}":                       model.AlertResource,
+	"/contracts/oracle/database/{id}":    model.OracleDatabaseContractResource,
+	"/contracts/mysql/database/{id}":     model.MySQLContractResource,
+	"/contracts/microsoft/database/{id}": model.SqlServerDatabaseContractResource,
+}
+
+// serviceAuthorization is the authorization of the requests authenticated with the service credentials
+var serviceAuthorization = model.UserAuthorization{
+	AllLocations: true,
+	Locations:    []string{},
+	Permissions:  map[string]string{model.AllLocation: model.WritePermission},
+}
+
+// authorize is the middleware that restricts the requests of the users to the locations granted by the roles of their groups.
+// The location parameter is scoped to the granted locations and the requests about the hosts of other locations,
+// or about the alerts and the contracts of the hosts of other locations, are rejected.
+// The locations of the hosts the request refers to are kept in the context, to check the permission on them.
+// The requests authenticated with the service credentials aren't restricted
+func (ctrl *APIController) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := context.Get(r, "user").(model.User)
+		if !ok {
+			context.Set(r, "authorization", serviceAuthorization)
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		authorization, err := ctrl.Service.GetUserAuthorization(user)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		context.Set(r, "authorization", *authorization)
+
+		if isUnscopedRoute(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if authorization.AllLocations && (r.Method == http.MethodGet || authorization.CanWriteAllLocations()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !authorization.AllLocations {
+			params := r.URL.Query()
+
+			locations := authorization.ScopeLocations(params.Get("location"))
+			if len(locations) == 0 {
+				utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+					utils.NewError(errors.New("The user isn't allowed to access the requested locations"), "FORBIDDEN_REQUEST"))
+				return
+			}
+
+			params.Set("location", strings.Join(locations, ","))
+			r.URL.RawQuery = params.Encode()
+		}
+
+		hostnames := make([]string, 0)
+		if hostname, ok := mux.Vars(r)["hostname"]; ok {
+			hostnames = append(hostnames, hostname)
+		}
+
+		if resource := routeResource(r); resource != "" {
+			if id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"]); err == nil {
+				resourceHosts, err := ctrl.Service.GetResourceHosts(resource, id)
+				if err != nil {
+					utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+					return
+				}
+
+				hostnames = append(hostnames, resourceHosts...)
+			}
+		}
+
+		hostLocations := make([]string, 0, len(hostnames))
+
+		for _, hostname := range hostnames {
+			location, err := ctrl.Service.GetHostLocation(hostname)
+			if errors.Is(err, utils.ErrHostNotFound) {
+				continue
+			} else if err != nil {
+				utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+				return
+			}
+
+			if !authorization.CanAccessLocation(location) {
+				utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+					utils.NewError(errors.New("The user isn't allowed to access the host"), "FORBIDDEN_REQUEST"))
+				return
+			}
+
+			hostLocations = append(hostLocations, location)
+		}
+
+		context.Set(r, "hostLocations", hostLocations)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeResource return the resource identified by the id parameter of the route, if its access depends on its hosts
+func routeResource(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	for prefix, resource := range resourceRoutes {
+		if strings.Contains(template, prefix) {
+			return resource
+		}
+	}
+
+	return ""
+}
+
+func isUnscopedRoute(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+
+	if strings.Contains(template, "/settings/") {
+		return true
+	}
+
+	for _, unscoped := range unscopedRoutes {
+		if strings.HasSuffix(template, unscoped) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canAccessLocation return true if the authorization of the request grants the location
+func canAccessLocation(r *http.Request, location string) bool {
+	authorization, ok := context.Get(r, "authorization").(model.UserAuthorization)
+
+	return ok && authorization.CanAccessLocation(location)
+}
+
+// authorizeHostsWrite checks that the user can modify the data of the hosts that the request carries in its body,
+// instead of in the route. It writes the error response and return false if the user can't
+func (ctrl *APIController) authorizeHostsWrite(w http.ResponseWriter, r *http.Request, hostnames []string) bool {
+	authorization, ok := context.Get(r, "authorization").(model.UserAuthorization)
+	if !ok {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+			utils.NewError(errors.New("The request has no authorization"), "FORBIDDEN_REQUEST"))
+		return false
+	}
+
+	if authorization.CanWriteAllLocations() {
+		return true
+	}
+
+	for _, hostname := range hostnames {
+		location, err := ctrl.Service.GetHostLocation(hostname)
+		if errors.Is(err, utils.ErrHostNotFound) {
+			continue
+		} else if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return false
+		}
+
+		if !authorization.CanWriteLocation(location) {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+				utils.NewError(fmt.Errorf("The user isn't allowed to modify the host %s", hostname), "FORBIDDEN_REQUEST"))
+			return false
+		}
+	}
+
+	return true
+}
+
+// authorizeResourceHostsWrite checks that the user can modify the data of the hosts that the stored resource
+// with the id refers to, and of the hosts of the request. It writes the error response and return false if the user can't
+func (ctrl *APIController) authorizeResourceHostsWrite(w http.ResponseWriter, r *http.Request,
+	resource string, id primitive.ObjectID, hostnames []string) bool {
+	if authorization, ok := context.Get(r, "authorization").(model.UserAuthorization); ok && authorization.CanWriteAllLocations() {
+		return true
+	}
+
+	resourceHosts, err := ctrl.Service.GetResourceHosts(resource, id)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return false
+	}
+
+	return ctrl.authorizeHostsWrite(w, r, append(resourceHosts, hostnames...))
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/auth/middleware"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestAuthorize(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	var location string
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		location = r.URL.Query().Get("location")
+	}
+
+	var requestUser *model.User
+
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requestUser != nil {
+				context.Set(r, "user", *requestUser)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Use(ac.authorize)
+	router.HandleFunc("/version", handler).Methods("GET")
+	router.HandleFunc("/hosts", handler).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}", handler).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}", middleware.Write(handler)).Methods("DELETE")
+	router.HandleFunc("/users/{username}/change-password", middleware.SelfOrAdmin(handler)).Methods("POST")
+	router.HandleFunc("/alerts/{id}/status", middleware.Write(handler)).Methods("PUT")
+	router.HandleFunc("/contracts/oracle/database/{id}", middleware.Write(handler)).Methods("DELETE")
+
+	user := model.User{Username: "pippo", Groups: []string{"italy"}}
+	italyReader := model.UserAuthorization{Locations: []string{"Italy"}, Permissions: map[string]string{"Italy": model.ReadPermission}}
+
+	serve := func(method, url string, user *model.User) *httptest.ResponseRecorder {
+		location = ""
+		requestUser = user
+
+		req, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	t.Run("Service credentials", func(t *testing.T) {
+		rr := serve("GET", "/hosts?location=France", nil)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "France", location)
+	})
+
+	t.Run("Scoped to the granted locations", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+
+		rr := serve("GET", "/hosts", &user)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "Italy", location)
+	})
+
+	t.Run("Location not granted", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+
+		rr := serve("GET", "/hosts?location=France", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Host of another location", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+		as.EXPECT().GetHostLocation("paris").Return("France", nil)
+
+		rr := serve("GET", "/hosts/paris", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Host of a granted location", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+
+		rr := serve("GET", "/hosts/rome", &user)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Read only user can't dismiss host", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+
+		rr := serve("DELETE", "/hosts/rome", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Service credentials can write", func(t *testing.T) {
+		rr := serve("DELETE", "/hosts/rome", nil)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Write without authorization", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "/hosts/rome", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		middleware.Write(handler)(rr, req)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Change the password of the same user", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+
+		rr := serve("POST", "/users/pippo/change-password", &user)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Change the password of another user", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReader, nil)
+
+		rr := serve("POST", "/users/pluto/change-password", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Admin changes the password of another user", func(t *testing.T) {
+		admin := model.User{Username: "admin", Groups: []string{model.GroupAdmin}}
+		as.EXPECT().GetUserAuthorization(admin).Return(&model.UserAuthorization{AllLocations: true, Permissions: map[string]string{model.AllLocation: model.AdminPermission}}, nil)
+
+		rr := serve("POST", "/users/pluto/change-password", &admin)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Change password with the service credentials", func(t *testing.T) {
+		rr := serve("POST", "/users/pluto/change-password", nil)
+
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	italyWriter := model.UserAuthorization{Locations: []string{"Italy"}, Permissions: map[string]string{"Italy": model.WritePermission}}
+	id := utils.Str2oid("5dc3f534db7e81a98b726a52")
+
+	t.Run("Alert of a host of another location", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyWriter, nil)
+		as.EXPECT().GetResourceHosts(model.AlertResource, id).Return([]string{"paris"}, nil)
+		as.EXPECT().GetHostLocation("paris").Return("France", nil)
+
+		rr := serve("PUT", "/alerts/5dc3f534db7e81a98b726a52/status", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Alert of a host of a granted location", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyWriter, nil)
+		as.EXPECT().GetResourceHosts(model.AlertResource, id).Return([]string{"rome"}, nil)
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+
+		rr := serve("PUT", "/alerts/5dc3f534db7e81a98b726a52/status", &user)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Contract with a host of another location", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyWriter, nil)
+		as.EXPECT().GetResourceHosts(model.OracleDatabaseContractResource, id).Return([]string{"rome", "paris"}, nil)
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+		as.EXPECT().GetHostLocation("paris").Return("France", nil)
+
+		rr := serve("DELETE", "/contracts/oracle/database/5dc3f534db7e81a98b726a52", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	italyReaderGermanyWriter := model.UserAuthorization{
+		Locations:   []string{"Italy", "Germany"},
+		Permissions: map[string]string{"Italy": model.ReadPermission, "Germany": model.WritePermission},
+	}
+
+	t.Run("Writer of another location can't dismiss host", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReaderGermanyWriter, nil)
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+
+		rr := serve("DELETE", "/hosts/rome", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Writer of the location dismisses host", func(t *testing.T) {
+		as.EXPECT().GetUserAuthorization(user).Return(&italyReaderGermanyWriter, nil)
+		as.EXPECT().GetHostLocation("berlin").Return("Germany", nil)
+
+		rr := serve("DELETE", "/hosts/berlin", &user)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Reader of all the locations can't write the hosts of the others", func(t *testing.T) {
+		allReaderGermanyWriter := model.UserAuthorization{
+			AllLocations: true,
+			Locations:    []string{"Germany"},
+			Permissions:  map[string]string{model.AllLocation: model.ReadPermission, "Germany": model.WritePermission},
+		}
+
+		as.EXPECT().GetUserAuthorization(user).Return(&allReaderGermanyWriter, nil)
+		as.EXPECT().GetHostLocation("paris").Return("France", nil)
+
+		rr := serve("DELETE", "/hosts/paris", &user)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("User without locations", func(t *testing.T) {
+		limited := model.UserAuthorization{Locations: []string{}}
+
+		as.EXPECT().GetUserAuthorization(user).Return(&limited, nil).Times(2)
+
+		rr := serve("GET", "/hosts", &user)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = serve("GET", "/version", &user)
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestAuthorizeHostsWrite(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	italyWriter := model.UserAuthorization{Locations: []string{"Italy"}, Permissions: map[string]string{"Italy": model.WritePermission}}
+	id := utils.Str2oid("5dc3f534db7e81a98b726a52")
+
+	request := func(authorization *model.UserAuthorization) *http.Request {
+		req, err := http.NewRequest("PUT", "/", nil)
+		require.NoError(t, err)
+
+		if authorization != nil {
+			context.Set(req, "authorization", *authorization)
+		}
+
+		return req
+	}
+
+	t.Run("Hosts of a granted location", func(t *testing.T) {
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+		as.EXPECT().GetHostLocation("new").Return("", utils.ErrHostNotFound)
+
+		rr := httptest.NewRecorder()
+		assert.True(t, ac.authorizeHostsWrite(rr, request(&italyWriter), []string{"rome", "new"}))
+	})
+
+	t.Run("Host of another location", func(t *testing.T) {
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil)
+		as.EXPECT().GetHostLocation("paris").Return("France", nil)
+
+		rr := httptest.NewRecorder()
+		assert.False(t, ac.authorizeHostsWrite(rr, request(&italyWriter), []string{"rome", "paris"}))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Stored host of another location", func(t *testing.T) {
+		as.EXPECT().GetResourceHosts(model.OracleDatabaseContractResource, id).Return([]string{"paris"}, nil)
+		as.EXPECT().GetHostLocation("paris").Return("France", nil)
+		as.EXPECT().GetHostLocation("rome").Return("Italy", nil).AnyTimes()
+
+		rr := httptest.NewRecorder()
+		assert.False(t, ac.authorizeResourceHostsWrite(rr, request(&italyWriter), model.OracleDatabaseContractResource, id, []string{"rome"}))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("Service credentials", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		assert.True(t, ac.authorizeResourceHostsWrite(rr, request(&serviceAuthorization), model.OracleDatabaseContractResource, id, []string{"paris"}))
+	})
+
+	t.Run("Without authorization", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		assert.False(t, ac.authorizeHostsWrite(rr, request(nil), []string{"rome"}))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetBackupComplianceReport_JSONSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	report := &dto.BackupComplianceReport{
+		Databases: []model.BackupCompliance{
+			{
+				Hostname:     "foobar",
+				DatabaseName: "NOBCK",
+				Policy:       "production",
+				Violations:   []model.BackupViolation{{Code: model.BackupViolationNoBackups, Description: "No backups are scheduled"}},
+			},
+		},
+		Summary: dto.BackupComplianceSummary{
+			Total:      1,
+			Violations: map[string]int{model.BackupViolationNoBackups: 1},
+		},
+	}
+
+	compliant := false
+	var user interface{}
+
+	as.EXPECT().ListLocations(user).Return([]string{"Italy"}, nil)
+	as.EXPECT().GetBackupComplianceReport(dto.BackupComplianceFilter{
+		Location:  "Italy",
+		Hostname:  "foobar",
+		Violation: model.BackupViolationNoBackups,
+		Compliant: &compliant,
+	}).Return(report, nil)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ac.GetBackupComplianceReport)
+	req, err := http.NewRequest("GET", "/hosts/technologies/oracle/databases/backup-compliance?hostname=foobar&violation=NO_BACKUPS&compliant=false", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(report), rr.Body.String())
+}
+
+func TestGetBackupComplianceReport_FailUnprocessableEntity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	for _, query := range []string{"violation=foo", "compliant=bar"} {
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.GetBackupComplianceReport)
+		req, err := http.NewRequest("GET", "/hosts/technologies/oracle/databases/backup-compliance?"+query, nil)
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code, query)
+	}
+}
+
+func TestGetBackupComplianceReport_FailInternalServerError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().GetBackupComplianceReport(dto.BackupComplianceFilter{Location: "Italy"}).
+		Return(nil, aerrMock)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ac.GetBackupComplianceReport)
+	req, err := http.NewRequest("GET", "/hosts/technologies/oracle/databases/backup-compliance?location=Italy", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestListCapacityForecasts_JSONSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	daysToFull := 12.5
+	forecasts := []model.CapacityForecast{
+		{
+			CapacityItem: model.CapacityItem{Hostname: "foobar", Kind: model.CapacityForecastKindFilesystem, Name: "/"},
+			Used:         520,
+			Capacity:     1000,
+			GrowthPerDay: 10,
+			DaysToFull:   &daysToFull,
+		},
+	}
+
+	var user interface{}
+
+	as.EXPECT().ListLocations(user).Return([]string{"Italy", "Germany"}, nil)
+	as.EXPECT().ListCapacityForecasts(dto.CapacityForecastFilter{
+		Location:   "Italy,Germany",
+		Hostname:   "foobar",
+		Kind:       model.CapacityForecastKindFilesystem,
+		WithinDays: 30,
+	}).Return(forecasts, nil)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ac.ListCapacityForecasts)
+	req, err := http.NewRequest("GET", "/hosts/capacity-forecasts?hostname=foobar&kind=FILESYSTEM&within-days=30", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(forecasts), rr.Body.String())
+}
+
+func TestListCapacityForecasts_FailUnprocessableEntity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	for _, query := range []string{"kind=foo", "within-days=-2", "within-days=bar"} {
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.ListCapacityForecasts)
+		req, err := http.NewRequest("GET", "/hosts/capacity-forecasts?"+query, nil)
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code, query)
+	}
+}
+
+func TestListCapacityForecasts_FailInternalServerError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().ListCapacityForecasts(dto.CapacityForecastFilter{Location: "Italy", WithinDays: -1}).
+		Return(nil, aerrMock)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ac.ListCapacityForecasts)
+	req, err := http.NewRequest("GET", "/hosts/capacity-forecasts?location=Italy", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -22,7 +22,6 @@ import (
 	"time"
 
 	"github.com/ercole-io/ercole/v2/api-service/dto"
-	"github.com/ercole-io/ercole/v2/model"
 
 	"github.com/golang/gddo/httputil"
 	"github.com/gorilla/context"
@@ -103,6 +102,24 @@ func (ctrl *APIController) SearchClustersJSON(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListClusters(mode, search, location, environment, olderThan, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	clusters, err := ctrl.Service.SearchClusters(mode, search, sortBy, sortDesc, pageNumber, pageSize, location, environment, olderThan)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
@@ -181,33 +198,30 @@ func (ctrl *APIController) GetClusterJSON(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	user := context.Get(r, "user")
-	locations, errLocation := ctrl.Service.ListLocations(user)
-
-	if errLocation != nil {
-		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, errLocation)
+	if !canAccessLocation(r, data.Location) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, errors.New(utils.ErrPermissionDenied))
 		return
 	}
 
-	var isLocationOk bool
+	utils.WriteJSONResponse(w, http.StatusOK, data)
+}
 
-	for _, location := range locations {
-		if location == data.Location || location == model.AllLocation {
-			isLocationOk = true
-			break
-		}
+//GetClusterXLSX get cluster data using the filters in the request and returns it in XLSX format
+func (ctrl *APIController) GetClusterXLSX(w http.ResponseWriter, r *http.Request, clusterName string, olderThan time.Time) {
+	cluster, err := ctrl.Service.GetCluster(clusterName, olderThan)
+	if errors.Is(err, utils.ErrClusterNotFound) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
 	}
 
-	if isLocationOk {
-		utils.WriteJSONResponse(w, http.StatusOK, data)
-	} else {
+	if !canAccessLocation(r, cluster.Location) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, errors.New(utils.ErrPermissionDenied))
 		return
 	}
-}
 
-//GetClusterXLSX get cluster data using the filters in the request and returns it in XLSX format
-func (ctrl *APIController) GetClusterXLSX(w http.ResponseWriter, r *http.Request, clusterName string, olderThan time.Time) {
 	xlsx, err := ctrl.Service.GetClusterXLSX(clusterName, olderThan)
 	if errors.Is(err, utils.ErrClusterNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
@@ -23,6 +23,7 @@ import (
 
 	"github.com/360EntSecGroup-Skylar/excelize"
 	gomock "github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,6 +31,7 @@ import (
 	dto "github.com/ercole-io/ercole/v2/api-service/dto"
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
@@ -430,6 +432,8 @@ func TestGetCluster(t *testing.T) {
 		Log: logger.NewLogger("TEST"),
 	}
 
+	italyReader := model.UserAuthorization{Locations: []string{"Italy"}, Permissions: map[string]string{"Italy": model.ReadPermission}}
+
 	t.Run("json", func(t *testing.T) {
 		cluster := &dto.Cluster{
 			ID:                          [12]byte{},
@@ -451,13 +455,6 @@ func TestGetCluster(t *testing.T) {
 			VMsErcoleAgentCount:         0,
 		}
 
-		var user interface{}
-		locations := []string{"Italy"}
-
-		as.EXPECT().
-			ListLocations(user).
-			Return(locations, nil)
-
 		as.EXPECT().
 			GetCluster("Pippo", utils.P("2020-06-10T11:54:59Z")).
 			Return(cluster, nil)
@@ -470,6 +467,7 @@ func TestGetCluster(t *testing.T) {
 		req = mux.SetURLVars(req, map[string]string{
 			"name": "Pippo",
 		})
+		context.Set(req, "authorization", italyReader)
 		req.Header.Add("Accept", "application/json")
 
 		handler.ServeHTTP(rr, req)
@@ -481,6 +479,9 @@ func TestGetCluster(t *testing.T) {
 	t.Run("xlsx", func(t *testing.T) {
 		xlsx := &excelize.File{}
 
+		as.EXPECT().
+			GetCluster("Pippo", utils.P("2020-06-10T11:54:59Z")).
+			Return(&dto.Cluster{Name: "Pippo", Location: "Italy"}, nil)
 		as.EXPECT().
 			GetClusterXLSX("Pippo", utils.P("2020-06-10T11:54:59Z")).
 			Return(xlsx, nil)
@@ -493,6 +494,7 @@ func TestGetCluster(t *testing.T) {
 		req = mux.SetURLVars(req, map[string]string{
 			"name": "Pippo",
 		})
+		context.Set(req, "authorization", italyReader)
 		req.Header.Add("Accept", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
 
 		handler.ServeHTTP(rr, req)
@@ -501,4 +503,25 @@ func TestGetCluster(t *testing.T) {
 		_, err = excelize.OpenReader(rr.Body)
 		require.NoError(t, err)
 	})
+
+	t.Run("Cluster of another location", func(t *testing.T) {
+		as.EXPECT().
+			GetCluster("Pippo", utils.P("2020-06-10T11:54:59Z")).
+			Return(&dto.Cluster{Name: "Pippo", Location: "France"}, nil)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.GetCluster)
+		req, err := http.NewRequest("GET", "/hosts/cluster/Pippo?older-than=2020-06-10T11%3A54%3A59Z", nil)
+		require.NoError(t, err)
+
+		req = mux.SetURLVars(req, map[string]string{
+			"name": "Pippo",
+		})
+		context.Set(req, "authorization", italyReader)
+		req.Header.Add("Accept", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
 }
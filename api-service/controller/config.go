@@ -40,7 +40,7 @@ func (ctrl *APIController) UpdateConfig(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := ctrl.Service.ChangeConfig(changes); err != nil {
+	if err := ctrl.auditedService(r).ChangeConfig(changes); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
 	}
@@ -60,6 +60,7 @@ func TestUpdateConfig_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -87,6 +87,10 @@ type APIControllerInterface interface {
 	GetOracleDatabasesStatistics(w http.ResponseWriter, r *http.Request)
 	// GetOracleDatabaseLicensesCompliance return licenses usage status and compliance
 	GetOracleDatabaseLicensesCompliance(w http.ResponseWriter, r *http.Request)
+	// SimulateOracleDatabaseLicenses return the difference of the licenses compliance after the hypothetical changes in the request
+	SimulateOracleDatabaseLicenses(w http.ResponseWriter, r *http.Request)
+	// PlanOracleDatabaseConsolidation return the placements of the Oracle databases on the target shapes in the request
+	PlanOracleDatabaseConsolidation(w http.ResponseWriter, r *http.Request)
 
 	// GetDefaultDatabaseTags return the default list of database tags from configuration
 	GetDefaultDatabaseTags(w http.ResponseWriter, r *http.Request)
@@ -50,7 +50,7 @@ func (ctrl *APIController) InsertGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	groupInserted, err := ctrl.Service.InsertGroup(group)
+	groupInserted, err := ctrl.auditedService(r).InsertGroup(group)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
@@ -83,7 +83,7 @@ func (ctrl *APIController) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	groupUpdated, err := ctrl.Service.UpdateGroup(group)
+	groupUpdated, err := ctrl.auditedService(r).UpdateGroup(group)
 	if errors.Is(err, utils.ErrGroupNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
@@ -145,7 +145,7 @@ func (ctrl *APIController) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	errDel := ctrl.Service.DeleteGroup(name)
+	errDel := ctrl.auditedService(r).DeleteGroup(name)
 	if errors.Is(errDel, utils.ErrGroupNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, errDel)
 		return
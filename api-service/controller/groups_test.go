@@ -37,6 +37,7 @@ func TestInsertGroup_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -75,6 +76,7 @@ func TestInsertGroup_UnprocessableEntity(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -117,6 +119,7 @@ func TestUpdateGroup_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -155,6 +158,7 @@ func TestUpdateGroup_NotFoundError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -201,6 +205,7 @@ func TestUpdateGroup_UnprocessableEntity(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -319,6 +324,7 @@ func TestDeleteGroup_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -349,6 +355,7 @@ func TestDeleteGroup_NotFoundError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -389,6 +396,7 @@ func TestDeleteGroup_UnprocessableEntity(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (ctrl *APIController) ListHostIdentities(w http.ResponseWriter, r *http.Request) {
+	identities, err := ctrl.Service.ListHostIdentities()
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, map[string]interface{}{"identities": identities})
+}
+
+func (ctrl *APIController) GetHostAliasSuggestions(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := ctrl.Service.GetHostAliasSuggestions()
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, map[string]interface{}{"suggestions": suggestions})
+}
+
+func (ctrl *APIController) AddHostAlias(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	var req dto.HostAliasRequest
+	if err := utils.Decode(r.Body, &req); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	identity, err := ctrl.auditedService(r).AddHostAlias(mux.Vars(r)["hostname"], req.Alias)
+	ctrl.writeHostIdentityResponse(w, identity, err)
+}
+
+func (ctrl *APIController) RemoveHostAlias(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	identity, err := ctrl.auditedService(r).RemoveHostAlias(mux.Vars(r)["hostname"], mux.Vars(r)["alias"])
+	ctrl.writeHostIdentityResponse(w, identity, err)
+}
+
+func (ctrl *APIController) MergeHost(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	var req dto.HostMergeRequest
+	if err := utils.Decode(r.Body, &req); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	identity, err := ctrl.auditedService(r).MergeHost(req.Source, req.Target)
+	ctrl.writeHostIdentityResponse(w, identity, err)
+}
+
+func (ctrl *APIController) writeHostIdentityResponse(w http.ResponseWriter, identity *model.HostIdentity, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidHostIdentity):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+	case errors.Is(err, utils.ErrHostNotFound), errors.Is(err, utils.ErrHostIdentityNotFound):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+	case errors.Is(err, utils.ErrHostAliasInUse):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusConflict, err)
+	case err != nil:
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+	default:
+		utils.WriteJSONResponse(w, http.StatusOK, identity)
+	}
+}
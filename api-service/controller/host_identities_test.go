@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestMergeHost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+
+	raw, err := json.Marshal(dto.HostMergeRequest{Source: "oldname", Target: "newname"})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		identity := model.HostIdentity{Hostname: "newname", Aliases: []string{"oldname"}}
+		as.EXPECT().MergeHost("oldname", "newname").Return(&identity, nil)
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.MergeHost).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(identity), rr.Body.String())
+	})
+
+	t.Run("Source not found", func(t *testing.T) {
+		as.EXPECT().MergeHost("oldname", "newname").Return(nil, utils.ErrHostNotFound)
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.MergeHost).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Target in use", func(t *testing.T) {
+		as.EXPECT().MergeHost("oldname", "newname").
+			Return(nil, utils.NewErrorf("%w: newname is an alias of barfoo", utils.ErrHostAliasInUse))
+
+		req, err := http.NewRequest("POST", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.MergeHost).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
+
+func TestRemoveHostAlias(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+
+	identity := model.HostIdentity{Hostname: "foobar", Aliases: []string{}}
+	as.EXPECT().RemoveHostAlias("foobar", "oldname").Return(&identity, nil)
+
+	req, err := http.NewRequest("DELETE", "", nil)
+	require.NoError(t, err)
+	req = mux.SetURLVars(req, map[string]string{"hostname": "foobar", "alias": "oldname"})
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ac.RemoveHostAlias).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(identity), rr.Body.String())
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (ctrl *APIController) ListHostMetadata(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ctrl.Service.ListHostMetadata(dto.GetHostMetadataFilter(r))
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, map[string]interface{}{"metadata": metadata})
+}
+
+func (ctrl *APIController) GetHostMetadata(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ctrl.Service.GetHostMetadata(mux.Vars(r)["hostname"])
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, metadata)
+}
+
+func (ctrl *APIController) UpdateHostMetadata(w http.ResponseWriter, r *http.Request) {
+	if ctrl.Config.APIService.ReadOnly {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden, utils.NewError(errors.New("The API is disabled because the service is put in read-only mode"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	var req dto.HostMetadataRequest
+	if err := utils.Decode(r.Body, &req); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	metadata, err := ctrl.auditedService(r).UpdateHostMetadata(mux.Vars(r)["hostname"], req)
+
+	switch {
+	case errors.Is(err, utils.ErrInvalidHostMetadata):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+	case errors.Is(err, utils.ErrHostNotFound):
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+	case err != nil:
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+	default:
+		utils.WriteJSONResponse(w, http.StatusOK, metadata)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestListHostMetadata(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	metadata := []model.HostMetadata{{Hostname: "foobar", OwnerTeam: "dba", Labels: []string{"pci"}}}
+	as.EXPECT().ListHostMetadata(dto.HostMetadataFilter{OwnerTeam: "dba", Labels: []string{"pci", "eu"}}).
+		Return(metadata, nil)
+
+	req, err := http.NewRequest("GET", "/hosts/metadata?owner-team=dba&labels=pci,eu", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ac.ListHostMetadata).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(map[string]interface{}{"metadata": metadata}), rr.Body.String())
+}
+
+func TestUpdateHostMetadata(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+
+	request := dto.HostMetadataRequest{OwnerTeam: "dba", Criticality: model.HostCriticalityHigh}
+	raw, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	newRequest := func(t *testing.T) *http.Request {
+		req, err := http.NewRequest("PUT", "", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		return mux.SetURLVars(req, map[string]string{"hostname": "foobar"})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		metadata := model.HostMetadata{Hostname: "foobar", OwnerTeam: "dba", Criticality: model.HostCriticalityHigh, Labels: []string{}}
+		as.EXPECT().UpdateHostMetadata("foobar", request).Return(&metadata, nil)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateHostMetadata).ServeHTTP(rr, newRequest(t))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(metadata), rr.Body.String())
+	})
+
+	t.Run("Invalid metadata", func(t *testing.T) {
+		as.EXPECT().UpdateHostMetadata("foobar", request).Return(nil, utils.ErrInvalidHostMetadata)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateHostMetadata).ServeHTTP(rr, newRequest(t))
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Host not found", func(t *testing.T) {
+		as.EXPECT().UpdateHostMetadata("foobar", request).Return(nil, utils.ErrHostNotFound)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateHostMetadata).ServeHTTP(rr, newRequest(t))
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Read only", func(t *testing.T) {
+		ac.Config.APIService.ReadOnly = true
+		defer func() { ac.Config.APIService.ReadOnly = false }()
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.UpdateHostMetadata).ServeHTTP(rr, newRequest(t))
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
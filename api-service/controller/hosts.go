@@ -83,6 +83,24 @@ func (ctrl *APIController) searchHostsJSON(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListHosts(mode, *filters, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	if mode == "summary" {
 		ctrl.getHostDataSummaries(w, filters)
 		return
@@ -298,7 +316,7 @@ func (ctrl *APIController) DismissHost(w http.ResponseWriter, r *http.Request) {
 
 	hostname := mux.Vars(r)["hostname"]
 
-	err := ctrl.Service.DismissHost(hostname)
+	err := ctrl.auditedService(r).DismissHost(hostname, requestUsername(r))
 	if errors.Is(err, utils.ErrHostNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 	} else if err != nil {
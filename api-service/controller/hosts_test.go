@@ -279,6 +279,100 @@ func TestSearchHosts_JSONHostnames(t *testing.T) {
 	assert.JSONEq(t, utils.ToJSON(expectedRes), rr.Body.String())
 }
 
+func TestSearchHosts_JSONListQuery(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	sort := []dto.SortField{{Field: "info.cpuCores", Desc: true}, {Field: "hostname"}}
+	cursor := dto.ListQuery{Sort: sort}.CursorAfter(bson.A{8, "test-aix", utils.Str2oid("5e96ade270c184faca93fe1b")})
+
+	expectedQuery := dto.ListQuery{
+		Cursor: cursor,
+		Limit:  2,
+		Sort:   sort,
+		Fields: []string{"hostname", "info.cpuCores"},
+	}
+
+	page := &dto.ListPage{
+		Items: []map[string]interface{}{
+			{"hostname": "test-db", "info": map[string]interface{}{"cpuCores": 4}},
+			{"hostname": "test-virt", "info": map[string]interface{}{"cpuCores": 2}},
+		},
+		NextCursor: expectedQuery.CursorAfter(bson.A{2, "test-virt", utils.Str2oid("5e96ade270c184faca93fe1c")}),
+	}
+
+	var user interface{}
+	var locations []string
+
+	as.EXPECT().
+		ListLocations(user).
+		Return(locations, nil)
+
+	as.EXPECT().
+		ListHosts("full", gomock.Any(), expectedQuery).
+		Return(page, nil)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ac.SearchHosts)
+	req, err := http.NewRequest("GET", "/hosts?limit=2&sort=-info.cpuCores,hostname&fields=hostname,info.cpuCores&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(page.Items), rr.Body.String())
+	assert.Empty(t, rr.Header().Get("X-Total-Count"))
+	assert.Equal(t, page.NextCursor, rr.Header().Get("X-Next-Cursor"))
+}
+
+func TestSearchHosts_JSONListQueryBadRequest(t *testing.T) {
+	otherSortCursor := dto.ListQuery{Sort: []dto.SortField{{Field: "hostname"}}}.CursorAfter(bson.A{"test-db", utils.Str2oid("5e96ade270c184faca93fe1b")})
+
+	for _, query := range []string{
+		"limit=0",
+		"limit=1001",
+		"sort=info.$where",
+		"fields=hostname,-info",
+		"cursor=not-a-cursor",
+		"sort=-hostname&cursor=" + otherSortCursor,
+	} {
+		t.Run(query, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			as := NewMockAPIServiceInterface(mockCtrl)
+			ac := APIController{
+				TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+				Service: as,
+				Config:  config.Configuration{},
+				Log:     logger.NewLogger("TEST"),
+			}
+
+			var user interface{}
+			var locations []string
+
+			as.EXPECT().
+				ListLocations(user).
+				Return(locations, nil)
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(ac.SearchHosts)
+			req, err := http.NewRequest("GET", "/hosts?"+query, nil)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
 func TestSearchHosts_JSONUnprocessableEntity1(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -1146,6 +1240,7 @@ func TestDismissHost_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -1153,7 +1248,7 @@ func TestDismissHost_Success(t *testing.T) {
 		Log:     logger.NewLogger("TEST"),
 	}
 
-	as.EXPECT().DismissHost("foobar").Return(nil)
+	as.EXPECT().DismissHost("foobar", "").Return(nil)
 
 	rr := httptest.NewRecorder()
 	handler := http.HandlerFunc(ac.DismissHost)
@@ -1200,6 +1295,7 @@ func TestDismissHost_FailNotFound(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -1207,7 +1303,7 @@ func TestDismissHost_FailNotFound(t *testing.T) {
 		Log:     logger.NewLogger("TEST"),
 	}
 
-	as.EXPECT().DismissHost("foobar").Return(utils.ErrHostNotFound)
+	as.EXPECT().DismissHost("foobar", "").Return(utils.ErrHostNotFound)
 
 	rr := httptest.NewRecorder()
 	handler := http.HandlerFunc(ac.DismissHost)
@@ -1226,6 +1322,7 @@ func TestDismissHost_FailInternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -1233,7 +1330,7 @@ func TestDismissHost_FailInternalServerError(t *testing.T) {
 		Log:     logger.NewLogger("TEST"),
 	}
 
-	as.EXPECT().DismissHost("foobar").Return(aerrMock)
+	as.EXPECT().DismissHost("foobar", "").Return(aerrMock)
 
 	rr := httptest.NewRecorder()
 	handler := http.HandlerFunc(ac.DismissHost)
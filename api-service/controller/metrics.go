@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/context"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/metrics"
+)
+
+// GetMetrics export the fleet, alert, licensing and service-internal metrics in the OpenMetrics format,
+// only to the admins and to the requests authenticated with the service credentials
+func (ctrl *APIController) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	if user, ok := context.Get(r, "user").(model.User); ok && !user.IsAdmin() {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+			utils.NewError(errors.New("The metrics are reserved to the admins"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
+	m, err := ctrl.Service.GetMetrics()
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	metrics.Handler(newMetricsRegistry(m)).ServeHTTP(w, r)
+}
+
+// newMetricsRegistry return a registry with the gauges of the metrics m
+func newMetricsRegistry(m *dto.Metrics) *prometheus.Registry {
+	hosts := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Name:      "hosts",
+		Help:      "Number of hosts by location, environment and technology",
+	}, []string{"location", "environment", "technology"})
+
+	for _, c := range m.HostsCount {
+		hosts.WithLabelValues(c.Location, c.Environment, c.Technology).Set(float64(c.Count))
+	}
+
+	hostDataAge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Name:      "host_data_age_seconds",
+		Help:      "Seconds elapsed since the last hostdata received from the host",
+	}, []string{"hostname", "location", "environment"})
+
+	for _, a := range m.HostDataAge {
+		hostDataAge.WithLabelValues(a.Hostname, a.Location, a.Environment).Set(a.Age)
+	}
+
+	openAlerts := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Name:      "open_alerts",
+		Help:      "Number of open alerts by code and severity",
+	}, []string{"code", "severity"})
+
+	for _, a := range m.OpenAlerts {
+		openAlerts.WithLabelValues(a.AlertCode, a.AlertSeverity).Set(float64(a.Count))
+	}
+
+	licenseLabels := []string{"license_type_id", "description", "metric"}
+
+	licenseConsumed := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Name:      "license_consumed",
+		Help:      "Number of licenses consumed by license type",
+	}, licenseLabels)
+
+	licenseCovered := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Name:      "license_covered",
+		Help:      "Number of consumed licenses covered by contracts by license type",
+	}, licenseLabels)
+
+	for _, l := range m.LicenseCompliance {
+		licenseConsumed.WithLabelValues(l.LicenseTypeID, l.ItemDescription, l.Metric).Set(l.Consumed)
+		licenseCovered.WithLabelValues(l.LicenseTypeID, l.ItemDescription, l.Metric).Set(l.Covered)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(hosts, hostDataAge, openAlerts, licenseConsumed, licenseCovered)
+
+	return registry
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetMetrics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		as.EXPECT().GetMetrics().Return(&dto.Metrics{
+			HostsCount: []dto.HostsCountMetric{
+				{Location: "Italy", Environment: "PROD", Technology: model.TechnologyOracleDatabase, Count: 2},
+			},
+			HostDataAge: []dto.HostDataAgeMetric{
+				{Hostname: "pippo", Location: "Italy", Environment: "PROD", Age: 3600},
+			},
+			OpenAlerts: []dto.AlertsCount{
+				{AlertCode: model.AlertCodeNoData, AlertSeverity: model.AlertSeverityCritical, Count: 3},
+			},
+			LicenseCompliance: []dto.LicenseCompliance{
+				{LicenseTypeID: "A90611", ItemDescription: "Oracle Database Enterprise Edition", Metric: "Processor Perpetual", Consumed: 4, Covered: 2},
+			},
+		}, nil)
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.GetMetrics).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		body := rr.Body.String()
+		assert.Contains(t, body, `ercole_hosts{environment="PROD",location="Italy",technology="Oracle/Database"} 2`)
+		assert.Contains(t, body, `ercole_host_data_age_seconds{environment="PROD",hostname="pippo",location="Italy"} 3600`)
+		assert.Contains(t, body, `ercole_open_alerts{code="NO_DATA",severity="CRITICAL"} 3`)
+		assert.Contains(t, body, `ercole_license_consumed{description="Oracle Database Enterprise Edition",license_type_id="A90611",metric="Processor Perpetual"} 4`)
+		assert.Contains(t, body, `ercole_license_covered{description="Oracle Database Enterprise Edition",license_type_id="A90611",metric="Processor Perpetual"} 2`)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		as.EXPECT().GetMetrics().Return(nil, aerrMock)
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.GetMetrics).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		as.EXPECT().GetMetrics().Return(&dto.Metrics{}, nil)
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		require.NoError(t, err)
+		context.Set(req, "user", model.User{Username: "admin", Groups: []string{model.GroupAdmin}})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.GetMetrics).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Not an admin", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		require.NoError(t, err)
+		context.Set(req, "user", model.User{Username: "mario", Groups: []string{"italy"}})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.GetMetrics).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
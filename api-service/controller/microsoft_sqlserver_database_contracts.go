@@ -51,7 +51,11 @@ func (ctrl *APIController) AddSqlServerDatabaseContract(w http.ResponseWriter, r
 		return
 	}
 
-	agr, err := ctrl.Service.AddSqlServerDatabaseContract(req)
+	if !ctrl.authorizeHostsWrite(w, r, req.Hosts) {
+		return
+	}
+
+	agr, err := ctrl.auditedService(r).AddSqlServerDatabaseContract(req)
 	if errors.Is(err, utils.ErrContractNotFound) ||
 		errors.Is(err, utils.ErrLicenseNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
@@ -78,7 +82,7 @@ func (ctrl *APIController) DeleteSqlServerDatabaseContract(w http.ResponseWriter
 		return
 	}
 
-	if err = ctrl.Service.DeleteSqlServerDatabaseContract(id); errors.Is(err, utils.ErrContractNotFound) {
+	if err = ctrl.auditedService(r).DeleteSqlServerDatabaseContract(id); errors.Is(err, utils.ErrContractNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
 	} else if err != nil {
@@ -106,7 +110,11 @@ func (ctrl *APIController) UpdateSqlServerDatabaseContract(w http.ResponseWriter
 		return
 	}
 
-	agr, err := ctrl.Service.UpdateSqlServerDatabaseContract(req)
+	if !ctrl.authorizeResourceHostsWrite(w, r, model.SqlServerDatabaseContractResource, req.ID, req.Hosts) {
+		return
+	}
+
+	agr, err := ctrl.auditedService(r).UpdateSqlServerDatabaseContract(req)
 	if errors.Is(err, utils.ErrContractNotFound) ||
 		errors.Is(err, utils.ErrLicenseNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
@@ -130,7 +138,7 @@ func (ctrl *APIController) GetSqlServerDatabaseContracts(w http.ResponseWriter,
 }
 
 func (ctrl *APIController) GetSqlServerDatabaseContractsJSON(w http.ResponseWriter, r *http.Request) {
-	contracts, err := ctrl.Service.GetSqlServerDatabaseContracts()
+	contracts, err := ctrl.Service.GetSqlServerDatabaseContracts(r.URL.Query().Get("location"))
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -144,7 +152,7 @@ func (ctrl *APIController) GetSqlServerDatabaseContractsJSON(w http.ResponseWrit
 }
 
 func (ctrl *APIController) GetSqlServerDatabaseContractsXLSX(w http.ResponseWriter, r *http.Request) {
-	xlsx, err := ctrl.Service.GetSqlServerDatabaseContractsAsXLSX()
+	xlsx, err := ctrl.Service.GetSqlServerDatabaseContractsAsXLSX(r.URL.Query().Get("location"))
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -154,7 +162,7 @@ func (ctrl *APIController) GetSqlServerDatabaseContractsXLSX(w http.ResponseWrit
 }
 
 func (ctrl *APIController) GetSqlServerDatabaseContractsAsXLSX(w http.ResponseWriter, r *http.Request) {
-	xlsx, err := ctrl.Service.GetSqlServerDatabaseContractsAsXLSX()
+	xlsx, err := ctrl.Service.GetSqlServerDatabaseContractsAsXLSX(r.URL.Query().Get("location"))
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
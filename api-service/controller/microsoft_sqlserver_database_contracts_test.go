@@ -27,6 +27,7 @@ import (
 	"github.com/ercole-io/ercole/v2/utils"
 
 	gomock "github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,6 +36,7 @@ func TestAddOSqlServerDatabaseContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -66,6 +68,7 @@ func TestAddOSqlServerDatabaseContract_Success(t *testing.T) {
 	handler := http.HandlerFunc(ac.AddSqlServerDatabaseContract)
 	req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -145,6 +148,7 @@ func TestAddSqlServerDatabaseContract_InternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -167,6 +171,7 @@ func TestAddSqlServerDatabaseContract_InternalServerError(t *testing.T) {
 	handler := http.HandlerFunc(ac.AddSqlServerDatabaseContract)
 	req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -177,6 +182,7 @@ func TestUpdateSqlServerDatabaseContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -200,6 +206,7 @@ func TestUpdateSqlServerDatabaseContract_Success(t *testing.T) {
 	handler := http.HandlerFunc(ac.UpdateSqlServerDatabaseContract)
 	req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -252,7 +259,7 @@ func TestGetSqlServerDatabaseContracts_Success(t *testing.T) {
 	}
 
 	as.EXPECT().
-		GetSqlServerDatabaseContracts().
+		GetSqlServerDatabaseContracts("").
 		Return(contracts, nil)
 
 	rr := httptest.NewRecorder()
@@ -56,7 +56,7 @@ func (ctrl *APIController) UpdateSqlServerLicenseIgnoredField(w http.ResponseWri
 	}
 
 	//set the value
-	err = ctrl.Service.UpdateSqlServerLicenseIgnoredField(hostname, dbname, ignored, req.IgnoredComment)
+	err = ctrl.auditedService(r).UpdateSqlServerLicenseIgnoredField(hostname, dbname, ignored, req.IgnoredComment)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
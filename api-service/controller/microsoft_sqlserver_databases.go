@@ -57,6 +57,24 @@ func (ctrl *APIController) SearchSqlServerInstances(w http.ResponseWriter, r *ht
 
 // SearchSqlServerInstancesJSON search instances data using the filters in the request returning it in JSON
 func (ctrl *APIController) SearchSqlServerInstancesJSON(w http.ResponseWriter, r *http.Request, filter dto.SearchSqlServerInstancesFilter) {
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListSqlServerInstances(filter, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	instances, err := ctrl.Service.SearchSqlServerInstances(filter)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
@@ -57,6 +57,24 @@ func (ctrl *APIController) SearchMongoDBInstances(w http.ResponseWriter, r *http
 
 //SearchMongoDBInstancesJSON search instances data using the filters in the request returning it in JSON
 func (ctrl *APIController) SearchMongoDBInstancesJSON(w http.ResponseWriter, r *http.Request, filter dto.SearchMongoDBInstancesFilter) {
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListMongoDBInstances(filter, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	instances, err := ctrl.Service.SearchMongoDBInstances(filter)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
@@ -47,7 +47,11 @@ func (ctrl *APIController) AddMySQLContract(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	contractAdded, err := ctrl.Service.AddMySQLContract(contract)
+	if !ctrl.authorizeHostsWrite(w, r, contract.Hosts) {
+		return
+	}
+
+	contractAdded, err := ctrl.auditedService(r).AddMySQLContract(contract)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -80,7 +84,11 @@ func (ctrl *APIController) UpdateMySQLContract(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	contractUpdated, err := ctrl.Service.UpdateMySQLContract(contract)
+	if !ctrl.authorizeHostsWrite(w, r, contract.Hosts) {
+		return
+	}
+
+	contractUpdated, err := ctrl.auditedService(r).UpdateMySQLContract(contract)
 	if errors.Is(err, utils.ErrNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
@@ -106,7 +114,7 @@ func (ctrl *APIController) GetMySQLContracts(w http.ResponseWriter, r *http.Requ
 }
 
 func (ctrl *APIController) GetMySQLContractsJSON(w http.ResponseWriter, r *http.Request) {
-	contracts, err := ctrl.Service.GetMySQLContracts()
+	contracts, err := ctrl.Service.GetMySQLContracts(r.URL.Query().Get("location"))
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -119,7 +127,7 @@ func (ctrl *APIController) GetMySQLContractsJSON(w http.ResponseWriter, r *http.
 }
 
 func (ctrl *APIController) GetMySQLContractsXLSX(w http.ResponseWriter, r *http.Request) {
-	xlsx, err := ctrl.Service.GetMySQLContractsAsXLSX()
+	xlsx, err := ctrl.Service.GetMySQLContractsAsXLSX(r.URL.Query().Get("location"))
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -135,7 +143,7 @@ func (ctrl *APIController) DeleteMySQLContract(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	err = ctrl.Service.DeleteMySQLContract(id)
+	err = ctrl.auditedService(r).DeleteMySQLContract(id)
 	if errors.Is(err, utils.ErrNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
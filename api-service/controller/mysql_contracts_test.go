@@ -25,6 +25,7 @@ import (
 	"github.com/360EntSecGroup-Skylar/excelize"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,7 @@ func TestAddMySQLContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -70,6 +72,7 @@ func TestAddMySQLContract_Success(t *testing.T) {
 	reader := bytes.NewReader(agrBytes)
 	req, err := http.NewRequest("GET", "", reader)
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler := http.HandlerFunc(ac.AddMySQLContract)
 	rr := httptest.NewRecorder()
@@ -212,6 +215,7 @@ func TestAddMySQLContract_InternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -237,6 +241,7 @@ func TestAddMySQLContract_InternalServerError(t *testing.T) {
 	reader := bytes.NewReader(agrBytes)
 	req, err := http.NewRequest("GET", "", reader)
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler := http.HandlerFunc(ac.AddMySQLContract)
 	rr := httptest.NewRecorder()
@@ -258,6 +263,7 @@ func TestUpdateMySQLContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -287,6 +293,7 @@ func TestUpdateMySQLContract_Success(t *testing.T) {
 	req = mux.SetURLVars(req, map[string]string{
 		"id": "aaaaaaaaaaaaaaaaaaaaaaaa",
 	})
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler := http.HandlerFunc(ac.UpdateMySQLContract)
 	rr := httptest.NewRecorder()
@@ -390,6 +397,7 @@ func TestUpdateMySQLContract_NotFoundError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -420,6 +428,7 @@ func TestUpdateMySQLContract_NotFoundError(t *testing.T) {
 	req = mux.SetURLVars(req, map[string]string{
 		"id": "aaaaaaaaaaaaaaaaaaaaaaaa",
 	})
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler := http.HandlerFunc(ac.UpdateMySQLContract)
 	rr := httptest.NewRecorder()
@@ -441,6 +450,7 @@ func TestUpdateMySQLContract_InternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -470,6 +480,7 @@ func TestUpdateMySQLContract_InternalServerError(t *testing.T) {
 	req = mux.SetURLVars(req, map[string]string{
 		"id": "aaaaaaaaaaaaaaaaaaaaaaaa",
 	})
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler := http.HandlerFunc(ac.UpdateMySQLContract)
 	rr := httptest.NewRecorder()
@@ -508,7 +519,7 @@ func TestGetMySQLContracts_Success(t *testing.T) {
 		},
 	}
 
-	as.EXPECT().GetMySQLContracts().
+	as.EXPECT().GetMySQLContracts("Italy").
 		Return(contracts, nil)
 
 	expBytes, err := json.Marshal(contracts)
@@ -541,7 +552,7 @@ func TestGetMySQLContracts_InternalServerError(t *testing.T) {
 		Log:     logger.NewLogger("TEST"),
 	}
 
-	as.EXPECT().GetMySQLContracts().
+	as.EXPECT().GetMySQLContracts("").
 		Return(nil, errMock)
 
 	req, err := http.NewRequest("GET", "/?environment=TEST", nil)
@@ -578,7 +589,7 @@ func TestGetMySQLContractsXLSX_Success(t *testing.T) {
 	xlsx := excelize.File{}
 
 	as.EXPECT().
-		GetMySQLContractsAsXLSX().
+		GetMySQLContractsAsXLSX("").
 		Return(&xlsx, nil)
 
 	rr := httptest.NewRecorder()
@@ -607,7 +618,7 @@ func TestGetMySQLContractsXLSX_InternalServerError1(t *testing.T) {
 	}
 
 	as.EXPECT().
-		GetMySQLContractsAsXLSX().
+		GetMySQLContractsAsXLSX("").
 		Return(nil, aerrMock)
 
 	rr := httptest.NewRecorder()
@@ -625,6 +636,7 @@ func TestDeleteMySQLContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -685,6 +697,7 @@ func TestDeleteMySQLContract_NotFoundError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -722,6 +735,7 @@ func TestDeleteMySQLContract_InternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -56,7 +56,7 @@ func (ctrl *APIController) UpdateMySqlLicenseIgnoredField(w http.ResponseWriter,
 	}
 
 	//set the value
-	err = ctrl.Service.UpdateMySqlLicenseIgnoredField(hostname, dbname, ignored, req.IgnoredComment)
+	err = ctrl.auditedService(r).UpdateMySqlLicenseIgnoredField(hostname, dbname, ignored, req.IgnoredComment)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -58,6 +58,24 @@ func (ctrl *APIController) SearchMySQLInstances(w http.ResponseWriter, r *http.R
 }
 
 func (ctrl *APIController) SearchMySQLInstancesJSON(w http.ResponseWriter, r *http.Request, filter dto.GlobalFilter) {
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListMySQLInstances(filter, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	databases, err := ctrl.Service.SearchMySQLInstances(filter)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
@@ -0,0 +1,164 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestPlanOracleDatabaseConsolidation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	request := dto.OracleDatabaseConsolidationRequest{
+		Location: "Italy",
+		Shapes: []dto.ConsolidationHostShape{
+			{Name: "large", CPUCores: 16, Memory: 128, CoreFactor: 0.5},
+		},
+	}
+
+	raw, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		plan := dto.OracleDatabaseConsolidationPlan{
+			Targets: []dto.ConsolidationTarget{
+				{
+					Name: "large-1", Shape: "large", CPUCores: 16, CoreFactor: 0.5, ProcessorLicenses: 8, CPU: 4,
+					Databases: []dto.ConsolidationDatabase{{Hostname: "db1", Name: "ERCOLE", CPU: 4, CPUSource: dto.ConsolidationCPUSourceHistory}},
+				},
+			},
+			Unplaced:                 []dto.ConsolidationDatabase{},
+			Sources:                  []dto.ConsolidationSource{{Hostname: "db1", CPUCores: 32, ProcessorLicenses: 16}},
+			CurrentProcessorLicenses: 16,
+			PlannedProcessorLicenses: 8,
+			ProcessorLicensesSavings: 8,
+		}
+
+		as.EXPECT().PlanOracleDatabaseConsolidation(request).Return(&plan, nil)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.PlanOracleDatabaseConsolidation)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(plan), rr.Body.String())
+	})
+
+	t.Run("Locations of the user", func(t *testing.T) {
+		request := dto.OracleDatabaseConsolidationRequest{Shapes: request.Shapes}
+		raw, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var user interface{}
+
+		as.EXPECT().ListLocations(user).Return([]string{"Italy", "Germany"}, nil)
+
+		request.Location = "Italy,Germany"
+		as.EXPECT().PlanOracleDatabaseConsolidation(request).Return(&dto.OracleDatabaseConsolidationPlan{}, nil)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.PlanOracleDatabaseConsolidation)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		as.EXPECT().PlanOracleDatabaseConsolidation(request).
+			Return(nil, utils.NewError(utils.ErrInvalidConsolidationRequest, "No shapes"))
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.PlanOracleDatabaseConsolidation)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.PlanOracleDatabaseConsolidation)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte("{")))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		as.EXPECT().PlanOracleDatabaseConsolidation(request).Return(nil, aerrMock)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.PlanOracleDatabaseConsolidation)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		xlsx := excelize.NewFile()
+
+		as.EXPECT().PlanOracleDatabaseConsolidationAsXLSX(request).Return(xlsx, nil)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.PlanOracleDatabaseConsolidation)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		req.Header.Add("Accept", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		_, err = excelize.OpenReader(rr.Body)
+		require.NoError(t, err)
+	})
+}
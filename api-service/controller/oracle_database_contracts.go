@@ -64,7 +64,11 @@ func (ctrl *APIController) AddOracleDatabaseContract(w http.ResponseWriter, r *h
 		return
 	}
 
-	agr, err := ctrl.Service.AddOracleDatabaseContract(req)
+	if !ctrl.authorizeHostsWrite(w, r, req.Hosts) {
+		return
+	}
+
+	agr, err := ctrl.auditedService(r).AddOracleDatabaseContract(req)
 	if errors.Is(err, utils.ErrContractNotFound) ||
 		errors.Is(err, utils.ErrOracleDatabaseLicenseTypeIDNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
@@ -103,7 +107,11 @@ func (ctrl *APIController) UpdateOracleDatabaseContract(w http.ResponseWriter, r
 		return
 	}
 
-	agr, err := ctrl.Service.UpdateOracleDatabaseContract(req)
+	if !ctrl.authorizeResourceHostsWrite(w, r, model.OracleDatabaseContractResource, req.ID, req.Hosts) {
+		return
+	}
+
+	agr, err := ctrl.auditedService(r).UpdateOracleDatabaseContract(req)
 	if errors.Is(err, utils.ErrContractNotFound) ||
 		errors.Is(err, utils.ErrOracleDatabaseLicenseTypeIDNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
@@ -168,6 +176,7 @@ func parseGetOracleDatabaseContractsFilters(urlValues url.Values) (dto.GetOracle
 	filters.CSI = urlValues.Get("csi")
 	filters.Metric = urlValues.Get("metrics")
 	filters.ReferenceNumber = urlValues.Get("reference-number")
+	filters.Location = urlValues.Get("location")
 
 	filters.Unlimited = urlValues.Get("unlimited")
 	if filters.Unlimited != "true" && filters.Unlimited != "false" && filters.Unlimited != "" {
@@ -231,7 +240,7 @@ func (ctrl *APIController) DeleteOracleDatabaseContract(w http.ResponseWriter, r
 		return
 	}
 
-	if err = ctrl.Service.DeleteOracleDatabaseContract(id); errors.Is(err, utils.ErrContractNotFound) {
+	if err = ctrl.auditedService(r).DeleteOracleDatabaseContract(id); errors.Is(err, utils.ErrContractNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
 	} else if err != nil {
@@ -265,7 +274,11 @@ func (ctrl *APIController) AddHostToOracleDatabaseContract(w http.ResponseWriter
 	}
 	defer r.Body.Close()
 
-	if err = ctrl.Service.AddHostToOracleDatabaseContract(id, string(raw)); errors.Is(err, utils.ErrContractNotFound) ||
+	if !ctrl.authorizeHostsWrite(w, r, []string{string(raw)}) {
+		return
+	}
+
+	if err = ctrl.auditedService(r).AddHostToOracleDatabaseContract(id, string(raw)); errors.Is(err, utils.ErrContractNotFound) ||
 		errors.Is(err, utils.ErrNotInClusterHostNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
@@ -296,7 +309,7 @@ func (ctrl *APIController) DeleteHostFromOracleDatabaseContract(w http.ResponseW
 
 	hostname = mux.Vars(r)["hostname"]
 
-	if err = ctrl.Service.DeleteHostFromOracleDatabaseContract(id, hostname); errors.Is(err, utils.ErrContractNotFound) {
+	if err = ctrl.auditedService(r).DeleteHostFromOracleDatabaseContract(id, hostname); errors.Is(err, utils.ErrContractNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
 		return
 	} else if err != nil {
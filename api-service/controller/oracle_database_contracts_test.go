@@ -31,6 +31,7 @@ import (
 
 	"github.com/360EntSecGroup-Skylar/excelize"
 	gomock "github.com/golang/mock/gomock"
+	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,7 @@ func TestAddOracleDatabaseContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -80,6 +82,7 @@ func TestAddOracleDatabaseContract_Success(t *testing.T) {
 	handler := http.HandlerFunc(ac.AddOracleDatabaseContract)
 	req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -190,6 +193,7 @@ func TestAddOracleDatabaseContract_InternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -212,6 +216,7 @@ func TestAddOracleDatabaseContract_InternalServerError(t *testing.T) {
 	handler := http.HandlerFunc(ac.AddOracleDatabaseContract)
 	req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -222,6 +227,7 @@ func TestUpdateOracleDatabaseContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -252,6 +258,7 @@ func TestUpdateOracleDatabaseContract_Success(t *testing.T) {
 	handler := http.HandlerFunc(ac.UpdateOracleDatabaseContract)
 	req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -314,6 +321,7 @@ func TestUpdateOracleDatabaseContract_InternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -344,6 +352,7 @@ func TestUpdateOracleDatabaseContract_InternalServerError(t *testing.T) {
 		handler := http.HandlerFunc(ac.UpdateOracleDatabaseContract)
 		req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 		require.NoError(t, err)
+		context.Set(req, "authorization", serviceAuthorization)
 
 		handler.ServeHTTP(rr, req)
 
@@ -357,6 +366,7 @@ func TestUpdateOracleDatabaseContract_InternalServerError(t *testing.T) {
 		handler := http.HandlerFunc(ac.UpdateOracleDatabaseContract)
 		req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 		require.NoError(t, err)
+		context.Set(req, "authorization", serviceAuthorization)
 
 		handler.ServeHTTP(rr, req)
 
@@ -370,6 +380,7 @@ func TestUpdateOracleDatabaseContract_InternalServerError(t *testing.T) {
 		handler := http.HandlerFunc(ac.UpdateOracleDatabaseContract)
 		req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte(utils.ToJSON(request))))
 		require.NoError(t, err)
+		context.Set(req, "authorization", serviceAuthorization)
 
 		handler.ServeHTTP(rr, req)
 
@@ -592,6 +603,7 @@ func TestAddHostToAssociatedPart_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -612,6 +624,7 @@ func TestAddHostToAssociatedPart_Success(t *testing.T) {
 		"id": "5f50a98611959b1baa17525e",
 	})
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -706,6 +719,7 @@ func TestAddHostToAssociatedPart_FailedContractNotFound(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -726,6 +740,7 @@ func TestAddHostToAssociatedPart_FailedContractNotFound(t *testing.T) {
 		"id": "5f50a98611959b1baa17525e",
 	})
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -736,6 +751,7 @@ func TestAddHostToAssociatedPart_FailedNotInClusterHostNotFound(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -756,6 +772,7 @@ func TestAddHostToAssociatedPart_FailedNotInClusterHostNotFound(t *testing.T) {
 		"id": "5f50a98611959b1baa17525e",
 	})
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -766,6 +783,7 @@ func TestAddHostToAssociatedPart_FailedAerrOracleDatabaseContractNotFound(t *tes
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -786,6 +804,7 @@ func TestAddHostToAssociatedPart_FailedAerrOracleDatabaseContractNotFound(t *tes
 		"id": "5f50a98611959b1baa17525e",
 	})
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -796,6 +815,7 @@ func TestAddHostToAssociatedPart_FailedInternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -816,6 +836,7 @@ func TestAddHostToAssociatedPart_FailedInternalServerError(t *testing.T) {
 		"id": "5f50a98611959b1baa17525e",
 	})
 	require.NoError(t, err)
+	context.Set(req, "authorization", serviceAuthorization)
 
 	handler.ServeHTTP(rr, req)
 
@@ -826,6 +847,7 @@ func TestRemoveHostFromAssociatedPart_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -915,6 +937,7 @@ func TestRemoveHostFromAssociatedPart_FailedContractNotFound(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -946,6 +969,7 @@ func TestRemoveHostFromAssociatedPart_FailedInternalServerError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -977,6 +1001,7 @@ func TestDeleteAssociatedPartFromOracleDatabaseContract_Success(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -1063,6 +1088,7 @@ func TestDeleteAssociatedPartFromOracleDatabaseContract_FailedContractNotFound(t
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
@@ -1093,6 +1119,7 @@ func TestDeleteAssociatedPartFromOracleDatabaseContract_FailedInternalServerErro
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	as := NewMockAPIServiceInterface(mockCtrl)
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
 	ac := APIController{
 		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		Service: as,
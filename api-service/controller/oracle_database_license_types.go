@@ -61,7 +61,7 @@ func (ctrl *APIController) DeleteOracleDatabaseLicenseType(w http.ResponseWriter
 
 	id := mux.Vars(r)["id"]
 
-	err := ctrl.Service.DeleteOracleDatabaseLicenseType(id)
+	err := ctrl.auditedService(r).DeleteOracleDatabaseLicenseType(id)
 
 	if errors.Is(err, utils.ErrOracleDatabaseLicenseTypeIDNotFound) {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
@@ -92,7 +92,7 @@ func (ctrl *APIController) AddOracleDatabaseLicenseType(w http.ResponseWriter, r
 		return
 	}
 
-	lt, err := ctrl.Service.AddOracleDatabaseLicenseType(req)
+	lt, err := ctrl.auditedService(r).AddOracleDatabaseLicenseType(req)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -118,7 +118,7 @@ func (ctrl *APIController) UpdateOracleDatabaseLicenseType(w http.ResponseWriter
 		return
 	}
 
-	lt, err := ctrl.Service.UpdateOracleDatabaseLicenseType(req)
+	lt, err := ctrl.auditedService(r).UpdateOracleDatabaseLicenseType(req)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
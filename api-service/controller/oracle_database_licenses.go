@@ -56,7 +56,7 @@ func (ctrl *APIController) UpdateLicenseIgnoredField(w http.ResponseWriter, r *h
 	}
 
 	//set the value
-	err = ctrl.Service.UpdateLicenseIgnoredField(hostname, dbname, licensetypeid, ignored, req.IgnoredComment)
+	err = ctrl.auditedService(r).UpdateLicenseIgnoredField(hostname, dbname, licensetypeid, ignored, req.IgnoredComment)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// SimulateOracleDatabaseLicenses return the difference of the licenses compliance after the hypothetical changes in the request
+func (ctrl *APIController) SimulateOracleDatabaseLicenses(w http.ResponseWriter, r *http.Request) {
+	var simulation dto.OracleDatabaseLicensesSimulation
+
+	if err := utils.Decode(r.Body, &simulation); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	diffs, err := ctrl.Service.SimulateOracleDatabaseLicenses(simulation, r.URL.Query().Get("location"))
+	if errors.Is(err, utils.ErrInvalidLicenseSimulation) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, diffs)
+}
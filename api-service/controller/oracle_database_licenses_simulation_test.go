@@ -0,0 +1,102 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestSimulateOracleDatabaseLicenses(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	simulation := dto.OracleDatabaseLicensesSimulation{
+		Changes: []dto.OracleDatabaseLicensesSimulationChange{
+			{Type: dto.SimulationChangeHostCores, Hostname: "db1", CPUCores: 8},
+		},
+	}
+
+	raw, err := json.Marshal(simulation)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		diffs := []dto.LicenseComplianceDiff{
+			{
+				LicenseTypeID: "A90611",
+				Before:        dto.LicenseCompliance{LicenseTypeID: "A90611", Consumed: 2, Covered: 2, Compliance: 1},
+				After:         dto.LicenseCompliance{LicenseTypeID: "A90611", Consumed: 4, Covered: 2, Compliance: 0.5},
+				ConsumedDelta: 2,
+			},
+		}
+
+		as.EXPECT().SimulateOracleDatabaseLicenses(simulation, "Italy").Return(diffs, nil)
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.SimulateOracleDatabaseLicenses)
+		req, err := http.NewRequest("POST", "/?location=Italy", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(diffs), rr.Body.String())
+	})
+
+	t.Run("Invalid change", func(t *testing.T) {
+		as.EXPECT().SimulateOracleDatabaseLicenses(simulation, "").
+			Return(nil, utils.NewError(utils.ErrInvalidLicenseSimulation, "Unknown host"))
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.SimulateOracleDatabaseLicenses)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(ac.SimulateOracleDatabaseLicenses)
+		req, err := http.NewRequest("POST", "/", bytes.NewReader([]byte("{")))
+		require.NoError(t, err)
+
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -418,6 +418,24 @@ func (ctrl *APIController) SearchOracleDatabases(w http.ResponseWriter, r *http.
 
 // SearchOracleDatabasesJSON search databases data using the filters in the request returning it in JSON
 func (ctrl *APIController) SearchOracleDatabasesJSON(w http.ResponseWriter, r *http.Request, filter dto.SearchOracleDatabasesFilter) {
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListOracleDatabases(filter, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	databases, err := ctrl.Service.SearchOracleDatabases(filter)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
@@ -1186,7 +1186,7 @@ func TestSearchOracleDatabases_JSONPaged(t *testing.T) {
 		SearchOracleDatabases(
 			dto.SearchOracleDatabasesFilter{
 				dto.GlobalFilter{
-					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"),
+					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"), dto.HostMetadataFilter{},
 				},
 				"foobar", "Hostname", true, 2, 3,
 			}).
@@ -1203,6 +1203,57 @@ func TestSearchOracleDatabases_JSONPaged(t *testing.T) {
 	assert.JSONEq(t, utils.ToJSON(&resFromService), rr.Body.String())
 }
 
+func TestSearchOracleDatabases_JSONListQuery(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	total := 1
+	page := &dto.ListPage{
+		Items: []map[string]interface{}{
+			{"hostname": "test-db", "name": "ERCOLE"},
+		},
+		Total: &total,
+	}
+
+	as.EXPECT().
+		ListOracleDatabases(
+			dto.SearchOracleDatabasesFilter{
+				GlobalFilter: dto.GlobalFilter{
+					Location:    "Italy",
+					Environment: "TST",
+					OlderThan:   utils.MAX_TIME,
+				},
+				Search:     "foobar",
+				PageNumber: -1,
+				PageSize:   -1,
+			},
+			dto.ListQuery{
+				Limit:  dto.DefaultListLimit,
+				Sort:   []dto.SortField{{Field: "name"}},
+				Fields: []string{"hostname", "name"},
+			}).
+		Return(page, nil)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ac.SearchOracleDatabases)
+	req, err := http.NewRequest("GET", "/databases?search=foobar&sort=name&fields=hostname,name&location=Italy&environment=TST", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(page.Items), rr.Body.String())
+	assert.Equal(t, "1", rr.Header().Get("X-Total-Count"))
+	assert.Empty(t, rr.Header().Get("X-Next-Cursor"))
+}
+
 func TestSearchOracleDatabases_JSONUnpaged(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -1274,7 +1325,7 @@ func TestSearchOracleDatabases_JSONUnpaged(t *testing.T) {
 		SearchOracleDatabases(
 			dto.SearchOracleDatabasesFilter{
 				dto.GlobalFilter{
-					"", "", utils.MAX_TIME,
+					"", "", utils.MAX_TIME, dto.HostMetadataFilter{},
 				},
 				"", "", false, -1, -1,
 			},
@@ -1398,7 +1449,7 @@ func TestSearchOracleDatabases_JSONInternalServerError1(t *testing.T) {
 		SearchOracleDatabases(
 			dto.SearchOracleDatabasesFilter{
 				dto.GlobalFilter{
-					"", "", utils.MAX_TIME,
+					"", "", utils.MAX_TIME, dto.HostMetadataFilter{},
 				},
 				"", "", false, -1, -1,
 			},
@@ -1434,7 +1485,7 @@ func TestSearchOracleDatabases_XLSXSuccess(t *testing.T) {
 		SearchOracleDatabasesAsXLSX(
 			dto.SearchOracleDatabasesFilter{
 				dto.GlobalFilter{
-					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"),
+					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"), dto.HostMetadataFilter{},
 				},
 				"foobar", "Hostname", true, -1, -1,
 			},
@@ -1526,7 +1577,7 @@ func TestSearchOracleDatabases_XLSXInternalServerError1(t *testing.T) {
 		SearchOracleDatabasesAsXLSX(
 			dto.SearchOracleDatabasesFilter{
 				dto.GlobalFilter{
-					"", "", utils.MAX_TIME,
+					"", "", utils.MAX_TIME, dto.HostMetadataFilter{},
 				},
 				"", "", false, -1, -1,
 			},
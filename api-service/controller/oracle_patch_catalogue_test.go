@@ -0,0 +1,154 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestImportOraclePatchCatalogue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().WithAuditActor(gomock.Any()).Return(as).AnyTimes()
+
+	releases := []model.OraclePatchRelease{
+		{
+			PatchNumber: "33806152",
+			Type:        model.OraclePatchTypeRU,
+			Version:     "19",
+			Release:     "19.15.0.0.220419",
+			ReleaseDate: utils.P("2022-04-19T00:00:00Z"),
+			Description: "Database Release Update 19.15.0.0.220419",
+		},
+	}
+	versions := []model.OracleVersionSupport{
+		{Version: "19", PremierSupportEnd: utils.P("2024-04-30T00:00:00Z"), ExtendedSupportEnd: utils.P("2027-04-30T00:00:00Z")},
+		{Version: "23"},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		catalogue := &model.OraclePatchCatalogue{Releases: releases, Versions: []model.OracleVersionSupport{}}
+		as.EXPECT().ImportOraclePatchCatalogue(dto.OraclePatchCatalogueImport{Releases: releases}).
+			Return(catalogue, nil)
+
+		body := "patchNumber,type,version,release,releaseDate,description\n" +
+			"33806152,ru,19,19.15.0.0.220419,2022-04-19,Database Release Update 19.15.0.0.220419\n"
+		req, err := http.NewRequest("PUT", "/admin/oracle-patch-catalogue", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ImportOraclePatchCatalogue).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(catalogue), rr.Body.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		catalogue := &model.OraclePatchCatalogue{Releases: []model.OraclePatchRelease{}, Versions: versions}
+		as.EXPECT().ImportOraclePatchCatalogue(dto.OraclePatchCatalogueImport{Versions: versions}).
+			Return(catalogue, nil)
+
+		body := `{"versions": [
+			{"version": "19", "premierSupportEnd": "2024-04-30", "extendedSupportEnd": "2027-04-30T00:00:00Z"},
+			{"version": "23"}
+		]}`
+		req, err := http.NewRequest("PUT", "/admin/oracle-patch-catalogue", strings.NewReader(body))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ImportOraclePatchCatalogue).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(catalogue), rr.Body.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, body := range []string{
+			`{}`,
+			`{"foo": []}`,
+			`{"releases": [{"patchNumber": "1", "type": "FOO", "version": "19", "releaseDate": "2022-04-19"}]}`,
+			`{"releases": [{"patchNumber": "1", "type": "RU", "version": "19"}]}`,
+			`{"releases": [{"patchNumber": "1", "type": "RU", "version": "19", "releaseDate": "19/04/2022"}]}`,
+			`{"versions": [{"version": "19"}, {"version": "19"}]}`,
+		} {
+			req, err := http.NewRequest("PUT", "/admin/oracle-patch-catalogue", strings.NewReader(body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(ac.ImportOraclePatchCatalogue).ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusUnprocessableEntity, rr.Code, body)
+		}
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		ac.Config.APIService.ReadOnly = true
+		defer func() { ac.Config.APIService.ReadOnly = false }()
+
+		req, err := http.NewRequest("PUT", "/admin/oracle-patch-catalogue", strings.NewReader(`{"versions": []}`))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ImportOraclePatchCatalogue).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
+
+func TestGetOraclePatchCatalogue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockAPIServiceInterface(mockCtrl)
+	ac := APIController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	catalogue := &model.OraclePatchCatalogue{Releases: []model.OraclePatchRelease{}, Versions: []model.OracleVersionSupport{{Version: "19"}}}
+	as.EXPECT().GetOraclePatchCatalogue().Return(catalogue, nil)
+
+	req, err := http.NewRequest("GET", "/hosts/technologies/oracle/patch-catalogue", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ac.GetOraclePatchCatalogue).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(catalogue), rr.Body.String())
+}
@@ -57,6 +57,24 @@ func (ctrl *APIController) SearchPostgreSqlInstances(w http.ResponseWriter, r *h
 
 //SearchPostgreSqlInstancesJSON search instances data using the filters in the request returning it in JSON
 func (ctrl *APIController) SearchPostgreSqlInstancesJSON(w http.ResponseWriter, r *http.Request, filter dto.SearchPostgreSqlInstancesFilter) {
+	listQuery, err := dto.GetListQuery(r)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if listQuery != nil {
+		page, err := ctrl.Service.ListPostgreSqlInstances(filter, *listQuery)
+		if err != nil {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeListPage(w, page)
+
+		return
+	}
+
 	instances, err := ctrl.Service.SearchPostgreSqlInstances(filter)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
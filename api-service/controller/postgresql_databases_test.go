@@ -67,7 +67,7 @@ func TestSearchPostgreSqlInstances_JSONPaged(t *testing.T) {
 		SearchPostgreSqlInstances(
 			dto.SearchPostgreSqlInstancesFilter{
 				dto.GlobalFilter{
-					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"),
+					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"), dto.HostMetadataFilter{},
 				},
 				"foobar", "Hostname", true, 2, 3,
 			}).
@@ -126,7 +126,7 @@ func TestSearchPostgreSqlInstances_JSONUnpaged(t *testing.T) {
 		SearchPostgreSqlInstances(
 			dto.SearchPostgreSqlInstancesFilter{
 				dto.GlobalFilter{
-					"", "", utils.MAX_TIME,
+					"", "", utils.MAX_TIME, dto.HostMetadataFilter{},
 				},
 				"", "", false, -1, -1,
 			},
@@ -250,7 +250,7 @@ func TestSearchPostgreSqlInstances_JSONInternalServerError1(t *testing.T) {
 		SearchPostgreSqlInstances(
 			dto.SearchPostgreSqlInstancesFilter{
 				dto.GlobalFilter{
-					"", "", utils.MAX_TIME,
+					"", "", utils.MAX_TIME, dto.HostMetadataFilter{},
 				},
 				"", "", false, -1, -1,
 			},
@@ -286,7 +286,7 @@ func TestSearchPostgreSqlInstances_XLSXSuccess(t *testing.T) {
 		SearchPostgreSqlInstancesAsXLSX(
 			dto.SearchPostgreSqlInstancesFilter{
 				dto.GlobalFilter{
-					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"),
+					"Italy", "TST", utils.P("2020-06-10T11:54:59Z"), dto.HostMetadataFilter{},
 				},
 				"foobar", "Hostname", true, -1, -1,
 			},
@@ -378,7 +378,7 @@ func TestSearchPostgreSqlInstances_XLSXInternalServerError1(t *testing.T) {
 		SearchPostgreSqlInstancesAsXLSX(
 			dto.SearchPostgreSqlInstancesFilter{
 				dto.GlobalFilter{
-					"", "", utils.MAX_TIME,
+					"", "", utils.MAX_TIME, dto.HostMetadataFilter{},
 				},
 				"", "", false, -1, -1,
 			},
@@ -63,7 +63,7 @@ func (ctrl *APIController) AddRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ctrl.Service.AddRole(*role); err != nil {
+	if err := ctrl.auditedService(r).AddRole(*role); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
 	}
@@ -85,7 +85,7 @@ func (ctrl *APIController) UpdateRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ctrl.Service.UpdateRole(*role); err != nil {
+	if err := ctrl.auditedService(r).UpdateRole(*role); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
 	}
@@ -111,7 +111,7 @@ func (ctrl *APIController) RemoveRole(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if errDel := ctrl.Service.RemoveRole(roleName); errDel != nil {
+	if errDel := ctrl.auditedService(r).RemoveRole(roleName); errDel != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, errDel)
 		return
 	}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ercole-io/ercole/v2/api-service/auth"
 	"github.com/ercole-io/ercole/v2/api-service/auth/middleware"
+	"github.com/ercole-io/ercole/v2/utils/metrics"
 )
 
 const (
@@ -32,6 +33,7 @@ const (
 // GetApiControllerHandler setup the routes of the router using the handler in the controller as http handler
 func (ctrl *APIController) GetApiControllerHandler(auths []auth.AuthenticationProvider) http.Handler {
 	router := mux.NewRouter()
+	router.Use(metrics.Middleware("api-service"))
 
 	//Add the routes
 	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
@@ -55,6 +57,13 @@ func (ctrl *APIController) GetApiControllerHandler(auths []auth.AuthenticationPr
 			prefix = "/ldap"
 		}
 
+		if oidc, ok := ap.(*auth.OIDCAuthenticationProvider); ok {
+			router.HandleFunc("/oidc/login", oidc.Login).Methods("GET")
+			router.HandleFunc("/oidc/callback", oidc.GetToken).Methods("GET")
+
+			prefix = "/oidc"
+		}
+
 		subrouter.Use(ap.AuthenticateMiddleware)
 		ctrl.setupProtectedRoutes(subrouter.PathPrefix(prefix).Subrouter())
 	}
@@ -63,23 +72,26 @@ func (ctrl *APIController) GetApiControllerHandler(auths []auth.AuthenticationPr
 }
 
 func (ctrl *APIController) setupProtectedRoutes(router *mux.Router) {
+	router.Use(ctrl.authorize)
+
 	// ERCOLE
 	router.HandleFunc("/version", ctrl.GetVersion).Methods("GET")
 	router.HandleFunc("/configuration", ctrl.GetConfig).Methods("GET")
-	router.HandleFunc("/configuration", ctrl.UpdateConfig).Methods("POST")
+	router.HandleFunc("/configuration", middleware.Admin(ctrl.UpdateConfig)).Methods("POST")
 	router.HandleFunc("/nodes", ctrl.GetNodes).Methods("GET")
+	router.HandleFunc("/metrics", ctrl.GetMetrics).Methods("GET")
 
 	// USERS
 	router.HandleFunc(userGroup, ctrl.GetUsers).Methods("GET")
 	router.HandleFunc(fmt.Sprintf("%s/info", userGroup), ctrl.GetInfo).Methods("GET")
 	router.HandleFunc(fmt.Sprintf("%s/{username}", userGroup), ctrl.GetUser).Methods("GET")
-	router.HandleFunc(fmt.Sprintf("%s/{username}/change-password", userGroup), ctrl.ChangePassword).Methods("POST")
+	router.HandleFunc(fmt.Sprintf("%s/{username}/change-password", userGroup), middleware.SelfOrAdmin(ctrl.ChangePassword)).Methods("POST")
 
 	// GROUPS
-	router.HandleFunc("/groups", ctrl.InsertGroup).Methods("POST")
-	router.HandleFunc("/groups/{name}", ctrl.UpdateGroup).Methods("PUT")
+	router.HandleFunc("/groups", middleware.Admin(ctrl.InsertGroup)).Methods("POST")
+	router.HandleFunc("/groups/{name}", middleware.Admin(ctrl.UpdateGroup)).Methods("PUT")
 	router.HandleFunc("/groups/{name}", ctrl.GetGroup).Methods("GET")
-	router.HandleFunc("/groups/{name}", ctrl.DeleteGroup).Methods("DELETE")
+	router.HandleFunc("/groups/{name}", middleware.Admin(ctrl.DeleteGroup)).Methods("DELETE")
 	router.HandleFunc("/groups", ctrl.GetGroups).Methods("GET")
 
 	// HOSTS
@@ -90,14 +102,18 @@ func (ctrl *APIController) setupProtectedRoutes(router *mux.Router) {
 	router.HandleFunc("/hosts/operating-systems", ctrl.GetOperatingSystemStats).Methods("GET")
 	router.HandleFunc("/hosts/locations", ctrl.ListLocations).Methods("GET")
 	router.HandleFunc("/hosts/environments", ctrl.ListEnvironments).Methods("GET")
+	router.HandleFunc("/hosts/metadata", ctrl.ListHostMetadata).Methods("GET")
+	router.HandleFunc("/hosts/capacity-forecasts", ctrl.ListCapacityForecasts).Methods("GET")
 	router.HandleFunc("/hosts/clusters", ctrl.SearchClusters).Methods("GET")
 	router.HandleFunc("/hosts/clusters/{name}", ctrl.GetCluster).Methods("GET")
 
 	router.HandleFunc("/hosts/{hostname}", ctrl.GetHost).Methods("GET")
-	router.HandleFunc("/hosts/{hostname}", ctrl.DismissHost).Methods("DELETE")
-	router.HandleFunc("/hosts/{hostname}/technologies/oracle/databases/{dbname}/licenses/{licenseTypeID}/ignored/{ignored}", ctrl.UpdateLicenseIgnoredField).Methods("PUT")
+	router.HandleFunc("/hosts/{hostname}", middleware.Write(ctrl.DismissHost)).Methods("DELETE")
+	router.HandleFunc("/hosts/{hostname}/technologies/oracle/databases/{dbname}/licenses/{licenseTypeID}/ignored/{ignored}", middleware.Write(ctrl.UpdateLicenseIgnoredField)).Methods("PUT")
 
 	router.HandleFunc("/hosts/{hostname}/is-missing-db", ctrl.GetMissingDbHost).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}/metadata", ctrl.GetHostMetadata).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}/metadata", middleware.Write(ctrl.UpdateHostMetadata)).Methods("PUT")
 
 	router.HandleFunc("/hosts/technologies", ctrl.ListTechnologies).Methods("GET")
 
@@ -128,9 +144,12 @@ func (ctrl *APIController) setupProtectedRoutes(router *mux.Router) {
 	router.HandleFunc("/hosts/technologies/oracle/databases/statistics", ctrl.GetOracleDatabasesStatistics).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/consumed-licenses", ctrl.SearchOracleDatabaseUsedLicenses).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/licenses-compliance", ctrl.GetOracleDatabaseLicensesCompliance).Methods("GET")
+	router.HandleFunc("/hosts/technologies/oracle/databases/licenses-compliance/simulation", ctrl.SimulateOracleDatabaseLicenses).Methods("POST")
+	router.HandleFunc("/hosts/technologies/oracle/databases/consolidation-plan", ctrl.PlanOracleDatabaseConsolidation).Methods("POST")
 	router.HandleFunc("/hosts/technologies/oracle/databases/addms", ctrl.SearchOracleDatabaseAddms).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/segment-advisors", ctrl.SearchOracleDatabaseSegmentAdvisors).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/patch-advisors", ctrl.SearchOracleDatabasePatchAdvisors).Methods("GET")
+	router.HandleFunc("/hosts/technologies/oracle/patch-catalogue", ctrl.GetOraclePatchCatalogue).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/patch-list", ctrl.GetOraclePatchList).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/option-list", ctrl.GetOracleOptionList).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/tablespaces", ctrl.ListOracleDatabaseTablespaces).Methods("GET")
@@ -139,40 +158,41 @@ func (ctrl *APIController) setupProtectedRoutes(router *mux.Router) {
 	router.HandleFunc("/hosts/technologies/oracle/databases/schemas", ctrl.ListOracleDatabaseSchemas).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/pdbs", ctrl.ListOracleDatabasePdbs).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/backup-list", ctrl.GetOracleBackupList).Methods("GET")
+	router.HandleFunc("/hosts/technologies/oracle/databases/backup-compliance", ctrl.GetBackupComplianceReport).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/service-list", ctrl.GetOracleServiceList).Methods("GET")
 	router.HandleFunc("/hosts/technologies/oracle/databases/partitionings", ctrl.ListOracleDatabasePartitionings).Methods("GET")
 
 	// ORACLE CONTRACTS
-	router.HandleFunc("/contracts/oracle/database", ctrl.AddOracleDatabaseContract).Methods("POST")
-	router.HandleFunc("/contracts/oracle/database", ctrl.UpdateOracleDatabaseContract).Methods("PUT")
+	router.HandleFunc("/contracts/oracle/database", middleware.Write(ctrl.AddOracleDatabaseContract)).Methods("POST")
+	router.HandleFunc("/contracts/oracle/database", middleware.Write(ctrl.UpdateOracleDatabaseContract)).Methods("PUT")
 	router.HandleFunc("/contracts/oracle/database", ctrl.GetOracleDatabaseContracts).Methods("GET")
-	router.HandleFunc("/contracts/oracle/database/{id}", ctrl.DeleteOracleDatabaseContract).Methods("DELETE")
+	router.HandleFunc("/contracts/oracle/database/{id}", middleware.Write(ctrl.DeleteOracleDatabaseContract)).Methods("DELETE")
 
-	router.HandleFunc("/contracts/oracle/database/{id}/hosts", ctrl.AddHostToOracleDatabaseContract).Methods("POST")
-	router.HandleFunc("/contracts/oracle/database/{id}/hosts/{hostname}", ctrl.DeleteHostFromOracleDatabaseContract).Methods("DELETE")
+	router.HandleFunc("/contracts/oracle/database/{id}/hosts", middleware.Write(ctrl.AddHostToOracleDatabaseContract)).Methods("POST")
+	router.HandleFunc("/contracts/oracle/database/{id}/hosts/{hostname}", middleware.Write(ctrl.DeleteHostFromOracleDatabaseContract)).Methods("DELETE")
 
 	// ORACLE LICENSE
 	router.HandleFunc("/hosts/{hostname}/technologies/oracle/databases/{dbname}/can-migrate", ctrl.CanMigrateLicense).Methods("GET")
 
 	// SQL SERVER CONTRACTS
-	router.HandleFunc("/contracts/microsoft/database", ctrl.AddSqlServerDatabaseContract).Methods("POST")
-	router.HandleFunc("/contracts/microsoft/database", ctrl.UpdateSqlServerDatabaseContract).Methods("PUT")
+	router.HandleFunc("/contracts/microsoft/database", middleware.Write(ctrl.AddSqlServerDatabaseContract)).Methods("POST")
+	router.HandleFunc("/contracts/microsoft/database", middleware.Write(ctrl.UpdateSqlServerDatabaseContract)).Methods("PUT")
 	router.HandleFunc("/contracts/microsoft/database", ctrl.GetSqlServerDatabaseContracts).Methods("GET")
-	router.HandleFunc("/contracts/microsoft/database/{id}", ctrl.DeleteSqlServerDatabaseContract).Methods("DELETE")
+	router.HandleFunc("/contracts/microsoft/database/{id}", middleware.Write(ctrl.DeleteSqlServerDatabaseContract)).Methods("DELETE")
 
 	// MYSQL
 	router.HandleFunc("/hosts/technologies/mysql/databases", ctrl.SearchMySQLInstances).Methods("GET")
-	router.HandleFunc("/hosts/{hostname}/technologies/mysql/databases/{dbname}/ignored/{ignored}", ctrl.UpdateMySqlLicenseIgnoredField).Methods("PUT")
+	router.HandleFunc("/hosts/{hostname}/technologies/mysql/databases/{dbname}/ignored/{ignored}", middleware.Write(ctrl.UpdateMySqlLicenseIgnoredField)).Methods("PUT")
 
 	// MYSQL CONTRACTS
-	router.HandleFunc("/contracts/mysql/database", ctrl.AddMySQLContract).Methods("POST")
-	router.HandleFunc("/contracts/mysql/database/{id}", ctrl.UpdateMySQLContract).Methods("PUT")
+	router.HandleFunc("/contracts/mysql/database", middleware.Write(ctrl.AddMySQLContract)).Methods("POST")
+	router.HandleFunc("/contracts/mysql/database/{id}", middleware.Write(ctrl.UpdateMySQLContract)).Methods("PUT")
 	router.HandleFunc("/contracts/mysql/database", ctrl.GetMySQLContracts).Methods("GET")
-	router.HandleFunc("/contracts/mysql/database/{id}", ctrl.DeleteMySQLContract).Methods("DELETE")
+	router.HandleFunc("/contracts/mysql/database/{id}", middleware.Write(ctrl.DeleteMySQLContract)).Methods("DELETE")
 
 	// SQL SERVER
 	router.HandleFunc("/hosts/technologies/microsoft/databases", ctrl.SearchSqlServerInstances).Methods("GET")
-	router.HandleFunc("/hosts/{hostname}/technologies/microsoft/databases/{dbname}/ignored/{ignored}", ctrl.UpdateSqlServerLicenseIgnoredField).Methods("PUT")
+	router.HandleFunc("/hosts/{hostname}/technologies/microsoft/databases/{dbname}/ignored/{ignored}", middleware.Write(ctrl.UpdateSqlServerLicenseIgnoredField)).Methods("PUT")
 
 	// POSTGRESQL
 	router.HandleFunc("/hosts/technologies/postgresql/databases", ctrl.SearchPostgreSqlInstances).Methods("GET")
@@ -182,12 +202,23 @@ func (ctrl *APIController) setupProtectedRoutes(router *mux.Router) {
 
 	// ALERTS
 	router.HandleFunc("/alerts", ctrl.SearchAlerts).Methods("GET")
-	router.HandleFunc("/alerts/ack", ctrl.AckAlerts).Methods("POST")
+	router.HandleFunc("/alerts/ack", middleware.Write(ctrl.AckAlerts)).Methods("POST")
+
+	router.HandleFunc("/alerts/routing-rules", ctrl.GetAlertRoutingRules).Methods("GET")
+	router.HandleFunc("/alerts/routing-rules/{id}", ctrl.GetAlertRoutingRule).Methods("GET")
+	router.HandleFunc("/alerts/routing-rules", middleware.Admin(ctrl.AddAlertRoutingRule)).Methods("POST")
+	router.HandleFunc("/alerts/routing-rules/{id}", middleware.Admin(ctrl.UpdateAlertRoutingRule)).Methods("PUT")
+	router.HandleFunc("/alerts/routing-rules/{id}", middleware.Admin(ctrl.DeleteAlertRoutingRule)).Methods("DELETE")
+
+	router.HandleFunc("/alerts/{id}", ctrl.GetAlert).Methods("GET")
+	router.HandleFunc("/alerts/{id}/status", middleware.Write(ctrl.UpdateAlertStatus)).Methods("PUT")
+	router.HandleFunc("/alerts/{id}/assignee", middleware.Write(ctrl.AssignAlert)).Methods("PUT")
+	router.HandleFunc("/alerts/{id}/comments", middleware.Write(ctrl.CommentAlert)).Methods("POST")
 
 	router.HandleFunc("/database/connection/status", ctrl.GetDatabaseConnectionStatus).Methods("GET")
 
 	// UPLOADS
-	router.HandleFunc("/contracts/{databaseType}/upload", ctrl.ImportContractFromCSV).Methods("POST")
+	router.HandleFunc("/contracts/{databaseType}/upload", middleware.Write(ctrl.ImportContractFromCSV)).Methods("POST")
 	router.HandleFunc("/contracts/{databaseType}/sample", ctrl.GetContractSampleCSV).Methods("GET")
 
 	// EXADATA
@@ -203,9 +234,9 @@ func (ctrl *APIController) setupSettingsRoutes(router *mux.Router) {
 	router.HandleFunc("/features", ctrl.GetErcoleFeatures).Methods("GET")
 	router.HandleFunc("/technologies", ctrl.GetTechnologyList).Methods("GET")
 	router.HandleFunc("/oracle/database/license-types", ctrl.GetOracleDatabaseLicenseTypes).Methods("GET")
-	router.HandleFunc("/oracle/database/license-types/{id}", ctrl.DeleteOracleDatabaseLicenseType).Methods("DELETE")
-	router.HandleFunc("/oracle/database/license-types", ctrl.AddOracleDatabaseLicenseType).Methods("POST")
-	router.HandleFunc("/oracle/database/license-types/{id}", ctrl.UpdateOracleDatabaseLicenseType).Methods("PUT")
+	router.HandleFunc("/oracle/database/license-types/{id}", middleware.Admin(ctrl.DeleteOracleDatabaseLicenseType)).Methods("DELETE")
+	router.HandleFunc("/oracle/database/license-types", middleware.Admin(ctrl.AddOracleDatabaseLicenseType)).Methods("POST")
+	router.HandleFunc("/oracle/database/license-types/{id}", middleware.Admin(ctrl.UpdateOracleDatabaseLicenseType)).Methods("PUT")
 	router.HandleFunc("/microsoft/database/license-types", ctrl.GetSqlServerDatabaseLicenseTypes).Methods("GET")
 	router.HandleFunc("/mysql/database/license-types", ctrl.GetMySqlLicenseTypes).Methods("GET")
 }
@@ -215,7 +246,7 @@ func (ctrl *APIController) setupFrontendAPIRoutes(router *mux.Router) {
 }
 
 func (ctrl *APIController) setupAdminRoutes(router *mux.Router) {
-	router.HandleFunc(userGroup, ctrl.AddUser).Methods("POST")
+	router.HandleFunc(userGroup, middleware.Admin(ctrl.AddUser)).Methods("POST")
 	router.HandleFunc(fmt.Sprintf("%s/{username}", userGroup), middleware.Admin(ctrl.UpdateUser)).Methods("PUT")
 	router.HandleFunc(fmt.Sprintf("%s/{username}", userGroup), middleware.Admin(ctrl.RemoveUser)).Methods("DELETE")
 	router.HandleFunc(fmt.Sprintf("%s/{username}/reset-password", userGroup), middleware.Admin(ctrl.NewPassword)).Methods("POST")
@@ -228,9 +259,27 @@ func (ctrl *APIController) setupAdminRoutes(router *mux.Router) {
 	router.HandleFunc("/roles/{roleName}", middleware.Admin(ctrl.UpdateRole)).Methods("PUT")
 	router.HandleFunc("/roles/{roleName}", middleware.Admin(ctrl.RemoveRole)).Methods("DELETE")
 
+	// API TOKENS
+	router.HandleFunc("/api-tokens", middleware.Admin(ctrl.GetAPITokens)).Methods("GET")
+	router.HandleFunc("/api-tokens", middleware.Admin(ctrl.CreateAPIToken)).Methods("POST")
+	router.HandleFunc("/api-tokens/{id}", middleware.Admin(ctrl.DeleteAPIToken)).Methods("DELETE")
+
+	// HOST IDENTITIES
+	router.HandleFunc("/host-identities", middleware.Admin(ctrl.ListHostIdentities)).Methods("GET")
+	router.HandleFunc("/host-identities/suggestions", middleware.Admin(ctrl.GetHostAliasSuggestions)).Methods("GET")
+	router.HandleFunc("/host-identities/merge", middleware.Admin(ctrl.MergeHost)).Methods("POST")
+	router.HandleFunc("/host-identities/{hostname}/aliases", middleware.Admin(ctrl.AddHostAlias)).Methods("POST")
+	router.HandleFunc("/host-identities/{hostname}/aliases/{alias}", middleware.Admin(ctrl.RemoveHostAlias)).Methods("DELETE")
+
+	// ORACLE PATCH CATALOGUE
+	router.HandleFunc("/oracle-patch-catalogue", middleware.Admin(ctrl.ImportOraclePatchCatalogue)).Methods("PUT")
+
+	// AUDIT LOG
+	router.HandleFunc("/audit-log", middleware.Admin(ctrl.SearchAuditLog)).Methods("GET")
+
 	// NODES
-	router.HandleFunc("/nodes", ctrl.AddNode).Methods("POST")
+	router.HandleFunc("/nodes", middleware.Admin(ctrl.AddNode)).Methods("POST")
 	router.HandleFunc("/nodes/{name}", ctrl.GetNode).Methods("GET")
-	router.HandleFunc("/nodes/{name}", ctrl.UpdateNode).Methods("PUT")
-	router.HandleFunc("/nodes/{name}", ctrl.RemoveNode).Methods("DELETE")
+	router.HandleFunc("/nodes/{name}", middleware.Admin(ctrl.UpdateNode)).Methods("PUT")
+	router.HandleFunc("/nodes/{name}", middleware.Admin(ctrl.RemoveNode)).Methods("DELETE")
 }
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
 )
 
@@ -32,6 +34,13 @@ const (
 )
 
 func (ctrl *APIController) ImportContractFromCSV(w http.ResponseWriter, r *http.Request) {
+	// the imported contracts can refer to the hosts of any location
+	if authorization, ok := context.Get(r, "authorization").(model.UserAuthorization); !ok || !authorization.CanWriteAllLocations() {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+			utils.NewError(errors.New("The user isn't allowed to modify the hosts of all the locations"), "FORBIDDEN_REQUEST"))
+		return
+	}
+
 	file, _, err := r.FormFile("file")
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
@@ -47,16 +56,17 @@ func (ctrl *APIController) ImportContractFromCSV(w http.ResponseWriter, r *http.
 	}
 
 	c := make(chan error)
+	auditedService := ctrl.auditedService(r)
 
 	reader := csv.NewReader(file)
 	go func(reader *csv.Reader) {
 		switch databaseType {
 		case "oracle":
-			c <- ctrl.Service.ImportOracleDatabaseContracts(reader)
+			c <- auditedService.ImportOracleDatabaseContracts(reader)
 		case "sqlserver":
-			c <- ctrl.Service.ImportSQLServerDatabaseContracts(reader)
+			c <- auditedService.ImportSQLServerDatabaseContracts(reader)
 		case "mysql":
-			c <- ctrl.Service.ImportMySQLDatabaseContracts(reader)
+			c <- auditedService.ImportMySQLDatabaseContracts(reader)
 		}
 	}(reader)
 
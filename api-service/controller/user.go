@@ -61,7 +61,7 @@ func (ctrl *APIController) AddUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ctrl.Service.AddUser(*user); err != nil {
+	if err := ctrl.auditedService(r).AddUser(*user); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
 	}
@@ -78,7 +78,7 @@ func (ctrl *APIController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ctrl.Service.UpdateUserGroups(username, user.Groups); err != nil {
+	if err := ctrl.auditedService(r).UpdateUserGroups(username, user.Groups); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
 	}
@@ -94,7 +94,7 @@ func (ctrl *APIController) RemoveUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ctrl.Service.RemoveUser(username); err != nil {
+	if err := ctrl.auditedService(r).RemoveUser(username); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
 	}
@@ -111,7 +111,7 @@ func (ctrl *APIController) NewPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newPassword, err := ctrl.Service.NewPassword(username)
+	newPassword, err := ctrl.auditedService(r).NewPassword(username)
 	if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 		return
@@ -152,7 +152,7 @@ func (ctrl *APIController) ChangePassword(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := ctrl.Service.UpdatePassword(username, reqChanges.OldPass, reqChanges.NewPass); err != nil {
+	if err := ctrl.auditedService(r).UpdatePassword(username, reqChanges.OldPass, reqChanges.NewPass); err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusBadRequest, err)
 		return
 	}
@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const alertRoutingRuleCollection = "alert_routing_rules"
+
+func (md *MongoDatabase) AddAlertRoutingRule(rule model.AlertRoutingRule) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertRoutingRuleCollection).
+		InsertOne(context.TODO(), rule)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+func (md *MongoDatabase) UpdateAlertRoutingRule(rule model.AlertRoutingRule) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertRoutingRuleCollection).
+		ReplaceOne(context.TODO(), bson.M{"_id": rule.ID}, rule)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.MatchedCount != 1 {
+		return utils.NewError(utils.ErrNotFound, "DB ERROR")
+	}
+
+	return nil
+}
+
+func (md *MongoDatabase) GetAlertRoutingRules() ([]model.AlertRoutingRule, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertRoutingRuleCollection).
+		Find(context.TODO(), bson.D{})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	rules := make([]model.AlertRoutingRule, 0)
+
+	if err := cur.All(context.TODO(), &rules); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return rules, nil
+}
+
+func (md *MongoDatabase) GetAlertRoutingRule(id primitive.ObjectID) (*model.AlertRoutingRule, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertRoutingRuleCollection).
+		FindOne(context.TODO(), bson.M{"_id": id})
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, utils.NewError(utils.ErrNotFound, "DB ERROR")
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var out model.AlertRoutingRule
+
+	if err := res.Decode(&out); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return &out, nil
+}
+
+func (md *MongoDatabase) DeleteAlertRoutingRule(id primitive.ObjectID) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertRoutingRuleCollection).
+		DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.DeletedCount != 1 {
+		return utils.NewError(utils.ErrNotFound, "DB ERROR")
+	}
+
+	return nil
+}
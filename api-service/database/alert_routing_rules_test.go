@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestAlertRoutingRules() {
+	defer m.db.Client.Database(m.dbname).Collection(alertRoutingRuleCollection).DeleteMany(context.TODO(), bson.M{})
+
+	rule := model.AlertRoutingRule{
+		ID:              utils.Str2oid("000000000000000000000001"),
+		Name:            "licensing",
+		Enabled:         true,
+		Severities:      []string{},
+		Categories:      []string{model.AlertCategoryLicense},
+		Codes:           []string{},
+		Technologies:    []string{},
+		Hostnames:       []string{},
+		Locations:       []string{},
+		Environments:    []string{},
+		Notifiers:       []string{"licensing"},
+		EmailRecipients: []string{},
+	}
+
+	m.T().Run("should_insert", func(t *testing.T) {
+		err := m.db.AddAlertRoutingRule(rule)
+		require.NoError(t, err)
+
+		actual, err := m.db.GetAlertRoutingRule(rule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, rule, *actual)
+	})
+
+	m.T().Run("should_update", func(t *testing.T) {
+		rule.Locations = []string{"Italy"}
+
+		err := m.db.UpdateAlertRoutingRule(rule)
+		require.NoError(t, err)
+
+		actual, err := m.db.GetAlertRoutingRules()
+		require.NoError(t, err)
+		assert.Equal(t, []model.AlertRoutingRule{rule}, actual)
+	})
+
+	m.T().Run("should_delete", func(t *testing.T) {
+		err := m.db.DeleteAlertRoutingRule(rule.ID)
+		require.NoError(t, err)
+
+		_, err = m.db.GetAlertRoutingRule(rule.ID)
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+
+		err = m.db.DeleteAlertRoutingRule(rule.ID)
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
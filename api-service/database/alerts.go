@@ -24,6 +24,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/amreo/mu"
 	"github.com/ercole-io/ercole/v2/api-service/dto"
@@ -41,143 +42,7 @@ func (md *MongoDatabase) SearchAlerts(alertFilter alert_filter.Alert) (*dto.Pagi
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			mu.APOptionalStage(alertFilter.Status != "", mu.APMatch(bson.M{
-				"alertStatus": alertFilter.Status,
-			})),
-			mu.APOptionalStage(alertFilter.Severity != "", mu.APMatch(bson.M{
-				"alertSeverity": alertFilter.Severity,
-			})),
-			mu.APOptionalStage(alertFilter.Category != "", mu.APMatch(bson.M{
-				"alertCategory": alertFilter.Category,
-			})),
-			mu.APOptionalStage(alertFilter.Code != "", mu.APMatch(bson.M{
-				"alertCode": alertFilter.Code,
-			})),
-			mu.APOptionalStage(alertFilter.Description != "", mu.APMatch(bson.M{
-				"description": primitive.Regex{Pattern: regexp.QuoteMeta(alertFilter.Description), Options: "i"},
-			})),
-			mu.APOptionalStage(alertFilter.Hostname != "", mu.APMatch(bson.M{
-				"otherInfo.hostname": primitive.Regex{Pattern: regexp.QuoteMeta(alertFilter.Hostname), Options: "i"},
-			})),
-			mu.APMatch(bson.M{
-				"date": bson.M{
-					"$gte": alertFilter.From,
-					"$lt":  alertFilter.To,
-				},
-			}),
-			mu.APSearchFilterStage([]interface{}{
-				"$description",
-				"$alertCode",
-				"$alertSeverity",
-				"$alertCategory",
-				"$otherInfo.Hostname",
-				"$otherInfo.Dbname",
-				"$otherInfo.Features",
-			}, alertFilter.Keywords),
-			mu.APSet(bson.M{
-				"hostname": "$otherInfo.hostname",
-			}),
-
-			mu.APOptionalStage(len(alertFilter.Location) > 0 || len(alertFilter.Environment) > 0 || alertFilter.OlderThan != utils.MAX_TIME,
-				mu.APLookupPipeline(
-					"hosts",
-					bson.M{"hn": "$otherInfo.hostname"},
-					"host",
-					mu.MAPipeline(
-						mu.APMatch(bson.M{
-							"$expr":       bson.M{"$eq": bson.A{"$hostname", "$$hn"}},
-							"dismissedAt": nil,
-							"archived":    false,
-						}),
-						mu.APProject(bson.M{
-							"_id":         0,
-							"location":    1,
-							"environment": 1,
-							"createdAt":   1,
-						}),
-					),
-				),
-			),
-			mu.APOptionalStage(len(alertFilter.Location) > 0 || len(alertFilter.Environment) > 0 || alertFilter.OlderThan != utils.MAX_TIME,
-				bson.M{
-					"$unwind": bson.M{"path": "$host", "preserveNullAndEmptyArrays": true},
-				},
-			),
-			mu.APOptionalStage(len(alertFilter.Location) > 0,
-				mu.APMatch(bson.M{
-					"$or": bson.A{
-						bson.M{"host.location": bson.M{"$in": strings.Split(alertFilter.Location, ",")}},
-						bson.M{"host": bson.M{"$exists": false}},
-					},
-				}),
-			),
-			mu.APOptionalStage(len(alertFilter.Environment) > 0,
-				mu.APMatch(bson.M{
-					"$or": bson.A{
-						bson.M{"host.environment": alertFilter.Environment},
-						bson.M{"host": bson.M{"$exists": false}},
-					},
-				})),
-			mu.APOptionalStage(alertFilter.OlderThan != utils.MAX_TIME, bson.A{
-				mu.APMatch(bson.M{
-					"$or": bson.A{
-						bson.M{"host.createdAt": mu.QOLessThanOrEqual(alertFilter.OlderThan)},
-						bson.M{"host": bson.M{"$exists": false}},
-					},
-				}),
-			}),
-			mu.APUnset("host"),
-
-			mu.APOptionalStage(alertFilter.Mode == "aggregated-code-severity", mu.MAPipeline(
-				mu.APGroup(bson.M{
-					"_id": bson.M{
-						"code":     "$alertCode",
-						"severity": "$alertSeverity",
-						"category": "$alertCategory",
-					},
-					"count": mu.APOSum(1),
-					"oldestAlert": bson.M{
-						"$min": "$date",
-					},
-					"affectedHosts": bson.M{
-						"$addToSet": "$hostname",
-					},
-				}),
-				mu.APProject(bson.M{
-					"_id":           false,
-					"category":      "$_id.category",
-					"code":          "$_id.code",
-					"severity":      "$_id.severity",
-					"count":         true,
-					"affectedHosts": mu.APOSize("$affectedHosts"),
-					"oldestAlert":   true,
-				}),
-			)),
-
-			mu.APOptionalStage(alertFilter.Mode == "aggregated-category-severity", mu.MAPipeline(
-				mu.APGroup(bson.M{
-					"_id": bson.M{
-						"severity": "$alertSeverity",
-						"category": "$alertCategory",
-					},
-					"count": mu.APOSum(1),
-					"oldestAlert": bson.M{
-						"$min": "$date",
-					},
-					"affectedHosts": bson.M{
-						"$addToSet": "$hostname",
-					},
-				}),
-				mu.APProject(bson.M{
-					"_id":           false,
-					"category":      "$_id.category",
-					"severity":      "$_id.severity",
-					"count":         true,
-					"affectedHosts": mu.APOSize("$affectedHosts"),
-					"oldestAlert":   true,
-				}),
-			)),
-
+			searchAlertsSteps(alertFilter),
 			mu.APOptionalSortingStage(alertFilter.SortBy, alertFilter.SortDesc),
 
 			bson.M{
@@ -224,6 +89,153 @@ func (md *MongoDatabase) SearchAlerts(alertFilter alert_filter.Alert) (*dto.Pagi
 	return dto.ToPagination(nil, int(count), alertFilter.Filter.Limit, alertFilter.Filter.Page), nil
 }
 
+// ListAlerts return the page of the alerts requested by the query
+func (md *MongoDatabase) ListAlerts(alertFilter alert_filter.Alert, q dto.ListQuery) (*dto.ListPage, error) {
+	return md.aggregateList(alertsCollection, searchAlertsSteps(alertFilter), q)
+}
+
+// searchAlertsSteps return the steps that filter the alerts, and aggregate them if so requested by the mode
+func searchAlertsSteps(alertFilter alert_filter.Alert) interface{} {
+	return mu.MAPipeline(
+		mu.APOptionalStage(alertFilter.Status != "", mu.APMatch(bson.M{
+			"alertStatus": alertFilter.Status,
+		})),
+		mu.APOptionalStage(alertFilter.Severity != "", mu.APMatch(bson.M{
+			"alertSeverity": alertFilter.Severity,
+		})),
+		mu.APOptionalStage(alertFilter.Category != "", mu.APMatch(bson.M{
+			"alertCategory": alertFilter.Category,
+		})),
+		mu.APOptionalStage(alertFilter.Code != "", mu.APMatch(bson.M{
+			"alertCode": alertFilter.Code,
+		})),
+		mu.APOptionalStage(alertFilter.Description != "", mu.APMatch(bson.M{
+			"description": primitive.Regex{Pattern: regexp.QuoteMeta(alertFilter.Description), Options: "i"},
+		})),
+		mu.APOptionalStage(alertFilter.Hostname != "", mu.APMatch(bson.M{
+			"otherInfo.hostname": primitive.Regex{Pattern: regexp.QuoteMeta(alertFilter.Hostname), Options: "i"},
+		})),
+		mu.APMatch(bson.M{
+			"date": bson.M{
+				"$gte": alertFilter.From,
+				"$lt":  alertFilter.To,
+			},
+		}),
+		mu.APSearchFilterStage([]interface{}{
+			"$description",
+			"$alertCode",
+			"$alertSeverity",
+			"$alertCategory",
+			"$otherInfo.Hostname",
+			"$otherInfo.Dbname",
+			"$otherInfo.Features",
+		}, alertFilter.Keywords),
+		mu.APSet(bson.M{
+			"hostname": "$otherInfo.hostname",
+		}),
+
+		mu.APOptionalStage(len(alertFilter.Location) > 0 || len(alertFilter.Environment) > 0 || alertFilter.OlderThan != utils.MAX_TIME,
+			mu.APLookupPipeline(
+				"hosts",
+				bson.M{"hn": "$otherInfo.hostname"},
+				"host",
+				mu.MAPipeline(
+					mu.APMatch(bson.M{
+						"$expr":       bson.M{"$eq": bson.A{"$hostname", "$$hn"}},
+						"dismissedAt": nil,
+						"archived":    false,
+					}),
+					mu.APProject(bson.M{
+						"_id":         0,
+						"location":    1,
+						"environment": 1,
+						"createdAt":   1,
+					}),
+				),
+			),
+		),
+		mu.APOptionalStage(len(alertFilter.Location) > 0 || len(alertFilter.Environment) > 0 || alertFilter.OlderThan != utils.MAX_TIME,
+			bson.M{
+				"$unwind": bson.M{"path": "$host", "preserveNullAndEmptyArrays": true},
+			},
+		),
+		mu.APOptionalStage(len(alertFilter.Location) > 0,
+			mu.APMatch(bson.M{
+				"$or": bson.A{
+					bson.M{"host.location": bson.M{"$in": strings.Split(alertFilter.Location, ",")}},
+					bson.M{"host": bson.M{"$exists": false}},
+				},
+			}),
+		),
+		mu.APOptionalStage(len(alertFilter.Environment) > 0,
+			mu.APMatch(bson.M{
+				"$or": bson.A{
+					bson.M{"host.environment": alertFilter.Environment},
+					bson.M{"host": bson.M{"$exists": false}},
+				},
+			})),
+		mu.APOptionalStage(alertFilter.OlderThan != utils.MAX_TIME, bson.A{
+			mu.APMatch(bson.M{
+				"$or": bson.A{
+					bson.M{"host.createdAt": mu.QOLessThanOrEqual(alertFilter.OlderThan)},
+					bson.M{"host": bson.M{"$exists": false}},
+				},
+			}),
+		}),
+		mu.APUnset("host"),
+
+		mu.APOptionalStage(alertFilter.Mode == "aggregated-code-severity", mu.MAPipeline(
+			mu.APGroup(bson.M{
+				"_id": bson.M{
+					"code":     "$alertCode",
+					"severity": "$alertSeverity",
+					"category": "$alertCategory",
+				},
+				"count": mu.APOSum(1),
+				"oldestAlert": bson.M{
+					"$min": "$date",
+				},
+				"affectedHosts": bson.M{
+					"$addToSet": "$hostname",
+				},
+			}),
+			mu.APProject(bson.M{
+				"_id":           false,
+				"category":      "$_id.category",
+				"code":          "$_id.code",
+				"severity":      "$_id.severity",
+				"count":         true,
+				"affectedHosts": mu.APOSize("$affectedHosts"),
+				"oldestAlert":   true,
+			}),
+		)),
+
+		mu.APOptionalStage(alertFilter.Mode == "aggregated-category-severity", mu.MAPipeline(
+			mu.APGroup(bson.M{
+				"_id": bson.M{
+					"severity": "$alertSeverity",
+					"category": "$alertCategory",
+				},
+				"count": mu.APOSum(1),
+				"oldestAlert": bson.M{
+					"$min": "$date",
+				},
+				"affectedHosts": bson.M{
+					"$addToSet": "$hostname",
+				},
+			}),
+			mu.APProject(bson.M{
+				"_id":           false,
+				"category":      "$_id.category",
+				"severity":      "$_id.severity",
+				"count":         true,
+				"affectedHosts": mu.APOSize("$affectedHosts"),
+				"oldestAlert":   true,
+			}),
+		)),
+	)
+}
+
 func (md *MongoDatabase) GetAlerts(location, environment, status string, from, to, olderThan time.Time) ([]map[string]interface{}, error) {
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).Aggregate(
 		context.TODO(),
@@ -309,50 +321,7 @@ func (md *MongoDatabase) GetAlerts(location, environment, status string, from, t
 	return out, nil
 }
 
-func (md *MongoDatabase) CountAlertsNODATA(alertsFilter dto.AlertsFilter) (int64, error) {
-	data, err := bson.Marshal(alertsFilter)
-	if err != nil {
-		return 0, err
-	}
-
-	var filter map[string]interface{}
-
-	err = bson.Unmarshal(data, &filter)
-	if err != nil {
-		return 0, err
-	}
-
-	if len(filter) < 1 {
-		return 0, nil //Do not acknowledge anything
-	}
-
-	if v, ok := filter["otherInfo"]; ok {
-		if v, ok := v.(map[string]interface{}); ok {
-			for k, vv := range v {
-				filter["otherInfo."+k] = vv
-			}
-		}
-
-		delete(filter, "otherInfo")
-	}
-
-	ids := alertsFilter.IDs
-	if len(ids) >= 1 {
-		filter["_id"] = bson.M{"$in": ids}
-	}
-
-	filter["alertCode"] = bson.M{"$eq": "NO_DATA"}
-
-	count, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).
-		CountDocuments(context.TODO(), filter)
-	if err != nil {
-		return 0, utils.NewError(err, "DB ERROR")
-	}
-
-	return count, nil
-}
-
-func (md *MongoDatabase) UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string) error {
+func (md *MongoDatabase) UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string, entry model.AlertHistoryEntry) error {
 	data, err := bson.Marshal(alertsFilter)
 	if err != nil {
 		return err
@@ -398,9 +367,12 @@ func (md *MongoDatabase) UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newSt
 		UpdateMany(
 			context.TODO(),
 			filter,
-			bson.M{"$set": bson.M{
-				"alertStatus": newStatus,
-			}},
+			bson.M{
+				"$set": bson.M{
+					"alertStatus": newStatus,
+				},
+				"$push": bson.M{"history": entry},
+			},
 		)
 	if err != nil {
 		return utils.NewError(err, "DB ERROR")
@@ -424,3 +396,72 @@ func (md *MongoDatabase) RemoveAlertsNODATA(alertsFilter dto.AlertsFilter) error
 
 	return nil
 }
+
+// GetAlert return the alert with the specified id
+func (md *MongoDatabase) GetAlert(id primitive.ObjectID) (*model.Alert, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).
+		FindOne(context.TODO(), bson.M{"_id": id})
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, utils.NewError(utils.ErrAlertNotFound, "DB ERROR")
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var out model.Alert
+
+	if err := res.Decode(&out); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return &out, nil
+}
+
+// UpdateAlertStatus change the status of the alert and append entry to its history
+func (md *MongoDatabase) UpdateAlertStatus(id primitive.ObjectID, status string, snoozedUntil *time.Time, entry model.AlertHistoryEntry) error {
+	update := bson.M{
+		"$set":  bson.M{"alertStatus": status},
+		"$push": bson.M{"history": entry},
+	}
+
+	if snoozedUntil != nil {
+		update["$set"] = bson.M{"alertStatus": status, "snoozedUntil": snoozedUntil}
+	} else {
+		update["$unset"] = bson.M{"snoozedUntil": ""}
+	}
+
+	return md.updateAlert(id, update)
+}
+
+// UpdateAlertAssignee change the assignee of the alert and append entry to its history
+func (md *MongoDatabase) UpdateAlertAssignee(id primitive.ObjectID, assignee string, entry model.AlertHistoryEntry) error {
+	update := bson.M{
+		"$push": bson.M{"history": entry},
+	}
+
+	if assignee != "" {
+		update["$set"] = bson.M{"assignee": assignee}
+	} else {
+		update["$unset"] = bson.M{"assignee": ""}
+	}
+
+	return md.updateAlert(id, update)
+}
+
+// AddAlertHistoryEntry append entry to the history of the alert
+func (md *MongoDatabase) AddAlertHistoryEntry(id primitive.ObjectID, entry model.AlertHistoryEntry) error {
+	return md.updateAlert(id, bson.M{"$push": bson.M{"history": entry}})
+}
+
+func (md *MongoDatabase) updateAlert(id primitive.ObjectID, update bson.M) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).
+		UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.MatchedCount != 1 {
+		return utils.NewError(utils.ErrAlertNotFound, "DB ERROR")
+	}
+
+	return nil
+}
@@ -596,6 +596,13 @@ func (m *MongodbSuite) TestGetAlerts() {
 }
 
 func (m *MongodbSuite) TestUpdateAlertsStatus() {
+	entry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-06T10:00:00Z"),
+		Username: "mario",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusAck,
+	}
+
 	a := model.Alert{
 
 		AlertAffectedTechnology: nil,
@@ -622,7 +629,8 @@ func (m *MongodbSuite) TestUpdateAlertsStatus() {
 		OtherInfo: map[string]interface{}{
 			"hostname": "myhost",
 		},
-		ID: utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+		ID:      utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+		History: []model.AlertHistoryEntry{entry},
 	}
 
 	b := model.Alert{
@@ -651,7 +659,8 @@ func (m *MongodbSuite) TestUpdateAlertsStatus() {
 			"hostname": "myhost",
 			"dbname":   "pippo",
 		},
-		ID: utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"),
+		ID:      utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"),
+		History: []model.AlertHistoryEntry{entry},
 	}
 
 	testCases := []struct {
@@ -716,7 +725,7 @@ func (m *MongodbSuite) TestUpdateAlertsStatus() {
 		_, _ = m.db.Client.Database(m.dbname).Collection(alertsCollection).
 			InsertMany(context.TODO(), alerts)
 
-		actErr := m.db.UpdateAlertsStatus(tc.filter, model.AlertStatusAck)
+		actErr := m.db.UpdateAlertsStatus(tc.filter, model.AlertStatusAck, entry)
 		if tc.expErr == nil {
 			assert.Nil(m.T(), actErr)
 		} else {
@@ -827,3 +836,86 @@ func (m *MongodbSuite) TestRemoveAlertsNODATA() {
 		clean()
 	}
 }
+
+func (m *MongodbSuite) TestAlertLifecycle() {
+	defer m.db.Client.Database(m.dbname).Collection(alertsCollection).DeleteMany(context.TODO(), bson.M{})
+
+	a := model.Alert{
+		AlertAffectedTechnology: nil,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               model.AlertCodeNewServer,
+		AlertSeverity:           model.AlertSeverityInfo,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    utils.P("2019-11-05T18:02:03Z"),
+		Description:             "The server 'foobar' was added to ercole",
+		OtherInfo: map[string]interface{}{
+			"hostname": "foobar",
+		},
+		ID: utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+	}
+
+	_, err := m.db.Client.Database(m.dbname).Collection(alertsCollection).InsertOne(context.TODO(), a)
+	require.NoError(m.T(), err)
+
+	m.T().Run("Get not existing alert", func(t *testing.T) {
+		_, err := m.db.GetAlert(utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"))
+		assert.ErrorIs(t, err, utils.ErrAlertNotFound)
+	})
+
+	snoozedUntil := utils.P("2019-11-06T18:02:03Z")
+	snoozeEntry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-05T19:02:03Z"),
+		Username: "pippo",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusSnoozed,
+	}
+	assignEntry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-05T20:02:03Z"),
+		Username: "pippo",
+		Action:   model.AlertActionAssign,
+		Assignee: "pluto",
+	}
+	commentEntry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-05T21:02:03Z"),
+		Username: "pluto",
+		Action:   model.AlertActionComment,
+		Comment:  "I'm on it",
+	}
+
+	m.T().Run("Snooze, assign and comment", func(t *testing.T) {
+		require.NoError(t, m.db.UpdateAlertStatus(a.ID, model.AlertStatusSnoozed, &snoozedUntil, snoozeEntry))
+		require.NoError(t, m.db.UpdateAlertAssignee(a.ID, "pluto", assignEntry))
+		require.NoError(t, m.db.AddAlertHistoryEntry(a.ID, commentEntry))
+
+		expected := a
+		expected.AlertStatus = model.AlertStatusSnoozed
+		expected.SnoozedUntil = &snoozedUntil
+		expected.Assignee = "pluto"
+		expected.History = []model.AlertHistoryEntry{snoozeEntry, assignEntry, commentEntry}
+
+		actual, err := m.db.GetAlert(a.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &expected, actual)
+	})
+
+	m.T().Run("Resolve", func(t *testing.T) {
+		resolveEntry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T22:02:03Z"),
+			Username: "pluto",
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusResolved,
+		}
+		require.NoError(t, m.db.UpdateAlertStatus(a.ID, model.AlertStatusResolved, nil, resolveEntry))
+
+		actual, err := m.db.GetAlert(a.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.AlertStatusResolved, actual.AlertStatus)
+		assert.Nil(t, actual.SnoozedUntil)
+		assert.Len(t, actual.History, 4)
+	})
+
+	m.T().Run("Update not existing alert", func(t *testing.T) {
+		err := m.db.AddAlertHistoryEntry(utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"), commentEntry)
+		assert.ErrorIs(t, err, utils.ErrAlertNotFound)
+	})
+}
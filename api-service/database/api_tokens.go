@@ -0,0 +1,120 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const apiTokenCollection = "api_tokens"
+
+func (md *MongoDatabase) InsertAPIToken(token model.APIToken) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(apiTokenCollection).
+		InsertOne(context.TODO(), token)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+func (md *MongoDatabase) GetAPITokens() ([]model.APIToken, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(apiTokenCollection).
+		Find(context.TODO(), bson.D{})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	tokens := make([]model.APIToken, 0)
+
+	if err := cur.All(context.TODO(), &tokens); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return tokens, nil
+}
+
+func (md *MongoDatabase) GetAPIToken(id primitive.ObjectID) (*model.APIToken, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(apiTokenCollection).
+		FindOne(context.TODO(), bson.M{"_id": id})
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, utils.NewError(utils.ErrNotFound, "DB ERROR")
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var out model.APIToken
+
+	if err := res.Decode(&out); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return &out, nil
+}
+
+func (md *MongoDatabase) GetAPITokenByHash(hash string) (*model.APIToken, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(apiTokenCollection).
+		FindOne(context.TODO(), bson.M{"tokenHash": hash})
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, utils.NewError(utils.ErrNotFound, "DB ERROR")
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var out model.APIToken
+
+	if err := res.Decode(&out); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return &out, nil
+}
+
+func (md *MongoDatabase) UpdateAPITokenLastUsed(id primitive.ObjectID, lastUsedAt time.Time) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(apiTokenCollection).
+		UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"lastUsedAt": lastUsedAt}})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.MatchedCount != 1 {
+		return utils.NewError(utils.ErrNotFound, "DB ERROR")
+	}
+
+	return nil
+}
+
+func (md *MongoDatabase) DeleteAPIToken(id primitive.ObjectID) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(apiTokenCollection).
+		DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.DeletedCount != 1 {
+		return utils.NewError(utils.ErrNotFound, "DB ERROR")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestAPITokens() {
+	defer m.db.Client.Database(m.dbname).Collection(apiTokenCollection).DeleteMany(context.TODO(), bson.M{})
+
+	token := model.APIToken{
+		ID:          utils.Str2oid("000000000000000000000001"),
+		Name:        "licenses-job",
+		Description: "",
+		TokenHash:   "abcdef",
+		Groups:      []string{"licensing"},
+		CreatedAt:   utils.P("2023-05-01T10:00:00Z").UTC(),
+		CreatedBy:   "ercole",
+	}
+
+	m.T().Run("should_insert", func(t *testing.T) {
+		err := m.db.InsertAPIToken(token)
+		require.NoError(t, err)
+
+		actual, err := m.db.GetAPITokenByHash(token.TokenHash)
+		require.NoError(t, err)
+		assert.Equal(t, token, *actual)
+	})
+
+	m.T().Run("should_update_last_used", func(t *testing.T) {
+		lastUsedAt := utils.P("2023-05-02T10:00:00Z").UTC()
+		token.LastUsedAt = &lastUsedAt
+
+		err := m.db.UpdateAPITokenLastUsed(token.ID, lastUsedAt)
+		require.NoError(t, err)
+
+		actual, err := m.db.GetAPITokens()
+		require.NoError(t, err)
+		assert.Equal(t, []model.APIToken{token}, actual)
+	})
+
+	m.T().Run("should_delete", func(t *testing.T) {
+		err := m.db.DeleteAPIToken(token.ID)
+		require.NoError(t, err)
+
+		_, err = m.db.GetAPITokenByHash(token.TokenHash)
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+
+		err = m.db.DeleteAPIToken(token.ID)
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
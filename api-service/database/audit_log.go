@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// auditLogCollection is append-only: the entries are never updated nor deleted by ercole
+const auditLogCollection = "audit_log"
+
+func (md *MongoDatabase) InsertAuditLogEntry(entry model.AuditLogEntry) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(auditLogCollection).
+		InsertOne(context.TODO(), entry)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+func (md *MongoDatabase) SearchAuditLog(filter dto.AuditLogFilter) ([]model.AuditLogEntry, error) {
+	query := bson.M{
+		"date": bson.M{
+			"$gte": filter.From,
+			"$lt":  filter.To,
+		},
+	}
+
+	if filter.Username != "" {
+		query["username"] = filter.Username
+	}
+
+	if filter.EntityType != "" {
+		query["entityType"] = filter.EntityType
+	}
+
+	if filter.EntityID != "" {
+		query["entityID"] = filter.EntityID
+	}
+
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}})
+
+	if filter.PageNumber >= 0 && filter.PageSize > 0 {
+		opts.SetSkip(int64(filter.PageNumber * filter.PageSize)).SetLimit(int64(filter.PageSize))
+	}
+
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(auditLogCollection).
+		Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	entries := make([]model.AuditLogEntry, 0)
+
+	if err := cur.All(context.TODO(), &entries); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return entries, nil
+}
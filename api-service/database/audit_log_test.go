@@ -0,0 +1,89 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestAuditLog() {
+	defer m.db.Client.Database(m.dbname).Collection(auditLogCollection).DeleteMany(context.TODO(), bson.M{})
+
+	first := model.AuditLogEntry{
+		ID:         utils.Str2oid("000000000000000000000001"),
+		Date:       utils.P("2023-05-01T10:00:00Z").UTC(),
+		AuditActor: model.AuditActor{Username: "mario", SourceIP: "10.0.0.1", Method: "POST", Endpoint: "/admin/groups"},
+		EntityType: model.AuditEntityGroup,
+		EntityID:   "dba",
+		Action:     model.AuditActionCreate,
+		Changes:    []model.AuditChange{{Field: "name", After: `"dba"`}},
+	}
+	second := model.AuditLogEntry{
+		ID:         utils.Str2oid("000000000000000000000002"),
+		Date:       utils.P("2023-05-02T10:00:00Z").UTC(),
+		AuditActor: model.AuditActor{Username: "luigi", SourceIP: "10.0.0.2", Method: "DELETE", Endpoint: "/admin/groups/{name}"},
+		EntityType: model.AuditEntityGroup,
+		EntityID:   "dba",
+		Action:     model.AuditActionDelete,
+		Changes:    []model.AuditChange{{Field: "name", Before: `"dba"`}},
+	}
+
+	require.NoError(m.T(), m.db.InsertAuditLogEntry(first))
+	require.NoError(m.T(), m.db.InsertAuditLogEntry(second))
+
+	all := dto.AuditLogFilter{From: utils.MIN_TIME, To: utils.MAX_TIME, PageNumber: -1, PageSize: -1}
+
+	m.T().Run("should_return_newest_first", func(t *testing.T) {
+		actual, err := m.db.SearchAuditLog(all)
+		require.NoError(t, err)
+		assert.Equal(t, []model.AuditLogEntry{second, first}, actual)
+	})
+
+	m.T().Run("should_filter", func(t *testing.T) {
+		filter := all
+		filter.Username = "mario"
+
+		actual, err := m.db.SearchAuditLog(filter)
+		require.NoError(t, err)
+		assert.Equal(t, []model.AuditLogEntry{first}, actual)
+
+		filter = all
+		filter.To = utils.P("2023-05-02T00:00:00Z")
+
+		actual, err = m.db.SearchAuditLog(filter)
+		require.NoError(t, err)
+		assert.Equal(t, []model.AuditLogEntry{first}, actual)
+	})
+
+	m.T().Run("should_paginate", func(t *testing.T) {
+		filter := all
+		filter.PageNumber = 1
+		filter.PageSize = 1
+
+		actual, err := m.db.SearchAuditLog(filter)
+		require.NoError(t, err)
+		assert.Equal(t, []model.AuditLogEntry{first}, actual)
+	})
+}
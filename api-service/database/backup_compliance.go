@@ -0,0 +1,61 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/amreo/mu"
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const backupComplianceCollection = "backup_compliance"
+
+// ListBackupCompliance return the evaluations of the Oracle backups matching the filter, sorted by hostname and database
+func (md *MongoDatabase) ListBackupCompliance(filter dto.BackupComplianceFilter) ([]model.BackupCompliance, error) {
+	compliant := bson.M{}
+	if filter.Compliant != nil {
+		compliant["compliant"] = *filter.Compliant
+	}
+
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(backupComplianceCollection).Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			mu.APOptionalStage(filter.Hostname != "", mu.APMatch(bson.M{"hostname": filter.Hostname})),
+			mu.APOptionalStage(filter.Policy != "", mu.APMatch(bson.M{"policy": filter.Policy})),
+			mu.APOptionalStage(filter.Violation != "", mu.APMatch(bson.M{"violations.code": filter.Violation})),
+			mu.APOptionalStage(filter.Compliant != nil, mu.APMatch(compliant)),
+			mu.APSort(bson.D{
+				{Key: "hostname", Value: 1},
+				{Key: "databaseName", Value: 1},
+			}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	compliance := make([]model.BackupCompliance, 0)
+	if err := cur.All(context.TODO(), &compliance); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return compliance, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/amreo/mu"
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const capacityForecastsCollection = "capacity_forecasts"
+
+// ListCapacityForecasts return the capacity forecasts matching the filter, without their samples,
+// sorted by hostname, kind, database and name
+func (md *MongoDatabase) ListCapacityForecasts(filter dto.CapacityForecastFilter) ([]model.CapacityForecast, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(capacityForecastsCollection).Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			mu.APOptionalStage(filter.Hostname != "", mu.APMatch(bson.M{"hostname": filter.Hostname})),
+			mu.APOptionalStage(filter.Kind != "", mu.APMatch(bson.M{"kind": filter.Kind})),
+			mu.APOptionalStage(filter.DatabaseName != "", mu.APMatch(bson.M{"databaseName": filter.DatabaseName})),
+			mu.APOptionalStage(filter.WithinDays >= 0, mu.APMatch(bson.M{"daysToFull": bson.M{"$lte": filter.WithinDays}})),
+			mu.APUnset("samples"),
+			mu.APSort(bson.D{
+				{Key: "hostname", Value: 1},
+				{Key: "kind", Value: 1},
+				{Key: "databaseName", Value: 1},
+				{Key: "name", Value: 1},
+			}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	forecasts := make([]model.CapacityForecast, 0)
+	if err := cur.All(context.TODO(), &forecasts); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return forecasts, nil
+}
@@ -28,70 +28,15 @@ import (
 
 // SearchClusters search clusters
 func (md *MongoDatabase) SearchClusters(mode string, keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) ([]dto.Cluster, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	//Find the matching hostdata
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			FilterByOldnessSteps(olderThan),
-			FilterByLocationAndEnvironmentSteps(location, environment),
-			mu.APUnwind("$clusters"),
-			mu.APProject(bson.M{
-				"hostname":    1,
-				"environment": 1,
-				"location":    1,
-				"createdAt":   1,
-				"cluster":     "$clusters",
-			}),
-			mu.APSearchFilterStage([]interface{}{"$cluster.name"}, keywords),
-			mu.APProject(bson.M{
-				"_id":                         true,
-				"environment":                 true,
-				"location":                    true,
-				"hostnameAgentVirtualization": "$hostname",
-				"hostname":                    true,
-				"fetchEndpoint":               "$cluster.fetchEndpoint",
-				"name":                        "$cluster.name",
-				"type":                        "$cluster.type",
-				"cpu":                         "$cluster.cpu",
-				"sockets":                     "$cluster.sockets",
-				"vms":                         "$cluster.vms",
-				"virtualizationNodes":         mu.APOSetUnion(mu.APOMap("$cluster.vms", "vm", mu.APOConcat("$$vm.virtualizationNode", mu.APOIfNull(mu.APOConcat(" - ", "$$vm.physicalServerModelName"), "")))),
-				"physicalServerModelNames":    mu.APOSetUnion(mu.APOMap("$cluster.vms", "vm", "$$vm.physicalServerModelName")),
-				"vmsCount":                    mu.APOSize("$cluster.vms"),
-			}),
-			mu.APLookupPipeline("hosts", bson.M{
-				"vms": "$vms",
-			}, "vmsErcoleAgentCount", mu.MAPipeline(
-				FilterByOldnessSteps(olderThan),
-				mu.APProject(bson.M{
-					"hostname": 1,
-				}),
-				mu.APMatch(mu.QOExpr(mu.APOAny("$$vms", "vm", mu.APOEqual("$$vm.hostname", "$hostname")))),
-			)),
-			mu.APSet(bson.M{
-				"vmsErcoleAgentCount": mu.APOSize("$vmsErcoleAgentCount"),
-			}),
-			mu.APOptionalStage(mode == "full", mu.APProject(bson.M{
-				"_id":                         true,
-				"createdAt":                   1,
-				"environment":                 true,
-				"location":                    true,
-				"hostnameAgentVirtualization": true,
-				"hostname":                    true,
-				"fetchEndpoint":               true,
-				"name":                        true,
-				"type":                        true,
-				"cpu":                         true,
-				"sockets":                     true,
-				"virtualizationNodes":         true,
-				"physicalServerModelNames":    true,
-				"vmsCount":                    true,
-				"vmsErcoleAgentCount":         true,
-			})),
-			mu.APOptionalStage(mode == "clusternames", mu.APProject(bson.M{
-				"_id":  false,
-				"name": true,
-			})),
+			searchClustersSteps(mode, keywords, location, environment, olderThan),
 			mu.APOptionalSortingStage(sortBy, sortDesc),
 			mu.APOptionalPagingStage(page, pageSize),
 		),
@@ -108,12 +53,92 @@ func (md *MongoDatabase) SearchClusters(mode string, keywords []string, sortBy s
 	return clusters, nil
 }
 
+// ListClusters return the page of the clusters requested by the query
+func (md *MongoDatabase) ListClusters(mode string, keywords []string, location string, environment string, olderThan time.Time, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchClustersSteps(mode, keywords, location, environment, olderThan), q, "_id", "name")
+}
+
+// searchClustersSteps return the steps that filter the clusters and shape them as requested by the mode, without sorting and paging them
+func searchClustersSteps(mode string, keywords []string, location string, environment string, olderThan time.Time) interface{} {
+	return mu.MAPipeline(
+		FilterByOldnessSteps(olderThan),
+		FilterByLocationAndEnvironmentSteps(location, environment),
+		mu.APUnwind("$clusters"),
+		mu.APProject(bson.M{
+			"hostname":    1,
+			"environment": 1,
+			"location":    1,
+			"createdAt":   1,
+			"cluster":     "$clusters",
+		}),
+		mu.APSearchFilterStage([]interface{}{"$cluster.name"}, keywords),
+		mu.APProject(bson.M{
+			"_id":                         true,
+			"environment":                 true,
+			"location":                    true,
+			"hostnameAgentVirtualization": "$hostname",
+			"hostname":                    true,
+			"fetchEndpoint":               "$cluster.fetchEndpoint",
+			"name":                        "$cluster.name",
+			"type":                        "$cluster.type",
+			"cpu":                         "$cluster.cpu",
+			"sockets":                     "$cluster.sockets",
+			"vms":                         "$cluster.vms",
+			"virtualizationNodes":         mu.APOSetUnion(mu.APOMap("$cluster.vms", "vm", mu.APOConcat("$$vm.virtualizationNode", mu.APOIfNull(mu.APOConcat(" - ", "$$vm.physicalServerModelName"), "")))),
+			"physicalServerModelNames":    mu.APOSetUnion(mu.APOMap("$cluster.vms", "vm", "$$vm.physicalServerModelName")),
+			"vmsCount":                    mu.APOSize("$cluster.vms"),
+		}),
+		mu.APLookupPipeline("hosts", bson.M{
+			"vms": "$vms",
+		}, "vmsErcoleAgentCount", mu.MAPipeline(
+			FilterByOldnessSteps(olderThan),
+			mu.APProject(bson.M{
+				"hostname": 1,
+			}),
+			mu.APMatch(mu.QOExpr(mu.APOAny("$$vms", "vm", mu.APOEqual("$$vm.hostname", "$hostname")))),
+		)),
+		mu.APSet(bson.M{
+			"vmsErcoleAgentCount": mu.APOSize("$vmsErcoleAgentCount"),
+		}),
+		mu.APOptionalStage(mode == "full", mu.APProject(bson.M{
+			"_id":                         true,
+			"createdAt":                   1,
+			"environment":                 true,
+			"location":                    true,
+			"hostnameAgentVirtualization": true,
+			"hostname":                    true,
+			"fetchEndpoint":               true,
+			"name":                        true,
+			"type":                        true,
+			"cpu":                         true,
+			"sockets":                     true,
+			"virtualizationNodes":         true,
+			"physicalServerModelNames":    true,
+			"vmsCount":                    true,
+			"vmsErcoleAgentCount":         true,
+		})),
+		mu.APOptionalStage(mode == "clusternames", mu.APProject(bson.M{
+			"_id":  false,
+			"name": true,
+		})),
+	)
+}
+
 func (md *MongoDatabase) GetClusters(filter dto.GlobalFilter) ([]dto.Cluster, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
 			FilterByOldnessSteps(filter.OlderThan),
 			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			FilterByHostMetadataSteps(filter.HostMetadataFilter),
 			mu.APUnwind("$clusters"),
 			mu.APProject(bson.M{
 				"hostname":    1,
@@ -185,6 +210,10 @@ func (md *MongoDatabase) GetClusters(filter dto.GlobalFilter) ([]dto.Cluster, er
 
 // GetCluster fetch all information about a cluster in the database
 func (md *MongoDatabase) GetCluster(clusterName string, olderThan time.Time) (*dto.Cluster, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
@@ -16,12 +16,14 @@
 package database
 
 import (
+	"context"
 	"math"
 	"strings"
 	"time"
 
 	"github.com/amreo/mu"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/ercole-io/ercole/v2/api-service/dto"
 	"github.com/ercole-io/ercole/v2/utils"
@@ -39,6 +41,18 @@ func FilterByLocationAndEnvironmentSteps(location string, environment string) in
 	}
 }
 
+// FilterByHostMetadataSteps return the steps required to filter the data by the metadata of their hosts
+func FilterByHostMetadataSteps(filter dto.HostMetadataFilter) interface{} {
+	return mu.APOptionalStage(!filter.IsEmpty(), mu.MAPipeline(
+		mu.APLookupSimple(hostMetadataCollection, "hostname", "hostname", "hostMetadata"),
+		mu.APSet(bson.M{
+			"hostMetadata": mu.APOArrayElemAt("$hostMetadata", 0),
+		}),
+		mu.APMatch(hostMetadataQuery("hostMetadata.", filter)),
+		mu.APUnset("hostMetadata"),
+	))
+}
+
 func FilterByOldnessSteps(olderThan time.Time) bson.A {
 	return mu.MAPipeline(
 		mu.APOptionalStage(olderThan == utils.MAX_TIME, mu.APMatch(bson.M{
@@ -73,6 +87,11 @@ func FilterByOldnessSteps(olderThan time.Time) bson.A {
 					"newRoot": "$hostdata",
 				},
 			},
+
+			// the hostdata stored as delta are read as rebuilt by rebuildHostDataHistory
+			mu.APLookupSimple(hostDataHistoryCacheCollection, "_id", "_id", "rebuilt"),
+			mu.APReplaceWith(mu.APOIfNull(mu.APOArrayElemAt("$rebuilt", 0), "$$ROOT")),
+			mu.APUnset("rebuilt", "cachedAt"),
 		}),
 	)
 }
@@ -117,6 +136,194 @@ func PagingMetadataStage(page int, size int) interface{} {
 	return mu.APOptionalPagingStage(0, math.MaxInt64)
 }
 
+// listCursorField is the field of the items that holds their sort keys, to build the cursor of the next page
+const listCursorField = "_cursor"
+
+// listSort return the sort of the query, followed by the keys that identify the items to break the ties
+func listSort(q dto.ListQuery, keys []string) bson.D {
+	sort := bson.D{}
+	sorted := make(map[string]bool)
+
+	for _, f := range q.Sort {
+		direction := 1
+		if f.Desc {
+			direction = -1
+		}
+
+		sort = append(sort, bson.E{Key: f.Field, Value: direction})
+		sorted[f.Field] = true
+	}
+
+	for _, key := range keys {
+		if !sorted[key] {
+			sort = append(sort, bson.E{Key: key, Value: 1})
+		}
+	}
+
+	return sort
+}
+
+// listSortKey return the expression of the value of the field used by the sort, that treats the missing fields as null
+func listSortKey(field string) interface{} {
+	return mu.APOIfNull("$"+field, nil)
+}
+
+// listAfterStage return the stage that match the items that follow, in the sort, the item with the sort keys
+func listAfterStage(sort bson.D, after bson.A) interface{} {
+	or := bson.A{}
+
+	for i, f := range sort {
+		and := bson.A{}
+
+		for j := 0; j < i; j++ {
+			and = append(and, mu.APOEqual(listSortKey(sort[j].Key), bson.M{"$literal": after[j]}))
+		}
+
+		operator := "$gt"
+		if f.Value == -1 {
+			operator = "$lt"
+		}
+
+		and = append(and, bson.M{operator: bson.A{listSortKey(f.Key), bson.M{"$literal": after[i]}}})
+		or = append(or, bson.M{"$and": and})
+	}
+
+	return mu.APMatch(mu.QOExpr(bson.M{"$or": or}))
+}
+
+// ListQuerySteps return the steps that match the documents after the sort keys of the cursor, sort them,
+// limit them to the page plus one, to know if there is a next page, and project them as requested by the query.
+// keys are the fields that identify the documents and break the ties of the sort.
+// The sort keys of every document are in listCursorField
+func ListQuerySteps(q dto.ListQuery, after bson.A, keys []string) (bson.A, error) {
+	sort := listSort(q, keys)
+
+	if after != nil && len(after) != len(sort) {
+		return nil, utils.NewErrorf("%w: invalid cursor", utils.ErrInvalidListQuery)
+	}
+
+	sortKeys := bson.A{}
+	for _, f := range sort {
+		sortKeys = append(sortKeys, listSortKey(f.Key))
+	}
+
+	projection := bson.M{}
+	for _, field := range q.Fields {
+		projection[field] = 1
+	}
+
+	if len(projection) > 0 {
+		if _, ok := projection["_id"]; !ok {
+			projection["_id"] = 0
+		}
+
+		projection[listCursorField] = 1
+	}
+
+	var afterStage interface{}
+	if after != nil {
+		afterStage = listAfterStage(sort, after)
+	}
+
+	return mu.MAPipeline(
+		afterStage,
+		bson.M{"$sort": sort},
+		mu.APLimit(q.Limit+1),
+		mu.APSet(bson.M{listCursorField: sortKeys}),
+		mu.APOptionalStage(len(projection) > 0, mu.APProject(projection)),
+	), nil
+}
+
+// aggregateList run the pipeline on the collection and return the page of the documents requested by the query.
+// keys are the fields that identify the documents, _id if none. The documents are counted only for the first page
+func (md *MongoDatabase) aggregateList(collection string, pipeline interface{}, q dto.ListQuery, keys ...string) (*dto.ListPage, error) {
+	if len(keys) == 0 {
+		keys = []string{"_id"}
+	}
+
+	after, err := q.After()
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := ListQuerySteps(q, after, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(collection).Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			pipeline,
+			steps,
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	items := make([]map[string]interface{}, 0)
+	if err := cur.All(context.TODO(), &items); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	page := dto.ListPage{Items: items}
+
+	if len(items) > q.Limit {
+		page.Items = items[:q.Limit]
+
+		sortKeys, ok := page.Items[q.Limit-1][listCursorField].(primitive.A)
+		if !ok {
+			return nil, utils.NewErrorf("%w: missing sort keys", utils.ErrInvalidListQuery)
+		}
+
+		page.NextCursor = q.CursorAfter(bson.A(sortKeys))
+	}
+
+	for _, item := range page.Items {
+		delete(item, listCursorField)
+	}
+
+	if after == nil {
+		total, err := md.countList(collection, pipeline)
+		if err != nil {
+			return nil, err
+		}
+
+		page.Total = &total
+	}
+
+	return &page, nil
+}
+
+// countList return the number of the documents returned by the pipeline on the collection
+func (md *MongoDatabase) countList(collection string, pipeline interface{}) (int, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(collection).Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			pipeline,
+			mu.APCount("total"),
+		),
+	)
+	if err != nil {
+		return 0, utils.NewError(err, "DB ERROR")
+	}
+
+	var res []struct {
+		Total int `bson:"total"`
+	}
+
+	if err := cur.All(context.TODO(), &res); err != nil {
+		return 0, utils.NewError(err, "Decode ERROR")
+	}
+
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	return res[0].Total, nil
+}
+
 func FindByHostname(hostname string) bson.A {
 	return mu.MAPipeline(mu.APOptionalStage(hostname != "", mu.APMatch(bson.M{"hostname": hostname})))
 }
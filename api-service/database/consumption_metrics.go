@@ -0,0 +1,50 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetOracleDatabaseConsumptionMetrics return the consumption metrics of the databases of the hosts since the date
+func (md *MongoDatabase) GetOracleDatabaseConsumptionMetrics(hostnames []string, since time.Time) ([]model.ConsumptionMetric, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("consumption_metrics").Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			mu.APMatch(bson.M{
+				"meta.hostname":     bson.M{"$in": hostnames},
+				"meta.databaseName": bson.M{"$exists": true},
+				"timestamp":         bson.M{"$gte": since},
+			}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	var items = make([]model.ConsumptionMetric, 0)
+	if err := cur.All(context.TODO(), &items); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return items, nil
+}
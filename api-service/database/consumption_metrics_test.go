@@ -0,0 +1,47 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestGetOracleDatabaseConsumptionMetrics() {
+	defer m.db.Client.Database(m.dbname).Collection("consumption_metrics").DeleteMany(context.TODO(), bson.M{})
+
+	cpu := 2.0
+	metrics := []interface{}{
+		model.ConsumptionMetric{Timestamp: utils.P("2019-12-01T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "db1", DatabaseName: "ERCOLE"}, CpuAvg: &cpu, Samples: 1},
+		model.ConsumptionMetric{Timestamp: utils.P("2019-11-01T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "db1", DatabaseName: "ERCOLE"}, CpuAvg: &cpu, Samples: 1},
+		model.ConsumptionMetric{Timestamp: utils.P("2019-12-01T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "db1"}, CpuAvg: &cpu, Samples: 1},
+		model.ConsumptionMetric{Timestamp: utils.P("2019-12-01T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "db2", DatabaseName: "FOOBAR"}, CpuAvg: &cpu, Samples: 1},
+	}
+
+	_, err := m.db.Client.Database(m.dbname).Collection("consumption_metrics").InsertMany(context.TODO(), metrics)
+	m.Require().NoError(err)
+
+	actual, err := m.db.GetOracleDatabaseConsumptionMetrics([]string{"db1"}, utils.P("2019-11-15T00:00:00Z"))
+	m.Require().NoError(err)
+
+	m.Require().Len(actual, 1)
+	m.Assert().Equal("ERCOLE", actual[0].Meta.DatabaseName)
+	m.Assert().Equal(2.0, *actual[0].CpuAvg)
+}
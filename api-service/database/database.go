@@ -44,6 +44,8 @@ type MongoDatabaseInterface interface {
 	ChangeConfig(config config.Configuration) error
 	// SearchHosts search hosts
 	SearchHosts(mode string, filters dto.SearchHostsFilters) ([]map[string]interface{}, error)
+	// ListHosts return a page of hosts using cursor pagination, multi-field sort and field projection
+	ListHosts(mode string, filters dto.SearchHostsFilters, q dto.ListQuery) (*dto.ListPage, error)
 	GetHostDataSummaries(filters dto.SearchHostsFilters) ([]dto.HostDataSummary, error)
 	// GetHost fetch all informations about a host in the database
 	GetHost(hostname string, olderThan time.Time, raw bool) (*dto.HostData, error)
@@ -51,10 +53,14 @@ type MongoDatabaseInterface interface {
 	GetHostDatas(olderThan time.Time) ([]model.HostDataBE, error)
 	// SearchAlerts search alerts
 	SearchAlerts(alertFilter alert_filter.Alert) (*dto.Pagination, error)
+	// ListAlerts return a page of alerts using cursor pagination, multi-field sort and field projection
+	ListAlerts(alertFilter alert_filter.Alert, q dto.ListQuery) (*dto.ListPage, error)
 	// GetAlerts get alerts
 	GetAlerts(location, environment, status string, from, to, olderThan time.Time) ([]map[string]interface{}, error)
 	// SearchClusters search clusters
 	SearchClusters(mode string, keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) ([]dto.Cluster, error)
+	// ListClusters return a page of clusters using cursor pagination, multi-field sort and field projection
+	ListClusters(mode string, keywords []string, location string, environment string, olderThan time.Time, q dto.ListQuery) (*dto.ListPage, error)
 	GetClusters(filter dto.GlobalFilter) ([]dto.Cluster, error)
 	// GetCluster fetch all information about a cluster in the database
 	GetCluster(clusterName string, olderThan time.Time) (*dto.Cluster, error)
@@ -65,8 +71,16 @@ type MongoDatabaseInterface interface {
 	SearchOraclePdbSegmentAdvisors(sortBy string, sortDesc bool, location string, environment string, olderThan time.Time) ([]dto.OracleDatabaseSegmentAdvisor, error)
 	// SearchOracleDatabasePatchAdvisors search patch advisors
 	SearchOracleDatabasePatchAdvisors(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, windowTime time.Time, location string, environment string, olderThan time.Time, status string) (*dto.PatchAdvisorResponse, error)
+	// GetOraclePatchCatalogue return the Oracle patch releases and the support of the Oracle versions
+	GetOraclePatchCatalogue() (*model.OraclePatchCatalogue, error)
+	// ReplaceOraclePatchReleases replace all the Oracle patch releases of the catalogue with the new ones
+	ReplaceOraclePatchReleases(releases []model.OraclePatchRelease) error
+	// ReplaceOracleVersionSupport replace the support of all the Oracle versions of the catalogue with the new ones
+	ReplaceOracleVersionSupport(versions []model.OracleVersionSupport) error
 	// SearchOracleDatabases search databases
-	SearchOracleDatabases(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.OracleDatabaseResponse, error)
+	SearchOracleDatabases(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.OracleDatabaseResponse, error)
+	// ListOracleDatabases return a page of databases using cursor pagination, multi-field sort and field projection
+	ListOracleDatabases(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error)
 	// SearchOracleDatabaseUsedLicenses search consumed licenses
 	SearchOracleDatabaseUsedLicenses(hostname string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.OracleDatabaseUsedLicenseSearchResponse, error)
 
@@ -94,8 +108,11 @@ type MongoDatabaseInterface interface {
 	GetTopReclaimableOracleDatabaseStats(location string, limit int, olderThan time.Time) ([]interface{}, error)
 	// GetOracleDatabasePatchStatusStats return a array containing the number of databases per patch status
 	GetOracleDatabasePatchStatusStats(location string, windowTime time.Time, olderThan time.Time) ([]interface{}, error)
-	// GetTopWorkloadOracleDatabaseStats return a array containing top databases by workload
-	GetTopWorkloadOracleDatabaseStats(location string, limit int, olderThan time.Time) ([]interface{}, error)
+	// GetTopWorkloadOracleDatabaseStats return a array containing top databases by workload,
+	// averaging the consumption metrics since the date
+	GetTopWorkloadOracleDatabaseStats(location string, limit int, olderThan, since time.Time) ([]interface{}, error)
+	// GetOracleDatabaseConsumptionMetrics return the consumption metrics of the databases of the hosts since the date
+	GetOracleDatabaseConsumptionMetrics(hostnames []string, since time.Time) ([]model.ConsumptionMetric, error)
 	// GetOracleDatabaseDataguardStatusStats return a array containing the number of databases per dataguard status
 	GetOracleDatabaseDataguardStatusStats(location string, environment string, olderThan time.Time) ([]interface{}, error)
 	// GetOracleDatabaseRACStatusStats return a array containing the number of databases per RAC status
@@ -154,10 +171,8 @@ type MongoDatabaseInterface interface {
 
 	// ReplaceHostData adds a new hostdata to the database
 	ReplaceHostData(hostData model.HostDataBE) error
-	// UpdateAlertsStatus change the status of the specified alerts
-	UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string) error
-	// CountAlertsNODATA gets alert with alertCode equals to "NO_DATA"
-	CountAlertsNODATA(alertsFilter dto.AlertsFilter) (int64, error)
+	// UpdateAlertsStatus change the status of the specified alerts and append entry to their history
+	UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string, entry model.AlertHistoryEntry) error
 	// DismissHost dismiss the specified host
 	DismissHost(hostname string) error
 	// GetHostMinValidCreatedAtDate get the host's minimun valid CreatedAt date
@@ -168,7 +183,19 @@ type MongoDatabaseInterface interface {
 	GetListDismissedHostsByRangeDates(from time.Time, to time.Time) ([]string, error)
 	// RemoveAlertsNODATA delete all alerts with alertCode equals to "NO_DATA"
 	RemoveAlertsNODATA(alertsFilter dto.AlertsFilter) error
-
+	// GetAlert return the alert with the specified id
+	GetAlert(id primitive.ObjectID) (*model.Alert, error)
+	// UpdateAlertStatus change the status of the alert and append entry to its history
+	UpdateAlertStatus(id primitive.ObjectID, status string, snoozedUntil *time.Time, entry model.AlertHistoryEntry) error
+	// UpdateAlertAssignee change the assignee of the alert and append entry to its history
+	UpdateAlertAssignee(id primitive.ObjectID, assignee string, entry model.AlertHistoryEntry) error
+	// AddAlertHistoryEntry append entry to the history of the alert
+	AddAlertHistoryEntry(id primitive.ObjectID, entry model.AlertHistoryEntry) error
+
+	// GetHostLocation return the location of the current host
+	GetHostLocation(hostname string) (string, error)
+	// ListHostnamesByLocations return the hostnames of the current hosts in the locations, comma separated
+	ListHostnamesByLocations(location string) ([]string, error)
 	// FindHostData find the current hostdata with a certain hostname
 	FindHostData(hostname string) (model.HostDataBE, error)
 	// ExistHostdata return true if the host specified by hostname exist, otherwise false
@@ -183,6 +210,7 @@ type MongoDatabaseInterface interface {
 	// MYSQL
 
 	SearchMySQLInstances(filter dto.GlobalFilter) ([]dto.MySQLInstance, error)
+	ListMySQLInstances(filter dto.GlobalFilter, q dto.ListQuery) (*dto.ListPage, error)
 	//GetMySQLUsedLicenses return MySQL used licenses.
 	// Only ENTERPRISE MySQL db are considered as licenses
 	GetMySQLUsedLicenses(hostname string, filter dto.GlobalFilter) ([]dto.MySQLUsedLicense, error)
@@ -199,7 +227,8 @@ type MongoDatabaseInterface interface {
 
 	GetSqlServerDatabaseLicenseTypes() ([]model.SqlServerDatabaseLicenseType, error)
 	InsertSqlServerDatabaseLicenseType(licenseType model.SqlServerDatabaseLicenseType) error
-	SearchSqlServerInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.SqlServerInstanceResponse, error)
+	SearchSqlServerInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.SqlServerInstanceResponse, error)
+	ListSqlServerInstances(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error)
 	SearchSqlServerDatabaseUsedLicenses(hostname string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.SqlServerDatabaseUsedLicenseSearchResponse, error)
 	UpdateSqlServerLicenseIgnoredField(hostname string, instancename string, ignored bool, ignoredComment string) error
 
@@ -210,10 +239,68 @@ type MongoDatabaseInterface interface {
 	UpdateSqlServerDatabaseContract(contract model.SqlServerDatabaseContract) error
 
 	// POSTGRESQL
-	SearchPostgreSqlInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.PostgreSqlInstanceResponse, error)
+	SearchPostgreSqlInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.PostgreSqlInstanceResponse, error)
+	ListPostgreSqlInstances(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error)
 
 	// MONGODB
-	SearchMongoDBInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.MongoDBInstanceResponse, error)
+	SearchMongoDBInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.MongoDBInstanceResponse, error)
+	ListMongoDBInstances(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error)
+
+	// ALERT ROUTING RULES
+	AddAlertRoutingRule(rule model.AlertRoutingRule) error
+	UpdateAlertRoutingRule(rule model.AlertRoutingRule) error
+	GetAlertRoutingRules() ([]model.AlertRoutingRule, error)
+	GetAlertRoutingRule(id primitive.ObjectID) (*model.AlertRoutingRule, error)
+	DeleteAlertRoutingRule(id primitive.ObjectID) error
+
+	// API TOKENS
+	InsertAPIToken(token model.APIToken) error
+	GetAPITokens() ([]model.APIToken, error)
+	GetAPIToken(id primitive.ObjectID) (*model.APIToken, error)
+	GetAPITokenByHash(hash string) (*model.APIToken, error)
+	UpdateAPITokenLastUsed(id primitive.ObjectID, lastUsedAt time.Time) error
+	DeleteAPIToken(id primitive.ObjectID) error
+
+	// AUDIT LOG
+	InsertAuditLogEntry(entry model.AuditLogEntry) error
+	// SearchAuditLog return the audit log entries matching the filter, from the newest
+	SearchAuditLog(filter dto.AuditLogFilter) ([]model.AuditLogEntry, error)
+
+	// HOST IDENTITIES
+	// ListHostIdentities return the identities of the hosts, sorted by hostname
+	ListHostIdentities() ([]model.HostIdentity, error)
+	// FindHostIdentity return the identity of the host with the canonical hostname
+	FindHostIdentity(hostname string) (*model.HostIdentity, error)
+	// FindHostIdentityByAlias return the identity of the host that has name as alias
+	FindHostIdentityByAlias(name string) (*model.HostIdentity, error)
+	// SaveHostIdentity insert the identity of the host, or replace it if it already exists
+	SaveHostIdentity(identity model.HostIdentity) error
+	DeleteHostIdentity(id primitive.ObjectID) error
+	// MergeHost move the hostdata, the alerts and the contracts of source to target and save the identity of target,
+	// deleting the one of source, in a single transaction
+	MergeHost(source, target string, identity model.HostIdentity, sourceIdentityID *primitive.ObjectID) error
+
+	// HOST METADATA
+	// ListHostMetadata return the metadata of the hosts matching the filter, sorted by hostname
+	ListHostMetadata(filter dto.HostMetadataFilter) ([]model.HostMetadata, error)
+	// FindHostMetadata return the metadata of the host, or empty metadata if it hasn't any
+	FindHostMetadata(hostname string) (*model.HostMetadata, error)
+	// UpdateHostMetadata save the metadata of the host edited by the users, leaving the CMDB record as it is
+	UpdateHostMetadata(metadata model.HostMetadata) error
+
+	// CAPACITY FORECASTS
+	// ListCapacityForecasts return the capacity forecasts matching the filter, without their samples
+	ListCapacityForecasts(filter dto.CapacityForecastFilter) ([]model.CapacityForecast, error)
+
+	// BACKUP COMPLIANCE
+	// ListBackupCompliance return the evaluations of the Oracle backups matching the filter
+	ListBackupCompliance(filter dto.BackupComplianceFilter) ([]model.BackupCompliance, error)
+
+	// METRICS
+	// GetHostsMetrics return the current hosts with their technologies
+	GetHostsMetrics() ([]dto.HostMetrics, error)
+	// GetOpenAlertsCount return the number of the open alerts by code and severity
+	GetOpenAlertsCount() ([]dto.AlertsCount, error)
 
 	// ROLES
 	GetRole(name string) (*model.Role, error)
@@ -17,9 +17,10 @@ package database
 import (
 	"context"
 
+	"github.com/amreo/mu"
+
 	"github.com/ercole-io/ercole/v2/api-service/dto"
 	"github.com/ercole-io/ercole/v2/model"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 const exadataCollection = "exadatas"
@@ -30,9 +31,10 @@ func (md *MongoDatabase) ListExadataInstances(f dto.GlobalFilter) ([]model.Oracl
 	result := make([]model.OracleExadataInstance, 0)
 
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(exadataCollection).Aggregate(ctx,
-		bson.A{
+		mu.MAPipeline(
 			Filter(f),
-		})
+			FilterByHostMetadataSteps(f.HostMetadataFilter),
+		))
 	if err != nil {
 		return nil, err
 	}
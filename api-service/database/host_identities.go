@@ -0,0 +1,235 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const hostIdentitiesCollection = "host_identities"
+
+// illegalOperationErrorCode is returned by MongoDB when transactions are used on a standalone server
+const illegalOperationErrorCode = 20
+
+// ListHostIdentities return the identities of the hosts, sorted by hostname
+func (md *MongoDatabase) ListHostIdentities() ([]model.HostIdentity, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostIdentitiesCollection).
+		Find(context.TODO(), bson.D{}, options.Find().SetSort(bson.D{{Key: "hostname", Value: 1}}))
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	identities := make([]model.HostIdentity, 0)
+	if err := cur.All(context.TODO(), &identities); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return identities, nil
+}
+
+// FindHostIdentity return the identity of the host with the canonical hostname
+func (md *MongoDatabase) FindHostIdentity(hostname string) (*model.HostIdentity, error) {
+	return md.findHostIdentity(bson.M{"hostname": hostname})
+}
+
+// FindHostIdentityByAlias return the identity of the host that has name as alias
+func (md *MongoDatabase) FindHostIdentityByAlias(name string) (*model.HostIdentity, error) {
+	return md.findHostIdentity(bson.M{"aliases": name})
+}
+
+func (md *MongoDatabase) findHostIdentity(filter bson.M) (*model.HostIdentity, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostIdentitiesCollection).
+		FindOne(context.TODO(), filter)
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, utils.NewError(utils.ErrHostIdentityNotFound, "DB ERROR")
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var identity model.HostIdentity
+	if err := res.Decode(&identity); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return &identity, nil
+}
+
+// SaveHostIdentity insert the identity of the host, or replace it if it already exists
+func (md *MongoDatabase) SaveHostIdentity(identity model.HostIdentity) error {
+	return md.saveHostIdentity(context.TODO(), identity)
+}
+
+func (md *MongoDatabase) saveHostIdentity(ctx context.Context, identity model.HostIdentity) error {
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostIdentitiesCollection).
+		ReplaceOne(ctx, bson.M{"_id": identity.ID}, identity, options.Replace().SetUpsert(true)); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// DeleteHostIdentity delete the identity of a host
+func (md *MongoDatabase) DeleteHostIdentity(id primitive.ObjectID) error {
+	return md.deleteHostIdentity(context.TODO(), id)
+}
+
+func (md *MongoDatabase) deleteHostIdentity(ctx context.Context, id primitive.ObjectID) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostIdentitiesCollection).
+		DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.DeletedCount == 0 {
+		return utils.NewError(utils.ErrHostIdentityNotFound, "DB ERROR")
+	}
+
+	return nil
+}
+
+// MergeHost move the hostdata, the alerts and the contracts of source to target, then save the identity of target
+// and delete the one of source, if any, in a single transaction.
+// If the MongoDB deployment doesn't support transactions, the writes are done one after the other, the identities last
+func (md *MongoDatabase) MergeHost(source, target string, identity model.HostIdentity, sourceIdentityID *primitive.ObjectID) error {
+	merge := func(ctx context.Context) error {
+		if err := md.mergeHostData(ctx, source, target); err != nil {
+			return err
+		}
+
+		if err := md.replaceHostInAlerts(ctx, source, target); err != nil {
+			return err
+		}
+
+		if err := md.replaceHostInContracts(ctx, source, target); err != nil {
+			return err
+		}
+
+		if err := md.saveHostIdentity(ctx, identity); err != nil {
+			return err
+		}
+
+		if sourceIdentityID != nil {
+			return md.deleteHostIdentity(ctx, *sourceIdentityID)
+		}
+
+		return nil
+	}
+
+	session, err := md.Client.StartSession()
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, merge(sessCtx)
+	})
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == illegalOperationErrorCode {
+		return merge(context.TODO())
+	}
+
+	var advErr *utils.AdvancedError
+	if err != nil && !errors.As(err, &advErr) {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return err
+}
+
+// mergeHostData move the hostdata of source, current and archived, to target.
+// If both hosts have a current hostdata, the older one is archived
+func (md *MongoDatabase) mergeHostData(ctx context.Context, source, target string) error {
+	hosts := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts")
+
+	cur, err := hosts.Find(ctx,
+		bson.M{"hostname": bson.M{"$in": bson.A{source, target}}, "archived": false},
+		options.Find().
+			SetProjection(bson.M{"_id": 1, "createdAt": 1}).
+			SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	var current []model.HostDataBE
+	if err := cur.All(ctx, &current); err != nil {
+		return utils.NewError(err, "DECODE ERROR")
+	}
+
+	if len(current) > 1 {
+		older := make(bson.A, 0, len(current)-1)
+		for _, hostdata := range current[1:] {
+			older = append(older, hostdata.ID)
+		}
+
+		if _, err := hosts.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": older}},
+			bson.M{"$set": bson.M{"archived": true}}); err != nil {
+			return utils.NewError(err, "DB ERROR")
+		}
+	}
+
+	if _, err := hosts.UpdateMany(ctx,
+		bson.M{"hostname": source},
+		bson.M{"$set": bson.M{"hostname": target}}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// replaceHostInAlerts change the host of the alerts of source to target
+func (md *MongoDatabase) replaceHostInAlerts(ctx context.Context, source, target string) error {
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).
+		UpdateMany(ctx,
+			bson.M{"otherInfo.hostname": source},
+			bson.M{"$set": bson.M{"otherInfo.hostname": target}}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// replaceHostInContracts replace source with target in the hosts of the Oracle, MySQL and SQL Server contracts
+func (md *MongoDatabase) replaceHostInContracts(ctx context.Context, source, target string) error {
+	for _, collection := range []string{oracleDbContractsCollection, mySQLContractCollection, sqlServerDbContractsCollection} {
+		contracts := md.Client.Database(md.Config.Mongodb.DBName).Collection(collection)
+
+		if _, err := contracts.UpdateMany(ctx,
+			bson.M{"hosts": source},
+			bson.M{"$addToSet": bson.M{"hosts": target}}); err != nil {
+			return utils.NewError(err, "DB ERROR")
+		}
+
+		if _, err := contracts.UpdateMany(ctx,
+			bson.M{"hosts": source},
+			bson.M{"$pull": bson.M{"hosts": source}}); err != nil {
+			return utils.NewError(err, "DB ERROR")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestHostIdentities() {
+	defer m.db.Client.Database(m.dbname).Collection(hostIdentitiesCollection).DeleteMany(context.TODO(), bson.M{})
+
+	identity := model.HostIdentity{
+		ID:        utils.Str2oid("000000000000000000000001"),
+		Hostname:  "foobar",
+		Aliases:   []string{"foobar.example.org"},
+		CreatedAt: utils.P("2023-05-01T10:00:00Z").UTC(),
+		UpdatedAt: utils.P("2023-05-01T10:00:00Z").UTC(),
+	}
+
+	m.T().Run("should_save", func(t *testing.T) {
+		require.NoError(t, m.db.SaveHostIdentity(identity))
+
+		identity.Aliases = append(identity.Aliases, "oldfoobar")
+		require.NoError(t, m.db.SaveHostIdentity(identity))
+
+		actual, err := m.db.ListHostIdentities()
+		require.NoError(t, err)
+		assert.Equal(t, []model.HostIdentity{identity}, actual)
+	})
+
+	m.T().Run("should_find", func(t *testing.T) {
+		actual, err := m.db.FindHostIdentity("foobar")
+		require.NoError(t, err)
+		assert.Equal(t, identity, *actual)
+
+		actual, err = m.db.FindHostIdentityByAlias("oldfoobar")
+		require.NoError(t, err)
+		assert.Equal(t, identity, *actual)
+
+		_, err = m.db.FindHostIdentityByAlias("foobar")
+		assert.ErrorIs(t, err, utils.ErrHostIdentityNotFound)
+	})
+
+	m.T().Run("should_delete", func(t *testing.T) {
+		require.NoError(t, m.db.DeleteHostIdentity(identity.ID))
+
+		err := m.db.DeleteHostIdentity(identity.ID)
+		assert.ErrorIs(t, err, utils.ErrHostIdentityNotFound)
+	})
+}
+
+func (m *MongodbSuite) TestMergeHost() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+	defer m.db.Client.Database(m.dbname).Collection(alertsCollection).DeleteMany(context.TODO(), bson.M{})
+	defer m.db.Client.Database(m.dbname).Collection(oracleDbContractsCollection).DeleteMany(context.TODO(), bson.M{})
+	defer m.db.Client.Database(m.dbname).Collection(hostIdentitiesCollection).DeleteMany(context.TODO(), bson.M{})
+
+	hosts := []interface{}{
+		bson.M{"_id": utils.Str2oid("000000000000000000000001"), "hostname": "oldname", "archived": true, "createdAt": utils.P("2023-05-01T10:00:00Z")},
+		bson.M{"_id": utils.Str2oid("000000000000000000000002"), "hostname": "oldname", "archived": false, "createdAt": utils.P("2023-05-02T10:00:00Z")},
+		bson.M{"_id": utils.Str2oid("000000000000000000000003"), "hostname": "newname", "archived": false, "createdAt": utils.P("2023-05-03T10:00:00Z")},
+	}
+	_, err := m.db.Client.Database(m.dbname).Collection("hosts").InsertMany(context.TODO(), hosts)
+	require.NoError(m.T(), err)
+
+	_, err = m.db.Client.Database(m.dbname).Collection(alertsCollection).InsertOne(context.TODO(),
+		bson.M{"_id": utils.Str2oid("000000000000000000000001"), "otherInfo": bson.M{"hostname": "oldname"}})
+	require.NoError(m.T(), err)
+
+	_, err = m.db.Client.Database(m.dbname).Collection(oracleDbContractsCollection).InsertMany(context.TODO(), []interface{}{
+		bson.M{"_id": utils.Str2oid("000000000000000000000001"), "hosts": bson.A{"oldname", "other"}},
+		bson.M{"_id": utils.Str2oid("000000000000000000000002"), "hosts": bson.A{"oldname", "newname"}},
+	})
+	require.NoError(m.T(), err)
+
+	sourceIdentity := model.HostIdentity{ID: utils.Str2oid("000000000000000000000020"), Hostname: "oldname", Aliases: []string{"oldname.example.org"}}
+	require.NoError(m.T(), m.db.SaveHostIdentity(sourceIdentity))
+
+	identity := model.HostIdentity{ID: utils.Str2oid("000000000000000000000010"), Hostname: "newname", Aliases: []string{"oldname", "oldname.example.org"}}
+	require.NoError(m.T(), m.db.MergeHost("oldname", "newname", identity, &sourceIdentity.ID))
+
+	actualIdentity, err := m.db.FindHostIdentityByAlias("oldname")
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), "newname", actualIdentity.Hostname)
+
+	_, err = m.db.FindHostIdentity("oldname")
+	assert.ErrorIs(m.T(), err, utils.ErrHostIdentityNotFound)
+
+	var hostdatas []bson.M
+	cur, err := m.db.Client.Database(m.dbname).Collection("hosts").Find(context.TODO(), bson.M{})
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), cur.All(context.TODO(), &hostdatas))
+
+	require.Len(m.T(), hostdatas, 3)
+	for _, hostdata := range hostdatas {
+		assert.Equal(m.T(), "newname", hostdata["hostname"])
+		assert.Equal(m.T(), hostdata["_id"] != utils.Str2oid("000000000000000000000003"), hostdata["archived"])
+	}
+
+	var alert bson.M
+	require.NoError(m.T(), m.db.Client.Database(m.dbname).Collection(alertsCollection).
+		FindOne(context.TODO(), bson.M{}).Decode(&alert))
+	assert.Equal(m.T(), "newname", alert["otherInfo"].(bson.M)["hostname"])
+
+	var contracts []bson.M
+	cur, err = m.db.Client.Database(m.dbname).Collection(oracleDbContractsCollection).Find(context.TODO(), bson.M{})
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), cur.All(context.TODO(), &contracts))
+
+	require.Len(m.T(), contracts, 2)
+	assert.ElementsMatch(m.T(), bson.A{"other", "newname"}, contracts[0]["hosts"])
+	assert.ElementsMatch(m.T(), bson.A{"newname"}, contracts[1]["hosts"])
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const hostMetadataCollection = "host_metadata"
+
+// hostMetadataQuery return the query of the metadata matching the filter, with the fields under prefix
+func hostMetadataQuery(prefix string, filter dto.HostMetadataFilter) bson.M {
+	query := bson.M{}
+
+	fields := map[string]string{
+		"ownerTeam":           filter.OwnerTeam,
+		"businessApplication": filter.BusinessApplication,
+		"costCenter":          filter.CostCenter,
+		"criticality":         filter.Criticality,
+	}
+
+	for field, value := range fields {
+		if value != "" {
+			query[prefix+field] = bson.M{"$in": strings.Split(value, ",")}
+		}
+	}
+
+	if len(filter.Labels) > 0 {
+		query[prefix+"labels"] = bson.M{"$all": filter.Labels}
+	}
+
+	return query
+}
+
+// ListHostMetadata return the metadata of the hosts matching the filter, sorted by hostname
+func (md *MongoDatabase) ListHostMetadata(filter dto.HostMetadataFilter) ([]model.HostMetadata, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostMetadataCollection).
+		Find(context.TODO(), hostMetadataQuery("", filter), options.Find().SetSort(bson.D{{Key: "hostname", Value: 1}}))
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	metadata := make([]model.HostMetadata, 0)
+	if err := cur.All(context.TODO(), &metadata); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return metadata, nil
+}
+
+// FindHostMetadata return the metadata of the host, or empty metadata if it hasn't any
+func (md *MongoDatabase) FindHostMetadata(hostname string) (*model.HostMetadata, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostMetadataCollection).
+		FindOne(context.TODO(), bson.M{"hostname": hostname})
+	if res.Err() == mongo.ErrNoDocuments {
+		return &model.HostMetadata{Hostname: hostname, Labels: []string{}}, nil
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var metadata model.HostMetadata
+	if err := res.Decode(&metadata); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return &metadata, nil
+}
+
+// UpdateHostMetadata save the metadata of the host edited by the users, leaving the CMDB record as it is
+func (md *MongoDatabase) UpdateHostMetadata(metadata model.HostMetadata) error {
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostMetadataCollection).
+		UpdateOne(context.TODO(),
+			bson.M{"hostname": metadata.Hostname},
+			bson.M{"$set": bson.M{
+				"ownerTeam":           metadata.OwnerTeam,
+				"contactEmail":        metadata.ContactEmail,
+				"businessApplication": metadata.BusinessApplication,
+				"costCenter":          metadata.CostCenter,
+				"criticality":         metadata.Criticality,
+				"labels":              metadata.Labels,
+				"updatedAt":           metadata.UpdatedAt,
+			}},
+			options.Update().SetUpsert(true)); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestHostMetadata() {
+	defer m.db.Client.Database(m.dbname).Collection(hostMetadataCollection).DeleteMany(context.TODO(), bson.M{})
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+
+	_, err := m.db.Client.Database(m.dbname).Collection(hostMetadataCollection).InsertOne(context.TODO(), bson.M{
+		"hostname": "foobar",
+		"cmdb":     bson.M{"source": "servicenow", "hostname": "FOOBAR", "environment": "PRD"},
+	})
+	m.Require().NoError(err)
+
+	_, err = m.db.Client.Database(m.dbname).Collection("hosts").InsertMany(context.TODO(), []interface{}{
+		bson.M{"hostname": "foobar", "archived": false, "createdAt": utils.P("2023-05-01T10:00:00Z")},
+		bson.M{"hostname": "barfoo", "archived": false, "createdAt": utils.P("2023-05-01T10:00:00Z")},
+	})
+	m.Require().NoError(err)
+
+	foobar := model.HostMetadata{
+		Hostname:            "foobar",
+		OwnerTeam:           "dba",
+		ContactEmail:        "dba@example.org",
+		BusinessApplication: "billing",
+		CostCenter:          "CC42",
+		Criticality:         model.HostCriticalityHigh,
+		Labels:              []string{"pci", "eu"},
+		UpdatedAt:           utils.P("2023-05-02T10:00:00Z").UTC(),
+	}
+	barfoo := model.HostMetadata{
+		Hostname:    "barfoo",
+		OwnerTeam:   "sysadmin",
+		Criticality: model.HostCriticalityLow,
+		Labels:      []string{"eu"},
+		UpdatedAt:   utils.P("2023-05-02T10:00:00Z").UTC(),
+	}
+
+	m.T().Run("should_update_keeping_cmdb_record", func(t *testing.T) {
+		require.NoError(t, m.db.UpdateHostMetadata(foobar))
+		require.NoError(t, m.db.UpdateHostMetadata(barfoo))
+
+		actual, err := m.db.FindHostMetadata("foobar")
+		require.NoError(t, err)
+		require.NotNil(t, actual.Cmdb)
+		assert.Equal(t, "servicenow", actual.Cmdb.Source)
+
+		actual.Cmdb = nil
+		assert.Equal(t, foobar, *actual)
+	})
+
+	m.T().Run("should_return_empty_metadata", func(t *testing.T) {
+		actual, err := m.db.FindHostMetadata("unknown")
+		require.NoError(t, err)
+		assert.Equal(t, model.HostMetadata{Hostname: "unknown", Labels: []string{}}, *actual)
+	})
+
+	m.T().Run("should_list_filtered", func(t *testing.T) {
+		actual, err := m.db.ListHostMetadata(dto.HostMetadataFilter{Labels: []string{"eu"}})
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, "barfoo", actual[0].Hostname)
+
+		actual, err = m.db.ListHostMetadata(dto.HostMetadataFilter{Criticality: "HIGH,CRITICAL", Labels: []string{"eu", "pci"}})
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "foobar", actual[0].Hostname)
+	})
+
+	m.T().Run("should_filter_hosts", func(t *testing.T) {
+		filters := dto.NewSearchHostsFilters()
+		filters.OwnerTeam = "sysadmin"
+
+		actual, err := m.db.SearchHosts("hostnames", filters)
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]interface{}{{"hostname": "barfoo"}}, actual)
+	})
+}
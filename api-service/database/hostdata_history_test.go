@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestGetHostData_Delta() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+
+	snapshot := model.HostDataBE{
+		ID:        utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		Hostname:  "foobar",
+		Archived:  true,
+		CreatedAt: utils.P("2020-12-05T14:02:03Z"),
+		Info:      model.Host{MemoryTotal: 32},
+	}
+	archived := snapshot
+	archived.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d4")
+	archived.CreatedAt = utils.P("2020-12-06T14:02:03Z")
+	archived.Info.MemoryTotal = 16
+
+	delta, err := model.NewHostDataHistoryDelta(snapshot, archived, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+
+	compacted, err := model.CompactHostData(archived, *delta)
+	require.NoError(m.T(), err)
+
+	_, err = m.db.Client.Database(m.dbname).Collection("hosts").InsertMany(context.TODO(), []interface{}{snapshot, compacted})
+	require.NoError(m.T(), err)
+
+	actual, err := m.db.GetHostData("foobar", utils.P("2020-12-06T15:00:00Z"))
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), archived, *actual)
+
+	actual, err = m.db.GetHostData("foobar", utils.P("2020-12-05T15:00:00Z"))
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), snapshot, *actual)
+
+	deltas, err := m.db.Client.Database(m.dbname).Collection("hosts").
+		CountDocuments(context.TODO(), bson.M{"historyDelta": bson.M{"$ne": nil}})
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), int64(1), deltas, "the reads mustn't rewrite the compacted hostdata")
+}
+
+func (m *MongodbSuite) TestSearchHosts_DeltaChain() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+	defer m.db.Client.Database(m.dbname).Collection(hostDataHistoryCacheCollection).DeleteMany(context.TODO(), bson.M{})
+
+	snapshot := model.HostDataBE{
+		ID:          utils.Str2oid("5ef9d239a1d25d1e8703c4e3"),
+		Hostname:    "foobar",
+		Location:    "Italy",
+		Environment: "PRD",
+		Archived:    true,
+		CreatedAt:   utils.P("2020-12-05T14:02:03Z"),
+		Info:        model.Host{MemoryTotal: 32},
+	}
+
+	withMySQL := snapshot
+	withMySQL.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4e4")
+	withMySQL.CreatedAt = utils.P("2020-12-06T14:02:03Z")
+	withMySQL.Features.MySQL = &model.MySQLFeature{Instances: []model.MySQLInstance{{Name: "mysql01"}}}
+
+	lessMemory := withMySQL
+	lessMemory.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4e5")
+	lessMemory.CreatedAt = utils.P("2020-12-07T14:02:03Z")
+	lessMemory.Info.MemoryTotal = 16
+
+	first, err := model.NewHostDataHistoryDelta(snapshot, withMySQL, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+	second, err := model.NewHostDataHistoryDelta(withMySQL, lessMemory, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+
+	compactedWithMySQL, err := model.CompactHostData(withMySQL, *first)
+	require.NoError(m.T(), err)
+	compactedLessMemory, err := model.CompactHostData(lessMemory, *second)
+	require.NoError(m.T(), err)
+
+	_, err = m.db.Client.Database(m.dbname).Collection("hosts").
+		InsertMany(context.TODO(), []interface{}{snapshot, compactedWithMySQL, compactedLessMemory})
+	require.NoError(m.T(), err)
+
+	filters := dto.NewSearchHostsFilters()
+
+	for _, tc := range []struct {
+		olderThan time.Time
+		memory    float64
+	}{
+		{utils.P("2020-12-06T15:00:00Z"), 32},
+		{utils.P("2020-12-07T15:00:00Z"), 16},
+	} {
+		filters.OlderThan = tc.olderThan
+
+		out, err := m.db.SearchHosts("summary", filters)
+		require.NoError(m.T(), err)
+		require.Len(m.T(), out, 1)
+
+		raw, err := bson.Marshal(out[0])
+		require.NoError(m.T(), err)
+
+		var summary struct {
+			Info      model.Host          `bson:"info"`
+			Databases map[string][]string `bson:"databases"`
+		}
+		require.NoError(m.T(), bson.Unmarshal(raw, &summary))
+
+		assert.Equal(m.T(), tc.memory, summary.Info.MemoryTotal)
+		assert.Equal(m.T(), []string{"mysql01"}, summary.Databases[model.TechnologyOracleMySQL])
+	}
+
+	deltas, err := m.db.Client.Database(m.dbname).Collection("hosts").
+		CountDocuments(context.TODO(), bson.M{"historyDelta": bson.M{"$ne": nil}})
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), int64(2), deltas, "the reads mustn't rewrite the compacted hostdata")
+}
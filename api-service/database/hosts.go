@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/amreo/mu"
@@ -53,251 +54,269 @@ func (md *MongoDatabase) GetHostDataSummaries(filters dto.SearchHostsFilters) ([
 	return out, nil
 }
 
+// ListHosts return the page of the hosts requested by the query
+func (md *MongoDatabase) ListHosts(mode string, filters dto.SearchHostsFilters, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(filters.OlderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchHostsSteps(mode, filters), q)
+}
+
 // out must be a pointer to a slice
 func (md *MongoDatabase) getHosts(mode string, filters dto.SearchHostsFilters, out interface{}) error {
+	if err := md.rebuildHostDataHistory(filters.OlderThan); err != nil {
+		return err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			FilterByLocationAndEnvironmentSteps(filters.Location, filters.Environment),
-			FilterByOldnessSteps(filters.OlderThan),
-			mu.MAPipeline(
-				mu.APOptionalStage(filters.Hostname != "", mu.APMatch(bson.M{
-					"hostname": primitive.Regex{Pattern: regexp.QuoteMeta(filters.Hostname), Options: "i"},
-				})),
-				mu.APOptionalStage(filters.Database != "", mu.APMatch(bson.M{
-					"features.oracle.database.databases.Name": primitive.Regex{Pattern: regexp.QuoteMeta(filters.Database), Options: "i"},
-				})),
-				mu.APOptionalStage(filters.HardwareAbstractionTechnology != "", mu.APMatch(bson.M{
-					"info.hardwareAbstractionTechnology": primitive.Regex{Pattern: regexp.QuoteMeta(filters.HardwareAbstractionTechnology), Options: "i"},
-				})),
-				mu.APOptionalStage(filters.OperatingSystem != "", mu.APMatch(bson.M{
-					"info.os": primitive.Regex{Pattern: regexp.QuoteMeta(filters.OperatingSystem), Options: "i"},
-				})),
-				mu.APOptionalStage(filters.Kernel != "", mu.APMatch(bson.M{
-					"info.kernel": primitive.Regex{Pattern: regexp.QuoteMeta(filters.Kernel), Options: "i"},
-				})),
-				mu.APOptionalStage(filters.LTEMemoryTotal != -1, mu.APMatch(bson.M{
-					"info.memoryTotal": mu.QOLessThanOrEqual(filters.LTEMemoryTotal),
-				})),
-				mu.APOptionalStage(filters.GTEMemoryTotal != -1, mu.APMatch(bson.M{
-					"info.memoryTotal": bson.M{
-						"$gte": filters.GTEMemoryTotal,
-					},
-				})),
-				mu.APOptionalStage(filters.LTESwapTotal != -1, mu.APMatch(bson.M{
-					"info.swapTotal": mu.QOLessThanOrEqual(filters.LTESwapTotal),
-				})),
-				mu.APOptionalStage(filters.GTESwapTotal != -1, mu.APMatch(bson.M{
-					"info.swapTotal": bson.M{
-						"$gte": filters.GTESwapTotal,
-					},
-				})),
-				getIsMemberOfClusterFilterStep(filters.IsMemberOfCluster),
-				mu.APOptionalStage(filters.CPUModel != "", mu.APMatch(bson.M{
-					"info.cpuModel": primitive.Regex{Pattern: regexp.QuoteMeta(filters.CPUModel), Options: "i"},
-				})),
-				mu.APOptionalStage(filters.LTECPUCores != -1, mu.APMatch(bson.M{
-					"info.cpuCores": mu.QOLessThanOrEqual(filters.LTECPUCores),
-				})),
-				mu.APOptionalStage(filters.GTECPUCores != -1, mu.APMatch(bson.M{
-					"info.cpuCores": bson.M{
-						"$gte": filters.GTECPUCores,
-					},
-				})),
-				mu.APOptionalStage(filters.LTECPUThreads != -1, mu.APMatch(bson.M{
-					"info.cpuThreads": mu.QOLessThanOrEqual(filters.LTECPUThreads),
-				})),
-				mu.APOptionalStage(filters.GTECPUThreads != -1, mu.APMatch(bson.M{
-					"info.cpuThreads": bson.M{
-						"$gte": filters.GTECPUThreads,
-					},
-				})),
+			searchHostsSteps(mode, filters),
+			mu.APOptionalSortingStage(filters.SortBy, filters.SortDesc),
+			mu.APOptionalStage(mode != "mongo" && mode != "hostnames", mu.APOptionalPagingStage(filters.PageNumber, filters.PageSize)),
+		),
+	)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if err := cur.All(context.TODO(), out); err != nil {
+		return utils.NewError(err, "Decode ERROR")
+	}
+
+	return nil
+}
+
+// searchHostsSteps return the steps that filter the hosts and shape them as requested by the mode, without sorting and paging them
+func searchHostsSteps(mode string, filters dto.SearchHostsFilters) interface{} {
+	return mu.MAPipeline(
+		FilterByLocationAndEnvironmentSteps(filters.Location, filters.Environment),
+		FilterByHostMetadataSteps(filters.HostMetadataFilter),
+		FilterByOldnessSteps(filters.OlderThan),
+		mu.MAPipeline(
+			mu.APOptionalStage(filters.Hostname != "", mu.APMatch(bson.M{
+				"hostname": primitive.Regex{Pattern: regexp.QuoteMeta(filters.Hostname), Options: "i"},
+			})),
+			mu.APOptionalStage(filters.Database != "", mu.APMatch(bson.M{
+				"features.oracle.database.databases.Name": primitive.Regex{Pattern: regexp.QuoteMeta(filters.Database), Options: "i"},
+			})),
+			mu.APOptionalStage(filters.HardwareAbstractionTechnology != "", mu.APMatch(bson.M{
+				"info.hardwareAbstractionTechnology": primitive.Regex{Pattern: regexp.QuoteMeta(filters.HardwareAbstractionTechnology), Options: "i"},
+			})),
+			mu.APOptionalStage(filters.OperatingSystem != "", mu.APMatch(bson.M{
+				"info.os": primitive.Regex{Pattern: regexp.QuoteMeta(filters.OperatingSystem), Options: "i"},
+			})),
+			mu.APOptionalStage(filters.Kernel != "", mu.APMatch(bson.M{
+				"info.kernel": primitive.Regex{Pattern: regexp.QuoteMeta(filters.Kernel), Options: "i"},
+			})),
+			mu.APOptionalStage(filters.LTEMemoryTotal != -1, mu.APMatch(bson.M{
+				"info.memoryTotal": mu.QOLessThanOrEqual(filters.LTEMemoryTotal),
+			})),
+			mu.APOptionalStage(filters.GTEMemoryTotal != -1, mu.APMatch(bson.M{
+				"info.memoryTotal": bson.M{
+					"$gte": filters.GTEMemoryTotal,
+				},
+			})),
+			mu.APOptionalStage(filters.LTESwapTotal != -1, mu.APMatch(bson.M{
+				"info.swapTotal": mu.QOLessThanOrEqual(filters.LTESwapTotal),
+			})),
+			mu.APOptionalStage(filters.GTESwapTotal != -1, mu.APMatch(bson.M{
+				"info.swapTotal": bson.M{
+					"$gte": filters.GTESwapTotal,
+				},
+			})),
+			getIsMemberOfClusterFilterStep(filters.IsMemberOfCluster),
+			mu.APOptionalStage(filters.CPUModel != "", mu.APMatch(bson.M{
+				"info.cpuModel": primitive.Regex{Pattern: regexp.QuoteMeta(filters.CPUModel), Options: "i"},
+			})),
+			mu.APOptionalStage(filters.LTECPUCores != -1, mu.APMatch(bson.M{
+				"info.cpuCores": mu.QOLessThanOrEqual(filters.LTECPUCores),
+			})),
+			mu.APOptionalStage(filters.GTECPUCores != -1, mu.APMatch(bson.M{
+				"info.cpuCores": bson.M{
+					"$gte": filters.GTECPUCores,
+				},
+			})),
+			mu.APOptionalStage(filters.LTECPUThreads != -1, mu.APMatch(bson.M{
+				"info.cpuThreads": mu.QOLessThanOrEqual(filters.LTECPUThreads),
+			})),
+			mu.APOptionalStage(filters.GTECPUThreads != -1, mu.APMatch(bson.M{
+				"info.cpuThreads": bson.M{
+					"$gte": filters.GTECPUThreads,
+				},
+			})),
+		),
+		mu.APSearchFilterStage([]interface{}{
+			"$hostname",
+			"$features.oracle.database.databases.name",
+			"$features.oracle.database.databases.uniqueName",
+			"$clusters.name",
+		}, filters.Search),
+		AddAssociatedClusterNameAndVirtualizationNode(filters.OlderThan),
+		getClusterFilterStep(filters.Cluster),
+		mu.APOptionalStage(filters.VirtualizationNode != "", mu.APMatch(bson.M{
+			"virtualizationNode": primitive.Regex{Pattern: regexp.QuoteMeta(filters.VirtualizationNode), Options: "i"},
+		})),
+		mu.APOptionalStage(mode == "mongo" || mode == "hostnames", mu.APUnset("cluster", "virtualizationNode")),
+		mu.APOptionalStage(mode == "hostnames", mu.APProject(bson.M{
+			"_id":      0,
+			"hostname": 1,
+		})),
+		mu.APOptionalStage(mode != "mongo" && mode != "hostnames", mu.MAPipeline(
+			mu.APOptionalStage(mode == "lms", mu.APMatch(
+				mu.QOExpr(mu.APOGreater(mu.APOSize(mu.APOIfNull("$features.oracle.database.databases", bson.A{})), 0))),
 			),
-			mu.APSearchFilterStage([]interface{}{
-				"$hostname",
-				"$features.oracle.database.databases.name",
-				"$features.oracle.database.databases.uniqueName",
-				"$clusters.name",
-			}, filters.Search),
-			AddAssociatedClusterNameAndVirtualizationNode(filters.OlderThan),
-			getClusterFilterStep(filters.Cluster),
-			mu.APOptionalStage(filters.VirtualizationNode != "", mu.APMatch(bson.M{
-				"virtualizationNode": primitive.Regex{Pattern: regexp.QuoteMeta(filters.VirtualizationNode), Options: "i"},
+			mu.APOptionalStage(mode == "summary", mu.APProject(bson.M{
+				"_id":                     true,
+				"createdAt":               true,
+				"hostname":                true,
+				"location":                true,
+				"environment":             true,
+				"agentVersion":            true,
+				"info":                    true,
+				"consumptions":            true,
+				"clusterMembershipStatus": true,
+				"virtualizationNode":      true,
+				"cluster":                 true,
+				"databases": bson.M{
+					model.TechnologyOracleDatabase:       "$features.oracle.database.databases.name",
+					model.TechnologyMicrosoftSQLServer:   "$features.microsoft.sqlServer.instances.name",
+					model.TechnologyOracleMySQL:          "$features.mysql.instances.name",
+					model.TechnologyPostgreSQLPostgreSQL: "$features.postgresql.instances.name",
+					model.TechnologyMongoDBMongoDB:       "$features.mongodb.instances.name",
+				},
 			})),
-			mu.APOptionalStage(mode == "mongo" || mode == "hostnames", mu.APUnset("cluster", "virtualizationNode")),
-			mu.APOptionalStage(mode == "hostnames", mu.MAPipeline(
-				mu.APProject(bson.M{
-					"_id":      0,
-					"hostname": 1,
+			mu.APOptionalStage(mode == "lms", mu.MAPipeline(
+				mu.APMatch(mu.QOExpr(mu.APOGreater(mu.APOSize("$features.oracle.database.databases"), 0))),
+				mu.APUnwind("$features.oracle.database.databases"),
+				mu.APSet(bson.M{
+					"database": "$features.oracle.database.databases",
 				}),
-				mu.APOptionalSortingStage(filters.SortBy, filters.SortDesc),
-			)),
-			mu.APOptionalStage(mode != "mongo" && mode != "hostnames", mu.MAPipeline(
-				mu.APOptionalStage(mode == "lms", mu.APMatch(
-					mu.QOExpr(mu.APOGreater(mu.APOSize(mu.APOIfNull("$features.oracle.database.databases", bson.A{})), 0))),
-				),
-				mu.APOptionalStage(mode == "summary", mu.APProject(bson.M{
-					"_id":                     true,
-					"createdAt":               true,
-					"hostname":                true,
-					"location":                true,
-					"environment":             true,
-					"agentVersion":            true,
-					"info":                    true,
-					"consumptions":            true,
-					"clusterMembershipStatus": true,
-					"virtualizationNode":      true,
-					"cluster":                 true,
-					"databases": bson.M{
-						model.TechnologyOracleDatabase:       "$features.oracle.database.databases.name",
-						model.TechnologyMicrosoftSQLServer:   "$features.microsoft.sqlServer.instances.name",
-						model.TechnologyOracleMySQL:          "$features.mysql.instances.name",
-						model.TechnologyPostgreSQLPostgreSQL: "$features.postgresql.instances.name",
-						model.TechnologyMongoDBMongoDB:       "$features.mongodb.instances.name",
-					},
-				})),
-				mu.APOptionalStage(mode == "lms", mu.MAPipeline(
-					mu.APMatch(mu.QOExpr(mu.APOGreater(mu.APOSize("$features.oracle.database.databases"), 0))),
-					mu.APUnwind("$features.oracle.database.databases"),
-					mu.APSet(bson.M{
-						"database": "$features.oracle.database.databases",
-					}),
-					mu.APUnset("features"),
-					mu.APSet(bson.M{
-						"isVirtualServer": mu.APOEqual("$info.hardwareAbstraction", model.HardwareAbstractionVirtual),
-						"database.pdbs": mu.APOCond("$database.isCDB", bson.M{
-							"$concatArrays": bson.A{
-								bson.A{""},
-								mu.APOMap("$database.pdbs", "pdb", "$$pdb.name"),
-							},
-						}, bson.A{""}),
-					}),
-					mu.APUnwind("$database.pdbs"),
-					mu.APProject(bson.M{
-						// "Database":           1,
-						"createdAt":          "$createdAt",
-						"dismissedAt":        "$dismissedAt",
-						"physicalServerName": mu.APOCond("$isVirtualServer", mu.APOIfNull("$cluster", ""), "$hostname"),
-						"virtualServerName":  mu.APOCond("$isVirtualServer", "$hostname", mu.APOIfNull("$cluster", "")),
-						"virtualizationTechnology": bson.M{
-							"$switch": bson.M{
-								"branches": bson.A{
-									bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyPhysical), "then": ""},
-									bson.M{
-										"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyOvm),
-										"then": mu.APOCond(bson.M{
-											"$regexMatch": bson.M{
-												"input": "$info.cpuModel",
-												"regex": primitive.Regex{
-													Options: "i",
-													Pattern: "sparc",
-												},
+				mu.APUnset("features"),
+				mu.APSet(bson.M{
+					"isVirtualServer": mu.APOEqual("$info.hardwareAbstraction", model.HardwareAbstractionVirtual),
+					"database.pdbs": mu.APOCond("$database.isCDB", bson.M{
+						"$concatArrays": bson.A{
+							bson.A{""},
+							mu.APOMap("$database.pdbs", "pdb", "$$pdb.name"),
+						},
+					}, bson.A{""}),
+				}),
+				mu.APUnwind("$database.pdbs"),
+				mu.APProject(bson.M{
+					// "Database":           1,
+					"createdAt":          "$createdAt",
+					"dismissedAt":        "$dismissedAt",
+					"physicalServerName": mu.APOCond("$isVirtualServer", mu.APOIfNull("$cluster", ""), "$hostname"),
+					"virtualServerName":  mu.APOCond("$isVirtualServer", "$hostname", mu.APOIfNull("$cluster", "")),
+					"virtualizationTechnology": bson.M{
+						"$switch": bson.M{
+							"branches": bson.A{
+								bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyPhysical), "then": ""},
+								bson.M{
+									"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyOvm),
+									"then": mu.APOCond(bson.M{
+										"$regexMatch": bson.M{
+											"input": "$info.cpuModel",
+											"regex": primitive.Regex{
+												Options: "i",
+												Pattern: "sparc",
 											},
-										}, "OVM Server for SPARC", "OVM Server for x86"),
-									},
-									bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyVmware), "then": "VMware"},
-									bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyHyperv), "then": "Hyper-V"},
-									bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyXen), "then": "Xen"},
-									bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyHpvirt), "then": "HP Integrity Virtual Machine"},
+										},
+									}, "OVM Server for SPARC", "OVM Server for x86"),
 								},
-								"default": mu.APOConcat("$info.hardwareAbstractionTechnology"),
+								bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyVmware), "then": "VMware"},
+								bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyHyperv), "then": "Hyper-V"},
+								bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyXen), "then": "Xen"},
+								bson.M{"case": mu.APOEqual("$info.hardwareAbstractionTechnology", model.HardwareAbstractionTechnologyHpvirt), "then": "HP Integrity Virtual Machine"},
 							},
+							"default": mu.APOConcat("$info.hardwareAbstractionTechnology"),
 						},
-						"dbInstanceName":        "$database.name",
-						"pluggableDatabaseName": "$database.pdbs",
-						"environment":           "$environment",
-						"options": mu.APOJoin(mu.APOMap(
-							mu.APOFilter("$database.licenses", "lic",
-								mu.APOAnd(
-									mu.APOGreater("$$lic.count", 0),
-									mu.APONotEqual("$$lic.name", "Oracle STD"),
-									mu.APONotEqual("$$lic.name", "Oracle EXE"),
-									mu.APONotEqual("$$lic.name", "Oracle ENT"),
-									mu.APOEqual("$$lic.ignored", false),
+					},
+					"dbInstanceName":        "$database.name",
+					"pluggableDatabaseName": "$database.pdbs",
+					"environment":           "$environment",
+					"options": mu.APOJoin(mu.APOMap(
+						mu.APOFilter("$database.licenses", "lic",
+							mu.APOAnd(
+								mu.APOGreater("$$lic.count", 0),
+								mu.APONotEqual("$$lic.name", "Oracle STD"),
+								mu.APONotEqual("$$lic.name", "Oracle EXE"),
+								mu.APONotEqual("$$lic.name", "Oracle ENT"),
+								mu.APOEqual("$$lic.ignored", false),
+							),
+						),
+						"lic",
+						"$$lic.name",
+					), ", "),
+					"usedManagementPacks": mu.APOJoin(mu.APOMap(
+						mu.APOFilter("$database.licenses", "lic",
+							mu.APOAnd(
+								mu.APOGreater("$$lic.count", 0),
+								mu.APOOr(
+									mu.APOEqual("$$lic.name", "Diagnostics Pack"),
+									mu.APOEqual("$$lic.name", "Tuning Pack"),
 								),
+								mu.APOEqual("$$lic.ignored", false),
 							),
-							"lic",
-							"$$lic.name",
-						), ", "),
-						"usedManagementPacks": mu.APOJoin(mu.APOMap(
+						),
+						"lic",
+						"$$lic.name",
+					), ", "),
+					"productVersion": mu.APOArrayElemAt(mu.APOSplit("$database.version", " "), 0),
+					"productLicenseAllocated": mu.APOLet(
+						bson.M{
+							"edition": mu.APOArrayElemAt(mu.APOSplit("$database.version", " "), 1),
+						},
+						bson.M{
+							"$switch": bson.M{
+								"branches": bson.A{
+									bson.M{"case": mu.APOEqual("$$edition", "Enterprise"), "then": "EE"},
+									bson.M{"case": mu.APOEqual("$$edition", "Standard"), "then": "SE"},
+								},
+								"default": mu.APOConcat("$$edition"),
+							},
+						},
+					),
+					"licenseMetricAllocated": "processor",
+					"usingLicenseCount": mu.APOIfNull(mu.APOArrayElemAt(
+						mu.APOMap(
 							mu.APOFilter("$database.licenses", "lic",
 								mu.APOAnd(
 									mu.APOGreater("$$lic.count", 0),
 									mu.APOOr(
-										mu.APOEqual("$$lic.name", "Diagnostics Pack"),
-										mu.APOEqual("$$lic.name", "Tuning Pack"),
+										mu.APOEqual("$$lic.name", "Oracle STD"),
+										mu.APOEqual("$$lic.name", "Oracle EXE"),
+										mu.APOEqual("$$lic.name", "Oracle ENT"),
 									),
 									mu.APOEqual("$$lic.ignored", false),
 								),
 							),
 							"lic",
-							"$$lic.name",
-						), ", "),
-						"productVersion": mu.APOArrayElemAt(mu.APOSplit("$database.version", " "), 0),
-						"productLicenseAllocated": mu.APOLet(
-							bson.M{
-								"edition": mu.APOArrayElemAt(mu.APOSplit("$database.version", " "), 1),
-							},
-							bson.M{
-								"$switch": bson.M{
-									"branches": bson.A{
-										bson.M{"case": mu.APOEqual("$$edition", "Enterprise"), "then": "EE"},
-										bson.M{"case": mu.APOEqual("$$edition", "Standard"), "then": "SE"},
-									},
-									"default": mu.APOConcat("$$edition"),
-								},
-							},
+							"$$lic.count",
 						),
-						"licenseMetricAllocated": "processor",
-						"usingLicenseCount": mu.APOIfNull(mu.APOArrayElemAt(
-							mu.APOMap(
-								mu.APOFilter("$database.licenses", "lic",
-									mu.APOAnd(
-										mu.APOGreater("$$lic.count", 0),
-										mu.APOOr(
-											mu.APOEqual("$$lic.name", "Oracle STD"),
-											mu.APOEqual("$$lic.name", "Oracle EXE"),
-											mu.APOEqual("$$lic.name", "Oracle ENT"),
-										),
-										mu.APOEqual("$$lic.ignored", false),
-									),
-								),
-								"lic",
-								"$$lic.count",
-							),
-							0,
-						), 0.0),
-						"processorModel":    "$info.cpuModel",
-						"processors":        "$info.cpuSockets",
-						"coresPerProcessor": "$info.coresPerSocket",
-						"threadsPerCore": mu.APOCond(
-							mu.APOGreaterOrEqual(mu.APOIndexOfCp("$info.cpuModel", "SPARC"), 0),
-							8,
-							2,
-						),
-						"processorSpeed":  "$info.cpuFrequency",
-						"operatingSystem": "$info.os",
-					}),
-					mu.APSet(bson.M{
-						"physicalCores": mu.APOCond(mu.APOEqual("$info.cpuSockets", 0), "$coresPerProcessor", bson.M{
-							"$multiply": bson.A{"$coresPerProcessor", "$processors"},
-						}),
+						0,
+					), 0.0),
+					"processorModel":    "$info.cpuModel",
+					"processors":        "$info.cpuSockets",
+					"coresPerProcessor": "$info.coresPerSocket",
+					"threadsPerCore": mu.APOCond(
+						mu.APOGreaterOrEqual(mu.APOIndexOfCp("$info.cpuModel", "SPARC"), 0),
+						8,
+						2,
+					),
+					"processorSpeed":  "$info.cpuFrequency",
+					"operatingSystem": "$info.os",
+				}),
+				mu.APSet(bson.M{
+					"physicalCores": mu.APOCond(mu.APOEqual("$info.cpuSockets", 0), "$coresPerProcessor", bson.M{
+						"$multiply": bson.A{"$coresPerProcessor", "$processors"},
 					}),
-				)),
-				mu.APOptionalSortingStage(filters.SortBy, filters.SortDesc),
-				mu.APOptionalPagingStage(filters.PageNumber, filters.PageSize),
+				}),
 			)),
-		),
+		)),
 	)
-	if err != nil {
-		return utils.NewError(err, "DB ERROR")
-	}
-
-	if err := cur.All(context.TODO(), out); err != nil {
-		return utils.NewError(err, "Decode ERROR")
-	}
-
-	return nil
 }
 
 func getClusterFilterStep(cl *string) interface{} {
@@ -330,6 +349,10 @@ func (md *MongoDatabase) GetHost(hostname string, olderThan time.Time, raw bool)
 
 	var err error
 
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	//Get host technology
 	technology, errTech := md.getHostTechnology(hostname, olderThan)
 	if errTech != nil {
@@ -559,10 +582,18 @@ func (md *MongoDatabase) GetHostData(hostname string, olderThan time.Time) (*mod
 		return nil, utils.NewError(err, "DB ERROR")
 	}
 
+	if hostdata.HistoryDelta != nil {
+		return md.findFullHostData(hostdata.ID)
+	}
+
 	return &hostdata, nil
 }
 
 func (md *MongoDatabase) GetHostDatas(olderThan time.Time) ([]model.HostDataBE, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
 		Aggregate(
 			context.TODO(),
@@ -584,6 +615,10 @@ func (md *MongoDatabase) GetHostDatas(olderThan time.Time) ([]model.HostDataBE,
 
 // ListAllLocations list all available locations
 func (md *MongoDatabase) ListAllLocations(location string, environment string, olderThan time.Time) ([]string, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []string = make([]string, 0)
 
 	//Find the matching hostdata
@@ -616,6 +651,10 @@ func (md *MongoDatabase) ListAllLocations(location string, environment string, o
 
 // ListEnvironments list environments
 func (md *MongoDatabase) ListEnvironments(location string, environment string, olderThan time.Time) ([]string, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []string = make([]string, 0)
 
 	//Find the matching hostdata
@@ -670,6 +709,30 @@ func (md *MongoDatabase) FindHostData(hostname string) (model.HostDataBE, error)
 	return out, nil
 }
 
+// GetHostLocation return the location of the current host
+func (md *MongoDatabase) GetHostLocation(hostname string) (string, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").FindOne(context.TODO(), bson.M{
+		"dismissedAt": nil,
+		"hostname":    hostname,
+		"archived":    false,
+	}, options.FindOne().SetProjection(bson.M{"location": 1}))
+	if res.Err() == mongo.ErrNoDocuments {
+		return "", utils.ErrHostNotFound
+	} else if res.Err() != nil {
+		return "", utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var out struct {
+		Location string `bson:"location"`
+	}
+
+	if err := res.Decode(&out); err != nil {
+		return "", utils.NewError(err, "Decode ERROR")
+	}
+
+	return out.Location, nil
+}
+
 // ReplaceHostData adds a new hostdata to the database
 func (md *MongoDatabase) ReplaceHostData(hostData model.HostDataBE) error {
 	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").ReplaceOne(context.TODO(),
@@ -772,6 +835,30 @@ func (md *MongoDatabase) GetListValidHostsByRangeDates(from time.Time, to time.T
 	return hosts, nil
 }
 
+// ListHostnamesByLocations return the hostnames of the current hosts in the locations, comma separated
+func (md *MongoDatabase) ListHostnamesByLocations(location string) ([]string, error) {
+	hosts := make([]string, 0)
+
+	values, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Distinct(
+		context.TODO(),
+		"hostname",
+		bson.M{
+			"dismissedAt": nil,
+			"archived":    false,
+			"location":    bson.M{"$in": strings.Split(location, ",")},
+		},
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	for _, val := range values {
+		hosts = append(hosts, val.(string))
+	}
+
+	return hosts, nil
+}
+
 // GetListDismissedHostsByRangeDates get list of dismissed hosts by range dates
 func (md *MongoDatabase) GetListDismissedHostsByRangeDates(from time.Time, to time.Time) ([]string, error) {
 	var hosts []string = make([]string, 0)
@@ -88,6 +88,85 @@ func (m *MongodbSuite) TestSearchHosts() {
 	})
 }
 
+func (m *MongodbSuite) TestListHosts() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_03.json"))
+	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_07.json"))
+	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_08.json"))
+
+	q := dto.ListQuery{
+		Limit:  2,
+		Sort:   []dto.SortField{{Field: "hostname", Desc: true}},
+		Fields: []string{"hostname"},
+	}
+
+	var nextCursor string
+
+	m.T().Run("first_page", func(t *testing.T) {
+		out, err := m.db.ListHosts("full", dto.NewSearchHostsFilters(), q)
+		m.Require().NoError(err)
+
+		expectedOut := []map[string]interface{}{
+			{"hostname": "test-virt"},
+			{"hostname": "test-small"},
+		}
+
+		assert.JSONEq(t, utils.ToJSON(expectedOut), utils.ToJSON(out.Items))
+		require.NotNil(t, out.Total)
+		assert.Equal(t, 3, *out.Total)
+		assert.NotEmpty(t, out.NextCursor)
+
+		nextCursor = out.NextCursor
+	})
+
+	m.T().Run("last_page", func(t *testing.T) {
+		q := q
+		q.Cursor = nextCursor
+
+		out, err := m.db.ListHosts("full", dto.NewSearchHostsFilters(), q)
+		m.Require().NoError(err)
+
+		expectedOut := []map[string]interface{}{
+			{"hostname": "test-db"},
+		}
+
+		assert.JSONEq(t, utils.ToJSON(expectedOut), utils.ToJSON(out.Items))
+		assert.Nil(t, out.Total)
+		assert.Empty(t, out.NextCursor)
+	})
+
+	m.T().Run("last_page_after_removing_a_host_of_the_first_one", func(t *testing.T) {
+		_, err := m.db.Client.Database(m.dbname).Collection("hosts").DeleteOne(context.TODO(), bson.M{"hostname": "test-virt"})
+		m.Require().NoError(err)
+
+		q := q
+		q.Cursor = nextCursor
+
+		out, err := m.db.ListHosts("full", dto.NewSearchHostsFilters(), q)
+		m.Require().NoError(err)
+
+		expectedOut := []map[string]interface{}{
+			{"hostname": "test-db"},
+		}
+
+		assert.JSONEq(t, utils.ToJSON(expectedOut), utils.ToJSON(out.Items))
+		assert.Empty(t, out.NextCursor)
+	})
+
+	m.T().Run("no_hosts", func(t *testing.T) {
+		filters := dto.NewSearchHostsFilters()
+		filters.Hostname = "foobar"
+
+		out, err := m.db.ListHosts("full", filters, q)
+		m.Require().NoError(err)
+
+		assert.Empty(t, out.Items)
+		require.NotNil(t, out.Total)
+		assert.Equal(t, 0, *out.Total)
+		assert.Empty(t, out.NextCursor)
+	})
+}
+
 func (m *MongodbSuite) TestGetHostDataSummaries() {
 	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
 	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_03.json"))
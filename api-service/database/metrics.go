@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// technologyIfPresent return an expression that evaluates to [technology] if the array at path isn't empty, otherwise []
+func technologyIfPresent(technology, path string) interface{} {
+	return mu.APOCond(mu.APOGreater(mu.APOSize(mu.APOIfNull(path, bson.A{})), 0), bson.A{technology}, bson.A{})
+}
+
+// GetHostsMetrics return the current hosts with their technologies
+func (md *MongoDatabase) GetHostsMetrics() ([]dto.HostMetrics, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			FilterByOldnessSteps(utils.MAX_TIME),
+			mu.APProject(bson.M{
+				"_id":         0,
+				"hostname":    1,
+				"location":    1,
+				"environment": 1,
+				"createdAt":   1,
+				"technologies": bson.M{"$concatArrays": bson.A{
+					technologyIfPresent(model.TechnologyOracleDatabase, "$features.oracle.database.databases"),
+					technologyIfPresent(model.TechnologyOracleExadata, "$features.oracle.exadata.components"),
+					technologyIfPresent(model.TechnologyOracleMySQL, "$features.mysql.instances"),
+					technologyIfPresent(model.TechnologyMicrosoftSQLServer, "$features.microsoft.sqlServer.instances"),
+					technologyIfPresent(model.TechnologyPostgreSQLPostgreSQL, "$features.postgresql.instances"),
+					technologyIfPresent(model.TechnologyMongoDBMongoDB, "$features.mongodb.instances"),
+				}},
+			}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	out := make([]dto.HostMetrics, 0)
+
+	if err := cur.All(context.TODO(), &out); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return out, nil
+}
+
+// GetOpenAlertsCount return the number of the open alerts by code and severity
+func (md *MongoDatabase) GetOpenAlertsCount() ([]dto.AlertsCount, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(alertsCollection).Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			mu.APMatch(bson.M{
+				"alertStatus": bson.M{"$in": bson.A{model.AlertStatusNew, model.AlertStatusAck, model.AlertStatusSnoozed}},
+			}),
+			mu.APGroup(bson.M{
+				"_id": bson.M{
+					"alertCode":     "$alertCode",
+					"alertSeverity": "$alertSeverity",
+				},
+				"count": mu.APOSum(1),
+			}),
+			mu.APProject(bson.M{
+				"_id":           0,
+				"alertCode":     "$_id.alertCode",
+				"alertSeverity": "$_id.alertSeverity",
+				"count":         1,
+			}),
+			mu.APSort(bson.D{{Key: "alertCode", Value: 1}, {Key: "alertSeverity", Value: 1}}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	out := make([]dto.AlertsCount, 0)
+
+	if err := cur.All(context.TODO(), &out); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return out, nil
+}
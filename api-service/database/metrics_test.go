@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestGetOpenAlertsCount() {
+	defer m.db.Client.Database(m.dbname).Collection(alertsCollection).DeleteMany(context.TODO(), bson.M{})
+
+	alerts := []interface{}{
+		model.Alert{
+			ID:            utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			AlertStatus:   model.AlertStatusNew,
+		},
+		model.Alert{
+			ID:            utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			AlertStatus:   model.AlertStatusSnoozed,
+		},
+		model.Alert{
+			ID:            utils.Str2oid("cccccccccccccccccccccccc"),
+			AlertCode:     model.AlertCodeNewServer,
+			AlertSeverity: model.AlertSeverityInfo,
+			AlertStatus:   model.AlertStatusAck,
+		},
+		model.Alert{
+			ID:            utils.Str2oid("dddddddddddddddddddddddd"),
+			AlertCode:     model.AlertCodeNewServer,
+			AlertSeverity: model.AlertSeverityInfo,
+			AlertStatus:   model.AlertStatusResolved,
+		},
+	}
+
+	_, err := m.db.Client.Database(m.dbname).Collection(alertsCollection).InsertMany(context.TODO(), alerts)
+	m.Require().NoError(err)
+
+	out, err := m.db.GetOpenAlertsCount()
+	m.Require().NoError(err)
+
+	expected := []dto.AlertsCount{
+		{AlertCode: model.AlertCodeNewServer, AlertSeverity: model.AlertSeverityInfo, Count: 1},
+		{AlertCode: model.AlertCodeNoData, AlertSeverity: model.AlertSeverityCritical, Count: 2},
+	}
+	assert.Equal(m.T(), expected, out)
+}
@@ -30,6 +30,10 @@ import (
 func (md *MongoDatabase) SearchSqlServerDatabaseUsedLicenses(hostname string, sortBy string, sortDesc bool, page int, pageSize int,
 	location string, environment string, olderThan time.Time,
 ) (*dto.SqlServerDatabaseUsedLicenseSearchResponse, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	cursor, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
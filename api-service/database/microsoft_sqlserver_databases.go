@@ -26,7 +26,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func (md *MongoDatabase) SearchSqlServerInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.SqlServerInstanceResponse, error) {
+func (md *MongoDatabase) SearchSqlServerInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.SqlServerInstanceResponse, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var sqlServerInstanceResponse dto.SqlServerInstanceResponse
 
 	var pagePaging, pagePagingSize int
@@ -44,25 +48,7 @@ func (md *MongoDatabase) SearchSqlServerInstances(keywords []string, sortBy stri
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			FilterByOldnessSteps(olderThan),
-			FilterByLocationAndEnvironmentSteps(location, environment),
-			mu.APUnwind("$features.microsoft.sqlServer.instances"),
-			mu.APProject(bson.M{
-				"hostname":    1,
-				"environment": 1,
-				"location":    1,
-				"instance":    "$features.microsoft.sqlServer.instances",
-			}),
-			mu.APSearchFilterStage([]interface{}{"$hostname", "$sqlserver.name"}, keywords),
-			mu.APAddFields(bson.M{
-				"name":          "$instance.name",
-				"stateDesc":     "$instance.stateDesc",
-				"edition":       "$instance.edition",
-				"collationName": "$instance.collationName",
-				"version":       "$instance.version",
-			}),
-			mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
-			mu.APUnset("instance"),
+			searchSqlServerInstancesSteps(keywords, location, environment, olderThan, metadata),
 			mu.APOptionalSortingStage(sortBy, sortDesc),
 			mu.APLimit(pagePagingSize),
 		),
@@ -88,6 +74,7 @@ func (md *MongoDatabase) SearchSqlServerInstances(keywords []string, sortBy stri
 		mu.MAPipeline(
 			FilterByOldnessSteps(olderThan),
 			FilterByLocationAndEnvironmentSteps(location, environment),
+			FilterByHostMetadataSteps(metadata),
 			mu.APUnwind("$features.microsoft.sqlServer.instances"),
 			mu.APProject(bson.M{
 				"hostname":    1,
@@ -136,3 +123,38 @@ func (md *MongoDatabase) SearchSqlServerInstances(keywords []string, sortBy stri
 
 	return &sqlServerInstanceResponse, nil
 }
+
+// ListSqlServerInstances return the page of the SQL Server instances requested by the query
+func (md *MongoDatabase) ListSqlServerInstances(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchSqlServerInstancesSteps(keywords, location, environment, olderThan, metadata), q, "_id", "name")
+}
+
+// searchSqlServerInstancesSteps return the steps that filter the SQL Server instances, without sorting and paging them
+func searchSqlServerInstancesSteps(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) interface{} {
+	return mu.MAPipeline(
+		FilterByOldnessSteps(olderThan),
+		FilterByLocationAndEnvironmentSteps(location, environment),
+		FilterByHostMetadataSteps(metadata),
+		mu.APUnwind("$features.microsoft.sqlServer.instances"),
+		mu.APProject(bson.M{
+			"hostname":    1,
+			"environment": 1,
+			"location":    1,
+			"instance":    "$features.microsoft.sqlServer.instances",
+		}),
+		mu.APSearchFilterStage([]interface{}{"$hostname", "$sqlserver.name"}, keywords),
+		mu.APAddFields(bson.M{
+			"name":          "$instance.name",
+			"stateDesc":     "$instance.stateDesc",
+			"edition":       "$instance.edition",
+			"collationName": "$instance.collationName",
+			"version":       "$instance.version",
+		}),
+		mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
+		mu.APUnset("instance"),
+	)
+}
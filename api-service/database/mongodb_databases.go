@@ -26,7 +26,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func (md *MongoDatabase) SearchMongoDBInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.MongoDBInstanceResponse, error) {
+func (md *MongoDatabase) SearchMongoDBInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.MongoDBInstanceResponse, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var mongoDBInstanceResponse dto.MongoDBInstanceResponse
 
 	var pagePaging, pagePagingSize int
@@ -44,25 +48,7 @@ func (md *MongoDatabase) SearchMongoDBInstances(keywords []string, sortBy string
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			FilterByOldnessSteps(olderThan),
-			FilterByLocationAndEnvironmentSteps(location, environment),
-			mu.APUnwind("$features.mongodb.instances"),
-			mu.APUnwind("$features.mongodb.instances.dbStats"),
-			mu.APProject(bson.M{
-				"hostname":    1,
-				"environment": 1,
-				"location":    1,
-				"instance":    "$features.mongodb.instances",
-			}),
-			mu.APSearchFilterStage([]interface{}{"$hostname", "$name"}, keywords),
-			mu.APAddFields(bson.M{
-				"name":    "$instance.name",
-				"dbName":  "$instance.dbStats.dbName",
-				"charset": "$instance.dbStats.charset",
-				"version": "$instance.version",
-			}),
-			mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
-			mu.APUnset("instance"),
+			searchMongoDBInstancesSteps(keywords, location, environment, olderThan, metadata),
 			mu.APOptionalSortingStage(sortBy, sortDesc),
 			mu.APLimit(pagePagingSize),
 		),
@@ -88,6 +74,7 @@ func (md *MongoDatabase) SearchMongoDBInstances(keywords []string, sortBy string
 		mu.MAPipeline(
 			FilterByOldnessSteps(olderThan),
 			FilterByLocationAndEnvironmentSteps(location, environment),
+			FilterByHostMetadataSteps(metadata),
 			mu.APUnwind("$features.mongodb.instances"),
 			mu.APProject(bson.M{
 				"hostname":    1,
@@ -136,3 +123,38 @@ func (md *MongoDatabase) SearchMongoDBInstances(keywords []string, sortBy string
 
 	return &mongoDBInstanceResponse, nil
 }
+
+// ListMongoDBInstances return the page of the MongoDB instances requested by the query
+func (md *MongoDatabase) ListMongoDBInstances(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchMongoDBInstancesSteps(keywords, location, environment, olderThan, metadata), q, "_id", "name", "dbName")
+}
+
+// searchMongoDBInstancesSteps return the steps that filter the MongoDB instances, without sorting and paging them
+func searchMongoDBInstancesSteps(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) interface{} {
+	return mu.MAPipeline(
+		FilterByOldnessSteps(olderThan),
+		FilterByLocationAndEnvironmentSteps(location, environment),
+		FilterByHostMetadataSteps(metadata),
+		mu.APUnwind("$features.mongodb.instances"),
+		mu.APUnwind("$features.mongodb.instances.dbStats"),
+		mu.APProject(bson.M{
+			"hostname":    1,
+			"environment": 1,
+			"location":    1,
+			"instance":    "$features.mongodb.instances",
+		}),
+		mu.APSearchFilterStage([]interface{}{"$hostname", "$name"}, keywords),
+		mu.APAddFields(bson.M{
+			"name":    "$instance.name",
+			"dbName":  "$instance.dbStats.dbName",
+			"charset": "$instance.dbStats.charset",
+			"version": "$instance.version",
+		}),
+		mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
+		mu.APUnset("instance"),
+	)
+}
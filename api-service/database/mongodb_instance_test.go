@@ -33,7 +33,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_35.json"))
 
 	m.T().Run("should_filter_out_by_environment", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, -1, -1, "", "PROD", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, -1, -1, "", "PROD", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		expectedOut := dto.MongoDBInstanceResponse{
@@ -53,7 +53,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("should_filter_out_by_location", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, -1, -1, "France", "", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, -1, -1, "France", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		expectedOut := dto.MongoDBInstanceResponse{
@@ -72,7 +72,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("should_filter_out_by_older_than", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, -1, -1, "", "", utils.P("1999-05-04T16:09:46.608+02:00"))
+		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, -1, -1, "", "", utils.P("1999-05-04T16:09:46.608+02:00"), dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		expectedOut := dto.MongoDBInstanceResponse{
@@ -92,7 +92,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("should_be_paging", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, 0, 1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{""}, "", false, 0, 1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		var expectedContent []dto.MongoDBInstance = []dto.MongoDBInstance{
@@ -124,7 +124,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("should_be_sorting", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{""}, "hostname", true, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{""}, "hostname", true, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.MongoDBInstance = []dto.MongoDBInstance{
 			{
@@ -155,7 +155,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("should_search_return_anything", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{"foobar"}, "", false, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{"foobar"}, "", false, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.MongoDBInstance = []dto.MongoDBInstance{}
 
@@ -176,7 +176,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("should_search_return_found", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{"test-db2"}, "", false, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{"test-db2"}, "", false, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.MongoDBInstance = []dto.MongoDBInstance{
 			{
@@ -207,7 +207,7 @@ func (m *MongodbSuite) TestSearchMongoDBInstances() {
 	})
 
 	m.T().Run("fullmode", func(t *testing.T) {
-		out, err := m.db.SearchMongoDBInstances([]string{""}, "hostname", false, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchMongoDBInstances([]string{""}, "hostname", false, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.MongoDBInstance = []dto.MongoDBInstance{
 			{
@@ -28,21 +28,13 @@ import (
 )
 
 func (md *MongoDatabase) SearchMySQLInstances(filter dto.GlobalFilter) ([]dto.MySQLInstance, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
-		mu.MAPipeline(
-			FilterByOldnessSteps(filter.OlderThan),
-			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
-			mu.APUnwind("$features.mysql.instances"),
-			mu.APProject(bson.M{
-				"hostname":    1,
-				"location":    1,
-				"environment": 1,
-				"instance":    "$features.mysql.instances",
-			}),
-			mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
-			mu.APUnset("instance"),
-		),
+		searchMySQLInstancesSteps(filter),
 	)
 	if err != nil {
 		return nil, utils.NewError(err, "DB ERROR")
@@ -58,13 +50,45 @@ func (md *MongoDatabase) SearchMySQLInstances(filter dto.GlobalFilter) ([]dto.My
 	return out, nil
 }
 
+// ListMySQLInstances return the page of the MySQL instances requested by the query
+func (md *MongoDatabase) ListMySQLInstances(filter dto.GlobalFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchMySQLInstancesSteps(filter), q, "_id", "name")
+}
+
+// searchMySQLInstancesSteps return the steps that filter the MySQL instances
+func searchMySQLInstancesSteps(filter dto.GlobalFilter) interface{} {
+	return mu.MAPipeline(
+		FilterByOldnessSteps(filter.OlderThan),
+		FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+		FilterByHostMetadataSteps(filter.HostMetadataFilter),
+		mu.APUnwind("$features.mysql.instances"),
+		mu.APProject(bson.M{
+			"hostname":    1,
+			"location":    1,
+			"environment": 1,
+			"instance":    "$features.mysql.instances",
+		}),
+		mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
+		mu.APUnset("instance"),
+	)
+}
+
 func (md *MongoDatabase) GetMySQLUsedLicenses(hostname string, filter dto.GlobalFilter) ([]dto.MySQLUsedLicense, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
 			FindByHostname(hostname),
 			FilterByOldnessSteps(filter.OlderThan),
 			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			FilterByHostMetadataSteps(filter.HostMetadataFilter),
 			mu.APUnwind("$features.mysql.instances"),
 			mu.APUnwind("$features.mysql.instances.license"),
 			mu.APMatch(bson.M{
@@ -25,6 +25,10 @@ import (
 )
 
 func (md *MongoDatabase) FindOracleChangesByHostname(filter dto.GlobalFilter, hostname string) ([]dto.OracleChangesDto, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
 	ctx := context.TODO()
 
 	result := make([]dto.OracleChangesDto, 0)
@@ -34,6 +38,7 @@ func (md *MongoDatabase) FindOracleChangesByHostname(filter dto.GlobalFilter, ho
 		mu.MAPipeline(
 			FilterByOldnessSteps(filter.OlderThan),
 			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			FilterByHostMetadataSteps(filter.HostMetadataFilter),
 			mu.APMatch(bson.M{
 				"features.oracle.database.databases": bson.M{
 					"$ne": nil,
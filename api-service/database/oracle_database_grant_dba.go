@@ -26,6 +26,10 @@ import (
 )
 
 func (md *MongoDatabase) FindGrantDbaByHostname(hostname string, filter dto.GlobalFilter) ([]dto.OracleGrantDbaDto, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
 	ctx := context.TODO()
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		ctx,
@@ -33,6 +37,7 @@ func (md *MongoDatabase) FindGrantDbaByHostname(hostname string, filter dto.Glob
 			FindByHostname(hostname),
 			FilterByOldnessSteps(filter.OlderThan),
 			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			FilterByHostMetadataSteps(filter.HostMetadataFilter),
 			mu.APUnwind("$features.oracle.database.databases"),
 			mu.APUnwind("$features.oracle.database.databases.grantDba"),
 			mu.APProject(
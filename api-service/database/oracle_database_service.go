@@ -25,6 +25,10 @@ import (
 )
 
 func (md *MongoDatabase) GetOracleServiceList(filter dto.GlobalFilter) ([]dto.OracleDatabaseServiceDto, error) {
+	if err := md.rebuildHostDataHistory(filter.OlderThan); err != nil {
+		return nil, err
+	}
+
 	ctx := context.TODO()
 
 	result := make([]dto.OracleDatabaseServiceDto, 0)
@@ -34,6 +38,7 @@ func (md *MongoDatabase) GetOracleServiceList(filter dto.GlobalFilter) ([]dto.Or
 		mu.MAPipeline(
 			FilterByOldnessSteps(filter.OlderThan),
 			FilterByLocationAndEnvironmentSteps(filter.Location, filter.Environment),
+			FilterByHostMetadataSteps(filter.HostMetadataFilter),
 			bson.M{"$unwind": bson.M{"path": "$features.oracle.database.databases"}},
 			bson.M{"$unwind": bson.M{"path": "$features.oracle.database.databases.services"}},
 			bson.M{"$project": bson.M{
@@ -28,7 +28,11 @@ import (
 )
 
 // SearchOracleDatabases search databases
-func (md *MongoDatabase) SearchOracleDatabases(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.OracleDatabaseResponse, error) {
+func (md *MongoDatabase) SearchOracleDatabases(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.OracleDatabaseResponse, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	//Find the matching hostdata
 	var oracleDatabaseResponse dto.OracleDatabaseResponse
 
@@ -47,55 +51,7 @@ func (md *MongoDatabase) SearchOracleDatabases(keywords []string, sortBy string,
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			FilterByOldnessSteps(olderThan),
-			FilterByLocationAndEnvironmentSteps(location, environment),
-			mu.APUnwind("$features.oracle.database.databases"),
-			AddHardwareAbstraction("features.oracle.database.databases.ha"),
-			mu.APProject(bson.M{
-				"hostname":     1,
-				"environment":  1,
-				"location":     1,
-				"name":         1,
-				"uniqueName":   1,
-				"status":       1,
-				"version":      1,
-				"archivelog":   1,
-				"charset":      1,
-				"blockSize":    1,
-				"cpuCount":     1,
-				"memoryTarget": 1,
-				"segmentsSize": 1,
-				"datafileSize": 1,
-				"work":         1,
-				"dataguard":    1,
-				"dbID":         1,
-				"role":         1,
-				"database":     "$features.oracle.database.databases",
-			}),
-			mu.APSearchFilterStage([]interface{}{"$hostname", "$database.name"}, keywords),
-			mu.APAddFields(bson.M{
-				"database.memory": mu.APOAdd(
-					"$database.pgaTarget",
-					"$database.sgaTarget",
-					"$database.memoryTarget",
-				),
-				"database.rac": mu.APOAny("$database.licenses", "lic", mu.APOAnd(
-					mu.APOEqual("$$lic.name", "Real Application Clusters"),
-					mu.APOGreater("$$lic.count", 0),
-				)),
-				"database.isCDB":    "$database.isCDB",
-				"database.services": "$database.services",
-				"database.licenses": "$database.licenses",
-			}),
-			mu.APSet(bson.M{
-				"database.pdbs": mu.APOCond("$database.isCDB", bson.M{
-					"$concatArrays": bson.A{
-						mu.APOMap("$database.pdbs", "pdb", "$$pdb.name"),
-					},
-				}, []string{}),
-			}),
-			mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$database")),
-			mu.APUnset("database"),
+			searchOracleDatabasesSteps(keywords, location, environment, olderThan, metadata),
 			mu.APOptionalSortingStage(sortBy, sortDesc),
 			mu.APLimit(pagePagingSize),
 		),
@@ -120,6 +76,7 @@ func (md *MongoDatabase) SearchOracleDatabases(keywords []string, sortBy string,
 		mu.MAPipeline(
 			FilterByOldnessSteps(olderThan),
 			FilterByLocationAndEnvironmentSteps(location, environment),
+			FilterByHostMetadataSteps(metadata),
 			mu.APUnwind("$features.oracle.database.databases"),
 			mu.APProject(bson.M{
 				"hostname":    1,
@@ -168,3 +125,68 @@ func (md *MongoDatabase) SearchOracleDatabases(keywords []string, sortBy string,
 
 	return &oracleDatabaseResponse, nil
 }
+
+// ListOracleDatabases return the page of the Oracle databases requested by the query
+func (md *MongoDatabase) ListOracleDatabases(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchOracleDatabasesSteps(keywords, location, environment, olderThan, metadata), q, "_id", "name")
+}
+
+// searchOracleDatabasesSteps return the steps that filter the Oracle databases, without sorting and paging them
+func searchOracleDatabasesSteps(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) interface{} {
+	return mu.MAPipeline(
+		FilterByOldnessSteps(olderThan),
+		FilterByLocationAndEnvironmentSteps(location, environment),
+		FilterByHostMetadataSteps(metadata),
+		mu.APUnwind("$features.oracle.database.databases"),
+		AddHardwareAbstraction("features.oracle.database.databases.ha"),
+		mu.APProject(bson.M{
+			"hostname":     1,
+			"environment":  1,
+			"location":     1,
+			"name":         1,
+			"uniqueName":   1,
+			"status":       1,
+			"version":      1,
+			"archivelog":   1,
+			"charset":      1,
+			"blockSize":    1,
+			"cpuCount":     1,
+			"memoryTarget": 1,
+			"segmentsSize": 1,
+			"datafileSize": 1,
+			"work":         1,
+			"dataguard":    1,
+			"dbID":         1,
+			"role":         1,
+			"database":     "$features.oracle.database.databases",
+		}),
+		mu.APSearchFilterStage([]interface{}{"$hostname", "$database.name"}, keywords),
+		mu.APAddFields(bson.M{
+			"database.memory": mu.APOAdd(
+				"$database.pgaTarget",
+				"$database.sgaTarget",
+				"$database.memoryTarget",
+			),
+			"database.rac": mu.APOAny("$database.licenses", "lic", mu.APOAnd(
+				mu.APOEqual("$$lic.name", "Real Application Clusters"),
+				mu.APOGreater("$$lic.count", 0),
+			)),
+			"database.isCDB":    "$database.isCDB",
+			"database.services": "$database.services",
+			"database.licenses": "$database.licenses",
+		}),
+		mu.APSet(bson.M{
+			"database.pdbs": mu.APOCond("$database.isCDB", bson.M{
+				"$concatArrays": bson.A{
+					mu.APOMap("$database.pdbs", "pdb", "$$pdb.name"),
+				},
+			}, []string{}),
+		}),
+		mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$database")),
+		mu.APUnset("database"),
+	)
+}
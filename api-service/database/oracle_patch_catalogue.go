@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const (
+	oraclePatchReleasesCollection  = "oracle_patch_releases"
+	oracleVersionSupportCollection = "oracle_version_support"
+)
+
+// GetOraclePatchCatalogue return the Oracle patch releases, sorted by version and release date,
+// and the support of the Oracle versions
+func (md *MongoDatabase) GetOraclePatchCatalogue() (*model.OraclePatchCatalogue, error) {
+	db := md.Client.Database(md.Config.Mongodb.DBName)
+
+	cur, err := db.Collection(oraclePatchReleasesCollection).Find(context.TODO(), bson.M{},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}, {Key: "releaseDate", Value: 1}}))
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	catalogue := model.OraclePatchCatalogue{
+		Releases: make([]model.OraclePatchRelease, 0),
+		Versions: make([]model.OracleVersionSupport, 0),
+	}
+
+	if err := cur.All(context.TODO(), &catalogue.Releases); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	cur, err = db.Collection(oracleVersionSupportCollection).Find(context.TODO(), bson.M{},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}}))
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	if err := cur.All(context.TODO(), &catalogue.Versions); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return &catalogue, nil
+}
+
+// ReplaceOraclePatchReleases replace all the Oracle patch releases of the catalogue with the new ones
+func (md *MongoDatabase) ReplaceOraclePatchReleases(releases []model.OraclePatchRelease) error {
+	docs := make([]interface{}, len(releases))
+	for i := range releases {
+		docs[i] = releases[i]
+	}
+
+	return md.replaceAll(oraclePatchReleasesCollection, docs)
+}
+
+// ReplaceOracleVersionSupport replace the support of all the Oracle versions of the catalogue with the new ones
+func (md *MongoDatabase) ReplaceOracleVersionSupport(versions []model.OracleVersionSupport) error {
+	docs := make([]interface{}, len(versions))
+	for i := range versions {
+		docs[i] = versions[i]
+	}
+
+	return md.replaceAll(oracleVersionSupportCollection, docs)
+}
+
+func (md *MongoDatabase) replaceAll(collectionName string, docs []interface{}) error {
+	collection := md.Client.Database(md.Config.Mongodb.DBName).Collection(collectionName)
+
+	if _, err := collection.DeleteMany(context.TODO(), bson.M{}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if _, err := collection.InsertMany(context.TODO(), docs); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
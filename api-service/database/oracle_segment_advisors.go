@@ -30,6 +30,10 @@ import (
 func (md *MongoDatabase) SearchOracleDatabaseSegmentAdvisors(keywords []string, sortBy string, sortDesc bool,
 	location string, environment string, olderThan time.Time,
 ) ([]dto.OracleDatabaseSegmentAdvisor, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
@@ -86,6 +90,10 @@ func (md *MongoDatabase) SearchOracleDatabaseSegmentAdvisors(keywords []string,
 
 func (md *MongoDatabase) SearchOraclePdbSegmentAdvisors(sortBy string, sortDesc bool,
 	location string, environment string, olderThan time.Time) ([]dto.OracleDatabaseSegmentAdvisor, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
@@ -27,6 +27,10 @@ import (
 
 // GetOracleDatabaseEnvironmentStats return a array containing the number of databases per environment
 func (md *MongoDatabase) GetOracleDatabaseEnvironmentStats(location string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 	//Calculate the stats
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
@@ -67,6 +71,10 @@ func (md *MongoDatabase) GetOracleDatabaseEnvironmentStats(location string, olde
 
 // GetOracleDatabaseHighReliabilityStats return a array containing the number of databases per high-reliability status
 func (md *MongoDatabase) GetOracleDatabaseHighReliabilityStats(location string, environment string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 	//Calculate the stats
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
@@ -108,6 +116,10 @@ func (md *MongoDatabase) GetOracleDatabaseHighReliabilityStats(location string,
 
 // GetOracleDatabaseVersionStats return a array containing the number of databases per version
 func (md *MongoDatabase) GetOracleDatabaseVersionStats(location string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -145,6 +157,10 @@ func (md *MongoDatabase) GetOracleDatabaseVersionStats(location string, olderTha
 
 // GetTopReclaimableOracleDatabaseStats return a array containing the total sum of reclaimable of segments advisors of the top reclaimable databases
 func (md *MongoDatabase) GetTopReclaimableOracleDatabaseStats(location string, limit int, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -186,8 +202,14 @@ func (md *MongoDatabase) GetTopReclaimableOracleDatabaseStats(location string, l
 	return out, nil
 }
 
-// GetTopWorkloadOracleDatabaseStats return a array containing top databases by workload
-func (md *MongoDatabase) GetTopWorkloadOracleDatabaseStats(location string, limit int, olderThan time.Time) ([]interface{}, error) {
+// GetTopWorkloadOracleDatabaseStats return a array containing top databases by workload.
+// The workload is the average CPU of the database in the consumption metrics since the date,
+// or the workload of the latest hostdata if there aren't metrics
+func (md *MongoDatabase) GetTopWorkloadOracleDatabaseStats(location string, limit int, olderThan, since time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -206,6 +228,24 @@ func (md *MongoDatabase) GetTopWorkloadOracleDatabaseStats(location string, limi
 				"dbname":   "$database.name",
 				"workload": mu.APOConvertToDoubleOrZero("$database.work"),
 			}),
+			mu.APLookupPipeline("consumption_metrics", bson.M{
+				"hostname": "$hostname",
+				"dbname":   "$dbname",
+			}, "history", mu.MAPipeline(
+				mu.APMatch(bson.M{"timestamp": bson.M{"$gte": since}}),
+				mu.APMatch(mu.QOExpr(mu.APOAnd(
+					mu.APOEqual("$meta.hostname", "$$hostname"),
+					mu.APOEqual("$meta.databaseName", "$$dbname"),
+				))),
+				mu.APGroup(bson.M{
+					"_id":      nil,
+					"workload": bson.M{"$avg": "$cpuAvg"},
+				}),
+			)),
+			mu.APSet(bson.M{
+				"workload": mu.APOIfNull(mu.APOArrayElemAt("$history.workload", 0), "$workload"),
+			}),
+			mu.APUnset("history"),
 			mu.APSort(bson.M{
 				"workload": -1,
 			}),
@@ -231,6 +271,10 @@ func (md *MongoDatabase) GetTopWorkloadOracleDatabaseStats(location string, limi
 
 // GetOracleDatabasePatchStatusStats return a array containing the number of databases per patch status
 func (md *MongoDatabase) GetOracleDatabasePatchStatusStats(location string, windowTime time.Time, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -286,6 +330,10 @@ func (md *MongoDatabase) GetOracleDatabasePatchStatusStats(location string, wind
 
 // GetOracleDatabaseDataguardStatusStats return a array containing the number of databases per dataguard status
 func (md *MongoDatabase) GetOracleDatabaseDataguardStatusStats(location string, environment string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -323,6 +371,10 @@ func (md *MongoDatabase) GetOracleDatabaseDataguardStatusStats(location string,
 
 // GetOracleDatabaseRACStatusStats return a array containing the number of databases per RAC status
 func (md *MongoDatabase) GetOracleDatabaseRACStatusStats(location string, environment string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -365,6 +417,10 @@ func (md *MongoDatabase) GetOracleDatabaseRACStatusStats(location string, enviro
 
 // GetOracleDatabaseArchivelogStatusStats return a array containing the number of databases per archivelog status
 func (md *MongoDatabase) GetOracleDatabaseArchivelogStatusStats(location string, environment string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Calculate the stats
@@ -402,6 +458,10 @@ func (md *MongoDatabase) GetOracleDatabaseArchivelogStatusStats(location string,
 
 // GetTotalOracleDatabaseWorkStats return the total work of databases
 func (md *MongoDatabase) GetTotalOracleDatabaseWorkStats(location string, environment string, olderThan time.Time) (float64, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return 0, err
+	}
+
 	var out map[string]float64
 
 	//Calculate the stats
@@ -440,6 +500,10 @@ func (md *MongoDatabase) GetTotalOracleDatabaseWorkStats(location string, enviro
 
 // GetTotalOracleDatabaseMemorySizeStats return the total of memory size of databases
 func (md *MongoDatabase) GetTotalOracleDatabaseMemorySizeStats(location string, environment string, olderThan time.Time) (float64, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return 0, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
@@ -485,6 +549,10 @@ func (md *MongoDatabase) GetTotalOracleDatabaseMemorySizeStats(location string,
 
 // GetTotalOracleDatabaseDatafileSizeStats return the total size of datafiles of databases
 func (md *MongoDatabase) GetTotalOracleDatabaseDatafileSizeStats(location string, environment string, olderThan time.Time) (float64, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return 0, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
@@ -533,6 +601,10 @@ func (md *MongoDatabase) GetTotalOracleDatabaseDatafileSizeStats(location string
 
 // GetTotalOracleDatabaseSegmentSizeStats return the total size of segments of databases
 func (md *MongoDatabase) GetTotalOracleDatabaseSegmentSizeStats(location string, environment string, olderThan time.Time) (float64, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return 0, err
+	}
+
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
@@ -581,6 +653,10 @@ func (md *MongoDatabase) GetTotalOracleDatabaseSegmentSizeStats(location string,
 
 // GetTopUnusedOracleDatabaseInstanceResourceStats return a array containing top unused instance resource by workload
 func (md *MongoDatabase) GetTopUnusedOracleDatabaseInstanceResourceStats(location string, environment string, limit int, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
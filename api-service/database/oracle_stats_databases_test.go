@@ -189,7 +189,7 @@ func (m *MongodbSuite) TestGetTopWorkloadOracleDatabaseStats() {
 	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_13.json"))
 
 	m.T().Run("should_filter_out_by_location", func(t *testing.T) {
-		out, err := m.db.GetTopWorkloadOracleDatabaseStats("France", 15, utils.MAX_TIME)
+		out, err := m.db.GetTopWorkloadOracleDatabaseStats("France", 15, utils.MAX_TIME, utils.MAX_TIME)
 		m.Require().NoError(err)
 		var expectedOut interface{} = []interface{}{}
 
@@ -197,7 +197,7 @@ func (m *MongodbSuite) TestGetTopWorkloadOracleDatabaseStats() {
 	})
 
 	m.T().Run("should_filter_out_by_older_than", func(t *testing.T) {
-		out, err := m.db.GetTopWorkloadOracleDatabaseStats("", 15, utils.MIN_TIME)
+		out, err := m.db.GetTopWorkloadOracleDatabaseStats("", 15, utils.MIN_TIME, utils.MAX_TIME)
 		m.Require().NoError(err)
 		var expectedOut interface{} = []interface{}{}
 
@@ -205,7 +205,7 @@ func (m *MongodbSuite) TestGetTopWorkloadOracleDatabaseStats() {
 	})
 
 	m.T().Run("should_limit_the_result", func(t *testing.T) {
-		out, err := m.db.GetTopWorkloadOracleDatabaseStats("", 1, utils.MAX_TIME)
+		out, err := m.db.GetTopWorkloadOracleDatabaseStats("", 1, utils.MAX_TIME, utils.MAX_TIME)
 		m.Require().NoError(err)
 		var expectedOut interface{} = []map[string]interface{}{
 			{
@@ -220,7 +220,7 @@ func (m *MongodbSuite) TestGetTopWorkloadOracleDatabaseStats() {
 	})
 
 	m.T().Run("should_return_all_results", func(t *testing.T) {
-		out, err := m.db.GetTopWorkloadOracleDatabaseStats("", 15, utils.MAX_TIME)
+		out, err := m.db.GetTopWorkloadOracleDatabaseStats("", 15, utils.MAX_TIME, utils.MAX_TIME)
 		m.Require().NoError(err)
 		var expectedOut interface{} = []map[string]interface{}{
 			{
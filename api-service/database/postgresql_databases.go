@@ -26,7 +26,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func (md *MongoDatabase) SearchPostgreSqlInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) (*dto.PostgreSqlInstanceResponse, error) {
+func (md *MongoDatabase) SearchPostgreSqlInstances(keywords []string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) (*dto.PostgreSqlInstanceResponse, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var postgreSqlInstanceResponse dto.PostgreSqlInstanceResponse
 
 	var pagePaging, pagePagingSize int
@@ -44,23 +48,7 @@ func (md *MongoDatabase) SearchPostgreSqlInstances(keywords []string, sortBy str
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
 		context.TODO(),
 		mu.MAPipeline(
-			FilterByOldnessSteps(olderThan),
-			FilterByLocationAndEnvironmentSteps(location, environment),
-			mu.APUnwind("$features.postgresql.instances"),
-			mu.APProject(bson.M{
-				"hostname":    1,
-				"environment": 1,
-				"location":    1,
-				"instance":    "$features.postgresql.instances",
-			}),
-			mu.APSearchFilterStage([]interface{}{"$hostname", "$name"}, keywords),
-			mu.APAddFields(bson.M{
-				"name":    "$instance.name",
-				"charset": "$instance.charset",
-				"version": "$instance.setting.dbVersion",
-			}),
-			mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
-			mu.APUnset("instance"),
+			searchPostgreSqlInstancesSteps(keywords, location, environment, olderThan, metadata),
 			mu.APOptionalSortingStage(sortBy, sortDesc),
 			mu.APLimit(pagePagingSize),
 		),
@@ -86,6 +74,7 @@ func (md *MongoDatabase) SearchPostgreSqlInstances(keywords []string, sortBy str
 		mu.MAPipeline(
 			FilterByOldnessSteps(olderThan),
 			FilterByLocationAndEnvironmentSteps(location, environment),
+			FilterByHostMetadataSteps(metadata),
 			mu.APUnwind("$features.postgresql.instances"),
 			mu.APProject(bson.M{
 				"hostname":    1,
@@ -134,3 +123,36 @@ func (md *MongoDatabase) SearchPostgreSqlInstances(keywords []string, sortBy str
 
 	return &postgreSqlInstanceResponse, nil
 }
+
+// ListPostgreSqlInstances return the page of the PostgreSQL instances requested by the query
+func (md *MongoDatabase) ListPostgreSqlInstances(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
+	return md.aggregateList("hosts", searchPostgreSqlInstancesSteps(keywords, location, environment, olderThan, metadata), q, "_id", "name")
+}
+
+// searchPostgreSqlInstancesSteps return the steps that filter the PostgreSQL instances, without sorting and paging them
+func searchPostgreSqlInstancesSteps(keywords []string, location string, environment string, olderThan time.Time, metadata dto.HostMetadataFilter) interface{} {
+	return mu.MAPipeline(
+		FilterByOldnessSteps(olderThan),
+		FilterByLocationAndEnvironmentSteps(location, environment),
+		FilterByHostMetadataSteps(metadata),
+		mu.APUnwind("$features.postgresql.instances"),
+		mu.APProject(bson.M{
+			"hostname":    1,
+			"environment": 1,
+			"location":    1,
+			"instance":    "$features.postgresql.instances",
+		}),
+		mu.APSearchFilterStage([]interface{}{"$hostname", "$name"}, keywords),
+		mu.APAddFields(bson.M{
+			"name":    "$instance.name",
+			"charset": "$instance.charset",
+			"version": "$instance.setting.dbVersion",
+		}),
+		mu.APReplaceWith(mu.APOMergeObjects("$$ROOT", "$instance")),
+		mu.APUnset("instance"),
+	)
+}
@@ -33,7 +33,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	m.InsertHostData(mongoutils.LoadFixtureMongoHostDataMap(m.T(), "../../fixture/test_apiservice_mongohostdata_32.json"))
 
 	m.T().Run("should_filter_out_by_environment", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "", "PROD", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "", "PROD", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		expectedOut := dto.PostgreSqlInstanceResponse{
@@ -53,7 +53,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("should_filter_out_by_location", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "France", "", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "France", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		expectedOut := dto.PostgreSqlInstanceResponse{
@@ -72,7 +72,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("should_filter_out_by_older_than", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "", "", utils.P("1999-05-04T16:09:46.608+02:00"))
+		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "", "", utils.P("1999-05-04T16:09:46.608+02:00"), dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		expectedOut := dto.PostgreSqlInstanceResponse{
@@ -92,7 +92,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("should_be_paging", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, 0, 1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "", false, 0, 1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 
 		var expectedContent []dto.PostgreSqlInstance = []dto.PostgreSqlInstance{
@@ -123,7 +123,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("should_be_sorting", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "hostname", true, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "hostname", true, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.PostgreSqlInstance = []dto.PostgreSqlInstance{
 			{
@@ -153,7 +153,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("should_search_return_anything", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{"foobar"}, "", false, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{"foobar"}, "", false, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.PostgreSqlInstance = []dto.PostgreSqlInstance{}
 
@@ -174,7 +174,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("should_search_return_found", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{"test-db2"}, "", false, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{"test-db2"}, "", false, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.PostgreSqlInstance = []dto.PostgreSqlInstance{
 			{
@@ -204,7 +204,7 @@ func (m *MongodbSuite) TestSearchPostgreSqlInstances() {
 	})
 
 	m.T().Run("fullmode", func(t *testing.T) {
-		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "hostname", false, -1, -1, "", "", utils.MAX_TIME)
+		out, err := m.db.SearchPostgreSqlInstances([]string{""}, "hostname", false, -1, -1, "", "", utils.MAX_TIME, dto.HostMetadataFilter{})
 		m.Require().NoError(err)
 		var expectedContent []dto.PostgreSqlInstance = []dto.PostgreSqlInstance{
 			{
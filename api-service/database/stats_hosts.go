@@ -27,6 +27,10 @@ import (
 
 // GetHostsCountStats return the number of the non-archived hosts
 func (md *MongoDatabase) GetHostsCountStats(location string, environment string, olderThan time.Time) (int, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return 0, err
+	}
+
 	var out map[string]int
 
 	//Calculate the stats
@@ -58,6 +62,10 @@ func (md *MongoDatabase) GetHostsCountStats(location string, environment string,
 
 // GetEnvironmentStats return a array containing the number of hosts per environment
 func (md *MongoDatabase) GetEnvironmentStats(location string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 	//Calculate the stats
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
@@ -90,6 +98,10 @@ func (md *MongoDatabase) GetEnvironmentStats(location string, olderThan time.Tim
 
 // GetTypeStats return a array containing the number of hosts per type
 func (md *MongoDatabase) GetTypeStats(location string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 	//Calculate the stats
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
@@ -122,6 +134,10 @@ func (md *MongoDatabase) GetTypeStats(location string, olderThan time.Time) ([]i
 
 // GetOperatingSystemStats return a array containing the number of hosts per operanting system
 func (md *MongoDatabase) GetOperatingSystemStats(location string, olderThan time.Time) ([]interface{}, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []interface{} = make([]interface{}, 0)
 
 	//Create the aggregation branches
@@ -0,0 +1,62 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// AuditLogFilter contains the filters of the audit log search
+type AuditLogFilter struct {
+	Username   string
+	EntityType string
+	EntityID   string
+	Action     string
+	From       time.Time
+	To         time.Time
+	PageNumber int
+	PageSize   int
+}
+
+func GetAuditLogFilter(r *http.Request) (f *AuditLogFilter, err error) {
+	f = new(AuditLogFilter)
+
+	f.Username = r.URL.Query().Get("username")
+	f.EntityType = r.URL.Query().Get("entity-type")
+	f.EntityID = r.URL.Query().Get("entity-id")
+	f.Action = r.URL.Query().Get("action")
+
+	if f.From, err = utils.Str2time(r.URL.Query().Get("from"), utils.MIN_TIME); err != nil {
+		return nil, err
+	}
+
+	if f.To, err = utils.Str2time(r.URL.Query().Get("to"), utils.MAX_TIME); err != nil {
+		return nil, err
+	}
+
+	if f.PageNumber, err = utils.Str2int(r.URL.Query().Get("page"), -1); err != nil {
+		return nil, err
+	}
+
+	if f.PageSize, err = utils.Str2int(r.URL.Query().Get("size"), -1); err != nil {
+		return nil, err
+	}
+
+	return
+}
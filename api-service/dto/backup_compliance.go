@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package dto
+
+import (
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// BackupComplianceFilter contains the filters of the evaluations of the Oracle backups
+type BackupComplianceFilter struct {
+	Location    string
+	Environment string
+	Hostname    string
+	Policy      string
+	// Violation selects the databases with the violation
+	Violation string
+	// Compliant selects the compliant databases if true, the non compliant ones if false, all of them if nil
+	Compliant *bool
+}
+
+func GetBackupComplianceFilter(r *http.Request) (*BackupComplianceFilter, error) {
+	f := &BackupComplianceFilter{
+		Location:    r.URL.Query().Get("location"),
+		Environment: r.URL.Query().Get("environment"),
+		Hostname:    r.URL.Query().Get("hostname"),
+		Policy:      r.URL.Query().Get("policy"),
+		Violation:   r.URL.Query().Get("violation"),
+	}
+
+	if f.Violation != "" && !utils.Contains(model.BackupViolations, f.Violation) {
+		return nil, utils.NewErrorf("%w: invalid violation %q", utils.ErrInvalidBackupComplianceFilter, f.Violation)
+	}
+
+	if compliant := r.URL.Query().Get("compliant"); compliant != "" {
+		value, err := utils.Str2bool(compliant, false)
+		if err != nil {
+			return nil, utils.NewErrorf("%w: invalid compliant %q", utils.ErrInvalidBackupComplianceFilter, compliant)
+		}
+
+		f.Compliant = &value
+	}
+
+	return f, nil
+}
+
+// BackupComplianceReport contains the evaluations of the Oracle backups and their summary
+type BackupComplianceReport struct {
+	Databases []model.BackupCompliance `json:"databases"`
+	Summary   BackupComplianceSummary  `json:"summary"`
+}
+
+// BackupComplianceSummary contains the number of databases evaluated, of the compliant ones and of the ones with each violation
+type BackupComplianceSummary struct {
+	Total      int            `json:"total"`
+	Compliant  int            `json:"compliant"`
+	Violations map[string]int `json:"violations"`
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package dto
+
+import (
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// CapacityForecastFilter contains the filters of the capacity forecasts
+type CapacityForecastFilter struct {
+	Location     string
+	Environment  string
+	Hostname     string
+	Kind         string
+	DatabaseName string
+	// WithinDays selects the items projected to be full within the days, -1 selects all the items
+	WithinDays int
+}
+
+func GetCapacityForecastFilter(r *http.Request) (*CapacityForecastFilter, error) {
+	f := &CapacityForecastFilter{
+		Location:     r.URL.Query().Get("location"),
+		Environment:  r.URL.Query().Get("environment"),
+		Hostname:     r.URL.Query().Get("hostname"),
+		Kind:         r.URL.Query().Get("kind"),
+		DatabaseName: r.URL.Query().Get("database"),
+	}
+
+	if f.Kind != "" && !utils.Contains(model.CapacityForecastKinds, f.Kind) {
+		return nil, utils.NewErrorf("%w: invalid kind %q", utils.ErrInvalidCapacityForecastFilter, f.Kind)
+	}
+
+	var err error
+	if f.WithinDays, err = utils.Str2int(r.URL.Query().Get("within-days"), -1); err != nil {
+		return nil, err
+	}
+
+	if f.WithinDays < -1 {
+		return nil, utils.NewErrorf("%w: within-days can't be negative", utils.ErrInvalidCapacityForecastFilter)
+	}
+
+	return f, nil
+}
@@ -26,6 +26,8 @@ type GlobalFilter struct {
 	Location    string
 	Environment string
 	OlderThan   time.Time
+
+	HostMetadataFilter
 }
 
 func GetGlobalFilter(r *http.Request) (f *GlobalFilter, err error) {
@@ -33,6 +35,7 @@ func GetGlobalFilter(r *http.Request) (f *GlobalFilter, err error) {
 
 	f.Location = r.URL.Query().Get("location")
 	f.Environment = r.URL.Query().Get("environment")
+	f.HostMetadataFilter = GetHostMetadataFilter(r)
 
 	if f.OlderThan, err = utils.Str2time(r.URL.Query().Get("older-than"), utils.MAX_TIME); err != nil {
 		return nil, err
@@ -0,0 +1,40 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import "time"
+
+// HostAliasRequest contains the alias to add to a host
+type HostAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+// HostMergeRequest contains the hosts to merge: the hostdata, alerts and contracts of source are moved to target,
+// and source becomes an alias of target. When target isn't known yet, the merge is a rename
+type HostMergeRequest struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// HostAliasSuggestion contains a host that is probably an alias of another one
+type HostAliasSuggestion struct {
+	// Source is the host that should become an alias, the one updated less recently
+	Source          string    `json:"source"`
+	SourceUpdatedAt time.Time `json:"sourceUpdatedAt"`
+	Target          string    `json:"target"`
+	TargetUpdatedAt time.Time `json:"targetUpdatedAt"`
+	Reasons         []string  `json:"reasons"`
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HostMetadataRequest contains the metadata of a host edited by the users
+type HostMetadataRequest struct {
+	OwnerTeam           string   `json:"ownerTeam"`
+	ContactEmail        string   `json:"contactEmail"`
+	BusinessApplication string   `json:"businessApplication"`
+	CostCenter          string   `json:"costCenter"`
+	Criticality         string   `json:"criticality"`
+	Labels              []string `json:"labels"`
+}
+
+// HostMetadataFilter contains the filters on the metadata of the hosts.
+// Every field but Labels can contain more comma separated values, Labels must be all present
+type HostMetadataFilter struct {
+	OwnerTeam           string
+	BusinessApplication string
+	CostCenter          string
+	Criticality         string
+	Labels              []string
+}
+
+// IsEmpty return true if the filter doesn't filter anything
+func (f HostMetadataFilter) IsEmpty() bool {
+	return f.OwnerTeam == "" && f.BusinessApplication == "" && f.CostCenter == "" && f.Criticality == "" && len(f.Labels) == 0
+}
+
+func GetHostMetadataFilter(r *http.Request) HostMetadataFilter {
+	f := HostMetadataFilter{
+		OwnerTeam:           r.URL.Query().Get("owner-team"),
+		BusinessApplication: r.URL.Query().Get("business-application"),
+		CostCenter:          r.URL.Query().Get("cost-center"),
+		Criticality:         r.URL.Query().Get("criticality"),
+	}
+
+	if labels := r.URL.Query().Get("labels"); labels != "" {
+		f.Labels = strings.Split(labels, ",")
+	}
+
+	return f
+}
@@ -52,6 +52,8 @@ type SearchHostsFilters struct {
 	GTECPUCores                   int
 	LTECPUThreads                 int
 	GTECPUThreads                 int
+
+	HostMetadataFilter
 }
 
 func NewSearchHostsFilters() SearchHostsFilters {
@@ -101,6 +103,7 @@ func GetSearchHostFilters(r *http.Request) (*SearchHostsFilters, error) {
 
 	f.Location = r.URL.Query().Get("location")
 	f.Environment = r.URL.Query().Get("environment")
+	f.HostMetadataFilter = GetHostMetadataFilter(r)
 
 	if f.OlderThan, err = utils.Str2time(r.URL.Query().Get("older-than"), utils.MAX_TIME); err != nil {
 		return nil, err
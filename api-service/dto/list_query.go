@@ -0,0 +1,168 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import (
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const (
+	// DefaultListLimit is the number of items of a page when the limit isn't requested
+	DefaultListLimit = 100
+	// MaxListLimit is the maximum number of items of a page
+	MaxListLimit = 1000
+)
+
+// listQueryFieldRegex match the names of the fields that can be sorted and projected
+var listQueryFieldRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// SortField is a field of a multi-field sort
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery contains the pagination, the sort and the projection requested for a list
+type ListQuery struct {
+	// Cursor is the opaque position of the page, returned with the previous page.
+	// It contains the sort keys of the last item of the previous page
+	Cursor string
+	Limit  int
+	Sort   []SortField
+	// Fields are the fields of the items to return, all if empty
+	Fields []string
+}
+
+// ListPage is a page of a list
+type ListPage struct {
+	Items []map[string]interface{}
+	// Total is the number of the items of all the pages, counted only with the first page
+	Total *int
+	// NextCursor is the cursor of the next page, empty if this is the last one
+	NextCursor string
+}
+
+type listCursor struct {
+	Sort string `bson:"s"`
+	Keys bson.A `bson:"k"`
+}
+
+// GetListQuery return the list query of the request, or nil if the request doesn't use the list parameters
+func GetListQuery(r *http.Request) (*ListQuery, error) {
+	query := r.URL.Query()
+	if !query.Has("cursor") && !query.Has("limit") && !query.Has("sort") && !query.Has("fields") {
+		return nil, nil
+	}
+
+	q := ListQuery{Cursor: query.Get("cursor")}
+
+	var err error
+	if q.Limit, err = utils.Str2int(query.Get("limit"), DefaultListLimit); err != nil {
+		return nil, err
+	}
+
+	if q.Limit <= 0 || q.Limit > MaxListLimit {
+		return nil, utils.NewErrorf("%w: limit must be between 1 and %d", utils.ErrInvalidListQuery, MaxListLimit)
+	}
+
+	for _, field := range splitListQueryParam(query.Get("sort")) {
+		sortField := SortField{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if !listQueryFieldRegex.MatchString(sortField.Field) {
+			return nil, utils.NewErrorf("%w: invalid sort field %q", utils.ErrInvalidListQuery, field)
+		}
+
+		q.Sort = append(q.Sort, sortField)
+	}
+
+	for _, field := range splitListQueryParam(query.Get("fields")) {
+		if !listQueryFieldRegex.MatchString(field) {
+			return nil, utils.NewErrorf("%w: invalid field %q", utils.ErrInvalidListQuery, field)
+		}
+
+		q.Fields = append(q.Fields, field)
+	}
+
+	if _, err := q.After(); err != nil {
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+func splitListQueryParam(value string) []string {
+	res := make([]string, 0)
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// sortString return the sort of the query in the format of the sort parameter
+func (q ListQuery) sortString() string {
+	fields := make([]string, 0, len(q.Sort))
+
+	for _, f := range q.Sort {
+		if f.Desc {
+			fields = append(fields, "-"+f.Field)
+		} else {
+			fields = append(fields, f.Field)
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// After return the sort keys of the last item before the page of the cursor, or nil for the first page.
+// A cursor is valid only with the same sort of the query that returned it
+func (q ListQuery) After() (bson.A, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, utils.NewErrorf("%w: invalid cursor", utils.ErrInvalidListQuery)
+	}
+
+	var cursor listCursor
+	if err := bson.UnmarshalExtJSON(raw, true, &cursor); err != nil || len(cursor.Keys) == 0 {
+		return nil, utils.NewErrorf("%w: invalid cursor", utils.ErrInvalidListQuery)
+	}
+
+	if cursor.Sort != q.sortString() {
+		return nil, utils.NewErrorf("%w: the cursor was returned with another sort", utils.ErrInvalidListQuery)
+	}
+
+	return cursor.Keys, nil
+}
+
+// CursorAfter return the cursor of the page that starts after the item with the sort keys
+func (q ListQuery) CursorAfter(keys bson.A) string {
+	raw, _ := bson.MarshalExtJSON(listCursor{Sort: q.sortString(), Keys: keys}, true, false)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import "time"
+
+// HostMetrics contains the informations of a current host used by the metrics
+type HostMetrics struct {
+	Hostname     string    `json:"hostname" bson:"hostname"`
+	Location     string    `json:"location" bson:"location"`
+	Environment  string    `json:"environment" bson:"environment"`
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+	Technologies []string  `json:"technologies" bson:"technologies"`
+}
+
+// AlertsCount contains the number of alerts with a code and a severity
+type AlertsCount struct {
+	AlertCode     string `json:"alertCode" bson:"alertCode"`
+	AlertSeverity string `json:"alertSeverity" bson:"alertSeverity"`
+	Count         int    `json:"count" bson:"count"`
+}
+
+// HostsCountMetric contains the number of hosts of a location and environment that use a technology
+type HostsCountMetric struct {
+	Location    string
+	Environment string
+	Technology  string
+	Count       int
+}
+
+// HostDataAgeMetric contains the seconds elapsed since the last hostdata received from a host
+type HostDataAgeMetric struct {
+	Hostname    string
+	Location    string
+	Environment string
+	Age         float64
+}
+
+// Metrics contains the fleet, alert and licensing metrics
+type Metrics struct {
+	HostsCount        []HostsCountMetric
+	HostDataAge       []HostDataAgeMetric
+	OpenAlerts        []AlertsCount
+	LicenseCompliance []LicenseCompliance
+}
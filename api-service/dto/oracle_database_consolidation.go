@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package dto
+
+// Sources of the CPU demand of a database in a consolidation plan
+const (
+	// ConsolidationCPUSourceHistory is the 95th percentile of the CPU in the consumption metrics
+	ConsolidationCPUSourceHistory = "HISTORY"
+	// ConsolidationCPUSourceHostdata is the 95th percentile of the CPU in the consumptions of the latest hostdata
+	ConsolidationCPUSourceHostdata = "HOSTDATA"
+	// ConsolidationCPUSourceCPUCount is the cpu_count of the database, used without consumptions
+	ConsolidationCPUSourceCPUCount = "CPU_COUNT"
+)
+
+// OracleDatabaseConsolidationRequest contains the target host shapes and the filters of the Oracle databases to consolidate
+type OracleDatabaseConsolidationRequest struct {
+	Location    string `json:"location"`
+	Environment string `json:"environment"`
+	// Hostnames contains the hosts of the databases, all the hosts if it's empty
+	Hostnames []string                 `json:"hostnames"`
+	Shapes    []ConsolidationHostShape `json:"shapes"`
+	// CPUHeadroom contains the percentage of the cores of the targets left free
+	CPUHeadroom float64 `json:"cpuHeadroom"`
+	// MemoryHeadroom contains the percentage of the memory of the targets left free
+	MemoryHeadroom float64 `json:"memoryHeadroom"`
+	// HistoryDays contains the days of consumption metrics of the CPU of the databases, WorkloadDays by default
+	HistoryDays int `json:"historyDays"`
+}
+
+// ConsolidationHostShape contains the resources of a target host
+type ConsolidationHostShape struct {
+	Name     string `json:"name"`
+	CPUCores int    `json:"cpuCores"`
+	// Memory contains the memory in GB
+	Memory float64 `json:"memory"`
+	// Storage contains the storage in GB, 0 if it's unlimited
+	Storage    float64 `json:"storage"`
+	CoreFactor float64 `json:"coreFactor"`
+	// MaxHosts contains the maximum number of hosts of the shape, 0 if it's unlimited
+	MaxHosts int `json:"maxHosts"`
+}
+
+// OracleDatabaseConsolidationPlan contains the placements of the Oracle databases on the target hosts
+// and the processor licenses before and after the consolidation
+type OracleDatabaseConsolidationPlan struct {
+	Targets []ConsolidationTarget `json:"targets"`
+	// Unplaced contains the databases that don't fit in any shape, they stay on their hosts
+	Unplaced []ConsolidationDatabase `json:"unplaced"`
+	Sources  []ConsolidationSource   `json:"sources"`
+
+	CurrentProcessorLicenses float64 `json:"currentProcessorLicenses"`
+	PlannedProcessorLicenses float64 `json:"plannedProcessorLicenses"`
+	ProcessorLicensesSavings float64 `json:"processorLicensesSavings"`
+}
+
+// ConsolidationDatabase contains the resources required by an Oracle database
+type ConsolidationDatabase struct {
+	Hostname string `json:"hostname"`
+	Name     string `json:"name"`
+	// CPU contains the cores required
+	CPU       float64 `json:"cpu"`
+	CPUSource string  `json:"cpuSource"`
+	// Memory contains the SGA and PGA in GB
+	Memory float64 `json:"memory"`
+	// Storage contains the datafiles in GB
+	Storage float64 `json:"storage"`
+}
+
+// ConsolidationTarget contains a target host of the plan and the databases placed on it
+type ConsolidationTarget struct {
+	Name              string                  `json:"name"`
+	Shape             string                  `json:"shape"`
+	CPUCores          int                     `json:"cpuCores"`
+	CoreFactor        float64                 `json:"coreFactor"`
+	ProcessorLicenses float64                 `json:"processorLicenses"`
+	CPU               float64                 `json:"cpu"`
+	Memory            float64                 `json:"memory"`
+	Storage           float64                 `json:"storage"`
+	Databases         []ConsolidationDatabase `json:"databases"`
+}
+
+// ConsolidationSource contains a current host of the databases and its processor licenses
+type ConsolidationSource struct {
+	Hostname          string  `json:"hostname"`
+	CPUCores          int     `json:"cpuCores"`
+	ProcessorLicenses float64 `json:"processorLicenses"`
+	// Retained is true if some databases aren't placed and the host is kept
+	Retained bool `json:"retained"`
+}
@@ -88,6 +88,8 @@ type GetOracleDatabaseContractsFilter struct {
 	AvailableLicensesPerCoreGTE int
 	AvailableLicensesPerUserLTE int
 	AvailableLicensesPerUserGTE int
+	// Location contains the locations, comma separated, of the hosts of the contracts
+	Location string
 }
 
 func NewGetOracleDatabaseContractsFilter() GetOracleDatabaseContractsFilter {
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Types of the changes of a simulation of the Oracle Database licenses
+const (
+	// SimulationChangeHostCores set the cores of Hostname to CPUCores
+	SimulationChangeHostCores = "HOST_CORES"
+	// SimulationChangeHostCoreFactor set the core factor of the databases of Hostname to CoreFactor
+	SimulationChangeHostCoreFactor = "HOST_CORE_FACTOR"
+	// SimulationChangeClusterCores set the cores of Cluster to CPUCores
+	SimulationChangeClusterCores = "CLUSTER_CORES"
+	// SimulationChangeHostCluster move Hostname in Cluster, or out of any cluster if Cluster is empty
+	SimulationChangeHostCluster = "HOST_CLUSTER"
+	// SimulationChangeMoveDatabase move DatabaseName from Hostname to TargetHostname
+	SimulationChangeMoveDatabase = "MOVE_DATABASE"
+	// SimulationChangeEnableOption enable LicenseTypeID on DatabaseName of Hostname, or on all its databases if DatabaseName is empty
+	SimulationChangeEnableOption = "ENABLE_OPTION"
+	// SimulationChangeDisableOption disable LicenseTypeID on DatabaseName of Hostname, or on all its databases if DatabaseName is empty
+	SimulationChangeDisableOption = "DISABLE_OPTION"
+	// SimulationChangeContractHosts set the hosts associated to ContractID to Hosts
+	SimulationChangeContractHosts = "CONTRACT_HOSTS"
+)
+
+// OracleDatabaseLicensesSimulation contains the hypothetical changes, applied in order, to simulate
+type OracleDatabaseLicensesSimulation struct {
+	Changes []OracleDatabaseLicensesSimulationChange `json:"changes"`
+}
+
+// OracleDatabaseLicensesSimulationChange contains a hypothetical change. The fields used depend on its type
+type OracleDatabaseLicensesSimulationChange struct {
+	Type           string             `json:"type"`
+	Hostname       string             `json:"hostname,omitempty"`
+	TargetHostname string             `json:"targetHostname,omitempty"`
+	DatabaseName   string             `json:"databaseName,omitempty"`
+	Cluster        string             `json:"cluster,omitempty"`
+	CPUCores       int                `json:"cpuCores,omitempty"`
+	CoreFactor     float64            `json:"coreFactor,omitempty"`
+	LicenseTypeID  string             `json:"licenseTypeID,omitempty"`
+	ContractID     primitive.ObjectID `json:"contractID,omitempty"`
+	Hosts          []string           `json:"hosts,omitempty"`
+}
+
+// LicenseComplianceDiff contains the compliance of a license type before and after the simulated changes
+type LicenseComplianceDiff struct {
+	LicenseTypeID   string `json:"licenseTypeID"`
+	ItemDescription string `json:"itemDescription"`
+	Metric          string `json:"metric"`
+
+	Before LicenseCompliance `json:"before"`
+	After  LicenseCompliance `json:"after"`
+
+	ConsumedDelta   float64 `json:"consumedDelta"`
+	CoveredDelta    float64 `json:"coveredDelta"`
+	ComplianceDelta float64 `json:"complianceDelta"`
+	AvailableDelta  float64 `json:"availableDelta"`
+}
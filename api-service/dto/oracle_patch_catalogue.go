@@ -0,0 +1,223 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package dto
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// OraclePatchCatalogueImport contains the parts of the Oracle patch catalogue to replace, the nil ones are kept
+type OraclePatchCatalogueImport struct {
+	Releases []model.OraclePatchRelease
+	Versions []model.OracleVersionSupport
+}
+
+// oraclePatchCatalogueRecords contains the catalogue as written in the imported files, with the dates as strings
+type oraclePatchCatalogueRecords struct {
+	Releases []map[string]string `json:"releases"`
+	Versions []map[string]string `json:"versions"`
+}
+
+// GetOraclePatchCatalogueImport read the catalogue from the body of the request: a JSON object with the releases and
+// the versions or, with the text/csv content type, a CSV file with the releases or with the versions
+func GetOraclePatchCatalogueImport(r *http.Request) (*OraclePatchCatalogueImport, error) {
+	records := oraclePatchCatalogueRecords{}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		rows, err := readCSVRecords(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) > 0 {
+			if _, ok := rows[0]["patchNumber"]; ok {
+				records.Releases = rows
+			} else {
+				records.Versions = rows
+			}
+		}
+	} else if err := utils.Decode(r.Body, &records); err != nil {
+		return nil, utils.NewErrorf("%w: %s", utils.ErrInvalidOraclePatchCatalogue, err)
+	}
+
+	if records.Releases == nil && records.Versions == nil {
+		return nil, utils.NewErrorf("%w: neither releases nor versions", utils.ErrInvalidOraclePatchCatalogue)
+	}
+
+	catalogue := &OraclePatchCatalogueImport{}
+
+	if records.Releases != nil {
+		catalogue.Releases = make([]model.OraclePatchRelease, 0, len(records.Releases))
+		patchNumbers := make(map[string]bool, len(records.Releases))
+
+		for i, record := range records.Releases {
+			release, err := parseOraclePatchRelease(record)
+			if err != nil {
+				return nil, utils.NewErrorf("%w: release %d: %s", utils.ErrInvalidOraclePatchCatalogue, i+1, err)
+			}
+
+			if patchNumbers[release.PatchNumber] {
+				return nil, utils.NewErrorf("%w: duplicated patch number %s", utils.ErrInvalidOraclePatchCatalogue, release.PatchNumber)
+			}
+
+			patchNumbers[release.PatchNumber] = true
+			catalogue.Releases = append(catalogue.Releases, *release)
+		}
+	}
+
+	if records.Versions != nil {
+		catalogue.Versions = make([]model.OracleVersionSupport, 0, len(records.Versions))
+		versions := make(map[string]bool, len(records.Versions))
+
+		for i, record := range records.Versions {
+			support, err := parseOracleVersionSupport(record)
+			if err != nil {
+				return nil, utils.NewErrorf("%w: version %d: %s", utils.ErrInvalidOraclePatchCatalogue, i+1, err)
+			}
+
+			if versions[support.Version] {
+				return nil, utils.NewErrorf("%w: duplicated version %s", utils.ErrInvalidOraclePatchCatalogue, support.Version)
+			}
+
+			versions[support.Version] = true
+			catalogue.Versions = append(catalogue.Versions, *support)
+		}
+	}
+
+	return catalogue, nil
+}
+
+// readCSVRecords return the rows of the CSV as maps from the names in the header to the values
+func readCSVRecords(body io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, utils.NewErrorf("%w: %s", utils.ErrInvalidOraclePatchCatalogue, err)
+	}
+
+	if len(lines) == 0 {
+		return nil, utils.NewErrorf("%w: empty CSV", utils.ErrInvalidOraclePatchCatalogue)
+	}
+
+	rows := make([]map[string]string, 0, len(lines)-1)
+
+	for _, line := range lines[1:] {
+		row := make(map[string]string, len(lines[0]))
+		for i, name := range lines[0] {
+			row[strings.TrimSpace(name)] = line[i]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseOraclePatchRelease(record map[string]string) (*model.OraclePatchRelease, error) {
+	release := &model.OraclePatchRelease{
+		PatchNumber: strings.TrimSpace(record["patchNumber"]),
+		Type:        strings.ToUpper(strings.TrimSpace(record["type"])),
+		Version:     strings.TrimSpace(record["version"]),
+		Release:     strings.TrimSpace(record["release"]),
+		Description: strings.TrimSpace(record["description"]),
+	}
+
+	if release.PatchNumber == "" || release.Version == "" {
+		return nil, errors.New("patchNumber and version are required")
+	}
+
+	if !utils.Contains(model.OraclePatchTypes, release.Type) {
+		return nil, fmt.Errorf("invalid type %q", release.Type)
+	}
+
+	var err error
+	if release.ReleaseDate, err = parseCatalogueDate(record["releaseDate"]); err != nil {
+		return nil, err
+	}
+
+	if release.ReleaseDate.IsZero() {
+		return nil, errors.New("releaseDate is required")
+	}
+
+	return release, nil
+}
+
+func parseOracleVersionSupport(record map[string]string) (*model.OracleVersionSupport, error) {
+	support := &model.OracleVersionSupport{
+		Version: strings.TrimSpace(record["version"]),
+	}
+
+	if support.Version == "" {
+		return nil, errors.New("version is required")
+	}
+
+	var err error
+	if support.PremierSupportEnd, err = parseCatalogueDate(record["premierSupportEnd"]); err != nil {
+		return nil, err
+	}
+
+	if support.ExtendedSupportEnd, err = parseCatalogueDate(record["extendedSupportEnd"]); err != nil {
+		return nil, err
+	}
+
+	return support, nil
+}
+
+// parseCatalogueDate parse a date like 2006-01-02 or a RFC3339 timestamp, a zero time if it's empty
+func parseCatalogueDate(date string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", date)
+	}
+
+	return t, nil
+}
+
+// OraclePatchAdvisorSummary contains the number of Oracle databases by position of their patches in the catalogue
+type OraclePatchAdvisorSummary struct {
+	Databases int `json:"databases"`
+	// UpToDate contains the databases with the latest release of their version
+	UpToDate int `json:"upToDate"`
+	// Behind contains the databases with newer releases of their version
+	Behind int `json:"behind"`
+	// NotInCatalogue contains the databases whose version hasn't releases in the catalogue
+	NotInCatalogue int `json:"notInCatalogue"`
+	// OutOfPremierSupport contains the databases whose version is in extended support or unsupported
+	OutOfPremierSupport int `json:"outOfPremierSupport"`
+	// Unsupported contains the databases whose version is out of extended support
+	Unsupported int `json:"unsupported"`
+}
@@ -17,6 +17,8 @@ package dto
 
 import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/model"
 )
 
 type PatchAdvisor struct {
@@ -33,6 +35,9 @@ type PatchAdvisor struct {
 	FourMonths   bool               `json:"fourMonths" bson:"fourMonths"`
 	SixMonths    bool               `json:"sixMonths" bson:"sixMonths"`
 	TwelveMonths bool               `json:"twelveMonths" bson:"twelveMonths"`
+
+	// OraclePatchAdvice contains the position of the installed patch in the Oracle patch catalogue
+	model.OraclePatchAdvice `bson:",inline"`
 }
 
 type PatchAdvisors []PatchAdvisor
@@ -0,0 +1,120 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package service is a package that provides methods for querying data
+package service
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (as *APIService) AddAlertRoutingRule(rule model.AlertRoutingRule) (*model.AlertRoutingRule, error) {
+	if err := as.validateAlertRoutingRule(rule); err != nil {
+		return nil, err
+	}
+
+	rule.ID = as.NewObjectID()
+
+	if err := as.Database.AddAlertRoutingRule(rule); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityAlertRoutingRule, rule.ID.Hex(), model.AuditActionCreate, nil, rule)
+
+	return &rule, nil
+}
+
+func (as *APIService) UpdateAlertRoutingRule(rule model.AlertRoutingRule) (*model.AlertRoutingRule, error) {
+	if err := as.validateAlertRoutingRule(rule); err != nil {
+		return nil, err
+	}
+
+	var before *model.AlertRoutingRule
+	if as.isAudited() {
+		before, _ = as.Database.GetAlertRoutingRule(rule.ID)
+	}
+
+	if err := as.Database.UpdateAlertRoutingRule(rule); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityAlertRoutingRule, rule.ID.Hex(), model.AuditActionUpdate, before, rule)
+
+	return &rule, nil
+}
+
+func (as *APIService) GetAlertRoutingRules() ([]model.AlertRoutingRule, error) {
+	return as.Database.GetAlertRoutingRules()
+}
+
+func (as *APIService) GetAlertRoutingRule(id primitive.ObjectID) (*model.AlertRoutingRule, error) {
+	return as.Database.GetAlertRoutingRule(id)
+}
+
+func (as *APIService) DeleteAlertRoutingRule(id primitive.ObjectID) error {
+	var before *model.AlertRoutingRule
+	if as.isAudited() {
+		before, _ = as.Database.GetAlertRoutingRule(id)
+	}
+
+	if err := as.Database.DeleteAlertRoutingRule(id); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityAlertRoutingRule, id.Hex(), model.AuditActionDelete, before, nil)
+
+	return nil
+}
+
+// validateAlertRoutingRule checks the rule and that its notifiers are configured in the alert service
+func (as *APIService) validateAlertRoutingRule(rule model.AlertRoutingRule) error {
+	if !rule.IsValid() {
+		return utils.NewError(utils.ErrInvalidAlertRoutingRule, "Invalid rule")
+	}
+
+	notifiers := as.getAlertNotifierNames()
+
+	for _, n := range rule.Notifiers {
+		if !utils.Contains(notifiers, n) {
+			return utils.NewError(utils.ErrInvalidAlertRoutingRule, fmt.Sprintf("Unknown notifier %q", n))
+		}
+	}
+
+	return nil
+}
+
+func (as *APIService) getAlertNotifierNames() []string {
+	conf := as.Config.AlertService.Notifiers
+	names := make([]string, 0)
+
+	for _, n := range conf.Webhooks {
+		names = append(names, n.Name)
+	}
+
+	for _, n := range conf.Chats {
+		names = append(names, n.Name)
+	}
+
+	for _, n := range conf.Syslogs {
+		names = append(names, n.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestAddAlertRoutingRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		NewObjectID: utils.NewObjectIDForTests(),
+		Config: config.Configuration{
+			AlertService: config.AlertService{
+				Notifiers: config.Notifiers{
+					Chats:   []config.ChatNotifier{{Name: "licensing"}},
+					Syslogs: []config.SyslogNotifier{{Name: "siem"}},
+				},
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		rule := model.AlertRoutingRule{
+			Name:       "licensing",
+			Enabled:    true,
+			Categories: []string{model.AlertCategoryLicense},
+			Notifiers:  []string{"licensing", "siem"},
+		}
+
+		expected := rule
+		expected.ID = utils.Str2oid("000000000000000000000001")
+
+		db.EXPECT().AddAlertRoutingRule(expected).Return(nil).Times(1)
+
+		actual, err := as.AddAlertRoutingRule(rule)
+		require.NoError(t, err)
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Unknown notifier", func(t *testing.T) {
+		rule := model.AlertRoutingRule{
+			Name:      "licensing",
+			Notifiers: []string{"pippo"},
+		}
+
+		_, err := as.AddAlertRoutingRule(rule)
+		assert.ErrorIs(t, err, utils.ErrInvalidAlertRoutingRule)
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		rule := model.AlertRoutingRule{
+			Name:            "licensing",
+			Codes:           []string{"PIPPO"},
+			EmailRecipients: []string{"licensing@ercole.test"},
+		}
+
+		_, err := as.AddAlertRoutingRule(rule)
+		assert.ErrorIs(t, err, utils.ErrInvalidAlertRoutingRule)
+	})
+}
+
+func TestUpdateAlertRoutingRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	rule := model.AlertRoutingRule{
+		ID:              utils.Str2oid("000000000000000000000001"),
+		Name:            "dba",
+		Categories:      []string{model.AlertCategoryAgent},
+		EmailRecipients: []string{"dba@ercole.test"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		db.EXPECT().UpdateAlertRoutingRule(rule).Return(nil).Times(1)
+
+		actual, err := as.UpdateAlertRoutingRule(rule)
+		require.NoError(t, err)
+		assert.Equal(t, rule, *actual)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		db.EXPECT().UpdateAlertRoutingRule(rule).Return(utils.ErrNotFound).Times(1)
+
+		_, err := as.UpdateAlertRoutingRule(rule)
+		assert.ErrorIs(t, err, utils.ErrNotFound)
+	})
+}
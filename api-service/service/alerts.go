@@ -17,6 +17,7 @@
 package service
 
 import (
+	"strings"
 	"time"
 
 	"github.com/360EntSecGroup-Skylar/excelize"
@@ -35,6 +36,10 @@ func (as *APIService) SearchAlerts(alertFilter alert_filter.Alert) (*dto.Paginat
 	return as.Database.SearchAlerts(alertFilter)
 }
 
+func (as *APIService) ListAlerts(alertFilter alert_filter.Alert, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListAlerts(alertFilter, q)
+}
+
 func (as *APIService) GetAlerts(status string, from, to time.Time, filter dto.GlobalFilter) ([]map[string]interface{}, error) {
 	alerts, err := as.Database.GetAlerts(filter.Location, filter.Environment, status, from, to, filter.OlderThan)
 	if err != nil {
@@ -81,33 +86,131 @@ func (as *APIService) SearchAlertsAsXLSX(status string, from, to time.Time, filt
 	return sheets, nil
 }
 
-func (as *APIService) AckAlerts(alertsFilter dto.AlertsFilter) error {
-	if alertsFilter.AlertCode != nil && *alertsFilter.AlertCode == model.AlertCodeNoData {
-		return utils.NewErrorf("%w: you are trying to ack alerts with code: %s",
-			utils.ErrInvalidAck,
-			model.AlertCodeNoData)
+func (as *APIService) AckAlerts(alertsFilter dto.AlertsFilter, username string) error {
+	return as.UpdateAlertsStatus(alertsFilter, model.AlertStatusAck, username)
+}
+
+func (as *APIService) RemoveAlertsNODATA(alertsFilter dto.AlertsFilter) error {
+	return as.Database.RemoveAlertsNODATA(alertsFilter)
+}
+
+// UpdateAlertsStatus change the status of the alerts selected by the filter. The changes requested
+// without a user, as the ones of the data-service, are recorded in the history as made by ercole
+func (as *APIService) UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus, username string) error {
+	if username == "" {
+		username = model.AlertSystemUsername
 	}
 
-	if alertsFilter.AlertCode == nil {
-		count, err := as.Database.CountAlertsNODATA(alertsFilter)
-		if err != nil {
-			return err
-		}
+	entry := model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: username,
+		Action:   model.AlertActionStatusChange,
+		Status:   newStatus,
+	}
 
-		if count != 0 {
-			return utils.NewErrorf("%w: you are trying to ack alerts with code: %s",
-				utils.ErrInvalidAck,
-				model.AlertCodeNoData)
+	if err := as.Database.UpdateAlertsStatus(alertsFilter, newStatus, entry); err != nil {
+		return err
+	}
+
+	as.auditAlertsStatus(alertsFilter, newStatus)
+
+	return nil
+}
+
+// auditAlertsStatus record the change of status of the alerts selected by the filter, one entry for each
+// alert when they are selected by id
+func (as *APIService) auditAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string) {
+	type alertsStatus struct {
+		Filter      *dto.AlertsFilter `json:"filter,omitempty"`
+		AlertStatus string            `json:"alertStatus"`
+	}
+
+	if len(alertsFilter.IDs) == 0 {
+		as.audit(model.AuditEntityAlert, "", model.AuditActionUpdate, nil, alertsStatus{Filter: &alertsFilter, AlertStatus: newStatus})
+		return
+	}
+
+	for _, id := range alertsFilter.IDs {
+		as.audit(model.AuditEntityAlert, id.Hex(), model.AuditActionUpdate, nil, alertsStatus{AlertStatus: newStatus})
+	}
+}
+
+func (as *APIService) GetAlert(id primitive.ObjectID) (*model.Alert, error) {
+	return as.Database.GetAlert(id)
+}
+
+func (as *APIService) UpdateAlertStatus(id primitive.ObjectID, status string, snoozedUntil *time.Time, comment, username string) (*model.Alert, error) {
+	alert, err := as.Database.GetAlert(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !alert.CanChangeStatus(status) {
+		return nil, utils.NewErrorf("%w: from %s to %s", utils.ErrInvalidAlertStatusChange, alert.AlertStatus, status)
+	}
+
+	if status == model.AlertStatusSnoozed {
+		if snoozedUntil == nil || !snoozedUntil.After(as.TimeNow()) {
+			return nil, utils.NewErrorf("%w: snoozedUntil must be in the future", utils.ErrInvalidAlertStatusChange)
 		}
+	} else {
+		snoozedUntil = nil
 	}
 
-	return as.Database.UpdateAlertsStatus(alertsFilter, model.AlertStatusAck)
+	entry := model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: username,
+		Action:   model.AlertActionStatusChange,
+		Status:   status,
+		Comment:  strings.TrimSpace(comment),
+	}
+
+	if err := as.Database.UpdateAlertStatus(id, status, snoozedUntil, entry); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityAlert, id.Hex(), model.AuditActionUpdate, nil, entry)
+
+	return as.Database.GetAlert(id)
 }
 
-func (as *APIService) RemoveAlertsNODATA(alertsFilter dto.AlertsFilter) error {
-	return as.Database.RemoveAlertsNODATA(alertsFilter)
+func (as *APIService) AssignAlert(id primitive.ObjectID, assignee, username string) (*model.Alert, error) {
+	assignee = strings.TrimSpace(assignee)
+
+	entry := model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: username,
+		Action:   model.AlertActionAssign,
+		Assignee: assignee,
+	}
+
+	if err := as.Database.UpdateAlertAssignee(id, assignee, entry); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityAlert, id.Hex(), model.AuditActionUpdate, nil, entry)
+
+	return as.Database.GetAlert(id)
 }
 
-func (as *APIService) UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string) error {
-	return as.Database.UpdateAlertsStatus(alertsFilter, newStatus)
+func (as *APIService) CommentAlert(id primitive.ObjectID, comment, username string) (*model.Alert, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, utils.NewErrorf("%w: the comment is empty", utils.ErrInvalidAlertComment)
+	}
+
+	entry := model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: username,
+		Action:   model.AlertActionComment,
+		Comment:  comment,
+	}
+
+	if err := as.Database.AddAlertHistoryEntry(id, entry); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityAlert, id.Hex(), model.AuditActionUpdate, nil, entry)
+
+	return as.Database.GetAlert(id)
 }
@@ -126,8 +126,6 @@ func TestAcknowledgeAlerts(t *testing.T) {
 		},
 	}
 
-	var count int64
-
 	for _, tc := range testCases {
 		mockCtrl := gomock.NewController(t)
 		defer func() {
@@ -137,41 +135,27 @@ func TestAcknowledgeAlerts(t *testing.T) {
 		db := NewMockMongoDatabaseInterface(mockCtrl)
 		as := APIService{
 			Database: db,
+			TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		}
 
-		db.EXPECT().CountAlertsNODATA(tc.filter).Return(count, nil)
-		db.EXPECT().UpdateAlertsStatus(tc.filter, model.AlertStatusAck).Return(tc.expErr)
+		entry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T14:02:03Z"),
+			Username: "mario",
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusAck,
+		}
+		db.EXPECT().UpdateAlertsStatus(tc.filter, model.AlertStatusAck, entry).Return(tc.expErr)
 
-		actErr := as.AckAlerts(tc.filter)
+		actErr := as.AckAlerts(tc.filter, "mario")
 		assert.Equal(t, tc.expErr, actErr)
 	}
 }
 
-func TestAcknowledgeAlerts_FailAlertCodeNoData(t *testing.T) {
+func TestAcknowledgeAlerts_AlertCodeNoData(t *testing.T) {
 	a_ack := dto.AlertsFilter{
 		AlertCode: utils.Str2ptr(model.AlertCodeNoData),
 	}
 
-	dataErr := utils.NewErrorf("%w: you are trying to ack alerts with code: %s",
-		utils.ErrInvalidAck,
-		model.AlertCodeNoData)
-
-	mockCtrl := gomock.NewController(t)
-	defer func() {
-		mockCtrl.Finish()
-	}()
-
-	as := APIService{}
-
-	actErr := as.AckAlerts(a_ack)
-	require.Error(t, actErr, dataErr.Message)
-}
-
-func TestAcknowledgeAlerts_FailCountAlertsNoData(t *testing.T) {
-	a_ack := dto.AlertsFilter{}
-
-	var count int64
-
 	mockCtrl := gomock.NewController(t)
 	defer func() {
 		mockCtrl.Finish()
@@ -180,32 +164,19 @@ func TestAcknowledgeAlerts_FailCountAlertsNoData(t *testing.T) {
 	db := NewMockMongoDatabaseInterface(mockCtrl)
 	as := APIService{
 		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 	}
 
-	db.EXPECT().CountAlertsNODATA(a_ack).Return(count, aerrMock)
-
-	actErr := as.AckAlerts(a_ack)
-	require.Equal(t, aerrMock, actErr)
-}
-
-func TestAcknowledgeAlerts_FailAckAlertsNoData(t *testing.T) {
-	a_ack := dto.AlertsFilter{}
-	var count int64 = 10
-
-	mockCtrl := gomock.NewController(t)
-	defer func() {
-		mockCtrl.Finish()
-	}()
-
-	db := NewMockMongoDatabaseInterface(mockCtrl)
-	as := APIService{
-		Database: db,
+	entry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-05T14:02:03Z"),
+		Username: model.AlertSystemUsername,
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusAck,
 	}
+	db.EXPECT().UpdateAlertsStatus(a_ack, model.AlertStatusAck, entry).Return(nil)
 
-	db.EXPECT().CountAlertsNODATA(a_ack).Return(count, nil)
-
-	actErr := as.AckAlerts(a_ack)
-	require.Error(t, actErr)
+	actErr := as.AckAlerts(a_ack, "")
+	require.NoError(t, actErr)
 }
 
 func TestSearchAlertsAsXLSX_Success(t *testing.T) {
@@ -307,11 +278,185 @@ func TestUpdateAlertsStatus_Success(t *testing.T) {
 		db := NewMockMongoDatabaseInterface(mockCtrl)
 		as := APIService{
 			Database: db,
+			TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 		}
 
-		db.EXPECT().UpdateAlertsStatus(tc.filter, model.AlertStatusDismissed).Return(tc.expErr)
+		entry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T14:02:03Z"),
+			Username: "mario",
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusDismissed,
+		}
+		db.EXPECT().UpdateAlertsStatus(tc.filter, model.AlertStatusDismissed, entry).Return(tc.expErr)
 
-		actErr := as.UpdateAlertsStatus(tc.filter, model.AlertStatusDismissed)
+		actErr := as.UpdateAlertsStatus(tc.filter, model.AlertStatusDismissed, "mario")
 		assert.Equal(t, tc.expErr, actErr)
 	}
 }
+
+func TestUpdateAlertStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+	alert := model.Alert{
+		ID:          id,
+		AlertCode:   model.AlertCodeNewServer,
+		AlertStatus: model.AlertStatusNew,
+	}
+
+	t.Run("Resolve", func(t *testing.T) {
+		resolved := alert
+		resolved.AlertStatus = model.AlertStatusResolved
+
+		entry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T14:02:03Z"),
+			Username: "pippo",
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusResolved,
+			Comment:  "fixed",
+		}
+
+		gomock.InOrder(
+			db.EXPECT().GetAlert(id).Return(&alert, nil),
+			db.EXPECT().UpdateAlertStatus(id, model.AlertStatusResolved, nil, entry).Return(nil),
+			db.EXPECT().GetAlert(id).Return(&resolved, nil),
+		)
+
+		actual, err := as.UpdateAlertStatus(id, model.AlertStatusResolved, nil, " fixed ", "pippo")
+		require.NoError(t, err)
+		assert.Equal(t, &resolved, actual)
+	})
+
+	t.Run("Snooze", func(t *testing.T) {
+		snoozedUntil := utils.P("2019-11-06T14:02:03Z")
+		entry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T14:02:03Z"),
+			Username: "pippo",
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusSnoozed,
+		}
+
+		gomock.InOrder(
+			db.EXPECT().GetAlert(id).Return(&alert, nil),
+			db.EXPECT().UpdateAlertStatus(id, model.AlertStatusSnoozed, &snoozedUntil, entry).Return(nil),
+			db.EXPECT().GetAlert(id).Return(&alert, nil),
+		)
+
+		_, err := as.UpdateAlertStatus(id, model.AlertStatusSnoozed, &snoozedUntil, "", "pippo")
+		require.NoError(t, err)
+	})
+
+	t.Run("Snooze in the past", func(t *testing.T) {
+		snoozedUntil := utils.P("2019-11-04T14:02:03Z")
+
+		db.EXPECT().GetAlert(id).Return(&alert, nil)
+
+		_, err := as.UpdateAlertStatus(id, model.AlertStatusSnoozed, &snoozedUntil, "", "pippo")
+		assert.ErrorIs(t, err, utils.ErrInvalidAlertStatusChange)
+	})
+
+	t.Run("Same status", func(t *testing.T) {
+		db.EXPECT().GetAlert(id).Return(&alert, nil)
+
+		_, err := as.UpdateAlertStatus(id, model.AlertStatusNew, nil, "", "pippo")
+		assert.ErrorIs(t, err, utils.ErrInvalidAlertStatusChange)
+	})
+
+	t.Run("Ack NO_DATA", func(t *testing.T) {
+		noData := alert
+		noData.AlertCode = model.AlertCodeNoData
+
+		acked := noData
+		acked.AlertStatus = model.AlertStatusAck
+
+		entry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T14:02:03Z"),
+			Username: "pippo",
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusAck,
+		}
+
+		gomock.InOrder(
+			db.EXPECT().GetAlert(id).Return(&noData, nil),
+			db.EXPECT().UpdateAlertStatus(id, model.AlertStatusAck, nil, entry).Return(nil),
+			db.EXPECT().GetAlert(id).Return(&acked, nil),
+		)
+
+		actual, err := as.UpdateAlertStatus(id, model.AlertStatusAck, nil, "", "pippo")
+		require.NoError(t, err)
+		assert.Equal(t, &acked, actual)
+	})
+}
+
+func TestAssignAlert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+	alert := model.Alert{ID: id, Assignee: "dba"}
+	entry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-05T14:02:03Z"),
+		Username: "pippo",
+		Action:   model.AlertActionAssign,
+		Assignee: "dba",
+	}
+
+	db.EXPECT().UpdateAlertAssignee(id, "dba", entry).Return(nil)
+	db.EXPECT().GetAlert(id).Return(&alert, nil)
+
+	actual, err := as.AssignAlert(id, "dba ", "pippo")
+	require.NoError(t, err)
+	assert.Equal(t, &alert, actual)
+}
+
+func TestCommentAlert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+	}
+
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+
+	t.Run("Success", func(t *testing.T) {
+		entry := model.AlertHistoryEntry{
+			Date:     utils.P("2019-11-05T14:02:03Z"),
+			Username: "pippo",
+			Action:   model.AlertActionComment,
+			Comment:  "checked",
+		}
+		alert := model.Alert{ID: id, History: []model.AlertHistoryEntry{entry}}
+
+		db.EXPECT().AddAlertHistoryEntry(id, entry).Return(nil)
+		db.EXPECT().GetAlert(id).Return(&alert, nil)
+
+		actual, err := as.CommentAlert(id, "checked", "pippo")
+		require.NoError(t, err)
+		assert.Equal(t, &alert, actual)
+	})
+
+	t.Run("Empty comment", func(t *testing.T) {
+		_, err := as.CommentAlert(id, "  ", "pippo")
+		assert.ErrorIs(t, err, utils.ErrInvalidAlertComment)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		db.EXPECT().AddAlertHistoryEntry(id, gomock.Any()).Return(utils.NewError(utils.ErrAlertNotFound, "DB ERROR"))
+
+		_, err := as.CommentAlert(id, "checked", "pippo")
+		assert.ErrorIs(t, err, utils.ErrAlertNotFound)
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package service is a package that provides methods for querying data
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	cr "github.com/ercole-io/ercole/v2/utils/crypto"
+)
+
+// apiTokenLastUsedResolution is how often the last use of a token is saved, to not write at every request
+const apiTokenLastUsedResolution = time.Minute
+
+func (as *APIService) CreateAPIToken(token model.APIToken, username string) (*dto.APITokenCreated, error) {
+	if strings.TrimSpace(token.Name) == "" || len(token.Groups) == 0 {
+		return nil, utils.NewError(utils.ErrInvalidAPIToken, "Name and groups are mandatory")
+	}
+
+	now := as.TimeNow()
+
+	if token.IsExpired(now) {
+		return nil, utils.NewError(utils.ErrInvalidAPIToken, "Expiration date must be in the future")
+	}
+
+	for _, groupName := range token.Groups {
+		if groupName == model.GroupAdmin {
+			continue
+		}
+
+		if _, err := as.Database.GetGroup(groupName); errors.Is(err, utils.ErrGroupNotFound) {
+			return nil, utils.NewErrorf("%w: unknown group %q", utils.ErrInvalidAPIToken, groupName)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	plain, err := cr.GenerateAPIToken()
+	if err != nil {
+		return nil, utils.NewError(err, "Can't generate the token")
+	}
+
+	token.ID = as.NewObjectID()
+	token.TokenHash = cr.HashAPIToken(plain)
+	token.LastUsedAt = nil
+	token.CreatedAt = now
+	token.CreatedBy = username
+
+	if err := as.Database.InsertAPIToken(token); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityAPIToken, token.ID.Hex(), model.AuditActionCreate, nil, token)
+
+	return &dto.APITokenCreated{APIToken: token, Token: plain}, nil
+}
+
+func (as *APIService) GetAPITokens() ([]model.APIToken, error) {
+	return as.Database.GetAPITokens()
+}
+
+func (as *APIService) DeleteAPIToken(id primitive.ObjectID) error {
+	var before *model.APIToken
+	if as.isAudited() {
+		before, _ = as.Database.GetAPIToken(id)
+	}
+
+	if err := as.Database.DeleteAPIToken(id); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityAPIToken, id.Hex(), model.AuditActionDelete, before, nil)
+
+	return nil
+}
+
+// AuthenticateAPIToken return the user impersonated by the token if it's valid and not expired
+func (as *APIService) AuthenticateAPIToken(plain string) (*model.User, error) {
+	token, err := as.Database.GetAPITokenByHash(cr.HashAPIToken(plain))
+	if errors.Is(err, utils.ErrNotFound) {
+		return nil, utils.ErrInvalidToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := as.TimeNow()
+
+	if token.IsExpired(now) {
+		return nil, utils.ErrInvalidToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenLastUsedResolution {
+		if err := as.Database.UpdateAPITokenLastUsed(token.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.User{Username: token.Username(), Groups: token.Groups}, nil
+}
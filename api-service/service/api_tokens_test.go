@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	cr "github.com/ercole-io/ercole/v2/utils/crypto"
+)
+
+func TestCreateAPIToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		TimeNow:     utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		token := model.APIToken{
+			Name:   "licenses-job",
+			Groups: []string{"licensing"},
+		}
+
+		var inserted model.APIToken
+
+		db.EXPECT().GetGroup("licensing").Return(&model.Group{Name: "licensing"}, nil).Times(1)
+		db.EXPECT().InsertAPIToken(gomock.Any()).
+			DoAndReturn(func(token model.APIToken) error {
+				inserted = token
+				return nil
+			}).Times(1)
+
+		actual, err := as.CreateAPIToken(token, "admin")
+		require.NoError(t, err)
+
+		assert.Equal(t, utils.Str2oid("000000000000000000000001"), actual.ID)
+		assert.Equal(t, "admin", actual.CreatedBy)
+		assert.Equal(t, utils.P("2023-05-01T10:00:00Z"), actual.CreatedAt)
+		assert.True(t, len(actual.Token) > len(cr.APITokenPrefix))
+		assert.Equal(t, cr.HashAPIToken(actual.Token), inserted.TokenHash)
+		assert.Equal(t, actual.APIToken, inserted)
+	})
+
+	t.Run("Missing groups", func(t *testing.T) {
+		_, err := as.CreateAPIToken(model.APIToken{Name: "licenses-job"}, "admin")
+		assert.ErrorIs(t, err, utils.ErrInvalidAPIToken)
+	})
+
+	t.Run("Unknown group", func(t *testing.T) {
+		token := model.APIToken{
+			Name:   "licenses-job",
+			Groups: []string{model.GroupAdmin, "unknown"},
+		}
+
+		db.EXPECT().GetGroup("unknown").
+			Return(nil, utils.NewError(utils.ErrGroupNotFound, "DB ERROR")).Times(1)
+
+		_, err := as.CreateAPIToken(token, "admin")
+		assert.ErrorIs(t, err, utils.ErrInvalidAPIToken)
+	})
+
+	t.Run("Already expired", func(t *testing.T) {
+		expiresAt := utils.P("2023-04-01T10:00:00Z")
+		token := model.APIToken{
+			Name:      "licenses-job",
+			Groups:    []string{"licensing"},
+			ExpiresAt: &expiresAt,
+		}
+
+		_, err := as.CreateAPIToken(token, "admin")
+		assert.ErrorIs(t, err, utils.ErrInvalidAPIToken)
+	})
+}
+
+func TestAuthenticateAPIToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+	}
+
+	plain := cr.APITokenPrefix + "0123456789abcdef"
+	token := model.APIToken{
+		ID:     utils.Str2oid("000000000000000000000001"),
+		Name:   "licenses-job",
+		Groups: []string{"licensing"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		db.EXPECT().GetAPITokenByHash(cr.HashAPIToken(plain)).Return(&token, nil).Times(1)
+		db.EXPECT().UpdateAPITokenLastUsed(token.ID, utils.P("2023-05-01T10:00:00Z")).Return(nil).Times(1)
+
+		actual, err := as.AuthenticateAPIToken(plain)
+		require.NoError(t, err)
+		assert.Equal(t, model.User{Username: "token:licenses-job", Groups: []string{"licensing"}}, *actual)
+	})
+
+	t.Run("Used less than a minute ago", func(t *testing.T) {
+		used := token
+		lastUsedAt := utils.P("2023-05-01T09:59:30Z")
+		used.LastUsedAt = &lastUsedAt
+
+		db.EXPECT().GetAPITokenByHash(cr.HashAPIToken(plain)).Return(&used, nil).Times(1)
+
+		_, err := as.AuthenticateAPIToken(plain)
+		require.NoError(t, err)
+	})
+
+	t.Run("Used more than a minute ago", func(t *testing.T) {
+		used := token
+		lastUsedAt := utils.P("2023-05-01T09:59:00Z")
+		used.LastUsedAt = &lastUsedAt
+
+		db.EXPECT().GetAPITokenByHash(cr.HashAPIToken(plain)).Return(&used, nil).Times(1)
+		db.EXPECT().UpdateAPITokenLastUsed(token.ID, utils.P("2023-05-01T10:00:00Z")).Return(nil).Times(1)
+
+		_, err := as.AuthenticateAPIToken(plain)
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		db.EXPECT().GetAPITokenByHash(cr.HashAPIToken(plain)).
+			Return(nil, utils.NewError(utils.ErrNotFound, "DB ERROR")).Times(1)
+
+		_, err := as.AuthenticateAPIToken(plain)
+		assert.ErrorIs(t, err, utils.ErrInvalidToken)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		expired := token
+		expiresAt := utils.P("2023-04-01T10:00:00Z")
+		expired.ExpiresAt = &expiresAt
+
+		db.EXPECT().GetAPITokenByHash(cr.HashAPIToken(plain)).Return(&expired, nil).Times(1)
+
+		_, err := as.AuthenticateAPIToken(plain)
+		assert.ErrorIs(t, err, utils.ErrInvalidToken)
+	})
+}
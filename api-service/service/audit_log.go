@@ -0,0 +1,150 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package service is a package that provides methods for querying data
+package service
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils/exutils"
+)
+
+// WithAuditActor return a copy of the service that records in the audit log the changes made by actor
+func (as *APIService) WithAuditActor(actor model.AuditActor) APIServiceInterface {
+	audited := *as
+	audited.auditActor = &actor
+
+	return &audited
+}
+
+// isAudited return true if the changes made through the service are recorded in the audit log
+func (as *APIService) isAudited() bool {
+	return as.auditActor != nil
+}
+
+// audit record a change in the audit log. A failure is logged without failing the change,
+// which has already been saved
+func (as *APIService) audit(entityType, entityID, action string, before, after interface{}) {
+	if !as.isAudited() {
+		return
+	}
+
+	entry := model.AuditLogEntry{
+		ID:         as.NewObjectID(),
+		Date:       as.TimeNow(),
+		AuditActor: *as.auditActor,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    model.NewAuditChanges(before, after),
+	}
+
+	if err := as.Database.InsertAuditLogEntry(entry); err != nil {
+		as.Log.Errorf("Can't write audit log entry of %s %s by %s: %s", entityType, entityID, entry.Username, err)
+	}
+}
+
+func (as *APIService) SearchAuditLog(filter dto.AuditLogFilter) ([]model.AuditLogEntry, error) {
+	return as.Database.SearchAuditLog(filter)
+}
+
+func (as *APIService) SearchAuditLogAsXLSX(filter dto.AuditLogFilter) (*excelize.File, error) {
+	entries, err := as.Database.SearchAuditLog(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := "Audit log"
+	headers := []string{
+		"Date",
+		"Username",
+		"Source IP",
+		"Method",
+		"Endpoint",
+		"Entity Type",
+		"Entity ID",
+		"Action",
+		"Field",
+		"Before",
+		"After",
+	}
+
+	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
+	if err != nil {
+		return nil, err
+	}
+
+	axisHelp := exutils.NewAxisHelper(1)
+
+	for _, entry := range entries {
+		changes := entry.Changes
+		if len(changes) == 0 {
+			changes = []model.AuditChange{{}}
+		}
+
+		for _, change := range changes {
+			nextAxis := axisHelp.NewRow()
+			sheets.SetCellValue(sheet, nextAxis(), entry.Date)
+			sheets.SetCellValue(sheet, nextAxis(), entry.Username)
+			sheets.SetCellValue(sheet, nextAxis(), entry.SourceIP)
+			sheets.SetCellValue(sheet, nextAxis(), entry.Method)
+			sheets.SetCellValue(sheet, nextAxis(), entry.Endpoint)
+			sheets.SetCellValue(sheet, nextAxis(), entry.EntityType)
+			sheets.SetCellValue(sheet, nextAxis(), entry.EntityID)
+			sheets.SetCellValue(sheet, nextAxis(), entry.Action)
+			sheets.SetCellValue(sheet, nextAxis(), change.Field)
+			sheets.SetCellValue(sheet, nextAxis(), change.Before)
+			sheets.SetCellValue(sheet, nextAxis(), change.After)
+		}
+	}
+
+	return sheets, nil
+}
+
+// getMySQLContractForAudit return the contract before the change, or nil if it can't be found
+func (as *APIService) getMySQLContractForAudit(id primitive.ObjectID) *model.MySQLContract {
+	contracts, err := as.Database.GetMySQLContracts()
+	if err != nil {
+		return nil
+	}
+
+	for i := range contracts {
+		if contracts[i].ID == id {
+			return &contracts[i]
+		}
+	}
+
+	return nil
+}
+
+// getSqlServerDatabaseContractForAudit return the contract before the change, or nil if it can't be found
+func (as *APIService) getSqlServerDatabaseContractForAudit(id primitive.ObjectID) *model.SqlServerDatabaseContract {
+	contracts, err := as.Database.ListSqlServerDatabaseContracts()
+	if err != nil {
+		return nil
+	}
+
+	for i := range contracts {
+		if contracts[i].ID == id {
+			return &contracts[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestAuditLog_UpdateGroup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		TimeNow:     utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	actor := model.AuditActor{Username: "mario", SourceIP: "10.0.0.1", Method: "PUT", Endpoint: "/admin/groups/{name}"}
+	before := model.Group{Name: "dba", Tags: []string{"oracle"}, Roles: []string{"admin"}}
+	group := model.Group{Name: "dba", Tags: []string{"oracle", "mysql"}, Roles: []string{"admin"}}
+
+	t.Run("Not audited", func(t *testing.T) {
+		db.EXPECT().UpdateGroup(group).Return(nil)
+
+		_, err := as.UpdateGroup(group)
+		require.NoError(t, err)
+	})
+
+	t.Run("Audited", func(t *testing.T) {
+		db.EXPECT().GetGroup("dba").Return(&before, nil)
+		db.EXPECT().UpdateGroup(group).Return(nil)
+		db.EXPECT().InsertAuditLogEntry(model.AuditLogEntry{
+			ID:         utils.Str2oid("000000000000000000000001"),
+			Date:       utils.P("2023-05-01T10:00:00Z"),
+			AuditActor: actor,
+			EntityType: model.AuditEntityGroup,
+			EntityID:   "dba",
+			Action:     model.AuditActionUpdate,
+			Changes:    []model.AuditChange{{Field: "tags.1", After: `"mysql"`}},
+		}).Return(nil)
+
+		_, err := as.WithAuditActor(actor).UpdateGroup(group)
+		require.NoError(t, err)
+	})
+
+	t.Run("Audit failure doesn't fail the change", func(t *testing.T) {
+		db.EXPECT().GetGroup("dba").Return(&before, nil)
+		db.EXPECT().UpdateGroup(group).Return(nil)
+		db.EXPECT().InsertAuditLogEntry(gomock.Any()).Return(errMock)
+
+		_, err := as.WithAuditActor(actor).UpdateGroup(group)
+		require.NoError(t, err)
+	})
+
+	t.Run("Failed change isn't audited", func(t *testing.T) {
+		db.EXPECT().GetGroup("dba").Return(&before, nil)
+		db.EXPECT().UpdateGroup(group).Return(errMock)
+
+		_, err := as.WithAuditActor(actor).UpdateGroup(group)
+		require.ErrorIs(t, err, errMock)
+	})
+}
+
+func TestAuditLog_DeleteAPIToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		TimeNow:     utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	actor := model.AuditActor{Username: "admin", SourceIP: "10.0.0.1", Method: "DELETE", Endpoint: "/api-tokens/{id}"}
+	id := utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa")
+	before := model.APIToken{ID: id, Name: "licenses-job", TokenHash: "secret", Groups: []string{"licensing"},
+		CreatedAt: utils.P("2023-04-01T10:00:00Z"), CreatedBy: "admin"}
+
+	db.EXPECT().GetAPIToken(id).Return(&before, nil)
+	db.EXPECT().DeleteAPIToken(id).Return(nil)
+	db.EXPECT().InsertAuditLogEntry(gomock.Any()).
+		DoAndReturn(func(entry model.AuditLogEntry) error {
+			assert.Equal(t, model.AuditEntityAPIToken, entry.EntityType)
+			assert.Equal(t, "aaaaaaaaaaaaaaaaaaaaaaaa", entry.EntityID)
+			assert.Equal(t, model.AuditActionDelete, entry.Action)
+			assert.Contains(t, entry.Changes, model.AuditChange{Field: "name", Before: `"licenses-job"`})
+
+			for _, change := range entry.Changes {
+				assert.NotContains(t, change.Before, "secret")
+			}
+
+			return nil
+		})
+
+	require.NoError(t, as.WithAuditActor(actor).DeleteAPIToken(id))
+}
+
+func TestAuditLog_AckAlerts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		TimeNow:     utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	actor := model.AuditActor{Username: "mario", SourceIP: "10.0.0.1", Method: "POST", Endpoint: "/alerts/ack"}
+	filter := dto.AlertsFilter{IDs: []primitive.ObjectID{
+		utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+		utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"),
+	}}
+
+	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusAck, model.AlertHistoryEntry{
+		Date:     utils.P("2023-05-01T10:00:00Z"),
+		Username: "mario",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusAck,
+	}).Return(nil)
+	db.EXPECT().InsertAuditLogEntry(model.AuditLogEntry{
+		ID:         utils.Str2oid("000000000000000000000001"),
+		Date:       utils.P("2023-05-01T10:00:00Z"),
+		AuditActor: actor,
+		EntityType: model.AuditEntityAlert,
+		EntityID:   "aaaaaaaaaaaaaaaaaaaaaaaa",
+		Action:     model.AuditActionUpdate,
+		Changes:    []model.AuditChange{{Field: "alertStatus", After: `"ACK"`}},
+	}).Return(nil)
+	db.EXPECT().InsertAuditLogEntry(model.AuditLogEntry{
+		ID:         utils.Str2oid("000000000000000000000002"),
+		Date:       utils.P("2023-05-01T10:00:00Z"),
+		AuditActor: actor,
+		EntityType: model.AuditEntityAlert,
+		EntityID:   "bbbbbbbbbbbbbbbbbbbbbbbb",
+		Action:     model.AuditActionUpdate,
+		Changes:    []model.AuditChange{{Field: "alertStatus", After: `"ACK"`}},
+	}).Return(nil)
+
+	require.NoError(t, as.WithAuditActor(actor).AckAlerts(filter, "mario"))
+}
+
+func TestSearchAuditLogAsXLSX(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Config: config.Configuration{
+			ResourceFilePath: "../../resources",
+		},
+		Database: db,
+	}
+
+	filter := dto.AuditLogFilter{From: utils.MIN_TIME, To: utils.MAX_TIME, PageNumber: -1, PageSize: -1}
+	entries := []model.AuditLogEntry{
+		{
+			Date:       utils.P("2023-05-01T10:00:00Z"),
+			AuditActor: model.AuditActor{Username: "mario", SourceIP: "10.0.0.1", Method: "DELETE", Endpoint: "/admin/roles/{roleName}"},
+			EntityType: model.AuditEntityRole,
+			EntityID:   "dba",
+			Action:     model.AuditActionDelete,
+			Changes: []model.AuditChange{
+				{Field: "location", Before: `"Italy"`},
+				{Field: "name", Before: `"dba"`},
+			},
+		},
+	}
+
+	db.EXPECT().SearchAuditLog(filter).Return(entries, nil)
+
+	actual, err := as.SearchAuditLogAsXLSX(filter)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mario", actual.GetCellValue("Audit log", "B2"))
+	assert.Equal(t, "ROLE", actual.GetCellValue("Audit log", "F2"))
+	assert.Equal(t, "location", actual.GetCellValue("Audit log", "I2"))
+	assert.Equal(t, `"Italy"`, actual.GetCellValue("Audit log", "J2"))
+	assert.Equal(t, "name", actual.GetCellValue("Audit log", "I3"))
+	assert.Equal(t, "dba", actual.GetCellValue("Audit log", "G3"))
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package service is a package that provides methods for querying data
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetUserAuthorization return the locations granted to the user by the roles of its groups and the permission on each of them
+func (as *APIService) GetUserAuthorization(user model.User) (*model.UserAuthorization, error) {
+	if user.IsAdmin() {
+		return &model.UserAuthorization{
+			AllLocations: true,
+			Locations:    []string{},
+			Permissions:  map[string]string{model.AllLocation: model.AdminPermission},
+		}, nil
+	}
+
+	authorization := model.UserAuthorization{Locations: make([]string, 0), Permissions: make(map[string]string)}
+
+	for _, groupName := range user.Groups {
+		group, err := as.Database.GetGroup(groupName)
+		if errors.Is(err, utils.ErrGroupNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, roleName := range group.Roles {
+			role, err := as.Database.GetRole(roleName)
+			if errors.Is(err, utils.ErrRoleNotFound) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			authorization.Grant(role.Location, role.Permission)
+		}
+	}
+
+	return &authorization, nil
+}
+
+// GetHostLocation return the location of the current host
+func (as *APIService) GetHostLocation(hostname string) (string, error) {
+	return as.Database.GetHostLocation(hostname)
+}
+
+// GetResourceHosts return the hostnames the resource with the id refers to.
+// It return no hostname if the resource doesn't exist
+func (as *APIService) GetResourceHosts(resource string, id primitive.ObjectID) ([]string, error) {
+	switch resource {
+	case model.AlertResource:
+		alert, err := as.Database.GetAlert(id)
+		if errors.Is(err, utils.ErrAlertNotFound) {
+			return []string{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if hostname, ok := alert.OtherInfo["hostname"].(string); ok {
+			return []string{hostname}, nil
+		}
+
+		return []string{}, nil
+	case model.OracleDatabaseContractResource:
+		contract, err := as.Database.GetOracleDatabaseContract(id)
+		if errors.Is(err, utils.ErrContractNotFound) {
+			return []string{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		return contract.Hosts, nil
+	case model.MySQLContractResource:
+		contracts, err := as.Database.GetMySQLContracts()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, contract := range contracts {
+			if contract.ID == id {
+				return contract.Hosts, nil
+			}
+		}
+
+		return []string{}, nil
+	case model.SqlServerDatabaseContractResource:
+		contracts, err := as.Database.ListSqlServerDatabaseContracts()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, contract := range contracts {
+			if contract.ID == id {
+				return contract.Hosts, nil
+			}
+		}
+
+		return []string{}, nil
+	default:
+		return nil, utils.NewError(fmt.Errorf("unknown resource %q", resource), "BAD_REQUEST")
+	}
+}
+
+// hostnamesInLocations return the set of the current hosts in the locations, comma separated,
+// or nil if no location is requested
+func (as *APIService) hostnamesInLocations(location string) (map[string]bool, error) {
+	if location == "" {
+		return nil, nil
+	}
+
+	hostnames, err := as.Database.ListHostnamesByLocations(location)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := make(map[string]bool, len(hostnames))
+	for _, hostname := range hostnames {
+		scope[hostname] = true
+	}
+
+	return scope, nil
+}
+
+// scopeContractHosts return the hosts of a contract that are in the scope, a nil scope keeps them all,
+// and false if the contract had hosts but none is in the scope
+func scopeContractHosts(hostnames []string, scope map[string]bool) ([]string, bool) {
+	if scope == nil || len(hostnames) == 0 {
+		return hostnames, true
+	}
+
+	scoped := make([]string, 0, len(hostnames))
+
+	for _, hostname := range hostnames {
+		if scope[hostname] {
+			scoped = append(scoped, hostname)
+		}
+	}
+
+	return scoped, len(scoped) > 0
+}
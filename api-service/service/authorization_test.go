@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetUserAuthorization(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	t.Run("Admin", func(t *testing.T) {
+		actual, err := as.GetUserAuthorization(model.User{Groups: []string{model.GroupAdmin}})
+		require.NoError(t, err)
+		expected := model.UserAuthorization{
+			AllLocations: true,
+			Locations:    []string{},
+			Permissions:  map[string]string{model.AllLocation: model.AdminPermission},
+		}
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Roles of the groups", func(t *testing.T) {
+		db.EXPECT().GetGroup("italy").Return(&model.Group{Name: "italy", Roles: []string{"italy-read", "italy-write"}}, nil)
+		db.EXPECT().GetGroup("france").Return(&model.Group{Name: "france", Roles: []string{"france-read", "deleted"}}, nil)
+		db.EXPECT().GetGroup("deleted").Return(nil, utils.NewError(utils.ErrGroupNotFound, "DB ERROR"))
+		db.EXPECT().GetRole("italy-read").Return(&model.Role{Location: "Italy", Permission: model.ReadPermission}, nil)
+		db.EXPECT().GetRole("italy-write").Return(&model.Role{Location: "Italy", Permission: model.WritePermission}, nil)
+		db.EXPECT().GetRole("france-read").Return(&model.Role{Location: "France", Permission: model.ReadPermission}, nil)
+		db.EXPECT().GetRole("deleted").Return(nil, utils.NewError(utils.ErrRoleNotFound, "DB ERROR"))
+
+		actual, err := as.GetUserAuthorization(model.User{Groups: []string{"italy", "france", "deleted"}})
+		require.NoError(t, err)
+		expected := model.UserAuthorization{
+			Locations:   []string{"Italy", "France"},
+			Permissions: map[string]string{"Italy": model.WritePermission, "France": model.ReadPermission},
+		}
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("All locations", func(t *testing.T) {
+		db.EXPECT().GetGroup("auditors").Return(&model.Group{Name: "auditors", Roles: []string{"all-read"}}, nil)
+		db.EXPECT().GetRole("all-read").Return(&model.Role{Location: model.AllLocation, Permission: model.ReadPermission}, nil)
+
+		actual, err := as.GetUserAuthorization(model.User{Groups: []string{"auditors"}})
+		require.NoError(t, err)
+		expected := model.UserAuthorization{
+			AllLocations: true,
+			Locations:    []string{},
+			Permissions:  map[string]string{model.AllLocation: model.ReadPermission},
+		}
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().GetGroup("italy").Return(nil, aerrMock)
+
+		_, err := as.GetUserAuthorization(model.User{Groups: []string{"italy"}})
+		assert.ErrorIs(t, err, errMock)
+	})
+}
+
+func TestGetResourceHosts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	id := utils.Str2oid("5dc3f534db7e81a98b726a52")
+
+	t.Run("Alert", func(t *testing.T) {
+		db.EXPECT().GetAlert(id).Return(&model.Alert{ID: id, OtherInfo: map[string]interface{}{"hostname": "rome"}}, nil)
+
+		actual, err := as.GetResourceHosts(model.AlertResource, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rome"}, actual)
+	})
+
+	t.Run("Alert not found", func(t *testing.T) {
+		db.EXPECT().GetAlert(id).Return(nil, utils.NewError(utils.ErrAlertNotFound, "DB ERROR"))
+
+		actual, err := as.GetResourceHosts(model.AlertResource, id)
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("Oracle database contract", func(t *testing.T) {
+		db.EXPECT().GetOracleDatabaseContract(id).Return(&model.OracleDatabaseContract{ID: id, Hosts: []string{"rome", "paris"}}, nil)
+
+		actual, err := as.GetResourceHosts(model.OracleDatabaseContractResource, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rome", "paris"}, actual)
+	})
+
+	t.Run("MySQL contract", func(t *testing.T) {
+		db.EXPECT().GetMySQLContracts().Return([]model.MySQLContract{
+			{ID: primitive.NewObjectID(), Hosts: []string{"rome"}},
+			{ID: id, Hosts: []string{"paris"}},
+		}, nil)
+
+		actual, err := as.GetResourceHosts(model.MySQLContractResource, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"paris"}, actual)
+	})
+
+	t.Run("Unknown resource", func(t *testing.T) {
+		_, err := as.GetResourceHosts("unknown", id)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/utils/exutils"
+)
+
+func (as *APIService) GetBackupComplianceReport(filter dto.BackupComplianceFilter) (*dto.BackupComplianceReport, error) {
+	compliance, err := as.Database.ListBackupCompliance(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.BackupComplianceReport{
+		Databases: compliance,
+		Summary: dto.BackupComplianceSummary{
+			Total:      len(compliance),
+			Violations: make(map[string]int),
+		},
+	}
+
+	for _, c := range compliance {
+		if c.Compliant {
+			report.Summary.Compliant++
+		}
+
+		counted := make(map[string]bool)
+
+		for _, violation := range c.Violations {
+			if !counted[violation.Code] {
+				report.Summary.Violations[violation.Code]++
+				counted[violation.Code] = true
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (as *APIService) GetBackupComplianceReportAsXLSX(filter dto.BackupComplianceFilter) (*excelize.File, error) {
+	compliance, err := as.Database.ListBackupCompliance(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := "Backup compliance"
+	headers := []string{
+		"Hostname",
+		"Location",
+		"Environment",
+		"Database",
+		"Policy",
+		"Compliant",
+		"Violations",
+		"Evaluated at",
+	}
+
+	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
+	if err != nil {
+		return nil, err
+	}
+
+	axisHelp := exutils.NewAxisHelper(1)
+	for _, val := range compliance {
+		nextAxis := axisHelp.NewRow()
+
+		violations := make([]string, 0, len(val.Violations))
+		for _, violation := range val.Violations {
+			violations = append(violations, violation.Description)
+		}
+
+		file.SetCellValue(sheet, nextAxis(), val.Hostname)
+		file.SetCellValue(sheet, nextAxis(), val.Location)
+		file.SetCellValue(sheet, nextAxis(), val.Environment)
+		file.SetCellValue(sheet, nextAxis(), val.DatabaseName)
+		file.SetCellValue(sheet, nextAxis(), val.Policy)
+		file.SetCellValue(sheet, nextAxis(), val.Compliant)
+		file.SetCellValue(sheet, nextAxis(), strings.Join(violations, "; "))
+		file.SetCellValue(sheet, nextAxis(), val.EvaluatedAt)
+	}
+
+	return file, nil
+}
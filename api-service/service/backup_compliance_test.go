@@ -0,0 +1,91 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+func TestGetBackupComplianceReport_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		Config:   config.Configuration{},
+	}
+
+	compliance := []model.BackupCompliance{
+		{Hostname: "foobar", DatabaseName: "ERCOLE", Policy: "production", Compliant: true, Violations: []model.BackupViolation{}},
+		{
+			Hostname:     "foobar",
+			DatabaseName: "SHORT",
+			Policy:       "production",
+			Violations: []model.BackupViolation{
+				{Code: model.BackupViolationMissingSchedule, Description: "foo"},
+				{Code: model.BackupViolationMissingSchedule, Description: "bar"},
+				{Code: model.BackupViolationShortRetention, Description: "baz"},
+			},
+		},
+		{
+			Hostname:     "foobar",
+			DatabaseName: "NOBCK",
+			Policy:       "production",
+			Violations:   []model.BackupViolation{{Code: model.BackupViolationNoBackups, Description: "qux"}},
+		},
+	}
+
+	filter := dto.BackupComplianceFilter{Location: "Italy"}
+	db.EXPECT().ListBackupCompliance(filter).Return(compliance, nil)
+
+	res, err := as.GetBackupComplianceReport(filter)
+	require.NoError(t, err)
+
+	assert.Equal(t, &dto.BackupComplianceReport{
+		Databases: compliance,
+		Summary: dto.BackupComplianceSummary{
+			Total:     3,
+			Compliant: 1,
+			Violations: map[string]int{
+				model.BackupViolationMissingSchedule: 1,
+				model.BackupViolationShortRetention:  1,
+				model.BackupViolationNoBackups:       1,
+			},
+		},
+	}, res)
+}
+
+func TestGetBackupComplianceReport_Fail(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	db.EXPECT().ListBackupCompliance(dto.BackupComplianceFilter{}).Return(nil, aerrMock)
+
+	res, err := as.GetBackupComplianceReport(dto.BackupComplianceFilter{})
+	require.Equal(t, aerrMock, err)
+	assert.Nil(t, res)
+}
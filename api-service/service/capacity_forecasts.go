@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"github.com/360EntSecGroup-Skylar/excelize"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils/exutils"
+)
+
+func (as *APIService) ListCapacityForecasts(filter dto.CapacityForecastFilter) ([]model.CapacityForecast, error) {
+	return as.Database.ListCapacityForecasts(filter)
+}
+
+func (as *APIService) ListCapacityForecastsAsXLSX(filter dto.CapacityForecastFilter) (*excelize.File, error) {
+	forecasts, err := as.Database.ListCapacityForecasts(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := "Capacity forecasts"
+	headers := []string{
+		"Hostname",
+		"Location",
+		"Environment",
+		"Kind",
+		"Database",
+		"Name",
+		"Used",
+		"Capacity",
+		"Growth per day",
+		"Days to full",
+		"Exhaustion date",
+	}
+
+	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
+	if err != nil {
+		return nil, err
+	}
+
+	axisHelp := exutils.NewAxisHelper(1)
+	for _, val := range forecasts {
+		nextAxis := axisHelp.NewRow()
+
+		file.SetCellValue(sheet, nextAxis(), val.Hostname)
+		file.SetCellValue(sheet, nextAxis(), val.Location)
+		file.SetCellValue(sheet, nextAxis(), val.Environment)
+		file.SetCellValue(sheet, nextAxis(), val.Kind)
+		file.SetCellValue(sheet, nextAxis(), val.DatabaseName)
+		file.SetCellValue(sheet, nextAxis(), val.Name)
+		file.SetCellValue(sheet, nextAxis(), val.Used)
+		file.SetCellValue(sheet, nextAxis(), val.Capacity)
+		file.SetCellValue(sheet, nextAxis(), val.GrowthPerDay)
+
+		if val.DaysToFull != nil {
+			file.SetCellValue(sheet, nextAxis(), *val.DaysToFull)
+			file.SetCellValue(sheet, nextAxis(), *val.ExhaustionDate)
+		}
+	}
+
+	return file, nil
+}
@@ -35,6 +35,11 @@ func (as *APIService) SearchClusters(mode string, search string, sortBy string,
 	return as.Database.SearchClusters(mode, strings.Split(search, " "), sortBy, sortDesc, page, pageSize, location, environment, olderThan)
 }
 
+// ListClusters return a page of clusters using cursor pagination, multi-field sort and field projection
+func (as *APIService) ListClusters(mode string, search string, location string, environment string, olderThan time.Time, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListClusters(mode, strings.Split(search, " "), location, environment, olderThan, q)
+}
+
 // GetCluster return the cluster specified in the clusterName param
 func (as *APIService) GetCluster(clusterName string, olderThan time.Time) (*dto.Cluster, error) {
 	cluster, err := as.Database.GetCluster(clusterName, olderThan)
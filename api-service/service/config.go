@@ -15,12 +15,26 @@
 
 package service
 
-import "github.com/ercole-io/ercole/v2/config"
+import (
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+)
 
 func (as *APIService) GetConfig() (*config.Configuration, error) {
 	return as.Database.FindConfig()
 }
 
 func (as *APIService) ChangeConfig(config config.Configuration) error {
-	return as.Database.ChangeConfig(config)
+	var before interface{}
+	if as.isAudited() {
+		before, _ = as.Database.FindConfig()
+	}
+
+	if err := as.Database.ChangeConfig(config); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityConfig, "", model.AuditActionUpdate, before, config)
+
+	return nil
 }
@@ -51,6 +51,23 @@ func (as *APIService) SearchDatabases(filter dto.GlobalFilter) ([]dto.Database,
 		dbs = append(dbs, thisDbs...)
 	}
 
+	hostnames, err := as.hostnamesMatchingMetadata(filter.HostMetadataFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if hostnames != nil {
+		filtered := make([]dto.Database, 0, len(dbs))
+
+		for _, db := range dbs {
+			if hostnames[db.Hostname] {
+				filtered = append(filtered, db)
+			}
+		}
+
+		dbs = filtered
+	}
+
 	return dbs, nil
 }
 
@@ -227,6 +244,11 @@ func (as *APIService) SearchDatabasesAsXLSX(filter dto.GlobalFilter) (*excelize.
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Databases"
 	headers := []string{
 		"Name",
@@ -240,6 +262,7 @@ func (as *APIService) SearchDatabasesAsXLSX(filter dto.GlobalFilter) (*excelize.
 		"Datafile Size",
 		"Segments Size",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -260,6 +283,10 @@ func (as *APIService) SearchDatabasesAsXLSX(filter dto.GlobalFilter) (*excelize.
 		file.SetCellValue(sheet, nextAxis(), val.Memory)
 		file.SetCellValue(sheet, nextAxis(), val.DatafileSize)
 		file.SetCellValue(sheet, nextAxis(), val.SegmentsSize)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return file, nil
@@ -319,7 +319,7 @@ func TestSearchDatabases_Success(t *testing.T) {
 		Database: db,
 	}
 
-	db.EXPECT().SearchOracleDatabases([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchOracleDatabases([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedRes, nil)
 
 	mysqlInstances := []dto.MySQLInstance{
@@ -360,13 +360,13 @@ func TestSearchDatabases_Success(t *testing.T) {
 	db.EXPECT().SearchMySQLInstances(globalFilter).
 		Return(mysqlInstances, nil)
 
-	db.EXPECT().SearchSqlServerInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchSqlServerInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedSqlServerRes, nil)
 
-	db.EXPECT().SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedPostgreSqlRes, nil)
 
-	db.EXPECT().SearchMongoDBInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchMongoDBInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedMongoDBRes, nil)
 
 	actual, err := as.SearchDatabases(globalFilter)
@@ -445,7 +445,7 @@ func TestSearchDatabasesAsXLSX_Success(t *testing.T) {
 		},
 	}
 
-	db.EXPECT().SearchOracleDatabases([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchOracleDatabases([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedRes, nil)
 
 	mysqlInstances := []dto.MySQLInstance{
@@ -486,14 +486,16 @@ func TestSearchDatabasesAsXLSX_Success(t *testing.T) {
 	db.EXPECT().SearchMySQLInstances(globalFilter).
 		Return(mysqlInstances, nil)
 
-	db.EXPECT().SearchSqlServerInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchSqlServerInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedSqlServerRes, nil)
 
-	db.EXPECT().SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedPostgreSqlRes, nil)
 
-	db.EXPECT().SearchMongoDBInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchMongoDBInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedMongoDBRes, nil)
+	db.EXPECT().ListHostMetadata(dto.HostMetadataFilter{}).
+		Return([]model.HostMetadata{{Hostname: "pluto", OwnerTeam: "dba", Criticality: model.HostCriticalityLow}}, nil)
 
 	actual, err := as.SearchDatabasesAsXLSX(globalFilter)
 	require.NoError(t, err)
@@ -564,6 +566,11 @@ func TestSearchDatabasesAsXLSX_Success(t *testing.T) {
 	assert.Equal(t, "Memory", actual.GetCellValue("Databases", "H1"))
 	assert.Equal(t, "42.42", actual.GetCellValue("Databases", "H2"))
 	assert.Equal(t, "42", actual.GetCellValue("Databases", "H3"))
+
+	assert.Equal(t, "Owner Team", actual.GetCellValue("Databases", "K1"))
+	assert.Equal(t, "", actual.GetCellValue("Databases", "K2"))
+	assert.Equal(t, "dba", actual.GetCellValue("Databases", "K3"))
+	assert.Equal(t, "LOW", actual.GetCellValue("Databases", "O3"))
 }
 
 func TestGetDatabasesStatistics_Success(t *testing.T) {
@@ -574,7 +581,7 @@ func TestGetDatabasesStatistics_Success(t *testing.T) {
 		Database: db,
 	}
 
-	db.EXPECT().SearchOracleDatabases([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchOracleDatabases([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedRes, nil)
 
 	mysqlInstances := []dto.MySQLInstance{
@@ -631,13 +638,13 @@ func TestGetDatabasesStatistics_Success(t *testing.T) {
 	db.EXPECT().SearchMySQLInstances(globalFilter).
 		Return(mysqlInstances, nil)
 
-	db.EXPECT().SearchSqlServerInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchSqlServerInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedSqlServerRes, nil)
 
-	db.EXPECT().SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchPostgreSqlInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedPostgreSqlRes, nil)
 
-	db.EXPECT().SearchMongoDBInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment).
+	db.EXPECT().SearchMongoDBInstances([]string{""}, "", false, -1, -1, "Dubai", "TEST", thisMoment, dto.HostMetadataFilter{}).
 		Return(&expectedMongoDBRes, nil)
 
 	actual, err := as.GetDatabasesStatistics(globalFilter)
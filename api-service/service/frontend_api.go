@@ -45,5 +45,10 @@ func (as *APIService) GetInfoForFrontendDashboard(location string, environment s
 
 	out["technologies"] = technologiesObject
 
+	out["oraclePatches"], err = as.GetOraclePatchAdvisorSummary(location, environment, olderThan)
+	if err != nil {
+		return nil, err
+	}
+
 	return out, nil
 }
@@ -34,6 +34,7 @@ func TestGetInfoForFrontendDashboard_Success(t *testing.T) {
 	as := APIService{
 		Database: db,
 		Log:      logger.NewLogger("TEST"),
+		TimeNow:  utils.Btc(utils.P("2023-01-01T00:00:00Z")),
 	}
 
 	expectedRes := map[string]interface{}{
@@ -86,6 +87,14 @@ func TestGetInfoForFrontendDashboard_Success(t *testing.T) {
 			"Oracle/Database": true,
 			"Oracle/Exadata":  false,
 		},
+		"oraclePatches": map[string]interface{}{
+			"databases":           2,
+			"upToDate":            0,
+			"behind":              1,
+			"notInCatalogue":      1,
+			"outOfPremierSupport": 0,
+			"unsupported":         0,
+		},
 	}
 
 	getTechnologiesUsageRes := map[string]float64{
@@ -291,6 +300,23 @@ func TestGetInfoForFrontendDashboard_Success(t *testing.T) {
 		db.EXPECT().
 			GetHostsCountUsingTechnologies("", "", utils.MAX_TIME).
 			Return(getTechnologiesUsageRes, nil),
+
+		db.EXPECT().
+			SearchOracleDatabasePatchAdvisors([]string{""}, "", false, -1, -1, utils.P("2023-01-01T00:00:00Z"),
+				"Italy", "PRD", utils.P("2019-12-05T14:02:03Z"), "").
+			Return(&dto.PatchAdvisorResponse{
+				Content: dto.PatchAdvisors{
+					{Hostname: "foobar", DbName: "ERCOLE", Dbver: "19.0.0.0.0", Description: "Database Release Update : 19.5.0.0.191015 (30125133)"},
+					{Hostname: "foobar", DbName: "OLD", Dbver: "11.2.0.4.0"},
+				},
+			}, nil),
+		db.EXPECT().GetOraclePatchCatalogue().
+			Return(&model.OraclePatchCatalogue{
+				Releases: []model.OraclePatchRelease{
+					{PatchNumber: "30125133", Type: model.OraclePatchTypeRU, Version: "19", ReleaseDate: utils.P("2019-10-15T00:00:00Z")},
+					{PatchNumber: "30557433", Type: model.OraclePatchTypeRU, Version: "19", ReleaseDate: utils.P("2020-01-14T00:00:00Z")},
+				},
+			}, nil),
 	)
 
 	res, err := as.GetInfoForFrontendDashboard("Italy", "PRD", utils.P("2019-12-05T14:02:03Z"))
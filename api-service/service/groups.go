@@ -26,14 +26,23 @@ func (as *APIService) InsertGroup(group model.Group) (*model.Group, error) {
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityGroup, group.Name, model.AuditActionCreate, nil, group)
+
 	return &group, nil
 }
 
 func (as *APIService) UpdateGroup(group model.Group) (*model.Group, error) {
+	var before *model.Group
+	if as.isAudited() {
+		before, _ = as.Database.GetGroup(group.Name)
+	}
+
 	if err := as.Database.UpdateGroup(group); err != nil {
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityGroup, group.Name, model.AuditActionUpdate, before, group)
+
 	return &group, nil
 }
 
@@ -56,10 +65,17 @@ func (as *APIService) GetGroups() ([]model.Group, error) {
 }
 
 func (as *APIService) DeleteGroup(name string) error {
+	var before *model.Group
+	if as.isAudited() {
+		before, _ = as.Database.GetGroup(name)
+	}
+
 	if err := as.Database.DeleteGroup(name); err != nil {
 		return err
 	}
 
+	as.audit(model.AuditEntityGroup, name, model.AuditActionDelete, before, nil)
+
 	return nil
 }
 
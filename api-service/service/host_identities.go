@@ -0,0 +1,294 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// hostAliasStaleAfter is how long a host must have stopped sending data,
+// before the newer one, to add the same hardware to the reasons of the suggestion
+const hostAliasStaleAfter = 24 * time.Hour
+
+func (as *APIService) ListHostIdentities() ([]model.HostIdentity, error) {
+	return as.Database.ListHostIdentities()
+}
+
+// getOrNewHostIdentity return the identity of the host, or a new one if the host hasn't any yet
+func (as *APIService) getOrNewHostIdentity(hostname string) (*model.HostIdentity, error) {
+	identity, err := as.Database.FindHostIdentity(hostname)
+	if errors.Is(err, utils.ErrHostIdentityNotFound) {
+		return &model.HostIdentity{
+			ID:        as.NewObjectID(),
+			Hostname:  hostname,
+			Aliases:   []string{},
+			CreatedAt: as.TimeNow(),
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// checkAliasAvailable return an error if name is already an alias of a host other than owner
+func (as *APIService) checkAliasAvailable(name, owner string) error {
+	identity, err := as.Database.FindHostIdentityByAlias(name)
+	if err == nil && identity.Hostname != owner {
+		return utils.NewErrorf("%w: %s is an alias of %s", utils.ErrHostAliasInUse, name, identity.Hostname)
+	} else if err != nil && !errors.Is(err, utils.ErrHostIdentityNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// AddHostAlias add alias to the names of the host. The alias can't be the name of a host with hostdata,
+// that must be merged instead
+func (as *APIService) AddHostAlias(hostname, alias string) (*model.HostIdentity, error) {
+	if alias == "" || alias == hostname {
+		return nil, utils.NewErrorf("%w: invalid alias %q of %s", utils.ErrInvalidHostIdentity, alias, hostname)
+	}
+
+	exist, err := as.Database.ExistHostdata(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, utils.ErrHostNotFound
+	}
+
+	exist, err = as.Database.ExistHostdata(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		return nil, utils.NewErrorf("%w: %s has hostdata, merge it into %s instead", utils.ErrHostAliasInUse, alias, hostname)
+	}
+
+	if err := as.checkAliasAvailable(alias, hostname); err != nil {
+		return nil, err
+	}
+
+	identity, err := as.getOrNewHostIdentity(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity.HasAlias(alias) {
+		return identity, nil
+	}
+
+	before := *identity
+	before.Aliases = append([]string{}, identity.Aliases...)
+
+	identity.AddAlias(alias)
+	identity.UpdatedAt = as.TimeNow()
+
+	if err := as.Database.SaveHostIdentity(*identity); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityHostIdentity, hostname, model.AuditActionUpdate, before, identity)
+
+	return identity, nil
+}
+
+// RemoveHostAlias remove alias from the names of the host
+func (as *APIService) RemoveHostAlias(hostname, alias string) (*model.HostIdentity, error) {
+	identity, err := as.Database.FindHostIdentity(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *identity
+	before.Aliases = append([]string{}, identity.Aliases...)
+
+	if !identity.RemoveAlias(alias) {
+		return nil, utils.NewErrorf("%w: %s isn't an alias of %s", utils.ErrHostIdentityNotFound, alias, hostname)
+	}
+
+	identity.UpdatedAt = as.TimeNow()
+
+	if err := as.Database.SaveHostIdentity(*identity); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityHostIdentity, hostname, model.AuditActionUpdate, before, identity)
+
+	return identity, nil
+}
+
+// MergeHost move the hostdata, history included, the alerts and the contracts of source to target,
+// and make source and its aliases aliases of target, so the next hostdata sent by source are stored as target.
+// If target has no hostdata, the merge renames source
+func (as *APIService) MergeHost(source, target string) (*model.HostIdentity, error) {
+	if source == "" || target == "" || source == target {
+		return nil, utils.NewErrorf("%w: can't merge %q into %q", utils.ErrInvalidHostIdentity, source, target)
+	}
+
+	exist, err := as.Database.ExistHostdata(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, utils.ErrHostNotFound
+	}
+
+	if err := as.checkAliasAvailable(target, source); err != nil {
+		return nil, err
+	}
+
+	identity, err := as.getOrNewHostIdentity(target)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIdentity, err := as.Database.FindHostIdentity(source)
+	if err != nil && !errors.Is(err, utils.ErrHostIdentityNotFound) {
+		return nil, err
+	}
+
+	identity.AddAlias(source)
+
+	if sourceIdentity != nil {
+		for _, alias := range sourceIdentity.Aliases {
+			identity.AddAlias(alias)
+		}
+	}
+
+	identity.RemoveAlias(target)
+	identity.UpdatedAt = as.TimeNow()
+
+	var sourceIdentityID *primitive.ObjectID
+	if sourceIdentity != nil {
+		sourceIdentityID = &sourceIdentity.ID
+	}
+
+	if err := as.Database.MergeHost(source, target, *identity, sourceIdentityID); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityHost, source, model.AuditActionMerge,
+		map[string]string{"hostname": source}, map[string]string{"hostname": target})
+
+	return identity, nil
+}
+
+// GetHostAliasSuggestions return the pairs of current hosts that are probably the same host:
+// with the same name without the domain or with an Oracle database with the same name and DBID.
+// The same hardware is too common to be a reason alone, in a farm of identical VMs, so it's only added
+// to the reasons of the pairs already suggested when the older host stopped sending data
+func (as *APIService) GetHostAliasSuggestions() ([]dto.HostAliasSuggestion, error) {
+	hostdatas, err := as.Database.GetHostDatas(utils.MAX_TIME)
+	if err != nil {
+		return nil, err
+	}
+
+	byShortHostname := make(map[string][]int)
+	byOracleDatabase := make(map[string][]int)
+	byHardware := make(map[string][]int)
+
+	for i, hostdata := range hostdatas {
+		shortHostname := strings.ToLower(strings.Split(hostdata.Hostname, ".")[0])
+		byShortHostname[shortHostname] = append(byShortHostname[shortHostname], i)
+
+		if hostdata.Features.Oracle != nil && hostdata.Features.Oracle.Database != nil {
+			for _, db := range hostdata.Features.Oracle.Database.Databases {
+				if db.DbID != 0 {
+					key := fmt.Sprintf("%s/%d", db.Name, db.DbID)
+					byOracleDatabase[key] = append(byOracleDatabase[key], i)
+				}
+			}
+		}
+
+		if hostdata.Info.CPUModel != "" {
+			key := fmt.Sprintf("%s/%d/%d/%v/%s", hostdata.Info.CPUModel, hostdata.Info.CPUSockets, hostdata.Info.CPUCores,
+				hostdata.Info.MemoryTotal, hostdata.Info.HardwareAbstractionTechnology)
+			byHardware[key] = append(byHardware[key], i)
+		}
+	}
+
+	suggestions := make(map[[2]int]*dto.HostAliasSuggestion)
+
+	// a weak reason is only added to the pairs suggested by the other reasons, when the source is stale
+	suggest := func(groups map[string][]int, reason string, weak bool) {
+		for _, group := range groups {
+			for a := 0; a < len(group); a++ {
+				for b := a + 1; b < len(group); b++ {
+					source, target := group[a], group[b]
+					if hostdatas[source].CreatedAt.After(hostdatas[target].CreatedAt) {
+						source, target = target, source
+					}
+
+					if weak && hostdatas[target].CreatedAt.Sub(hostdatas[source].CreatedAt) < hostAliasStaleAfter {
+						continue
+					}
+
+					suggestion, ok := suggestions[[2]int{source, target}]
+					if !ok && weak {
+						continue
+					} else if !ok {
+						suggestion = &dto.HostAliasSuggestion{
+							Source:          hostdatas[source].Hostname,
+							SourceUpdatedAt: hostdatas[source].CreatedAt,
+							Target:          hostdatas[target].Hostname,
+							TargetUpdatedAt: hostdatas[target].CreatedAt,
+							Reasons:         []string{},
+						}
+						suggestions[[2]int{source, target}] = suggestion
+					}
+
+					if !utils.Contains(suggestion.Reasons, reason) {
+						suggestion.Reasons = append(suggestion.Reasons, reason)
+					}
+				}
+			}
+		}
+	}
+
+	suggest(byShortHostname, model.HostAliasReasonSameShortHostname, false)
+	suggest(byOracleDatabase, model.HostAliasReasonSameOracleDatabase, false)
+	suggest(byHardware, model.HostAliasReasonSameHardware, true)
+
+	out := make([]dto.HostAliasSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		out = append(out, *suggestion)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Target != out[j].Target {
+			return out[i].Target < out[j].Target
+		}
+
+		return out[i].Source < out[j].Source
+	})
+
+	return out, nil
+}
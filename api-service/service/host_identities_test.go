@@ -0,0 +1,245 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestAddHostAlias(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		TimeNow:     utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	t.Run("New identity", func(t *testing.T) {
+		expected := model.HostIdentity{
+			ID:        utils.Str2oid("000000000000000000000001"),
+			Hostname:  "foobar",
+			Aliases:   []string{"foobar.example.org"},
+			CreatedAt: utils.P("2023-05-01T10:00:00Z"),
+			UpdatedAt: utils.P("2023-05-01T10:00:00Z"),
+		}
+
+		gomock.InOrder(
+			db.EXPECT().ExistHostdata("foobar").Return(true, nil),
+			db.EXPECT().ExistHostdata("foobar.example.org").Return(false, nil),
+			db.EXPECT().FindHostIdentityByAlias("foobar.example.org").Return(nil, utils.ErrHostIdentityNotFound),
+			db.EXPECT().FindHostIdentity("foobar").Return(nil, utils.ErrHostIdentityNotFound),
+			db.EXPECT().SaveHostIdentity(expected).Return(nil),
+		)
+
+		actual, err := as.AddHostAlias("foobar", "foobar.example.org")
+		require.NoError(t, err)
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Alias with hostdata", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().ExistHostdata("foobar").Return(true, nil),
+			db.EXPECT().ExistHostdata("barfoo").Return(true, nil),
+		)
+
+		_, err := as.AddHostAlias("foobar", "barfoo")
+		assert.ErrorIs(t, err, utils.ErrHostAliasInUse)
+	})
+
+	t.Run("Alias of another host", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().ExistHostdata("foobar").Return(true, nil),
+			db.EXPECT().ExistHostdata("oldname").Return(false, nil),
+			db.EXPECT().FindHostIdentityByAlias("oldname").
+				Return(&model.HostIdentity{Hostname: "barfoo", Aliases: []string{"oldname"}}, nil),
+		)
+
+		_, err := as.AddHostAlias("foobar", "oldname")
+		assert.ErrorIs(t, err, utils.ErrHostAliasInUse)
+	})
+
+	t.Run("Host not found", func(t *testing.T) {
+		db.EXPECT().ExistHostdata("foobar").Return(false, nil)
+
+		_, err := as.AddHostAlias("foobar", "oldname")
+		assert.ErrorIs(t, err, utils.ErrHostNotFound)
+	})
+
+	t.Run("Invalid alias", func(t *testing.T) {
+		_, err := as.AddHostAlias("foobar", "foobar")
+		assert.ErrorIs(t, err, utils.ErrInvalidHostIdentity)
+	})
+}
+
+func TestRemoveHostAlias(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+	}
+
+	identity := model.HostIdentity{Hostname: "foobar", Aliases: []string{"oldname", "foobar.example.org"}}
+
+	t.Run("Success", func(t *testing.T) {
+		expected := model.HostIdentity{
+			Hostname:  "foobar",
+			Aliases:   []string{"foobar.example.org"},
+			UpdatedAt: utils.P("2023-05-01T10:00:00Z"),
+		}
+
+		gomock.InOrder(
+			db.EXPECT().FindHostIdentity("foobar").Return(&identity, nil),
+			db.EXPECT().SaveHostIdentity(expected).Return(nil),
+		)
+
+		actual, err := as.RemoveHostAlias("foobar", "oldname")
+		require.NoError(t, err)
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Not an alias", func(t *testing.T) {
+		db.EXPECT().FindHostIdentity("foobar").
+			Return(&model.HostIdentity{Hostname: "foobar", Aliases: []string{"foobar.example.org"}}, nil)
+
+		_, err := as.RemoveHostAlias("foobar", "oldname")
+		assert.ErrorIs(t, err, utils.ErrHostIdentityNotFound)
+	})
+}
+
+func TestMergeHost(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database:    db,
+		TimeNow:     utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		target := model.HostIdentity{
+			ID:       utils.Str2oid("000000000000000000000010"),
+			Hostname: "newname",
+			Aliases:  []string{"newname.example.org"},
+		}
+		source := model.HostIdentity{
+			ID:       utils.Str2oid("000000000000000000000020"),
+			Hostname: "oldname",
+			Aliases:  []string{"oldname.example.org", "newname"},
+		}
+		expected := model.HostIdentity{
+			ID:        utils.Str2oid("000000000000000000000010"),
+			Hostname:  "newname",
+			Aliases:   []string{"newname.example.org", "oldname", "oldname.example.org"},
+			UpdatedAt: utils.P("2023-05-01T10:00:00Z"),
+		}
+
+		gomock.InOrder(
+			db.EXPECT().ExistHostdata("oldname").Return(true, nil),
+			db.EXPECT().FindHostIdentityByAlias("newname").Return(&source, nil),
+			db.EXPECT().FindHostIdentity("newname").Return(&target, nil),
+			db.EXPECT().FindHostIdentity("oldname").Return(&source, nil),
+			db.EXPECT().MergeHost("oldname", "newname", expected, &source.ID).Return(nil),
+		)
+
+		actual, err := as.MergeHost("oldname", "newname")
+		require.NoError(t, err)
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Target alias of another host", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().ExistHostdata("oldname").Return(true, nil),
+			db.EXPECT().FindHostIdentityByAlias("newname").
+				Return(&model.HostIdentity{Hostname: "barfoo", Aliases: []string{"newname"}}, nil),
+		)
+
+		_, err := as.MergeHost("oldname", "newname")
+		assert.ErrorIs(t, err, utils.ErrHostAliasInUse)
+	})
+
+	t.Run("Source not found", func(t *testing.T) {
+		db.EXPECT().ExistHostdata("oldname").Return(false, nil)
+
+		_, err := as.MergeHost("oldname", "newname")
+		assert.ErrorIs(t, err, utils.ErrHostNotFound)
+	})
+
+	t.Run("Merge into itself", func(t *testing.T) {
+		_, err := as.MergeHost("oldname", "oldname")
+		assert.ErrorIs(t, err, utils.ErrInvalidHostIdentity)
+	})
+}
+
+func TestGetHostAliasSuggestions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	oracleFeature := func(dbID uint) model.Features {
+		return model.Features{Oracle: &model.OracleFeature{Database: &model.OracleDatabaseFeature{
+			Databases: []model.OracleDatabase{{Name: "ERCOLE", DbID: dbID}},
+		}}}
+	}
+	hardware := model.Host{CPUModel: "Intel Xeon", CPUSockets: 2, CPUCores: 8, MemoryTotal: 64, HardwareAbstractionTechnology: "VMWARE"}
+
+	hostdatas := []model.HostDataBE{
+		{Hostname: "foobar", CreatedAt: utils.P("2023-05-01T10:00:00Z")},
+		{Hostname: "FOOBAR.example.org", CreatedAt: utils.P("2023-05-01T11:00:00Z")},
+		{Hostname: "db01", CreatedAt: utils.P("2023-04-01T10:00:00Z"), Features: oracleFeature(42), Info: hardware},
+		{Hostname: "db01-new", CreatedAt: utils.P("2023-05-01T10:00:00Z"), Features: oracleFeature(42), Info: hardware},
+		{Hostname: "app01", CreatedAt: utils.P("2023-05-01T09:00:00Z"), Info: hardware},
+		{Hostname: "app02", CreatedAt: utils.P("2023-05-01T09:00:00Z"), Info: hardware},
+	}
+
+	db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(hostdatas, nil)
+
+	expected := []dto.HostAliasSuggestion{
+		{
+			Source:          "foobar",
+			SourceUpdatedAt: utils.P("2023-05-01T10:00:00Z"),
+			Target:          "FOOBAR.example.org",
+			TargetUpdatedAt: utils.P("2023-05-01T11:00:00Z"),
+			Reasons:         []string{model.HostAliasReasonSameShortHostname},
+		},
+		{
+			Source:          "db01",
+			SourceUpdatedAt: utils.P("2023-04-01T10:00:00Z"),
+			Target:          "db01-new",
+			TargetUpdatedAt: utils.P("2023-05-01T10:00:00Z"),
+			Reasons:         []string{model.HostAliasReasonSameOracleDatabase, model.HostAliasReasonSameHardware},
+		},
+	}
+
+	actual, err := as.GetHostAliasSuggestions()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
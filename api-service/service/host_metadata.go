@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (as *APIService) ListHostMetadata(filter dto.HostMetadataFilter) ([]model.HostMetadata, error) {
+	return as.Database.ListHostMetadata(filter)
+}
+
+func (as *APIService) GetHostMetadata(hostname string) (*model.HostMetadata, error) {
+	return as.Database.FindHostMetadata(hostname)
+}
+
+// UpdateHostMetadata replace the metadata of the host edited by the users. The CMDB record isn't changed
+func (as *APIService) UpdateHostMetadata(hostname string, req dto.HostMetadataRequest) (*model.HostMetadata, error) {
+	if err := checkHostMetadataRequest(req); err != nil {
+		return nil, err
+	}
+
+	exist, err := as.Database.ExistHostdata(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, utils.ErrHostNotFound
+	}
+
+	before, err := as.Database.FindHostMetadata(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := *before
+	metadata.OwnerTeam = strings.TrimSpace(req.OwnerTeam)
+	metadata.ContactEmail = strings.TrimSpace(req.ContactEmail)
+	metadata.BusinessApplication = strings.TrimSpace(req.BusinessApplication)
+	metadata.CostCenter = strings.TrimSpace(req.CostCenter)
+	metadata.Criticality = req.Criticality
+	metadata.Labels = normalizeHostLabels(req.Labels)
+	metadata.UpdatedAt = as.TimeNow()
+
+	if err := as.Database.UpdateHostMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityHostMetadata, hostname, model.AuditActionUpdate, before, metadata)
+
+	return &metadata, nil
+}
+
+func checkHostMetadataRequest(req dto.HostMetadataRequest) error {
+	if req.Criticality != "" && !utils.Contains(model.HostCriticalities, req.Criticality) {
+		return utils.NewErrorf("%w: invalid criticality %q, must be one of %s",
+			utils.ErrInvalidHostMetadata, req.Criticality, strings.Join(model.HostCriticalities, ", "))
+	}
+
+	if email := strings.TrimSpace(req.ContactEmail); email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return utils.NewErrorf("%w: invalid contact email %q", utils.ErrInvalidHostMetadata, email)
+		}
+	}
+
+	return nil
+}
+
+// normalizeHostLabels trim the labels, removing the empty and the duplicated ones
+func normalizeHostLabels(labels []string) []string {
+	res := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label != "" && !utils.Contains(res, label) {
+			res = append(res, label)
+		}
+	}
+
+	return res
+}
+
+// hostMetadataByHostname return the metadata of all the hosts by hostname
+func (as *APIService) hostMetadataByHostname() (map[string]model.HostMetadata, error) {
+	metadata, err := as.Database.ListHostMetadata(dto.HostMetadataFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]model.HostMetadata, len(metadata))
+	for _, m := range metadata {
+		res[m.Hostname] = m
+	}
+
+	return res, nil
+}
+
+// hostnamesMatchingMetadata return the hostnames with the metadata matching the filter, or nil if the filter is empty
+func (as *APIService) hostnamesMatchingMetadata(filter dto.HostMetadataFilter) (map[string]bool, error) {
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+
+	metadata, err := as.Database.ListHostMetadata(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]bool, len(metadata))
+	for _, m := range metadata {
+		res[m.Hostname] = true
+	}
+
+	return res, nil
+}
+
+// hostMetadataXLSXHeaders are the headers of the columns with the metadata of the hosts in the XLSX reports
+var hostMetadataXLSXHeaders = []string{
+	"Owner Team",
+	"Contact Email",
+	"Business Application",
+	"Cost Center",
+	"Criticality",
+	"Labels",
+}
+
+// hostMetadataXLSXValues return the values of the columns with the metadata of the host in the XLSX reports
+func hostMetadataXLSXValues(metadata model.HostMetadata) []interface{} {
+	return []interface{}{
+		metadata.OwnerTeam,
+		metadata.ContactEmail,
+		metadata.BusinessApplication,
+		metadata.CostCenter,
+		metadata.Criticality,
+		strings.Join(metadata.Labels, ", "),
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestUpdateHostMetadata(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2023-05-01T10:00:00Z")),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		cmdb := &model.CmdbRecord{Source: "servicenow", Hostname: "FOOBAR"}
+		expected := model.HostMetadata{
+			Hostname:            "foobar",
+			OwnerTeam:           "dba",
+			ContactEmail:        "dba@example.org",
+			BusinessApplication: "billing",
+			CostCenter:          "CC42",
+			Criticality:         model.HostCriticalityHigh,
+			Labels:              []string{"pci", "eu"},
+			UpdatedAt:           utils.P("2023-05-01T10:00:00Z"),
+			Cmdb:                cmdb,
+		}
+
+		gomock.InOrder(
+			db.EXPECT().ExistHostdata("foobar").Return(true, nil),
+			db.EXPECT().FindHostMetadata("foobar").Return(&model.HostMetadata{Hostname: "foobar", OwnerTeam: "old", Cmdb: cmdb}, nil),
+			db.EXPECT().UpdateHostMetadata(expected).Return(nil),
+		)
+
+		actual, err := as.UpdateHostMetadata("foobar", dto.HostMetadataRequest{
+			OwnerTeam:           " dba ",
+			ContactEmail:        "dba@example.org",
+			BusinessApplication: "billing",
+			CostCenter:          "CC42",
+			Criticality:         model.HostCriticalityHigh,
+			Labels:              []string{"pci", " eu", "", "pci"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("Invalid criticality", func(t *testing.T) {
+		_, err := as.UpdateHostMetadata("foobar", dto.HostMetadataRequest{Criticality: "VERY_HIGH"})
+		assert.ErrorIs(t, err, utils.ErrInvalidHostMetadata)
+	})
+
+	t.Run("Invalid contact email", func(t *testing.T) {
+		_, err := as.UpdateHostMetadata("foobar", dto.HostMetadataRequest{ContactEmail: "dba"})
+		assert.ErrorIs(t, err, utils.ErrInvalidHostMetadata)
+	})
+
+	t.Run("Host not found", func(t *testing.T) {
+		db.EXPECT().ExistHostdata("unknown").Return(false, nil)
+
+		_, err := as.UpdateHostMetadata("unknown", dto.HostMetadataRequest{})
+		assert.ErrorIs(t, err, utils.ErrHostNotFound)
+	})
+}
+
+func TestHostnamesMatchingMetadata(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	actual, err := as.hostnamesMatchingMetadata(dto.HostMetadataFilter{})
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	filter := dto.HostMetadataFilter{OwnerTeam: "dba"}
+	db.EXPECT().ListHostMetadata(filter).
+		Return([]model.HostMetadata{{Hostname: "foobar"}, {Hostname: "barfoo"}}, nil)
+
+	actual, err = as.hostnamesMatchingMetadata(filter)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"foobar": true, "barfoo": true}, actual)
+}
@@ -34,6 +34,10 @@ func (as *APIService) SearchHosts(mode string, filters dto.SearchHostsFilters) (
 	return as.Database.SearchHosts(mode, filters)
 }
 
+func (as *APIService) ListHosts(mode string, filters dto.SearchHostsFilters, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListHosts(mode, filters, q)
+}
+
 func (as *APIService) SearchHostsAsLMS(filters dto.SearchHostsAsLMS) (*excelize.File, error) {
 	sheetDatabaseEbsDbTier := "Database_&_EBS_DB_Tier"
 	sheetHostAdded := "Hosts_added"
@@ -199,6 +203,11 @@ func (as *APIService) SearchHostsAsXLSX(filters dto.SearchHostsFilters) (*exceli
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Hosts"
 	headers := []string{
 		"Hostname",
@@ -221,6 +230,7 @@ func (as *APIService) SearchHostsAsXLSX(filters dto.SearchHostsFilters) (*exceli
 		"Memory",
 		"Swap",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -273,6 +283,10 @@ func (as *APIService) SearchHostsAsXLSX(filters dto.SearchHostsFilters) (*exceli
 		file.SetCellValue(sheet, nextAxis(), val.Info.KernelVersion)
 		file.SetCellValue(sheet, nextAxis(), val.Info.MemoryTotal)
 		file.SetCellValue(sheet, nextAxis(), val.Info.SwapTotal)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return file, nil
@@ -383,17 +397,17 @@ func (as *APIService) ListEnvironments(location string, environment string, olde
 }
 
 // DismissHost dismiss the specified host
-func (as *APIService) DismissHost(hostname string) error {
+func (as *APIService) DismissHost(hostname, username string) error {
 	filter := dto.AlertsFilter{OtherInfo: map[string]interface{}{"hostname": hostname}}
 	if err := as.RemoveAlertsNODATA(filter); err != nil {
 		as.Log.Errorf("Can't delete alerts by %s", hostname)
 	}
 
-	if err := as.AckAlerts(filter); err != nil {
+	if err := as.AckAlerts(filter, username); err != nil {
 		as.Log.Errorf("Can't ack hostname %s alerts by filter", hostname)
 	}
 
-	if err := as.UpdateAlertsStatus(filter, model.AlertStatusDismissed); err != nil {
+	if err := as.UpdateAlertsStatus(filter, model.AlertStatusDismissed, username); err != nil {
 		as.Log.Errorf("Can't dismiss hostname %s alerts by filter", hostname)
 	}
 
@@ -405,6 +419,9 @@ func (as *APIService) DismissHost(hostname string) error {
 		return err
 	}
 
+	as.audit(model.AuditEntityHost, hostname, model.AuditActionDismiss,
+		map[string]bool{"dismissed": false}, map[string]bool{"dismissed": true})
+
 	alr := model.Alert{
 		ID:                      primitive.NewObjectIDFromTimestamp(as.TimeNow()),
 		AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
@@ -602,10 +602,26 @@ func TestSearchHostsAsXLSX(t *testing.T) {
 		db.EXPECT().
 			GetHostDataSummaries(filters).
 			Return(expectedRes, nil)
+		db.EXPECT().
+			ListHostMetadata(dto.HostMetadataFilter{}).
+			Return([]model.HostMetadata{
+				{
+					Hostname:            "test-db",
+					OwnerTeam:           "dba",
+					ContactEmail:        "dba@example.org",
+					BusinessApplication: "billing",
+					CostCenter:          "CC42",
+					Criticality:         model.HostCriticalityHigh,
+					Labels:              []string{"pci", "eu"},
+				},
+			}, nil)
 
 		sp, err := as.SearchHostsAsXLSX(filters)
 		assert.NoError(t, err)
 
+		assert.Equal(t, "Owner Team", sp.GetCellValue("Hosts", "T1"))
+		assert.Equal(t, "Labels", sp.GetCellValue("Hosts", "Y1"))
+
 		assert.Equal(t, "engelsiz-ee2ceb8e1e7fc19e4aeccbae135e2804", sp.GetCellValue("Hosts", "A2"))
 		assert.Equal(t, "Bare metal", sp.GetCellValue("Hosts", "B2"))
 		assert.Equal(t, "", sp.GetCellValue("Hosts", "C2"))
@@ -647,8 +663,12 @@ func TestSearchHostsAsXLSX(t *testing.T) {
 		assert.Equal(t, "", sp.GetCellValue("Hosts", "Q3"))
 		assert.Equal(t, "3", sp.GetCellValue("Hosts", "R3"))
 		assert.Equal(t, "1", sp.GetCellValue("Hosts", "S3"))
-		assert.Equal(t, "", sp.GetCellValue("Hosts", "T3"))
-		assert.Equal(t, "", sp.GetCellValue("Hosts", "U3"))
+		assert.Equal(t, "dba", sp.GetCellValue("Hosts", "T3"))
+		assert.Equal(t, "dba@example.org", sp.GetCellValue("Hosts", "U3"))
+		assert.Equal(t, "billing", sp.GetCellValue("Hosts", "V3"))
+		assert.Equal(t, "CC42", sp.GetCellValue("Hosts", "W3"))
+		assert.Equal(t, "HIGH", sp.GetCellValue("Hosts", "X3"))
+		assert.Equal(t, "pci, eu", sp.GetCellValue("Hosts", "Y3"))
 	})
 
 	t.Run("Db error", func(t *testing.T) {
@@ -976,8 +996,6 @@ func TestDismissHost_Success(t *testing.T) {
 		TimeNow:        alwaysTheSameMoment,
 	}
 
-	var count int64
-
 	expectedRes := []map[string]interface{}{
 		{
 			"hostname": "foobar",
@@ -988,9 +1006,18 @@ func TestDismissHost_Success(t *testing.T) {
 
 	filter := dto.AlertsFilter{OtherInfo: map[string]interface{}{"hostname": "foobar"}}
 	db.EXPECT().RemoveAlertsNODATA(filter).Return(nil).Times(1)
-	db.EXPECT().CountAlertsNODATA(filter).Return(count, nil).Times(1)
-	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusAck).Return(nil)
-	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusDismissed).Return(nil)
+	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusAck, model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: "mario",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusAck,
+	}).Return(nil)
+	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusDismissed, model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: "mario",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusDismissed,
+	}).Return(nil)
 	commonFilters := dto.NewSearchHostsFilters()
 	db.EXPECT().SearchHosts(
 		"hostnames",
@@ -1010,7 +1037,7 @@ func TestDismissHost_Success(t *testing.T) {
 
 	db.EXPECT().DismissHost("foobar").Return(nil).Times(1)
 
-	err := as.DismissHost("foobar")
+	err := as.DismissHost("foobar", "mario")
 	require.NoError(t, err)
 }
 
@@ -1021,10 +1048,9 @@ func TestDismissHost_Fail(t *testing.T) {
 	as := APIService{
 		Database: db,
 		Log:      logger.NewLogger("TEST"),
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
 	}
 
-	var count int64
-
 	expectedRes := []map[string]interface{}{
 		{
 			"hostname": "foobar",
@@ -1035,9 +1061,18 @@ func TestDismissHost_Fail(t *testing.T) {
 
 	filter := dto.AlertsFilter{OtherInfo: map[string]interface{}{"hostname": "foobar"}}
 	db.EXPECT().RemoveAlertsNODATA(filter).Return(nil).Times(1)
-	db.EXPECT().CountAlertsNODATA(filter).Return(count, nil).Times(1)
-	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusAck).Return(nil)
-	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusDismissed).Return(nil)
+	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusAck, model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: "mario",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusAck,
+	}).Return(nil)
+	db.EXPECT().UpdateAlertsStatus(filter, model.AlertStatusDismissed, model.AlertHistoryEntry{
+		Date:     as.TimeNow(),
+		Username: "mario",
+		Action:   model.AlertActionStatusChange,
+		Status:   model.AlertStatusDismissed,
+	}).Return(nil)
 	commonFilters := dto.NewSearchHostsFilters()
 	db.EXPECT().SearchHosts(
 		"hostnames",
@@ -1046,6 +1081,6 @@ func TestDismissHost_Fail(t *testing.T) {
 	db.EXPECT().ListOracleDatabaseContracts().Return(listContracts, nil)
 	db.EXPECT().DismissHost("foobar").Return(aerrMock).Times(1)
 
-	err := as.DismissHost("foobar")
+	err := as.DismissHost("foobar", "mario")
 	assert.Error(t, err)
 }
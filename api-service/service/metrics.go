@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"sort"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+)
+
+// noTechnology is the technology of the hosts without any known technology
+const noTechnology = "none"
+
+func (as *APIService) GetMetrics() (*dto.Metrics, error) {
+	hosts, err := as.Database.GetHostsMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	openAlerts, err := as.Database.GetOpenAlertsCount()
+	if err != nil {
+		return nil, err
+	}
+
+	licenses, err := as.GetDatabaseLicensesCompliance()
+	if err != nil {
+		return nil, err
+	}
+
+	now := as.TimeNow()
+
+	counts := make(map[dto.HostsCountMetric]int)
+	ages := make([]dto.HostDataAgeMetric, 0, len(hosts))
+
+	for _, host := range hosts {
+		technologies := host.Technologies
+		if len(technologies) == 0 {
+			technologies = []string{noTechnology}
+		}
+
+		for _, technology := range technologies {
+			counts[dto.HostsCountMetric{Location: host.Location, Environment: host.Environment, Technology: technology}]++
+		}
+
+		ages = append(ages, dto.HostDataAgeMetric{
+			Hostname:    host.Hostname,
+			Location:    host.Location,
+			Environment: host.Environment,
+			Age:         now.Sub(host.CreatedAt).Seconds(),
+		})
+	}
+
+	hostsCount := make([]dto.HostsCountMetric, 0, len(counts))
+
+	for key, count := range counts {
+		key.Count = count
+		hostsCount = append(hostsCount, key)
+	}
+
+	sort.Slice(hostsCount, func(i, j int) bool {
+		a, b := hostsCount[i], hostsCount[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+
+		if a.Environment != b.Environment {
+			return a.Environment < b.Environment
+		}
+
+		return a.Technology < b.Technology
+	})
+
+	return &dto.Metrics{
+		HostsCount:        hostsCount,
+		HostDataAge:       ages,
+		OpenAlerts:        openAlerts,
+		LicenseCompliance: licenses,
+	}, nil
+}
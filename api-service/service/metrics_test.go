@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetMetrics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Config: config.Configuration{
+			ResourceFilePath: "../../resources",
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:00:00Z")),
+	}
+
+	hosts := []dto.HostMetrics{
+		{
+			Hostname:     "pippo",
+			Location:     "Italy",
+			Environment:  "PROD",
+			CreatedAt:    utils.P("2019-11-05T13:00:00Z"),
+			Technologies: []string{model.TechnologyOracleDatabase, model.TechnologyOracleMySQL},
+		},
+		{
+			Hostname:     "pluto",
+			Location:     "Italy",
+			Environment:  "PROD",
+			CreatedAt:    utils.P("2019-11-04T14:00:00Z"),
+			Technologies: []string{model.TechnologyOracleDatabase},
+		},
+		{
+			Hostname:    "topolino",
+			Location:    "Germany",
+			Environment: "TST",
+			CreatedAt:   utils.P("2019-11-05T14:00:00Z"),
+		},
+	}
+	openAlerts := []dto.AlertsCount{
+		{AlertCode: model.AlertCodeNoData, AlertSeverity: model.AlertSeverityCritical, Count: 2},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		db.EXPECT().GetHostsMetrics().Return(hosts, nil)
+		db.EXPECT().GetOpenAlertsCount().Return(openAlerts, nil)
+
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return([]model.HostDataBE{}, nil).AnyTimes()
+		db.EXPECT().GetClusters(gomock.Any()).Return([]dto.Cluster{}, nil).AnyTimes()
+		db.EXPECT().ListOracleDatabaseContracts().Return([]dto.OracleDatabaseContractFE{}, nil).AnyTimes()
+		db.EXPECT().SearchOracleDatabaseUsedLicenses("", "", false, -1, -1, "", "", utils.MAX_TIME).
+			Return(&dto.OracleDatabaseUsedLicenseSearchResponse{Content: []dto.OracleDatabaseUsedLicense{}}, nil).AnyTimes()
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return([]model.OracleDatabaseLicenseType{}, nil).AnyTimes()
+		db.EXPECT().GetMySQLUsedLicenses("", gomock.Any()).Return([]dto.MySQLUsedLicense{}, nil).AnyTimes()
+		db.EXPECT().GetMySQLContracts().Return([]model.MySQLContract{}, nil).AnyTimes()
+		db.EXPECT().SearchSqlServerDatabaseUsedLicenses("", "", false, -1, -1, "", "", utils.MAX_TIME).
+			Return(&dto.SqlServerDatabaseUsedLicenseSearchResponse{Content: []dto.SqlServerDatabaseUsedLicense{}}, nil).AnyTimes()
+		db.EXPECT().ListSqlServerDatabaseContracts().Return([]model.SqlServerDatabaseContract{}, nil).AnyTimes()
+		db.EXPECT().GetSqlServerDatabaseLicenseTypes().Return([]model.SqlServerDatabaseLicenseType{}, nil).AnyTimes()
+
+		actual, err := as.GetMetrics()
+		require.NoError(t, err)
+
+		expected := &dto.Metrics{
+			HostsCount: []dto.HostsCountMetric{
+				{Location: "Germany", Environment: "TST", Technology: "none", Count: 1},
+				{Location: "Italy", Environment: "PROD", Technology: model.TechnologyOracleDatabase, Count: 2},
+				{Location: "Italy", Environment: "PROD", Technology: model.TechnologyOracleMySQL, Count: 1},
+			},
+			HostDataAge: []dto.HostDataAgeMetric{
+				{Hostname: "pippo", Location: "Italy", Environment: "PROD", Age: 3600},
+				{Hostname: "pluto", Location: "Italy", Environment: "PROD", Age: 86400},
+				{Hostname: "topolino", Location: "Germany", Environment: "TST", Age: 0},
+			},
+			OpenAlerts:        openAlerts,
+			LicenseCompliance: []dto.LicenseCompliance{},
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().GetHostsMetrics().Return(nil, aerrMock)
+
+		actual, err := as.GetMetrics()
+		assert.Nil(t, actual)
+		assert.Equal(t, aerrMock, err)
+	})
+}
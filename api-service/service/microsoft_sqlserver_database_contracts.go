@@ -41,7 +41,9 @@ func (as *APIService) AddSqlServerDatabaseContract(contract model.SqlServerDatab
 		return nil, err
 	}
 
-	agrs, err := as.GetSqlServerDatabaseContracts()
+	as.audit(model.AuditEntitySqlServerDatabaseContract, contract.ID.Hex(), model.AuditActionCreate, nil, contract)
+
+	agrs, err := as.GetSqlServerDatabaseContracts("")
 	if err != nil {
 		return nil, err
 	}
@@ -64,17 +66,33 @@ func (as *APIService) sqlServerLicenseTypeIDExists(licenseTypeID string) error {
 	return nil
 }
 
-func (as *APIService) GetSqlServerDatabaseContracts() ([]model.SqlServerDatabaseContract, error) {
+// GetSqlServerDatabaseContracts return the contracts with the hosts in the locations, comma separated, or all the contracts if location is empty.
+// The hosts of the other locations are removed from the contracts
+func (as *APIService) GetSqlServerDatabaseContracts(location string) ([]model.SqlServerDatabaseContract, error) {
 	contracts, err := as.Database.ListSqlServerDatabaseContracts()
 	if err != nil {
 		return nil, err
 	}
 
-	return contracts, nil
+	scope, err := as.hostnamesInLocations(location)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := make([]model.SqlServerDatabaseContract, 0, len(contracts))
+
+	for _, contract := range contracts {
+		var ok bool
+		if contract.Hosts, ok = scopeContractHosts(contract.Hosts, scope); ok {
+			scoped = append(scoped, contract)
+		}
+	}
+
+	return scoped, nil
 }
 
-func (as *APIService) GetSqlServerDatabaseContractsAsXLSX() (*excelize.File, error) {
-	contracts, err := as.GetSqlServerDatabaseContracts()
+func (as *APIService) GetSqlServerDatabaseContractsAsXLSX(location string) (*excelize.File, error) {
+	contracts, err := as.GetSqlServerDatabaseContracts(location)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +145,18 @@ func (as *APIService) GetSqlServerDatabaseContractsAsXLSX() (*excelize.File, err
 }
 
 func (as *APIService) DeleteSqlServerDatabaseContract(id primitive.ObjectID) error {
-	return as.Database.RemoveSqlServerDatabaseContract(id)
+	var before *model.SqlServerDatabaseContract
+	if as.isAudited() {
+		before = as.getSqlServerDatabaseContractForAudit(id)
+	}
+
+	if err := as.Database.RemoveSqlServerDatabaseContract(id); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntitySqlServerDatabaseContract, id.Hex(), model.AuditActionDelete, before, nil)
+
+	return nil
 }
 
 func (as *APIService) UpdateSqlServerDatabaseContract(contract model.SqlServerDatabaseContract) (*model.SqlServerDatabaseContract, error) {
@@ -139,11 +168,18 @@ func (as *APIService) UpdateSqlServerDatabaseContract(contract model.SqlServerDa
 		return nil, err
 	}
 
+	var before *model.SqlServerDatabaseContract
+	if as.isAudited() {
+		before = as.getSqlServerDatabaseContractForAudit(contract.ID)
+	}
+
 	if err := as.Database.UpdateSqlServerDatabaseContract(contract); err != nil {
 		return nil, err
 	}
 
-	agrs, err := as.GetSqlServerDatabaseContracts()
+	as.audit(model.AuditEntitySqlServerDatabaseContract, contract.ID.Hex(), model.AuditActionUpdate, before, contract)
+
+	agrs, err := as.GetSqlServerDatabaseContracts("")
 	if err != nil {
 		return nil, err
 	}
@@ -30,14 +30,23 @@ import (
 
 func (as *APIService) SearchSqlServerInstances(f dto.SearchSqlServerInstancesFilter) (*dto.SqlServerInstanceResponse, error) {
 	return as.Database.SearchSqlServerInstances(strings.Split(f.Search, " "), f.SortBy, f.SortDesc,
-		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan)
+		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter)
+}
+
+func (as *APIService) ListSqlServerInstances(f dto.SearchSqlServerInstancesFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListSqlServerInstances(strings.Split(f.Search, " "), f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter, q)
 }
 
 func (as *APIService) SearchSqlServerInstancesAsXLSX(filter dto.SearchSqlServerInstancesFilter) (*excelize.File, error) {
 	instances, err := as.Database.SearchSqlServerInstances(strings.Split(filter.Search, " "),
 		filter.SortBy, filter.SortDesc,
 		-1, -1,
-		filter.Location, filter.Environment, filter.OlderThan)
+		filter.Location, filter.Environment, filter.OlderThan, filter.HostMetadataFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := as.hostMetadataByHostname()
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +61,7 @@ func (as *APIService) SearchSqlServerInstancesAsXLSX(filter dto.SearchSqlServerI
 		"CollationName",
 		"Version",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -69,6 +79,10 @@ func (as *APIService) SearchSqlServerInstancesAsXLSX(filter dto.SearchSqlServerI
 		file.SetCellValue(sheet, nextAxis(), val.Edition)
 		file.SetCellValue(sheet, nextAxis(), val.CollationName)
 		file.SetCellValue(sheet, nextAxis(), val.Version)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return file, nil
@@ -72,13 +72,13 @@ func TestSearchSqlServerInstances_Success(t *testing.T) {
 	db.EXPECT().SearchSqlServerInstances(
 		[]string{"foo", "bar", "foobarx"}, "Hostname",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(&expectedRes, nil).Times(1)
 
 	res, err := as.SearchSqlServerInstances(
 		dto.SearchSqlServerInstancesFilter{
 			dto.GlobalFilter{
-				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 			},
 			"foo bar foobarx", "Hostname",
 			true, 1, 1,
@@ -100,14 +100,14 @@ func TestSearchSqlServerInstances_Fail(t *testing.T) {
 	db.EXPECT().SearchSqlServerInstances(
 		[]string{"foo", "bar", "foobarx"}, "Memory",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(nil, aerrMock).Times(1)
 
 	res, err := as.SearchSqlServerInstances(
 
 		dto.SearchSqlServerInstancesFilter{
 			dto.GlobalFilter{
-				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 			},
 			"foo bar foobarx", "Memory",
 			true, 1, 1,
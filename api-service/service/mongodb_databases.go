@@ -27,14 +27,23 @@ import (
 
 func (as *APIService) SearchMongoDBInstances(f dto.SearchMongoDBInstancesFilter) (*dto.MongoDBInstanceResponse, error) {
 	return as.Database.SearchMongoDBInstances(strings.Split(f.Search, " "), f.SortBy, f.SortDesc,
-		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan)
+		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter)
+}
+
+func (as *APIService) ListMongoDBInstances(f dto.SearchMongoDBInstancesFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListMongoDBInstances(strings.Split(f.Search, " "), f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter, q)
 }
 
 func (as *APIService) SearchMongoDBInstancesAsXLSX(filter dto.SearchMongoDBInstancesFilter) (*excelize.File, error) {
 	instances, err := as.Database.SearchMongoDBInstances(strings.Split(filter.Search, " "),
 		filter.SortBy, filter.SortDesc,
 		-1, -1,
-		filter.Location, filter.Environment, filter.OlderThan)
+		filter.Location, filter.Environment, filter.OlderThan, filter.HostMetadataFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := as.hostMetadataByHostname()
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +58,7 @@ func (as *APIService) SearchMongoDBInstancesAsXLSX(filter dto.SearchMongoDBInsta
 		"Charset",
 		"Version",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -66,6 +76,10 @@ func (as *APIService) SearchMongoDBInstancesAsXLSX(filter dto.SearchMongoDBInsta
 		file.SetCellValue(sheet, nextAxis(), val.DBName)
 		file.SetCellValue(sheet, nextAxis(), val.Charset)
 		file.SetCellValue(sheet, nextAxis(), val.Version)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return file, nil
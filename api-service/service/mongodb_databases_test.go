@@ -70,7 +70,7 @@ func TestSearchMongoDBInstances_Success(t *testing.T) {
 	db.EXPECT().SearchMongoDBInstances(
 		[]string{"foo", "bar", "foobarx"}, "Hostname",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(&expectedRes, nil).Times(1)
 
 	res, err := as.SearchMongoDBInstances(
@@ -98,7 +98,7 @@ func TestSearchMongoDBInstances_Fail(t *testing.T) {
 	db.EXPECT().SearchMongoDBInstances(
 		[]string{"foo", "bar", "foobarx"}, "Memory",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(nil, aerrMock).Times(1)
 
 	res, err := as.SearchMongoDBInstances(
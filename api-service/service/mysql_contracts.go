@@ -32,36 +32,68 @@ func (as *APIService) AddMySQLContract(contract model.MySQLContract) (*model.MyS
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityMySQLContract, contract.ID.Hex(), model.AuditActionCreate, nil, contract)
+
 	return &contract, nil
 }
 
 func (as *APIService) UpdateMySQLContract(contract model.MySQLContract) (*model.MySQLContract, error) {
+	var before *model.MySQLContract
+	if as.isAudited() {
+		before = as.getMySQLContractForAudit(contract.ID)
+	}
+
 	if err := as.Database.UpdateMySQLContract(contract); err != nil {
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityMySQLContract, contract.ID.Hex(), model.AuditActionUpdate, before, contract)
+
 	return &contract, nil
 }
 
-func (as *APIService) GetMySQLContracts() ([]model.MySQLContract, error) {
+// GetMySQLContracts return the contracts with the hosts in the locations, comma separated, or all the contracts if location is empty.
+// The hosts of the other locations are removed from the contracts
+func (as *APIService) GetMySQLContracts(location string) ([]model.MySQLContract, error) {
 	contracts, err := as.Database.GetMySQLContracts()
 	if err != nil {
 		return nil, err
 	}
 
-	return contracts, nil
+	scope, err := as.hostnamesInLocations(location)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := make([]model.MySQLContract, 0, len(contracts))
+
+	for _, contract := range contracts {
+		var ok bool
+		if contract.Hosts, ok = scopeContractHosts(contract.Hosts, scope); ok {
+			scoped = append(scoped, contract)
+		}
+	}
+
+	return scoped, nil
 }
 
 func (as *APIService) DeleteMySQLContract(id primitive.ObjectID) error {
+	var before *model.MySQLContract
+	if as.isAudited() {
+		before = as.getMySQLContractForAudit(id)
+	}
+
 	if err := as.Database.DeleteMySQLContract(id); err != nil {
 		return err
 	}
 
+	as.audit(model.AuditEntityMySQLContract, id.Hex(), model.AuditActionDelete, before, nil)
+
 	return nil
 }
 
-func (as *APIService) GetMySQLContractsAsXLSX() (*excelize.File, error) {
-	contracts, err := as.GetMySQLContracts()
+func (as *APIService) GetMySQLContractsAsXLSX(location string) (*excelize.File, error) {
+	contracts, err := as.GetMySQLContracts(location)
 	if err != nil {
 		return nil, err
 	}
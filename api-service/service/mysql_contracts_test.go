@@ -131,7 +131,7 @@ func TestGetMySQLContracts(t *testing.T) {
 		db.EXPECT().GetMySQLContracts().
 			Return(expected, nil).Times(1)
 
-		actual, err := as.GetMySQLContracts()
+		actual, err := as.GetMySQLContracts("")
 		require.NoError(t, err)
 
 		assert.Equal(t, expected, actual)
@@ -141,11 +141,47 @@ func TestGetMySQLContracts(t *testing.T) {
 		db.EXPECT().GetMySQLContracts().
 			Return(nil, errMock).Times(1)
 
-		actual, err := as.GetMySQLContracts()
+		actual, err := as.GetMySQLContracts("")
 		require.EqualError(t, err, "MockError")
 
 		assert.Nil(t, actual)
 	})
+
+	t.Run("Scoped to the location", func(t *testing.T) {
+		contracts := []model.MySQLContract{
+			{
+				ID:    utils.Str2oid("000000000000000000000001"),
+				Hosts: []string{"italy-host", "france-host"},
+			},
+			{
+				ID:    utils.Str2oid("000000000000000000000002"),
+				Hosts: []string{"france-host"},
+			},
+			{
+				ID:    utils.Str2oid("000000000000000000000003"),
+				Hosts: []string{},
+			},
+		}
+		db.EXPECT().ListHostnamesByLocations("Italy").
+			Return([]string{"italy-host"}, nil).Times(1)
+		db.EXPECT().GetMySQLContracts().
+			Return(contracts, nil).Times(1)
+
+		actual, err := as.GetMySQLContracts("Italy")
+		require.NoError(t, err)
+
+		expected := []model.MySQLContract{
+			{
+				ID:    utils.Str2oid("000000000000000000000001"),
+				Hosts: []string{"italy-host"},
+			},
+			{
+				ID:    utils.Str2oid("000000000000000000000003"),
+				Hosts: []string{},
+			},
+		}
+		assert.Equal(t, expected, actual)
+	})
 }
 
 func TestGetMySQLContractsAsXLSX_Success(t *testing.T) {
@@ -173,7 +209,7 @@ func TestGetMySQLContractsAsXLSX_Success(t *testing.T) {
 	db.EXPECT().GetMySQLContracts().
 		Return(data, nil).Times(1)
 
-	actual, err := as.GetMySQLContractsAsXLSX()
+	actual, err := as.GetMySQLContractsAsXLSX("")
 	require.NoError(t, err)
 	assert.Equal(t, "server", actual.GetCellValue("Contracts", "A2"))
 	assert.Equal(t, "", actual.GetCellValue("Contracts", "B2"))
@@ -15,11 +15,16 @@
 
 package service
 
+import "github.com/ercole-io/ercole/v2/model"
+
 // UpdateMySqlLicenseIgnoredField update license ignored field (true/false)
 func (as *APIService) UpdateMySqlLicenseIgnoredField(hostname string, instancename string, ignored bool, ignoredComment string) error {
 	if err := as.Database.UpdateMySqlLicenseIgnoredField(hostname, instancename, ignored, ignoredComment); err != nil {
 		return err
 	}
 
+	as.audit(model.AuditEntityMySQLLicense, hostname+"/"+instancename, model.AuditActionUpdate, nil,
+		model.IgnoredLicenseChange{Ignored: ignored, IgnoredComment: ignoredComment})
+
 	return nil
 }
@@ -37,12 +37,21 @@ func (as *APIService) SearchMySQLInstances(filter dto.GlobalFilter) ([]dto.MySQL
 	return instances, nil
 }
 
+func (as *APIService) ListMySQLInstances(filter dto.GlobalFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListMySQLInstances(filter, q)
+}
+
 func (as *APIService) SearchMySQLInstancesAsXLSX(filter dto.GlobalFilter) (*excelize.File, error) {
 	instances, err := as.Database.SearchMySQLInstances(filter)
 	if err != nil {
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Instances"
 	headers := []string{
 		"Name",
@@ -64,6 +73,7 @@ func (as *APIService) SearchMySQLInstancesAsXLSX(filter dto.GlobalFilter) (*exce
 		"Databases",
 		"Table Schemas",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -104,6 +114,10 @@ func (as *APIService) SearchMySQLInstancesAsXLSX(filter dto.GlobalFilter) (*exce
 		}
 
 		file.SetCellValue(sheet, nextAxis(), strings.Join(tableSchemas, ", "))
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return file, nil
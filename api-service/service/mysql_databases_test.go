@@ -159,6 +159,8 @@ func TestSearchMySQLInstancesAsXLSX_Success(t *testing.T) {
 
 	db.EXPECT().SearchMySQLInstances(globalFilter).
 		Return(returned, nil)
+	db.EXPECT().ListHostMetadata(dto.HostMetadataFilter{}).
+		Return([]model.HostMetadata{{Hostname: "pippo", OwnerTeam: "dba", Labels: []string{"pci", "eu"}}}, nil)
 
 	actual, err := as.SearchMySQLInstancesAsXLSX(globalFilter)
 	require.NoError(t, err)
@@ -174,6 +176,12 @@ func TestSearchMySQLInstancesAsXLSX_Success(t *testing.T) {
 	assert.Equal(t, "Table Schemas", actual.GetCellValue("Instances", "R1"))
 	assert.Equal(t, "marte, venere, saturno", actual.GetCellValue("Instances", "R2"))
 	assert.Equal(t, "", actual.GetCellValue("Instances", "R3"))
+
+	assert.Equal(t, "Owner Team", actual.GetCellValue("Instances", "S1"))
+	assert.Equal(t, "dba", actual.GetCellValue("Instances", "S2"))
+	assert.Equal(t, "", actual.GetCellValue("Instances", "S3"))
+	assert.Equal(t, "Labels", actual.GetCellValue("Instances", "X1"))
+	assert.Equal(t, "pci, eu", actual.GetCellValue("Instances", "X2"))
 }
 
 func TestGetMySQLUsedLicenses(t *testing.T) {
@@ -36,6 +36,11 @@ func (as *APIService) CreateGetOracleBackupListXLSX(filter dto.GlobalFilter) (*e
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Backups"
 	headers := []string{
 		"Hostname",
@@ -46,6 +51,7 @@ func (as *APIService) CreateGetOracleBackupListXLSX(filter dto.GlobalFilter) (*e
 		"Average",
 		"RMAN",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -63,6 +69,10 @@ func (as *APIService) CreateGetOracleBackupListXLSX(filter dto.GlobalFilter) (*e
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabaseBackup.BackupType)
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabaseBackup.AvgBckSize)
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabaseBackup.Retention)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
@@ -0,0 +1,454 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/exutils"
+)
+
+// PlanOracleDatabaseConsolidation place the Oracle databases of the current hosts on the target shapes,
+// minimising the processor licenses, and return the licenses before and after the consolidation. Nothing is saved
+func (as *APIService) PlanOracleDatabaseConsolidation(request dto.OracleDatabaseConsolidationRequest) (*dto.OracleDatabaseConsolidationPlan, error) {
+	if err := validateConsolidationRequest(request); err != nil {
+		return nil, err
+	}
+
+	hostdatas, err := as.Database.GetHostDatas(utils.MAX_TIME)
+	if err != nil {
+		return nil, err
+	}
+
+	licenseTypes, err := as.Database.GetOracleDatabaseLicenseTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	processorLicenseTypes := make(map[string]bool, len(licenseTypes))
+
+	for _, licenseType := range licenseTypes {
+		if licenseType.Metric == model.LicenseTypeMetricProcessorPerpetual && !licenseType.Option {
+			processorLicenseTypes[licenseType.ID] = true
+		}
+	}
+
+	hosts := make([]model.HostDataBE, 0, len(hostdatas))
+	hostnames := make([]string, 0, len(hostdatas))
+
+	for _, hostdata := range hostdatas {
+		if hostdata.Features.Oracle == nil || hostdata.Features.Oracle.Database == nil ||
+			len(hostdata.Features.Oracle.Database.Databases) == 0 || !consolidationRequestMatches(request, &hostdata) {
+			continue
+		}
+
+		hosts = append(hosts, hostdata)
+		hostnames = append(hostnames, hostdata.Hostname)
+	}
+
+	historyDays := request.HistoryDays
+	if historyDays == 0 {
+		historyDays = as.Config.DataService.ConsumptionMetrics.WorkloadDays
+	}
+
+	metrics, err := as.Database.GetOracleDatabaseConsumptionMetrics(hostnames, as.TimeNow().AddDate(0, 0, -historyDays))
+	if err != nil {
+		return nil, err
+	}
+
+	metricsBySeries := make(map[model.ConsumptionMetricMeta][]model.ConsumptionMetric)
+	for _, metric := range metrics {
+		metricsBySeries[metric.Meta] = append(metricsBySeries[metric.Meta], metric)
+	}
+
+	plan := &dto.OracleDatabaseConsolidationPlan{
+		Sources: make([]dto.ConsolidationSource, 0, len(hosts)),
+	}
+	databases := make([]dto.ConsolidationDatabase, 0)
+
+	for i := range hosts {
+		host := &hosts[i]
+		source := dto.ConsolidationSource{
+			Hostname: host.Hostname,
+			CPUCores: host.Info.CPUCores,
+		}
+
+		for j := range host.Features.Oracle.Database.Databases {
+			db := &host.Features.Oracle.Database.Databases[j]
+
+			for _, license := range db.Licenses {
+				if !license.Ignored && processorLicenseTypes[license.LicenseTypeID] {
+					source.ProcessorLicenses = math.Max(source.ProcessorLicenses, license.Count)
+				}
+			}
+
+			meta := model.ConsumptionMetricMeta{Hostname: host.Hostname, DatabaseName: db.Name}
+			databases = append(databases, newConsolidationDatabase(host, db, metricsBySeries[meta]))
+		}
+
+		plan.Sources = append(plan.Sources, source)
+		plan.CurrentProcessorLicenses += source.ProcessorLicenses
+	}
+
+	plan.Targets, plan.Unplaced = planConsolidation(databases, request.Shapes, request.CPUHeadroom, request.MemoryHeadroom)
+
+	for _, target := range plan.Targets {
+		plan.PlannedProcessorLicenses += target.ProcessorLicenses
+	}
+
+	for i := range plan.Sources {
+		source := &plan.Sources[i]
+
+		for _, db := range plan.Unplaced {
+			if db.Hostname == source.Hostname {
+				source.Retained = true
+			}
+		}
+
+		if source.Retained {
+			plan.PlannedProcessorLicenses += source.ProcessorLicenses
+		}
+	}
+
+	plan.ProcessorLicensesSavings = plan.CurrentProcessorLicenses - plan.PlannedProcessorLicenses
+
+	return plan, nil
+}
+
+func validateConsolidationRequest(request dto.OracleDatabaseConsolidationRequest) error {
+	invalid := func(format string, a ...interface{}) error {
+		return utils.NewError(utils.ErrInvalidConsolidationRequest, fmt.Sprintf(format, a...))
+	}
+
+	if len(request.Shapes) == 0 {
+		return invalid("No shapes")
+	}
+
+	names := make(map[string]bool, len(request.Shapes))
+
+	for _, shape := range request.Shapes {
+		switch {
+		case shape.Name == "" || names[shape.Name]:
+			return invalid("Missing or duplicated shape name %q", shape.Name)
+		case shape.CPUCores <= 0:
+			return invalid("Invalid cores %d of the shape %q", shape.CPUCores, shape.Name)
+		case shape.Memory <= 0:
+			return invalid("Invalid memory %v of the shape %q", shape.Memory, shape.Name)
+		case shape.Storage < 0:
+			return invalid("Invalid storage %v of the shape %q", shape.Storage, shape.Name)
+		case shape.CoreFactor <= 0:
+			return invalid("Invalid core factor %v of the shape %q", shape.CoreFactor, shape.Name)
+		case shape.MaxHosts < 0:
+			return invalid("Invalid max hosts %d of the shape %q", shape.MaxHosts, shape.Name)
+		}
+
+		names[shape.Name] = true
+	}
+
+	if request.CPUHeadroom < 0 || request.CPUHeadroom >= 100 || request.MemoryHeadroom < 0 || request.MemoryHeadroom >= 100 {
+		return invalid("The headrooms must be between 0 and 100")
+	}
+
+	if request.HistoryDays < 0 {
+		return invalid("Invalid history days %d", request.HistoryDays)
+	}
+
+	return nil
+}
+
+func consolidationRequestMatches(request dto.OracleDatabaseConsolidationRequest, host *model.HostDataBE) bool {
+	if request.Location != "" && !utils.Contains(strings.Split(request.Location, ","), host.Location) {
+		return false
+	}
+
+	if request.Environment != "" && host.Environment != request.Environment {
+		return false
+	}
+
+	return len(request.Hostnames) == 0 || utils.Contains(request.Hostnames, host.Hostname)
+}
+
+// newConsolidationDatabase return the resources required by the database. The CPU is the 95th percentile
+// of the consumption metrics, or of the consumptions in the hostdata if there are none, or the cpu_count
+func newConsolidationDatabase(host *model.HostDataBE, db *model.OracleDatabase, metrics []model.ConsumptionMetric) dto.ConsolidationDatabase {
+	database := dto.ConsolidationDatabase{
+		Hostname: host.Hostname,
+		Name:     db.Name,
+		Memory:   math.Max(db.SGATarget+db.PGATarget, db.MemoryTarget),
+		Storage:  db.DatafileSize,
+	}
+
+	if cpu := model.NewConsumptionRollups(metrics).Cpu; cpu.Samples > 0 {
+		database.CPU, database.CPUSource = cpu.P95, dto.ConsolidationCPUSourceHistory
+		return database
+	}
+
+	hostdataMetrics := make([]model.ConsumptionMetric, 0)
+
+	for _, metric := range host.ConsumptionMetrics() {
+		if metric.Meta.DatabaseName == db.Name {
+			hostdataMetrics = append(hostdataMetrics, metric)
+		}
+	}
+
+	if cpu := model.NewConsumptionRollups(hostdataMetrics).Cpu; cpu.Samples > 0 {
+		database.CPU, database.CPUSource = cpu.P95, dto.ConsolidationCPUSourceHostdata
+		return database
+	}
+
+	database.CPU, database.CPUSource = float64(db.CPUCount), dto.ConsolidationCPUSourceCPUCount
+
+	return database
+}
+
+// consolidationBin is a target host being filled
+type consolidationBin struct {
+	shape     int
+	cpu       float64
+	memory    float64
+	storage   float64
+	databases []dto.ConsolidationDatabase
+}
+
+// shapeProcessorLicenses return the processor licenses of a host of the shape, rounded up
+func shapeProcessorLicenses(shape dto.ConsolidationHostShape) float64 {
+	return math.Ceil(float64(shape.CPUCores) * shape.CoreFactor)
+}
+
+// planConsolidation place the databases with a best-fit decreasing on the CPU, opening the hosts of the shape with
+// the fewest processor licenses per core that fits, then downsizes every host to the cheapest shape that fits its databases
+func planConsolidation(databases []dto.ConsolidationDatabase, shapes []dto.ConsolidationHostShape,
+	cpuHeadroom, memoryHeadroom float64) ([]dto.ConsolidationTarget, []dto.ConsolidationDatabase) {
+	cpuCapacity := func(shape int) float64 {
+		return float64(shapes[shape].CPUCores) * (1 - cpuHeadroom/100)
+	}
+	memoryCapacity := func(shape int) float64 {
+		return shapes[shape].Memory * (1 - memoryHeadroom/100)
+	}
+	fits := func(shape int, cpu, memory, storage float64) bool {
+		return cpu <= cpuCapacity(shape) && memory <= memoryCapacity(shape) &&
+			(shapes[shape].Storage == 0 || storage <= shapes[shape].Storage)
+	}
+
+	shapesByLicenses := make([]int, len(shapes))
+	shapesByLicensesPerCore := make([]int, len(shapes))
+
+	for i := range shapes {
+		shapesByLicenses[i] = i
+		shapesByLicensesPerCore[i] = i
+	}
+
+	sort.SliceStable(shapesByLicenses, func(i, j int) bool {
+		a, b := shapes[shapesByLicenses[i]], shapes[shapesByLicenses[j]]
+		if shapeProcessorLicenses(a) != shapeProcessorLicenses(b) {
+			return shapeProcessorLicenses(a) < shapeProcessorLicenses(b)
+		}
+
+		return a.CPUCores < b.CPUCores
+	})
+	sort.SliceStable(shapesByLicensesPerCore, func(i, j int) bool {
+		a, b := shapes[shapesByLicensesPerCore[i]], shapes[shapesByLicensesPerCore[j]]
+		aPerCore := shapeProcessorLicenses(a) / float64(a.CPUCores)
+		bPerCore := shapeProcessorLicenses(b) / float64(b.CPUCores)
+
+		if aPerCore != bPerCore {
+			return aPerCore < bPerCore
+		}
+
+		return shapeProcessorLicenses(a) < shapeProcessorLicenses(b)
+	})
+
+	sorted := make([]dto.ConsolidationDatabase, len(databases))
+	copy(sorted, databases)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CPU != sorted[j].CPU {
+			return sorted[i].CPU > sorted[j].CPU
+		}
+
+		return sorted[i].Memory > sorted[j].Memory
+	})
+
+	bins := make([]*consolidationBin, 0)
+	hostsByShape := make([]int, len(shapes))
+	available := func(shape int) bool {
+		return shapes[shape].MaxHosts == 0 || hostsByShape[shape] < shapes[shape].MaxHosts
+	}
+	unplaced := make([]dto.ConsolidationDatabase, 0)
+
+	for _, db := range sorted {
+		var best *consolidationBin
+
+		for _, bin := range bins {
+			if !fits(bin.shape, bin.cpu+db.CPU, bin.memory+db.Memory, bin.storage+db.Storage) {
+				continue
+			}
+
+			if best == nil || cpuCapacity(bin.shape)-bin.cpu < cpuCapacity(best.shape)-best.cpu {
+				best = bin
+			}
+		}
+
+		if best == nil {
+			for _, shape := range shapesByLicensesPerCore {
+				if available(shape) && fits(shape, db.CPU, db.Memory, db.Storage) {
+					best = &consolidationBin{shape: shape}
+					bins = append(bins, best)
+					hostsByShape[shape]++
+
+					break
+				}
+			}
+		}
+
+		if best == nil {
+			unplaced = append(unplaced, db)
+			continue
+		}
+
+		best.cpu += db.CPU
+		best.memory += db.Memory
+		best.storage += db.Storage
+		best.databases = append(best.databases, db)
+	}
+
+	for _, bin := range bins {
+		for _, shape := range shapesByLicenses {
+			if shapeProcessorLicenses(shapes[shape]) >= shapeProcessorLicenses(shapes[bin.shape]) {
+				break
+			}
+
+			if available(shape) && fits(shape, bin.cpu, bin.memory, bin.storage) {
+				hostsByShape[bin.shape]--
+				hostsByShape[shape]++
+				bin.shape = shape
+
+				break
+			}
+		}
+	}
+
+	targets := make([]dto.ConsolidationTarget, 0, len(bins))
+	counters := make(map[int]int, len(shapes))
+
+	for _, bin := range bins {
+		shape := shapes[bin.shape]
+		counters[bin.shape]++
+
+		targets = append(targets, dto.ConsolidationTarget{
+			Name:              fmt.Sprintf("%s-%d", shape.Name, counters[bin.shape]),
+			Shape:             shape.Name,
+			CPUCores:          shape.CPUCores,
+			CoreFactor:        shape.CoreFactor,
+			ProcessorLicenses: shapeProcessorLicenses(shape),
+			CPU:               bin.cpu,
+			Memory:            bin.memory,
+			Storage:           bin.storage,
+			Databases:         bin.databases,
+		})
+	}
+
+	return targets, unplaced
+}
+
+// PlanOracleDatabaseConsolidationAsXLSX return the consolidation plan as XLSX, a row for each database
+func (as *APIService) PlanOracleDatabaseConsolidationAsXLSX(request dto.OracleDatabaseConsolidationRequest) (*excelize.File, error) {
+	plan, err := as.PlanOracleDatabaseConsolidation(request)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := "Consolidation plan"
+	headers := []string{
+		"Target",
+		"Shape",
+		"Cores",
+		"Core factor",
+		"Processor licenses",
+		"Hostname",
+		"Database",
+		"CPU",
+		"CPU source",
+		"Memory",
+		"Storage",
+	}
+
+	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
+	if err != nil {
+		return nil, err
+	}
+
+	axisHelp := exutils.NewAxisHelper(1)
+
+	setDatabase := func(nextAxis func() string, db dto.ConsolidationDatabase) {
+		file.SetCellValue(sheet, nextAxis(), db.Hostname)
+		file.SetCellValue(sheet, nextAxis(), db.Name)
+		file.SetCellValue(sheet, nextAxis(), db.CPU)
+		file.SetCellValue(sheet, nextAxis(), db.CPUSource)
+		file.SetCellValue(sheet, nextAxis(), db.Memory)
+		file.SetCellValue(sheet, nextAxis(), db.Storage)
+	}
+
+	for _, target := range plan.Targets {
+		for _, db := range target.Databases {
+			nextAxis := axisHelp.NewRow()
+
+			file.SetCellValue(sheet, nextAxis(), target.Name)
+			file.SetCellValue(sheet, nextAxis(), target.Shape)
+			file.SetCellValue(sheet, nextAxis(), target.CPUCores)
+			file.SetCellValue(sheet, nextAxis(), target.CoreFactor)
+			file.SetCellValue(sheet, nextAxis(), target.ProcessorLicenses)
+			setDatabase(nextAxis, db)
+		}
+	}
+
+	for _, db := range plan.Unplaced {
+		nextAxis := axisHelp.NewRow()
+
+		file.SetCellValue(sheet, nextAxis(), "Not placed")
+		nextAxis()
+		nextAxis()
+		nextAxis()
+		nextAxis()
+		setDatabase(nextAxis, db)
+	}
+
+	summary := "Summary"
+	file.NewSheet(summary)
+	axisHelp = exutils.NewAxisHelper(0)
+
+	for _, row := range []struct {
+		label string
+		value float64
+	}{
+		{"Current processor licenses", plan.CurrentProcessorLicenses},
+		{"Planned processor licenses", plan.PlannedProcessorLicenses},
+		{"Processor licenses savings", plan.ProcessorLicensesSavings},
+	} {
+		nextAxis := axisHelp.NewRow()
+
+		file.SetCellValue(summary, nextAxis(), row.label)
+		file.SetCellValue(summary, nextAxis(), row.value)
+	}
+
+	return file, nil
+}
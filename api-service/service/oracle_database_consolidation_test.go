@@ -0,0 +1,285 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestPlanConsolidation(t *testing.T) {
+	shapes := []dto.ConsolidationHostShape{
+		{Name: "small", CPUCores: 4, Memory: 32, CoreFactor: 1},
+		{Name: "large", CPUCores: 16, Memory: 128, Storage: 1000, CoreFactor: 0.5},
+	}
+
+	t.Run("Databases packed on the shape with fewest licenses per core", func(t *testing.T) {
+		databases := []dto.ConsolidationDatabase{
+			{Hostname: "db1", Name: "A", CPU: 2, Memory: 12, Storage: 100},
+			{Hostname: "db1", Name: "B", CPU: 4, Memory: 5, Storage: 50},
+			{Hostname: "db2", Name: "C", CPU: 3, Memory: 24, Storage: 200},
+		}
+
+		targets, unplaced := planConsolidation(databases, shapes, 0, 0)
+		require.Len(t, targets, 1)
+		assert.Empty(t, unplaced)
+
+		assert.Equal(t, "large-1", targets[0].Name)
+		assert.Equal(t, 8.0, targets[0].ProcessorLicenses)
+		assert.Equal(t, 9.0, targets[0].CPU)
+		assert.Equal(t, 41.0, targets[0].Memory)
+		assert.Equal(t, 350.0, targets[0].Storage)
+		assert.Equal(t, []string{"B", "C", "A"},
+			[]string{targets[0].Databases[0].Name, targets[0].Databases[1].Name, targets[0].Databases[2].Name})
+	})
+
+	t.Run("Host downsized to the cheapest shape", func(t *testing.T) {
+		databases := []dto.ConsolidationDatabase{
+			{Hostname: "db1", Name: "A", CPU: 1, Memory: 8},
+		}
+
+		targets, unplaced := planConsolidation(databases, shapes, 0, 0)
+		require.Len(t, targets, 1)
+		assert.Empty(t, unplaced)
+
+		assert.Equal(t, "small-1", targets[0].Name)
+		assert.Equal(t, 4.0, targets[0].ProcessorLicenses)
+	})
+
+	t.Run("Headrooms and max hosts", func(t *testing.T) {
+		limited := []dto.ConsolidationHostShape{
+			{Name: "large", CPUCores: 16, Memory: 128, CoreFactor: 0.5, MaxHosts: 1},
+		}
+		databases := []dto.ConsolidationDatabase{
+			{Hostname: "db1", Name: "A", CPU: 8, Memory: 8},
+			{Hostname: "db2", Name: "B", CPU: 6, Memory: 8},
+			{Hostname: "db3", Name: "C", CPU: 0.1, Memory: 100},
+		}
+
+		targets, unplaced := planConsolidation(databases, limited, 10, 20)
+		require.Len(t, targets, 1)
+		assert.Equal(t, 14.0, targets[0].CPU)
+
+		require.Len(t, unplaced, 1)
+		assert.Equal(t, "C", unplaced[0].Name)
+	})
+
+	t.Run("Database too big for every shape", func(t *testing.T) {
+		databases := []dto.ConsolidationDatabase{
+			{Hostname: "db1", Name: "A", CPU: 32, Memory: 8},
+		}
+
+		targets, unplaced := planConsolidation(databases, shapes, 0, 0)
+		assert.Empty(t, targets)
+		assert.Len(t, unplaced, 1)
+	})
+}
+
+func TestPlanOracleDatabaseConsolidation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Config: config.Configuration{
+			ResourceFilePath: "../../resources",
+			DataService: config.DataService{
+				ConsumptionMetrics: config.ConsumptionMetrics{WorkloadDays: 30},
+			},
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-12-10T00:00:00Z")),
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	licenseTypes := []model.OracleDatabaseLicenseType{
+		{ID: "A90611", Metric: model.LicenseTypeMetricProcessorPerpetual},
+		{ID: "A90619", Metric: model.LicenseTypeMetricProcessorPerpetual, Option: true},
+		{ID: "A90610", Metric: model.LicenseTypeMetricNamedUserPlusPerpetual},
+	}
+
+	processorLicense := func(count float64) []model.OracleDatabaseLicense {
+		return []model.OracleDatabaseLicense{
+			{LicenseTypeID: "A90611", Count: count},
+			{LicenseTypeID: "A90619", Count: count * 2},
+			{LicenseTypeID: "A90610", Count: 100},
+		}
+	}
+
+	oracleFeature := func(databases ...model.OracleDatabase) model.Features {
+		return model.Features{
+			Oracle: &model.OracleFeature{
+				Database: &model.OracleDatabaseFeature{Databases: databases},
+			},
+		}
+	}
+
+	hostdataStart, hostdataEnd := utils.P("2019-12-08T00:00:00Z"), utils.P("2019-12-09T00:00:00Z")
+	hostdataCPU := 3.0
+
+	hostdatas := []model.HostDataBE{
+		{
+			Hostname: "db1", Location: "Italy", Environment: "PRD",
+			Info: model.Host{CPUCores: 8},
+			Features: oracleFeature(
+				model.OracleDatabase{Name: "A", CPUCount: 8, SGATarget: 10, PGATarget: 2, DatafileSize: 100, Licenses: processorLicense(4)},
+				model.OracleDatabase{Name: "B", CPUCount: 4, SGATarget: 4, PGATarget: 1, DatafileSize: 50, Licenses: processorLicense(4)},
+			),
+		},
+		{
+			Hostname: "db2", Location: "Italy", Environment: "PRD",
+			Info: model.Host{CPUCores: 16},
+			Features: oracleFeature(
+				model.OracleDatabase{Name: "C", CPUCount: 16, MemoryTarget: 24, DatafileSize: 200, Licenses: processorLicense(8),
+					CpuDiskConsumptions: []model.CpuDiskConsumption{
+						{TimeStart: &hostdataStart, TimeEnd: &hostdataEnd, CpuDbAvg: &hostdataCPU},
+					},
+				},
+			),
+		},
+		{
+			Hostname: "db3", Location: "Italy", Environment: "PRD",
+			Info:     model.Host{CPUCores: 32},
+			Features: oracleFeature(model.OracleDatabase{Name: "D", CPUCount: 32, Licenses: processorLicense(16)}),
+		},
+		{
+			Hostname: "db4", Location: "Germany", Environment: "PRD",
+			Info:     model.Host{CPUCores: 4},
+			Features: oracleFeature(model.OracleDatabase{Name: "E", CPUCount: 4, Licenses: processorLicense(2)}),
+		},
+		{
+			Hostname: "app1", Location: "Italy", Environment: "PRD",
+		},
+	}
+
+	cpu1, cpu2 := 1.0, 2.0
+	metrics := []model.ConsumptionMetric{
+		{Timestamp: utils.P("2019-12-01T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "db1", DatabaseName: "A"}, CpuAvg: &cpu1},
+		{Timestamp: utils.P("2019-12-02T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "db1", DatabaseName: "A"}, CpuAvg: &cpu2},
+	}
+
+	request := dto.OracleDatabaseConsolidationRequest{
+		Location: "Italy",
+		Shapes: []dto.ConsolidationHostShape{
+			{Name: "small", CPUCores: 4, Memory: 32, CoreFactor: 1},
+			{Name: "large", CPUCores: 16, Memory: 128, CoreFactor: 0.5},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(hostdatas, nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(licenseTypes, nil)
+		db.EXPECT().GetOracleDatabaseConsumptionMetrics([]string{"db1", "db2", "db3"}, utils.P("2019-11-10T00:00:00Z")).
+			Return(metrics, nil)
+
+		actual, err := as.PlanOracleDatabaseConsolidation(request)
+		require.NoError(t, err)
+
+		require.Len(t, actual.Targets, 1)
+		target := actual.Targets[0]
+		assert.Equal(t, "large-1", target.Name)
+		require.Len(t, target.Databases, 3)
+		assert.Equal(t, dto.ConsolidationDatabase{Hostname: "db1", Name: "B", CPU: 4, CPUSource: dto.ConsolidationCPUSourceCPUCount, Memory: 5, Storage: 50}, target.Databases[0])
+		assert.Equal(t, dto.ConsolidationDatabase{Hostname: "db2", Name: "C", CPU: 3, CPUSource: dto.ConsolidationCPUSourceHostdata, Memory: 24, Storage: 200}, target.Databases[1])
+		assert.Equal(t, dto.ConsolidationDatabase{Hostname: "db1", Name: "A", CPU: 2, CPUSource: dto.ConsolidationCPUSourceHistory, Memory: 12, Storage: 100}, target.Databases[2])
+
+		require.Len(t, actual.Unplaced, 1)
+		assert.Equal(t, "D", actual.Unplaced[0].Name)
+
+		assert.Equal(t, []dto.ConsolidationSource{
+			{Hostname: "db1", CPUCores: 8, ProcessorLicenses: 4},
+			{Hostname: "db2", CPUCores: 16, ProcessorLicenses: 8},
+			{Hostname: "db3", CPUCores: 32, ProcessorLicenses: 16, Retained: true},
+		}, actual.Sources)
+		assert.Equal(t, 28.0, actual.CurrentProcessorLicenses)
+		assert.Equal(t, 24.0, actual.PlannedProcessorLicenses)
+		assert.Equal(t, 4.0, actual.ProcessorLicensesSavings)
+	})
+
+	t.Run("Explicit history days", func(t *testing.T) {
+		request := request
+		request.HistoryDays = 7
+		request.Hostnames = []string{"db2"}
+
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(hostdatas, nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(licenseTypes, nil)
+		db.EXPECT().GetOracleDatabaseConsumptionMetrics([]string{"db2"}, utils.P("2019-12-03T00:00:00Z")).
+			Return([]model.ConsumptionMetric{}, nil)
+
+		actual, err := as.PlanOracleDatabaseConsolidation(request)
+		require.NoError(t, err)
+
+		require.Len(t, actual.Targets, 1)
+		assert.Equal(t, "small-1", actual.Targets[0].Name)
+		assert.Equal(t, 8.0, actual.CurrentProcessorLicenses)
+		assert.Equal(t, 4.0, actual.ProcessorLicensesSavings)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		invalids := []dto.OracleDatabaseConsolidationRequest{
+			{},
+			{Shapes: []dto.ConsolidationHostShape{{Name: "a", CPUCores: 4, Memory: 32, CoreFactor: 1}, {Name: "a", CPUCores: 8, Memory: 32, CoreFactor: 1}}},
+			{Shapes: []dto.ConsolidationHostShape{{Name: "a", CPUCores: 0, Memory: 32, CoreFactor: 1}}},
+			{Shapes: []dto.ConsolidationHostShape{{Name: "a", CPUCores: 4, Memory: 32, CoreFactor: 0}}},
+			{Shapes: request.Shapes, CPUHeadroom: 100},
+			{Shapes: request.Shapes, HistoryDays: -1},
+		}
+
+		for _, invalid := range invalids {
+			_, err := as.PlanOracleDatabaseConsolidation(invalid)
+			assert.ErrorIs(t, err, utils.ErrInvalidConsolidationRequest)
+		}
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(nil, aerrMock)
+
+		actual, err := as.PlanOracleDatabaseConsolidation(request)
+		assert.Nil(t, actual)
+		assert.Equal(t, aerrMock, err)
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(hostdatas, nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(licenseTypes, nil)
+		db.EXPECT().GetOracleDatabaseConsumptionMetrics(gomock.Any(), gomock.Any()).Return(metrics, nil)
+
+		file, err := as.PlanOracleDatabaseConsolidationAsXLSX(request)
+		require.NoError(t, err)
+
+		sheet := "Consolidation plan"
+		assert.Equal(t, "large-1", file.GetCellValue(sheet, "A2"))
+		assert.Equal(t, "large", file.GetCellValue(sheet, "B2"))
+		assert.Equal(t, "db1", file.GetCellValue(sheet, "F2"))
+		assert.Equal(t, "B", file.GetCellValue(sheet, "G2"))
+		assert.Equal(t, dto.ConsolidationCPUSourceCPUCount, file.GetCellValue(sheet, "I2"))
+		assert.Equal(t, "Not placed", file.GetCellValue(sheet, "A5"))
+		assert.Equal(t, "", file.GetCellValue(sheet, "B5"))
+		assert.Equal(t, "D", file.GetCellValue(sheet, "G5"))
+
+		assert.Equal(t, "Current processor licenses", file.GetCellValue("Summary", "A1"))
+		assert.Equal(t, "28", file.GetCellValue("Summary", "B1"))
+		assert.Equal(t, "4", file.GetCellValue("Summary", "B3"))
+	})
+}
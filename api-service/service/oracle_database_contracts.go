@@ -48,6 +48,8 @@ func (as *APIService) AddOracleDatabaseContract(contract model.OracleDatabaseCon
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityOracleDatabaseContract, contract.ID.Hex(), model.AuditActionCreate, nil, contract)
+
 	agrs, err := as.GetOracleDatabaseContracts(dto.NewGetOracleDatabaseContractsFilter())
 	if err != nil {
 		return nil, err
@@ -80,10 +82,17 @@ func (as *APIService) UpdateOracleDatabaseContract(contract model.OracleDatabase
 		return nil, err
 	}
 
+	var before *model.OracleDatabaseContract
+	if as.isAudited() {
+		before, _ = as.Database.GetOracleDatabaseContract(contract.ID)
+	}
+
 	if err := as.Database.UpdateOracleDatabaseContract(contract); err != nil {
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityOracleDatabaseContract, contract.ID.Hex(), model.AuditActionUpdate, before, contract)
+
 	agrs, err := as.GetOracleDatabaseContracts(dto.NewGetOracleDatabaseContractsFilter())
 	if err != nil {
 		return nil, err
@@ -117,10 +126,19 @@ func (as *APIService) GetOracleDatabaseContracts(filter dto.GetOracleDatabaseCon
 		return nil, utils.NewError(err, "DB ERROR")
 	}
 
+	scope, err := as.hostnamesInLocations(filter.Location)
+	if err != nil {
+		return nil, err
+	}
+
 	filteredAgrs := make([]dto.OracleDatabaseContractFE, 0)
 
 	for _, agr := range contracts {
-		if checkOracleDatabaseContractMatchFilter(agr, filter) {
+		if !checkOracleDatabaseContractMatchFilter(agr, filter) {
+			continue
+		}
+
+		if agr, ok := scopeOracleDatabaseContractHosts(agr, scope); ok {
 			filteredAgrs = append(filteredAgrs, agr)
 		}
 	}
@@ -128,6 +146,26 @@ func (as *APIService) GetOracleDatabaseContracts(filter dto.GetOracleDatabaseCon
 	return filteredAgrs, nil
 }
 
+// scopeOracleDatabaseContractHosts return the contract with only the hosts in the scope, a nil scope keeps them all,
+// and false if the contract had hosts but none is in the scope
+func scopeOracleDatabaseContractHosts(contract dto.OracleDatabaseContractFE, scope map[string]bool) (dto.OracleDatabaseContractFE, bool) {
+	if scope == nil || len(contract.Hosts) == 0 {
+		return contract, true
+	}
+
+	hosts := make([]dto.OracleDatabaseContractAssociatedHostFE, 0, len(contract.Hosts))
+
+	for _, host := range contract.Hosts {
+		if scope[host.Hostname] {
+			hosts = append(hosts, host)
+		}
+	}
+
+	contract.Hosts = hosts
+
+	return contract, len(hosts) > 0
+}
+
 func (as *APIService) GetOracleDatabaseContractsAsXLSX(filter dto.GetOracleDatabaseContractsFilter) (*excelize.File, error) {
 	contracts, err := as.GetOracleDatabaseContracts(filter)
 	if err != nil {
@@ -629,7 +667,18 @@ func checkOracleDatabaseContractMatchFilter(agr dto.OracleDatabaseContractFE, fi
 }
 
 func (as *APIService) DeleteOracleDatabaseContract(id primitive.ObjectID) error {
-	return as.Database.RemoveOracleDatabaseContract(id)
+	var before *model.OracleDatabaseContract
+	if as.isAudited() {
+		before, _ = as.Database.GetOracleDatabaseContract(id)
+	}
+
+	if err := as.Database.RemoveOracleDatabaseContract(id); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityOracleDatabaseContract, id.Hex(), model.AuditActionDelete, before, nil)
+
+	return nil
 }
 
 func (as *APIService) AddHostToOracleDatabaseContract(id primitive.ObjectID, hostname string) error {
@@ -648,9 +697,16 @@ func (as *APIService) AddHostToOracleDatabaseContract(id primitive.ObjectID, hos
 		}
 	}
 
-	contract.Hosts = append(contract.Hosts, hostname)
+	before := *contract
+	contract.Hosts = append(append([]string{}, contract.Hosts...), hostname)
 
-	return as.Database.UpdateOracleDatabaseContract(*contract)
+	if err := as.Database.UpdateOracleDatabaseContract(*contract); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityOracleDatabaseContract, id.Hex(), model.AuditActionUpdate, before, contract)
+
+	return nil
 }
 
 func (as *APIService) DeleteHostFromOracleDatabaseContract(id primitive.ObjectID, hostname string) error {
@@ -663,6 +719,9 @@ func (as *APIService) DeleteHostFromOracleDatabaseContract(id primitive.ObjectID
 		return err
 	}
 
+	before := *contract
+	before.Hosts = append([]string{}, contract.Hosts...)
+
 	for i := range contract.Hosts {
 		host := contract.Hosts[i]
 		if host == hostname {
@@ -674,7 +733,13 @@ func (as *APIService) DeleteHostFromOracleDatabaseContract(id primitive.ObjectID
 		}
 	}
 
-	return as.Database.UpdateOracleDatabaseContract(*contract)
+	if err := as.Database.UpdateOracleDatabaseContract(*contract); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityOracleDatabaseContract, id.Hex(), model.AuditActionUpdate, before, contract)
+
+	return nil
 }
 
 func (as *APIService) DeleteHostFromOracleDatabaseContracts(hostname string) error {
@@ -36,6 +36,11 @@ func (as *APIService) CreateGetOracleOptionListXLSX(filter dto.GlobalFilter) (*e
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Options"
 	headers := []string{
 		"Hostname",
@@ -48,6 +53,7 @@ func (as *APIService) CreateGetOracleOptionListXLSX(filter dto.GlobalFilter) (*e
 		"Extra",
 		"Feature",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -67,6 +73,10 @@ func (as *APIService) CreateGetOracleOptionListXLSX(filter dto.GlobalFilter) (*e
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabaseFeatureUsageStat.CurrentlyUsed)
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabaseFeatureUsageStat.ExtraFeatureInfo)
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabaseFeatureUsageStat.Feature)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
@@ -355,7 +355,18 @@ func (as *APIService) getterNewLicenseCompliance() (func(licenseTypeID string) *
 }
 
 func (as *APIService) DeleteOracleDatabaseLicenseType(id string) error {
-	return as.Database.RemoveOracleDatabaseLicenseType(id)
+	var before *model.OracleDatabaseLicenseType
+	if as.isAudited() {
+		before, _ = as.Database.GetOracleDatabaseLicenseType(id)
+	}
+
+	if err := as.Database.RemoveOracleDatabaseLicenseType(id); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityOracleDatabaseLicenseType, id, model.AuditActionDelete, before, nil)
+
+	return nil
 }
 
 func (as *APIService) AddOracleDatabaseLicenseType(licenseType model.OracleDatabaseLicenseType) (*model.OracleDatabaseLicenseType, error) {
@@ -364,13 +375,22 @@ func (as *APIService) AddOracleDatabaseLicenseType(licenseType model.OracleDatab
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityOracleDatabaseLicenseType, licenseType.ID, model.AuditActionCreate, nil, licenseType)
+
 	return &licenseType, nil
 }
 
 func (as *APIService) UpdateOracleDatabaseLicenseType(licenseType model.OracleDatabaseLicenseType) (*model.OracleDatabaseLicenseType, error) {
+	var before *model.OracleDatabaseLicenseType
+	if as.isAudited() {
+		before, _ = as.Database.GetOracleDatabaseLicenseType(licenseType.ID)
+	}
+
 	if err := as.Database.UpdateOracleDatabaseLicenseType(licenseType); err != nil {
 		return nil, err
 	}
 
+	as.audit(model.AuditEntityOracleDatabaseLicenseType, licenseType.ID, model.AuditActionUpdate, before, licenseType)
+
 	return &licenseType, nil
 }
@@ -15,12 +15,17 @@
 
 package service
 
+import "github.com/ercole-io/ercole/v2/model"
+
 // UpdateLicenseIgnoredField update license ignored field (true/false)
 func (as *APIService) UpdateLicenseIgnoredField(hostname string, dbname string, licenseTypeID string, ignored bool, ignoredComment string) error {
 	if err := as.Database.UpdateLicenseIgnoredField(hostname, dbname, licenseTypeID, ignored, ignoredComment); err != nil {
 		return err
 	}
 
+	as.audit(model.AuditEntityOracleDatabaseLicense, hostname+"/"+dbname+"/"+licenseTypeID, model.AuditActionUpdate, nil,
+		model.IgnoredLicenseChange{Ignored: ignored, IgnoredComment: ignoredComment})
+
 	return nil
 }
 
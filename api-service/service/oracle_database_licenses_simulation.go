@@ -0,0 +1,580 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package service is a package that provides methods for querying data
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/api-service/database"
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// SimulateOracleDatabaseLicenses return the difference of the compliance of each license type
+// after the hypothetical changes to the hosts in the locations, comma separated, or to all the hosts
+// if location is empty. Nothing is saved
+func (as *APIService) SimulateOracleDatabaseLicenses(simulation dto.OracleDatabaseLicensesSimulation, location string) ([]dto.LicenseComplianceDiff, error) {
+	current, err := as.newSimulatedDatabase(location)
+	if err != nil {
+		return nil, err
+	}
+
+	simulated := current.clone()
+
+	for _, change := range simulation.Changes {
+		if err := simulated.apply(change); err != nil {
+			return nil, err
+		}
+	}
+
+	before, err := as.withSimulatedDatabase(current).GetOracleDatabaseLicensesCompliance()
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := as.withSimulatedDatabase(simulated).GetOracleDatabaseLicensesCompliance()
+	if err != nil {
+		return nil, err
+	}
+
+	return diffLicensesCompliance(before, after), nil
+}
+
+// withSimulatedDatabase return a copy of the service that reads the hosts, the clusters and the contracts from db
+func (as *APIService) withSimulatedDatabase(db *simulatedDatabase) *APIService {
+	simulated := *as
+	simulated.Database = db
+	simulated.auditActor = nil
+
+	return &simulated
+}
+
+func diffLicensesCompliance(before, after []dto.LicenseCompliance) []dto.LicenseComplianceDiff {
+	diffs := make(map[string]*dto.LicenseComplianceDiff)
+
+	get := func(license dto.LicenseCompliance) *dto.LicenseComplianceDiff {
+		diff, ok := diffs[license.LicenseTypeID]
+		if !ok {
+			diff = &dto.LicenseComplianceDiff{
+				LicenseTypeID:   license.LicenseTypeID,
+				ItemDescription: license.ItemDescription,
+				Metric:          license.Metric,
+			}
+			diffs[license.LicenseTypeID] = diff
+		}
+
+		return diff
+	}
+
+	for _, license := range before {
+		get(license).Before = license
+	}
+
+	for _, license := range after {
+		get(license).After = license
+	}
+
+	res := make([]dto.LicenseComplianceDiff, 0, len(diffs))
+
+	for _, diff := range diffs {
+		diff.ConsumedDelta = diff.After.Consumed - diff.Before.Consumed
+		diff.CoveredDelta = diff.After.Covered - diff.Before.Covered
+		diff.ComplianceDelta = diff.After.Compliance - diff.Before.Compliance
+		diff.AvailableDelta = diff.After.Available - diff.Before.Available
+
+		res = append(res, *diff)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].LicenseTypeID < res[j].LicenseTypeID
+	})
+
+	return res
+}
+
+// simulatedDatabase is an in-memory snapshot of the hosts, the clusters and the Oracle Database contracts
+// used to compute the licenses compliance, restricted to the hosts of the requested locations.
+// The other methods are delegated to the real database
+type simulatedDatabase struct {
+	database.MongoDatabaseInterface
+
+	hostdatas    []model.HostDataBE
+	clusters     []dto.Cluster
+	contracts    []dto.OracleDatabaseContractFE
+	licenseTypes map[string]model.OracleDatabaseLicenseType
+}
+
+func (as *APIService) newSimulatedDatabase(location string) (*simulatedDatabase, error) {
+	hostdatas, err := as.Database.GetHostDatas(utils.MAX_TIME)
+	if err != nil {
+		return nil, err
+	}
+
+	var scope map[string]bool
+
+	if location != "" {
+		locations := strings.Split(location, ",")
+		scope = make(map[string]bool)
+		scoped := make([]model.HostDataBE, 0, len(hostdatas))
+
+		for _, hostdata := range hostdatas {
+			if utils.Contains(locations, hostdata.Location) {
+				scope[hostdata.Hostname] = true
+				scoped = append(scoped, hostdata)
+			}
+		}
+
+		hostdatas = scoped
+	}
+
+	clusters, err := as.Database.GetClusters(dto.GlobalFilter{
+		Location:    location,
+		Environment: "",
+		OlderThan:   utils.MAX_TIME,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	allContracts, err := as.Database.ListOracleDatabaseContracts()
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]dto.OracleDatabaseContractFE, 0, len(allContracts))
+
+	for _, contract := range allContracts {
+		if contract, ok := scopeOracleDatabaseContractHosts(contract, scope); ok {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	licenseTypes, err := as.Database.GetOracleDatabaseLicenseTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	sd := &simulatedDatabase{
+		MongoDatabaseInterface: as.Database,
+		hostdatas:              hostdatas,
+		clusters:               clusters,
+		contracts:              contracts,
+		licenseTypes:           make(map[string]model.OracleDatabaseLicenseType, len(licenseTypes)),
+	}
+
+	for _, licenseType := range licenseTypes {
+		sd.licenseTypes[licenseType.ID] = licenseType
+	}
+
+	return sd, nil
+}
+
+// clone return a copy of the snapshot that can be changed without affecting sd
+func (sd *simulatedDatabase) clone() *simulatedDatabase {
+	return &simulatedDatabase{
+		MongoDatabaseInterface: sd.MongoDatabaseInterface,
+		hostdatas:              cloneHostdatas(sd.hostdatas),
+		clusters:               cloneClusters(sd.clusters),
+		contracts:              cloneOracleDatabaseContracts(sd.contracts),
+		licenseTypes:           sd.licenseTypes,
+	}
+}
+
+// cloneHostdatas copy the hostdatas, deeply enough to change their cores and Oracle databases
+func cloneHostdatas(hostdatas []model.HostDataBE) []model.HostDataBE {
+	res := make([]model.HostDataBE, len(hostdatas))
+
+	for i, hostdata := range hostdatas {
+		if hostdata.Features.Oracle != nil && hostdata.Features.Oracle.Database != nil {
+			oracleDatabase := *hostdata.Features.Oracle.Database
+			oracleDatabase.Databases = make([]model.OracleDatabase, len(hostdata.Features.Oracle.Database.Databases))
+
+			for j, db := range hostdata.Features.Oracle.Database.Databases {
+				db.Licenses = append([]model.OracleDatabaseLicense{}, db.Licenses...)
+				oracleDatabase.Databases[j] = db
+			}
+
+			hostdata.Features.Oracle = &model.OracleFeature{Database: &oracleDatabase}
+		}
+
+		res[i] = hostdata
+	}
+
+	return res
+}
+
+func cloneClusters(clusters []dto.Cluster) []dto.Cluster {
+	res := make([]dto.Cluster, len(clusters))
+
+	for i, cluster := range clusters {
+		cluster.VMs = append([]dto.VM{}, cluster.VMs...)
+		res[i] = cluster
+	}
+
+	return res
+}
+
+func cloneOracleDatabaseContracts(contracts []dto.OracleDatabaseContractFE) []dto.OracleDatabaseContractFE {
+	res := make([]dto.OracleDatabaseContractFE, len(contracts))
+
+	for i, contract := range contracts {
+		contract.Hosts = append([]dto.OracleDatabaseContractAssociatedHostFE{}, contract.Hosts...)
+		res[i] = contract
+	}
+
+	return res
+}
+
+func (sd *simulatedDatabase) GetHostDatas(olderThan time.Time) ([]model.HostDataBE, error) {
+	return cloneHostdatas(sd.hostdatas), nil
+}
+
+func (sd *simulatedDatabase) ExistHostdata(hostname string) (bool, error) {
+	return sd.getHostdata(hostname) != nil, nil
+}
+
+func (sd *simulatedDatabase) GetClusters(filter dto.GlobalFilter) ([]dto.Cluster, error) {
+	return cloneClusters(sd.clusters), nil
+}
+
+func (sd *simulatedDatabase) GetCluster(clusterName string, olderThan time.Time) (*dto.Cluster, error) {
+	cluster := sd.getCluster(clusterName)
+	if cluster == nil {
+		return nil, utils.NewError(utils.ErrClusterNotFound)
+	}
+
+	res := cloneClusters([]dto.Cluster{*cluster})[0]
+
+	return &res, nil
+}
+
+// ListOracleDatabaseContracts return a copy of the contracts, because the assignment of the licenses changes them
+func (sd *simulatedDatabase) ListOracleDatabaseContracts() ([]dto.OracleDatabaseContractFE, error) {
+	return cloneOracleDatabaseContracts(sd.contracts), nil
+}
+
+// SearchOracleDatabaseUsedLicenses return all the licenses used by the databases of hostname, or of every host.
+// The snapshot contains all the current hosts, so the other filters, the sorting and the paging are ignored
+func (sd *simulatedDatabase) SearchOracleDatabaseUsedLicenses(hostname string, sortBy string, sortDesc bool, page int, pageSize int,
+	location string, environment string, olderThan time.Time,
+) (*dto.OracleDatabaseUsedLicenseSearchResponse, error) {
+	content := make([]dto.OracleDatabaseUsedLicense, 0)
+
+	for _, hostdata := range sd.hostdatas {
+		if hostname != "" && hostdata.Hostname != hostname {
+			continue
+		}
+
+		for _, db := range oracleDatabasesOf(&hostdata) {
+			for _, license := range db.Licenses {
+				if license.Count <= 0 {
+					continue
+				}
+
+				usedLicenses := license.Count
+				if sd.licenseTypes[license.LicenseTypeID].Metric == model.LicenseTypeMetricComputerPerpetual {
+					usedLicenses = 1
+				}
+
+				content = append(content, dto.OracleDatabaseUsedLicense{
+					LicenseTypeID:  license.LicenseTypeID,
+					DbName:         db.Name,
+					Hostname:       hostdata.Hostname,
+					UsedLicenses:   usedLicenses,
+					Ignored:        license.Ignored,
+					IgnoredComment: license.IgnoredComment,
+				})
+			}
+		}
+	}
+
+	return &dto.OracleDatabaseUsedLicenseSearchResponse{
+		Content: content,
+		Metadata: dto.PagingMetadata{
+			Empty:         len(content) == 0,
+			First:         true,
+			Last:          true,
+			Size:          len(content),
+			TotalElements: len(content),
+			TotalPages:    1,
+		},
+	}, nil
+}
+
+func oracleDatabasesOf(hostdata *model.HostDataBE) []model.OracleDatabase {
+	if hostdata.Features.Oracle == nil || hostdata.Features.Oracle.Database == nil {
+		return nil
+	}
+
+	return hostdata.Features.Oracle.Database.Databases
+}
+
+func (sd *simulatedDatabase) getHostdata(hostname string) *model.HostDataBE {
+	for i := range sd.hostdatas {
+		if sd.hostdatas[i].Hostname == hostname {
+			return &sd.hostdatas[i]
+		}
+	}
+
+	return nil
+}
+
+func (sd *simulatedDatabase) getCluster(name string) *dto.Cluster {
+	for i := range sd.clusters {
+		if sd.clusters[i].Name == name {
+			return &sd.clusters[i]
+		}
+	}
+
+	return nil
+}
+
+func invalidSimulationChange(format string, a ...interface{}) error {
+	return utils.NewError(utils.ErrInvalidLicenseSimulation, fmt.Sprintf(format, a...))
+}
+
+// apply change to the snapshot
+func (sd *simulatedDatabase) apply(change dto.OracleDatabaseLicensesSimulationChange) error {
+	switch change.Type {
+	case dto.SimulationChangeClusterCores:
+		cluster := sd.getCluster(change.Cluster)
+		if cluster == nil {
+			return invalidSimulationChange("Unknown cluster %q", change.Cluster)
+		}
+
+		if change.CPUCores < 0 {
+			return invalidSimulationChange("Invalid cores %d", change.CPUCores)
+		}
+
+		cluster.CPU = change.CPUCores
+
+		return nil
+	case dto.SimulationChangeContractHosts:
+		for i := range sd.contracts {
+			contract := &sd.contracts[i]
+			if contract.ID != change.ContractID {
+				continue
+			}
+
+			contract.Hosts = make([]dto.OracleDatabaseContractAssociatedHostFE, 0, len(change.Hosts))
+			for _, hostname := range change.Hosts {
+				contract.Hosts = append(contract.Hosts, dto.OracleDatabaseContractAssociatedHostFE{Hostname: hostname})
+			}
+
+			return nil
+		}
+
+		return invalidSimulationChange("Unknown contract %q", change.ContractID.Hex())
+	}
+
+	hostdata := sd.getHostdata(change.Hostname)
+	if hostdata == nil {
+		return invalidSimulationChange("Unknown host %q", change.Hostname)
+	}
+
+	switch change.Type {
+	case dto.SimulationChangeHostCores:
+		if change.CPUCores < 0 {
+			return invalidSimulationChange("Invalid cores %d", change.CPUCores)
+		}
+
+		setHostCores(hostdata, change.CPUCores)
+	case dto.SimulationChangeHostCoreFactor:
+		if change.CoreFactor <= 0 {
+			return invalidSimulationChange("Invalid core factor %f", change.CoreFactor)
+		}
+
+		for _, db := range oracleDatabasesOf(hostdata) {
+			for i := range db.Licenses {
+				if db.Licenses[i].Count > 0 {
+					db.Licenses[i].Count = float64(hostdata.Info.CPUCores) * change.CoreFactor
+				}
+			}
+		}
+	case dto.SimulationChangeHostCluster:
+		return sd.moveHostToCluster(change.Hostname, change.Cluster)
+	case dto.SimulationChangeMoveDatabase:
+		return sd.moveDatabase(hostdata, change.DatabaseName, change.TargetHostname)
+	case dto.SimulationChangeEnableOption, dto.SimulationChangeDisableOption:
+		return sd.setOption(hostdata, change.DatabaseName, change.LicenseTypeID, change.Type == dto.SimulationChangeEnableOption)
+	default:
+		return invalidSimulationChange("Unknown change type %q", change.Type)
+	}
+
+	return nil
+}
+
+// setHostCores change the cores of the host and scale the licenses of its databases, keeping their core factor
+func setHostCores(hostdata *model.HostDataBE, cores int) {
+	for _, db := range oracleDatabasesOf(hostdata) {
+		for i := range db.Licenses {
+			license := &db.Licenses[i]
+			if license.Count <= 0 {
+				continue
+			}
+
+			if hostdata.Info.CPUCores > 0 {
+				license.Count = license.Count / float64(hostdata.Info.CPUCores) * float64(cores)
+			} else {
+				license.Count = float64(cores) * hostdata.CoreFactor()
+			}
+		}
+	}
+
+	hostdata.Info.CPUCores = cores
+}
+
+// hostLicensesCoreFactor return the core factor used by the licenses of db, or the default one of the host
+func hostLicensesCoreFactor(hostdata *model.HostDataBE, db *model.OracleDatabase) float64 {
+	if hostdata.Info.CPUCores > 0 {
+		for _, license := range db.Licenses {
+			if license.Count > 0 {
+				return license.Count / float64(hostdata.Info.CPUCores)
+			}
+		}
+	}
+
+	return hostdata.CoreFactor()
+}
+
+func (sd *simulatedDatabase) moveHostToCluster(hostname, clusterName string) error {
+	var target *dto.Cluster
+
+	if clusterName != "" {
+		if target = sd.getCluster(clusterName); target == nil {
+			return invalidSimulationChange("Unknown cluster %q", clusterName)
+		}
+	}
+
+	vm := dto.VM{Hostname: hostname, Name: hostname}
+
+	for i := range sd.clusters {
+		cluster := &sd.clusters[i]
+
+		for j := 0; j < len(cluster.VMs); {
+			if cluster.VMs[j].Hostname == hostname {
+				vm = cluster.VMs[j]
+				cluster.VMs = append(cluster.VMs[:j], cluster.VMs[j+1:]...)
+
+				continue
+			}
+
+			j++
+		}
+	}
+
+	if target != nil {
+		target.VMs = append(target.VMs, vm)
+	}
+
+	return nil
+}
+
+func (sd *simulatedDatabase) moveDatabase(source *model.HostDataBE, dbName, targetHostname string) error {
+	target := sd.getHostdata(targetHostname)
+	if target == nil {
+		return invalidSimulationChange("Unknown host %q", targetHostname)
+	}
+
+	databases := oracleDatabasesOf(source)
+
+	for i := range databases {
+		if databases[i].Name != dbName {
+			continue
+		}
+
+		db := databases[i]
+		source.Features.Oracle.Database.Databases = append(databases[:i:i], databases[i+1:]...)
+
+		for j := range db.Licenses {
+			if db.Licenses[j].Count > 0 && source.Info.CPUCores > 0 {
+				db.Licenses[j].Count = db.Licenses[j].Count / float64(source.Info.CPUCores) * float64(target.Info.CPUCores)
+			}
+		}
+
+		if target.Features.Oracle == nil {
+			target.Features.Oracle = &model.OracleFeature{}
+		}
+
+		if target.Features.Oracle.Database == nil {
+			target.Features.Oracle.Database = &model.OracleDatabaseFeature{}
+		}
+
+		target.Features.Oracle.Database.Databases = append(target.Features.Oracle.Database.Databases, db)
+
+		return nil
+	}
+
+	return invalidSimulationChange("Unknown database %q on host %q", dbName, source.Hostname)
+}
+
+func (sd *simulatedDatabase) setOption(hostdata *model.HostDataBE, dbName, licenseTypeID string, enabled bool) error {
+	licenseType, ok := sd.licenseTypes[licenseTypeID]
+	if !ok {
+		return invalidSimulationChange("Unknown license type %q", licenseTypeID)
+	}
+
+	found := false
+
+	for i := range oracleDatabasesOf(hostdata) {
+		db := &hostdata.Features.Oracle.Database.Databases[i]
+		if dbName != "" && db.Name != dbName {
+			continue
+		}
+
+		found = true
+
+		var count float64
+		if enabled {
+			count = float64(hostdata.Info.CPUCores) * hostLicensesCoreFactor(hostdata, db)
+		}
+
+		license := findLicenseByTypeID(db, licenseTypeID)
+		if license != nil {
+			license.Count = count
+			continue
+		}
+
+		if enabled {
+			db.Licenses = append(db.Licenses, model.OracleDatabaseLicense{
+				LicenseTypeID: licenseTypeID,
+				Name:          licenseType.ItemDescription,
+				Count:         count,
+			})
+		}
+	}
+
+	if !found {
+		return invalidSimulationChange("Unknown database %q on host %q", dbName, hostdata.Hostname)
+	}
+
+	return nil
+}
+
+func findLicenseByTypeID(db *model.OracleDatabase, licenseTypeID string) *model.OracleDatabaseLicense {
+	for i := range db.Licenses {
+		if db.Licenses[i].LicenseTypeID == licenseTypeID {
+			return &db.Licenses[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestSimulateOracleDatabaseLicenses(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	licenseTypes := []model.OracleDatabaseLicenseType{
+		{
+			ID:              "A90611",
+			ItemDescription: "Oracle Database Enterprise Edition",
+			Metric:          model.LicenseTypeMetricProcessorPerpetual,
+			Cost:            47500,
+		},
+		{
+			ID:              "A90619",
+			ItemDescription: "Partitioning",
+			Metric:          model.LicenseTypeMetricProcessorPerpetual,
+			Cost:            11500,
+			Option:          true,
+		},
+	}
+
+	hostdatas := []model.HostDataBE{
+		{
+			Hostname: "db1",
+			Info:     model.Host{Hostname: "db1", CPUCores: 4},
+			Features: model.Features{
+				Oracle: &model.OracleFeature{
+					Database: &model.OracleDatabaseFeature{
+						Databases: []model.OracleDatabase{
+							{
+								Name: "ERCOLE",
+								Licenses: []model.OracleDatabaseLicense{
+									{LicenseTypeID: "A90611", Name: "Oracle ENT", Count: 2},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Hostname: "db2",
+			Info:     model.Host{Hostname: "db2", CPUCores: 2},
+		},
+	}
+
+	contracts := []dto.OracleDatabaseContractFE{
+		{
+			ID:                       utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"),
+			ContractID:               "AID001",
+			LicenseTypeID:            "A90611",
+			ItemDescription:          "Oracle Database Enterprise Edition",
+			Metric:                   model.LicenseTypeMetricProcessorPerpetual,
+			Hosts:                    []dto.OracleDatabaseContractAssociatedHostFE{{Hostname: "db1"}},
+			LicensesPerCore:          2,
+			AvailableLicensesPerCore: 2,
+		},
+	}
+
+	expectSnapshot := func() {
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(hostdatas, nil)
+		db.EXPECT().GetClusters(gomock.Any()).Return([]dto.Cluster{}, nil)
+		db.EXPECT().ListOracleDatabaseContracts().Return(contracts, nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(licenseTypes, nil).AnyTimes()
+	}
+
+	t.Run("More cores and a new option", func(t *testing.T) {
+		expectSnapshot()
+
+		simulation := dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: dto.SimulationChangeHostCores, Hostname: "db1", CPUCores: 8},
+				{Type: dto.SimulationChangeEnableOption, Hostname: "db1", DatabaseName: "ERCOLE", LicenseTypeID: "A90619"},
+			},
+		}
+
+		actual, err := as.SimulateOracleDatabaseLicenses(simulation, "")
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+
+		assert.Equal(t, "A90611", actual[0].LicenseTypeID)
+		assert.Equal(t, 2.0, actual[0].Before.Consumed)
+		assert.Equal(t, 1.0, actual[0].Before.Compliance)
+		assert.Equal(t, 4.0, actual[0].After.Consumed)
+		assert.Equal(t, 2.0, actual[0].After.Covered)
+		assert.Equal(t, 0.5, actual[0].After.Compliance)
+		assert.Equal(t, 2.0, actual[0].ConsumedDelta)
+		assert.Equal(t, -0.5, actual[0].ComplianceDelta)
+
+		assert.Equal(t, "A90619", actual[1].LicenseTypeID)
+		assert.Equal(t, 0.0, actual[1].Before.Consumed)
+		assert.Equal(t, 4.0, actual[1].After.Consumed)
+		assert.Equal(t, 0.0, actual[1].After.Compliance)
+
+		assert.Equal(t, 2.0, hostdatas[0].Features.Oracle.Database.Databases[0].Licenses[0].Count, "snapshot must not be changed")
+	})
+
+	t.Run("Database moved to an uncovered host", func(t *testing.T) {
+		expectSnapshot()
+
+		simulation := dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: dto.SimulationChangeMoveDatabase, Hostname: "db1", DatabaseName: "ERCOLE", TargetHostname: "db2"},
+			},
+		}
+
+		actual, err := as.SimulateOracleDatabaseLicenses(simulation, "")
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+
+		assert.Equal(t, 1.0, actual[0].After.Consumed)
+		assert.Equal(t, 0.0, actual[0].After.Covered)
+		assert.Equal(t, 2.0, actual[0].AvailableDelta)
+	})
+
+	t.Run("Database moved to a host covered by the contract", func(t *testing.T) {
+		expectSnapshot()
+
+		simulation := dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: dto.SimulationChangeMoveDatabase, Hostname: "db1", DatabaseName: "ERCOLE", TargetHostname: "db2"},
+				{Type: dto.SimulationChangeContractHosts, ContractID: utils.Str2oid("aaaaaaaaaaaaaaaaaaaaaaaa"), Hosts: []string{"db2"}},
+			},
+		}
+
+		actual, err := as.SimulateOracleDatabaseLicenses(simulation, "")
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+
+		assert.Equal(t, 1.0, actual[0].After.Covered)
+		assert.Equal(t, 1.0, actual[0].After.Compliance)
+	})
+
+	t.Run("Scoped to the location", func(t *testing.T) {
+		italy := cloneHostdatas(hostdatas)
+		for i := range italy {
+			italy[i].Location = "Italy"
+		}
+
+		france := model.HostDataBE{
+			Hostname: "db3",
+			Location: "France",
+			Info:     model.Host{Hostname: "db3", CPUCores: 2},
+			Features: model.Features{
+				Oracle: &model.OracleFeature{
+					Database: &model.OracleDatabaseFeature{
+						Databases: []model.OracleDatabase{
+							{
+								Name: "PARIS",
+								Licenses: []model.OracleDatabaseLicense{
+									{LicenseTypeID: "A90619", Name: "Partitioning", Count: 1},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		partitioning := dto.OracleDatabaseContractFE{
+			ID:              utils.Str2oid("bbbbbbbbbbbbbbbbbbbbbbbb"),
+			ContractID:      "AID002",
+			LicenseTypeID:   "A90619",
+			ItemDescription: "Partitioning",
+			Metric:          model.LicenseTypeMetricProcessorPerpetual,
+			Hosts:           []dto.OracleDatabaseContractAssociatedHostFE{{Hostname: "db3"}},
+			LicensesPerCore: 1,
+		}
+
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(append(italy, france), nil)
+		db.EXPECT().GetClusters(dto.GlobalFilter{Location: "Italy", OlderThan: utils.MAX_TIME}).Return([]dto.Cluster{}, nil)
+		db.EXPECT().ListOracleDatabaseContracts().Return(append(cloneOracleDatabaseContracts(contracts), partitioning), nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(licenseTypes, nil).AnyTimes()
+
+		simulation := dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: dto.SimulationChangeHostCores, Hostname: "db1", CPUCores: 8},
+			},
+		}
+
+		actual, err := as.SimulateOracleDatabaseLicenses(simulation, "Italy")
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+
+		assert.Equal(t, "A90611", actual[0].LicenseTypeID)
+		assert.Equal(t, 2.0, actual[0].ConsumedDelta)
+
+		simulation = dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: dto.SimulationChangeHostCores, Hostname: "db3", CPUCores: 8},
+			},
+		}
+
+		db.EXPECT().GetHostDatas(utils.MAX_TIME).Return(append(italy, france), nil)
+		db.EXPECT().GetClusters(gomock.Any()).Return([]dto.Cluster{}, nil)
+		db.EXPECT().ListOracleDatabaseContracts().Return(contracts, nil)
+
+		_, err = as.SimulateOracleDatabaseLicenses(simulation, "Italy")
+		assert.ErrorIs(t, err, utils.ErrInvalidLicenseSimulation)
+	})
+
+	t.Run("Unknown host", func(t *testing.T) {
+		expectSnapshot()
+
+		simulation := dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: dto.SimulationChangeHostCores, Hostname: "unknown", CPUCores: 8},
+			},
+		}
+
+		_, err := as.SimulateOracleDatabaseLicenses(simulation, "")
+		assert.ErrorIs(t, err, utils.ErrInvalidLicenseSimulation)
+	})
+
+	t.Run("Unknown change type", func(t *testing.T) {
+		expectSnapshot()
+
+		simulation := dto.OracleDatabaseLicensesSimulation{
+			Changes: []dto.OracleDatabaseLicensesSimulationChange{
+				{Type: "REBOOT", Hostname: "db1"},
+			},
+		}
+
+		_, err := as.SimulateOracleDatabaseLicenses(simulation, "")
+		assert.ErrorIs(t, err, utils.ErrInvalidLicenseSimulation)
+	})
+}
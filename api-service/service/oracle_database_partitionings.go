@@ -52,6 +52,11 @@ func (as *APIService) CreateOracleDatabasePartitioningsXlsx(filter dto.GlobalFil
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Partitioning"
 	headers := []string{
 		"Hostname",
@@ -62,6 +67,7 @@ func (as *APIService) CreateOracleDatabasePartitioningsXlsx(filter dto.GlobalFil
 		"Count",
 		"Mb",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -79,6 +85,10 @@ func (as *APIService) CreateOracleDatabasePartitioningsXlsx(filter dto.GlobalFil
 		sheets.SetCellValue(sheet, nextAxis(), val.SegmentName)
 		sheets.SetCellValue(sheet, nextAxis(), val.Count)
 		sheets.SetCellValue(sheet, nextAxis(), val.Mb)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	for _, valPdb := range pdbPartitionings {
@@ -90,6 +100,10 @@ func (as *APIService) CreateOracleDatabasePartitioningsXlsx(filter dto.GlobalFil
 		sheets.SetCellValue(sheet, nextAxis(), valPdb.SegmentName)
 		sheets.SetCellValue(sheet, nextAxis(), valPdb.Count)
 		sheets.SetCellValue(sheet, nextAxis(), valPdb.Mb)
+
+		for _, v := range hostMetadataXLSXValues(metadata[valPdb.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
@@ -36,6 +36,11 @@ func (as *APIService) CreateGetOraclePatchListXLSX(filter dto.GlobalFilter) (*ex
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Patch"
 	headers := []string{
 		"Hostname",
@@ -46,6 +51,7 @@ func (as *APIService) CreateGetOraclePatchListXLSX(filter dto.GlobalFilter) (*ex
 		"Patch ID",
 		"Patch Version",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -63,6 +69,10 @@ func (as *APIService) CreateGetOraclePatchListXLSX(filter dto.GlobalFilter) (*ex
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabasePatch.Description)
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabasePatch.PatchID)
 		sheets.SetCellValue(sheet, nextAxis(), val.OracleDatabasePatch.Version)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
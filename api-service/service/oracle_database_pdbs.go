@@ -51,6 +51,11 @@ func (as *APIService) CreateOracleDatabasePdbsXlsx(filter dto.GlobalFilter) (*ex
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Pluggable dbs"
 	headers := []string{
 		"Hostname",
@@ -65,6 +70,7 @@ func (as *APIService) CreateOracleDatabasePdbsXlsx(filter dto.GlobalFilter) (*ex
 		"Services",
 		"GrantDba",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -86,6 +92,10 @@ func (as *APIService) CreateOracleDatabasePdbsXlsx(filter dto.GlobalFilter) (*ex
 		sheets.SetCellValue(sheet, nextAxis(), val.Schemas)
 		sheets.SetCellValue(sheet, nextAxis(), val.Services)
 		sheets.SetCellValue(sheet, nextAxis(), val.GrantDba)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
@@ -47,6 +47,11 @@ func (as *APIService) CreateOracleDatabaseSchemasXlsx(filter dto.GlobalFilter) (
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Schemas"
 	headers := []string{
 		"Hostname",
@@ -58,6 +63,7 @@ func (as *APIService) CreateOracleDatabaseSchemasXlsx(filter dto.GlobalFilter) (
 		"User",
 		"Account Status",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -76,6 +82,10 @@ func (as *APIService) CreateOracleDatabaseSchemasXlsx(filter dto.GlobalFilter) (
 		sheets.SetCellValue(sheet, nextAxis(), val.Total)
 		sheets.SetCellValue(sheet, nextAxis(), val.User)
 		sheets.SetCellValue(sheet, nextAxis(), val.AccountStatus)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
@@ -36,6 +36,11 @@ func (as *APIService) CreateGetOracleServiceListXLSX(filter dto.GlobalFilter) (*
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Services"
 	headers := []string{
 		"Hostname",
@@ -43,6 +48,7 @@ func (as *APIService) CreateGetOracleServiceListXLSX(filter dto.GlobalFilter) (*
 		"Service Name",
 		"Enabled",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -67,6 +73,10 @@ func (as *APIService) CreateGetOracleServiceListXLSX(filter dto.GlobalFilter) (*
 		} else {
 			sheets.SetCellValue(sheet, nextAxis(), "")
 		}
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
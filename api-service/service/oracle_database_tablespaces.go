@@ -36,6 +36,11 @@ func (as *APIService) CreateOracleDatabaseTablespacesXlsx(filter dto.GlobalFilte
 		return nil, err
 	}
 
+	metadata, err := as.hostMetadataByHostname()
+	if err != nil {
+		return nil, err
+	}
+
 	sheet := "Tablespaces"
 	headers := []string{
 		"Hostname",
@@ -46,6 +51,7 @@ func (as *APIService) CreateOracleDatabaseTablespacesXlsx(filter dto.GlobalFilte
 		"UsedPerc",
 		"Status",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -63,6 +69,10 @@ func (as *APIService) CreateOracleDatabaseTablespacesXlsx(filter dto.GlobalFilte
 		sheets.SetCellValue(sheet, nextAxis(), val.Used)
 		sheets.SetCellValue(sheet, nextAxis(), val.UsedPerc)
 		sheets.SetCellValue(sheet, nextAxis(), val.Status)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			sheets.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return sheets, err
@@ -106,7 +106,16 @@ func (as *APIService) SearchOracleDatabaseSegmentAdvisorsAsXLSX(filter dto.Globa
 
 // SearchOracleDatabasePatchAdvisors search patch advisors
 func (as *APIService) SearchOracleDatabasePatchAdvisors(search string, sortBy string, sortDesc bool, page int, pageSize int, windowTime time.Time, location string, environment string, olderThan time.Time, status string) (*dto.PatchAdvisorResponse, error) {
-	return as.Database.SearchOracleDatabasePatchAdvisors(strings.Split(search, " "), sortBy, sortDesc, page, pageSize, windowTime, location, environment, olderThan, status)
+	patchAdvisorResponse, err := as.Database.SearchOracleDatabasePatchAdvisors(strings.Split(search, " "), sortBy, sortDesc, page, pageSize, windowTime, location, environment, olderThan, status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.adviseOraclePatches(patchAdvisorResponse.Content); err != nil {
+		return nil, err
+	}
+
+	return patchAdvisorResponse, nil
 }
 
 func (as *APIService) SearchOracleDatabasePatchAdvisorsAsXLSX(windowTime time.Time, filter dto.GlobalFilter) (*excelize.File, error) {
@@ -115,6 +124,10 @@ func (as *APIService) SearchOracleDatabasePatchAdvisorsAsXLSX(windowTime time.Ti
 		return nil, err
 	}
 
+	if err := as.adviseOraclePatches(patchAdvisorResponse.Content); err != nil {
+		return nil, err
+	}
+
 	sheet := "Patch_Advisor"
 	headers := []string{
 		"Hostname",
@@ -126,6 +139,10 @@ func (as *APIService) SearchOracleDatabasePatchAdvisorsAsXLSX(windowTime time.Ti
 		"4 Months",
 		"6 Months",
 		"12 Months",
+		"Installed release",
+		"Latest release",
+		"Releases behind",
+		"Support",
 	}
 
 	sheets, err := exutils.NewXLSX(as.Config, sheet, headers...)
@@ -146,6 +163,26 @@ func (as *APIService) SearchOracleDatabasePatchAdvisorsAsXLSX(windowTime time.Ti
 		sheets.SetCellValue("Patch_Advisor", nextAxis(), val.FourMonths)
 		sheets.SetCellValue("Patch_Advisor", nextAxis(), val.SixMonths)
 		sheets.SetCellValue("Patch_Advisor", nextAxis(), val.TwelveMonths)
+
+		if val.InstalledRelease != nil {
+			sheets.SetCellValue("Patch_Advisor", nextAxis(), val.InstalledRelease.Release)
+		} else {
+			nextAxis()
+		}
+
+		if val.LatestRelease != nil {
+			sheets.SetCellValue("Patch_Advisor", nextAxis(), val.LatestRelease.Release)
+		} else {
+			nextAxis()
+		}
+
+		if val.ReleasesBehind != nil {
+			sheets.SetCellValue("Patch_Advisor", nextAxis(), *val.ReleasesBehind)
+		} else {
+			nextAxis()
+		}
+
+		sheets.SetCellValue("Patch_Advisor", nextAxis(), val.SupportStatus)
 	}
 
 	return sheets, err
@@ -154,14 +191,23 @@ func (as *APIService) SearchOracleDatabasePatchAdvisorsAsXLSX(windowTime time.Ti
 // SearchOracleDatabases search databases
 func (as *APIService) SearchOracleDatabases(f dto.SearchOracleDatabasesFilter) (*dto.OracleDatabaseResponse, error) {
 	return as.Database.SearchOracleDatabases(strings.Split(f.Search, " "), f.SortBy, f.SortDesc,
-		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan)
+		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter)
+}
+
+func (as *APIService) ListOracleDatabases(f dto.SearchOracleDatabasesFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListOracleDatabases(strings.Split(f.Search, " "), f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter, q)
 }
 
 func (as *APIService) SearchOracleDatabasesAsXLSX(filter dto.SearchOracleDatabasesFilter) (*excelize.File, error) {
 	databases, err := as.Database.SearchOracleDatabases(strings.Split(filter.Search, " "),
 		filter.SortBy, filter.SortDesc,
 		-1, -1,
-		filter.Location, filter.Environment, filter.OlderThan)
+		filter.Location, filter.Environment, filter.OlderThan, filter.HostMetadataFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := as.hostMetadataByHostname()
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +217,11 @@ func (as *APIService) SearchOracleDatabasesAsXLSX(filter dto.SearchOracleDatabas
 		return nil, err
 	}
 
+	// the metadata of the hosts follow the columns of the template
+	for j, header := range hostMetadataXLSXHeaders {
+		file.SetCellValue("Databases", fmt.Sprintf("%c1", 'U'+j), header)
+	}
+
 	for i, val := range databases.Content {
 		i += 2 // offset for headers
 		file.SetCellValue("Databases", fmt.Sprintf("A%d", i), val.Name)
@@ -205,6 +256,10 @@ func (as *APIService) SearchOracleDatabasesAsXLSX(filter dto.SearchOracleDatabas
 			file.SetCellValue("Databases", fmt.Sprintf("S%d", i), "False")
 			file.SetCellValue("Databases", fmt.Sprintf("T%d", i), "")
 		}
+
+		for j, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue("Databases", fmt.Sprintf("%c%d", 'U'+j, i), v)
+		}
 	}
 
 	return file, nil
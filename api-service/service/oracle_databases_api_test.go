@@ -24,6 +24,7 @@ import (
 
 	"github.com/ercole-io/ercole/v2/api-service/dto"
 	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
@@ -238,6 +239,15 @@ func TestSearchOracleDatabasePatchAdvisorsAsXLSX_Success(t *testing.T) {
 		utils.P("2019-12-05T14:02:03Z"), "Italy", "TST",
 		utils.P("2019-12-05T14:02:03Z"), "",
 	).Return(data, nil).Times(1)
+	db.EXPECT().GetOraclePatchCatalogue().Return(&model.OraclePatchCatalogue{
+		Releases: []model.OraclePatchRelease{
+			{PatchNumber: "13343438", Type: model.OraclePatchTypePSU, Version: "11.2.0.3", Release: "11.2.0.3.2", ReleaseDate: utils.P("2012-04-17T00:00:00Z")},
+			{PatchNumber: "16619892", Type: model.OraclePatchTypePSU, Version: "11.2.0.3", Release: "11.2.0.3.7", ReleaseDate: utils.P("2013-07-16T00:00:00Z")},
+		},
+		Versions: []model.OracleVersionSupport{
+			{Version: "11.2.0.3", PremierSupportEnd: utils.P("2015-01-31T00:00:00Z"), ExtendedSupportEnd: utils.P("2015-08-27T00:00:00Z")},
+		},
+	}, nil).Times(1)
 
 	windowTime := utils.P("2019-12-05T14:02:03Z")
 	filter := dto.GlobalFilter{
@@ -258,6 +268,10 @@ func TestSearchOracleDatabasePatchAdvisorsAsXLSX_Success(t *testing.T) {
 	assert.Equal(t, "0", actual.GetCellValue("Patch_Advisor", "G2"))
 	assert.Equal(t, "0", actual.GetCellValue("Patch_Advisor", "H2"))
 	assert.Equal(t, "0", actual.GetCellValue("Patch_Advisor", "I2"))
+	assert.Equal(t, "11.2.0.3.2", actual.GetCellValue("Patch_Advisor", "J2"))
+	assert.Equal(t, "11.2.0.3.7", actual.GetCellValue("Patch_Advisor", "K2"))
+	assert.Equal(t, "1", actual.GetCellValue("Patch_Advisor", "L2"))
+	assert.Equal(t, model.OracleSupportUnsupported, actual.GetCellValue("Patch_Advisor", "M2"))
 
 }
 
@@ -358,13 +372,13 @@ func TestSearchOracleDatabases_Success(t *testing.T) {
 	db.EXPECT().SearchOracleDatabases(
 		[]string{"foo", "bar", "foobarx"}, "Memory",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(&expectedRes, nil).Times(1)
 
 	res, err := as.SearchOracleDatabases(
 		dto.SearchOracleDatabasesFilter{
 			dto.GlobalFilter{
-				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 			},
 			"foo bar foobarx", "Memory",
 			true, 1, 1,
@@ -386,14 +400,14 @@ func TestSearchOracleDatabases_Fail(t *testing.T) {
 	db.EXPECT().SearchOracleDatabases(
 		[]string{"foo", "bar", "foobarx"}, "Memory",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(nil, aerrMock).Times(1)
 
 	res, err := as.SearchOracleDatabases(
 
 		dto.SearchOracleDatabasesFilter{
 			dto.GlobalFilter{
-				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 			},
 			"foo bar foobarx", "Memory",
 			true, 1, 1,
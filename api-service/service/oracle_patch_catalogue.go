@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"time"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+func (as *APIService) GetOraclePatchCatalogue() (*model.OraclePatchCatalogue, error) {
+	return as.Database.GetOraclePatchCatalogue()
+}
+
+func (as *APIService) ImportOraclePatchCatalogue(catalogue dto.OraclePatchCatalogueImport) (*model.OraclePatchCatalogue, error) {
+	before, err := as.Database.GetOraclePatchCatalogue()
+	if err != nil {
+		return nil, err
+	}
+
+	if catalogue.Releases != nil {
+		if err := as.Database.ReplaceOraclePatchReleases(catalogue.Releases); err != nil {
+			return nil, err
+		}
+	}
+
+	if catalogue.Versions != nil {
+		if err := as.Database.ReplaceOracleVersionSupport(catalogue.Versions); err != nil {
+			return nil, err
+		}
+	}
+
+	after, err := as.Database.GetOraclePatchCatalogue()
+	if err != nil {
+		return nil, err
+	}
+
+	as.audit(model.AuditEntityOraclePatchCatalogue, "", model.AuditActionUpdate,
+		oraclePatchCatalogueAuditSummary(before), oraclePatchCatalogueAuditSummary(after))
+
+	return after, nil
+}
+
+// oraclePatchCatalogueAuditSummary return the sizes of the catalogue recorded in the audit log, instead of the whole catalogue
+func oraclePatchCatalogueAuditSummary(catalogue *model.OraclePatchCatalogue) map[string]interface{} {
+	return map[string]interface{}{
+		"releases": len(catalogue.Releases),
+		"versions": len(catalogue.Versions),
+	}
+}
+
+// adviseOraclePatches set the position in the Oracle patch catalogue of the patches installed on the databases
+func (as *APIService) adviseOraclePatches(advisors dto.PatchAdvisors) error {
+	if len(advisors) == 0 {
+		return nil
+	}
+
+	catalogue, err := as.Database.GetOraclePatchCatalogue()
+	if err != nil {
+		return err
+	}
+
+	now := as.TimeNow()
+
+	for i := range advisors {
+		patchDate := advisors[i].Date.Time()
+		if patchDate.Unix() <= 0 {
+			patchDate = time.Time{}
+		}
+
+		advisors[i].OraclePatchAdvice = catalogue.Advise(advisors[i].Dbver, advisors[i].Description, patchDate, now)
+	}
+
+	return nil
+}
+
+// GetOraclePatchAdvisorSummary return the number of Oracle databases by position of their patches in the catalogue
+func (as *APIService) GetOraclePatchAdvisorSummary(location string, environment string, olderThan time.Time) (*dto.OraclePatchAdvisorSummary, error) {
+	advisors, err := as.SearchOracleDatabasePatchAdvisors("", "", false, -1, -1, as.TimeNow(), location, environment, olderThan, "")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dto.OraclePatchAdvisorSummary{Databases: len(advisors.Content)}
+
+	for _, advisor := range advisors.Content {
+		switch {
+		case advisor.ReleasesBehind == nil:
+			summary.NotInCatalogue++
+		case *advisor.ReleasesBehind == 0:
+			summary.UpToDate++
+		default:
+			summary.Behind++
+		}
+
+		switch advisor.SupportStatus {
+		case model.OracleSupportUnsupported:
+			summary.Unsupported++
+			summary.OutOfPremierSupport++
+		case model.OracleSupportExtended:
+			summary.OutOfPremierSupport++
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestImportOraclePatchCatalogue_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	releases := []model.OraclePatchRelease{
+		{PatchNumber: "33806152", Type: model.OraclePatchTypeRU, Version: "19", Release: "19.15.0.0.220419", ReleaseDate: utils.P("2022-04-19T00:00:00Z")},
+	}
+	versions := []model.OracleVersionSupport{
+		{Version: "19", PremierSupportEnd: utils.P("2024-04-30T00:00:00Z"), ExtendedSupportEnd: utils.P("2027-04-30T00:00:00Z")},
+	}
+	expected := &model.OraclePatchCatalogue{Releases: releases, Versions: versions}
+
+	gomock.InOrder(
+		db.EXPECT().GetOraclePatchCatalogue().Return(&model.OraclePatchCatalogue{Versions: versions}, nil),
+		db.EXPECT().ReplaceOraclePatchReleases(releases).Return(nil),
+		db.EXPECT().GetOraclePatchCatalogue().Return(expected, nil),
+	)
+
+	res, err := as.ImportOraclePatchCatalogue(dto.OraclePatchCatalogueImport{Releases: releases})
+	require.NoError(t, err)
+	assert.Equal(t, expected, res)
+}
+
+func TestImportOraclePatchCatalogue_Fail(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := APIService{
+		Database: db,
+	}
+
+	versions := []model.OracleVersionSupport{{Version: "19"}}
+
+	db.EXPECT().GetOraclePatchCatalogue().Return(&model.OraclePatchCatalogue{}, nil)
+	db.EXPECT().ReplaceOracleVersionSupport(versions).Return(aerrMock)
+
+	res, err := as.ImportOraclePatchCatalogue(dto.OraclePatchCatalogueImport{Versions: versions})
+	require.Equal(t, aerrMock, err)
+	assert.Nil(t, res)
+}
@@ -52,9 +52,12 @@ func (as *APIService) GetOracleDatabasePatchStatusStats(location string, windowT
 	return as.Database.GetOracleDatabasePatchStatusStats(location, windowTime, olderThan)
 }
 
-// GetTopWorkloadOracleDatabaseStats return a array containing top databases by workload
+// GetTopWorkloadOracleDatabaseStats return a array containing top databases by workload,
+// averaging their CPU consumption in the last WorkloadDays
 func (as *APIService) GetTopWorkloadOracleDatabaseStats(location string, limit int, olderThan time.Time) ([]interface{}, error) {
-	return as.Database.GetTopWorkloadOracleDatabaseStats(location, limit, olderThan)
+	since := as.TimeNow().AddDate(0, 0, -as.Config.DataService.ConsumptionMetrics.WorkloadDays)
+
+	return as.Database.GetTopWorkloadOracleDatabaseStats(location, limit, olderThan, since)
 }
 
 // GetOracleDatabaseRACStatusStats return a array containing the number of databases per RAC status
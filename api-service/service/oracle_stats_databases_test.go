@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ercole-io/ercole/v2/api-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
@@ -290,7 +291,13 @@ func TestGetTopWorkloadOracleDatabaseStats_Success(t *testing.T) {
 	defer mockCtrl.Finish()
 	db := NewMockMongoDatabaseInterface(mockCtrl)
 	as := APIService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				ConsumptionMetrics: config.ConsumptionMetrics{WorkloadDays: 30},
+			},
+		},
 		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-12-10T00:00:00Z")),
 	}
 
 	expectedRes := []interface{}{
@@ -309,7 +316,7 @@ func TestGetTopWorkloadOracleDatabaseStats_Success(t *testing.T) {
 	}
 
 	db.EXPECT().GetTopWorkloadOracleDatabaseStats(
-		"Italy", 10, utils.P("2019-12-05T14:02:03Z"),
+		"Italy", 10, utils.P("2019-12-05T14:02:03Z"), utils.P("2019-11-10T00:00:00Z"),
 	).Return(expectedRes, nil).Times(1)
 
 	res, err := as.GetTopWorkloadOracleDatabaseStats(
@@ -325,11 +332,17 @@ func TestGetTopWorkloadOracleDatabaseStats_Fail(t *testing.T) {
 	defer mockCtrl.Finish()
 	db := NewMockMongoDatabaseInterface(mockCtrl)
 	as := APIService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				ConsumptionMetrics: config.ConsumptionMetrics{WorkloadDays: 30},
+			},
+		},
 		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-12-10T00:00:00Z")),
 	}
 
 	db.EXPECT().GetTopWorkloadOracleDatabaseStats(
-		"Italy", 10, utils.P("2019-12-05T14:02:03Z"),
+		"Italy", 10, utils.P("2019-12-05T14:02:03Z"), utils.P("2019-11-10T00:00:00Z"),
 	).Return(nil, aerrMock).Times(1)
 
 	res, err := as.GetTopWorkloadOracleDatabaseStats(
@@ -27,14 +27,23 @@ import (
 
 func (as *APIService) SearchPostgreSqlInstances(f dto.SearchPostgreSqlInstancesFilter) (*dto.PostgreSqlInstanceResponse, error) {
 	return as.Database.SearchPostgreSqlInstances(strings.Split(f.Search, " "), f.SortBy, f.SortDesc,
-		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan)
+		f.PageNumber, f.PageSize, f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter)
+}
+
+func (as *APIService) ListPostgreSqlInstances(f dto.SearchPostgreSqlInstancesFilter, q dto.ListQuery) (*dto.ListPage, error) {
+	return as.Database.ListPostgreSqlInstances(strings.Split(f.Search, " "), f.Location, f.Environment, f.OlderThan, f.HostMetadataFilter, q)
 }
 
 func (as *APIService) SearchPostgreSqlInstancesAsXLSX(filter dto.SearchPostgreSqlInstancesFilter) (*excelize.File, error) {
 	instances, err := as.Database.SearchPostgreSqlInstances(strings.Split(filter.Search, " "),
 		filter.SortBy, filter.SortDesc,
 		-1, -1,
-		filter.Location, filter.Environment, filter.OlderThan)
+		filter.Location, filter.Environment, filter.OlderThan, filter.HostMetadataFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := as.hostMetadataByHostname()
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +56,7 @@ func (as *APIService) SearchPostgreSqlInstancesAsXLSX(filter dto.SearchPostgreSq
 		"Charset",
 		"Version",
 	}
+	headers = append(headers, hostMetadataXLSXHeaders...)
 
 	file, err := exutils.NewXLSX(as.Config, sheet, headers...)
 	if err != nil {
@@ -62,6 +72,10 @@ func (as *APIService) SearchPostgreSqlInstancesAsXLSX(filter dto.SearchPostgreSq
 		file.SetCellValue(sheet, nextAxis(), val.Name)
 		file.SetCellValue(sheet, nextAxis(), val.Charset)
 		file.SetCellValue(sheet, nextAxis(), val.Version)
+
+		for _, v := range hostMetadataXLSXValues(metadata[val.Hostname]) {
+			file.SetCellValue(sheet, nextAxis(), v)
+		}
 	}
 
 	return file, nil
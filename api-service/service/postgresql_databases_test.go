@@ -68,13 +68,13 @@ func TestSearchPostgreSqlInstances_Success(t *testing.T) {
 	db.EXPECT().SearchPostgreSqlInstances(
 		[]string{"foo", "bar", "foobarx"}, "Hostname",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(&expectedRes, nil).Times(1)
 
 	res, err := as.SearchPostgreSqlInstances(
 		dto.SearchPostgreSqlInstancesFilter{
 			dto.GlobalFilter{
-				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 			},
 			"foo bar foobarx", "Hostname",
 			true, 1, 1,
@@ -96,14 +96,14 @@ func TestSearchPostgreSqlInstances_Fail(t *testing.T) {
 	db.EXPECT().SearchPostgreSqlInstances(
 		[]string{"foo", "bar", "foobarx"}, "Memory",
 		true, 1, 1,
-		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+		"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 	).Return(nil, aerrMock).Times(1)
 
 	res, err := as.SearchPostgreSqlInstances(
 
 		dto.SearchPostgreSqlInstancesFilter{
 			dto.GlobalFilter{
-				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"),
+				"Italy", "PROD", utils.P("2019-12-05T14:02:03Z"), dto.HostMetadataFilter{},
 			},
 			"foo bar foobarx", "Memory",
 			true, 1, 1,
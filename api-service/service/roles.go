@@ -58,7 +58,13 @@ func (as *APIService) AddRole(role model.Role) error {
 		return err
 	}
 
-	return as.Database.AddRole(role)
+	if err := as.Database.AddRole(role); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityRole, role.Name, model.AuditActionCreate, nil, role)
+
+	return nil
 }
 
 func (as *APIService) UpdateRole(role model.Role) error {
@@ -75,6 +81,11 @@ func (as *APIService) UpdateRole(role model.Role) error {
 		return err
 	}
 
+	var before *model.Role
+	if as.isAudited() {
+		before, _ = as.Database.GetRole(role.Name)
+	}
+
 	documents := bson.D{
 		primitive.E{Key: "description", Value: role.Description},
 		primitive.E{Key: "location", Value: role.Location},
@@ -85,11 +96,24 @@ func (as *APIService) UpdateRole(role model.Role) error {
 		return err
 	}
 
+	as.audit(model.AuditEntityRole, role.Name, model.AuditActionUpdate, before, role)
+
 	return nil
 }
 
 func (as *APIService) RemoveRole(roleName string) error {
-	return as.Database.RemoveRole(roleName)
+	var before *model.Role
+	if as.isAudited() {
+		before, _ = as.Database.GetRole(roleName)
+	}
+
+	if err := as.Database.RemoveRole(roleName); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityRole, roleName, model.AuditActionDelete, before, nil)
+
+	return nil
 }
 
 func (as *APIService) getLocationError(location string) error {
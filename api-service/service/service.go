@@ -38,6 +38,8 @@ type APIServiceInterface interface {
 	Init()
 	// SearchHosts search hosts
 	SearchHosts(mode string, filters dto.SearchHostsFilters) ([]map[string]interface{}, error)
+	// ListHosts return a page of hosts using cursor pagination, multi-field sort and field projection
+	ListHosts(mode string, filters dto.SearchHostsFilters, q dto.ListQuery) (*dto.ListPage, error)
 	// SearchHostsAsLMS return LMS template file with the hosts filtered
 	SearchHostsAsLMS(filters dto.SearchHostsAsLMS) (*excelize.File, error)
 	SearchHostsAsXLSX(filters dto.SearchHostsFilters) (*excelize.File, error)
@@ -48,10 +50,14 @@ type APIServiceInterface interface {
 	ListManagedTechnologies(sortBy string, sortDesc bool, location string, environment string, olderThan time.Time) ([]model.TechnologyStatus, error)
 	// SearchAlerts search alerts
 	SearchAlerts(alertFilter alert_filter.Alert) (*dto.Pagination, error)
+	// ListAlerts return a page of alerts using cursor pagination, multi-field sort and field projection
+	ListAlerts(alertFilter alert_filter.Alert, q dto.ListQuery) (*dto.ListPage, error)
 	SearchAlertsAsXLSX(status string, from, to time.Time, filter dto.GlobalFilter) (*excelize.File, error)
 	GetAlerts(status string, from, to time.Time, filter dto.GlobalFilter) ([]map[string]interface{}, error)
 	// SearchClusters search clusters
 	SearchClusters(mode string, search string, sortBy string, sortDesc bool, page int, pageSize int, location string, environment string, olderThan time.Time) ([]dto.Cluster, error)
+	// ListClusters return a page of clusters using cursor pagination, multi-field sort and field projection
+	ListClusters(mode string, search string, location string, environment string, olderThan time.Time, q dto.ListQuery) (*dto.ListPage, error)
 	SearchClustersAsXLSX(filter dto.GlobalFilter) (*excelize.File, error)
 	// GetCluster return the cluster specified in the clusterName param
 	GetCluster(clusterName string, olderThan time.Time) (*dto.Cluster, error)
@@ -65,8 +71,16 @@ type APIServiceInterface interface {
 	// SearchOracleDatabasePatchAdvisors search patch advisors
 	SearchOracleDatabasePatchAdvisors(search string, sortBy string, sortDesc bool, page int, pageSize int, windowTime time.Time, location string, environment string, olderThan time.Time, status string) (*dto.PatchAdvisorResponse, error)
 	SearchOracleDatabasePatchAdvisorsAsXLSX(windowTime time.Time, filter dto.GlobalFilter) (*excelize.File, error)
+	// GetOraclePatchAdvisorSummary return the number of Oracle databases by position of their patches in the catalogue
+	GetOraclePatchAdvisorSummary(location string, environment string, olderThan time.Time) (*dto.OraclePatchAdvisorSummary, error)
+	// GetOraclePatchCatalogue return the Oracle patch releases and the support of the Oracle versions
+	GetOraclePatchCatalogue() (*model.OraclePatchCatalogue, error)
+	// ImportOraclePatchCatalogue replace the parts of the Oracle patch catalogue that aren't nil
+	ImportOraclePatchCatalogue(catalogue dto.OraclePatchCatalogueImport) (*model.OraclePatchCatalogue, error)
 	// SearchOracleDatabases search databases
 	SearchOracleDatabases(filter dto.SearchOracleDatabasesFilter) (*dto.OracleDatabaseResponse, error)
+	// ListOracleDatabases return a page of databases using cursor pagination, multi-field sort and field projection
+	ListOracleDatabases(filter dto.SearchOracleDatabasesFilter, q dto.ListQuery) (*dto.ListPage, error)
 	// SearchOracleDatabases search databases
 	SearchOracleDatabasesAsXLSX(filter dto.SearchOracleDatabasesFilter) (*excelize.File, error)
 	// SearchOracleDatabaseUsedLicenses return the list of consumed licenses
@@ -76,6 +90,12 @@ type APIServiceInterface interface {
 	ListAllLocations(location string, environment string, olderThan time.Time) ([]string, error)
 	// ListLocations list locations
 	ListLocations(user interface{}) ([]string, error)
+	// GetUserAuthorization return the locations granted to the user by the roles of its groups and the permission on each of them
+	GetUserAuthorization(user model.User) (*model.UserAuthorization, error)
+	// GetHostLocation return the location of the current host
+	GetHostLocation(hostname string) (string, error)
+	// GetResourceHosts return the hostnames the resource with the id refers to
+	GetResourceHosts(resource string, id primitive.ObjectID) ([]string, error)
 	// ListEnvironments list environments
 	ListEnvironments(location string, environment string, olderThan time.Time) ([]string, error)
 
@@ -135,6 +155,13 @@ type APIServiceInterface interface {
 
 	GetOracleDatabaseLicenseTypes() ([]model.OracleDatabaseLicenseType, error)
 	GetOracleDatabaseLicensesCompliance() ([]dto.LicenseCompliance, error)
+	// SimulateOracleDatabaseLicenses return the difference of the licenses compliance of the hosts in the locations
+	// after the hypothetical changes, without saving them
+	SimulateOracleDatabaseLicenses(simulation dto.OracleDatabaseLicensesSimulation, location string) ([]dto.LicenseComplianceDiff, error)
+	// PlanOracleDatabaseConsolidation place the Oracle databases on the target shapes minimising the processor licenses
+	PlanOracleDatabaseConsolidation(request dto.OracleDatabaseConsolidationRequest) (*dto.OracleDatabaseConsolidationPlan, error)
+	// PlanOracleDatabaseConsolidationAsXLSX return the consolidation plan as XLSX
+	PlanOracleDatabaseConsolidationAsXLSX(request dto.OracleDatabaseConsolidationRequest) (*excelize.File, error)
 	DeleteOracleDatabaseLicenseType(id string) error
 	AddOracleDatabaseLicenseType(licenseType model.OracleDatabaseLicenseType) (*model.OracleDatabaseLicenseType, error)
 	UpdateOracleDatabaseLicenseType(licenseType model.OracleDatabaseLicenseType) (*model.OracleDatabaseLicenseType, error)
@@ -186,27 +213,36 @@ type APIServiceInterface interface {
 
 	// SearchSqlServerInstances search databases
 	SearchSqlServerInstances(filter dto.SearchSqlServerInstancesFilter) (*dto.SqlServerInstanceResponse, error)
+	ListSqlServerInstances(filter dto.SearchSqlServerInstancesFilter, q dto.ListQuery) (*dto.ListPage, error)
 	// SearchOracleDatabases search databases
 	SearchSqlServerInstancesAsXLSX(filter dto.SearchSqlServerInstancesFilter) (*excelize.File, error)
 
 	// SQL SERVER DATABASE CONTRACTS
 	AddSqlServerDatabaseContract(contract model.SqlServerDatabaseContract) (*model.SqlServerDatabaseContract, error)
-	GetSqlServerDatabaseContracts() ([]model.SqlServerDatabaseContract, error)
-	GetSqlServerDatabaseContractsAsXLSX() (*excelize.File, error)
+	GetSqlServerDatabaseContracts(location string) ([]model.SqlServerDatabaseContract, error)
+	GetSqlServerDatabaseContractsAsXLSX(location string) (*excelize.File, error)
 	DeleteSqlServerDatabaseContract(id primitive.ObjectID) error
 	UpdateSqlServerDatabaseContract(contract model.SqlServerDatabaseContract) (*model.SqlServerDatabaseContract, error)
 
 	ImportSQLServerDatabaseContracts(reader *csv.Reader) error
 
-	// AckAlerts ack the specified alerts
-	AckAlerts(alertsFilter dto.AlertsFilter) error
+	// AckAlerts ack the specified alerts, recording it in their history
+	AckAlerts(alertsFilter dto.AlertsFilter, username string) error
 	// DismissHost dismiss the specified host
-	DismissHost(hostname string) error
+	DismissHost(hostname, username string) error
 
 	IsMissingDB(hostname string) (bool, error)
 
-	// UpdateAlertsStatus update alerts status
-	UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus string) error
+	// UpdateAlertsStatus update alerts status, recording it in their history
+	UpdateAlertsStatus(alertsFilter dto.AlertsFilter, newStatus, username string) error
+	// GetAlert return the alert with its history
+	GetAlert(id primitive.ObjectID) (*model.Alert, error)
+	// UpdateAlertStatus change the status of the alert, recording it in the history
+	UpdateAlertStatus(id primitive.ObjectID, status string, snoozedUntil *time.Time, comment, username string) (*model.Alert, error)
+	// AssignAlert change the assignee of the alert, recording it in the history
+	AssignAlert(id primitive.ObjectID, assignee, username string) (*model.Alert, error)
+	// CommentAlert add a comment to the history of the alert
+	CommentAlert(id primitive.ObjectID, comment, username string) (*model.Alert, error)
 
 	// GetInfoForFrontendDashboard return all informations needed for the frontend dashboard page
 	GetInfoForFrontendDashboard(location string, environment string, olderThan time.Time) (map[string]interface{}, error)
@@ -238,6 +274,7 @@ type APIServiceInterface interface {
 	// MYSQL
 
 	SearchMySQLInstances(filter dto.GlobalFilter) ([]dto.MySQLInstance, error)
+	ListMySQLInstances(filter dto.GlobalFilter, q dto.ListQuery) (*dto.ListPage, error)
 	SearchMySQLInstancesAsXLSX(filter dto.GlobalFilter) (*excelize.File, error)
 	GetMySQLUsedLicenses(hostname string, filter dto.GlobalFilter) ([]dto.MySQLUsedLicense, error)
 	GetUsedLicensesPerDatabasesAsXLSX(filter dto.GlobalFilter) (*excelize.File, error)
@@ -245,8 +282,8 @@ type APIServiceInterface interface {
 
 	AddMySQLContract(contract model.MySQLContract) (*model.MySQLContract, error)
 	UpdateMySQLContract(contract model.MySQLContract) (*model.MySQLContract, error)
-	GetMySQLContracts() ([]model.MySQLContract, error)
-	GetMySQLContractsAsXLSX() (*excelize.File, error)
+	GetMySQLContracts(location string) ([]model.MySQLContract, error)
+	GetMySQLContractsAsXLSX(location string) (*excelize.File, error)
 	DeleteMySQLContract(id primitive.ObjectID) error
 
 	ImportMySQLDatabaseContracts(reader *csv.Reader) error
@@ -254,15 +291,72 @@ type APIServiceInterface interface {
 	// POSTGRESQL
 	// SearchSqlServerInstances search databases
 	SearchPostgreSqlInstances(filter dto.SearchPostgreSqlInstancesFilter) (*dto.PostgreSqlInstanceResponse, error)
+	ListPostgreSqlInstances(filter dto.SearchPostgreSqlInstancesFilter, q dto.ListQuery) (*dto.ListPage, error)
 	// SearchOracleDatabases search databases
 	SearchPostgreSqlInstancesAsXLSX(filter dto.SearchPostgreSqlInstancesFilter) (*excelize.File, error)
 
 	// MONGODB
 	// SearchMongoDBInstances search databases
 	SearchMongoDBInstances(filter dto.SearchMongoDBInstancesFilter) (*dto.MongoDBInstanceResponse, error)
+	ListMongoDBInstances(filter dto.SearchMongoDBInstancesFilter, q dto.ListQuery) (*dto.ListPage, error)
 	// SearchOracleDatabases search databases
 	SearchMongoDBInstancesAsXLSX(filter dto.SearchMongoDBInstancesFilter) (*excelize.File, error)
 
+	// ALERT ROUTING RULES
+	AddAlertRoutingRule(rule model.AlertRoutingRule) (*model.AlertRoutingRule, error)
+	UpdateAlertRoutingRule(rule model.AlertRoutingRule) (*model.AlertRoutingRule, error)
+	GetAlertRoutingRules() ([]model.AlertRoutingRule, error)
+	GetAlertRoutingRule(id primitive.ObjectID) (*model.AlertRoutingRule, error)
+	DeleteAlertRoutingRule(id primitive.ObjectID) error
+
+	// API TOKENS
+	// CreateAPIToken create a new API token and return it with its plaintext value
+	CreateAPIToken(token model.APIToken, username string) (*dto.APITokenCreated, error)
+	GetAPITokens() ([]model.APIToken, error)
+	DeleteAPIToken(id primitive.ObjectID) error
+	// AuthenticateAPIToken return the user impersonated by the token if it's valid and not expired
+	AuthenticateAPIToken(plain string) (*model.User, error)
+
+	// HOST IDENTITIES
+	ListHostIdentities() ([]model.HostIdentity, error)
+	// AddHostAlias add alias to the names of the host
+	AddHostAlias(hostname, alias string) (*model.HostIdentity, error)
+	// RemoveHostAlias remove alias from the names of the host
+	RemoveHostAlias(hostname, alias string) (*model.HostIdentity, error)
+	// MergeHost move the hostdata, the alerts and the contracts of source to target, and make source an alias of target
+	MergeHost(source, target string) (*model.HostIdentity, error)
+	// GetHostAliasSuggestions return the pairs of current hosts that are probably the same host
+	GetHostAliasSuggestions() ([]dto.HostAliasSuggestion, error)
+
+	// HOST METADATA
+	// ListHostMetadata return the metadata of the hosts matching the filter
+	ListHostMetadata(filter dto.HostMetadataFilter) ([]model.HostMetadata, error)
+	// GetHostMetadata return the metadata of the host
+	GetHostMetadata(hostname string) (*model.HostMetadata, error)
+	// UpdateHostMetadata replace the metadata of the host edited by the users
+	UpdateHostMetadata(hostname string, req dto.HostMetadataRequest) (*model.HostMetadata, error)
+
+	// CAPACITY FORECASTS
+	// ListCapacityForecasts return the capacity forecasts matching the filter
+	ListCapacityForecasts(filter dto.CapacityForecastFilter) ([]model.CapacityForecast, error)
+	ListCapacityForecastsAsXLSX(filter dto.CapacityForecastFilter) (*excelize.File, error)
+
+	// BACKUP COMPLIANCE
+	// GetBackupComplianceReport return the evaluations of the Oracle backups matching the filter and their summary
+	GetBackupComplianceReport(filter dto.BackupComplianceFilter) (*dto.BackupComplianceReport, error)
+	GetBackupComplianceReportAsXLSX(filter dto.BackupComplianceFilter) (*excelize.File, error)
+
+	// AUDIT LOG
+	// WithAuditActor return a service that records in the audit log the changes made by actor
+	WithAuditActor(actor model.AuditActor) APIServiceInterface
+	// SearchAuditLog return the audit log entries matching the filter, from the newest
+	SearchAuditLog(filter dto.AuditLogFilter) ([]model.AuditLogEntry, error)
+	SearchAuditLogAsXLSX(filter dto.AuditLogFilter) (*excelize.File, error)
+
+	// METRICS
+	// GetMetrics return the fleet, alert and licensing metrics
+	GetMetrics() (*dto.Metrics, error)
+
 	// ROLES
 	GetRole(name string) (*model.Role, error)
 	GetRoles() ([]model.Role, error)
@@ -325,6 +419,9 @@ type APIService struct {
 	mockGetOracleDatabaseContracts func(filters dto.GetOracleDatabaseContractsFilter) ([]dto.OracleDatabaseContractFE, error)
 
 	AlertSvcClient alertServiceClient.AlertSvcClientInterface
+
+	// auditActor is who makes the changes recorded in the audit log, nil when they aren't recorded
+	auditActor *model.AuditActor
 }
 
 // Init initializes the service and database
@@ -55,11 +55,35 @@ func (as *APIService) AddUser(user model.User) error {
 		return err
 	}
 
-	return as.Database.AddUser(user)
+	if err := as.Database.AddUser(user); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityUser, user.Username, model.AuditActionCreate, nil, user)
+
+	return nil
 }
 
 func (as *APIService) UpdateUserGroups(username string, groups []string) error {
-	return as.Database.UpdateUserGroups(username, groups)
+	type userGroups struct {
+		Groups []string `json:"groups"`
+	}
+
+	var before *userGroups
+
+	if as.isAudited() {
+		if user, err := as.Database.GetUser(username); err == nil {
+			before = &userGroups{Groups: user.Groups}
+		}
+	}
+
+	if err := as.Database.UpdateUserGroups(username, groups); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityUser, username, model.AuditActionUpdate, before, userGroups{Groups: groups})
+
+	return nil
 }
 
 func (as *APIService) UpdateUserLastLogin(updatedUser model.User) error {
@@ -83,7 +107,18 @@ func (as *APIService) AddLimitedGroup(updatedUser model.User) error {
 }
 
 func (as *APIService) RemoveUser(username string) error {
-	return as.Database.RemoveUser(username)
+	var before *model.User
+	if as.isAudited() {
+		before, _ = as.Database.GetUser(username)
+	}
+
+	if err := as.Database.RemoveUser(username); err != nil {
+		return err
+	}
+
+	as.audit(model.AuditEntityUser, username, model.AuditActionDelete, before, nil)
+
+	return nil
 }
 
 func (as *APIService) NewPassword(username string) (string, error) {
@@ -100,6 +135,9 @@ func (as *APIService) NewPassword(username string) (string, error) {
 		return "", err
 	}
 
+	as.audit(model.AuditEntityUser, username, model.AuditActionResetPassword,
+		nil, map[string]string{"password": hashPwd})
+
 	return suggestedPassword, nil
 }
 
@@ -139,5 +177,8 @@ func (as *APIService) UpdatePassword(username string, oldPass string, newPass st
 		return err
 	}
 
+	as.audit(model.AuditEntityUser, username, model.AuditActionUpdate,
+		map[string]string{"password": user.Password}, map[string]string{"password": hashPwd})
+
 	return nil
 }
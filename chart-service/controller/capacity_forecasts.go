@@ -0,0 +1,64 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetCapacityForecastChart return the used space of the tablespaces, filesystems and databases of the host over time
+// and its projection
+func (ctrl *ChartController) GetCapacityForecastChart(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	var horizonDays int
+
+	hostname := mux.Vars(r)["hostname"]
+	kind := r.URL.Query().Get("kind")
+	databaseName := r.URL.Query().Get("database")
+
+	if kind != "" && !utils.Contains(model.CapacityForecastKinds, kind) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, utils.NewError(errors.New("Invalid kind"), http.StatusText(http.StatusUnprocessableEntity)))
+		return
+	}
+
+	if horizonDays, err = utils.Str2int(r.URL.Query().Get("horizon-days"), 90); err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if horizonDays < 0 {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, utils.NewError(errors.New("Invalid horizon-days"), http.StatusText(http.StatusUnprocessableEntity)))
+		return
+	}
+
+	charts, err := ctrl.Service.GetCapacityForecastChart(hostname, kind, databaseName, horizonDays)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"capacityForecasts": charts,
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetHostConsumptionChart return the CPU and IO consumption of a host over a range and its rollups
+func (ctrl *ChartController) GetHostConsumptionChart(w http.ResponseWriter, r *http.Request) {
+	ctrl.getConsumptionChart(w, r, "")
+}
+
+// GetOracleDatabaseConsumptionChart return the CPU and IO consumption of an Oracle database over a range and its rollups
+func (ctrl *ChartController) GetOracleDatabaseConsumptionChart(w http.ResponseWriter, r *http.Request) {
+	ctrl.getConsumptionChart(w, r, mux.Vars(r)["dbname"])
+}
+
+func (ctrl *ChartController) getConsumptionChart(w http.ResponseWriter, r *http.Request, databaseName string) {
+	hostname := mux.Vars(r)["hostname"]
+
+	to, err := utils.Str2time(r.URL.Query().Get("to"), ctrl.TimeNow())
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	from, err := utils.Str2time(r.URL.Query().Get("from"), to.AddDate(0, 0, -30))
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if !from.Before(to) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, utils.NewError(errors.New("from must be before to"), http.StatusText(http.StatusUnprocessableEntity)))
+		return
+	}
+
+	resolution := strings.ToUpper(r.URL.Query().Get("resolution"))
+	if resolution != "" && !utils.Contains(model.ConsumptionResolutions, resolution) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, utils.NewError(errors.New("Invalid resolution"), http.StatusText(http.StatusUnprocessableEntity)))
+		return
+	}
+
+	chart, err := ctrl.Service.GetConsumptionChart(hostname, databaseName, resolution, from, to)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, chart)
+}
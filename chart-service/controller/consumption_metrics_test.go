@@ -0,0 +1,147 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/chart-service/dto"
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetHostConsumptionChart_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockChartServiceInterface(mockCtrl)
+	ac := ChartController{
+		Service: as,
+		Config:  config.Configuration{},
+		TimeNow: utils.Btc(utils.P("2022-05-10T00:00:00Z")),
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	chart := &dto.ConsumptionChart{
+		Hostname:   "foobar",
+		Resolution: model.ConsumptionResolutionRaw,
+		From:       utils.P("2022-04-10T00:00:00Z"),
+		To:         utils.P("2022-05-10T00:00:00Z"),
+		Metrics:    []model.ConsumptionMetric{},
+	}
+	as.EXPECT().GetConsumptionChart("foobar", "", "", utils.P("2022-04-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z")).
+		Return(chart, nil)
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+	req = mux.SetURLVars(req, map[string]string{"hostname": "foobar"})
+
+	http.HandlerFunc(ac.GetHostConsumptionChart).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(chart), rr.Body.String())
+}
+
+func TestGetOracleDatabaseConsumptionChart_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockChartServiceInterface(mockCtrl)
+	ac := ChartController{
+		Service: as,
+		Config:  config.Configuration{},
+		TimeNow: utils.Btc(utils.P("2022-05-10T00:00:00Z")),
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	chart := &dto.ConsumptionChart{
+		Hostname:     "foobar",
+		DatabaseName: "ERCOLE",
+		Resolution:   model.ConsumptionResolutionDaily,
+		Metrics:      []model.ConsumptionMetric{},
+	}
+	as.EXPECT().GetConsumptionChart("foobar", "ERCOLE", model.ConsumptionResolutionDaily, utils.P("2021-01-01T00:00:00Z"), utils.P("2022-01-01T00:00:00Z")).
+		Return(chart, nil)
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/?from=2021-01-01T00:00:00Z&to=2022-01-01T00:00:00Z&resolution=daily", nil)
+	require.NoError(t, err)
+	req = mux.SetURLVars(req, map[string]string{"hostname": "foobar", "dbname": "ERCOLE"})
+
+	http.HandlerFunc(ac.GetOracleDatabaseConsumptionChart).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(chart), rr.Body.String())
+}
+
+func TestGetHostConsumptionChart_UnprocessableEntity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockChartServiceInterface(mockCtrl)
+	ac := ChartController{
+		Service: as,
+		Config:  config.Configuration{},
+		TimeNow: utils.Btc(utils.P("2022-05-10T00:00:00Z")),
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	for _, query := range []string{
+		"/?from=asdf",
+		"/?to=asdf",
+		"/?from=2022-05-01T00:00:00Z&to=2022-04-01T00:00:00Z",
+		"/?resolution=hourly",
+	} {
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", query, nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"hostname": "foobar"})
+
+		http.HandlerFunc(ac.GetHostConsumptionChart).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, query)
+	}
+}
+
+func TestGetHostConsumptionChart_InternalServerError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockChartServiceInterface(mockCtrl)
+	ac := ChartController{
+		Service: as,
+		Config:  config.Configuration{},
+		TimeNow: utils.Btc(utils.P("2022-05-10T00:00:00Z")),
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().GetConsumptionChart("foobar", "", "", gomock.Any(), gomock.Any()).
+		Return(nil, aerrMock)
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+	req = mux.SetURLVars(req, map[string]string{"hostname": "foobar"})
+
+	http.HandlerFunc(ac.GetHostConsumptionChart).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+}
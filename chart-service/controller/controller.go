@@ -42,6 +42,14 @@ type ChartControllerInterface interface {
 	GetTechnologiesMetrics(w http.ResponseWriter, r *http.Request)
 
 	GetHostCores(w http.ResponseWriter, r *http.Request)
+
+	// GetCapacityForecastChart return the chart data related to the capacity forecasts of a host
+	GetCapacityForecastChart(w http.ResponseWriter, r *http.Request)
+
+	// GetHostConsumptionChart return the CPU and IO consumption of a host over a range and its rollups
+	GetHostConsumptionChart(w http.ResponseWriter, r *http.Request)
+	// GetOracleDatabaseConsumptionChart return the CPU and IO consumption of an Oracle database over a range and its rollups
+	GetOracleDatabaseConsumptionChart(w http.ResponseWriter, r *http.Request)
 }
 
 // ChartController is the struct used to handle the requests from agents and contains the concrete implementation of ChartControllerInterface
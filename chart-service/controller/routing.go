@@ -59,4 +59,7 @@ func (ctrl *ChartController) setupProtectedRoutes(router *mux.Router) {
 	router.HandleFunc("/technologies/types", ctrl.GetTechnologyTypes).Methods("GET")
 
 	router.HandleFunc("/hosts/cores", ctrl.GetHostCores).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}/capacity-forecasts", ctrl.GetCapacityForecastChart).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}/consumptions", ctrl.GetHostConsumptionChart).Methods("GET")
+	router.HandleFunc("/hosts/{hostname}/technologies/oracle/databases/{dbname}/consumptions", ctrl.GetOracleDatabaseConsumptionChart).Methods("GET")
 }
@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetCapacityForecasts return the capacity forecasts of the host, filtered by kind and database if they aren't empty
+func (md *MongoDatabase) GetCapacityForecasts(hostname, kind, databaseName string) ([]model.CapacityForecast, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("capacity_forecasts").Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			mu.APMatch(bson.M{"hostname": hostname}),
+			mu.APOptionalStage(kind != "", mu.APMatch(bson.M{"kind": kind})),
+			mu.APOptionalStage(databaseName != "", mu.APMatch(bson.M{"databaseName": databaseName})),
+			mu.APSort(bson.D{
+				{Key: "kind", Value: 1},
+				{Key: "databaseName", Value: 1},
+				{Key: "name", Value: 1},
+			}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	var items = make([]model.CapacityForecast, 0)
+	if err := cur.All(context.TODO(), &items); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return items, nil
+}
@@ -58,6 +58,11 @@ func FilterByOldnessSteps(olderThan time.Time) bson.A {
 				"check": mu.QOSize(0),
 			}),
 			mu.APUnset("check"),
+
+			// the hostdata stored as delta are read as rebuilt by rebuildHostDataHistory
+			mu.APLookupSimple(hostDataHistoryCacheCollection, "_id", "_id", "rebuilt"),
+			mu.APReplaceWith(mu.APOIfNull(mu.APOArrayElemAt("$rebuilt", 0), "$$ROOT")),
+			mu.APUnset("rebuilt", "cachedAt"),
 		}),
 	)
 }
@@ -0,0 +1,62 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetConsumptionMetrics return the consumption metrics of the host, or of its database if it isn't empty,
+// with the resolution from the start of the range to its end
+func (md *MongoDatabase) GetConsumptionMetrics(hostname, databaseName, resolution string, from, to time.Time) ([]model.ConsumptionMetric, error) {
+	collection := "consumption_metrics"
+	if resolution == model.ConsumptionResolutionDaily {
+		collection = "consumption_metrics_daily"
+	}
+
+	database := interface{}(databaseName)
+	if databaseName == "" {
+		database = bson.M{"$exists": false}
+	}
+
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(collection).Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			mu.APMatch(bson.M{
+				"meta.hostname":     hostname,
+				"meta.databaseName": database,
+				"timestamp":         bson.M{"$gte": from, "$lte": to},
+			}),
+			mu.APSort(bson.M{"timestamp": 1}),
+		),
+	)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	var items = make([]model.ConsumptionMetric, 0)
+	if err := cur.All(context.TODO(), &items); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return items, nil
+}
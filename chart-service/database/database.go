@@ -27,6 +27,7 @@ import (
 	"github.com/ercole-io/ercole/v2/chart-service/dto"
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
@@ -45,6 +46,13 @@ type MongoDatabaseInterface interface {
 	GetLicenseComplianceHistory() ([]dto.LicenseComplianceHistory, error)
 
 	GetHostCores(location, environment string, olderThan, newerThan time.Time) ([]dto.HostCores, error)
+
+	// GetCapacityForecasts return the capacity forecasts of the host, filtered by kind and database if they aren't empty
+	GetCapacityForecasts(hostname, kind, databaseName string) ([]model.CapacityForecast, error)
+
+	// GetConsumptionMetrics return the consumption metrics of the host, or of its database if it isn't empty,
+	// with the resolution in the range
+	GetConsumptionMetrics(hostname, databaseName, resolution string, from, to time.Time) ([]model.ConsumptionMetric, error)
 }
 
 // MongoDatabase is a implementation
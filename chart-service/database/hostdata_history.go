@@ -0,0 +1,153 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// hostDataHistoryCacheCollection contains the archived hostdata stored as delta, rebuilt for the reads at a past date.
+// Its documents expire, so the history in hosts stays compacted
+const hostDataHistoryCacheCollection = "hosts_history_cache"
+
+// rebuildHostDataHistory store in hostDataHistoryCacheCollection the hostdata that are the most recent of their host
+// at the date olderThan and are stored as delta, so FilterByOldnessSteps reads them as any other hostdata
+func (md *MongoDatabase) rebuildHostDataHistory(olderThan time.Time) error {
+	if olderThan == utils.MAX_TIME {
+		return nil
+	}
+
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
+		context.TODO(),
+		mu.MAPipeline(
+			mu.APMatch(bson.M{
+				"createdAt": mu.QOLessThanOrEqual(olderThan),
+			}),
+			mu.APSort(bson.M{"createdAt": -1}),
+			mu.APGroup(bson.M{
+				"_id":   "$hostname",
+				"id":    bson.M{"$first": "$_id"},
+				"delta": bson.M{"$first": "$historyDelta.baseID"},
+			}),
+			mu.APMatch(bson.M{
+				"delta": bson.M{"$ne": nil},
+			}),
+			mu.APLookupPipeline(hostDataHistoryCacheCollection, bson.M{"id": "$id"}, "cached", mu.MAPipeline(
+				mu.APMatch(mu.QOExpr(mu.APOEqual("$_id", "$$id"))),
+				mu.APProject(bson.M{"_id": 1}),
+			)),
+			mu.APProject(bson.M{
+				"_id":    0,
+				"id":     1,
+				"cached": mu.APOGreater(mu.APOSize("$cached"), 0),
+			}),
+		),
+	)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	var deltas []struct {
+		ID     primitive.ObjectID `bson:"id"`
+		Cached bool               `bson:"cached"`
+	}
+	if err := cur.All(context.TODO(), &deltas); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	cache := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostDataHistoryCacheCollection)
+	cached := make([]primitive.ObjectID, 0)
+
+	for _, delta := range deltas {
+		if delta.Cached {
+			cached = append(cached, delta.ID)
+			continue
+		}
+
+		full, err := md.findFullHostData(delta.ID)
+		if err != nil {
+			return err
+		}
+
+		raw, err := bson.Marshal(full)
+		if err != nil {
+			return utils.NewError(err, "Can't encode hostdata")
+		}
+
+		var doc bson.M
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return utils.NewError(err, "Can't encode hostdata")
+		}
+
+		doc["cachedAt"] = md.TimeNow()
+
+		if _, err := cache.ReplaceOne(context.TODO(), bson.M{"_id": full.ID}, doc, options.Replace().SetUpsert(true)); err != nil {
+			return utils.NewError(err, "DB ERROR")
+		}
+	}
+
+	// the hostdata still in use mustn't expire during the reads
+	if len(cached) > 0 {
+		if _, err := cache.UpdateMany(context.TODO(),
+			bson.M{"_id": bson.M{"$in": cached}},
+			bson.M{"$set": bson.M{"cachedAt": md.TimeNow()}},
+		); err != nil {
+			return utils.NewError(err, "DB ERROR")
+		}
+	}
+
+	return nil
+}
+
+// findFullHostData return the hostdata with the id, rebuilt from the chain of deltas if it's stored as delta
+func (md *MongoDatabase) findFullHostData(id primitive.ObjectID) (*model.HostDataBE, error) {
+	var hostdata model.HostDataBE
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		FindOne(context.TODO(), bson.M{"_id": id}).Decode(&hostdata)
+	if err == mongo.ErrNoDocuments {
+		return nil, utils.ErrHostNotFound
+	}
+
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	if hostdata.HistoryDelta == nil {
+		return &hostdata, nil
+	}
+
+	base, err := md.findFullHostData(hostdata.HistoryDelta.BaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	full, err := hostdata.HistoryDelta.Apply(*base)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't rebuild hostdata %s: %w", id.Hex(), err)
+	}
+
+	return full, nil
+}
@@ -29,6 +29,10 @@ import (
 
 // GetOracleDatabaseChartByVersion return the chart data about oracle database version
 func (md *MongoDatabase) GetOracleDatabaseChartByVersion(location string, environment string, olderThan time.Time) ([]dto.ChartBubble, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out = make([]dto.ChartBubble, 0)
 	//Find the matching hostdata
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
@@ -63,6 +67,10 @@ func (md *MongoDatabase) GetOracleDatabaseChartByVersion(location string, enviro
 
 // GetOracleDatabaseChartByWork return the chart data about the work of all database
 func (md *MongoDatabase) GetOracleDatabaseChartByWork(location string, environment string, olderThan time.Time) ([]dto.ChartBubble, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out []dto.ChartBubble = make([]dto.ChartBubble, 0)
 	//Find the matching hostdata
 	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Aggregate(
@@ -29,6 +29,10 @@ import (
 
 // GetTechnologyCount return the number of occurence per technology
 func (md *MongoDatabase) GetTechnologyCount(location string, environment string, olderThan time.Time) (map[string]float64, error) {
+	if err := md.rebuildHostDataHistory(olderThan); err != nil {
+		return nil, err
+	}
+
 	var out map[string]float64
 	//Create the operating system technology detector
 	var technologyDetector bson.M = bson.M{}
@@ -0,0 +1,35 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package dto
+
+import (
+	"time"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// CapacityForecastChart contains the used space of a tablespace, filesystem or database over time and its projection
+type CapacityForecastChart struct {
+	model.CapacityForecast
+	// Projection contains the used space projected by the growth trend, from the last sample
+	// to the exhaustion or to the horizon
+	Projection []CapacityForecastPoint `json:"projection"`
+}
+
+// CapacityForecastPoint contains the projected used space at a date
+type CapacityForecastPoint struct {
+	Date time.Time `json:"date"`
+	Used float64   `json:"used"`
+}
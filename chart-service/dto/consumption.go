@@ -0,0 +1,33 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package dto
+
+import (
+	"time"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// ConsumptionChart contains the CPU and IO consumption of a host or of a database in a range and its rollups
+type ConsumptionChart struct {
+	Hostname     string `json:"hostname"`
+	DatabaseName string `json:"databaseName,omitempty"`
+	// Resolution contains the resolution of the metrics, RAW or DAILY
+	Resolution string                    `json:"resolution"`
+	From       time.Time                 `json:"from"`
+	To         time.Time                 `json:"to"`
+	Metrics    []model.ConsumptionMetric `json:"metrics"`
+	Rollups    model.ConsumptionRollups  `json:"rollups"`
+}
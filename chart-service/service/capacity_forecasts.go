@@ -0,0 +1,66 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"math"
+
+	"github.com/ercole-io/ercole/v2/chart-service/dto"
+)
+
+// GetCapacityForecastChart return the used space of the tablespaces, filesystems and databases of the host over time
+// and its projection, until the exhaustion or for horizonDays
+func (as *ChartService) GetCapacityForecastChart(hostname, kind, databaseName string, horizonDays int) ([]dto.CapacityForecastChart, error) {
+	forecasts, err := as.Database.GetCapacityForecasts(hostname, kind, databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	horizon := as.TimeNow().AddDate(0, 0, horizonDays)
+	charts := make([]dto.CapacityForecastChart, 0, len(forecasts))
+
+	for _, forecast := range forecasts {
+		chart := dto.CapacityForecastChart{
+			CapacityForecast: forecast,
+			Projection:       make([]dto.CapacityForecastPoint, 0, 2),
+		}
+
+		if len(forecast.Samples) > 0 {
+			last := forecast.Samples[len(forecast.Samples)-1]
+
+			end := horizon
+			if forecast.ExhaustionDate != nil && forecast.ExhaustionDate.Before(end) {
+				end = *forecast.ExhaustionDate
+			}
+
+			if end.After(last.Date) {
+				days := end.Sub(last.Date).Hours() / 24
+				used := math.Max(0, last.Used+forecast.GrowthPerDay*days)
+
+				if forecast.Capacity > 0 {
+					used = math.Min(used, forecast.Capacity)
+				}
+
+				chart.Projection = append(chart.Projection,
+					dto.CapacityForecastPoint{Date: last.Date, Used: last.Used},
+					dto.CapacityForecastPoint{Date: end, Used: used})
+			}
+		}
+
+		charts = append(charts, chart)
+	}
+
+	return charts, nil
+}
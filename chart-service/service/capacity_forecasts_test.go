@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/chart-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetCapacityForecastChart_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := ChartService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2020-12-10T00:00:00Z")),
+	}
+
+	exhaustion := utils.P("2021-01-26T00:00:00Z")
+	daysToFull := 47.0
+	samples := []model.CapacitySample{
+		{Date: utils.P("2020-12-01T00:00:00Z"), Used: 440, Capacity: 1000},
+		{Date: utils.P("2020-12-09T00:00:00Z"), Used: 520, Capacity: 1000},
+	}
+	flat := []model.CapacitySample{
+		{Date: utils.P("2020-12-01T00:00:00Z"), Used: 100, Capacity: 1000},
+		{Date: utils.P("2020-12-09T00:00:00Z"), Used: 100, Capacity: 1000},
+	}
+
+	forecasts := []model.CapacityForecast{
+		{
+			CapacityItem:   model.CapacityItem{Hostname: "foobar", Kind: model.CapacityForecastKindFilesystem, Name: "/"},
+			Used:           520,
+			Capacity:       1000,
+			GrowthPerDay:   10,
+			DaysToFull:     &daysToFull,
+			ExhaustionDate: &exhaustion,
+			Samples:        samples,
+		},
+		{
+			CapacityItem: model.CapacityItem{Hostname: "foobar", Kind: model.CapacityForecastKindFilesystem, Name: "/data"},
+			Used:         100,
+			Capacity:     1000,
+			Samples:      flat,
+		},
+	}
+
+	db.EXPECT().GetCapacityForecasts("foobar", model.CapacityForecastKindFilesystem, "").
+		Return(forecasts, nil)
+
+	res, err := as.GetCapacityForecastChart("foobar", model.CapacityForecastKindFilesystem, "", 90)
+	require.NoError(t, err)
+
+	expected := []dto.CapacityForecastChart{
+		{
+			CapacityForecast: forecasts[0],
+			Projection: []dto.CapacityForecastPoint{
+				{Date: utils.P("2020-12-09T00:00:00Z"), Used: 520},
+				{Date: exhaustion, Used: 1000},
+			},
+		},
+		{
+			CapacityForecast: forecasts[1],
+			Projection: []dto.CapacityForecastPoint{
+				{Date: utils.P("2020-12-09T00:00:00Z"), Used: 100},
+				{Date: utils.P("2021-03-10T00:00:00Z"), Used: 100},
+			},
+		},
+	}
+	assert.Equal(t, expected, res)
+}
+
+func TestGetCapacityForecastChart_Fail(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := ChartService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2020-12-10T00:00:00Z")),
+	}
+
+	db.EXPECT().GetCapacityForecasts("foobar", "", "").
+		Return(nil, aerrMock)
+
+	res, err := as.GetCapacityForecastChart("foobar", "", "", 90)
+	require.Equal(t, aerrMock, err)
+	assert.Nil(t, res)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"time"
+
+	"github.com/ercole-io/ercole/v2/chart-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// GetConsumptionChart return the CPU and IO consumption of the host, or of its database if it isn't empty, in the range
+// and its rollups. Without a resolution, the samples are returned if the range is within their retention, otherwise the daily rollups
+func (as *ChartService) GetConsumptionChart(hostname, databaseName, resolution string, from, to time.Time) (*dto.ConsumptionChart, error) {
+	if resolution == "" {
+		resolution = model.ConsumptionResolutionRaw
+
+		rawRetention := as.TimeNow().AddDate(0, 0, -as.Config.DataService.ConsumptionMetrics.RawRetentionDays)
+		if from.Before(rawRetention) {
+			resolution = model.ConsumptionResolutionDaily
+		}
+	}
+
+	metrics, err := as.Database.GetConsumptionMetrics(hostname, databaseName, resolution, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ConsumptionChart{
+		Hostname:     hostname,
+		DatabaseName: databaseName,
+		Resolution:   resolution,
+		From:         from,
+		To:           to,
+		Metrics:      metrics,
+		Rollups:      model.NewConsumptionRollups(metrics),
+	}, nil
+}
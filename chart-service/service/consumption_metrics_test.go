@@ -0,0 +1,99 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestGetConsumptionChart_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := ChartService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				ConsumptionMetrics: config.ConsumptionMetrics{RawRetentionDays: 90},
+			},
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2022-05-10T00:00:00Z")),
+	}
+
+	cpu1, cpu2, cpuMax := 1.0, 3.0, 4.0
+	metrics := []model.ConsumptionMetric{
+		{Timestamp: utils.P("2022-05-01T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "foobar", DatabaseName: "ERCOLE"}, CpuAvg: &cpu1, CpuMax: &cpuMax},
+		{Timestamp: utils.P("2022-05-02T00:00:00Z"), Meta: model.ConsumptionMetricMeta{Hostname: "foobar", DatabaseName: "ERCOLE"}, CpuAvg: &cpu2},
+	}
+
+	t.Run("Raw within retention", func(t *testing.T) {
+		db.EXPECT().GetConsumptionMetrics("foobar", "ERCOLE", model.ConsumptionResolutionRaw, utils.P("2022-04-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z")).
+			Return(metrics, nil)
+
+		chart, err := as.GetConsumptionChart("foobar", "ERCOLE", "", utils.P("2022-04-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "foobar", chart.Hostname)
+		assert.Equal(t, "ERCOLE", chart.DatabaseName)
+		assert.Equal(t, model.ConsumptionResolutionRaw, chart.Resolution)
+		assert.Equal(t, metrics, chart.Metrics)
+		assert.Equal(t, model.ConsumptionRollup{Samples: 2, Avg: 2, P95: 3, Max: 4}, chart.Rollups.Cpu)
+		assert.Equal(t, model.ConsumptionRollup{}, chart.Rollups.Iops)
+	})
+
+	t.Run("Daily beyond retention", func(t *testing.T) {
+		db.EXPECT().GetConsumptionMetrics("foobar", "", model.ConsumptionResolutionDaily, utils.P("2021-05-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z")).
+			Return([]model.ConsumptionMetric{}, nil)
+
+		chart, err := as.GetConsumptionChart("foobar", "", "", utils.P("2021-05-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z"))
+		require.NoError(t, err)
+
+		assert.Equal(t, model.ConsumptionResolutionDaily, chart.Resolution)
+	})
+
+	t.Run("Explicit resolution", func(t *testing.T) {
+		db.EXPECT().GetConsumptionMetrics("foobar", "", model.ConsumptionResolutionRaw, utils.P("2021-05-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z")).
+			Return([]model.ConsumptionMetric{}, nil)
+
+		chart, err := as.GetConsumptionChart("foobar", "", model.ConsumptionResolutionRaw, utils.P("2021-05-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z"))
+		require.NoError(t, err)
+
+		assert.Equal(t, model.ConsumptionResolutionRaw, chart.Resolution)
+	})
+}
+
+func TestGetConsumptionChart_DatabaseError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	as := ChartService{
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2022-05-10T00:00:00Z")),
+	}
+
+	db.EXPECT().GetConsumptionMetrics("foobar", "", model.ConsumptionResolutionDaily, gomock.Any(), gomock.Any()).
+		Return(nil, aerrMock)
+
+	_, err := as.GetConsumptionChart("foobar", "", "", utils.P("2022-04-10T00:00:00Z"), utils.P("2022-05-10T00:00:00Z"))
+	require.Equal(t, aerrMock, err)
+}
@@ -46,6 +46,14 @@ type ChartServiceInterface interface {
 	GetTechnologyTypesChart(location string, environment string, olderThan time.Time) (dto.TechnologyTypesChart, error)
 
 	GetHostCores(location string, environment string, olderThan time.Time, newerThan time.Time) ([]dto.HostCores, error)
+
+	// GetCapacityForecastChart return the used space of the tablespaces, filesystems and databases of the host over time
+	// and its projection
+	GetCapacityForecastChart(hostname, kind, databaseName string, horizonDays int) ([]dto.CapacityForecastChart, error)
+
+	// GetConsumptionChart return the CPU and IO consumption of the host, or of its database if it isn't empty, in the range
+	// and its rollups
+	GetConsumptionChart(hostname, databaseName, resolution string, from, to time.Time) (*dto.ConsumptionChart, error)
 }
 
 type ChartService struct {
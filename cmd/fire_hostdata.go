@@ -16,18 +16,29 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ercole-io/ercole/v2/data-service/controller"
+	"github.com/ercole-io/ercole/v2/data-service/dto"
 )
 
 // fireHostDataCmd represents the fire-hostdata command
 var fireHostDataCmd = &cobra.Command{
 	Use:   "fire-hostdata",
 	Short: "Fire hostdata",
-	Long:  `Fire hostdata from the stdin or from the files in the args`,
+	Long: `Fire hostdata from the stdin or from the files in the args.
+The hostdata files (*.json) in a directory are sent together in a single compressed batch`,
 	Run: func(cmd *cobra.Command, args []string) {
 		//Load the data
 		if len(args) == 0 {
@@ -39,7 +50,9 @@ var fireHostDataCmd = &cobra.Command{
 			fireHostdata("stdin", raw)
 		} else {
 			for _, arg := range args {
-				if raw, err := os.ReadFile(arg); err != nil {
+				if info, err := os.Stat(arg); err == nil && info.IsDir() {
+					fireHostdataDirectory(arg)
+				} else if raw, err := os.ReadFile(arg); err != nil {
 					fmt.Fprintf(os.Stderr, "Failed to read the file %s: %v\n", arg, err)
 					os.Exit(1)
 				} else {
@@ -59,3 +72,125 @@ func init() {
 func fireHostdata(filename string, content []byte) {
 	importDataRequest(filename, content, "/hosts")
 }
+
+// fireHostdataDirectory send the hostdata files in the directory, and its subdirectories, as a gzipped tar
+func fireHostdataDirectory(dir string) {
+	content, count, err := tarHostdataDirectory(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read the directory %s: %v\n", dir, err)
+		os.Exit(1)
+	}
+
+	if count == 0 {
+		fmt.Fprintf(os.Stderr, "No hostdata files in the directory %s\n", dir)
+		return
+	}
+
+	req, err := newDataServiceRequest("/hosts/batch", content)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create request: %s", err)
+		os.Exit(1)
+	}
+
+	req.Header.Set("Content-Type", controller.ContentTypeTar)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := newDataServiceClient().Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send data from %s: %v\n", dir, err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		fmt.Fprintf(os.Stderr, "Directory: %s Status: %d Cause: %s\n", dir, resp.StatusCode, string(out))
+		os.Exit(1)
+	}
+
+	var result dto.HostDataBatchResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode the response: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, item := range result.Items {
+		if item.Error != "" {
+			fmt.Fprintf(os.Stderr, "File: %s Status: %d Cause: %s\n", filepath.Join(dir, item.Item), item.Status, item.Error)
+		} else if verbose {
+			fmt.Printf("File: %s Status: %d\n", filepath.Join(dir, item.Item), item.Status)
+		}
+	}
+
+	if result.Rejected > 0 {
+		fmt.Fprintf(os.Stderr, "Directory: %s Accepted: %d Rejected: %d\n", dir, result.Accepted, result.Rejected)
+		os.Exit(1)
+	}
+
+	if verbose {
+		fmt.Printf("Directory: %s Accepted: %d\n", dir, result.Accepted)
+	}
+}
+
+func tarHostdataDirectory(dir string) ([]byte, int, error) {
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	count := 0
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name: filepath.ToSlash(name),
+			Mode: 0644,
+			Size: int64(len(raw)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(raw); err != nil {
+			return err
+		}
+
+		count++
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := gw.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	return buf.Bytes(), count, nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+)
+
+var reprocessRequest dto.HostDataReprocessRequest
+var reprocessOutputJSON bool
+
+// reprocessCmd represents the reprocess command
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess [hostname...]",
+	Short: "Reprocess the current hostdata",
+	Long: `Recompute licenses, secondary databases and clusters of the current hostdata of the hosts in the args,
+or of all hosts selected by the filters, with the current license types and configuration`,
+	Run: func(cmd *cobra.Command, args []string) {
+		reprocessRequest.Hostnames = args
+
+		content, err := json.Marshal(reprocessRequest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+
+		report := reprocessHostData(content)
+
+		if reprocessOutputJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+
+			if err := enc.Encode(report); err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				os.Exit(1)
+			}
+
+			return
+		}
+
+		printReprocessReport(report)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reprocessCmd)
+	reprocessCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable the verbosity")
+	reprocessCmd.Flags().BoolVarP(&insecure, "insecure", "i", false, "Allow insecure server connections when using SSL")
+	reprocessCmd.Flags().StringVarP(&reprocessRequest.Location, "location", "l", "", "Reprocess only the hosts in the location")
+	reprocessCmd.Flags().StringVarP(&reprocessRequest.Environment, "environment", "e", "", "Reprocess only the hosts in the environment")
+	reprocessCmd.Flags().StringVarP(&reprocessRequest.Technology, "technology", "t", "",
+		"Reprocess only the hosts with the technology (Oracle/Database, Microsoft/SQLServer or Oracle/MySQL)")
+	reprocessCmd.Flags().BoolVarP(&reprocessRequest.DryRun, "dry-run", "n", false, "Show the differences without saving them")
+	reprocessCmd.Flags().BoolVar(&reprocessOutputJSON, "json", false, "Print the report as JSON")
+}
+
+func reprocessHostData(content []byte) dto.HostDataReprocessReport {
+	req, err := newDataServiceAdminRequest("/hosts/reprocess", content)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create request: %s", err)
+		os.Exit(1)
+	}
+
+	resp, err := newDataServiceClient().Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send the reprocess request: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		fmt.Fprintf(os.Stderr, "Status: %d Cause: %s\n", resp.StatusCode, string(out))
+		os.Exit(1)
+	}
+
+	var report dto.HostDataReprocessReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode the report: %v\n", err)
+		os.Exit(1)
+	}
+
+	return report
+}
+
+func printReprocessReport(report dto.HostDataReprocessReport) {
+	for _, host := range report.Hosts {
+		switch {
+		case host.Error != "":
+			fmt.Printf("%s: error: %s\n", host.Hostname, host.Error)
+		case host.Changed:
+			fmt.Printf("%s: %d changes\n", host.Hostname, len(host.Diff))
+		case verbose:
+			fmt.Printf("%s: unchanged\n", host.Hostname)
+		}
+
+		for _, op := range host.Diff {
+			if op.Value == nil {
+				fmt.Printf("\t%s %s\n", op.Op, op.Path)
+				continue
+			}
+
+			value, _ := json.Marshal(op.Value)
+			fmt.Printf("\t%s %s %s\n", op.Op, op.Path, value)
+		}
+	}
+
+	action := "changed"
+	if report.DryRun {
+		action = "would be changed (dry run)"
+	}
+
+	fmt.Printf("Processed %d hosts, %d %s\n", report.Processed, report.Changed, action)
+}
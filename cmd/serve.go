@@ -41,6 +41,8 @@ import (
 	alertservice_controller "github.com/ercole-io/ercole/v2/alert-service/controller"
 	alertservice_database "github.com/ercole-io/ercole/v2/alert-service/database"
 	alertservice_emailer "github.com/ercole-io/ercole/v2/alert-service/emailer"
+	alertservice_job "github.com/ercole-io/ercole/v2/alert-service/job"
+	alertservice_notifier "github.com/ercole-io/ercole/v2/alert-service/notifier"
 	alertservice_service "github.com/ercole-io/ercole/v2/alert-service/service"
 
 	apiservice_auth "github.com/ercole-io/ercole/v2/api-service/auth"
@@ -185,10 +187,15 @@ func serveDataService(config config.Configuration, wg *sync.WaitGroup) {
 		Log:            log,
 	}
 
+	if config.DataService.IngestionQueue.Enabled {
+		service.StartIngestionWorkers()
+	}
+
 	job := &dataservice_job.Job{
 		Config:        config,
 		ServerVersion: config.Version,
 		Database:      db,
+		Service:       service,
 		TimeNow:       time.Now,
 		Log:           log,
 	}
@@ -236,14 +243,24 @@ func serveAlertService(config config.Configuration, wg *sync.WaitGroup) {
 	}
 
 	service := &alertservice_service.AlertService{
+		Config:    config,
+		Database:  db,
+		TimeNow:   time.Now,
+		Log:       log,
+		Emailer:   emailer,
+		Notifiers: alertservice_notifier.BuildNotifiers(config.AlertService.Notifiers, log),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	service.Init(ctx, wg)
+
+	job := &alertservice_job.Job{
 		Config:   config,
 		Database: db,
+		Emailer:  emailer,
 		TimeNow:  time.Now,
 		Log:      log,
-		Emailer:  emailer,
 	}
-	ctx, cancel := context.WithCancel(context.Background())
-	service.Init(ctx, wg)
+	job.Init()
 
 	ctrl := &alertservice_controller.AlertQueueController{
 		Config:  config,
@@ -11,26 +11,14 @@ import (
 )
 
 func importDataRequest(filename string, content []byte, path string) {
-	client := &http.Client{}
-	tr := http.DefaultTransport.(*http.Transport).Clone()
-
-	if insecure {
-		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
-
-	client.Transport = tr
+	client := newDataServiceClient()
 
-	req, err := http.NewRequest("POST", ercoleConfig.DataService.RemoteEndpoint+path, bytes.NewReader(content))
+	req, err := newDataServiceRequest(path, content)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create request: %s", err)
 		os.Exit(1)
 	}
 
-	src := ercoleConfig.DataService.AgentUsername + ":" + ercoleConfig.DataService.AgentPassword
-	bearer := "Basic " + base64.StdEncoding.EncodeToString([]byte(src))
-	req.Header.Add("Authorization", bearer)
-	req.Header.Add("Content-Type", "application/json")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to send data from %s: %v\n", filename, err)
@@ -52,3 +40,41 @@ func importDataRequest(filename string, content []byte, path string) {
 		fmt.Printf("File: %s Status: %d\n", filename, resp.StatusCode)
 	}
 }
+
+func newDataServiceClient() *http.Client {
+	client := &http.Client{}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+
+	if insecure {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	client.Transport = tr
+
+	return client
+}
+
+func newDataServiceRequest(path string, content []byte) (*http.Request, error) {
+	return newDataServiceRequestWithCredentials(path, content,
+		ercoleConfig.DataService.AgentUsername, ercoleConfig.DataService.AgentPassword)
+}
+
+// newDataServiceAdminRequest return a request to the admin endpoints of the data-service
+func newDataServiceAdminRequest(path string, content []byte) (*http.Request, error) {
+	return newDataServiceRequestWithCredentials(path, content,
+		ercoleConfig.DataService.AdminUsername, ercoleConfig.DataService.AdminPassword)
+}
+
+func newDataServiceRequestWithCredentials(path string, content []byte, username, password string) (*http.Request, error) {
+	req, err := http.NewRequest("POST", ercoleConfig.DataService.RemoteEndpoint+path, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	src := username + ":" + password
+	bearer := "Basic " + base64.StdEncoding.EncodeToString([]byte(src))
+	req.Header.Add("Authorization", bearer)
+	req.Header.Add("Content-Type", "application/json")
+
+	return req, nil
+}
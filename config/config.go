@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/OpenPeeDeeP/xdg"
 	"github.com/goraz/onion"
@@ -70,6 +71,10 @@ type DataService struct {
 	AgentUsername string
 	// AgentPassword contains the password of the agent
 	AgentPassword string
+	// AdminUsername contains the username of the administrative endpoints, disabled if empty
+	AdminUsername string
+	// AdminPassword contains the password of the administrative endpoints
+	AdminPassword string
 	// CurrentHostCleaningJob contains the parameters of the current host cleaning
 	CurrentHostCleaningJob CurrentHostCleaningJob
 	// ArchivedCleaningJob contains the parameters of the archived host cleaning
@@ -81,6 +86,22 @@ type DataService struct {
 	// LicenseTypeMetricsByEnvironment custom priority order of metric of licenseType when importing HostData
 	// per environment
 	LicenseTypeMetricsByEnvironment map[string][]string
+	// HostDriftDetection contains the rules of the alerts raised on host configuration changes
+	HostDriftDetection HostDriftDetection
+	// IngestionQueue contains the parameters of the asynchronous hostdata ingestion
+	IngestionQueue IngestionQueue
+	// HostDataHistory contains the parameters of the storage of the archived hostdata
+	HostDataHistory HostDataHistory
+	// CmdbSyncJob contains the parameters of the synchronisation of the hosts with the CMDBs
+	CmdbSyncJob CmdbSyncJob
+	// CapacityForecastJob contains the parameters of the forecast of the exhaustion of tablespaces, filesystems and databases
+	CapacityForecastJob CapacityForecastJob
+	// BackupComplianceJob contains the parameters of the evaluation of the Oracle backups against the backup policies
+	BackupComplianceJob BackupComplianceJob
+	// PatchAdvisorJob contains the parameters of the alerts of the Oracle databases behind the patch catalogue
+	PatchAdvisorJob PatchAdvisorJob
+	// ConsumptionMetrics contains the parameters of the storage of the CPU and IO consumptions of hosts and databases
+	ConsumptionMetrics ConsumptionMetrics
 }
 
 // AlertService contains configuration about the alert service
@@ -105,6 +126,12 @@ type AlertService struct {
 	QueueBufferSize int
 	// Emailer contains the settings about the emailer
 	Emailer Emailer
+	// Notifiers contains the settings about the other alert notification channels
+	Notifiers Notifiers
+	// Grouping contains the settings about the grouping and the deduplication of the alert notifications
+	Grouping AlertGrouping
+	// DigestJob contains the settings about the digest of the new alerts
+	DigestJob AlertDigestJob
 }
 
 // APIService contains configuration about the api service
@@ -127,6 +154,8 @@ type APIService struct {
 	OperatingSystemAggregationRules []AggregationRule
 	// DefaultDatabaseTags contains the default list of database tags
 	DefaultDatabaseTags []string
+	// TrustedProxies contains the addresses, or the CIDRs, of the reverse proxies whose X-Forwarded-For header is trusted
+	TrustedProxies []string
 }
 
 // RepoService contains configuration about the repo service
@@ -178,12 +207,257 @@ type Mongodb struct {
 	Migrate bool
 }
 
+// IngestionQueue contains the parameters of the queue of the received hostdata
+type IngestionQueue struct {
+	// Enabled contains true if the hostdata are queued and processed asynchronously, otherwise
+	// they are processed inside the http request
+	Enabled bool
+	// Workers contains the number of hostdata processed concurrently
+	Workers int
+	// MaxAttempts contains the number of attempts before moving an ingestion to the dead letter
+	MaxAttempts int
+	// RetryDelaySeconds contains the delay before the first retry, doubled at each attempt
+	RetryDelaySeconds int
+	// LeaseSeconds contains the time after that an ingestion still processing is considered abandoned
+	LeaseSeconds int
+	// PollIntervalSeconds contains the interval between two checks of an empty queue
+	PollIntervalSeconds int
+	// IdempotencyWindowMinutes contains for how long a processed hostdata is remembered to discard its duplicates
+	IdempotencyWindowMinutes int
+}
+
+// HostDataHistory contains the parameters of the storage of the archived hostdata
+type HostDataHistory struct {
+	// Mode contains "full" to keep every archived hostdata as it is, or "delta" to keep periodically
+	// a full snapshot and, between them, the differences from the previous hostdata
+	Mode string
+	// SnapshotIntervalHours contains the hours between two full snapshots in delta mode
+	SnapshotIntervalHours int
+}
+
+// HostDriftDetection contains the rules used to compare a new hostdata with the previous one
+type HostDriftDetection struct {
+	// Enabled contains true if the drift detection is enabled, otherwise false
+	Enabled bool
+	// Rules contains the rule of each drift alert code. Codes without a rule don't raise alerts
+	Rules map[string]HostDriftRule
+}
+
+// HostDriftRule contains the parameters of the alert raised for a kind of drift
+type HostDriftRule struct {
+	// Enabled contains true if the drift should raise an alert, otherwise false
+	Enabled bool
+	// Severity contains the severity of the raised alert
+	Severity string
+}
+
 // FreshnessCheckJob contains parameters for the freshness check
 type FreshnessCheckJob struct {
 	// Crontab contains the crontab string used to schedule the freshness check
 	Crontab string
 	// RunAtStartup contains true if the job should run when the service start, otherwise false
 	RunAtStartup bool
+	// Policies contains the freshness policies: the first one matching the host is applied,
+	// a host without policies raises a CRITICAL alert after its period
+	Policies []FreshnessPolicy
+	// MaintenanceWindows contains the periods when the hosts don't raise or escalate NO_DATA alerts
+	MaintenanceWindows []MaintenanceWindow
+}
+
+// FreshnessPolicy contains the thresholds of the NO_DATA alerts of the hosts in some environments, locations or with some tags.
+// A policy without environments, locations and tags matches every host
+type FreshnessPolicy struct {
+	// Name contains the name of the policy, reported in the alerts
+	Name string
+	// Environments contains the environments of the hosts
+	Environments []string
+	// Locations contains the locations of the hosts
+	Locations []string
+	// Tags contains the tags of the hosts, any of them matches
+	Tags []string
+	// WarningAfterHours contains the hours without data before raising a WARNING alert, 0 to disable
+	WarningAfterHours int
+	// CriticalAfterHours contains the hours without data before raising a CRITICAL alert, 0 to disable
+	CriticalAfterHours int
+	// DismissSuggestionAfterDays contains the days without data before suggesting to dismiss the host, 0 to disable
+	DismissSuggestionAfterDays int
+}
+
+// MaintenanceWindow contains a period when the selected hosts don't raise or escalate NO_DATA alerts.
+// A window without hostnames, environments, locations and tags matches every host
+type MaintenanceWindow struct {
+	// Hostnames contains the hostnames of the hosts
+	Hostnames []string
+	// Environments contains the environments of the hosts
+	Environments []string
+	// Locations contains the locations of the hosts
+	Locations []string
+	// Tags contains the tags of the hosts, any of them matches
+	Tags []string
+	// Start contains the start of the window
+	Start time.Time
+	// End contains the end of the window
+	End time.Time
+}
+
+// CapacityForecastJob contains parameters for the forecast of the exhaustion of tablespaces, filesystems and databases
+type CapacityForecastJob struct {
+	// Crontab contains the crontab string used to schedule the forecast
+	Crontab string
+	// RunAtStartup contains true if the job should run when the service start, otherwise false
+	RunAtStartup bool
+	// HistoryDays contains the days of hostdata used to fit the growth trends, 90 by default
+	HistoryDays int
+	// MinSamples contains the minimum number of hostdata needed to forecast an item, 3 by default
+	MinSamples int
+	// WarningHorizonDays contains the days within which a projected exhaustion raises a WARNING alert, 0 to disable
+	WarningHorizonDays int
+	// CriticalHorizonDays contains the days within which a projected exhaustion raises a CRITICAL alert, 0 to disable
+	CriticalHorizonDays int
+}
+
+// BackupComplianceJob contains parameters for the evaluation of the Oracle backups against the backup policies
+type BackupComplianceJob struct {
+	// Crontab contains the crontab string used to schedule the evaluation
+	Crontab string
+	// RunAtStartup contains true if the job should run when the service start, otherwise false
+	RunAtStartup bool
+	// Policies contains the backup policies: the first one matching the host is applied,
+	// the databases of a host without policies aren't evaluated
+	Policies []BackupPolicy
+}
+
+// BackupPolicy contains the backups required to the Oracle databases of the hosts in some environments, locations or with some tags.
+// A policy without environments, locations and tags matches every host
+type BackupPolicy struct {
+	// Name contains the name of the policy, reported in the evaluations and in the alerts
+	Name string
+	// Environments contains the environments of the hosts
+	Environments []string
+	// Locations contains the locations of the hosts
+	Locations []string
+	// Tags contains the tags of the hosts, any of them matches
+	Tags []string
+	// Schedules contains the backups that must be scheduled
+	Schedules []BackupSchedule
+	// MinRetentionDays contains the minimum recovery window of the backups, 0 to disable
+	MinRetentionDays int
+	// RequireArchivelogBackups contains true if the archivelogs must be backed up, otherwise false
+	RequireArchivelogBackups bool
+	// RequireArchivelogMode contains true if the databases must be in archivelog mode, otherwise false
+	RequireArchivelogMode bool
+	// Severity contains the severity of the alerts raised on the violations of the policy, WARNING by default
+	Severity string
+}
+
+// BackupSchedule contains a backup that must be scheduled in some days of the week
+type BackupSchedule struct {
+	// BackupTypes contains the types of the backups that satisfy the schedule, e.g. Level0 and Level1, ignoring the case
+	BackupTypes []string
+	// MinDaysPerWeek contains the minimum number of days of the week with one of the backups, 7 for a daily backup
+	MinDaysPerWeek int
+}
+
+// PatchAdvisorJob contains parameters for the alerts of the Oracle databases behind the patch catalogue
+type PatchAdvisorJob struct {
+	// Crontab contains the crontab string used to schedule the check
+	Crontab string
+	// RunAtStartup contains true if the job should run when the service start, otherwise false
+	RunAtStartup bool
+	// WarningReleasesBehind contains the releases behind the latest one that raise a WARNING alert, 0 to disable
+	WarningReleasesBehind int
+	// CriticalReleasesBehind contains the releases behind the latest one that raise a CRITICAL alert, 0 to disable
+	CriticalReleasesBehind int
+	// AlertUnsupportedVersions contains true if the versions out of extended support raise a CRITICAL alert, otherwise false
+	AlertUnsupportedVersions bool
+}
+
+// ConsumptionMetrics contains parameters for the storage of the CPU and IO consumptions of hosts and databases,
+// extracted from the hostdata in time-series collections
+type ConsumptionMetrics struct {
+	// RawRetentionDays contains the days the samples received from the agents are kept, 90 by default
+	RawRetentionDays int
+	// DailyRetentionDays contains the days the daily rollups of the samples are kept, 730 by default
+	DailyRetentionDays int
+	// WorkloadDays contains the days of samples averaged in the workload of the Oracle databases, 30 by default
+	WorkloadDays int
+	// DownsamplingJob contains the parameters of the job that rolls up the samples by day and applies the retentions
+	DownsamplingJob ConsumptionDownsamplingJob
+}
+
+// ConsumptionDownsamplingJob contains parameters for the daily rollup of the consumption samples
+type ConsumptionDownsamplingJob struct {
+	// Crontab contains the crontab string used to schedule the rollup
+	Crontab string
+	// RunAtStartup contains true if the job should run when the service start, otherwise false
+	RunAtStartup bool
+}
+
+// CmdbSyncJob contains parameters for the synchronisation of the hosts with the CMDBs
+type CmdbSyncJob struct {
+	// Crontab contains the crontab string used to schedule the synchronisation
+	Crontab string
+	// RunAtStartup contains true if the job should run when the service start, otherwise false
+	RunAtStartup bool
+	// Sources contains the CMDBs from which the records of the hosts are read
+	Sources []CmdbSource
+}
+
+// Types of CMDB source
+const (
+	// CmdbSourceTypeFile reads the CSV and JSON files dropped in a directory
+	CmdbSourceTypeFile = "file"
+	// CmdbSourceTypeRest reads the records from a generic REST endpoint
+	CmdbSourceTypeRest = "rest"
+	// CmdbSourceTypeServiceNow reads the records from a ServiceNow compatible table API
+	CmdbSourceTypeServiceNow = "servicenow"
+)
+
+// CmdbSource contains the parameters of a CMDB
+type CmdbSource struct {
+	// Name contains the name of the CMDB, reported in the host metadata and in the alerts
+	Name string
+	// Type contains the type of the source: file, rest or servicenow
+	Type string
+	// Directory contains the directory where the CSV and JSON files are dropped, for the file sources.
+	// The files are moved to its processed subdirectory after the synchronisation
+	Directory string
+	// URL contains the endpoint of the rest sources, or the address of the ServiceNow instance
+	URL string
+	// Table contains the ServiceNow table of the hosts, cmdb_ci_server by default
+	Table string
+	// Query contains the ServiceNow encoded query used to filter the records
+	Query string
+	// Username contains the username for the basic authentication
+	Username string
+	// Password contains the password for the basic authentication
+	Password string
+	// Token contains the bearer token, used instead of the basic authentication
+	Token string
+	// TimeoutSeconds contains the timeout of the requests to the CMDB
+	TimeoutSeconds int
+	// RecordsPath contains the JSONPath of the array of the records in the responses and in the JSON files
+	RecordsPath string
+	// Fields contains the JSONPaths of the fields in the records, or the columns of the CSV files
+	Fields CmdbFields
+	// DecommissionedValues contains the values of the Decommissioned field of the decommissioned hosts, true by default
+	DecommissionedValues []string
+	// OverrideEnvironment contains true if the environment from the CMDB replaces the one sent by the agent
+	OverrideEnvironment bool
+	// OverrideLocation contains true if the location from the CMDB replaces the one sent by the agent
+	OverrideLocation bool
+	// AlertMissingHosts contains true if the hosts missing in Ercole or in the CMDB raise alerts
+	AlertMissingHosts bool
+}
+
+// CmdbFields contains where the fields of the host are found in the records of a CMDB. Empty fields aren't read
+type CmdbFields struct {
+	Hostname        string
+	Environment     string
+	Location        string
+	Owner           string
+	BusinessService string
+	Decommissioned  string
 }
 
 // CurrentHostCleaningJob contains parameters for the current host cleaning
@@ -293,16 +567,125 @@ type Emailer struct {
 	DisableSSLCertificateValidation bool
 }
 
+// AlertGrouping contains the settings about the grouping and the deduplication of the alert notifications
+type AlertGrouping struct {
+	// Enabled contains true if the alerts with the same code and host are notified together, otherwise false
+	Enabled bool
+	// Window contains the number of seconds in which the alerts with the same code and host are grouped
+	Window int
+	// SuppressUnacknowledgedRepeats contains true if the notification of an alert is suppressed
+	// when an identical alert is still unacknowledged, otherwise false
+	SuppressUnacknowledgedRepeats bool
+}
+
+// AlertDigestJob contains the settings about the digest of the new alerts
+type AlertDigestJob struct {
+	// Enabled contains true if the digest is sent, otherwise false
+	Enabled bool
+	// Crontab contains the crontab string used to schedule the digest
+	Crontab string
+	// HourThreshold contains the number of hours covered by the digest, 24 by default
+	HourThreshold int
+	// To contains the destinations of the digest
+	To []string
+}
+
+// Notifiers contains the settings of the alert notification channels other than the emailer
+type Notifiers struct {
+	// Webhooks contains the generic HTTP webhooks
+	Webhooks []WebhookNotifier
+	// Chats contains the chat webhooks (Slack or Teams incoming webhooks)
+	Chats []ChatNotifier
+	// Syslogs contains the RFC 5424 syslog receivers
+	Syslogs []SyslogNotifier
+}
+
+// NotifierRetry contains the retry policy of a notifier
+type NotifierRetry struct {
+	// MaxAttempts contains the maximum number of attempts, values lower than 1 mean a single attempt
+	MaxAttempts int
+	// InitialBackoff contains the number of milliseconds to wait before the first retry
+	InitialBackoff int
+	// MaxBackoff contains the maximum number of milliseconds to wait between two attempts
+	MaxBackoff int
+	// BackoffMultiplier contains the factor applied to the backoff after every failed attempt
+	BackoffMultiplier float64
+}
+
+// WebhookNotifier contains the settings of a generic HTTP webhook
+type WebhookNotifier struct {
+	// Name contains the name of the notifier
+	Name string
+	// Enabled contains true if the notifier is enabled, otherwise false
+	Enabled bool
+	// URL contains the url called for every alert
+	URL string
+	// Method contains the HTTP method, POST by default
+	Method string
+	// Headers contains the additional HTTP headers
+	Headers map[string]string
+	// BodyTemplate contains the text/template used to build the body from the alert.
+	// If it's empty the alert is sent as JSON
+	BodyTemplate string
+	// Timeout contains the number of seconds before the request is aborted
+	Timeout int
+	// DisableSSLCertificateValidation contains true if disable the certification validation, otherwise false
+	DisableSSLCertificateValidation bool
+	// Retry contains the retry policy
+	Retry NotifierRetry
+}
+
+// ChatNotifier contains the settings of a chat incoming webhook
+type ChatNotifier struct {
+	// Name contains the name of the notifier
+	Name string
+	// Enabled contains true if the notifier is enabled, otherwise false
+	Enabled bool
+	// URL contains the url of the incoming webhook
+	URL string
+	// Format contains the format of the message. Supported formats are:
+	//	- slack
+	//	- teams
+	Format string
+	// Timeout contains the number of seconds before the request is aborted
+	Timeout int
+	// Retry contains the retry policy
+	Retry NotifierRetry
+}
+
+// SyslogNotifier contains the settings of a RFC 5424 syslog receiver
+type SyslogNotifier struct {
+	// Name contains the name of the notifier
+	Name string
+	// Enabled contains true if the notifier is enabled, otherwise false
+	Enabled bool
+	// Network contains the network used to connect to the receiver (udp, tcp)
+	Network string
+	// Address contains the address of the receiver, like 127.0.0.1:514
+	Address string
+	// Facility contains the syslog facility code, from 0 (kern) to 23 (local7), 16 (local0) if not set
+	Facility *int
+	// AppName contains the APP-NAME field, ercole by default
+	AppName string
+	// Timeout contains the number of seconds before the connection is aborted
+	Timeout int
+	// Retry contains the retry policy
+	Retry NotifierRetry
+}
+
 // AuthenticationProviderConfig contains the settings used to authenticate the users
 type AuthenticationProviderConfig struct {
 	// Type contains the type of the source. Supported types are:
 	//	- basic
 	// 	- ldap
+	// 	- oidc
 	Types []string
 	// Service username (basic token)
 	Username string
 	// Service password (basic token)
 	Password string
+	// APIToken is the API token used by the ercole services to call the api-service instead of Username and Password
+	APIToken string
 	// PrivateKey is the filename of the key
 	PrivateKey string
 	// PublicKey is the filename of the key
@@ -315,6 +698,17 @@ type AuthenticationProviderConfig struct {
 	LDAPBindDN           string
 	LDAPBindPassword     string
 	LDAPUserFilter       string
+	// OIDCIssuer is the URL of the OpenID Connect issuer, used to discover its endpoints
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	// OIDCRedirectURL is the URL of the /oidc/callback endpoint, as registered in the identity provider
+	OIDCRedirectURL string
+	OIDCScopes      []string
+	// OIDCUsernameClaim is the claim of the ID token containing the username
+	OIDCUsernameClaim string
+	// OIDCGroupsClaim is the claim of the ID token, also nested (i.e. realm_access.roles), whose values are matched against the tags of the groups
+	OIDCGroupsClaim string
 }
 
 // ReadConfig read, parse and return a Configuration from the configuration file
@@ -409,6 +803,15 @@ func checkConfiguration(log logger.Logger, config *Configuration) error {
 	}
 
 	checkOracleDatabaseLicenseTypeMetrics(log, config)
+	checkHostDriftRules(log, config)
+	checkIngestionQueue(config)
+	checkHostDataHistory(log, config)
+	checkFreshnessCheckJob(log, config)
+	checkCmdbSyncJob(log, config)
+	checkCapacityForecastJob(log, config)
+	checkBackupComplianceJob(log, config)
+	checkPatchAdvisorJob(log, config)
+	checkConsumptionMetrics(log, config)
 
 	return nil
 }
@@ -432,3 +835,259 @@ metrics:
 			m, model.GetAllLicenseTypeMetrics())
 	}
 }
+
+func checkHostDriftRules(log logger.Logger, config *Configuration) {
+	rules := make(map[string]HostDriftRule, len(config.DataService.HostDriftDetection.Rules))
+
+codes:
+	for code, rule := range config.DataService.HostDriftDetection.Rules {
+		code = strings.ToUpper(code)
+
+		if !model.IsValidAlertSeverity(rule.Severity) {
+			log.Fatalf("Check configuration: Invalid severity %q of the host drift rule %q", rule.Severity, code)
+		}
+
+		rules[code] = rule
+
+		for _, validCode := range model.GetHostDriftAlertCodes() {
+			if code == validCode {
+				continue codes
+			}
+		}
+
+		log.Fatalf("Check configuration: Invalid host drift rule: %q\nValid values are: %q",
+			code, model.GetHostDriftAlertCodes())
+	}
+
+	config.DataService.HostDriftDetection.Rules = rules
+}
+
+func checkIngestionQueue(config *Configuration) {
+	queue := &config.DataService.IngestionQueue
+
+	if queue.Workers <= 0 {
+		queue.Workers = 1
+	}
+
+	if queue.MaxAttempts <= 0 {
+		queue.MaxAttempts = 1
+	}
+
+	if queue.LeaseSeconds <= 0 {
+		queue.LeaseSeconds = 600
+	}
+
+	if queue.PollIntervalSeconds <= 0 {
+		queue.PollIntervalSeconds = 5
+	}
+}
+
+func checkHostDataHistory(log logger.Logger, config *Configuration) {
+	history := &config.DataService.HostDataHistory
+
+	switch history.Mode {
+	case "":
+		history.Mode = model.HostDataHistoryModeFull
+	case model.HostDataHistoryModeFull, model.HostDataHistoryModeDelta:
+	default:
+		log.Fatalf("Check configuration: Invalid HostDataHistory mode: %q\nValid values are: %q",
+			history.Mode, []string{model.HostDataHistoryModeFull, model.HostDataHistoryModeDelta})
+	}
+
+	if history.SnapshotIntervalHours <= 0 {
+		history.SnapshotIntervalHours = model.DefaultHostDataSnapshotIntervalHours
+	}
+}
+
+func checkFreshnessCheckJob(log logger.Logger, config *Configuration) {
+	for _, policy := range config.DataService.FreshnessCheckJob.Policies {
+		if policy.WarningAfterHours < 0 || policy.CriticalAfterHours < 0 || policy.DismissSuggestionAfterDays < 0 {
+			log.Fatalf("Invalid freshness policy %q: thresholds can't be negative", policy.Name)
+		}
+
+		if policy.WarningAfterHours == 0 && policy.CriticalAfterHours == 0 {
+			log.Fatalf("Invalid freshness policy %q: WarningAfterHours or CriticalAfterHours is required", policy.Name)
+		}
+
+		if policy.WarningAfterHours > 0 && policy.CriticalAfterHours > 0 && policy.WarningAfterHours >= policy.CriticalAfterHours {
+			log.Fatalf("Invalid freshness policy %q: WarningAfterHours must be less than CriticalAfterHours", policy.Name)
+		}
+	}
+
+	for _, window := range config.DataService.FreshnessCheckJob.MaintenanceWindows {
+		if !window.End.After(window.Start) {
+			log.Fatalf("Invalid maintenance window from %s to %s: the end must be after the start", window.Start, window.End)
+		}
+	}
+}
+
+func checkCapacityForecastJob(log logger.Logger, config *Configuration) {
+	job := &config.DataService.CapacityForecastJob
+
+	if job.HistoryDays <= 0 {
+		job.HistoryDays = 90
+	}
+
+	if job.MinSamples < 2 {
+		job.MinSamples = 3
+	}
+
+	if job.WarningHorizonDays < 0 || job.CriticalHorizonDays < 0 {
+		log.Fatalf("Invalid CapacityForecastJob: the horizons can't be negative")
+	}
+
+	if job.WarningHorizonDays > 0 && job.CriticalHorizonDays > 0 && job.WarningHorizonDays <= job.CriticalHorizonDays {
+		log.Fatalf("Invalid CapacityForecastJob: WarningHorizonDays must be greater than CriticalHorizonDays")
+	}
+}
+
+func checkBackupComplianceJob(log logger.Logger, config *Configuration) {
+	policies := config.DataService.BackupComplianceJob.Policies
+
+	for i := range policies {
+		policy := &policies[i]
+
+		if policy.Severity == "" {
+			policy.Severity = model.AlertSeverityWarning
+		}
+
+		if !model.IsValidAlertSeverity(policy.Severity) {
+			log.Fatalf("Invalid backup policy %q: invalid severity %q", policy.Name, policy.Severity)
+		}
+
+		if policy.MinRetentionDays < 0 {
+			log.Fatalf("Invalid backup policy %q: MinRetentionDays can't be negative", policy.Name)
+		}
+
+		for _, schedule := range policy.Schedules {
+			if len(schedule.BackupTypes) == 0 {
+				log.Fatalf("Invalid backup policy %q: BackupTypes of the schedules is required", policy.Name)
+			}
+
+			if schedule.MinDaysPerWeek < 1 || schedule.MinDaysPerWeek > 7 {
+				log.Fatalf("Invalid backup policy %q: MinDaysPerWeek must be between 1 and 7", policy.Name)
+			}
+		}
+	}
+}
+
+func checkPatchAdvisorJob(log logger.Logger, config *Configuration) {
+	job := config.DataService.PatchAdvisorJob
+
+	if job.WarningReleasesBehind < 0 || job.CriticalReleasesBehind < 0 {
+		log.Fatalf("Invalid PatchAdvisorJob: the releases behind can't be negative")
+	}
+
+	if job.WarningReleasesBehind > 0 && job.CriticalReleasesBehind > 0 && job.WarningReleasesBehind >= job.CriticalReleasesBehind {
+		log.Fatalf("Invalid PatchAdvisorJob: WarningReleasesBehind must be less than CriticalReleasesBehind")
+	}
+}
+
+func checkConsumptionMetrics(log logger.Logger, config *Configuration) {
+	metrics := &config.DataService.ConsumptionMetrics
+
+	if metrics.RawRetentionDays <= 0 {
+		metrics.RawRetentionDays = 90
+	}
+
+	if metrics.DailyRetentionDays <= 0 {
+		metrics.DailyRetentionDays = 730
+	}
+
+	if metrics.WorkloadDays <= 0 {
+		metrics.WorkloadDays = 30
+	}
+
+	if metrics.DailyRetentionDays < metrics.RawRetentionDays {
+		log.Fatalf("Invalid ConsumptionMetrics: DailyRetentionDays can't be less than RawRetentionDays")
+	}
+
+	if metrics.WorkloadDays > metrics.RawRetentionDays {
+		log.Fatalf("Invalid ConsumptionMetrics: WorkloadDays can't be greater than RawRetentionDays")
+	}
+}
+
+func checkCmdbSyncJob(log logger.Logger, config *Configuration) {
+	names := make(map[string]bool)
+
+	for i := range config.DataService.CmdbSyncJob.Sources {
+		source := &config.DataService.CmdbSyncJob.Sources[i]
+
+		if source.Name == "" || names[source.Name] {
+			log.Fatalf("Invalid CMDB source %q: the name is required and must be unique", source.Name)
+		}
+
+		names[source.Name] = true
+
+		switch source.Type {
+		case CmdbSourceTypeFile:
+			if source.Directory == "" {
+				log.Fatalf("Invalid CMDB source %q: Directory is required", source.Name)
+			}
+
+			source.Fields.setDefaults(CmdbFields{
+				Hostname:        "hostname",
+				Environment:     "environment",
+				Location:        "location",
+				Owner:           "owner",
+				BusinessService: "businessService",
+				Decommissioned:  "decommissioned",
+			})
+		case CmdbSourceTypeRest:
+			if source.URL == "" {
+				log.Fatalf("Invalid CMDB source %q: URL is required", source.Name)
+			}
+
+			source.Fields.setDefaults(CmdbFields{
+				Hostname:        "$.hostname",
+				Environment:     "$.environment",
+				Location:        "$.location",
+				Owner:           "$.owner",
+				BusinessService: "$.businessService",
+				Decommissioned:  "$.decommissioned",
+			})
+		case CmdbSourceTypeServiceNow:
+			if source.URL == "" {
+				log.Fatalf("Invalid CMDB source %q: URL is required", source.Name)
+			}
+
+			if source.Table == "" {
+				source.Table = "cmdb_ci_server"
+			}
+
+			if source.RecordsPath == "" {
+				source.RecordsPath = "$.result"
+			}
+
+			if len(source.DecommissionedValues) == 0 {
+				source.DecommissionedValues = []string{"Retired"}
+			}
+
+			source.Fields.setDefaults(CmdbFields{
+				Hostname:       "$.name",
+				Environment:    "$.environment",
+				Location:       "$.location",
+				Owner:          "$.owned_by",
+				Decommissioned: "$.install_status",
+			})
+		default:
+			log.Fatalf("Invalid CMDB source %q: invalid type %q\nValid values are: %q", source.Name, source.Type,
+				[]string{CmdbSourceTypeFile, CmdbSourceTypeRest, CmdbSourceTypeServiceNow})
+		}
+
+		if source.RecordsPath == "" {
+			source.RecordsPath = "$"
+		}
+
+		if source.TimeoutSeconds <= 0 {
+			source.TimeoutSeconds = 60
+		}
+	}
+}
+
+// setDefaults set the fields to their default when none of them is set
+func (fields *CmdbFields) setDefaults(defaults CmdbFields) {
+	if *fields == (CmdbFields{}) {
+		*fields = defaults
+	}
+}
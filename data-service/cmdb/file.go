@@ -0,0 +1,128 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// processedDirectory is the subdirectory where the files are moved after the synchronisation
+const processedDirectory = "processed"
+
+// fileSource reads the records from the CSV and JSON files dropped in a directory
+type fileSource struct {
+	conf   config.CmdbSource
+	mapper *recordMapper
+	files  []string
+}
+
+func (source *fileSource) Fetch() ([]model.CmdbRecord, error) {
+	entries, err := os.ReadDir(source.conf.Directory)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't read CMDB directory %s: %s", source.conf.Directory, err)
+	}
+
+	source.files = make([]string, 0)
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.Type().IsRegular() && (ext == ".csv" || ext == ".json") {
+			source.files = append(source.files, entry.Name())
+		}
+	}
+
+	sort.Strings(source.files)
+
+	records := make([]model.CmdbRecord, 0)
+
+	for _, name := range source.files {
+		fileRecords, err := source.readFile(filepath.Join(source.conf.Directory, name))
+		if err != nil {
+			return nil, utils.NewErrorf("Can't read CMDB file %s: %s", name, err)
+		}
+
+		records = append(records, fileRecords...)
+	}
+
+	return records, nil
+}
+
+func (source *fileSource) readFile(path string) ([]model.CmdbRecord, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		var doc interface{}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+
+		return source.mapper.fromJSON(doc)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	firstLine := string(raw)
+	if i := strings.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return []model.CmdbRecord{}, nil
+	}
+
+	return source.mapper.fromCSV(rows[0], rows[1:]), nil
+}
+
+// Commit move the read files to the processed subdirectory, so they aren't read again
+func (source *fileSource) Commit() error {
+	processed := filepath.Join(source.conf.Directory, processedDirectory)
+
+	if err := os.MkdirAll(processed, 0o755); err != nil {
+		return utils.NewError(err, "Can't create the directory of the processed CMDB files")
+	}
+
+	for _, name := range source.files {
+		if err := os.Rename(filepath.Join(source.conf.Directory, name), filepath.Join(processed, name)); err != nil {
+			return utils.NewErrorf("Can't move the processed CMDB file %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.csv"), []byte(
+		"Hostname;Environment;Location;Owner;BusinessService;Decommissioned\n"+
+			"foobar;PRD;Italy;dba;billing;false\n"+
+			";TST;Italy;;;\n"+
+			"oldhost;PRD;Italy;dba;billing;true\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(
+		`[{"hostname": "barfoo.example.org", "environment": "TST", "decommissioned": "Retired"}]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("ignored"), 0o600))
+
+	source, err := NewSource(config.CmdbSource{
+		Name:        "export",
+		Type:        config.CmdbSourceTypeFile,
+		Directory:   dir,
+		RecordsPath: "$",
+		Fields: config.CmdbFields{
+			Hostname:        "hostname",
+			Environment:     "environment",
+			Location:        "location",
+			Owner:           "owner",
+			BusinessService: "businessService",
+			Decommissioned:  "decommissioned",
+		},
+		DecommissionedValues: []string{"true", "Retired"},
+	})
+	require.NoError(t, err)
+
+	records, err := source.Fetch()
+	require.NoError(t, err)
+
+	expected := []model.CmdbRecord{
+		{Hostname: "foobar", Environment: "PRD", Location: "Italy", Owner: "dba", BusinessService: "billing"},
+		{Hostname: "oldhost", Environment: "PRD", Location: "Italy", Owner: "dba", BusinessService: "billing", Decommissioned: true},
+		{Hostname: "barfoo.example.org", Environment: "TST", Decommissioned: true},
+	}
+	assert.Equal(t, expected, records)
+
+	require.NoError(t, source.Commit())
+
+	assert.FileExists(t, filepath.Join(dir, processedDirectory, "a.csv"))
+	assert.FileExists(t, filepath.Join(dir, processedDirectory, "b.json"))
+	assert.FileExists(t, filepath.Join(dir, "readme.txt"))
+
+	records, err = source.Fetch()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestFileSource_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"hostname": "foobar"}`), 0o600))
+
+	source, err := NewSource(config.CmdbSource{
+		Name:        "export",
+		Type:        config.CmdbSourceTypeFile,
+		Directory:   dir,
+		RecordsPath: "$",
+		Fields:      config.CmdbFields{Hostname: "hostname"},
+	})
+	require.NoError(t, err)
+
+	_, err = source.Fetch()
+	assert.Error(t, err)
+}
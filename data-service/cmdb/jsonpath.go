@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// jsonPath is a compiled subset of JSONPath: an optional leading $, then fields separated by dots
+// or quoted in brackets (['name']) and array indexes ([0]). A path without $ starts from the root too
+type jsonPath []jsonPathStep
+
+type jsonPathStep struct {
+	field string
+	index int
+	isIdx bool
+}
+
+func compileJSONPath(path string) (jsonPath, error) {
+	rest := strings.TrimSpace(path)
+	rest = strings.TrimPrefix(rest, "$")
+
+	steps := make(jsonPath, 0)
+
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			fallthrough
+		case rest[0] != '[':
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			if end == 0 {
+				return nil, utils.NewErrorf("Invalid JSONPath %q: empty field", path)
+			}
+
+			steps = append(steps, jsonPathStep{field: rest[:end]})
+			rest = rest[end:]
+		default:
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, utils.NewErrorf("Invalid JSONPath %q: missing ]", path)
+			}
+
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, jsonPathStep{field: inner[1 : len(inner)-1]})
+				continue
+			}
+
+			if inner == "" || strings.IndexFunc(inner, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+				return nil, utils.NewErrorf("Invalid JSONPath %q: invalid index %q", path, inner)
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, utils.NewErrorf("Invalid JSONPath %q: %s", path, err)
+			}
+
+			steps = append(steps, jsonPathStep{index: index, isIdx: true})
+		}
+	}
+
+	return steps, nil
+}
+
+// lookup return the value at the path in the decoded JSON document, and false if it isn't there
+func (path jsonPath) lookup(doc interface{}) (interface{}, bool) {
+	value := doc
+
+	for _, step := range path {
+		if step.isIdx {
+			array, ok := value.([]interface{})
+			if !ok || step.index >= len(array) {
+				return nil, false
+			}
+
+			value = array[step.index]
+
+			continue
+		}
+
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = object[step.field]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
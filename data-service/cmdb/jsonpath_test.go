@@ -0,0 +1,64 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"result": [
+			{"name": "foobar", "location": {"display_value": "Italy"}, "owner.name": "dba"},
+			{"name": "barfoo"}
+		]
+	}`), &doc))
+
+	testCases := []struct {
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{path: "$.result[0].name", expected: "foobar", found: true},
+		{path: "result[1].name", expected: "barfoo", found: true},
+		{path: "$['result'][0]['owner.name']", expected: "dba", found: true},
+		{path: "$.result[0].location.display_value", expected: "Italy", found: true},
+		{path: "$.result[2].name", found: false},
+		{path: "$.result.name", found: false},
+		{path: "$.missing", found: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			path, err := compileJSONPath(tc.path)
+			require.NoError(t, err)
+
+			actual, found := path.lookup(doc)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	for _, invalid := range []string{"$.result[", "$.result[a]", "$..name"} {
+		_, err := compileJSONPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
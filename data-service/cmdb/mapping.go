@@ -0,0 +1,183 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// recordMapper build the records of the hosts from the JSON objects or the CSV rows of a CMDB
+type recordMapper struct {
+	fields               config.CmdbFields
+	recordsPath          jsonPath
+	paths                map[string]jsonPath
+	decommissionedValues []string
+}
+
+func newRecordMapper(conf config.CmdbSource) (*recordMapper, error) {
+	mapper := &recordMapper{
+		fields:               conf.Fields,
+		paths:                make(map[string]jsonPath),
+		decommissionedValues: conf.DecommissionedValues,
+	}
+
+	var err error
+	if mapper.recordsPath, err = compileJSONPath(conf.RecordsPath); err != nil {
+		return nil, err
+	}
+
+	for _, field := range mapper.fieldNames() {
+		if field == "" {
+			continue
+		}
+
+		if mapper.paths[field], err = compileJSONPath(field); err != nil {
+			return nil, err
+		}
+	}
+
+	return mapper, nil
+}
+
+func (mapper *recordMapper) fieldNames() []string {
+	return []string{
+		mapper.fields.Hostname,
+		mapper.fields.Environment,
+		mapper.fields.Location,
+		mapper.fields.Owner,
+		mapper.fields.BusinessService,
+		mapper.fields.Decommissioned,
+	}
+}
+
+// record return the record built from the values of the fields, and false if it has no hostname
+func (mapper *recordMapper) record(value func(field string) string) (model.CmdbRecord, bool) {
+	get := func(field string) string {
+		if field == "" {
+			return ""
+		}
+
+		return strings.TrimSpace(value(field))
+	}
+
+	record := model.CmdbRecord{
+		Hostname:        get(mapper.fields.Hostname),
+		Environment:     get(mapper.fields.Environment),
+		Location:        get(mapper.fields.Location),
+		Owner:           get(mapper.fields.Owner),
+		BusinessService: get(mapper.fields.BusinessService),
+		Decommissioned:  mapper.isDecommissioned(get(mapper.fields.Decommissioned)),
+	}
+
+	return record, record.Hostname != ""
+}
+
+// isDecommissioned return true if value is one of the decommissioned values, or true when they aren't set
+func (mapper *recordMapper) isDecommissioned(value string) bool {
+	if len(mapper.decommissionedValues) == 0 {
+		return strings.EqualFold(value, "true")
+	}
+
+	for _, decommissioned := range mapper.decommissionedValues {
+		if strings.EqualFold(value, decommissioned) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fromJSON return the records in the decoded JSON document, found at the records path
+func (mapper *recordMapper) fromJSON(doc interface{}) ([]model.CmdbRecord, error) {
+	value, ok := mapper.recordsPath.lookup(doc)
+	if !ok {
+		return nil, utils.NewErrorf("Records not found in the JSON document")
+	}
+
+	objects, ok := value.([]interface{})
+	if !ok {
+		return nil, utils.NewErrorf("Records aren't a JSON array")
+	}
+
+	records := make([]model.CmdbRecord, 0, len(objects))
+
+	for _, object := range objects {
+		record, ok := mapper.record(func(field string) string {
+			v, _ := mapper.paths[field].lookup(object)
+			return stringify(v)
+		})
+		if ok {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// fromCSV return the records in the CSV rows, which columns are named by header
+func (mapper *recordMapper) fromCSV(header []string, rows [][]string) []model.CmdbRecord {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	records := make([]model.CmdbRecord, 0, len(rows))
+
+	for _, row := range rows {
+		record, ok := mapper.record(func(field string) string {
+			i, ok := columns[strings.ToLower(field)]
+			if !ok || i >= len(row) {
+				return ""
+			}
+
+			return row[i]
+		})
+		if ok {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// stringify return the value of a JSON field as string. The ServiceNow reference fields
+// are objects with the display value
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		if display, ok := v["display_value"]; ok {
+			return stringify(display)
+		}
+	}
+
+	raw, _ := json.Marshal(value)
+
+	return string(raw)
+}
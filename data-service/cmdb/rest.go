@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// restSource reads the records from a generic REST endpoint that returns them in a JSON document
+type restSource struct {
+	conf   config.CmdbSource
+	mapper *recordMapper
+	client *http.Client
+}
+
+func (source *restSource) Fetch() ([]model.CmdbRecord, error) {
+	doc, err := source.get(source.conf.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.mapper.fromJSON(doc)
+}
+
+func (source *restSource) Commit() error {
+	return nil
+}
+
+// get return the decoded JSON document returned by url
+func (source *restSource) get(url string) (interface{}, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, utils.NewError(err, "Can't create the CMDB request")
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if source.conf.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+source.conf.Token)
+	} else if source.conf.Username != "" {
+		req.SetBasicAuth(source.conf.Username, source.conf.Password)
+	}
+
+	resp, err := source.client.Do(req)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't read CMDB %s: %s", source.conf.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, utils.NewErrorf("Can't read CMDB %s: response status %d: %s", source.conf.Name, resp.StatusCode, body)
+	}
+
+	var doc interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, utils.NewErrorf("Can't decode the response of CMDB %s: %s", source.conf.Name, err)
+	}
+
+	return doc, nil
+}
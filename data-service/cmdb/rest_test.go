@@ -0,0 +1,147 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+func TestRestSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer s3cr3t", r.Header.Get("Authorization"))
+
+		fmt.Fprint(w, `{"data": {"items": [
+			{"ci": {"fqdn": "foobar.example.org"}, "env": "PRD", "active": true},
+			{"ci": {"fqdn": "barfoo.example.org"}, "env": "TST", "active": false}
+		]}}`)
+	}))
+	defer server.Close()
+
+	source, err := NewSource(config.CmdbSource{
+		Name:                 "rest",
+		Type:                 config.CmdbSourceTypeRest,
+		URL:                  server.URL,
+		Token:                "s3cr3t",
+		TimeoutSeconds:       10,
+		RecordsPath:          "$.data.items",
+		Fields:               config.CmdbFields{Hostname: "$.ci.fqdn", Environment: "$.env", Decommissioned: "$.active"},
+		DecommissionedValues: []string{"false"},
+	})
+	require.NoError(t, err)
+
+	records, err := source.Fetch()
+	require.NoError(t, err)
+
+	expected := []model.CmdbRecord{
+		{Hostname: "foobar.example.org", Environment: "PRD"},
+		{Hostname: "barfoo.example.org", Environment: "TST", Decommissioned: true},
+	}
+	assert.Equal(t, expected, records)
+}
+
+func TestRestSource_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source, err := NewSource(config.CmdbSource{
+		Name:           "rest",
+		Type:           config.CmdbSourceTypeRest,
+		URL:            server.URL,
+		TimeoutSeconds: 10,
+		RecordsPath:    "$",
+		Fields:         config.CmdbFields{Hostname: "$.hostname"},
+	})
+	require.NoError(t, err)
+
+	_, err = source.Fetch()
+	assert.Error(t, err)
+}
+
+func TestServiceNowSource(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		assert.Equal(t, "/api/now/table/cmdb_ci_server", r.URL.Path)
+		assert.Equal(t, "operational_status=1", r.URL.Query().Get("sysparm_query"))
+		assert.Equal(t, "true", r.URL.Query().Get("sysparm_display_value"))
+
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "ercole", user)
+		assert.Equal(t, "password", password)
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+		require.NoError(t, err)
+
+		switch offset {
+		case 0:
+			fmt.Fprint(w, `{"result": [
+				{"name": "host1", "environment": "Production", "location": "Milan", "owned_by": "dba", "install_status": "Installed"},
+				{"name": "", "environment": "Production"}
+			]}`)
+		case 2:
+			fmt.Fprint(w, `{"result": [
+				{"name": "host2", "environment": "Test", "location": "Rome", "owned_by": "", "install_status": "Retired"}
+			]}`)
+		default:
+			t.Errorf("unexpected offset %d", offset)
+		}
+	}))
+	defer server.Close()
+
+	source := &serviceNowSource{pageSize: 2}
+	source.conf = config.CmdbSource{
+		Name:                 "servicenow",
+		Type:                 config.CmdbSourceTypeServiceNow,
+		URL:                  server.URL + "/",
+		Table:                "cmdb_ci_server",
+		Query:                "operational_status=1",
+		Username:             "ercole",
+		Password:             "password",
+		RecordsPath:          "$.result",
+		Fields:               config.CmdbFields{Hostname: "$.name", Environment: "$.environment", Location: "$.location", Owner: "$.owned_by", Decommissioned: "$.install_status"},
+		DecommissionedValues: []string{"Retired"},
+	}
+	source.client = http.DefaultClient
+
+	var err error
+	source.mapper, err = newRecordMapper(source.conf)
+	require.NoError(t, err)
+
+	records, err := source.Fetch()
+	require.NoError(t, err)
+
+	expected := []model.CmdbRecord{
+		{Hostname: "host1", Environment: "Production", Location: "Milan", Owner: "dba"},
+		{Hostname: "host2", Environment: "Test", Location: "Rome", Decommissioned: true},
+	}
+	assert.Equal(t, expected, records)
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmdb
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// serviceNowPageSize is the number of records read with each request to the ServiceNow table API
+const serviceNowPageSize = 1000
+
+// serviceNowSource reads the records from a table of the ServiceNow table API, a page at a time
+type serviceNowSource struct {
+	restSource
+	pageSize int
+}
+
+func (source *serviceNowSource) Fetch() ([]model.CmdbRecord, error) {
+	records := make([]model.CmdbRecord, 0)
+
+	for offset := 0; ; offset += source.pageSize {
+		doc, err := source.get(source.pageURL(offset))
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := source.mapper.fromJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, page...)
+
+		// the records without hostname are dropped, so the size of the page is counted on the document
+		if value, _ := source.mapper.recordsPath.lookup(doc); len(value.([]interface{})) < source.pageSize {
+			return records, nil
+		}
+	}
+}
+
+func (source *serviceNowSource) pageURL(offset int) string {
+	params := url.Values{}
+	params.Set("sysparm_display_value", "true")
+	params.Set("sysparm_exclude_reference_link", "true")
+	params.Set("sysparm_limit", strconv.Itoa(source.pageSize))
+	params.Set("sysparm_offset", strconv.Itoa(offset))
+
+	if source.conf.Query != "" {
+		params.Set("sysparm_query", source.conf.Query)
+	}
+
+	return strings.TrimSuffix(source.conf.URL, "/") + "/api/now/table/" + url.PathEscape(source.conf.Table) + "?" + params.Encode()
+}
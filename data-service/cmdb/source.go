@@ -0,0 +1,55 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package cmdb contains the sources from which the records of the hosts are read from the CMDBs
+package cmdb
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// Source is a CMDB from which the records of the hosts are read
+type Source interface {
+	// Fetch return the records of the hosts. The Source and SyncedAt fields aren't set
+	Fetch() ([]model.CmdbRecord, error)
+	// Commit is called when the fetched records have been saved
+	Commit() error
+}
+
+// NewSource return the source of the CMDB configured by conf
+func NewSource(conf config.CmdbSource) (Source, error) {
+	mapper, err := newRecordMapper(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: time.Duration(conf.TimeoutSeconds) * time.Second}
+
+	switch conf.Type {
+	case config.CmdbSourceTypeFile:
+		return &fileSource{conf: conf, mapper: mapper}, nil
+	case config.CmdbSourceTypeRest:
+		return &restSource{conf: conf, mapper: mapper, client: client}, nil
+	case config.CmdbSourceTypeServiceNow:
+		return &serviceNowSource{restSource: restSource{conf: conf, mapper: mapper, client: client}, pageSize: serviceNowPageSize}, nil
+	default:
+		return nil, utils.NewErrorf("Invalid type %q of CMDB source %q", conf.Type, conf.Name)
+	}
+}
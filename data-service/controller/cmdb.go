@@ -16,6 +16,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/ercole-io/ercole/v2/data-service/dto"
@@ -37,3 +38,16 @@ func (ctrl *DataController) CompareCmdbInfo(w http.ResponseWriter, r *http.Reque
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (ctrl *DataController) SyncCmdbs(w http.ResponseWriter, r *http.Request) {
+	reports, err := ctrl.Service.SyncCmdbs(r.URL.Query().Get("source"))
+	if errors.Is(err, utils.ErrCmdbSourceNotFound) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, map[string]interface{}{"reports": reports})
+}
@@ -123,3 +123,74 @@ func TestCompareCmdbsInfo_InternalServerError(t *testing.T) {
 
 	assert.Equal(t, "mock", actual.Message)
 }
+
+func TestSyncCmdbs_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	reports := []dto.CmdbSyncReport{{Source: "servicenow", Records: 3, Matched: 2, Unmatched: 1}}
+	as.EXPECT().SyncCmdbs("servicenow").Return(reports, nil)
+
+	req, err := http.NewRequest("POST", "/?source=servicenow", nil)
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(ac.SyncCmdbs)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, utils.ToJSON(map[string]interface{}{"reports": reports}), rr.Body.String())
+}
+
+func TestSyncCmdbs_NotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().SyncCmdbs("foobar").Return(nil, utils.NewErrorf("%w: foobar", utils.ErrCmdbSourceNotFound))
+
+	req, err := http.NewRequest("POST", "/?source=foobar", nil)
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(ac.SyncCmdbs)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSyncCmdbs_InternalServerError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	as.EXPECT().SyncCmdbs("").Return(nil, aerrMock)
+
+	req, err := http.NewRequest("POST", "/", nil)
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(ac.SyncCmdbs)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
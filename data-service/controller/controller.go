@@ -28,9 +28,12 @@ import (
 
 type DataControllerInterface interface {
 	InsertHostData(w http.ResponseWriter, r *http.Request)
+	GetHostDataIngestion(w http.ResponseWriter, r *http.Request)
 	CompareCmdbInfo(w http.ResponseWriter, r *http.Request)
+	SyncCmdbs(w http.ResponseWriter, r *http.Request)
 
 	AuthenticateMiddleware(h http.Handler) http.Handler
+	AdminAuthenticateMiddleware(h http.Handler) http.Handler
 }
 
 type DataController struct {
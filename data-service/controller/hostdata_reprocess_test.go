@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestReprocessHostData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		expectedReq := dto.HostDataReprocessRequest{Location: "Italy", DryRun: true}
+		report := &dto.HostDataReprocessReport{DryRun: true, Hosts: []dto.HostDataReprocessResult{}}
+		as.EXPECT().ReprocessHostData(expectedReq).Return(report, nil)
+
+		req, err := http.NewRequest("POST", "/hosts/reprocess", bytes.NewReader([]byte(`{"location":"Italy","dryRun":true}`)))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ReprocessHostData).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.JSONEq(t, utils.ToJSON(report), rr.Body.String())
+	})
+
+	t.Run("Empty body", func(t *testing.T) {
+		as.EXPECT().ReprocessHostData(dto.HostDataReprocessRequest{}).Return(&dto.HostDataReprocessReport{}, nil)
+
+		req, err := http.NewRequest("POST", "/hosts/reprocess", bytes.NewReader([]byte{}))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ReprocessHostData).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/hosts/reprocess", bytes.NewReader([]byte(`{"location":`)))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ReprocessHostData).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Invalid technology", func(t *testing.T) {
+		as.EXPECT().ReprocessHostData(dto.HostDataReprocessRequest{Technology: "Foo/Bar"}).
+			Return(nil, utils.NewErrorf("%w: unsupported technology", utils.ErrInvalidHostDataReprocess))
+
+		req, err := http.NewRequest("POST", "/hosts/reprocess", bytes.NewReader([]byte(`{"technology":"Foo/Bar"}`)))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ReprocessHostData).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		as.EXPECT().ReprocessHostData(dto.HostDataReprocessRequest{}).Return(nil, errors.New("error"))
+
+		req, err := http.NewRequest("POST", "/hosts/reprocess", bytes.NewReader([]byte(`{}`)))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.ReprocessHostData).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
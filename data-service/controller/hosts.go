@@ -22,6 +22,9 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/schema"
 	"github.com/ercole-io/ercole/v2/utils"
@@ -37,18 +40,36 @@ func (ctrl *DataController) InsertHostData(w http.ResponseWriter, r *http.Reques
 	}
 	defer r.Body.Close()
 
-	if raw, err = ctrl.sanitizeJson(raw); err != nil {
+	_, ingestion, status, err := ctrl.insertHostData(raw)
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, status, err)
+		return
+	}
+
+	if ingestion != nil {
+		w.Header().Set("Location", fmt.Sprintf("/ingestions/%s", ingestion.ID.Hex()))
+		utils.WriteJSONResponse(w, status, ingestion)
+
+		return
+	}
+
+	utils.WriteJSONResponse(w, status, nil)
+}
+
+// insertHostData sanitize, validate and insert the raw hostdata, or queue it if the ingestion queue is enabled.
+// It returns the http status of the outcome
+func (ctrl *DataController) insertHostData(raw []byte) (*model.HostDataBE, *model.HostDataIngestion, int, error) {
+	raw, err := ctrl.sanitizeJson(raw)
+	if err != nil {
 		if errors.Is(err, utils.ErrInvalidJSON) {
-			utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, err)
 			ctrl.Service.AlertInvalidHostData(err, nil)
 
-			return
+			return nil, nil, http.StatusUnprocessableEntity, err
 		}
 
 		ctrl.Log.Error(err)
-		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	var hostdata model.HostDataBE
@@ -56,35 +77,59 @@ func (ctrl *DataController) InsertHostData(w http.ResponseWriter, r *http.Reques
 	if validationErr := schema.ValidateHostdata(raw); validationErr != nil {
 		if errors.Is(validationErr, utils.ErrInvalidHostdata) {
 			ctrl.Log.Info(validationErr)
-			utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, validationErr)
 
 			if unmarshalErr := json.Unmarshal(raw, &hostdata); unmarshalErr != nil {
 				ctrl.Service.AlertInvalidHostData(validationErr, nil)
-			} else {
-				ctrl.Service.AlertInvalidHostData(validationErr, &hostdata)
+
+				return nil, nil, http.StatusUnprocessableEntity, validationErr
 			}
 
-			return
+			ctrl.Service.AlertInvalidHostData(validationErr, &hostdata)
+
+			return &hostdata, nil, http.StatusUnprocessableEntity, validationErr
 		}
 
-		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, validationErr)
+		return nil, nil, http.StatusInternalServerError, validationErr
+	}
 
-		return
+	if err := json.Unmarshal(raw, &hostdata); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	if ctrl.Config.DataService.IngestionQueue.Enabled {
+		ingestion, err := ctrl.Service.EnqueueHostData(hostdata)
+		if err != nil {
+			return &hostdata, nil, http.StatusInternalServerError, err
+		}
+
+		return &hostdata, ingestion, http.StatusAccepted, nil
 	}
 
-	err = json.Unmarshal(raw, &hostdata)
+	if err := ctrl.Service.InsertHostData(hostdata); err != nil {
+		return &hostdata, nil, http.StatusInternalServerError, err
+	}
+
+	return &hostdata, nil, http.StatusOK, nil
+}
+
+// GetHostDataIngestion return the status of a queued hostdata
+func (ctrl *DataController) GetHostDataIngestion(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
 	if err != nil {
-		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnprocessableEntity, fmt.Errorf("Can't decode id: %w", err))
 		return
 	}
 
-	err = ctrl.Service.InsertHostData(hostdata)
-	if err != nil {
+	ingestion, err := ctrl.Service.GetHostDataIngestion(id)
+	if errors.Is(err, utils.ErrHostDataIngestionNotFound) {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
 		utils.WriteAndLogError(ctrl.Log, w, http.StatusInternalServerError, err)
 		return
 	}
 
-	utils.WriteJSONResponse(w, http.StatusOK, nil)
+	utils.WriteJSONResponse(w, http.StatusOK, ingestion)
 }
 
 func (ctrl *DataController) sanitizeJson(raw []byte) ([]byte, error) {
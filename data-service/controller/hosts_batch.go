@@ -0,0 +1,209 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// Content types accepted by InsertHostDataBatch
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeTar    = "application/x-tar"
+)
+
+// maxBatchItemSize is the maximum size of a single hostdata of a batch, after decompression
+const maxBatchItemSize = 64 << 20
+
+var errUnsupportedBatch = errors.New("Unsupported batch")
+
+// batchItemsReader return the name and the content of the next hostdata of a batch, or io.EOF
+type batchItemsReader func() (string, []byte, error)
+
+// InsertHostDataBatch insert the hostdata of a batch, sent as NDJSON or tar, optionally compressed with gzip or zstd.
+// Each hostdata is validated and inserted independently, and the response contains the outcome of each of them
+func (ctrl *DataController) InsertHostDataBatch(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	body, err := decompressBatch(r.Body, r.Header.Get("Content-Encoding"))
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+	defer body.Close()
+
+	next, err := newBatchItemsReader(body, r.Header.Get("Content-Type"))
+	if err != nil {
+		utils.WriteAndLogError(ctrl.Log, w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	result := dto.HostDataBatchResult{
+		Items: make([]dto.HostDataBatchItemResult, 0),
+	}
+
+	for {
+		name, raw, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			// the rest of the batch can't be read
+			result.Items = append(result.Items, dto.HostDataBatchItemResult{
+				Item:   name,
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			result.Rejected++
+
+			break
+		}
+
+		item := dto.HostDataBatchItemResult{Item: name}
+
+		hostdata, ingestion, status, err := ctrl.insertHostData(raw)
+		if hostdata != nil {
+			item.Hostname = hostdata.Hostname
+		}
+
+		if ingestion != nil {
+			item.IngestionID = &ingestion.ID
+		}
+
+		item.Status = status
+
+		if err != nil {
+			ctrl.Log.Errorf("Can't insert hostdata %s of the batch: %s", name, err)
+
+			item.Error = err.Error()
+			result.Rejected++
+		} else {
+			result.Accepted++
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func decompressBatch(body io.Reader, contentEncoding string) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return io.NopCloser(body), nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "zstd":
+		decoder, err := zstd.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("%w: content encoding %q", errUnsupportedBatch, contentEncoding)
+	}
+}
+
+func newBatchItemsReader(body io.Reader, contentType string) (batchItemsReader, error) {
+	mediaType := ContentTypeNDJSON
+
+	if contentType != "" {
+		var err error
+
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return nil, fmt.Errorf("%w: %s", errUnsupportedBatch, err)
+		}
+	}
+
+	switch mediaType {
+	case ContentTypeNDJSON, "application/jsonl":
+		return newNDJSONItemsReader(body), nil
+	case ContentTypeTar:
+		return newTarItemsReader(body), nil
+	default:
+		return nil, fmt.Errorf("%w: content type %q", errUnsupportedBatch, contentType)
+	}
+}
+
+func newNDJSONItemsReader(body io.Reader) batchItemsReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchItemSize)
+
+	line := 0
+
+	return func() (string, []byte, error) {
+		for scanner.Scan() {
+			line++
+
+			raw := bytes.TrimSpace(scanner.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+
+			return fmt.Sprintf("line %d", line), append([]byte(nil), raw...), nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			return fmt.Sprintf("line %d", line+1), nil, err
+		}
+
+		return "", nil, io.EOF
+	}
+}
+
+func newTarItemsReader(body io.Reader) batchItemsReader {
+	tr := tar.NewReader(body)
+
+	return func() (string, []byte, error) {
+		for {
+			header, err := tr.Next()
+			if err != nil {
+				return "", nil, err
+			}
+
+			if header.Typeflag != tar.TypeReg {
+				continue
+			}
+
+			if header.Size > maxBatchItemSize {
+				return header.Name, nil, fmt.Errorf("%s is bigger than %d bytes", header.Name, maxBatchItemSize)
+			}
+
+			raw, err := io.ReadAll(tr)
+			if err != nil {
+				return header.Name, nil, err
+			}
+
+			return header.Name, raw, nil
+		}
+	}
+}
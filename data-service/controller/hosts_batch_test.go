@@ -0,0 +1,160 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/mongoutils"
+)
+
+func TestInsertHostDataBatch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	raw, err := os.ReadFile("../../fixture/test_dataservice_hostdata_v1_00.json")
+	require.NoError(t, err)
+
+	var compacted bytes.Buffer
+	require.NoError(t, json.Compact(&compacted, raw))
+
+	hostdata := mongoutils.LoadFixtureHostData(t, "../../fixture/test_dataservice_hostdata_v1_00.json")
+
+	ndjson := []byte(compacted.String() + "\n\n{\"hostname\": \n")
+
+	send := func(t *testing.T, body []byte, contentType, contentEncoding string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/hosts/batch", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Content-Encoding", contentEncoding)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(ac.InsertHostDataBatch).ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	decode := func(t *testing.T, rr *httptest.ResponseRecorder) dto.HostDataBatchResult {
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var result dto.HostDataBatchResult
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+
+		return result
+	}
+
+	t.Run("NDJSON", func(t *testing.T) {
+		as.EXPECT().InsertHostData(hostdata).Return(nil)
+		as.EXPECT().AlertInvalidHostData(gomock.Any(), nil)
+
+		result := decode(t, send(t, ndjson, ContentTypeNDJSON, ""))
+
+		assert.Equal(t, 1, result.Accepted)
+		assert.Equal(t, 1, result.Rejected)
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, dto.HostDataBatchItemResult{Item: "line 1", Hostname: hostdata.Hostname, Status: http.StatusOK}, result.Items[0])
+		assert.Equal(t, "line 3", result.Items[1].Item)
+		assert.Equal(t, http.StatusUnprocessableEntity, result.Items[1].Status)
+		assert.NotEmpty(t, result.Items[1].Error)
+	})
+
+	t.Run("Zstd NDJSON", func(t *testing.T) {
+		as.EXPECT().InsertHostData(hostdata).Return(nil)
+		as.EXPECT().AlertInvalidHostData(gomock.Any(), nil)
+
+		encoder, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		compressed := encoder.EncodeAll(ndjson, nil)
+
+		result := decode(t, send(t, compressed, ContentTypeNDJSON, "zstd"))
+
+		assert.Equal(t, 1, result.Accepted)
+		assert.Equal(t, 1, result.Rejected)
+	})
+
+	t.Run("Gzip tar, queued", func(t *testing.T) {
+		ac.Config.DataService.IngestionQueue.Enabled = true
+		defer func() { ac.Config.DataService.IngestionQueue.Enabled = false }()
+
+		ingestion := model.HostDataIngestion{ID: utils.Str2oid("5ef9d239a1d25d1e8703c4d3")}
+		as.EXPECT().EnqueueHostData(hostdata).Return(&ingestion, nil).Times(2)
+
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "hosts/", Typeflag: tar.TypeDir, Mode: 0755}))
+
+		for _, name := range []string{"hosts/a.json", "hosts/b.json"} {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(raw))}))
+			_, err := tw.Write(raw)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, tw.Close())
+		require.NoError(t, gw.Close())
+
+		result := decode(t, send(t, buf.Bytes(), ContentTypeTar, "gzip"))
+
+		assert.Equal(t, 2, result.Accepted)
+		assert.Equal(t, 0, result.Rejected)
+		assert.Equal(t, []dto.HostDataBatchItemResult{
+			{Item: "hosts/a.json", Hostname: hostdata.Hostname, Status: http.StatusAccepted, IngestionID: &ingestion.ID},
+			{Item: "hosts/b.json", Hostname: hostdata.Hostname, Status: http.StatusAccepted, IngestionID: &ingestion.ID},
+		}, result.Items)
+	})
+
+	t.Run("Corrupted stream", func(t *testing.T) {
+		result := decode(t, send(t, []byte("not a tar"), ContentTypeTar, ""))
+
+		assert.Equal(t, 1, result.Rejected)
+		assert.Equal(t, http.StatusBadRequest, result.Items[0].Status)
+	})
+
+	t.Run("Unsupported encoding", func(t *testing.T) {
+		rr := send(t, ndjson, ContentTypeNDJSON, "br")
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		rr := send(t, ndjson, "application/xml", "")
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+}
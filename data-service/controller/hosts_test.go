@@ -24,11 +24,13 @@ import (
 	"testing"
 
 	gomock "github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 	"github.com/ercole-io/ercole/v2/utils/mongoutils"
 )
@@ -61,6 +63,98 @@ func TestUpdateHostInfo_Success(t *testing.T) {
 	require.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestUpdateHostInfo_Queued(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config: config.Configuration{
+			DataService: config.DataService{
+				IngestionQueue: config.IngestionQueue{Enabled: true},
+			},
+		},
+		Log: logger.NewLogger("TEST"),
+	}
+
+	raw, err := ioutil.ReadFile("../../fixture/test_dataservice_hostdata_v1_00.json")
+	require.NoError(t, err)
+
+	expectedHostDataBE := mongoutils.LoadFixtureHostData(t, "../../fixture/test_dataservice_hostdata_v1_00.json")
+
+	ingestion := model.HostDataIngestion{
+		ID:       utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		Hostname: expectedHostDataBE.Hostname,
+		Status:   model.HostDataIngestionStatusQueued,
+	}
+	as.EXPECT().EnqueueHostData(expectedHostDataBE).Return(&ingestion, nil)
+
+	handler := http.HandlerFunc(ac.InsertHostData)
+	req, err := http.NewRequest("POST", "/", bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "/ingestions/5ef9d239a1d25d1e8703c4d3", rr.Header().Get("Location"))
+	assert.JSONEq(t, utils.ToJSON(ingestion), rr.Body.String())
+}
+
+func TestGetHostDataIngestion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	as := NewMockHostDataServiceInterface(mockCtrl)
+	ac := DataController{
+		TimeNow: utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Service: as,
+		Config:  config.Configuration{},
+		Log:     logger.NewLogger("TEST"),
+	}
+
+	id := utils.Str2oid("5ef9d239a1d25d1e8703c4d3")
+
+	t.Run("Success", func(t *testing.T) {
+		ingestion := model.HostDataIngestion{ID: id, Hostname: "foobar", Status: model.HostDataIngestionStatusDone}
+		as.EXPECT().GetHostDataIngestion(id).Return(&ingestion, nil)
+
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+
+		http.HandlerFunc(ac.GetHostDataIngestion).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, utils.ToJSON(ingestion), rr.Body.String())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		as.EXPECT().GetHostDataIngestion(id).Return(nil, utils.ErrHostDataIngestionNotFound)
+
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": id.Hex()})
+
+		http.HandlerFunc(ac.GetHostDataIngestion).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{"id": "foobar"})
+
+		http.HandlerFunc(ac.GetHostDataIngestion).ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+}
+
 func TestUpdateHostInfo_FailBadRequest(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -16,15 +16,20 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/goji/httpauth"
 	"github.com/gorilla/mux"
+
+	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/metrics"
 )
 
 // GetDataControllerHandler setup the routes of the router using the handler in the controller as http handler
 func (ctrl *DataController) GetDataControllerHandler() http.Handler {
 	router := mux.NewRouter()
+	router.Use(metrics.Middleware("data-service"))
 
 	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		if _, err := w.Write([]byte("Pong")); err != nil {
@@ -34,22 +39,50 @@ func (ctrl *DataController) GetDataControllerHandler() http.Handler {
 	})
 
 	router.StrictSlash(true)
-	router.Use(ctrl.AuthenticateMiddleware)
 
-	ctrl.setupProtectedRoutes(router)
+	agentRouter := router.NewRoute().Subrouter()
+	agentRouter.Use(ctrl.AuthenticateMiddleware)
+	ctrl.setupProtectedRoutes(agentRouter)
+
+	adminRouter := router.NewRoute().Subrouter()
+	adminRouter.Use(ctrl.AdminAuthenticateMiddleware)
+	ctrl.setupAdminRoutes(adminRouter)
 
 	return router
 }
 
 func (ctrl *DataController) setupProtectedRoutes(router *mux.Router) {
 	router.HandleFunc("/hosts", ctrl.InsertHostData).Methods("POST")
+	router.HandleFunc("/hosts/batch", ctrl.InsertHostDataBatch).Methods("POST")
+	router.HandleFunc("/ingestions/{id}", ctrl.GetHostDataIngestion).Methods("GET")
 	router.HandleFunc("/cmdbs", ctrl.CompareCmdbInfo).Methods("POST")
 	router.HandleFunc("/oracle/license-types", ctrl.InsertOracleLicenseTypes).Methods("POST")
 	router.HandleFunc("/exadatas", ctrl.InsertExadata).Methods("POST")
 }
 
+// setupAdminRoutes setup the routes that change the stored data, reserved to the admin credentials
+func (ctrl *DataController) setupAdminRoutes(router *mux.Router) {
+	router.HandleFunc("/hosts/reprocess", ctrl.ReprocessHostData).Methods("POST")
+	router.HandleFunc("/cmdbs/sync", ctrl.SyncCmdbs).Methods("POST")
+}
+
 // AuthenticateMiddleware return the middleware used to authenticate (request) users
 func (ctrl *DataController) AuthenticateMiddleware(h http.Handler) http.Handler {
 	basicAuthHandler := httpauth.SimpleBasicAuth(ctrl.Config.DataService.AgentUsername, ctrl.Config.DataService.AgentPassword)
 	return basicAuthHandler(h)
 }
+
+// AdminAuthenticateMiddleware return the middleware used to authenticate the admin requests,
+// all rejected when the admin credentials aren't configured
+func (ctrl *DataController) AdminAuthenticateMiddleware(h http.Handler) http.Handler {
+	if ctrl.Config.DataService.AdminUsername == "" || ctrl.Config.DataService.AdminPassword == "" {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			utils.WriteAndLogError(ctrl.Log, w, http.StatusForbidden,
+				utils.NewError(errors.New("The admin credentials of the data-service aren't configured"), "FORBIDDEN_REQUEST"))
+		})
+	}
+
+	basicAuthHandler := httpauth.SimpleBasicAuth(ctrl.Config.DataService.AdminUsername, ctrl.Config.DataService.AdminPassword)
+
+	return basicAuthHandler(h)
+}
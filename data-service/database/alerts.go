@@ -17,19 +17,114 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
-// DeleteAllNoDataAlerts delete all alerts with code NO_DATA
-func (md *MongoDatabase) DeleteAllNoDataAlerts() error {
-	_, err := md.Client.Database(md.Config.Mongodb.DBName).
+// FindUnresolvedNoDataAlerts return the NO_DATA alerts not resolved yet, dismissed ones included
+func (md *MongoDatabase) FindUnresolvedNoDataAlerts() ([]model.Alert, error) {
+	return md.findUnresolvedAlerts(model.AlertCodeNoData)
+}
+
+// FindUnresolvedCapacityAlerts return the alerts of projected capacity exhaustion not resolved yet, dismissed ones included
+func (md *MongoDatabase) FindUnresolvedCapacityAlerts() ([]model.Alert, error) {
+	return md.findUnresolvedAlerts(capacityAlertCodes()...)
+}
+
+// FindUnresolvedBackupAlerts return the alerts of backup policy violations not resolved yet, dismissed ones included
+func (md *MongoDatabase) FindUnresolvedBackupAlerts() ([]model.Alert, error) {
+	return md.findUnresolvedAlerts(backupAlertCodes()...)
+}
+
+// FindUnresolvedPatchAlerts return the ORACLE_PATCH_OUTDATED alerts not resolved yet, dismissed ones included
+func (md *MongoDatabase) FindUnresolvedPatchAlerts() ([]model.Alert, error) {
+	return md.findUnresolvedAlerts(model.AlertCodeOraclePatchOutdated)
+}
+
+func capacityAlertCodes() []string {
+	return alertCodes(model.CapacityExhaustionAlertCodes)
+}
+
+func backupAlertCodes() []string {
+	return alertCodes(model.BackupViolationAlertCodes)
+}
+
+// alertCodes return the distinct codes of the map
+func alertCodes(codesMap map[string]string) []string {
+	codes := make([]string, 0, len(codesMap))
+	for _, code := range codesMap {
+		if !utils.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes
+}
+
+func (md *MongoDatabase) findUnresolvedAlerts(codes ...string) ([]model.Alert, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).
 		Collection("alerts").
-		DeleteMany(context.TODO(), bson.M{"alertCode": model.AlertCodeNoData})
+		Find(context.TODO(), bson.M{
+			"alertCode":   bson.M{"$in": codes},
+			"alertStatus": bson.M{"$ne": model.AlertStatusResolved},
+		})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	alerts := make([]model.Alert, 0)
+	if err := cur.All(context.TODO(), &alerts); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return alerts, nil
+}
+
+// UpdateNoDataAlert update severity, description and otherInfo of the NO_DATA alert,
+// adding the history entry if it isn't nil
+func (md *MongoDatabase) UpdateNoDataAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error {
+	return md.updateAlert(alert, historyEntry, model.AlertCodeNoData)
+}
+
+// UpdateCapacityAlert update severity, description and otherInfo of the alert of projected capacity exhaustion,
+// adding the history entry if it isn't nil
+func (md *MongoDatabase) UpdateCapacityAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error {
+	return md.updateAlert(alert, historyEntry, capacityAlertCodes()...)
+}
+
+// UpdateBackupAlert update severity, description and otherInfo of the alert of a backup policy violation,
+// adding the history entry if it isn't nil
+func (md *MongoDatabase) UpdateBackupAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error {
+	return md.updateAlert(alert, historyEntry, backupAlertCodes()...)
+}
+
+// UpdatePatchAlert update severity, description and otherInfo of the ORACLE_PATCH_OUTDATED alert,
+// adding the history entry if it isn't nil
+func (md *MongoDatabase) UpdatePatchAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error {
+	return md.updateAlert(alert, historyEntry, model.AlertCodeOraclePatchOutdated)
+}
+
+func (md *MongoDatabase) updateAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry, codes ...string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"alertSeverity": alert.AlertSeverity,
+			"description":   alert.Description,
+			"otherInfo":     alert.OtherInfo,
+		},
+	}
+
+	if historyEntry != nil {
+		update["$push"] = bson.M{"history": historyEntry}
+	}
 
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).
+		Collection("alerts").
+		UpdateOne(context.TODO(), bson.M{"_id": alert.ID, "alertCode": bson.M{"$in": codes}}, update)
 	if err != nil {
 		return utils.NewError(err, "DB ERROR")
 	}
@@ -37,14 +132,35 @@ func (md *MongoDatabase) DeleteAllNoDataAlerts() error {
 	return nil
 }
 
-// DeleteNoDataAlertByHost delete NO_DATA alert by hostname
-func (md *MongoDatabase) DeleteNoDataAlertByHost(hostname string) error {
+// ResolveNoDataAlertsByHost resolve the open NO_DATA alerts of the host, because it has sent fresh data
+func (md *MongoDatabase) ResolveNoDataAlertsByHost(hostname string, date time.Time) error {
+	return md.resolveNoDataAlerts(hostname, date, "Fresh data received from the host")
+}
+
+// ResolveNoDataAlertsOfInactiveHost resolve the open NO_DATA alerts of a host dismissed or removed
+func (md *MongoDatabase) ResolveNoDataAlertsOfInactiveHost(hostname string, date time.Time) error {
+	return md.resolveNoDataAlerts(hostname, date, "The host isn't active anymore")
+}
+
+func (md *MongoDatabase) resolveNoDataAlerts(hostname string, date time.Time, comment string) error {
 	_, err := md.Client.Database(md.Config.Mongodb.DBName).
 		Collection("alerts").
-		DeleteOne(context.TODO(),
+		UpdateMany(context.TODO(),
 			bson.M{
 				"alertCode":          model.AlertCodeNoData,
 				"otherInfo.hostname": hostname,
+				"alertStatus":        bson.M{"$in": []string{model.AlertStatusNew, model.AlertStatusAck, model.AlertStatusSnoozed}},
+			},
+			bson.M{
+				"$set":   bson.M{"alertStatus": model.AlertStatusResolved},
+				"$unset": bson.M{"snoozedUntil": ""},
+				"$push": bson.M{"history": model.AlertHistoryEntry{
+					Date:     date,
+					Username: model.AlertSystemUsername,
+					Action:   model.AlertActionStatusChange,
+					Status:   model.AlertStatusResolved,
+					Comment:  comment,
+				}},
 			})
 
 	if err != nil {
@@ -53,3 +169,45 @@ func (md *MongoDatabase) DeleteNoDataAlertByHost(hostname string) error {
 
 	return nil
 }
+
+// ResolveCapacityAlert resolve the alert of projected capacity exhaustion, if it's still open
+func (md *MongoDatabase) ResolveCapacityAlert(id primitive.ObjectID, date time.Time, comment string) error {
+	return md.resolveAlert(id, date, comment, capacityAlertCodes()...)
+}
+
+// ResolveBackupAlert resolve the alert of a backup policy violation, if it's still open
+func (md *MongoDatabase) ResolveBackupAlert(id primitive.ObjectID, date time.Time, comment string) error {
+	return md.resolveAlert(id, date, comment, backupAlertCodes()...)
+}
+
+// ResolvePatchAlert resolve the ORACLE_PATCH_OUTDATED alert, if it's still open
+func (md *MongoDatabase) ResolvePatchAlert(id primitive.ObjectID, date time.Time, comment string) error {
+	return md.resolveAlert(id, date, comment, model.AlertCodeOraclePatchOutdated)
+}
+
+func (md *MongoDatabase) resolveAlert(id primitive.ObjectID, date time.Time, comment string, codes ...string) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).
+		Collection("alerts").
+		UpdateOne(context.TODO(),
+			bson.M{
+				"_id":         id,
+				"alertCode":   bson.M{"$in": codes},
+				"alertStatus": bson.M{"$in": []string{model.AlertStatusNew, model.AlertStatusAck, model.AlertStatusSnoozed}},
+			},
+			bson.M{
+				"$set":   bson.M{"alertStatus": model.AlertStatusResolved},
+				"$unset": bson.M{"snoozedUntil": ""},
+				"$push": bson.M{"history": model.AlertHistoryEntry{
+					Date:     date,
+					Username: model.AlertSystemUsername,
+					Action:   model.AlertActionStatusChange,
+					Status:   model.AlertStatusResolved,
+					Comment:  comment,
+				}},
+			})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
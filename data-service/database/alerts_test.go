@@ -19,14 +19,16 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
-func (m *MongodbSuite) TestDeleteAllNoDataAlerts_Success() {
+func (m *MongodbSuite) TestFindUnresolvedNoDataAlerts_Success() {
 	var alert1 model.Alert = model.Alert{
 		AlertCode:               model.AlertCodeNewServer,
 		AlertSeverity:           model.AlertSeverityInfo,
@@ -46,7 +48,7 @@ func (m *MongodbSuite) TestDeleteAllNoDataAlerts_Success() {
 		AlertSeverity:           model.AlertSeverityCritical,
 		AlertAffectedTechnology: nil,
 		AlertCategory:           model.AlertCategoryEngine,
-		AlertStatus:             model.AlertStatusNew,
+		AlertStatus:             model.AlertStatusAck,
 		Date:                    utils.P("2019-11-05T18:02:03Z"),
 		Description:             "test desc pippo",
 		OtherInfo: map[string]interface{}{
@@ -60,7 +62,7 @@ func (m *MongodbSuite) TestDeleteAllNoDataAlerts_Success() {
 		AlertSeverity:           model.AlertSeverityCritical,
 		AlertAffectedTechnology: nil,
 		AlertCategory:           model.AlertCategoryEngine,
-		AlertStatus:             model.AlertStatusNew,
+		AlertStatus:             model.AlertStatusResolved,
 		Date:                    utils.P("2019-12-25T18:02:03Z"),
 		Description:             "test desc pluto",
 		OtherInfo: map[string]interface{}{
@@ -71,40 +73,60 @@ func (m *MongodbSuite) TestDeleteAllNoDataAlerts_Success() {
 
 	defer m.db.Client.Database(m.dbname).Collection("alerts").DeleteMany(context.TODO(), bson.M{})
 
-	_, err := m.db.Client.Database(m.dbname).Collection("alerts").InsertOne(context.TODO(), alert1)
+	_, err := m.db.Client.Database(m.dbname).Collection("alerts").InsertMany(context.TODO(),
+		[]interface{}{alert1, alert3, alert4})
 	require.NoError(m.T(), err)
 
-	_, err = m.db.Client.Database(m.dbname).Collection("alerts").InsertOne(context.TODO(), alert3)
-	require.NoError(m.T(), err)
-	_, err = m.db.Client.Database(m.dbname).Collection("alerts").InsertOne(context.TODO(), alert4)
+	alerts, err := m.db.FindUnresolvedNoDataAlerts()
 	require.NoError(m.T(), err)
+	assert.Equal(m.T(), []model.Alert{alert3}, alerts)
+}
+
+func (m *MongodbSuite) TestUpdateNoDataAlert_Success() {
+	defer m.db.Client.Database(m.dbname).Collection("alerts").DeleteMany(context.TODO(), bson.M{})
+
+	alert := model.Alert{
+		AlertCode:               model.AlertCodeNoData,
+		AlertSeverity:           model.AlertSeverityWarning,
+		AlertAffectedTechnology: nil,
+		AlertCategory:           model.AlertCategoryAgent,
+		AlertStatus:             model.AlertStatusAck,
+		Date:                    utils.P("2019-11-05T18:02:03Z"),
+		Description:             "No data received from the host pippo-host in the last 0 day(s)",
+		OtherInfo: map[string]interface{}{
+			"hostname": "pippo-host",
+		},
+		ID: utils.Str2oid("5dd40bfb12f54dfda7b1c292"),
+	}
 
-	err = m.db.DeleteAllNoDataAlerts()
+	_, err := m.db.Client.Database(m.dbname).Collection("alerts").InsertOne(context.TODO(), alert)
 	require.NoError(m.T(), err)
 
-	// Check that there are no more AlertCodeNoData alerts
-	val, erro := m.db.Client.Database(m.dbname).Collection("alerts").
-		Find(context.TODO(), bson.M{"alertCode": model.AlertCodeNoData})
-	require.NoError(m.T(), erro)
-
-	res := make([]model.Alert, 0)
-	erro = val.All(context.TODO(), &res)
-	require.NoError(m.T(), erro)
-	require.Equal(m.T(), 0, len(res))
-
-	// Check that there's still alert1
-	val, erro = m.db.Client.Database(m.dbname).Collection("alerts").
-		Find(context.TODO(), bson.M{})
-	require.NoError(m.T(), erro)
-
-	res = make([]model.Alert, 0)
-	erro = val.All(context.TODO(), &res)
-	require.NoError(m.T(), erro)
-	require.Equal(m.T(), 1, len(res))
-	require.Equal(m.T(), alert1, (res)[0])
+	escalated := alert
+	escalated.AlertSeverity = model.AlertSeverityCritical
+	escalated.Description = "No data received from the host pippo-host in the last 1 day(s)"
+	escalated.OtherInfo = map[string]interface{}{
+		"hostname":        "pippo-host",
+		"freshnessPolicy": "production",
+	}
+	entry := model.AlertHistoryEntry{
+		Date:     utils.P("2019-11-06T18:02:03Z"),
+		Username: model.AlertSystemUsername,
+		Action:   model.AlertActionSeverityChange,
+		Severity: model.AlertSeverityCritical,
+	}
+
+	require.NoError(m.T(), m.db.UpdateNoDataAlert(escalated, &entry))
+
+	var actual model.Alert
+	require.NoError(m.T(), m.db.Client.Database(m.dbname).Collection("alerts").
+		FindOne(context.TODO(), bson.M{"_id": alert.ID}).Decode(&actual))
+
+	escalated.History = []model.AlertHistoryEntry{entry}
+	assert.Equal(m.T(), escalated, actual)
 }
 
-func (m *MongodbSuite) TestDeleteNoDataAlertByHost_Success() {
+func (m *MongodbSuite) TestResolveNoDataAlertsByHost_Success() {
 	defer m.db.Client.Database(m.dbname).Collection("alerts").DeleteMany(context.TODO(), bson.M{})
 
 	var alert1 model.Alert = model.Alert{
@@ -116,7 +138,7 @@ func (m *MongodbSuite) TestDeleteNoDataAlertByHost_Success() {
 		Date:                    utils.P("2019-11-05T18:02:03Z"),
 		Description:             "pippo",
 		OtherInfo: map[string]interface{}{
-			"hostname": "myhost",
+			"hostname": "pippo-host",
 		},
 		ID: utils.Str2oid("5dd40bfb12f54dfda7b1c291"),
 	}
@@ -141,35 +163,47 @@ func (m *MongodbSuite) TestDeleteNoDataAlertByHost_Success() {
 	_, err = m.db.Client.Database(m.dbname).Collection("alerts").InsertOne(context.TODO(), alert3)
 	require.NoError(m.T(), err)
 
-	m.T().Run("There is still alert3", func(t *testing.T) {
-		alert1Hostname := alert1.OtherInfo["hostname"].(string)
-		err = m.db.DeleteNoDataAlertByHost(alert1Hostname)
-		require.NoError(m.T(), err)
+	err = m.db.ResolveNoDataAlertsByHost("pippo-host", utils.P("2019-11-06T18:02:03Z"))
+	require.NoError(m.T(), err)
 
-		val, err2 := m.db.Client.Database(m.dbname).Collection("alerts").
-			Find(context.TODO(), bson.M{"alertCode": model.AlertCodeNoData})
-		require.NoError(m.T(), err2)
+	val, err := m.db.Client.Database(m.dbname).Collection("alerts").
+		Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	require.NoError(m.T(), err)
 
-		alerts := make([]model.Alert, 0)
-		err3 := val.All(context.TODO(), &alerts)
-		require.NoError(m.T(), err3)
+	alerts := make([]model.Alert, 0)
+	require.NoError(m.T(), val.All(context.TODO(), &alerts))
+	require.Equal(m.T(), 2, len(alerts))
+
+	assert.Equal(m.T(), alert1, alerts[0])
+
+	resolved := alert3
+	resolved.AlertStatus = model.AlertStatusResolved
+	resolved.History = []model.AlertHistoryEntry{
+		{
+			Date:     utils.P("2019-11-06T18:02:03Z"),
+			Username: model.AlertSystemUsername,
+			Action:   model.AlertActionStatusChange,
+			Status:   model.AlertStatusResolved,
+			Comment:  "Fresh data received from the host",
+		},
+	}
+	assert.Equal(m.T(), resolved, alerts[1])
 
-		require.Equal(m.T(), 1, len(alerts))
-		require.Equal(m.T(), alerts[0], alert3)
-	})
+	m.T().Run("Inactive host", func(t *testing.T) {
+		alert4 := alert3
+		alert4.ID = utils.Str2oid("5dd40bfb12f54dfda7b1c293")
+		alert4.OtherInfo = map[string]interface{}{"hostname": "pluto-host"}
+
+		_, err := m.db.Client.Database(m.dbname).Collection("alerts").InsertOne(context.TODO(), alert4)
+		require.NoError(t, err)
 
-	m.T().Run("There are no more alerts", func(t *testing.T) {
-		alert3Hostname := alert3.OtherInfo["hostname"].(string)
-		err = m.db.DeleteNoDataAlertByHost(alert3Hostname)
+		require.NoError(t, m.db.ResolveNoDataAlertsOfInactiveHost("pluto-host", utils.P("2019-11-07T18:02:03Z")))
 
-		require.NoError(m.T(), err)
-		val, err2 := m.db.Client.Database(m.dbname).Collection("alerts").
-			Find(context.TODO(), bson.M{"alertCode": model.AlertCodeNoData})
-		require.NoError(m.T(), err2)
+		var actual model.Alert
+		require.NoError(t, m.db.Client.Database(m.dbname).Collection("alerts").
+			FindOne(context.TODO(), bson.M{"_id": alert4.ID}).Decode(&actual))
 
-		alerts := make([]model.Alert, 0)
-		err3 := val.All(context.TODO(), &alerts)
-		require.NoError(m.T(), err3)
-		require.Equal(m.T(), 0, len(alerts))
+		assert.Equal(t, model.AlertStatusResolved, actual.AlertStatus)
+		assert.Equal(t, "The host isn't active anymore", actual.History[0].Comment)
 	})
 }
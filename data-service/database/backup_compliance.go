@@ -0,0 +1,50 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const backupComplianceCollection = "backup_compliance"
+
+// ReplaceBackupCompliance replace all the evaluations of the Oracle backups with the new ones
+func (md *MongoDatabase) ReplaceBackupCompliance(compliance []model.BackupCompliance) error {
+	collection := md.Client.Database(md.Config.Mongodb.DBName).Collection(backupComplianceCollection)
+
+	if _, err := collection.DeleteMany(context.TODO(), bson.M{}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if len(compliance) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(compliance))
+	for i := range compliance {
+		docs[i] = compliance[i]
+	}
+
+	if _, err := collection.InsertMany(context.TODO(), docs); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const capacityForecastsCollection = "capacity_forecasts"
+
+// FindArchivedHostDataCapacities return the archived hostdata of the hosts created from the date, rebuilt from their
+// deltas, with only the fields used to forecast the capacity: the filesystems and the sizes of the Oracle databases
+// and of their tablespaces
+func (md *MongoDatabase) FindArchivedHostDataCapacities(hostnames []string, from time.Time) ([]model.HostDataBE, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		Find(context.TODO(),
+			bson.M{
+				"archived":  true,
+				"hostname":  bson.M{"$in": hostnames},
+				"createdAt": bson.M{"$gte": from},
+			},
+			options.Find().SetSort(bson.D{{Key: "hostname", Value: 1}, {Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+	defer cur.Close(context.TODO())
+
+	hostdatas := make([]model.HostDataBE, 0)
+
+	var previous *model.HostDataBE
+
+	for cur.Next(context.TODO()) {
+		var hostdata model.HostDataBE
+		if err := cur.Decode(&hostdata); err != nil {
+			return nil, utils.NewError(err, "Decode ERROR")
+		}
+
+		full := &hostdata
+
+		switch {
+		case hostdata.HistoryDelta == nil:
+		case previous != nil && previous.ID == hostdata.HistoryDelta.BaseID:
+			full, err = hostdata.HistoryDelta.Apply(*previous)
+			if err != nil {
+				return nil, utils.NewErrorf("Can't rebuild hostdata %s: %w", hostdata.ID.Hex(), err)
+			}
+		default:
+			full, err = md.FindFullHostData(hostdata.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		previous = full
+
+		hostdatas = append(hostdatas, hostDataCapacities(*full))
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	return hostdatas, nil
+}
+
+// hostDataCapacities return the hostdata with only the fields used to forecast the capacity
+func hostDataCapacities(hostdata model.HostDataBE) model.HostDataBE {
+	capacities := model.HostDataBE{
+		ID:          hostdata.ID,
+		Hostname:    hostdata.Hostname,
+		CreatedAt:   hostdata.CreatedAt,
+		Archived:    hostdata.Archived,
+		Filesystems: hostdata.Filesystems,
+	}
+
+	if hostdata.Features.Oracle == nil || hostdata.Features.Oracle.Database == nil {
+		return capacities
+	}
+
+	databases := make([]model.OracleDatabase, 0, len(hostdata.Features.Oracle.Database.Databases))
+	for _, db := range hostdata.Features.Oracle.Database.Databases {
+		databases = append(databases, model.OracleDatabase{
+			Name:         db.Name,
+			DatafileSize: db.DatafileSize,
+			SegmentsSize: db.SegmentsSize,
+			Tablespaces:  db.Tablespaces,
+		})
+	}
+
+	capacities.Features.Oracle = &model.OracleFeature{
+		Database: &model.OracleDatabaseFeature{Databases: databases},
+	}
+
+	return capacities
+}
+
+// ReplaceCapacityForecasts replace all the capacity forecasts with the new ones
+func (md *MongoDatabase) ReplaceCapacityForecasts(forecasts []model.CapacityForecast) error {
+	collection := md.Client.Database(md.Config.Mongodb.DBName).Collection(capacityForecastsCollection)
+
+	if _, err := collection.DeleteMany(context.TODO(), bson.M{}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if len(forecasts) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(forecasts))
+	for i := range forecasts {
+		docs[i] = forecasts[i]
+	}
+
+	if _, err := collection.InsertMany(context.TODO(), docs); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestFindArchivedHostDataCapacities() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+
+	snapshot := model.HostDataBE{
+		ID:          utils.Str2oid("5ef9d239a1d25d1e8703c4e3"),
+		Hostname:    "foobar",
+		Archived:    true,
+		CreatedAt:   utils.P("2020-12-05T14:02:03Z"),
+		Filesystems: []model.Filesystem{{MountedOn: "/", Size: 100, UsedSpace: 10, AvailableSpace: 90}},
+	}
+	second := snapshot
+	second.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4e4")
+	second.CreatedAt = utils.P("2020-12-06T14:02:03Z")
+	second.Filesystems = []model.Filesystem{{MountedOn: "/", Size: 100, UsedSpace: 20, AvailableSpace: 80}}
+
+	third := second
+	third.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4e5")
+	third.CreatedAt = utils.P("2020-12-07T14:02:03Z")
+	third.Filesystems = []model.Filesystem{{MountedOn: "/", Size: 100, UsedSpace: 30, AvailableSpace: 70}}
+
+	for _, hd := range []model.HostDataBE{snapshot, second, third} {
+		require.NoError(m.T(), m.db.InsertHostData(hd))
+	}
+
+	delta, err := model.NewHostDataHistoryDelta(snapshot, second, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), m.db.CompactHostData(second, *delta))
+
+	delta, err = model.NewHostDataHistoryDelta(second, third, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), m.db.CompactHostData(third, *delta))
+
+	m.T().Run("From the snapshot", func(t *testing.T) {
+		actual, err := m.db.FindArchivedHostDataCapacities([]string{"foobar"}, snapshot.CreatedAt)
+		require.NoError(t, err)
+
+		require.Len(t, actual, 3)
+		for i, hd := range []model.HostDataBE{snapshot, second, third} {
+			assert.Equal(t, hd.ID, actual[i].ID)
+			assert.Equal(t, hd.Filesystems, actual[i].Filesystems)
+		}
+	})
+
+	m.T().Run("From a delta", func(t *testing.T) {
+		actual, err := m.db.FindArchivedHostDataCapacities([]string{"foobar"}, third.CreatedAt)
+		require.NoError(t, err)
+
+		require.Len(t, actual, 1)
+		assert.Equal(t, third.ID, actual[0].ID)
+		assert.Equal(t, third.Filesystems, actual[0].Filesystems)
+	})
+}
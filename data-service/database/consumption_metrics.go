@@ -0,0 +1,194 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const (
+	consumptionMetricsCollection      = "consumption_metrics"
+	dailyConsumptionMetricsCollection = "consumption_metrics_daily"
+)
+
+// InsertConsumptionMetrics insert the samples newer than the last stored one of their series,
+// since the agents send again the samples of the previous days
+func (md *MongoDatabase) InsertConsumptionMetrics(metrics []model.ConsumptionMetric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	ctx := context.TODO()
+	collection := md.Client.Database(md.Config.Mongodb.DBName).Collection(consumptionMetricsCollection)
+
+	hostnames := make([]string, 0, 1)
+	for _, m := range metrics {
+		if !utils.Contains(hostnames, m.Meta.Hostname) {
+			hostnames = append(hostnames, m.Meta.Hostname)
+		}
+	}
+
+	cur, err := collection.Aggregate(ctx,
+		mu.MAPipeline(
+			mu.APMatch(bson.M{"meta.hostname": bson.M{"$in": hostnames}}),
+			mu.APGroup(bson.M{
+				"_id":  "$meta",
+				"last": bson.M{"$max": "$timestamp"},
+			}),
+		),
+	)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	var series []struct {
+		Meta model.ConsumptionMetricMeta `bson:"_id"`
+		Last time.Time                   `bson:"last"`
+	}
+	if err := cur.All(ctx, &series); err != nil {
+		return utils.NewError(err, "Decode ERROR")
+	}
+
+	lasts := make(map[model.ConsumptionMetricMeta]time.Time, len(series))
+	for _, s := range series {
+		lasts[s.Meta] = s.Last
+	}
+
+	docs := make([]interface{}, 0, len(metrics))
+
+	for _, m := range metrics {
+		if last, ok := lasts[m.Meta]; ok && !m.Timestamp.After(last) {
+			continue
+		}
+
+		docs = append(docs, m)
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if _, err := collection.InsertMany(ctx, docs); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// FindLastDailyConsumptionMetricDate return the day of the last daily rollup, a zero time if there are none
+func (md *MongoDatabase) FindLastDailyConsumptionMetricDate() (time.Time, error) {
+	var metric model.ConsumptionMetric
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection(dailyConsumptionMetricsCollection).
+		FindOne(context.TODO(), bson.M{}, options.FindOne().SetSort(bson.M{"timestamp": -1})).
+		Decode(&metric)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, utils.NewError(err, "DB ERROR")
+	}
+
+	return metric.Timestamp, nil
+}
+
+// DownsampleConsumptionMetrics roll up by day the samples between from and until, the days must be completed,
+// and return the number of the daily metrics inserted
+func (md *MongoDatabase) DownsampleConsumptionMetrics(from, until time.Time) (int, error) {
+	ctx := context.TODO()
+	db := md.Client.Database(md.Config.Mongodb.DBName)
+
+	cur, err := db.Collection(consumptionMetricsCollection).Aggregate(ctx,
+		mu.MAPipeline(
+			mu.APMatch(bson.M{"timestamp": bson.M{"$gte": from, "$lt": until}}),
+			mu.APGroup(bson.M{
+				"_id": bson.M{
+					"meta": "$meta",
+					"day":  bson.M{"$dateTrunc": bson.M{"date": "$timestamp", "unit": "day"}},
+				},
+				"cpuAvg":  bson.M{"$avg": "$cpuAvg"},
+				"cpuMax":  bson.M{"$max": "$cpuMax"},
+				"iopsAvg": bson.M{"$avg": "$iopsAvg"},
+				"iopsMax": bson.M{"$max": "$iopsMax"},
+				"iombAvg": bson.M{"$avg": "$iombAvg"},
+				"iombMax": bson.M{"$max": "$iombMax"},
+				"samples": bson.M{"$sum": 1},
+			}),
+			mu.APProject(bson.M{
+				"_id":       0,
+				"timestamp": "$_id.day",
+				"meta":      "$_id.meta",
+				"timeEnd":   bson.M{"$dateAdd": bson.M{"startDate": "$_id.day", "unit": "day", "amount": 1}},
+				"cpuAvg":    1,
+				"cpuMax":    1,
+				"iopsAvg":   1,
+				"iopsMax":   1,
+				"iombAvg":   1,
+				"iombMax":   1,
+				"samples":   1,
+			}),
+		),
+	)
+	if err != nil {
+		return 0, utils.NewError(err, "DB ERROR")
+	}
+
+	metrics := make([]model.ConsumptionMetric, 0)
+	if err := cur.All(ctx, &metrics); err != nil {
+		return 0, utils.NewError(err, "Decode ERROR")
+	}
+
+	if len(metrics) == 0 {
+		return 0, nil
+	}
+
+	docs := make([]interface{}, len(metrics))
+	for i := range metrics {
+		docs[i] = metrics[i]
+	}
+
+	if _, err := db.Collection(dailyConsumptionMetricsCollection).InsertMany(ctx, docs); err != nil {
+		return 0, utils.NewError(err, "DB ERROR")
+	}
+
+	return len(metrics), nil
+}
+
+// ApplyConsumptionMetricsRetention set the days after which the samples and the daily rollups expire
+func (md *MongoDatabase) ApplyConsumptionMetricsRetention(rawDays, dailyDays int) error {
+	db := md.Client.Database(md.Config.Mongodb.DBName)
+
+	for collection, days := range map[string]int{
+		consumptionMetricsCollection:      rawDays,
+		dailyConsumptionMetricsCollection: dailyDays,
+	} {
+		if err := db.RunCommand(context.TODO(), bson.D{
+			{Key: "collMod", Value: collection},
+			{Key: "expireAfterSeconds", Value: int64(days) * 24 * 60 * 60},
+		}).Err(); err != nil {
+			return utils.NewError(err, "DB ERROR")
+		}
+	}
+
+	return nil
+}
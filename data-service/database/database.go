@@ -36,6 +36,8 @@ type MongoDatabaseInterface interface {
 	Init()
 	DismissHost(hostname string) error
 	InsertHostData(hostData model.HostDataBE) error
+	// ArchiveAndInsertHostData archive the current hostdata of the host and insert the new one atomically
+	ArchiveAndInsertHostData(hostData model.HostDataBE) error
 	GetCurrentHostnames() ([]string, error)
 	// FindOldCurrentHostnames return the list of current hosts names that haven't sent hostdata after time t
 	FindOldCurrentHostnames(t time.Time) ([]string, error)
@@ -43,11 +45,73 @@ type MongoDatabaseInterface interface {
 	// FindOldArchivedHosts return the list of archived hosts older than t
 	FindOldArchivedHosts(t time.Time) ([]primitive.ObjectID, error)
 	GetActiveHostdata() ([]model.HostDataBE, error)
+	// DeleteHostData delete the hostdata, storing as full snapshot the following one if it depends on it
 	DeleteHostData(id primitive.ObjectID) error
+	// FindFullHostData return the hostdata, rebuilt from the deltas if it's stored as delta
+	FindFullHostData(id primitive.ObjectID) (*model.HostDataBE, error)
+	// CompactHostData replace the archived hostdata with its delta from the previous one
+	CompactHostData(hostdata model.HostDataBE, delta model.HostDataHistoryDelta) error
 	HistoricizeLicensesCompliance(licenses []dto.LicenseCompliance) error
-
-	DeleteNoDataAlertByHost(hostname string) error
-	DeleteAllNoDataAlerts() error
+	// FindCurrentHostData return the current hostdata of the hosts, filtered by location, environment and technology
+	FindCurrentHostData(hostnames []string, location, environment, technology string) ([]model.HostDataBE, error)
+	// ReplaceHostData replace the current hostdata with the same id
+	ReplaceHostData(hostdata model.HostDataBE) error
+	// FindHostIdentityByAlias return the identity of the host that has name as alias, or nil if there isn't any
+	FindHostIdentityByAlias(name string) (*model.HostIdentity, error)
+	ListHostIdentities() ([]model.HostIdentity, error)
+	// FindHostMetadata return the metadata of the host, or nil if it hasn't any
+	FindHostMetadata(hostname string) (*model.HostMetadata, error)
+	// SaveHostCmdbRecord save the CMDB record in the metadata of the host
+	SaveHostCmdbRecord(hostname string, record model.CmdbRecord) error
+	// RemoveStaleCmdbRecords remove the records of the CMDB source not synchronised since syncedBefore
+	RemoveStaleCmdbRecords(source string, syncedBefore time.Time) error
+	// UpdateCurrentHostDataEnvironmentAndLocation change environment and location of the current hostdata of the host
+	UpdateCurrentHostDataEnvironmentAndLocation(hostname, environment, location string) error
+
+	ResolveNoDataAlertsByHost(hostname string, date time.Time) error
+	// FindUnresolvedNoDataAlerts return the NO_DATA alerts not resolved yet, dismissed ones included
+	FindUnresolvedNoDataAlerts() ([]model.Alert, error)
+	// UpdateNoDataAlert update severity, description and otherInfo of the NO_DATA alert
+	UpdateNoDataAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error
+	// ResolveNoDataAlertsOfInactiveHost resolve the open NO_DATA alerts of a host dismissed or removed
+	ResolveNoDataAlertsOfInactiveHost(hostname string, date time.Time) error
+	// FindUnresolvedCapacityAlerts return the alerts of projected capacity exhaustion not resolved yet, dismissed ones included
+	FindUnresolvedCapacityAlerts() ([]model.Alert, error)
+	// UpdateCapacityAlert update severity, description and otherInfo of the alert of projected capacity exhaustion
+	UpdateCapacityAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error
+	// ResolveCapacityAlert resolve the alert of projected capacity exhaustion, if it's still open
+	ResolveCapacityAlert(id primitive.ObjectID, date time.Time, comment string) error
+	// FindArchivedHostDataCapacities return the archived hostdata of the hosts created from the date,
+	// with only the fields used to forecast the capacity
+	FindArchivedHostDataCapacities(hostnames []string, from time.Time) ([]model.HostDataBE, error)
+	// ReplaceCapacityForecasts replace all the capacity forecasts with the new ones
+	ReplaceCapacityForecasts(forecasts []model.CapacityForecast) error
+	// FindUnresolvedBackupAlerts return the alerts of backup policy violations not resolved yet, dismissed ones included
+	FindUnresolvedBackupAlerts() ([]model.Alert, error)
+	// UpdateBackupAlert update severity, description and otherInfo of the alert of a backup policy violation
+	UpdateBackupAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error
+	// ResolveBackupAlert resolve the alert of a backup policy violation, if it's still open
+	ResolveBackupAlert(id primitive.ObjectID, date time.Time, comment string) error
+	// ReplaceBackupCompliance replace all the evaluations of the Oracle backups with the new ones
+	ReplaceBackupCompliance(compliance []model.BackupCompliance) error
+	// FindUnresolvedPatchAlerts return the ORACLE_PATCH_OUTDATED alerts not resolved yet, dismissed ones included
+	FindUnresolvedPatchAlerts() ([]model.Alert, error)
+	// UpdatePatchAlert update severity, description and otherInfo of the ORACLE_PATCH_OUTDATED alert
+	UpdatePatchAlert(alert model.Alert, historyEntry *model.AlertHistoryEntry) error
+	// ResolvePatchAlert resolve the ORACLE_PATCH_OUTDATED alert, if it's still open
+	ResolvePatchAlert(id primitive.ObjectID, date time.Time, comment string) error
+
+	// InsertConsumptionMetrics insert the samples newer than the last stored one of their series
+	InsertConsumptionMetrics(metrics []model.ConsumptionMetric) error
+	// FindLastDailyConsumptionMetricDate return the day of the last daily rollup, a zero time if there are none
+	FindLastDailyConsumptionMetricDate() (time.Time, error)
+	// DownsampleConsumptionMetrics roll up by day the samples between from and until and return the number of the daily metrics
+	DownsampleConsumptionMetrics(from, until time.Time) (int, error)
+	// ApplyConsumptionMetricsRetention set the days after which the samples and the daily rollups expire
+	ApplyConsumptionMetricsRetention(rawDays, dailyDays int) error
+
+	// GetOraclePatchCatalogue return the Oracle patch releases and the support of the Oracle versions
+	GetOraclePatchCatalogue() (*model.OraclePatchCatalogue, error)
 	// FindMostRecentHostDataOlderThan return the most recest hostdata that is older than t
 	FindMostRecentHostDataOlderThan(hostname string, t time.Time) (*model.HostDataBE, error)
 	GetHostnames() ([]string, error)
@@ -57,6 +121,16 @@ type MongoDatabaseInterface interface {
 	FindExadataByRackID(rackID string) (*model.OracleExadataInstance, error)
 	AddExadata(exadata model.OracleExadataInstance) error
 	UpdateExadata(exadata model.OracleExadataInstance) error
+
+	EnqueueHostDataIngestion(ingestion model.HostDataIngestion) (*model.HostDataIngestion, bool, error)
+	ClaimHostDataIngestion(now, leaseExpiresAt time.Time) (*model.HostDataIngestion, error)
+	CompleteHostDataIngestion(id primitive.ObjectID, finishedAt time.Time) error
+	RetryHostDataIngestion(id primitive.ObjectID, nextAttemptAt time.Time, lastError string) error
+	FailHostDataIngestion(id primitive.ObjectID, finishedAt time.Time, lastError string) error
+	AddHostDataIngestionThrownAlert(id primitive.ObjectID, key string) error
+	SetHostDataIngestionStored(id primitive.ObjectID, storedAt time.Time) error
+	DeleteHostDataIngestionsDoneBefore(t time.Time) error
+	FindHostDataIngestion(id primitive.ObjectID) (*model.HostDataIngestion, error)
 }
 
 type MongoDatabase struct {
@@ -0,0 +1,62 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const hostIdentitiesCollection = "host_identities"
+
+// FindHostIdentityByAlias return the identity of the host that has name as alias, or nil if there isn't any
+func (md *MongoDatabase) FindHostIdentityByAlias(name string) (*model.HostIdentity, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostIdentitiesCollection).
+		FindOne(context.TODO(), bson.M{"aliases": name})
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var identity model.HostIdentity
+	if err := res.Decode(&identity); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return &identity, nil
+}
+
+// ListHostIdentities return the identities of the hosts
+func (md *MongoDatabase) ListHostIdentities() ([]model.HostIdentity, error) {
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostIdentitiesCollection).
+		Find(context.TODO(), bson.D{})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	identities := make([]model.HostIdentity, 0)
+	if err := cur.All(context.TODO(), &identities); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return identities, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const hostMetadataCollection = "host_metadata"
+
+// FindHostMetadata return the metadata of the host, or nil if it hasn't any
+func (md *MongoDatabase) FindHostMetadata(hostname string) (*model.HostMetadata, error) {
+	res := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostMetadataCollection).
+		FindOne(context.TODO(), bson.M{"hostname": hostname})
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if res.Err() != nil {
+		return nil, utils.NewError(res.Err(), "DB ERROR")
+	}
+
+	var metadata model.HostMetadata
+	if err := res.Decode(&metadata); err != nil {
+		return nil, utils.NewError(err, "DECODE ERROR")
+	}
+
+	return &metadata, nil
+}
+
+// SaveHostCmdbRecord save the CMDB record in the metadata of the host
+func (md *MongoDatabase) SaveHostCmdbRecord(hostname string, record model.CmdbRecord) error {
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostMetadataCollection).
+		UpdateOne(context.TODO(),
+			bson.M{"hostname": hostname},
+			bson.M{"$set": bson.M{"cmdb": record}},
+			options.Update().SetUpsert(true)); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// RemoveStaleCmdbRecords remove the records of the CMDB source not synchronised since syncedBefore,
+// that aren't in the CMDB anymore
+func (md *MongoDatabase) RemoveStaleCmdbRecords(source string, syncedBefore time.Time) error {
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostMetadataCollection).
+		UpdateMany(context.TODO(),
+			bson.M{"cmdb.source": source, "cmdb.syncedAt": bson.M{"$lt": syncedBefore}},
+			bson.M{"$unset": bson.M{"cmdb": ""}}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// UpdateCurrentHostDataEnvironmentAndLocation change environment and location of the current hostdata of the host.
+// The empty values aren't changed
+func (md *MongoDatabase) UpdateCurrentHostDataEnvironmentAndLocation(hostname, environment, location string) error {
+	set := bson.M{}
+
+	if environment != "" {
+		set["environment"] = environment
+	}
+
+	if location != "" {
+		set["location"] = location
+	}
+
+	if len(set) == 0 {
+		return nil
+	}
+
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		UpdateMany(context.TODO(),
+			bson.M{"hostname": hostname, "archived": false},
+			bson.M{"$set": set}); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestHostCmdbRecords() {
+	defer m.db.Client.Database(m.dbname).Collection(hostMetadataCollection).DeleteMany(context.TODO(), bson.M{})
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+
+	record := model.CmdbRecord{
+		Source:      "servicenow",
+		Hostname:    "foobar.example.org",
+		Environment: "PRD",
+		Location:    "Italy",
+		SyncedAt:    utils.P("2023-05-01T10:00:00Z").UTC(),
+	}
+
+	require.NoError(m.T(), m.db.SaveHostCmdbRecord("foobar", record))
+
+	actual, err := m.db.FindHostMetadata("foobar")
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), model.HostMetadata{Hostname: "foobar", Cmdb: &record}, *actual)
+
+	actual, err = m.db.FindHostMetadata("barfoo")
+	require.NoError(m.T(), err)
+	assert.Nil(m.T(), actual)
+
+	require.NoError(m.T(), m.db.RemoveStaleCmdbRecords("servicenow", utils.P("2023-05-01T10:00:00Z")))
+
+	actual, err = m.db.FindHostMetadata("foobar")
+	require.NoError(m.T(), err)
+	assert.NotNil(m.T(), actual.Cmdb)
+
+	require.NoError(m.T(), m.db.RemoveStaleCmdbRecords("servicenow", utils.P("2023-05-02T10:00:00Z")))
+
+	actual, err = m.db.FindHostMetadata("foobar")
+	require.NoError(m.T(), err)
+	assert.Nil(m.T(), actual.Cmdb)
+
+	_, err = m.db.Client.Database(m.dbname).Collection("hosts").InsertMany(context.TODO(), []interface{}{
+		bson.M{"hostname": "foobar", "archived": true, "environment": "TST", "location": "Germany"},
+		bson.M{"hostname": "foobar", "archived": false, "environment": "TST", "location": "Germany"},
+	})
+	require.NoError(m.T(), err)
+
+	require.NoError(m.T(), m.db.UpdateCurrentHostDataEnvironmentAndLocation("foobar", "PRD", ""))
+
+	var hostdatas []bson.M
+	cur, err := m.db.Client.Database(m.dbname).Collection("hosts").Find(context.TODO(), bson.M{"hostname": "foobar"})
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), cur.All(context.TODO(), &hostdatas))
+
+	for _, hostdata := range hostdatas {
+		expected := "TST"
+		if hostdata["archived"] == false {
+			expected = "PRD"
+		}
+
+		assert.Equal(m.T(), expected, hostdata["environment"])
+		assert.Equal(m.T(), "Germany", hostdata["location"])
+	}
+}
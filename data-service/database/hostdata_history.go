@@ -0,0 +1,100 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// FindFullHostData return the hostdata with the id, rebuilt from the chain of deltas if it's stored as delta
+func (md *MongoDatabase) FindFullHostData(id primitive.ObjectID) (*model.HostDataBE, error) {
+	var hostdata model.HostDataBE
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		FindOne(context.TODO(), bson.M{"_id": id}).Decode(&hostdata)
+	if err == mongo.ErrNoDocuments {
+		return nil, utils.ErrHostNotFound
+	}
+
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	if hostdata.HistoryDelta == nil {
+		return &hostdata, nil
+	}
+
+	base, err := md.FindFullHostData(hostdata.HistoryDelta.BaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	full, err := hostdata.HistoryDelta.Apply(*base)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't rebuild hostdata %s: %w", id.Hex(), err)
+	}
+
+	return full, nil
+}
+
+// CompactHostData replace the archived hostdata with its delta from the previous one
+func (md *MongoDatabase) CompactHostData(hostdata model.HostDataBE, delta model.HostDataHistoryDelta) error {
+	compacted, err := model.CompactHostData(hostdata, delta)
+	if err != nil {
+		return utils.NewError(err, "Can't compact hostdata")
+	}
+
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		ReplaceOne(context.TODO(), bson.M{"_id": hostdata.ID}, compacted); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// materializeDependentHostData store as full snapshot the hostdata that is a delta from the one with the id,
+// so the latter can be deleted
+func (md *MongoDatabase) materializeDependentHostData(id primitive.ObjectID) error {
+	var dependent model.HostDataBE
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		FindOne(context.TODO(), bson.M{"historyDelta.baseID": id}).Decode(&dependent)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	full, err := md.FindFullHostData(dependent.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		ReplaceOne(context.TODO(), bson.M{"_id": full.ID}, full); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
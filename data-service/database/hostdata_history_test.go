@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestHostDataHistory() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+
+	snapshot := model.HostDataBE{
+		ID:        utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		Hostname:  "foobar",
+		Archived:  true,
+		CreatedAt: utils.P("2020-12-05T14:02:03Z"),
+		Info:      model.Host{MemoryTotal: 32},
+	}
+	second := snapshot
+	second.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d4")
+	second.CreatedAt = utils.P("2020-12-06T14:02:03Z")
+	second.Info.MemoryTotal = 16
+
+	third := second
+	third.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d5")
+	third.CreatedAt = utils.P("2020-12-07T14:02:03Z")
+	third.Info.MemoryTotal = 8
+
+	for _, hd := range []model.HostDataBE{snapshot, second, third} {
+		require.NoError(m.T(), m.db.InsertHostData(hd))
+	}
+
+	delta, err := model.NewHostDataHistoryDelta(snapshot, second, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), m.db.CompactHostData(second, *delta))
+
+	delta, err = model.NewHostDataHistoryDelta(second, third, snapshot.CreatedAt)
+	require.NoError(m.T(), err)
+	require.NoError(m.T(), m.db.CompactHostData(third, *delta))
+
+	actual, err := m.db.FindFullHostData(third.ID)
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), third, *actual)
+
+	require.NoError(m.T(), m.db.DeleteHostData(snapshot.ID))
+
+	actual, err = m.db.FindFullHostData(third.ID)
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), third, *actual)
+
+	_, err = m.db.FindFullHostData(snapshot.ID)
+	assert.ErrorIs(m.T(), err, utils.ErrHostNotFound)
+}
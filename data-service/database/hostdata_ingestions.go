@@ -0,0 +1,185 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/amreo/mu"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const hostDataIngestionsCollection = "hostdata_ingestions"
+
+// EnqueueHostDataIngestion insert the ingestion in the queue, unless another one with the same idempotency key
+// is already there. It returns the ingestion in the queue and true if it's the new one
+func (md *MongoDatabase) EnqueueHostDataIngestion(ingestion model.HostDataIngestion) (*model.HostDataIngestion, bool, error) {
+	collection := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostDataIngestionsCollection)
+	filter := bson.M{"idempotencyKey": ingestion.IdempotencyKey}
+
+	var out model.HostDataIngestion
+
+	err := collection.FindOneAndUpdate(context.TODO(),
+		filter,
+		bson.M{"$setOnInsert": ingestion},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&out)
+	if mongo.IsDuplicateKeyError(err) {
+		// a concurrent request has inserted the same ingestion
+		err = collection.FindOne(context.TODO(), filter).Decode(&out)
+	}
+
+	if err != nil {
+		return nil, false, utils.NewError(err, "DB ERROR")
+	}
+
+	return &out, out.ID == ingestion.ID, nil
+}
+
+// ClaimHostDataIngestion take the next ingestion ready to be processed, including the ones abandoned
+// by a worker, and lock it until leaseExpiresAt. It returns nil if the queue is empty
+func (md *MongoDatabase) ClaimHostDataIngestion(now, leaseExpiresAt time.Time) (*model.HostDataIngestion, error) {
+	var out model.HostDataIngestion
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostDataIngestionsCollection).FindOneAndUpdate(
+		context.TODO(),
+		bson.M{
+			"$or": bson.A{
+				bson.M{"status": model.HostDataIngestionStatusQueued, "nextAttemptAt": mu.QOLessThanOrEqual(now)},
+				bson.M{"status": model.HostDataIngestionStatusProcessing, "leaseExpiresAt": mu.QOLessThanOrEqual(now)},
+			},
+		},
+		bson.M{
+			"$set": bson.M{
+				"status":         model.HostDataIngestionStatusProcessing,
+				"leaseExpiresAt": leaseExpiresAt,
+			},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&out)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	return &out, nil
+}
+
+// CompleteHostDataIngestion mark the ingestion as successfully processed
+func (md *MongoDatabase) CompleteHostDataIngestion(id primitive.ObjectID, finishedAt time.Time) error {
+	return md.updateHostDataIngestion(id, bson.M{
+		"$set": bson.M{
+			"status":     model.HostDataIngestionStatusDone,
+			"finishedAt": finishedAt,
+		},
+		"$unset": bson.M{"leaseExpiresAt": "", "lastError": ""},
+	})
+}
+
+// RetryHostDataIngestion put back the ingestion in the queue, to be processed again after nextAttemptAt
+func (md *MongoDatabase) RetryHostDataIngestion(id primitive.ObjectID, nextAttemptAt time.Time, lastError string) error {
+	return md.updateHostDataIngestion(id, bson.M{
+		"$set": bson.M{
+			"status":        model.HostDataIngestionStatusQueued,
+			"nextAttemptAt": nextAttemptAt,
+			"lastError":     lastError,
+		},
+		"$unset": bson.M{"leaseExpiresAt": ""},
+	})
+}
+
+// FailHostDataIngestion move the ingestion to the dead letter and release its idempotency key
+func (md *MongoDatabase) FailHostDataIngestion(id primitive.ObjectID, finishedAt time.Time, lastError string) error {
+	return md.updateHostDataIngestion(id, bson.M{
+		"$set": bson.M{
+			"status":     model.HostDataIngestionStatusFailed,
+			"finishedAt": finishedAt,
+			"lastError":  lastError,
+		},
+		"$unset": bson.M{"leaseExpiresAt": "", "idempotencyKey": ""},
+	})
+}
+
+// AddHostDataIngestionThrownAlert record that the ingestion has thrown the alert with the key
+func (md *MongoDatabase) AddHostDataIngestionThrownAlert(id primitive.ObjectID, key string) error {
+	return md.updateHostDataIngestion(id, bson.M{
+		"$addToSet": bson.M{"thrownAlerts": key},
+	})
+}
+
+// SetHostDataIngestionStored record that the ingestion has stored its hostdata
+func (md *MongoDatabase) SetHostDataIngestionStored(id primitive.ObjectID, storedAt time.Time) error {
+	return md.updateHostDataIngestion(id, bson.M{
+		"$set": bson.M{"storedAt": storedAt},
+	})
+}
+
+func (md *MongoDatabase) updateHostDataIngestion(id primitive.ObjectID, update bson.M) error {
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostDataIngestionsCollection).
+		UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.MatchedCount != 1 {
+		return utils.ErrHostDataIngestionNotFound
+	}
+
+	return nil
+}
+
+// DeleteHostDataIngestionsDoneBefore delete the ingestions successfully processed before t
+func (md *MongoDatabase) DeleteHostDataIngestionsDoneBefore(t time.Time) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostDataIngestionsCollection).
+		DeleteMany(context.TODO(), bson.M{
+			"status":     model.HostDataIngestionStatusDone,
+			"finishedAt": mu.QOLessThan(t),
+		})
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+// FindHostDataIngestion return the ingestion with the id
+func (md *MongoDatabase) FindHostDataIngestion(id primitive.ObjectID) (*model.HostDataIngestion, error) {
+	var out model.HostDataIngestion
+
+	err := md.Client.Database(md.Config.Mongodb.DBName).Collection(hostDataIngestionsCollection).
+		FindOne(context.TODO(), bson.M{"_id": id}).Decode(&out)
+	if err == mongo.ErrNoDocuments {
+		return nil, utils.ErrHostDataIngestionNotFound
+	}
+
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func (m *MongodbSuite) TestHostDataIngestionsQueue() {
+	defer m.db.Client.Database(m.dbname).Collection(hostDataIngestionsCollection).DeleteMany(context.TODO(), bson.M{})
+
+	ingestion := model.HostDataIngestion{
+		ID:             utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		IdempotencyKey: "foobar:abc",
+		Hostname:       "foobar",
+		PayloadHash:    "abc",
+		Status:         model.HostDataIngestionStatusQueued,
+		CreatedAt:      utils.P("2020-12-05T14:02:03Z"),
+		NextAttemptAt:  utils.P("2020-12-05T14:02:03Z"),
+		HostData:       model.HostDataBE{Hostname: "foobar"},
+	}
+
+	queued, created, err := m.db.EnqueueHostDataIngestion(ingestion)
+	require.NoError(m.T(), err)
+	assert.True(m.T(), created)
+	assert.Equal(m.T(), ingestion.ID, queued.ID)
+
+	duplicate := ingestion
+	duplicate.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d4")
+
+	queued, created, err = m.db.EnqueueHostDataIngestion(duplicate)
+	require.NoError(m.T(), err)
+	assert.False(m.T(), created)
+	assert.Equal(m.T(), ingestion.ID, queued.ID)
+
+	claimed, err := m.db.ClaimHostDataIngestion(utils.P("2020-12-05T14:01:00Z"), utils.P("2020-12-05T14:11:00Z"))
+	require.NoError(m.T(), err)
+	assert.Nil(m.T(), claimed)
+
+	claimed, err = m.db.ClaimHostDataIngestion(utils.P("2020-12-05T14:03:00Z"), utils.P("2020-12-05T14:13:00Z"))
+	require.NoError(m.T(), err)
+	require.NotNil(m.T(), claimed)
+	assert.Equal(m.T(), model.HostDataIngestionStatusProcessing, claimed.Status)
+	assert.Equal(m.T(), 1, claimed.Attempts)
+	assert.Equal(m.T(), "foobar", claimed.HostData.Hostname)
+
+	claimed, err = m.db.ClaimHostDataIngestion(utils.P("2020-12-05T14:04:00Z"), utils.P("2020-12-05T14:14:00Z"))
+	require.NoError(m.T(), err)
+	assert.Nil(m.T(), claimed)
+
+	// the lease is expired
+	claimed, err = m.db.ClaimHostDataIngestion(utils.P("2020-12-05T14:14:00Z"), utils.P("2020-12-05T14:24:00Z"))
+	require.NoError(m.T(), err)
+	require.NotNil(m.T(), claimed)
+	assert.Equal(m.T(), 2, claimed.Attempts)
+
+	require.NoError(m.T(), m.db.AddHostDataIngestionThrownAlert(ingestion.ID, "alert1"))
+	require.NoError(m.T(), m.db.AddHostDataIngestionThrownAlert(ingestion.ID, "alert1"))
+	require.NoError(m.T(), m.db.RetryHostDataIngestion(ingestion.ID, utils.P("2020-12-05T15:00:00Z"), "MockError"))
+
+	actual, err := m.db.FindHostDataIngestion(ingestion.ID)
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), model.HostDataIngestionStatusQueued, actual.Status)
+	assert.Equal(m.T(), "MockError", actual.LastError)
+	assert.Equal(m.T(), []string{"alert1"}, actual.ThrownAlerts)
+	assert.Nil(m.T(), actual.StoredAt)
+
+	require.NoError(m.T(), m.db.SetHostDataIngestionStored(ingestion.ID, utils.P("2020-12-05T15:00:00Z")))
+
+	actual, err = m.db.FindHostDataIngestion(ingestion.ID)
+	require.NoError(m.T(), err)
+	require.NotNil(m.T(), actual.StoredAt)
+	assert.Equal(m.T(), utils.P("2020-12-05T15:00:00Z"), actual.StoredAt.UTC())
+
+	require.NoError(m.T(), m.db.FailHostDataIngestion(ingestion.ID, utils.P("2020-12-05T15:00:00Z"), "MockError"))
+
+	actual, err = m.db.FindHostDataIngestion(ingestion.ID)
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), model.HostDataIngestionStatusFailed, actual.Status)
+	assert.Empty(m.T(), actual.IdempotencyKey)
+
+	// the key of the failed ingestion is released
+	queued, created, err = m.db.EnqueueHostDataIngestion(duplicate)
+	require.NoError(m.T(), err)
+	assert.True(m.T(), created)
+
+	require.NoError(m.T(), m.db.CompleteHostDataIngestion(queued.ID, utils.P("2020-12-05T15:00:00Z")))
+	require.NoError(m.T(), m.db.DeleteHostDataIngestionsDoneBefore(utils.P("2020-12-05T16:00:00Z")))
+
+	_, err = m.db.FindHostDataIngestion(queued.ID)
+	assert.ErrorIs(m.T(), err, utils.ErrHostDataIngestionNotFound)
+
+	err = m.db.CompleteHostDataIngestion(queued.ID, utils.P("2020-12-05T15:00:00Z"))
+	assert.ErrorIs(m.T(), err, utils.ErrHostDataIngestionNotFound)
+}
+
+func (m *MongodbSuite) TestArchiveAndInsertHostData() {
+	defer m.db.Client.Database(m.dbname).Collection("hosts").DeleteMany(context.TODO(), bson.M{})
+
+	first := model.HostDataBE{
+		ID:        utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		Hostname:  "foobar",
+		CreatedAt: utils.P("2020-12-05T14:02:03Z"),
+	}
+	second := first
+	second.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d4")
+	second.CreatedAt = utils.P("2020-12-06T14:02:03Z")
+
+	require.NoError(m.T(), m.db.ArchiveAndInsertHostData(first))
+	require.NoError(m.T(), m.db.ArchiveAndInsertHostData(second))
+
+	count, err := m.db.Client.Database(m.dbname).Collection("hosts").CountDocuments(context.TODO(), bson.M{
+		"hostname": "foobar",
+		"archived": false,
+	})
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), int64(1), count)
+
+	count, err = m.db.Client.Database(m.dbname).Collection("hosts").CountDocuments(context.TODO(), bson.M{
+		"hostname": "foobar",
+		"archived": true,
+	})
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), int64(1), count)
+}
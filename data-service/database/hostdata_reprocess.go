@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// FindCurrentHostData return the current hostdata of the hosts, filtered by location, environment and technology.
+// Empty parameters match every host
+func (md *MongoDatabase) FindCurrentHostData(hostnames []string, location, environment, technology string) ([]model.HostDataBE, error) {
+	filter := bson.M{
+		"archived": false,
+	}
+
+	if len(hostnames) > 0 {
+		filter["hostname"] = bson.M{"$in": hostnames}
+	}
+
+	if location != "" {
+		filter["location"] = location
+	}
+
+	if environment != "" {
+		filter["environment"] = environment
+	}
+
+	switch technology {
+	case "":
+	case model.TechnologyOracleDatabase:
+		filter["features.oracle.database"] = bson.M{"$ne": nil}
+	case model.TechnologyMicrosoftSQLServer:
+		filter["features.microsoft.sqlServer"] = bson.M{"$ne": nil}
+	case model.TechnologyOracleMySQL:
+		filter["features.mysql"] = bson.M{"$ne": nil}
+	default:
+		return nil, utils.NewErrorf("%w: unsupported technology %q", utils.ErrInvalidHostDataReprocess, technology)
+	}
+
+	cur, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		Find(context.TODO(), filter)
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	hosts := make([]model.HostDataBE, 0)
+	if err := cur.All(context.TODO(), &hosts); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return hosts, nil
+}
+
+// ReplaceHostData replace the current hostdata with the same id
+func (md *MongoDatabase) ReplaceHostData(hostdata model.HostDataBE) error {
+	filter := bson.M{
+		"_id":      hostdata.ID,
+		"archived": false,
+	}
+
+	res, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").
+		ReplaceOne(context.TODO(), filter, hostdata)
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	if res.MatchedCount == 0 {
+		return utils.ErrHostNotFound
+	}
+
+	return nil
+}
@@ -17,28 +17,39 @@ package database
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/amreo/mu"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
 )
 
+// illegalOperationErrorCode is returned by MongoDB when transactions are used on a standalone server
+const illegalOperationErrorCode = 20
+
 func (md *MongoDatabase) DismissHost(hostname string) error {
-	if _, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").UpdateOne(context.TODO(), bson.M{
+	if err := md.dismissHost(context.TODO(), hostname); err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
+func (md *MongoDatabase) dismissHost(ctx context.Context, hostname string) error {
+	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").UpdateOne(ctx, bson.M{
 		"hostname":    hostname,
 		"dismissedAt": nil,
 	}, mu.UOSet(bson.M{
 		"dismissedAt": time.Now(),
 		"archived":    true,
-	})); err != nil {
-		return utils.NewError(err, "DB ERROR")
-	} else {
-		return nil
-	}
+	}))
+
+	return err
 }
 
 func (md *MongoDatabase) InsertHostData(hostData model.HostDataBE) error {
@@ -50,6 +61,39 @@ func (md *MongoDatabase) InsertHostData(hostData model.HostDataBE) error {
 	return nil
 }
 
+// ArchiveAndInsertHostData archive the current hostdata of the host and insert the new one in a single transaction.
+// If the MongoDB deployment doesn't support transactions, the two writes are done one after the other
+func (md *MongoDatabase) ArchiveAndInsertHostData(hostData model.HostDataBE) error {
+	session, err := md.Client.StartSession()
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := md.dismissHost(sessCtx, hostData.Hostname); err != nil {
+			return nil, err
+		}
+
+		return md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").InsertOne(sessCtx, hostData)
+	})
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == illegalOperationErrorCode {
+		if err := md.DismissHost(hostData.Hostname); err != nil {
+			return err
+		}
+
+		return md.InsertHostData(hostData)
+	}
+
+	if err != nil {
+		return utils.NewError(err, "DB ERROR")
+	}
+
+	return nil
+}
+
 func (md *MongoDatabase) GetCurrentHostnames() ([]string, error) {
 	values, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").Distinct(
 		context.TODO(),
@@ -166,6 +210,10 @@ func (md *MongoDatabase) FindOldArchivedHosts(t time.Time) ([]primitive.ObjectID
 }
 
 func (md *MongoDatabase) DeleteHostData(id primitive.ObjectID) error {
+	if err := md.materializeDependentHostData(id); err != nil {
+		return err
+	}
+
 	_, err := md.Client.Database(md.Config.Mongodb.DBName).Collection("hosts").DeleteOne(
 		context.TODO(),
 		bson.M{
@@ -0,0 +1,54 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// GetOraclePatchCatalogue return the Oracle patch releases and the support of the Oracle versions
+func (md *MongoDatabase) GetOraclePatchCatalogue() (*model.OraclePatchCatalogue, error) {
+	db := md.Client.Database(md.Config.Mongodb.DBName)
+
+	catalogue := model.OraclePatchCatalogue{
+		Releases: make([]model.OraclePatchRelease, 0),
+		Versions: make([]model.OracleVersionSupport, 0),
+	}
+
+	cur, err := db.Collection("oracle_patch_releases").Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	if err := cur.All(context.TODO(), &catalogue.Releases); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	cur, err = db.Collection("oracle_version_support").Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, utils.NewError(err, "DB ERROR")
+	}
+
+	if err := cur.All(context.TODO(), &catalogue.Versions); err != nil {
+		return nil, utils.NewError(err, "Decode ERROR")
+	}
+
+	return &catalogue, nil
+}
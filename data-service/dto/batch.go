@@ -0,0 +1,36 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// HostDataBatchResult contains the outcome of each hostdata of a batch
+type HostDataBatchResult struct {
+	Accepted int                       `json:"accepted"`
+	Rejected int                       `json:"rejected"`
+	Items    []HostDataBatchItemResult `json:"items"`
+}
+
+// HostDataBatchItemResult contains the outcome of a hostdata of a batch
+type HostDataBatchItemResult struct {
+	// Item is the line of the NDJSON or the name of the file in the tar
+	Item     string `json:"item"`
+	Hostname string `json:"hostname,omitempty"`
+	// Status is the http status the hostdata would have had if sent alone
+	Status      int                 `json:"status"`
+	IngestionID *primitive.ObjectID `json:"ingestionID,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
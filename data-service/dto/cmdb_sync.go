@@ -0,0 +1,29 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+// CmdbSyncReport contains the result of the synchronisation of the hosts with a CMDB
+type CmdbSyncReport struct {
+	Source string `json:"source"`
+	// Records contains the number of records read from the CMDB
+	Records int `json:"records"`
+	// Matched contains the number of records of hosts known by Ercole
+	Matched int `json:"matched"`
+	// Unmatched contains the number of records of hosts unknown by Ercole
+	Unmatched      int    `json:"unmatched"`
+	Decommissioned int    `json:"decommissioned"`
+	Error          string `json:"error,omitempty"`
+}
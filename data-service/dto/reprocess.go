@@ -0,0 +1,43 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dto
+
+import "github.com/ercole-io/ercole/v2/utils/jsonpatch"
+
+// HostDataReprocessRequest select the current hostdata to reprocess
+type HostDataReprocessRequest struct {
+	Hostnames   []string `json:"hostnames"`
+	Location    string   `json:"location"`
+	Environment string   `json:"environment"`
+	Technology  string   `json:"technology"`
+	DryRun      bool     `json:"dryRun"`
+}
+
+// HostDataReprocessReport contains the outcome of a reprocess
+type HostDataReprocessReport struct {
+	DryRun    bool                      `json:"dryRun"`
+	Processed int                       `json:"processed"`
+	Changed   int                       `json:"changed"`
+	Hosts     []HostDataReprocessResult `json:"hosts"`
+}
+
+// HostDataReprocessResult contains the differences between the stored hostdata and the reprocessed one
+type HostDataReprocessResult struct {
+	Hostname string                `json:"hostname"`
+	Changed  bool                  `json:"changed"`
+	Diff     []jsonpatch.Operation `json:"diff"`
+	Error    string                `json:"error,omitempty"`
+}
@@ -0,0 +1,389 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+
+	alert_service_client "github.com/ercole-io/ercole/v2/alert-service/client"
+	"github.com/ercole-io/ercole/v2/data-service/database"
+)
+
+// BackupComplianceJob is the job used to evaluate the backups of the Oracle databases against the backup policies
+type BackupComplianceJob struct {
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Database contains the database layer
+	Database database.MongoDatabaseInterface
+	// AlertSvcClient
+	AlertSvcClient alert_service_client.AlertSvcClientInterface
+	// Config contains the dataservice global configuration
+	Config config.Configuration
+	// Log contains logger formatted
+	Log logger.Logger
+	// NewObjectID return a new ObjectID
+	NewObjectID func() primitive.ObjectID
+}
+
+// backupAlertKey identifies the alert raised for a kind of violations of a database
+type backupAlertKey struct {
+	hostname     string
+	databaseName string
+	alertCode    string
+}
+
+var backupRetentionDaysRegexp = regexp.MustCompile(`(?i)(\d+)\s+DAYS?\b`)
+
+// Run evaluates the backups of the Oracle databases of the active hosts, saves the evaluations
+// and throws, updates or resolves the alerts of the violations
+func (job *BackupComplianceJob) Run() {
+	compliance, err := job.evaluate()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	if err := job.Database.ReplaceBackupCompliance(compliance); err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	job.updateAlerts(compliance)
+}
+
+func (job *BackupComplianceJob) evaluate() ([]model.BackupCompliance, error) {
+	hosts, err := job.Database.GetActiveHostdata()
+	if err != nil {
+		return nil, err
+	}
+
+	now := job.TimeNow()
+	compliance := make([]model.BackupCompliance, 0)
+
+	for i := range hosts {
+		host := &hosts[i]
+		if host.Features.Oracle == nil || host.Features.Oracle.Database == nil {
+			continue
+		}
+
+		policy, ok := job.getBackupPolicy(host)
+		if !ok {
+			continue
+		}
+
+		for j := range host.Features.Oracle.Database.Databases {
+			db := &host.Features.Oracle.Database.Databases[j]
+			violations := evaluateBackupPolicy(policy, db)
+
+			compliance = append(compliance, model.BackupCompliance{
+				Hostname:     host.Hostname,
+				Location:     host.Location,
+				Environment:  host.Environment,
+				DatabaseName: db.Name,
+				Policy:       policy.Name,
+				Compliant:    len(violations) == 0,
+				Violations:   violations,
+				EvaluatedAt:  now,
+			})
+		}
+	}
+
+	sort.Slice(compliance, func(i, j int) bool {
+		if compliance[i].Hostname != compliance[j].Hostname {
+			return compliance[i].Hostname < compliance[j].Hostname
+		}
+
+		return compliance[i].DatabaseName < compliance[j].DatabaseName
+	})
+
+	return compliance, nil
+}
+
+// getBackupPolicy return the first policy matching the host, false if none matches
+func (job *BackupComplianceJob) getBackupPolicy(host *model.HostDataBE) (config.BackupPolicy, bool) {
+	for _, policy := range job.Config.DataService.BackupComplianceJob.Policies {
+		if hostMatches(host, nil, policy.Environments, policy.Locations, policy.Tags) {
+			return policy, true
+		}
+	}
+
+	return config.BackupPolicy{}, false
+}
+
+// evaluateBackupPolicy return the violations of the policy by the database
+func evaluateBackupPolicy(policy config.BackupPolicy, db *model.OracleDatabase) []model.BackupViolation {
+	violations := make([]model.BackupViolation, 0)
+
+	if policy.RequireArchivelogMode && !db.Archivelog {
+		violations = append(violations, model.BackupViolation{
+			Code:        model.BackupViolationNoArchivelogMode,
+			Description: "The database isn't in archivelog mode",
+		})
+	}
+
+	if len(db.Backups) == 0 {
+		return append(violations, model.BackupViolation{
+			Code:        model.BackupViolationNoBackups,
+			Description: "No backups are scheduled",
+		})
+	}
+
+	if policy.RequireArchivelogBackups && len(backupWeekDays(db.Backups, []string{"Archivelog"})) == 0 {
+		violations = append(violations, model.BackupViolation{
+			Code:        model.BackupViolationNoArchivelogBackups,
+			Description: "No archivelog backups are scheduled",
+		})
+	}
+
+	for _, schedule := range policy.Schedules {
+		if days := len(backupWeekDays(db.Backups, schedule.BackupTypes)); days < schedule.MinDaysPerWeek {
+			violations = append(violations, model.BackupViolation{
+				Code: model.BackupViolationMissingSchedule,
+				Description: fmt.Sprintf("The backups of type %s are scheduled on %d day(s) of the week instead of %d",
+					strings.Join(schedule.BackupTypes, ", "), days, schedule.MinDaysPerWeek),
+			})
+		}
+	}
+
+	if policy.MinRetentionDays > 0 {
+		for _, backup := range db.Backups {
+			if days, ok := backupRetentionDays(backup.Retention); !ok || days < policy.MinRetentionDays {
+				violations = append(violations, model.BackupViolation{
+					Code: model.BackupViolationShortRetention,
+					Description: fmt.Sprintf("The retention %q isn't a recovery window of at least %d days",
+						backup.Retention, policy.MinRetentionDays),
+				})
+
+				break
+			}
+		}
+	}
+
+	return violations
+}
+
+// backupWeekDays return the distinct days of the week with a backup of one of the types, ignoring the case
+func backupWeekDays(backups []model.OracleDatabaseBackup, backupTypes []string) map[time.Weekday]bool {
+	days := make(map[time.Weekday]bool)
+
+	for _, backup := range backups {
+		if !containsFold(backupTypes, backup.BackupType) {
+			continue
+		}
+
+		for _, weekDay := range backup.WeekDays {
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				if strings.EqualFold(strings.TrimSpace(weekDay), day.String()) {
+					days[day] = true
+				}
+			}
+		}
+	}
+
+	return days
+}
+
+// backupRetentionDays return the days of the recovery window of the retention, false if it isn't a recovery window
+func backupRetentionDays(retention string) (int, bool) {
+	match := backupRetentionDaysRegexp.FindStringSubmatch(retention)
+	if match == nil {
+		return 0, false
+	}
+
+	days, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return days, true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (job *BackupComplianceJob) updateAlerts(compliance []model.BackupCompliance) {
+	unresolvedAlerts, err := job.Database.FindUnresolvedBackupAlerts()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	alerts := make(map[backupAlertKey]model.Alert, len(unresolvedAlerts))
+
+	for _, alert := range unresolvedAlerts {
+		key := backupAlertKey{alertCode: alert.AlertCode}
+		key.hostname, _ = alert.OtherInfo["hostname"].(string)
+		key.databaseName, _ = alert.OtherInfo["dbname"].(string)
+
+		if other, ok := alerts[key]; !ok || other.Date.Before(alert.Date) {
+			alerts[key] = alert
+		}
+	}
+
+	for i := range compliance {
+		evaluation := &compliance[i]
+
+		for _, code := range backupAlertCodesOf(evaluation.Violations) {
+			key := backupAlertKey{hostname: evaluation.Hostname, databaseName: evaluation.DatabaseName, alertCode: code}
+
+			alert, ok := alerts[key]
+			delete(alerts, key)
+
+			switch {
+			case !ok:
+				if err := job.AlertSvcClient.ThrowNewAlert(job.newBackupAlert(evaluation, code)); err != nil {
+					job.Log.Error(err)
+				}
+			case alert.AlertStatus == model.AlertStatusDismissed:
+			default:
+				if err := job.updateBackupAlert(alert, evaluation); err != nil {
+					job.Log.Error(err)
+				}
+			}
+		}
+	}
+
+	for _, alert := range alerts {
+		if alert.AlertStatus == model.AlertStatusDismissed {
+			continue
+		}
+
+		if err := job.Database.ResolveBackupAlert(alert.ID, job.TimeNow(), "The backups comply with the policy"); err != nil {
+			job.Log.Error(err)
+		}
+	}
+}
+
+// backupAlertCodesOf return the distinct codes of the alerts of the violations, in their order
+func backupAlertCodesOf(violations []model.BackupViolation) []string {
+	codes := make([]string, 0, len(violations))
+
+	for _, violation := range violations {
+		code := model.BackupViolationAlertCodes[violation.Code]
+		if !utils.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes
+}
+
+func (job *BackupComplianceJob) newBackupAlert(evaluation *model.BackupCompliance, code string) model.Alert {
+	policy, _ := job.getPolicyByName(evaluation.Policy)
+
+	return model.Alert{
+		ID:                      job.NewObjectID(),
+		AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               code,
+		AlertSeverity:           policy.Severity,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    job.TimeNow(),
+		Description:             backupAlertDescription(evaluation, code),
+		OtherInfo:               backupAlertOtherInfo(evaluation, code),
+	}
+}
+
+// updateBackupAlert update the alert, keeping its status and date, if anything is changed
+func (job *BackupComplianceJob) updateBackupAlert(alert model.Alert, evaluation *model.BackupCompliance) error {
+	policy, _ := job.getPolicyByName(evaluation.Policy)
+	description := backupAlertDescription(evaluation, alert.AlertCode)
+
+	var historyEntry *model.AlertHistoryEntry
+
+	if alert.AlertSeverity != policy.Severity {
+		historyEntry = &model.AlertHistoryEntry{
+			Date:     job.TimeNow(),
+			Username: model.AlertSystemUsername,
+			Action:   model.AlertActionSeverityChange,
+			Severity: policy.Severity,
+			Comment:  description,
+		}
+	} else if alert.Description == description {
+		return nil
+	}
+
+	alert.AlertSeverity = policy.Severity
+	alert.Description = description
+	alert.OtherInfo = backupAlertOtherInfo(evaluation, alert.AlertCode)
+
+	return job.Database.UpdateBackupAlert(alert, historyEntry)
+}
+
+func (job *BackupComplianceJob) getPolicyByName(name string) (config.BackupPolicy, bool) {
+	for _, policy := range job.Config.DataService.BackupComplianceJob.Policies {
+		if policy.Name == name {
+			return policy, true
+		}
+	}
+
+	return config.BackupPolicy{Severity: model.AlertSeverityWarning}, false
+}
+
+// backupAlertViolations return the violations of the evaluation raising the alert with the code
+func backupAlertViolations(evaluation *model.BackupCompliance, code string) []model.BackupViolation {
+	violations := make([]model.BackupViolation, 0, len(evaluation.Violations))
+
+	for _, violation := range evaluation.Violations {
+		if model.BackupViolationAlertCodes[violation.Code] == code {
+			violations = append(violations, violation)
+		}
+	}
+
+	return violations
+}
+
+func backupAlertDescription(evaluation *model.BackupCompliance, code string) string {
+	descriptions := make([]string, 0)
+	for _, violation := range backupAlertViolations(evaluation, code) {
+		descriptions = append(descriptions, violation.Description)
+	}
+
+	return fmt.Sprintf("The database %s on the host %s violates the backup policy %s: %s",
+		evaluation.DatabaseName, evaluation.Hostname, evaluation.Policy, strings.Join(descriptions, "; "))
+}
+
+func backupAlertOtherInfo(evaluation *model.BackupCompliance, code string) map[string]interface{} {
+	violations := make([]string, 0)
+	for _, violation := range backupAlertViolations(evaluation, code) {
+		violations = append(violations, violation.Code)
+	}
+
+	return map[string]interface{}{
+		"hostname":   evaluation.Hostname,
+		"dbname":     evaluation.DatabaseName,
+		"policy":     evaluation.Policy,
+		"violations": violations,
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+var productionBackupPolicy = config.BackupPolicy{
+	Name:                     "production",
+	Environments:             []string{"PRD"},
+	MinRetentionDays:         14,
+	RequireArchivelogBackups: true,
+	RequireArchivelogMode:    true,
+	Severity:                 model.AlertSeverityCritical,
+	Schedules: []config.BackupSchedule{
+		{BackupTypes: []string{"Level0", "Level1"}, MinDaysPerWeek: 7},
+		{BackupTypes: []string{"Level0"}, MinDaysPerWeek: 1},
+	},
+}
+
+func backupComplianceTestHosts() []model.HostDataBE {
+	oracle := func(dbs ...model.OracleDatabase) model.Features {
+		return model.Features{Oracle: &model.OracleFeature{Database: &model.OracleDatabaseFeature{Databases: dbs}}}
+	}
+
+	return []model.HostDataBE{
+		{
+			Hostname:    "test-db",
+			Location:    "Italy",
+			Environment: "PRD",
+			Features: oracle(
+				model.OracleDatabase{
+					Name:       "SHORT",
+					Archivelog: true,
+					Backups: []model.OracleDatabaseBackup{
+						{BackupType: "Level1", WeekDays: []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}, Retention: "1 NUMBERS"},
+					},
+				},
+				model.OracleDatabase{
+					Name:       "ERCOLE",
+					Archivelog: true,
+					Backups: []model.OracleDatabaseBackup{
+						{BackupType: "Level0", WeekDays: []string{"Sunday"}, Retention: "14 DAYS"},
+						{BackupType: "Level1", WeekDays: []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}, Retention: "14 DAYS"},
+						{BackupType: "Archivelog", WeekDays: []string{"Monday", "Thursday"}, Retention: "14 DAYS"},
+					},
+				},
+				model.OracleDatabase{
+					Name:       "NOBCK",
+					Archivelog: false,
+					Backups:    []model.OracleDatabaseBackup{},
+				},
+			),
+		},
+		{
+			Hostname:    "test-tst",
+			Location:    "Italy",
+			Environment: "TST",
+			Features:    oracle(model.OracleDatabase{Name: "TST", Backups: []model.OracleDatabaseBackup{}}),
+		},
+	}
+}
+
+func TestEvaluateBackupPolicy(t *testing.T) {
+	hosts := backupComplianceTestHosts()
+	dbs := hosts[0].Features.Oracle.Database.Databases
+
+	assert.Empty(t, evaluateBackupPolicy(productionBackupPolicy, &dbs[1]))
+
+	assert.Equal(t, []model.BackupViolation{
+		{Code: model.BackupViolationNoArchivelogMode, Description: "The database isn't in archivelog mode"},
+		{Code: model.BackupViolationNoBackups, Description: "No backups are scheduled"},
+	}, evaluateBackupPolicy(productionBackupPolicy, &dbs[2]))
+
+	assert.Equal(t, []model.BackupViolation{
+		{Code: model.BackupViolationNoArchivelogBackups, Description: "No archivelog backups are scheduled"},
+		{
+			Code:        model.BackupViolationMissingSchedule,
+			Description: "The backups of type Level0, Level1 are scheduled on 5 day(s) of the week instead of 7",
+		},
+		{
+			Code:        model.BackupViolationMissingSchedule,
+			Description: "The backups of type Level0 are scheduled on 0 day(s) of the week instead of 1",
+		},
+		{
+			Code:        model.BackupViolationShortRetention,
+			Description: "The retention \"1 NUMBERS\" isn't a recovery window of at least 14 days",
+		},
+	}, evaluateBackupPolicy(productionBackupPolicy, &dbs[0]))
+
+	assert.Empty(t, evaluateBackupPolicy(config.BackupPolicy{}, &dbs[0]))
+}
+
+func TestBackupRetentionDays(t *testing.T) {
+	testCases := []struct {
+		retention string
+		days      int
+		ok        bool
+	}{
+		{"14 DAYS", 14, true},
+		{"RECOVERY WINDOW OF 30 days", 30, true},
+		{"1 DAY", 1, true},
+		{"1 NUMBERS", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range testCases {
+		days, ok := backupRetentionDays(tc.retention)
+		assert.Equal(t, tc.days, days, tc.retention)
+		assert.Equal(t, tc.ok, ok, tc.retention)
+	}
+}
+
+func TestBackupComplianceJobRun_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	now := utils.Btc(utils.P("2020-12-10T00:00:00Z"))
+
+	job := BackupComplianceJob{
+		TimeNow:        now,
+		Database:       db,
+		AlertSvcClient: asc,
+		Config: config.Configuration{
+			DataService: config.DataService{
+				BackupComplianceJob: config.BackupComplianceJob{
+					Policies: []config.BackupPolicy{productionBackupPolicy},
+				},
+			},
+		},
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	db.EXPECT().GetActiveHostdata().Return(backupComplianceTestHosts(), nil)
+	db.EXPECT().ReplaceBackupCompliance(gomock.Any()).
+		DoAndReturn(func(compliance []model.BackupCompliance) error {
+			require.Len(t, compliance, 3)
+
+			assert.Equal(t, "ERCOLE", compliance[0].DatabaseName)
+			assert.True(t, compliance[0].Compliant)
+			assert.Equal(t, "NOBCK", compliance[1].DatabaseName)
+			assert.False(t, compliance[1].Compliant)
+			assert.Equal(t, "SHORT", compliance[2].DatabaseName)
+			assert.Len(t, compliance[2].Violations, 4)
+
+			for _, c := range compliance {
+				assert.Equal(t, "test-db", c.Hostname)
+				assert.Equal(t, "Italy", c.Location)
+				assert.Equal(t, "PRD", c.Environment)
+				assert.Equal(t, "production", c.Policy)
+				assert.Equal(t, now(), c.EvaluatedAt)
+			}
+
+			return nil
+		})
+
+	shortAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000010"),
+		AlertCode:     model.AlertCodeBackupPolicyViolation,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusAck,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "dbname": "SHORT"},
+	}
+	ercoleAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000011"),
+		AlertCode:     model.AlertCodeBackupMissing,
+		AlertSeverity: model.AlertSeverityCritical,
+		AlertStatus:   model.AlertStatusNew,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "dbname": "ERCOLE"},
+	}
+	db.EXPECT().FindUnresolvedBackupAlerts().Return([]model.Alert{shortAlert, ercoleAlert}, nil)
+
+	newAlert := func(id, dbname, code, description string, violations ...string) model.Alert {
+		return model.Alert{
+			ID:                      utils.Str2oid(id),
+			AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
+			AlertCategory:           model.AlertCategoryEngine,
+			AlertCode:               code,
+			AlertSeverity:           model.AlertSeverityCritical,
+			AlertStatus:             model.AlertStatusNew,
+			Date:                    now(),
+			Description:             "The database " + dbname + " on the host test-db violates the backup policy production: " + description,
+			OtherInfo: map[string]interface{}{
+				"hostname":   "test-db",
+				"dbname":     dbname,
+				"policy":     "production",
+				"violations": violations,
+			},
+		}
+	}
+
+	gomock.InOrder(
+		asc.EXPECT().ThrowNewAlert(newAlert("000000000000000000000001", "NOBCK", model.AlertCodeNoArchivelogMode,
+			"The database isn't in archivelog mode", model.BackupViolationNoArchivelogMode)).Return(nil),
+		asc.EXPECT().ThrowNewAlert(newAlert("000000000000000000000002", "NOBCK", model.AlertCodeBackupMissing,
+			"No backups are scheduled", model.BackupViolationNoBackups)).Return(nil),
+		asc.EXPECT().ThrowNewAlert(newAlert("000000000000000000000003", "SHORT", model.AlertCodeArchivelogBackupMissing,
+			"No archivelog backups are scheduled", model.BackupViolationNoArchivelogBackups)).Return(nil),
+	)
+
+	description := "The database SHORT on the host test-db violates the backup policy production: " +
+		"The backups of type Level0, Level1 are scheduled on 5 day(s) of the week instead of 7; " +
+		"The backups of type Level0 are scheduled on 0 day(s) of the week instead of 1; " +
+		"The retention \"1 NUMBERS\" isn't a recovery window of at least 14 days"
+	expectedShortAlert := shortAlert
+	expectedShortAlert.AlertSeverity = model.AlertSeverityCritical
+	expectedShortAlert.Description = description
+	expectedShortAlert.OtherInfo = map[string]interface{}{
+		"hostname":   "test-db",
+		"dbname":     "SHORT",
+		"policy":     "production",
+		"violations": []string{model.BackupViolationMissingSchedule, model.BackupViolationMissingSchedule, model.BackupViolationShortRetention},
+	}
+	db.EXPECT().UpdateBackupAlert(expectedShortAlert, &model.AlertHistoryEntry{
+		Date:     now(),
+		Username: model.AlertSystemUsername,
+		Action:   model.AlertActionSeverityChange,
+		Severity: model.AlertSeverityCritical,
+		Comment:  description,
+	}).Return(nil)
+
+	db.EXPECT().ResolveBackupAlert(ercoleAlert.ID, now(), "The backups comply with the policy").Return(nil)
+
+	job.Run()
+}
+
+func TestBackupComplianceJobRun_DatabaseError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	job := BackupComplianceJob{
+		TimeNow:  utils.Btc(utils.P("2020-12-10T00:00:00Z")),
+		Database: db,
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	db.EXPECT().GetActiveHostdata().Return(nil, aerrMock)
+
+	job.Run()
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+
+	alert_service_client "github.com/ercole-io/ercole/v2/alert-service/client"
+	"github.com/ercole-io/ercole/v2/data-service/database"
+)
+
+// CapacityForecastJob is the job used to forecast the exhaustion of the tablespaces, the filesystems and the databases
+type CapacityForecastJob struct {
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Database contains the database layer
+	Database database.MongoDatabaseInterface
+	// AlertSvcClient
+	AlertSvcClient alert_service_client.AlertSvcClientInterface
+	// Config contains the dataservice global configuration
+	Config config.Configuration
+	// Log contains logger formatted
+	Log logger.Logger
+	// NewObjectID return a new ObjectID
+	NewObjectID func() primitive.ObjectID
+}
+
+// Run fits the growth trends of the items of the active hosts over their history, saves the forecasts
+// and throws, escalates or resolves the alerts of the items projected to be full within the horizons
+func (job *CapacityForecastJob) Run() {
+	forecasts, err := job.forecast()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	if err := job.Database.ReplaceCapacityForecasts(forecasts); err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	job.updateAlerts(forecasts)
+}
+
+func (job *CapacityForecastJob) forecast() ([]model.CapacityForecast, error) {
+	hosts, err := job.Database.GetActiveHostdata()
+	if err != nil {
+		return nil, err
+	}
+
+	now := job.TimeNow()
+	jobConfig := job.Config.DataService.CapacityForecastJob
+
+	hostnames := make([]string, len(hosts))
+	for i := range hosts {
+		hostnames[i] = hosts[i].Hostname
+	}
+
+	archived, err := job.Database.FindArchivedHostDataCapacities(hostnames, now.AddDate(0, 0, -jobConfig.HistoryDays))
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[model.CapacityItem][]model.CapacitySample)
+
+	for i := range archived {
+		for item, sample := range model.CapacitySamples(archived[i]) {
+			history[item] = append(history[item], sample)
+		}
+	}
+
+	forecasts := make([]model.CapacityForecast, 0)
+
+	for i := range hosts {
+		for item, sample := range model.CapacitySamples(hosts[i]) {
+			samples := append(history[item], sample)
+			if len(samples) < jobConfig.MinSamples {
+				continue
+			}
+
+			forecast := model.NewCapacityForecast(item, samples, now)
+			forecast.Location = hosts[i].Location
+			forecast.Environment = hosts[i].Environment
+
+			forecasts = append(forecasts, forecast)
+		}
+	}
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		a, b := forecasts[i].CapacityItem, forecasts[j].CapacityItem
+		if a.Hostname != b.Hostname {
+			return a.Hostname < b.Hostname
+		}
+
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		if a.DatabaseName != b.DatabaseName {
+			return a.DatabaseName < b.DatabaseName
+		}
+
+		return a.Name < b.Name
+	})
+
+	return forecasts, nil
+}
+
+func (job *CapacityForecastJob) updateAlerts(forecasts []model.CapacityForecast) {
+	unresolvedAlerts, err := job.Database.FindUnresolvedCapacityAlerts()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	alerts := make(map[model.CapacityItem]model.Alert, len(unresolvedAlerts))
+
+	for _, alert := range unresolvedAlerts {
+		item := capacityAlertItem(alert)
+
+		if other, ok := alerts[item]; !ok || other.Date.Before(alert.Date) {
+			alerts[item] = alert
+		}
+	}
+
+	for i := range forecasts {
+		forecast := &forecasts[i]
+		severity := job.capacitySeverity(forecast)
+
+		alert, ok := alerts[forecast.CapacityItem]
+		delete(alerts, forecast.CapacityItem)
+
+		switch {
+		case !ok && severity == "":
+		case !ok:
+			if err := job.AlertSvcClient.ThrowNewAlert(job.newCapacityAlert(forecast, severity)); err != nil {
+				job.Log.Error(err)
+			}
+		case alert.AlertStatus == model.AlertStatusDismissed:
+		case severity == "":
+			if err := job.Database.ResolveCapacityAlert(alert.ID, job.TimeNow(),
+				"The exhaustion isn't projected within the horizon anymore"); err != nil {
+				job.Log.Error(err)
+			}
+		default:
+			if err := job.updateCapacityAlert(alert, forecast, severity); err != nil {
+				job.Log.Error(err)
+			}
+		}
+	}
+
+	for _, alert := range alerts {
+		if alert.AlertStatus == model.AlertStatusDismissed {
+			continue
+		}
+
+		if err := job.Database.ResolveCapacityAlert(alert.ID, job.TimeNow(), "The capacity isn't forecasted anymore"); err != nil {
+			job.Log.Error(err)
+		}
+	}
+}
+
+// capacitySeverity return the severity of the alert of the forecast, or an empty string if its exhaustion
+// isn't projected within the horizons
+func (job *CapacityForecastJob) capacitySeverity(forecast *model.CapacityForecast) string {
+	if forecast.DaysToFull == nil {
+		return ""
+	}
+
+	jobConfig := job.Config.DataService.CapacityForecastJob
+
+	switch days := *forecast.DaysToFull; {
+	case jobConfig.CriticalHorizonDays > 0 && days <= float64(jobConfig.CriticalHorizonDays):
+		return model.AlertSeverityCritical
+	case jobConfig.WarningHorizonDays > 0 && days <= float64(jobConfig.WarningHorizonDays):
+		return model.AlertSeverityWarning
+	default:
+		return ""
+	}
+}
+
+func (job *CapacityForecastJob) newCapacityAlert(forecast *model.CapacityForecast, severity string) model.Alert {
+	var technology *string
+	if forecast.Kind != model.CapacityForecastKindFilesystem {
+		technology = model.TechnologyOracleDatabasePtr
+	}
+
+	return model.Alert{
+		ID:                      job.NewObjectID(),
+		AlertAffectedTechnology: technology,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               model.CapacityExhaustionAlertCodes[forecast.Kind],
+		AlertSeverity:           severity,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    job.TimeNow(),
+		Description:             capacityAlertDescription(forecast),
+		OtherInfo:               capacityAlertOtherInfo(forecast.CapacityItem),
+	}
+}
+
+// updateCapacityAlert update the alert, keeping its status and date, if anything is changed
+func (job *CapacityForecastJob) updateCapacityAlert(alert model.Alert, forecast *model.CapacityForecast, severity string) error {
+	description := capacityAlertDescription(forecast)
+
+	var historyEntry *model.AlertHistoryEntry
+
+	if alert.AlertSeverity != severity {
+		historyEntry = &model.AlertHistoryEntry{
+			Date:     job.TimeNow(),
+			Username: model.AlertSystemUsername,
+			Action:   model.AlertActionSeverityChange,
+			Severity: severity,
+			Comment:  description,
+		}
+	} else if alert.Description == description {
+		return nil
+	}
+
+	alert.AlertSeverity = severity
+	alert.Description = description
+	alert.OtherInfo = capacityAlertOtherInfo(forecast.CapacityItem)
+
+	return job.Database.UpdateCapacityAlert(alert, historyEntry)
+}
+
+func capacityAlertDescription(forecast *model.CapacityForecast) string {
+	when := fmt.Sprintf("in %d day(s), on %s", int(math.Floor(*forecast.DaysToFull)), forecast.ExhaustionDate.Format("2006-01-02"))
+
+	switch forecast.Kind {
+	case model.CapacityForecastKindTablespace:
+		return fmt.Sprintf("The tablespace %s of the database %s on the host %s is projected to be full %s",
+			forecast.Name, forecast.DatabaseName, forecast.Hostname, when)
+	case model.CapacityForecastKindDatabase:
+		return fmt.Sprintf("The segments of the database %s on the host %s are projected to fill its datafiles %s",
+			forecast.DatabaseName, forecast.Hostname, when)
+	default:
+		return fmt.Sprintf("The filesystem %s on the host %s is projected to be full %s", forecast.Name, forecast.Hostname, when)
+	}
+}
+
+func capacityAlertOtherInfo(item model.CapacityItem) map[string]interface{} {
+	otherInfo := map[string]interface{}{
+		"hostname": item.Hostname,
+		"kind":     item.Kind,
+		"name":     item.Name,
+	}
+
+	if item.DatabaseName != "" {
+		otherInfo["dbname"] = item.DatabaseName
+	}
+
+	return otherInfo
+}
+
+// capacityAlertItem return the item of the alert, from its otherInfo
+func capacityAlertItem(alert model.Alert) model.CapacityItem {
+	item := model.CapacityItem{}
+	item.Hostname, _ = alert.OtherInfo["hostname"].(string)
+	item.Kind, _ = alert.OtherInfo["kind"].(string)
+	item.DatabaseName, _ = alert.OtherInfo["dbname"].(string)
+	item.Name, _ = alert.OtherInfo["name"].(string)
+
+	return item
+}
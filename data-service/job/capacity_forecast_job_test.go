@@ -0,0 +1,228 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func capacityForecastJobConfig(warningHorizonDays, criticalHorizonDays int) config.Configuration {
+	return config.Configuration{
+		DataService: config.DataService{
+			CapacityForecastJob: config.CapacityForecastJob{
+				HistoryDays:         90,
+				MinSamples:          3,
+				WarningHorizonDays:  warningHorizonDays,
+				CriticalHorizonDays: criticalHorizonDays,
+			},
+		},
+	}
+}
+
+func capacityForecastTestHostdata() ([]model.HostDataBE, []model.HostDataBE) {
+	hostdata := func(createdAt string, rootUsed int64) model.HostDataBE {
+		return model.HostDataBE{
+			Hostname:    "test-db",
+			Location:    "Italy",
+			Environment: "PRD",
+			CreatedAt:   utils.P(createdAt),
+			Filesystems: []model.Filesystem{
+				{MountedOn: "/", Size: 1000, UsedSpace: rootUsed},
+				{MountedOn: "/data", Size: 1000, UsedSpace: 100},
+			},
+		}
+	}
+
+	return []model.HostDataBE{hostdata("2020-12-09T00:00:00Z", 520)},
+		[]model.HostDataBE{hostdata("2020-12-01T00:00:00Z", 440), hostdata("2020-12-05T00:00:00Z", 480)}
+}
+
+func TestCapacityForecastJobRun_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	now := utils.Btc(utils.P("2020-12-10T00:00:00Z"))
+
+	job := CapacityForecastJob{
+		TimeNow:        now,
+		Database:       db,
+		AlertSvcClient: asc,
+		Config:         capacityForecastJobConfig(60, 7),
+		Log:            logger.NewLogger("TEST"),
+		NewObjectID:    utils.NewObjectIDForTests(),
+	}
+
+	hosts, archived := capacityForecastTestHostdata()
+
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().FindArchivedHostDataCapacities([]string{"test-db"}, utils.P("2020-09-11T00:00:00Z")).Return(archived, nil)
+	db.EXPECT().ReplaceCapacityForecasts(gomock.Any()).
+		DoAndReturn(func(forecasts []model.CapacityForecast) error {
+			require.Len(t, forecasts, 2)
+
+			assert.Equal(t, "/", forecasts[0].Name)
+			assert.Equal(t, "Italy", forecasts[0].Location)
+			assert.Equal(t, "PRD", forecasts[0].Environment)
+			assert.Len(t, forecasts[0].Samples, 3)
+			require.NotNil(t, forecasts[0].DaysToFull)
+			assert.InDelta(t, 47, *forecasts[0].DaysToFull, 1e-9)
+
+			assert.Equal(t, "/data", forecasts[1].Name)
+			assert.Nil(t, forecasts[1].DaysToFull)
+
+			return nil
+		})
+
+	dataAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000010"),
+		AlertCode:     model.AlertCodeFilesystemExhaustion,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusAck,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "kind": model.CapacityForecastKindFilesystem, "name": "/data"},
+	}
+	oldAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000011"),
+		AlertCode:     model.AlertCodeFilesystemExhaustion,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusNew,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "kind": model.CapacityForecastKindFilesystem, "name": "/old"},
+	}
+	dismissedAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000012"),
+		AlertCode:     model.AlertCodeFilesystemExhaustion,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusDismissed,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "kind": model.CapacityForecastKindFilesystem, "name": "/tmp"},
+	}
+	db.EXPECT().FindUnresolvedCapacityAlerts().Return([]model.Alert{dataAlert, oldAlert, dismissedAlert}, nil)
+
+	expectedAlert := model.Alert{
+		ID:                      utils.Str2oid("000000000000000000000001"),
+		AlertAffectedTechnology: nil,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               model.AlertCodeFilesystemExhaustion,
+		AlertSeverity:           model.AlertSeverityWarning,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    now(),
+		Description:             "The filesystem / on the host test-db is projected to be full in 47 day(s), on 2021-01-26",
+		OtherInfo: map[string]interface{}{
+			"hostname": "test-db",
+			"kind":     model.CapacityForecastKindFilesystem,
+			"name":     "/",
+		},
+	}
+	asc.EXPECT().ThrowNewAlert(expectedAlert).Return(nil)
+
+	db.EXPECT().ResolveCapacityAlert(dataAlert.ID, now(), "The exhaustion isn't projected within the horizon anymore").Return(nil)
+	db.EXPECT().ResolveCapacityAlert(oldAlert.ID, now(), "The capacity isn't forecasted anymore").Return(nil)
+
+	job.Run()
+}
+
+func TestCapacityForecastJobRun_EscalateAlert(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	now := utils.Btc(utils.P("2020-12-10T00:00:00Z"))
+
+	job := CapacityForecastJob{
+		TimeNow:     now,
+		Database:    db,
+		Config:      capacityForecastJobConfig(90, 50),
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	hosts, archived := capacityForecastTestHostdata()
+
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().FindArchivedHostDataCapacities([]string{"test-db"}, gomock.Any()).Return(archived, nil)
+	db.EXPECT().ReplaceCapacityForecasts(gomock.Any()).Return(nil)
+
+	rootAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000010"),
+		AlertCode:     model.AlertCodeFilesystemExhaustion,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusAck,
+		Description:   "The filesystem / on the host test-db is projected to be full in 60 day(s), on 2021-02-08",
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "kind": model.CapacityForecastKindFilesystem, "name": "/"},
+	}
+	db.EXPECT().FindUnresolvedCapacityAlerts().Return([]model.Alert{rootAlert}, nil)
+
+	description := "The filesystem / on the host test-db is projected to be full in 47 day(s), on 2021-01-26"
+	expectedAlert := rootAlert
+	expectedAlert.AlertSeverity = model.AlertSeverityCritical
+	expectedAlert.Description = description
+
+	db.EXPECT().UpdateCapacityAlert(expectedAlert, &model.AlertHistoryEntry{
+		Date:     now(),
+		Username: model.AlertSystemUsername,
+		Action:   model.AlertActionSeverityChange,
+		Severity: model.AlertSeverityCritical,
+		Comment:  description,
+	}).Return(nil)
+
+	job.Run()
+}
+
+func TestCapacityForecastJobRun_NotEnoughSamples(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	job := CapacityForecastJob{
+		TimeNow:     utils.Btc(utils.P("2020-12-10T00:00:00Z")),
+		Database:    db,
+		Config:      capacityForecastJobConfig(60, 7),
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	hosts, _ := capacityForecastTestHostdata()
+
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().FindArchivedHostDataCapacities([]string{"test-db"}, gomock.Any()).Return([]model.HostDataBE{}, nil)
+	db.EXPECT().ReplaceCapacityForecasts([]model.CapacityForecast{}).Return(nil)
+	db.EXPECT().FindUnresolvedCapacityAlerts().Return([]model.Alert{}, nil)
+
+	job.Run()
+}
+
+func TestCapacityForecastJobRun_DatabaseError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	job := CapacityForecastJob{
+		TimeNow:  utils.Btc(utils.P("2020-12-10T00:00:00Z")),
+		Database: db,
+		Config:   capacityForecastJobConfig(60, 7),
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	db.EXPECT().GetActiveHostdata().Return(nil, aerrMock)
+
+	job.Run()
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package job
+
+import (
+	"github.com/ercole-io/ercole/v2/data-service/service"
+	"github.com/ercole-io/ercole/v2/logger"
+)
+
+// CmdbSyncJob synchronise the hosts with the CMDBs
+type CmdbSyncJob struct {
+	Service service.HostDataServiceInterface
+	Log     logger.Logger
+}
+
+// Run synchronise the hosts with every configured CMDB
+func (job *CmdbSyncJob) Run() {
+	reports, err := job.Service.SyncCmdbs("")
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	for _, report := range reports {
+		if report.Error != "" {
+			continue
+		}
+
+		job.Log.Infof("Synchronised CMDB %s: %d records, %d matched, %d unmatched, %d decommissioned",
+			report.Source, report.Records, report.Matched, report.Unmatched, report.Decommissioned)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"time"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/data-service/database"
+	"github.com/ercole-io/ercole/v2/logger"
+)
+
+// ConsumptionDownsamplingJob is the job used to roll up by day the consumption samples and to apply their retentions
+type ConsumptionDownsamplingJob struct {
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Database contains the database layer
+	Database database.MongoDatabaseInterface
+	// Config contains the dataservice global configuration
+	Config config.Configuration
+	// Log contains logger formatted
+	Log logger.Logger
+}
+
+// Run applies the retentions and rolls up the samples of the completed days not rolled up yet
+func (job *ConsumptionDownsamplingJob) Run() {
+	metricsConfig := job.Config.DataService.ConsumptionMetrics
+
+	if err := job.Database.ApplyConsumptionMetricsRetention(metricsConfig.RawRetentionDays, metricsConfig.DailyRetentionDays); err != nil {
+		job.Log.Error(err)
+	}
+
+	last, err := job.Database.FindLastDailyConsumptionMetricDate()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	until := job.TimeNow().UTC().Truncate(24 * time.Hour)
+
+	from := until.AddDate(0, 0, -metricsConfig.RawRetentionDays)
+	if !last.IsZero() && last.AddDate(0, 0, 1).After(from) {
+		from = last.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(until) {
+		return
+	}
+
+	count, err := job.Database.DownsampleConsumptionMetrics(from, until)
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	job.Log.Infof("%d daily consumption metrics have been rolled up from %s to %s", count, from.Format("2006-01-02"), until.Format("2006-01-02"))
+}
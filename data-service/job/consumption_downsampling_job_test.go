@@ -0,0 +1,81 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func consumptionDownsamplingTestJob(db *MockMongoDatabaseInterface) *ConsumptionDownsamplingJob {
+	return &ConsumptionDownsamplingJob{
+		TimeNow:  utils.Btc(utils.P("2022-05-10T08:30:00Z")),
+		Database: db,
+		Config: config.Configuration{
+			DataService: config.DataService{
+				ConsumptionMetrics: config.ConsumptionMetrics{
+					RawRetentionDays:   90,
+					DailyRetentionDays: 730,
+				},
+			},
+		},
+		Log: logger.NewLogger("TEST"),
+	}
+}
+
+func TestConsumptionDownsamplingJobRun_FromLastDailyMetric(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	gomock.InOrder(
+		db.EXPECT().ApplyConsumptionMetricsRetention(90, 730).Return(nil),
+		db.EXPECT().FindLastDailyConsumptionMetricDate().Return(utils.P("2022-05-07T00:00:00Z"), nil),
+		db.EXPECT().DownsampleConsumptionMetrics(utils.P("2022-05-08T00:00:00Z"), utils.P("2022-05-10T00:00:00Z")).Return(4, nil),
+	)
+
+	consumptionDownsamplingTestJob(db).Run()
+}
+
+func TestConsumptionDownsamplingJobRun_FirstRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	gomock.InOrder(
+		db.EXPECT().ApplyConsumptionMetricsRetention(90, 730).Return(nil),
+		db.EXPECT().FindLastDailyConsumptionMetricDate().Return(time.Time{}, nil),
+		db.EXPECT().DownsampleConsumptionMetrics(utils.P("2022-02-09T00:00:00Z"), utils.P("2022-05-10T00:00:00Z")).Return(0, nil),
+	)
+
+	consumptionDownsamplingTestJob(db).Run()
+}
+
+func TestConsumptionDownsamplingJobRun_AlreadyRolledUp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	db.EXPECT().ApplyConsumptionMetricsRetention(90, 730).Return(aerrMock)
+	db.EXPECT().FindLastDailyConsumptionMetricDate().Return(utils.P("2022-05-09T00:00:00Z"), nil)
+
+	consumptionDownsamplingTestJob(db).Run()
+}
@@ -17,6 +17,7 @@ package job
 
 import (
 	"fmt"
+	"reflect"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -24,6 +25,7 @@ import (
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
 	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
 
 	alert_service_client "github.com/ercole-io/ercole/v2/alert-service/client"
 	"github.com/ercole-io/ercole/v2/data-service/database"
@@ -45,58 +47,210 @@ type FreshnessCheckJob struct {
 	NewObjectID func() primitive.ObjectID
 }
 
-// Run throws NO_DATA alert for each hosts that haven't sent a hostdata withing the host.Period (hours)
+// Run throws a NO_DATA alert for each host that hasn't sent a hostdata within the thresholds of its freshness policy,
+// or updates the alert already thrown, escalating its severity
 func (job *FreshnessCheckJob) Run() {
-	if err := job.Database.DeleteAllNoDataAlerts(); err != nil {
+	hosts, err := job.Database.GetActiveHostdata()
+	if err != nil {
 		job.Log.Error(err)
 		return
 	}
 
-	hosts, err := job.Database.GetActiveHostdata()
-
+	unresolvedAlerts, err := job.Database.FindUnresolvedNoDataAlerts()
 	if err != nil {
 		job.Log.Error(err)
 		return
 	}
 
-	for _, host := range hosts {
-		var period time.Duration
+	alerts := make(map[string]model.Alert, len(unresolvedAlerts))
+
+	for _, alert := range unresolvedAlerts {
+		hostname, ok := alert.OtherInfo["hostname"].(string)
+		if !ok {
+			continue
+		}
 
-		if host.Period <= 0 {
-			period = 24
-		} else {
-			period = time.Duration(host.Period)
+		if other, ok := alerts[hostname]; !ok || other.Date.Before(alert.Date) {
+			alerts[hostname] = alert
 		}
+	}
 
-		isOld, err := job.Database.FindOldCurrentHostdata(host.Hostname, job.TimeNow().Add(-(period)*time.Hour))
-		if err != nil {
-			job.Log.Error(err)
+	now := job.TimeNow()
+	activeHosts := make(map[string]bool, len(hosts))
+
+	for i := range hosts {
+		host := &hosts[i]
+		activeHosts[host.Hostname] = true
+
+		if job.isInMaintenanceWindow(host, now) {
+			continue
+		}
+
+		policy := job.getFreshnessPolicy(host)
+		elapsed := now.Sub(host.CreatedAt)
+
+		severity := freshnessSeverity(policy, elapsed)
+		if severity == "" {
 			continue
 		}
 
-		if isOld {
-			elapsed := job.TimeNow().Sub(host.CreatedAt)
-			elapsedDays := int(elapsed.Truncate(time.Hour*24).Hours() / 24)
-
-			alert := model.Alert{
-				ID:                      job.NewObjectID(),
-				AlertAffectedTechnology: nil,
-				AlertCategory:           model.AlertCategoryAgent,
-				AlertCode:               model.AlertCodeNoData,
-				AlertSeverity:           model.AlertSeverityCritical,
-				AlertStatus:             model.AlertStatusNew,
-				Date:                    job.TimeNow(),
-				Description:             fmt.Sprintf("No data received from the host %s in the last %d day(s)", host.Hostname, elapsedDays),
-				OtherInfo: map[string]interface{}{
-					"hostname": host.Hostname,
-				},
+		alert, ok := alerts[host.Hostname]
+		if !ok || alert.Date.Before(host.CreatedAt) {
+			// there isn't an alert or it was raised before the last hostdata
+			if err := job.AlertSvcClient.ThrowNewAlert(job.newNoDataAlert(host, policy, severity, elapsed)); err != nil {
+				job.Log.Error(err)
 			}
 
-			errAlert := job.AlertSvcClient.ThrowNewAlert(alert)
-			if errAlert != nil {
-				job.Log.Error(errAlert)
-				continue
+			continue
+		}
+
+		if alert.AlertStatus == model.AlertStatusDismissed {
+			continue
+		}
+
+		if err := job.updateNoDataAlert(alert, host, policy, severity, elapsed); err != nil {
+			job.Log.Error(err)
+		}
+	}
+
+	for hostname, alert := range alerts {
+		if activeHosts[hostname] || alert.AlertStatus == model.AlertStatusDismissed {
+			continue
+		}
+
+		if err := job.Database.ResolveNoDataAlertsOfInactiveHost(hostname, now); err != nil {
+			job.Log.Error(err)
+		}
+	}
+}
+
+// getFreshnessPolicy return the first policy matching the host or, if none matches,
+// the default one that raises a CRITICAL alert after the host period
+func (job *FreshnessCheckJob) getFreshnessPolicy(host *model.HostDataBE) config.FreshnessPolicy {
+	for _, policy := range job.Config.DataService.FreshnessCheckJob.Policies {
+		if hostMatches(host, nil, policy.Environments, policy.Locations, policy.Tags) {
+			return policy
+		}
+	}
+
+	period := int(host.Period)
+	if period <= 0 {
+		period = 24
+	}
+
+	return config.FreshnessPolicy{CriticalAfterHours: period}
+}
+
+func (job *FreshnessCheckJob) isInMaintenanceWindow(host *model.HostDataBE, now time.Time) bool {
+	for _, window := range job.Config.DataService.FreshnessCheckJob.MaintenanceWindows {
+		if !now.Before(window.Start) && now.Before(window.End) &&
+			hostMatches(host, window.Hostnames, window.Environments, window.Locations, window.Tags) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hostMatches return true if the host matches every non-empty list
+func hostMatches(host *model.HostDataBE, hostnames, environments, locations, tags []string) bool {
+	if len(hostnames) > 0 && !utils.Contains(hostnames, host.Hostname) {
+		return false
+	}
+
+	if len(environments) > 0 && !utils.Contains(environments, host.Environment) {
+		return false
+	}
+
+	if len(locations) > 0 && !utils.Contains(locations, host.Location) {
+		return false
+	}
+
+	if len(tags) > 0 {
+		for _, tag := range host.Tags {
+			if utils.Contains(tags, tag) {
+				return true
 			}
 		}
+
+		return false
+	}
+
+	return true
+}
+
+// freshnessSeverity return the severity of the NO_DATA alert of a host without data since elapsed,
+// or an empty string if the host is fresh
+func freshnessSeverity(policy config.FreshnessPolicy, elapsed time.Duration) string {
+	switch {
+	case policy.CriticalAfterHours > 0 && elapsed >= time.Duration(policy.CriticalAfterHours)*time.Hour:
+		return model.AlertSeverityCritical
+	case policy.WarningAfterHours > 0 && elapsed >= time.Duration(policy.WarningAfterHours)*time.Hour:
+		return model.AlertSeverityWarning
+	default:
+		return ""
+	}
+}
+
+func (job *FreshnessCheckJob) newNoDataAlert(host *model.HostDataBE, policy config.FreshnessPolicy, severity string,
+	elapsed time.Duration) model.Alert {
+	description, otherInfo := noDataAlertInfo(host, policy, elapsed)
+
+	return model.Alert{
+		ID:                      job.NewObjectID(),
+		AlertAffectedTechnology: nil,
+		AlertCategory:           model.AlertCategoryAgent,
+		AlertCode:               model.AlertCodeNoData,
+		AlertSeverity:           severity,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    job.TimeNow(),
+		Description:             description,
+		OtherInfo:               otherInfo,
+	}
+}
+
+// updateNoDataAlert update the alert, keeping its status and date, if anything is changed
+func (job *FreshnessCheckJob) updateNoDataAlert(alert model.Alert, host *model.HostDataBE, policy config.FreshnessPolicy,
+	severity string, elapsed time.Duration) error {
+	description, otherInfo := noDataAlertInfo(host, policy, elapsed)
+
+	var historyEntry *model.AlertHistoryEntry
+
+	if alert.AlertSeverity != severity {
+		historyEntry = &model.AlertHistoryEntry{
+			Date:     job.TimeNow(),
+			Username: model.AlertSystemUsername,
+			Action:   model.AlertActionSeverityChange,
+			Severity: severity,
+			Comment:  description,
+		}
+	} else if alert.Description == description && reflect.DeepEqual(alert.OtherInfo, otherInfo) {
+		return nil
 	}
+
+	alert.AlertSeverity = severity
+	alert.Description = description
+	alert.OtherInfo = otherInfo
+
+	return job.Database.UpdateNoDataAlert(alert, historyEntry)
+}
+
+func noDataAlertInfo(host *model.HostDataBE, policy config.FreshnessPolicy, elapsed time.Duration) (string, map[string]interface{}) {
+	elapsedDays := int(elapsed.Truncate(time.Hour*24).Hours() / 24)
+
+	description := fmt.Sprintf("No data received from the host %s in the last %d day(s)", host.Hostname, elapsedDays)
+	otherInfo := map[string]interface{}{
+		"hostname": host.Hostname,
+	}
+
+	if policy.Name != "" {
+		otherInfo["freshnessPolicy"] = policy.Name
+	}
+
+	if policy.DismissSuggestionAfterDays > 0 && elapsedDays >= policy.DismissSuggestionAfterDays {
+		description += ", consider dismissing it"
+		otherInfo["dismissSuggested"] = true
+	}
+
+	return description, otherInfo
 }
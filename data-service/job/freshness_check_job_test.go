@@ -12,7 +12,6 @@
 //
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
-
 package job
 
 import (
@@ -20,7 +19,9 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
 	"github.com/ercole-io/ercole/v2/model"
 	"github.com/ercole-io/ercole/v2/utils"
@@ -41,15 +42,12 @@ func TestFreshnessCheckJobRun_SuccessNoOldCurrentHosts(t *testing.T) {
 	pippo := []model.HostDataBE{
 		{
 			Hostname:  "pippohost",
-			CreatedAt: utils.P("2019-10-05T14:02:03Z"),
+			CreatedAt: utils.P("2019-11-05T10:02:03Z"),
 		},
 	}
 
-	db.EXPECT().DeleteAllNoDataAlerts().Return(nil).Times(1)
-
 	db.EXPECT().GetActiveHostdata().Return(pippo, nil)
-
-	db.EXPECT().FindOldCurrentHostdata(pippo[0].Hostname, utils.P("2019-11-04T14:02:03Z")).Return(false, nil)
+	db.EXPECT().FindUnresolvedNoDataAlerts().Return([]model.Alert{}, nil)
 
 	fcj.Run()
 }
@@ -69,35 +67,19 @@ func TestFreshnessCheckJobRun_SuccessTwoOldCurrentHosts(t *testing.T) {
 		NewObjectID:    utils.NewObjectIDForTests(),
 	}
 
-	db.EXPECT().DeleteAllNoDataAlerts().Return(nil).Times(1)
-
-	pippo := model.HostDataBE{
-		Hostname:  "pippohost",
-		CreatedAt: utils.P("2019-10-05T14:02:03Z"),
-	}
-	pluto := model.HostDataBE{
-		Hostname:  "plutohost",
-		CreatedAt: utils.P("2019-10-15T14:02:03Z"),
-	}
-
 	hosts := []model.HostDataBE{
 		{
-			Hostname:  pippo.Hostname,
-			CreatedAt: pippo.CreatedAt,
+			Hostname:  "pippohost",
+			CreatedAt: utils.P("2019-10-05T14:02:03Z"),
 		},
 		{
-			Hostname:  pluto.Hostname,
-			CreatedAt: pluto.CreatedAt,
+			Hostname:  "plutohost",
+			CreatedAt: utils.P("2019-10-15T14:02:03Z"),
 		},
 	}
 
 	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
-
-	db.EXPECT().FindOldCurrentHostdata(pippo.Hostname, utils.P("2019-11-04T14:02:03Z")).
-		Return(true, nil)
-
-	db.EXPECT().FindOldCurrentHostdata(pluto.Hostname, utils.P("2019-11-04T14:02:03Z")).
-		Return(true, nil)
+	db.EXPECT().FindUnresolvedNoDataAlerts().Return([]model.Alert{}, nil)
 
 	alert1 := model.Alert{
 		ID:                      utils.Str2oid("000000000000000000000001"),
@@ -134,7 +116,7 @@ func TestFreshnessCheckJobRun_SuccessTwoOldCurrentHosts(t *testing.T) {
 	fcj.Run()
 }
 
-func TestFreshnessCheckJobRun_DeleteAllNoDataAlertsError(t *testing.T) {
+func TestFreshnessCheckJobRun_GetActiveHostdataError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	db := NewMockMongoDatabaseInterface(mockCtrl)
@@ -146,12 +128,12 @@ func TestFreshnessCheckJobRun_DeleteAllNoDataAlertsError(t *testing.T) {
 		NewObjectID:    utils.NewObjectIDForTests(),
 	}
 
-	db.EXPECT().DeleteAllNoDataAlerts().Return(aerrMock).Times(1)
+	db.EXPECT().GetActiveHostdata().Return(nil, aerrMock).Times(1)
 
 	fcj.Run()
 }
 
-func TestFreshnessCheckJobRun_FindOldCurrentHostdataError(t *testing.T) {
+func TestFreshnessCheckJobRun_FindUnresolvedNoDataAlertsError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	db := NewMockMongoDatabaseInterface(mockCtrl)
@@ -171,8 +153,7 @@ func TestFreshnessCheckJobRun_FindOldCurrentHostdataError(t *testing.T) {
 	}
 
 	db.EXPECT().GetActiveHostdata().Return(pippo, nil)
-	db.EXPECT().DeleteAllNoDataAlerts().Return(nil).Times(1)
-	db.EXPECT().FindOldCurrentHostdata(gomock.Any(), gomock.Any()).Return(true, aerrMock).Times(1)
+	db.EXPECT().FindUnresolvedNoDataAlerts().Return(nil, aerrMock).Times(1)
 
 	fcj.Run()
 }
@@ -182,7 +163,6 @@ func TestFreshnessCheckJobRun_ThrowNoDataAlertError(t *testing.T) {
 	defer mockCtrl.Finish()
 	db := NewMockMongoDatabaseInterface(mockCtrl)
 	asc := NewMockAlertSvcClientInterface(mockCtrl)
-	now := utils.Btc(utils.P("2019-11-05T14:02:03Z"))
 
 	fcj := FreshnessCheckJob{
 		TimeNow:        utils.Btc(utils.P("2019-11-05T14:02:03Z")),
@@ -192,67 +172,258 @@ func TestFreshnessCheckJobRun_ThrowNoDataAlertError(t *testing.T) {
 		NewObjectID:    utils.NewObjectIDForTests(),
 	}
 
-	db.EXPECT().DeleteAllNoDataAlerts().Return(nil).Times(1)
-
-	pippo := model.HostDataBE{
-		Hostname:  "pippohost",
-		CreatedAt: utils.P("2019-10-05T14:02:03Z"),
+	hosts := []model.HostDataBE{
+		{
+			Hostname:  "pippohost",
+			CreatedAt: utils.P("2019-10-05T14:02:03Z"),
+		},
+		{
+			Hostname:  "plutohost",
+			CreatedAt: utils.P("2019-10-15T14:02:03Z"),
+		},
 	}
-	pluto := model.HostDataBE{
-		Hostname:  "plutohost",
-		CreatedAt: utils.P("2019-10-15T14:02:03Z"),
+
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().FindUnresolvedNoDataAlerts().Return([]model.Alert{}, nil)
+
+	asc.EXPECT().ThrowNewAlert(gomock.Any()).Return(aerrMock).Times(2)
+
+	fcj.Run()
+}
+
+func TestFreshnessCheckJobRun_Policies(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	now := utils.Btc(utils.P("2019-11-05T14:02:03Z"))
+
+	fcj := FreshnessCheckJob{
+		TimeNow:        now,
+		Database:       db,
+		AlertSvcClient: asc,
+		Config: config.Configuration{
+			DataService: config.DataService{
+				FreshnessCheckJob: config.FreshnessCheckJob{
+					Policies: []config.FreshnessPolicy{
+						{
+							Name:                       "production",
+							Environments:               []string{"PRD"},
+							WarningAfterHours:          6,
+							CriticalAfterHours:         48,
+							DismissSuggestionAfterDays: 30,
+						},
+						{
+							Name:              "lab",
+							Tags:              []string{"lab"},
+							WarningAfterHours: 72,
+						},
+					},
+					MaintenanceWindows: []config.MaintenanceWindow{
+						{
+							Locations: []string{"Germany"},
+							Start:     utils.P("2019-11-05T00:00:00Z"),
+							End:       utils.P("2019-11-06T00:00:00Z"),
+						},
+						{
+							Hostnames: []string{"pluto"},
+							Start:     utils.P("2019-11-01T00:00:00Z"),
+							End:       utils.P("2019-11-02T00:00:00Z"),
+						},
+					},
+				},
+			},
+		},
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
 	}
 
 	hosts := []model.HostDataBE{
+		{Hostname: "warning", Environment: "PRD", CreatedAt: utils.P("2019-11-05T02:02:03Z")},
+		{Hostname: "critical", Environment: "PRD", CreatedAt: utils.P("2019-11-02T14:02:03Z")},
+		{Hostname: "dismiss", Environment: "PRD", CreatedAt: utils.P("2019-10-05T14:02:03Z")},
+		{Hostname: "lab", Environment: "TST", Tags: []string{"lab"}, CreatedAt: utils.P("2019-11-03T14:02:03Z")},
+		{Hostname: "maintenance", Environment: "PRD", Location: "Germany", CreatedAt: utils.P("2019-10-05T14:02:03Z")},
+		{Hostname: "pluto", Environment: "TST", CreatedAt: utils.P("2019-11-03T14:02:03Z")},
+	}
+
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().FindUnresolvedNoDataAlerts().Return([]model.Alert{}, nil)
+
+	expected := []model.Alert{
 		{
-			Hostname:  pippo.Hostname,
-			CreatedAt: pippo.CreatedAt,
+			ID:            utils.Str2oid("000000000000000000000001"),
+			AlertCategory: model.AlertCategoryAgent,
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityWarning,
+			AlertStatus:   model.AlertStatusNew,
+			Date:          now(),
+			Description:   "No data received from the host warning in the last 0 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "warning", "freshnessPolicy": "production"},
 		},
 		{
-			Hostname:  pluto.Hostname,
-			CreatedAt: pluto.CreatedAt,
+			ID:            utils.Str2oid("000000000000000000000002"),
+			AlertCategory: model.AlertCategoryAgent,
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			AlertStatus:   model.AlertStatusNew,
+			Date:          now(),
+			Description:   "No data received from the host critical in the last 3 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "critical", "freshnessPolicy": "production"},
+		},
+		{
+			ID:            utils.Str2oid("000000000000000000000003"),
+			AlertCategory: model.AlertCategoryAgent,
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			AlertStatus:   model.AlertStatusNew,
+			Date:          now(),
+			Description:   "No data received from the host dismiss in the last 31 day(s), consider dismissing it",
+			OtherInfo: map[string]interface{}{
+				"hostname":         "dismiss",
+				"freshnessPolicy":  "production",
+				"dismissSuggested": true,
+			},
+		},
+		{
+			ID:            utils.Str2oid("000000000000000000000004"),
+			AlertCategory: model.AlertCategoryAgent,
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			AlertStatus:   model.AlertStatusNew,
+			Date:          now(),
+			Description:   "No data received from the host pluto in the last 2 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "pluto"},
 		},
 	}
+	for _, alert := range expected {
+		asc.EXPECT().ThrowNewAlert(alert).Return(nil).Times(1)
+	}
 
-	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
-
-	db.EXPECT().FindOldCurrentHostdata(pippo.Hostname, utils.P("2019-11-04T14:02:03Z")).
-		Return(true, nil)
+	fcj.Run()
+}
 
-	db.EXPECT().FindOldCurrentHostdata(pluto.Hostname, utils.P("2019-11-04T14:02:03Z")).
-		Return(true, nil)
+func TestFreshnessCheckJobRun_ExistingAlerts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	now := utils.Btc(utils.P("2019-11-05T14:02:03Z"))
 
-	alert1 := model.Alert{
-		ID:                      utils.Str2oid("000000000000000000000001"),
-		AlertAffectedTechnology: nil,
-		AlertCategory:           model.AlertCategoryAgent,
-		AlertCode:               model.AlertCodeNoData,
-		AlertSeverity:           model.AlertSeverityCritical,
-		AlertStatus:             model.AlertStatusNew,
-		Date:                    now(),
-		Description: fmt.Sprintf("No data received from the host %s in the last %d day(s)",
-			"pippohost", 31),
-		OtherInfo: map[string]interface{}{
-			"hostname": "pippohost",
+	fcj := FreshnessCheckJob{
+		TimeNow:        now,
+		Database:       db,
+		AlertSvcClient: asc,
+		Config: config.Configuration{
+			DataService: config.DataService{
+				FreshnessCheckJob: config.FreshnessCheckJob{
+					Policies: []config.FreshnessPolicy{
+						{WarningAfterHours: 24, CriticalAfterHours: 72},
+					},
+				},
+			},
 		},
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
 	}
-	asc.EXPECT().ThrowNewAlert(alert1).Return(aerrMock).Times(1)
 
-	alert2 := model.Alert{
-		ID:                      utils.Str2oid("000000000000000000000002"),
-		AlertAffectedTechnology: nil,
-		AlertCategory:           model.AlertCategoryAgent,
-		AlertCode:               model.AlertCodeNoData,
-		AlertSeverity:           model.AlertSeverityCritical,
-		AlertStatus:             model.AlertStatusNew,
-		Date:                    now(),
-		Description: fmt.Sprintf("No data received from the host %s in the last %d day(s)",
-			"plutohost", 21),
-		OtherInfo: map[string]interface{}{
-			"hostname": "plutohost",
+	hosts := []model.HostDataBE{
+		{Hostname: "unchanged", CreatedAt: utils.P("2019-11-04T12:02:03Z")},
+		{Hostname: "escalated", CreatedAt: utils.P("2019-11-01T14:02:03Z")},
+		{Hostname: "dismissed", CreatedAt: utils.P("2019-11-01T14:02:03Z")},
+		{Hostname: "old", CreatedAt: utils.P("2019-11-03T14:02:03Z")},
+	}
+
+	existing := []model.Alert{
+		{
+			ID:            utils.Str2oid("5dd40bfb12f54dfda7b1c291"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityWarning,
+			AlertStatus:   model.AlertStatusAck,
+			Date:          utils.P("2019-11-05T13:02:03Z"),
+			Description:   "No data received from the host unchanged in the last 1 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "unchanged"},
+		},
+		{
+			ID:            utils.Str2oid("5dd40bfb12f54dfda7b1c292"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityWarning,
+			AlertStatus:   model.AlertStatusAck,
+			Date:          utils.P("2019-11-02T15:02:03Z"),
+			Description:   "No data received from the host escalated in the last 1 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "escalated"},
+		},
+		{
+			ID:            utils.Str2oid("5dd40bfb12f54dfda7b1c293"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityWarning,
+			AlertStatus:   model.AlertStatusDismissed,
+			Date:          utils.P("2019-11-02T15:02:03Z"),
+			Description:   "No data received from the host dismissed in the last 1 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "dismissed"},
+		},
+		{
+			ID:            utils.Str2oid("5dd40bfb12f54dfda7b1c294"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityWarning,
+			AlertStatus:   model.AlertStatusDismissed,
+			Date:          utils.P("2019-10-02T15:02:03Z"),
+			Description:   "No data received from the host old in the last 1 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "old"},
+		},
+		{
+			ID:            utils.Str2oid("5dd40bfb12f54dfda7b1c295"),
+			AlertCode:     model.AlertCodeNoData,
+			AlertSeverity: model.AlertSeverityCritical,
+			AlertStatus:   model.AlertStatusNew,
+			Date:          utils.P("2019-10-02T15:02:03Z"),
+			Description:   "No data received from the host removed in the last 5 day(s)",
+			OtherInfo:     map[string]interface{}{"hostname": "removed"},
 		},
 	}
-	asc.EXPECT().ThrowNewAlert(alert2).Return(aerrMock).Times(1)
+
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().FindUnresolvedNoDataAlerts().Return(existing, nil)
+
+	escalated := existing[1]
+	escalated.AlertSeverity = model.AlertSeverityCritical
+	escalated.Description = "No data received from the host escalated in the last 4 day(s)"
+	db.EXPECT().UpdateNoDataAlert(escalated, &model.AlertHistoryEntry{
+		Date:     now(),
+		Username: model.AlertSystemUsername,
+		Action:   model.AlertActionSeverityChange,
+		Severity: model.AlertSeverityCritical,
+		Comment:  escalated.Description,
+	}).Return(nil).Times(1)
+
+	asc.EXPECT().ThrowNewAlert(model.Alert{
+		ID:            utils.Str2oid("000000000000000000000001"),
+		AlertCategory: model.AlertCategoryAgent,
+		AlertCode:     model.AlertCodeNoData,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusNew,
+		Date:          now(),
+		Description:   "No data received from the host old in the last 2 day(s)",
+		OtherInfo:     map[string]interface{}{"hostname": "old"},
+	}).Return(nil).Times(1)
+
+	db.EXPECT().ResolveNoDataAlertsOfInactiveHost("removed", now()).Return(nil).Times(1)
 
 	fcj.Run()
 }
+
+func TestHostMatches(t *testing.T) {
+	host := &model.HostDataBE{
+		Hostname:    "pippo",
+		Environment: "PRD",
+		Location:    "Italy",
+		Tags:        []string{"foo", "bar"},
+	}
+
+	assert.True(t, hostMatches(host, nil, nil, nil, nil))
+	assert.True(t, hostMatches(host, []string{"pippo"}, []string{"PRD", "TST"}, []string{"Italy"}, []string{"bar"}))
+	assert.False(t, hostMatches(host, []string{"pluto"}, nil, nil, nil))
+	assert.False(t, hostMatches(host, nil, []string{"TST"}, nil, nil))
+	assert.False(t, hostMatches(host, nil, nil, []string{"Germany"}, nil))
+	assert.False(t, hostMatches(host, nil, nil, nil, []string{"baz"}))
+}
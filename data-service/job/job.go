@@ -25,6 +25,7 @@ import (
 	alert_service_client "github.com/ercole-io/ercole/v2/alert-service/client"
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/data-service/database"
+	"github.com/ercole-io/ercole/v2/data-service/service"
 	"github.com/ercole-io/ercole/v2/logger"
 )
 
@@ -36,6 +37,7 @@ type Job struct {
 	Config        config.Configuration
 	ServerVersion string
 	Database      database.MongoDatabaseInterface
+	Service       service.HostDataServiceInterface
 	TimeNow       func() time.Time
 	Log           logger.Logger
 }
@@ -79,6 +81,87 @@ func (j *Job) Init() {
 		jobrunner.Now(freshnessJob)
 	}
 
+	capacityForecastJob := &CapacityForecastJob{
+		TimeNow:        j.TimeNow,
+		Database:       j.Database,
+		AlertSvcClient: alert_service_client.NewClient(j.Config.AlertService),
+		Config:         j.Config,
+		Log:            j.Log,
+		NewObjectID: func() primitive.ObjectID {
+			return primitive.NewObjectIDFromTimestamp(j.TimeNow())
+		},
+	}
+	if err := jobrunner.Schedule(j.Config.DataService.CapacityForecastJob.Crontab, capacityForecastJob); err != nil {
+		j.Log.Errorf("Something went wrong scheduling CapacityForecastJob: %v", err)
+	}
+
+	if j.Config.DataService.CapacityForecastJob.RunAtStartup {
+		jobrunner.Now(capacityForecastJob)
+	}
+
+	if len(j.Config.DataService.BackupComplianceJob.Policies) > 0 {
+		backupComplianceJob := &BackupComplianceJob{
+			TimeNow:        j.TimeNow,
+			Database:       j.Database,
+			AlertSvcClient: alert_service_client.NewClient(j.Config.AlertService),
+			Config:         j.Config,
+			Log:            j.Log,
+			NewObjectID: func() primitive.ObjectID {
+				return primitive.NewObjectIDFromTimestamp(j.TimeNow())
+			},
+		}
+		if err := jobrunner.Schedule(j.Config.DataService.BackupComplianceJob.Crontab, backupComplianceJob); err != nil {
+			j.Log.Errorf("Something went wrong scheduling BackupComplianceJob: %v", err)
+		}
+
+		if j.Config.DataService.BackupComplianceJob.RunAtStartup {
+			jobrunner.Now(backupComplianceJob)
+		}
+	}
+
+	patchAdvisorJob := &PatchAdvisorJob{
+		TimeNow:        j.TimeNow,
+		Database:       j.Database,
+		AlertSvcClient: alert_service_client.NewClient(j.Config.AlertService),
+		Config:         j.Config,
+		Log:            j.Log,
+		NewObjectID: func() primitive.ObjectID {
+			return primitive.NewObjectIDFromTimestamp(j.TimeNow())
+		},
+	}
+	if err := jobrunner.Schedule(j.Config.DataService.PatchAdvisorJob.Crontab, patchAdvisorJob); err != nil {
+		j.Log.Errorf("Something went wrong scheduling PatchAdvisorJob: %v", err)
+	}
+
+	if j.Config.DataService.PatchAdvisorJob.RunAtStartup {
+		jobrunner.Now(patchAdvisorJob)
+	}
+
+	consumptionDownsamplingJob := &ConsumptionDownsamplingJob{
+		TimeNow:  j.TimeNow,
+		Database: j.Database,
+		Config:   j.Config,
+		Log:      j.Log,
+	}
+	if err := jobrunner.Schedule(j.Config.DataService.ConsumptionMetrics.DownsamplingJob.Crontab, consumptionDownsamplingJob); err != nil {
+		j.Log.Errorf("Something went wrong scheduling ConsumptionDownsamplingJob: %v", err)
+	}
+
+	if j.Config.DataService.ConsumptionMetrics.DownsamplingJob.RunAtStartup {
+		jobrunner.Now(consumptionDownsamplingJob)
+	}
+
+	if len(j.Config.DataService.CmdbSyncJob.Sources) > 0 {
+		cmdbSyncJob := &CmdbSyncJob{Service: j.Service, Log: j.Log}
+		if err := jobrunner.Schedule(j.Config.DataService.CmdbSyncJob.Crontab, cmdbSyncJob); err != nil {
+			j.Log.Errorf("Something went wrong scheduling CmdbSyncJob: %v", err)
+		}
+
+		if j.Config.DataService.CmdbSyncJob.RunAtStartup {
+			jobrunner.Now(cmdbSyncJob)
+		}
+	}
+
 	historicizeLicensesComplianceJob := &HistoricizeLicensesComplianceJob{
 		Database: j.Database,
 		TimeNow:  j.TimeNow,
@@ -43,9 +43,17 @@ func (job *HistoricizeLicensesComplianceJob) Run() {
 		job.Config.APIService.AuthenticationProvider.Password,
 		"/hosts/technologies/all/databases/licenses-compliance").String()
 
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	utils.SetAPIToken(req, job.Config.APIService.AuthenticationProvider.APIToken)
+
 	client := http.Client{Timeout: 1 * time.Minute}
 
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil || resp == nil {
 		err = fmt.Errorf("Error while retrieving licenses compliance: [%w], response: [%v]", err, resp)
 		job.Log.Error(err)
@@ -0,0 +1,249 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+
+	alert_service_client "github.com/ercole-io/ercole/v2/alert-service/client"
+	"github.com/ercole-io/ercole/v2/data-service/database"
+)
+
+// PatchAdvisorJob is the job used to raise the alerts of the Oracle databases behind the patch catalogue
+type PatchAdvisorJob struct {
+	// TimeNow contains a function that return the current time
+	TimeNow func() time.Time
+	// Database contains the database layer
+	Database database.MongoDatabaseInterface
+	// AlertSvcClient
+	AlertSvcClient alert_service_client.AlertSvcClientInterface
+	// Config contains the dataservice global configuration
+	Config config.Configuration
+	// Log contains logger formatted
+	Log logger.Logger
+	// NewObjectID return a new ObjectID
+	NewObjectID func() primitive.ObjectID
+}
+
+// patchAlertKey identifies the ORACLE_PATCH_OUTDATED alert of a database
+type patchAlertKey struct {
+	hostname     string
+	databaseName string
+}
+
+// Run compares the patches of the Oracle databases of the active hosts with the catalogue
+// and throws, escalates or resolves their ORACLE_PATCH_OUTDATED alerts
+func (job *PatchAdvisorJob) Run() {
+	hosts, err := job.Database.GetActiveHostdata()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	catalogue, err := job.Database.GetOraclePatchCatalogue()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	unresolvedAlerts, err := job.Database.FindUnresolvedPatchAlerts()
+	if err != nil {
+		job.Log.Error(err)
+		return
+	}
+
+	alerts := make(map[patchAlertKey]model.Alert, len(unresolvedAlerts))
+
+	for _, alert := range unresolvedAlerts {
+		key := patchAlertKey{}
+		key.hostname, _ = alert.OtherInfo["hostname"].(string)
+		key.databaseName, _ = alert.OtherInfo["dbname"].(string)
+
+		if other, ok := alerts[key]; !ok || other.Date.Before(alert.Date) {
+			alerts[key] = alert
+		}
+	}
+
+	now := job.TimeNow()
+
+	for i := range hosts {
+		host := &hosts[i]
+		if host.Features.Oracle == nil || host.Features.Oracle.Database == nil {
+			continue
+		}
+
+		for j := range host.Features.Oracle.Database.Databases {
+			db := &host.Features.Oracle.Database.Databases[j]
+
+			description, date := latestOraclePSU(db.PSUs)
+			advice := catalogue.Advise(db.Version, description, date, now)
+			severity := job.patchSeverity(&advice)
+
+			key := patchAlertKey{hostname: host.Hostname, databaseName: db.Name}
+			alert, ok := alerts[key]
+			delete(alerts, key)
+
+			switch {
+			case !ok && severity == "":
+			case !ok:
+				if err := job.AlertSvcClient.ThrowNewAlert(job.newPatchAlert(key, &advice, severity)); err != nil {
+					job.Log.Error(err)
+				}
+			case alert.AlertStatus == model.AlertStatusDismissed:
+			case severity == "":
+				if err := job.Database.ResolvePatchAlert(alert.ID, now, "The database is patched as required"); err != nil {
+					job.Log.Error(err)
+				}
+			default:
+				if err := job.updatePatchAlert(alert, key, &advice, severity); err != nil {
+					job.Log.Error(err)
+				}
+			}
+		}
+	}
+
+	for _, alert := range alerts {
+		if alert.AlertStatus == model.AlertStatusDismissed {
+			continue
+		}
+
+		if err := job.Database.ResolvePatchAlert(alert.ID, now, "The database isn't active anymore"); err != nil {
+			job.Log.Error(err)
+		}
+	}
+}
+
+// latestOraclePSU return the description and the date of the latest PSU, a zero date if it's unknown
+func latestOraclePSU(psus []model.OracleDatabasePSU) (string, time.Time) {
+	var description string
+
+	var latest time.Time
+
+	for _, psu := range psus {
+		date, err := time.Parse("2006-01-02", psu.Date)
+		if err != nil {
+			continue
+		}
+
+		if description == "" || date.After(latest) {
+			description = psu.Description
+			latest = date
+		}
+	}
+
+	return description, latest
+}
+
+// patchSeverity return the severity of the alert of the advice, or an empty string if the database is patched as required
+func (job *PatchAdvisorJob) patchSeverity(advice *model.OraclePatchAdvice) string {
+	jobConfig := job.Config.DataService.PatchAdvisorJob
+
+	behind := 0
+	if advice.ReleasesBehind != nil {
+		behind = *advice.ReleasesBehind
+	}
+
+	switch {
+	case jobConfig.AlertUnsupportedVersions && advice.SupportStatus == model.OracleSupportUnsupported:
+		return model.AlertSeverityCritical
+	case jobConfig.CriticalReleasesBehind > 0 && behind >= jobConfig.CriticalReleasesBehind:
+		return model.AlertSeverityCritical
+	case jobConfig.WarningReleasesBehind > 0 && behind >= jobConfig.WarningReleasesBehind:
+		return model.AlertSeverityWarning
+	default:
+		return ""
+	}
+}
+
+func (job *PatchAdvisorJob) newPatchAlert(key patchAlertKey, advice *model.OraclePatchAdvice, severity string) model.Alert {
+	return model.Alert{
+		ID:                      job.NewObjectID(),
+		AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               model.AlertCodeOraclePatchOutdated,
+		AlertSeverity:           severity,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    job.TimeNow(),
+		Description:             patchAlertDescription(key, advice),
+		OtherInfo:               patchAlertOtherInfo(key, advice),
+	}
+}
+
+// updatePatchAlert update the alert, keeping its status and date, if anything is changed
+func (job *PatchAdvisorJob) updatePatchAlert(alert model.Alert, key patchAlertKey, advice *model.OraclePatchAdvice, severity string) error {
+	description := patchAlertDescription(key, advice)
+
+	var historyEntry *model.AlertHistoryEntry
+
+	if alert.AlertSeverity != severity {
+		historyEntry = &model.AlertHistoryEntry{
+			Date:     job.TimeNow(),
+			Username: model.AlertSystemUsername,
+			Action:   model.AlertActionSeverityChange,
+			Severity: severity,
+			Comment:  description,
+		}
+	} else if alert.Description == description {
+		return nil
+	}
+
+	alert.AlertSeverity = severity
+	alert.Description = description
+	alert.OtherInfo = patchAlertOtherInfo(key, advice)
+
+	return job.Database.UpdatePatchAlert(alert, historyEntry)
+}
+
+func patchAlertDescription(key patchAlertKey, advice *model.OraclePatchAdvice) string {
+	problems := make([]string, 0, 2)
+
+	if advice.ReleasesBehind != nil && *advice.ReleasesBehind > 0 && advice.LatestRelease != nil {
+		problems = append(problems, fmt.Sprintf("is %d release(s) behind the latest release %s (%s)",
+			*advice.ReleasesBehind, advice.LatestRelease.Release, advice.LatestRelease.PatchNumber))
+	}
+
+	if advice.SupportStatus == model.OracleSupportUnsupported {
+		problems = append(problems, fmt.Sprintf("has the version %s out of extended support", advice.CatalogueVersion))
+	}
+
+	return fmt.Sprintf("The database %s on the host %s %s", key.databaseName, key.hostname, strings.Join(problems, " and "))
+}
+
+func patchAlertOtherInfo(key patchAlertKey, advice *model.OraclePatchAdvice) map[string]interface{} {
+	otherInfo := map[string]interface{}{
+		"hostname":      key.hostname,
+		"dbname":        key.databaseName,
+		"version":       advice.CatalogueVersion,
+		"supportStatus": advice.SupportStatus,
+	}
+
+	if advice.ReleasesBehind != nil {
+		otherInfo["releasesBehind"] = *advice.ReleasesBehind
+	}
+
+	if advice.LatestRelease != nil {
+		otherInfo["latestPatchNumber"] = advice.LatestRelease.PatchNumber
+	}
+
+	return otherInfo
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package job
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func patchAdvisorTestCatalogue() *model.OraclePatchCatalogue {
+	return &model.OraclePatchCatalogue{
+		Releases: []model.OraclePatchRelease{
+			{PatchNumber: "30125133", Type: model.OraclePatchTypeRU, Version: "19", Release: "19.5.0.0.191015", ReleaseDate: utils.P("2019-10-15T00:00:00Z")},
+			{PatchNumber: "30557433", Type: model.OraclePatchTypeRU, Version: "19", Release: "19.6.0.0.200114", ReleaseDate: utils.P("2020-01-14T00:00:00Z")},
+			{PatchNumber: "33806152", Type: model.OraclePatchTypeRU, Version: "19", Release: "19.15.0.0.220419", ReleaseDate: utils.P("2022-04-19T00:00:00Z")},
+			{PatchNumber: "16619892", Type: model.OraclePatchTypePSU, Version: "11.2.0.3", Release: "11.2.0.3.7", ReleaseDate: utils.P("2013-07-16T00:00:00Z")},
+			{PatchNumber: "16902043", Type: model.OraclePatchTypePSU, Version: "11.2.0.3", Release: "11.2.0.3.8", ReleaseDate: utils.P("2013-10-15T00:00:00Z")},
+		},
+		Versions: []model.OracleVersionSupport{
+			{Version: "19", PremierSupportEnd: utils.P("2024-04-30T00:00:00Z"), ExtendedSupportEnd: utils.P("2027-04-30T00:00:00Z")},
+			{Version: "11.2.0.3", PremierSupportEnd: utils.P("2015-01-31T00:00:00Z"), ExtendedSupportEnd: utils.P("2015-08-27T00:00:00Z")},
+		},
+	}
+}
+
+func TestLatestOraclePSU(t *testing.T) {
+	description, date := latestOraclePSU([]model.OracleDatabasePSU{
+		{Date: "2019-10-15", Description: "Database Release Update : 19.5.0.0.191015 (30125133)"},
+		{Date: "2020-01-14", Description: "Database Release Update : 19.6.0.0.200114 (30557433)"},
+		{Date: "N/A", Description: "Invalid"},
+	})
+
+	assert.Equal(t, "Database Release Update : 19.6.0.0.200114 (30557433)", description)
+	assert.Equal(t, utils.P("2020-01-14T00:00:00Z"), date)
+
+	description, date = latestOraclePSU(nil)
+	assert.Empty(t, description)
+	assert.True(t, date.IsZero())
+}
+
+func TestPatchAdvisorJobRun_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	now := utils.Btc(utils.P("2023-01-01T00:00:00Z"))
+
+	job := PatchAdvisorJob{
+		TimeNow:        now,
+		Database:       db,
+		AlertSvcClient: asc,
+		Config: config.Configuration{
+			DataService: config.DataService{
+				PatchAdvisorJob: config.PatchAdvisorJob{
+					WarningReleasesBehind:    2,
+					CriticalReleasesBehind:   4,
+					AlertUnsupportedVersions: true,
+				},
+			},
+		},
+		Log:         logger.NewLogger("TEST"),
+		NewObjectID: utils.NewObjectIDForTests(),
+	}
+
+	hosts := []model.HostDataBE{
+		{
+			Hostname: "test-db",
+			Features: model.Features{Oracle: &model.OracleFeature{Database: &model.OracleDatabaseFeature{Databases: []model.OracleDatabase{
+				{
+					Name:    "ERCOLE",
+					Version: "19.0.0.0.0 Enterprise Edition",
+					PSUs:    []model.OracleDatabasePSU{{Date: "2019-10-15", Description: "Database Release Update : 19.5.0.0.191015 (30125133)"}},
+				},
+				{
+					Name:    "OLD",
+					Version: "11.2.0.3.0 Enterprise Edition",
+					PSUs:    []model.OracleDatabasePSU{{Date: "2013-10-15", Description: "PSU 11.2.0.3.8"}},
+				},
+				{
+					Name:    "UPTODATE",
+					Version: "19.0.0.0.0 Enterprise Edition",
+					PSUs:    []model.OracleDatabasePSU{{Date: "2022-04-19", Description: "Database Release Update : 19.15.0.0.220419 (33806152)"}},
+				},
+			}}}},
+		},
+	}
+	db.EXPECT().GetActiveHostdata().Return(hosts, nil)
+	db.EXPECT().GetOraclePatchCatalogue().Return(patchAdvisorTestCatalogue(), nil)
+
+	ercoleAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000010"),
+		AlertCode:     model.AlertCodeOraclePatchOutdated,
+		AlertSeverity: model.AlertSeverityCritical,
+		AlertStatus:   model.AlertStatusAck,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "dbname": "ERCOLE"},
+	}
+	upToDateAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000011"),
+		AlertCode:     model.AlertCodeOraclePatchOutdated,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusNew,
+		OtherInfo:     map[string]interface{}{"hostname": "test-db", "dbname": "UPTODATE"},
+	}
+	goneAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000012"),
+		AlertCode:     model.AlertCodeOraclePatchOutdated,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusNew,
+		OtherInfo:     map[string]interface{}{"hostname": "test-gone", "dbname": "GONE"},
+	}
+	dismissedAlert := model.Alert{
+		ID:            utils.Str2oid("000000000000000000000013"),
+		AlertCode:     model.AlertCodeOraclePatchOutdated,
+		AlertSeverity: model.AlertSeverityWarning,
+		AlertStatus:   model.AlertStatusDismissed,
+		OtherInfo:     map[string]interface{}{"hostname": "test-gone", "dbname": "DISMISSED"},
+	}
+	db.EXPECT().FindUnresolvedPatchAlerts().Return([]model.Alert{ercoleAlert, upToDateAlert, goneAlert, dismissedAlert}, nil)
+
+	description := "The database ERCOLE on the host test-db is 2 release(s) behind the latest release 19.15.0.0.220419 (33806152)"
+	expectedErcoleAlert := ercoleAlert
+	expectedErcoleAlert.AlertSeverity = model.AlertSeverityWarning
+	expectedErcoleAlert.Description = description
+	expectedErcoleAlert.OtherInfo = map[string]interface{}{
+		"hostname":          "test-db",
+		"dbname":            "ERCOLE",
+		"version":           "19",
+		"supportStatus":     model.OracleSupportPremier,
+		"releasesBehind":    2,
+		"latestPatchNumber": "33806152",
+	}
+	db.EXPECT().UpdatePatchAlert(expectedErcoleAlert, &model.AlertHistoryEntry{
+		Date:     now(),
+		Username: model.AlertSystemUsername,
+		Action:   model.AlertActionSeverityChange,
+		Severity: model.AlertSeverityWarning,
+		Comment:  description,
+	}).Return(nil)
+
+	asc.EXPECT().ThrowNewAlert(model.Alert{
+		ID:                      utils.Str2oid("000000000000000000000001"),
+		AlertAffectedTechnology: model.TechnologyOracleDatabasePtr,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               model.AlertCodeOraclePatchOutdated,
+		AlertSeverity:           model.AlertSeverityCritical,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    now(),
+		Description:             "The database OLD on the host test-db has the version 11.2.0.3 out of extended support",
+		OtherInfo: map[string]interface{}{
+			"hostname":          "test-db",
+			"dbname":            "OLD",
+			"version":           "11.2.0.3",
+			"supportStatus":     model.OracleSupportUnsupported,
+			"releasesBehind":    0,
+			"latestPatchNumber": "16902043",
+		},
+	}).Return(nil)
+
+	db.EXPECT().ResolvePatchAlert(upToDateAlert.ID, now(), "The database is patched as required").Return(nil)
+	db.EXPECT().ResolvePatchAlert(goneAlert.ID, now(), "The database isn't active anymore").Return(nil)
+
+	job.Run()
+}
+
+func TestPatchAdvisorJobRun_DatabaseError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	job := PatchAdvisorJob{
+		TimeNow:  utils.Btc(utils.P("2023-01-01T00:00:00Z")),
+		Database: db,
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	db.EXPECT().GetActiveHostdata().Return([]model.HostDataBE{}, nil)
+	db.EXPECT().GetOraclePatchCatalogue().Return(nil, aerrMock)
+
+	job.Run()
+}
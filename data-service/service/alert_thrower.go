@@ -318,3 +318,27 @@ func (hds *HostDataService) throwMissingDatabasesAlert(hostname string, dbNames
 
 	return hds.AlertSvcClient.ThrowNewAlert(alr)
 }
+
+func (hds *HostDataService) throwHostDriftAlert(hostname, alertSeverity string, drift hostDrift) error {
+	otherInfo := map[string]interface{}{
+		"hostname": hostname,
+	}
+
+	for k, v := range drift.otherInfo {
+		otherInfo[k] = v
+	}
+
+	alr := model.Alert{
+		ID:                      primitive.NewObjectIDFromTimestamp(hds.TimeNow()),
+		AlertAffectedTechnology: drift.technology,
+		AlertCategory:           model.AlertCategoryEngine,
+		AlertCode:               drift.alertCode,
+		AlertSeverity:           alertSeverity,
+		AlertStatus:             model.AlertStatusNew,
+		Date:                    hds.TimeNow(),
+		Description:             drift.description,
+		OtherInfo:               otherInfo,
+	}
+
+	return hds.AlertSvcClient.ThrowNewAlert(alr)
+}
@@ -29,11 +29,27 @@ func (hds *HostDataService) CompareCmdbInfo(cmdbInfo dto.CmdbInfo) error {
 		return err
 	}
 
+	identities, err := hds.Database.ListHostIdentities()
+	if err != nil {
+		return err
+	}
+
+	aliases := make(map[string][]string, len(identities))
+	for _, identity := range identities {
+		aliases[identity.Hostname] = identity.Aliases
+	}
+
+	knownHostnames := make([]string, 0, len(hostnames))
+	for _, h := range hostnames {
+		knownHostnames = append(knownHostnames, h)
+		knownHostnames = append(knownHostnames, aliases[h]...)
+	}
+
 	missingAlerts := make([]model.Alert, 0, 2)
 
 	descriptionErcole := ""
 
-	for _, h := range differenceHostnames(cmdbInfo.Hostnames, hostnames) {
+	for _, h := range differenceHostnames(cmdbInfo.Hostnames, knownHostnames) {
 		descriptionErcole += fmt.Sprintf("Received unknown hostname %s from CMDB %s\n", h, cmdbInfo.Name)
 	}
 
@@ -50,8 +66,12 @@ func (hds *HostDataService) CompareCmdbInfo(cmdbInfo dto.CmdbInfo) error {
 
 	descriptionCmdb := ""
 
-	for _, h := range differenceHostnames(hostnames, cmdbInfo.Hostnames) {
-		descriptionCmdb += fmt.Sprintf("Missing hostname %s in CMDB %s\n", h, cmdbInfo.Name)
+	for _, h := range hostnames {
+		// the host is in the CMDB if it's there with its hostname or with one of its aliases
+		names := append([]string{h}, aliases[h]...)
+		if len(differenceHostnames(names, cmdbInfo.Hostnames)) == len(names) {
+			descriptionCmdb += fmt.Sprintf("Missing hostname %s in CMDB %s\n", h, cmdbInfo.Name)
+		}
 	}
 
 	if descriptionCmdb != "" {
@@ -0,0 +1,233 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/data-service/cmdb"
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// SyncCmdbs synchronise the hosts with the configured CMDBs, or only with the one named source if it isn't empty
+func (hds *HostDataService) SyncCmdbs(source string) ([]dto.CmdbSyncReport, error) {
+	reports := make([]dto.CmdbSyncReport, 0)
+
+	for _, conf := range hds.Config.DataService.CmdbSyncJob.Sources {
+		if source != "" && conf.Name != source {
+			continue
+		}
+
+		report := dto.CmdbSyncReport{Source: conf.Name}
+		if err := hds.syncCmdb(conf, &report); err != nil {
+			hds.Log.Errorf("Can't synchronise CMDB %s: %s", conf.Name, err)
+			report.Error = err.Error()
+		}
+
+		reports = append(reports, report)
+	}
+
+	if source != "" && len(reports) == 0 {
+		return nil, utils.NewErrorf("%w: %s", utils.ErrCmdbSourceNotFound, source)
+	}
+
+	return reports, nil
+}
+
+// syncCmdb save the records read from the CMDB in the metadata of the hosts.
+// When the CMDB has no records nothing changes, so an empty drop directory doesn't remove the records
+func (hds *HostDataService) syncCmdb(conf config.CmdbSource, report *dto.CmdbSyncReport) error {
+	source, err := cmdb.NewSource(conf)
+	if err != nil {
+		return err
+	}
+
+	records, err := source.Fetch()
+	if err != nil {
+		return err
+	}
+
+	report.Records = len(records)
+	if len(records) == 0 {
+		return nil
+	}
+
+	resolve, err := hds.cmdbHostnameResolver()
+	if err != nil {
+		return err
+	}
+
+	now := hds.TimeNow()
+	activeHostnames := make([]string, 0, len(records))
+
+	for _, record := range records {
+		record.Source = conf.Name
+		record.SyncedAt = now
+
+		hostname, found := resolve(record.Hostname)
+
+		if err := hds.Database.SaveHostCmdbRecord(hostname, record); err != nil {
+			return err
+		}
+
+		if record.Decommissioned {
+			report.Decommissioned++
+		} else {
+			activeHostnames = append(activeHostnames, hostname)
+		}
+
+		if !found {
+			report.Unmatched++
+			continue
+		}
+
+		report.Matched++
+
+		environment, location := cmdbOverrides(conf, record)
+		if err := hds.Database.UpdateCurrentHostDataEnvironmentAndLocation(hostname, environment, location); err != nil {
+			return err
+		}
+	}
+
+	if err := hds.Database.RemoveStaleCmdbRecords(conf.Name, now); err != nil {
+		return err
+	}
+
+	if err := source.Commit(); err != nil {
+		return err
+	}
+
+	if conf.AlertMissingHosts {
+		return hds.CompareCmdbInfo(dto.CmdbInfo{Name: conf.Name, Hostnames: activeHostnames})
+	}
+
+	return nil
+}
+
+// cmdbHostnameResolver return a function that find the current host of a hostname of the CMDB:
+// the same hostname ignoring the case, an alias, or the same hostname without the domain.
+// It returns the hostname itself, and false, if there isn't any
+func (hds *HostDataService) cmdbHostnameResolver() (func(string) (string, bool), error) {
+	hostnames, err := hds.Database.GetCurrentHostnames()
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := hds.Database.ListHostIdentities()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]string, len(hostnames))
+	byShortName := make(map[string]string, len(hostnames))
+	ambiguous := make(map[string]bool)
+
+	for _, hostname := range hostnames {
+		name := strings.ToLower(hostname)
+		byName[name] = hostname
+
+		short := hostnameWithoutDomain(name)
+		if other, ok := byShortName[short]; ok && other != hostname {
+			ambiguous[short] = true
+		}
+
+		byShortName[short] = hostname
+	}
+
+	for _, identity := range identities {
+		if _, ok := byName[strings.ToLower(identity.Hostname)]; !ok {
+			continue
+		}
+
+		for _, alias := range identity.Aliases {
+			if _, ok := byName[strings.ToLower(alias)]; !ok {
+				byName[strings.ToLower(alias)] = identity.Hostname
+			}
+		}
+	}
+
+	return func(cmdbHostname string) (string, bool) {
+		name := strings.ToLower(cmdbHostname)
+		if hostname, ok := byName[name]; ok {
+			return hostname, true
+		}
+
+		short := hostnameWithoutDomain(name)
+		if hostname, ok := byShortName[short]; ok && !ambiguous[short] {
+			return hostname, true
+		}
+
+		return cmdbHostname, false
+	}, nil
+}
+
+// cmdbOverrides return the environment and the location of the record that override the ones sent by the agent.
+// The values not overridden are empty
+func cmdbOverrides(conf config.CmdbSource, record model.CmdbRecord) (environment, location string) {
+	if conf.OverrideEnvironment {
+		environment = record.Environment
+	}
+
+	if conf.OverrideLocation {
+		location = record.Location
+	}
+
+	return environment, location
+}
+
+// applyCmdbOverrides replace environment and location of the hostdata with the ones from its CMDB, if so configured
+func (hds *HostDataService) applyCmdbOverrides(hostdata *model.HostDataBE) error {
+	sources := make(map[string]config.CmdbSource)
+
+	for _, conf := range hds.Config.DataService.CmdbSyncJob.Sources {
+		if conf.OverrideEnvironment || conf.OverrideLocation {
+			sources[conf.Name] = conf
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil
+	}
+
+	metadata, err := hds.Database.FindHostMetadata(hostdata.Hostname)
+	if err != nil {
+		return err
+	}
+
+	if metadata == nil || metadata.Cmdb == nil {
+		return nil
+	}
+
+	conf, ok := sources[metadata.Cmdb.Source]
+	if !ok {
+		return nil
+	}
+
+	environment, location := cmdbOverrides(conf, *metadata.Cmdb)
+
+	if environment != "" {
+		hostdata.Environment = environment
+	}
+
+	if location != "" {
+		hostdata.Location = location
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func newCmdbFileSource(dir string) config.CmdbSource {
+	return config.CmdbSource{
+		Name:        "export",
+		Type:        config.CmdbSourceTypeFile,
+		Directory:   dir,
+		RecordsPath: "$",
+		Fields: config.CmdbFields{
+			Hostname:       "hostname",
+			Environment:    "environment",
+			Location:       "location",
+			Decommissioned: "decommissioned",
+		},
+	}
+}
+
+func TestSyncCmdbs_Success(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.csv"), []byte(
+		"hostname,environment,location,decommissioned\n"+
+			"FOOBAR.example.org,PRD,Italy,false\n"+
+			"oldbar,TST,Germany,false\n"+
+			"newhost,PRD,Italy,false\n"+
+			"gone,PRD,Italy,true\n"), 0o600))
+
+	source := newCmdbFileSource(dir)
+	source.OverrideEnvironment = true
+	source.AlertMissingHosts = true
+
+	now := utils.P("2019-11-05T14:02:03Z")
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				CmdbSyncJob: config.CmdbSyncJob{Sources: []config.CmdbSource{source}},
+			},
+		},
+		Database:       db,
+		AlertSvcClient: asc,
+		TimeNow:        utils.Btc(now),
+		Log:            logger.NewLogger("TEST"),
+	}
+
+	identities := []model.HostIdentity{{Hostname: "barfoo", Aliases: []string{"oldbar"}}}
+	db.EXPECT().GetCurrentHostnames().Return([]string{"foobar", "barfoo"}, nil).Times(2)
+	db.EXPECT().ListHostIdentities().Return(identities, nil).Times(2)
+
+	record := func(hostname, environment, location string, decommissioned bool) model.CmdbRecord {
+		return model.CmdbRecord{
+			Source:         "export",
+			Hostname:       hostname,
+			Environment:    environment,
+			Location:       location,
+			Decommissioned: decommissioned,
+			SyncedAt:       now,
+		}
+	}
+
+	gomock.InOrder(
+		db.EXPECT().SaveHostCmdbRecord("foobar", record("FOOBAR.example.org", "PRD", "Italy", false)).Return(nil),
+		db.EXPECT().UpdateCurrentHostDataEnvironmentAndLocation("foobar", "PRD", "").Return(nil),
+		db.EXPECT().SaveHostCmdbRecord("barfoo", record("oldbar", "TST", "Germany", false)).Return(nil),
+		db.EXPECT().UpdateCurrentHostDataEnvironmentAndLocation("barfoo", "TST", "").Return(nil),
+		db.EXPECT().SaveHostCmdbRecord("newhost", record("newhost", "PRD", "Italy", false)).Return(nil),
+		db.EXPECT().SaveHostCmdbRecord("gone", record("gone", "PRD", "Italy", true)).Return(nil),
+		db.EXPECT().RemoveStaleCmdbRecords("export", now).Return(nil),
+	)
+
+	asc.EXPECT().ThrowNewAlert(gomock.Any()).
+		Do(func(alert model.Alert) {
+			assert.Equal(t, "Received unknown hostname newhost from CMDB export\n", alert.Description)
+		}).Return(nil)
+
+	actual, err := hds.SyncCmdbs("")
+	require.NoError(t, err)
+
+	expected := []dto.CmdbSyncReport{
+		{Source: "export", Records: 4, Matched: 2, Unmatched: 2, Decommissioned: 1},
+	}
+	assert.Equal(t, expected, actual)
+
+	assert.FileExists(t, filepath.Join(dir, "processed", "hosts.csv"))
+}
+
+func TestSyncCmdbs_NoRecords(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				CmdbSyncJob: config.CmdbSyncJob{Sources: []config.CmdbSource{newCmdbFileSource(t.TempDir())}},
+			},
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	actual, err := hds.SyncCmdbs("export")
+	require.NoError(t, err)
+
+	assert.Equal(t, []dto.CmdbSyncReport{{Source: "export"}}, actual)
+}
+
+func TestSyncCmdbs_DatabaseError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.csv"), []byte(
+		"hostname\nfoobar\n"), 0o600))
+
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				CmdbSyncJob: config.CmdbSyncJob{Sources: []config.CmdbSource{newCmdbFileSource(dir)}},
+			},
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	db.EXPECT().GetCurrentHostnames().Return(nil, aerrMock)
+
+	actual, err := hds.SyncCmdbs("")
+	require.NoError(t, err)
+
+	assert.Equal(t, []dto.CmdbSyncReport{{Source: "export", Records: 1, Error: aerrMock.Error()}}, actual)
+	assert.FileExists(t, filepath.Join(dir, "hosts.csv"))
+}
+
+func TestSyncCmdbs_SourceNotFound(t *testing.T) {
+	hds := HostDataService{
+		Config: config.Configuration{},
+		Log:    logger.NewLogger("TEST"),
+	}
+
+	_, err := hds.SyncCmdbs("foobar")
+	assert.True(t, errors.Is(err, utils.ErrCmdbSourceNotFound))
+}
+
+func TestApplyCmdbOverrides(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+
+	source := newCmdbFileSource("")
+	source.OverrideLocation = true
+
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				CmdbSyncJob: config.CmdbSyncJob{Sources: []config.CmdbSource{source}},
+			},
+		},
+		Database: db,
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	db.EXPECT().FindHostMetadata("foobar").Return(&model.HostMetadata{
+		Hostname: "foobar",
+		Cmdb:     &model.CmdbRecord{Source: "export", Environment: "TST", Location: "Germany"},
+	}, nil)
+
+	hostdata := model.HostDataBE{Hostname: "foobar", Environment: "PRD", Location: "Italy"}
+	require.NoError(t, hds.applyCmdbOverrides(&hostdata))
+
+	assert.Equal(t, "PRD", hostdata.Environment)
+	assert.Equal(t, "Germany", hostdata.Location)
+}
+
+func TestApplyCmdbOverrides_NoOverrides(t *testing.T) {
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				CmdbSyncJob: config.CmdbSyncJob{Sources: []config.CmdbSource{newCmdbFileSource("")}},
+			},
+		},
+		Log: logger.NewLogger("TEST"),
+	}
+
+	hostdata := model.HostDataBE{Hostname: "foobar", Environment: "PRD", Location: "Italy"}
+	require.NoError(t, hds.applyCmdbOverrides(&hostdata))
+
+	assert.Equal(t, "PRD", hostdata.Environment)
+	assert.Equal(t, "Italy", hostdata.Location)
+}
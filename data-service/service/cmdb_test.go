@@ -68,6 +68,7 @@ func TestCompareCmdbInfo_NoAlerts(t *testing.T) {
 
 	db.EXPECT().GetCurrentHostnames().
 		Return([]string{"pippo", "topolino", "pluto"}, nil)
+	db.EXPECT().ListHostIdentities().Return(nil, nil)
 
 	cmdbInfo := dto.CmdbInfo{
 		Name:      "thisCmdb",
@@ -94,6 +95,7 @@ func TestCompareCmdbInfo_MissingHostInErcole(t *testing.T) {
 
 	db.EXPECT().GetCurrentHostnames().
 		Return([]string{"pippo", "topolino.topolinia.top", "pluto"}, nil)
+	db.EXPECT().ListHostIdentities().Return(nil, nil)
 
 	alert := model.Alert{
 		AlertCategory: model.AlertCategoryEngine,
@@ -131,6 +133,7 @@ func TestCompareCmdbInfo_MissingHostInCmdb(t *testing.T) {
 
 	db.EXPECT().GetCurrentHostnames().
 		Return([]string{"pippo.topolinia.top", "TOPOLINO", "pluto"}, nil)
+	db.EXPECT().ListHostIdentities().Return(nil, nil)
 
 	alert := model.Alert{
 		AlertCategory: model.AlertCategoryEngine,
@@ -149,3 +152,31 @@ func TestCompareCmdbInfo_MissingHostInCmdb(t *testing.T) {
 	actualErr := hds.CompareCmdbInfo(cmdbInfo)
 	assert.Nil(t, actualErr)
 }
+
+func TestCompareCmdbInfo_Aliases(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+
+	hds := HostDataService{
+		Config:         config.Configuration{},
+		ServerVersion:  "1.6.6",
+		Database:       db,
+		AlertSvcClient: asc,
+		TimeNow:        utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:            logger.NewLogger("TEST"),
+	}
+
+	db.EXPECT().GetCurrentHostnames().
+		Return([]string{"pippo", "paperino"}, nil)
+	db.EXPECT().ListHostIdentities().
+		Return([]model.HostIdentity{{Hostname: "paperino", Aliases: []string{"paolino"}}}, nil)
+
+	cmdbInfo := dto.CmdbInfo{
+		Name:      "thisCmdb",
+		Hostnames: []string{"pippo", "paolino.paperopoli.dk"},
+	}
+	actualErr := hds.CompareCmdbInfo(cmdbInfo)
+	assert.Nil(t, actualErr)
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// hostDrift is a change between the previous and the new hostdata of a host
+type hostDrift struct {
+	alertCode   string
+	technology  *string
+	description string
+	otherInfo   map[string]interface{}
+}
+
+type hostDriftDetector func(previous, current *model.HostDataBE) []hostDrift
+
+var hostDriftDetectors = []hostDriftDetector{
+	detectOSDrift,
+	detectKernelDrift,
+	detectMemoryDrift,
+	detectSwapDrift,
+	detectHardwareAbstractionDrift,
+	detectClusterMembershipDrift,
+	detectFilesystemsDrift,
+	detectOracleDatabasesDrift,
+	detectMySQLInstancesDrift,
+	detectSqlServerInstancesDrift,
+}
+
+// hostDriftChecks compare the new hostdata with the previous one and throw an alert
+// for every drift enabled in the configured rules
+func (hds *HostDataService) hostDriftChecks(previousHostdata, hostdata *model.HostDataBE) {
+	conf := hds.Config.DataService.HostDriftDetection
+	if !conf.Enabled || previousHostdata == nil {
+		return
+	}
+
+	for _, detect := range hostDriftDetectors {
+		for _, drift := range detect(previousHostdata, hostdata) {
+			rule, ok := conf.Rules[drift.alertCode]
+			if !ok || !rule.Enabled {
+				continue
+			}
+
+			if err := hds.throwHostDriftAlert(hostdata.Hostname, rule.Severity, drift); err != nil {
+				hds.Log.Error(err)
+			}
+		}
+	}
+}
+
+func detectOSDrift(previous, current *model.HostDataBE) []hostDrift {
+	before := strings.TrimSpace(previous.Info.OS + " " + previous.Info.OSVersion)
+	after := strings.TrimSpace(current.Info.OS + " " + current.Info.OSVersion)
+
+	if before == "" || before == after {
+		return nil
+	}
+
+	return []hostDrift{{
+		alertCode:   model.AlertCodeOSChanged,
+		description: fmt.Sprintf("The operating system of the host %s changed: from %q to %q", current.Hostname, before, after),
+		otherInfo: map[string]interface{}{
+			"previousOS": before,
+			"os":         after,
+		},
+	}}
+}
+
+func detectKernelDrift(previous, current *model.HostDataBE) []hostDrift {
+	before := strings.TrimSpace(previous.Info.Kernel + " " + previous.Info.KernelVersion)
+	after := strings.TrimSpace(current.Info.Kernel + " " + current.Info.KernelVersion)
+
+	if before == "" || before == after {
+		return nil
+	}
+
+	return []hostDrift{{
+		alertCode:   model.AlertCodeKernelChanged,
+		description: fmt.Sprintf("The kernel of the host %s changed: from %q to %q", current.Hostname, before, after),
+		otherInfo: map[string]interface{}{
+			"previousKernel": before,
+			"kernel":         after,
+		},
+	}}
+}
+
+func detectMemoryDrift(previous, current *model.HostDataBE) []hostDrift {
+	if current.Info.MemoryTotal >= previous.Info.MemoryTotal {
+		return nil
+	}
+
+	return []hostDrift{{
+		alertCode: model.AlertCodeDecreasedMemory,
+		description: fmt.Sprintf("The host %s has now less memory: from %.2f GB to %.2f GB",
+			current.Hostname, previous.Info.MemoryTotal, current.Info.MemoryTotal),
+		otherInfo: map[string]interface{}{
+			"previousMemoryTotal": previous.Info.MemoryTotal,
+			"memoryTotal":         current.Info.MemoryTotal,
+		},
+	}}
+}
+
+func detectSwapDrift(previous, current *model.HostDataBE) []hostDrift {
+	if current.Info.SwapTotal >= previous.Info.SwapTotal {
+		return nil
+	}
+
+	return []hostDrift{{
+		alertCode: model.AlertCodeDecreasedSwap,
+		description: fmt.Sprintf("The host %s has now less swap: from %.2f GB to %.2f GB",
+			current.Hostname, previous.Info.SwapTotal, current.Info.SwapTotal),
+		otherInfo: map[string]interface{}{
+			"previousSwapTotal": previous.Info.SwapTotal,
+			"swapTotal":         current.Info.SwapTotal,
+		},
+	}}
+}
+
+func detectHardwareAbstractionDrift(previous, current *model.HostDataBE) []hostDrift {
+	before := previous.Info.HardwareAbstraction + "/" + previous.Info.HardwareAbstractionTechnology
+	after := current.Info.HardwareAbstraction + "/" + current.Info.HardwareAbstractionTechnology
+
+	if previous.Info.HardwareAbstraction == "" || before == after {
+		return nil
+	}
+
+	return []hostDrift{{
+		alertCode:   model.AlertCodeHardwareAbstractionChanged,
+		description: fmt.Sprintf("The hardware abstraction of the host %s changed: from %s to %s", current.Hostname, before, after),
+		otherInfo: map[string]interface{}{
+			"previousHardwareAbstraction":           previous.Info.HardwareAbstraction,
+			"previousHardwareAbstractionTechnology": previous.Info.HardwareAbstractionTechnology,
+			"hardwareAbstraction":                   current.Info.HardwareAbstraction,
+			"hardwareAbstractionTechnology":         current.Info.HardwareAbstractionTechnology,
+		},
+	}}
+}
+
+func detectClusterMembershipDrift(previous, current *model.HostDataBE) []hostDrift {
+	before, after := previous.ClusterMembershipStatus, current.ClusterMembershipStatus
+	changes := make([]string, 0)
+
+	compare := func(name string, before, after bool) {
+		if before != after {
+			changes = append(changes, fmt.Sprintf("%s from %t to %t", name, before, after))
+		}
+	}
+
+	compare("Oracle Clusterware", before.OracleClusterware, after.OracleClusterware)
+	compare("Sun Cluster", before.SunCluster, after.SunCluster)
+	compare("HACMP", before.HACMP, after.HACMP)
+	compare("Veritas Cluster Server", before.VeritasClusterServer, after.VeritasClusterServer)
+
+	beforeHostnames := sortedCopy(before.VeritasClusterHostnames)
+	afterHostnames := sortedCopy(after.VeritasClusterHostnames)
+
+	if strings.Join(beforeHostnames, ",") != strings.Join(afterHostnames, ",") {
+		changes = append(changes, fmt.Sprintf("Veritas cluster hostnames from %q to %q", beforeHostnames, afterHostnames))
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return []hostDrift{{
+		alertCode: model.AlertCodeClusterMembershipChanged,
+		description: fmt.Sprintf("The cluster membership of the host %s changed: %s",
+			current.Hostname, strings.Join(changes, ", ")),
+		otherInfo: map[string]interface{}{
+			"changes": changes,
+		},
+	}}
+}
+
+func detectFilesystemsDrift(previous, current *model.HostDataBE) []hostDrift {
+	mounted := make(map[string]bool, len(current.Filesystems))
+	for _, fs := range current.Filesystems {
+		mounted[fs.MountedOn] = true
+	}
+
+	missing := make([]string, 0)
+
+	for _, fs := range previous.Filesystems {
+		if !mounted[fs.MountedOn] {
+			missing = append(missing, fs.MountedOn)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+
+	return []hostDrift{{
+		alertCode: model.AlertCodeMissingFilesystem,
+		description: fmt.Sprintf("The filesystems mounted on %q on %q are missing compared to the previous hostdata",
+			strings.Join(missing, ", "), current.Hostname),
+		otherInfo: map[string]interface{}{
+			"mountedOn": missing,
+		},
+	}}
+}
+
+func detectOracleDatabasesDrift(previous, current *model.HostDataBE) []hostDrift {
+	if previous.Features.Oracle == nil || previous.Features.Oracle.Database == nil ||
+		current.Features.Oracle == nil || current.Features.Oracle.Database == nil {
+		return nil
+	}
+
+	previousDbs := make(map[string]model.OracleDatabase)
+	for _, db := range previous.Features.Oracle.Database.Databases {
+		previousDbs[db.Name] = db
+	}
+
+	drifts := make([]hostDrift, 0)
+
+	for _, db := range current.Features.Oracle.Database.Databases {
+		previousDb, ok := previousDbs[db.Name]
+		if !ok {
+			continue
+		}
+
+		if previousDb.Version != db.Version {
+			drifts = append(drifts, newDatabaseVersionDrift(model.TechnologyOracleDatabasePtr,
+				current.Hostname, db.Name, previousDb.Version, db.Version))
+		}
+
+		if previousDb.Archivelog && !db.Archivelog {
+			drifts = append(drifts, hostDrift{
+				alertCode:   model.AlertCodeArchivelogDisabled,
+				technology:  model.TechnologyOracleDatabasePtr,
+				description: fmt.Sprintf("The archivelog of the database %s on %s was disabled", db.Name, current.Hostname),
+				otherInfo: map[string]interface{}{
+					"dbname": db.Name,
+				},
+			})
+		}
+
+		if previousDb.Dataguard && !db.Dataguard {
+			drifts = append(drifts, hostDrift{
+				alertCode:   model.AlertCodeDataguardDisabled,
+				technology:  model.TechnologyOracleDatabasePtr,
+				description: fmt.Sprintf("The Dataguard of the database %s on %s was disabled", db.Name, current.Hostname),
+				otherInfo: map[string]interface{}{
+					"dbname": db.Name,
+				},
+			})
+		}
+	}
+
+	return drifts
+}
+
+func detectMySQLInstancesDrift(previous, current *model.HostDataBE) []hostDrift {
+	if previous.Features.MySQL == nil || current.Features.MySQL == nil {
+		return nil
+	}
+
+	previousVersions := make(map[string]string)
+	for _, instance := range previous.Features.MySQL.Instances {
+		previousVersions[instance.Name] = instance.Version
+	}
+
+	drifts := make([]hostDrift, 0)
+
+	for _, instance := range current.Features.MySQL.Instances {
+		if previousVersion, ok := previousVersions[instance.Name]; ok && previousVersion != instance.Version {
+			drifts = append(drifts, newDatabaseVersionDrift(model.TechnologyOracleMySQLPrt,
+				current.Hostname, instance.Name, previousVersion, instance.Version))
+		}
+	}
+
+	return drifts
+}
+
+func detectSqlServerInstancesDrift(previous, current *model.HostDataBE) []hostDrift {
+	if previous.Features.Microsoft == nil || previous.Features.Microsoft.SQLServer == nil ||
+		current.Features.Microsoft == nil || current.Features.Microsoft.SQLServer == nil {
+		return nil
+	}
+
+	previousVersions := make(map[string]string)
+	for _, instance := range previous.Features.Microsoft.SQLServer.Instances {
+		previousVersions[instance.Name] = instance.Version
+	}
+
+	drifts := make([]hostDrift, 0)
+
+	for _, instance := range current.Features.Microsoft.SQLServer.Instances {
+		if previousVersion, ok := previousVersions[instance.Name]; ok && previousVersion != instance.Version {
+			drifts = append(drifts, newDatabaseVersionDrift(model.TechnologyMicrosoftSQLServerPrt,
+				current.Hostname, instance.Name, previousVersion, instance.Version))
+		}
+	}
+
+	return drifts
+}
+
+func newDatabaseVersionDrift(technology *string, hostname, dbname, previousVersion, version string) hostDrift {
+	return hostDrift{
+		alertCode:  model.AlertCodeDatabaseVersionChanged,
+		technology: technology,
+		description: fmt.Sprintf("The version of the database %s on %s changed: from %q to %q",
+			dbname, hostname, previousVersion, version),
+		otherInfo: map[string]interface{}{
+			"dbname":          dbname,
+			"previousVersion": previousVersion,
+			"version":         version,
+		},
+	}
+}
+
+func sortedCopy(values []string) []string {
+	res := make([]string, len(values))
+	copy(res, values)
+	sort.Strings(res)
+
+	return res
+}
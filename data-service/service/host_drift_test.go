@@ -0,0 +1,224 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestHostDriftChecks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				HostDriftDetection: config.HostDriftDetection{
+					Enabled: true,
+					Rules: map[string]config.HostDriftRule{
+						model.AlertCodeKernelChanged:      {Enabled: true, Severity: model.AlertSeverityInfo},
+						model.AlertCodeDecreasedMemory:    {Enabled: true, Severity: model.AlertSeverityWarning},
+						model.AlertCodeArchivelogDisabled: {Enabled: true, Severity: model.AlertSeverityCritical},
+						model.AlertCodeDecreasedSwap:      {Enabled: false, Severity: model.AlertSeverityWarning},
+					},
+				},
+			},
+		},
+		AlertSvcClient: asc,
+		TimeNow:        utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:            logger.NewLogger("TEST"),
+	}
+
+	previous := model.HostDataBE{
+		Hostname: "foobar",
+		Info: model.Host{
+			OS:            "Red Hat Enterprise Linux",
+			OSVersion:     "7.6",
+			Kernel:        "Linux",
+			KernelVersion: "3.10.0-957",
+			MemoryTotal:   32,
+			SwapTotal:     8,
+		},
+		Features: model.Features{
+			Oracle: &model.OracleFeature{
+				Database: &model.OracleDatabaseFeature{
+					Databases: []model.OracleDatabase{
+						{Name: "ERCOLE", Version: "19.0.0.0.0", Archivelog: true},
+					},
+				},
+			},
+		},
+	}
+
+	current := previous
+	current.Info.KernelVersion = "3.10.0-1160"
+	current.Info.MemoryTotal = 16
+	current.Info.SwapTotal = 4
+	current.Features = model.Features{
+		Oracle: &model.OracleFeature{
+			Database: &model.OracleDatabaseFeature{
+				Databases: []model.OracleDatabase{
+					{Name: "ERCOLE", Version: "19.0.0.0.0", Archivelog: false},
+				},
+			},
+		},
+	}
+
+	gomock.InOrder(
+		asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
+			assert.Equal(t, model.AlertCategoryEngine, a.AlertCategory)
+			assert.Equal(t, model.AlertCodeKernelChanged, a.AlertCode)
+			assert.Equal(t, model.AlertSeverityInfo, a.AlertSeverity)
+			assert.Equal(t, `The kernel of the host foobar changed: from "Linux 3.10.0-957" to "Linux 3.10.0-1160"`, a.Description)
+			assert.Equal(t, "foobar", a.OtherInfo["hostname"])
+		}).Return(nil),
+		asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
+			assert.Equal(t, model.AlertCodeDecreasedMemory, a.AlertCode)
+			assert.Equal(t, model.AlertSeverityWarning, a.AlertSeverity)
+			assert.Equal(t, "The host foobar has now less memory: from 32.00 GB to 16.00 GB", a.Description)
+		}).Return(nil),
+		asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
+			assert.Equal(t, model.AlertCodeArchivelogDisabled, a.AlertCode)
+			assert.Equal(t, model.AlertSeverityCritical, a.AlertSeverity)
+			assert.Equal(t, model.TechnologyOracleDatabasePtr, a.AlertAffectedTechnology)
+			assert.Equal(t, "ERCOLE", a.OtherInfo["dbname"])
+		}).Return(errMock),
+	)
+
+	hds.hostDriftChecks(&previous, &current)
+}
+
+func TestHostDriftChecks_Disabled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	asc := NewMockAlertSvcClientInterface(mockCtrl)
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				HostDriftDetection: config.HostDriftDetection{
+					Enabled: false,
+					Rules: map[string]config.HostDriftRule{
+						model.AlertCodeDecreasedMemory: {Enabled: true, Severity: model.AlertSeverityWarning},
+					},
+				},
+			},
+		},
+		AlertSvcClient: asc,
+		TimeNow:        utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:            logger.NewLogger("TEST"),
+	}
+
+	previous := model.HostDataBE{Hostname: "foobar", Info: model.Host{MemoryTotal: 32}}
+	current := model.HostDataBE{Hostname: "foobar", Info: model.Host{MemoryTotal: 16}}
+
+	hds.hostDriftChecks(&previous, &current)
+	hds.hostDriftChecks(nil, &current)
+}
+
+func TestDetectClusterMembershipDrift(t *testing.T) {
+	previous := model.HostDataBE{
+		Hostname: "foobar",
+		ClusterMembershipStatus: model.ClusterMembershipStatus{
+			VeritasClusterServer:    true,
+			VeritasClusterHostnames: []string{"foo", "bar"},
+		},
+	}
+	current := model.HostDataBE{
+		Hostname: "foobar",
+		ClusterMembershipStatus: model.ClusterMembershipStatus{
+			OracleClusterware:       true,
+			VeritasClusterServer:    true,
+			VeritasClusterHostnames: []string{"bar", "foo"},
+		},
+	}
+
+	actual := detectClusterMembershipDrift(&previous, &current)
+
+	assert.Len(t, actual, 1)
+	assert.Equal(t, model.AlertCodeClusterMembershipChanged, actual[0].alertCode)
+	assert.Equal(t, "The cluster membership of the host foobar changed: Oracle Clusterware from false to true", actual[0].description)
+
+	assert.Empty(t, detectClusterMembershipDrift(&current, &current))
+}
+
+func TestDetectFilesystemsDrift(t *testing.T) {
+	previous := model.HostDataBE{
+		Hostname: "foobar",
+		Filesystems: []model.Filesystem{
+			{MountedOn: "/"},
+			{MountedOn: "/u01"},
+			{MountedOn: "/backup"},
+		},
+	}
+	current := model.HostDataBE{
+		Hostname: "foobar",
+		Filesystems: []model.Filesystem{
+			{MountedOn: "/"},
+		},
+	}
+
+	actual := detectFilesystemsDrift(&previous, &current)
+
+	assert.Len(t, actual, 1)
+	assert.Equal(t, model.AlertCodeMissingFilesystem, actual[0].alertCode)
+	assert.Equal(t, []string{"/backup", "/u01"}, actual[0].otherInfo["mountedOn"])
+
+	assert.Empty(t, detectFilesystemsDrift(&current, &previous))
+}
+
+func TestDetectDatabasesVersionDrift(t *testing.T) {
+	previous := model.HostDataBE{
+		Hostname: "foobar",
+		Features: model.Features{
+			MySQL: &model.MySQLFeature{
+				Instances: []model.MySQLInstance{{Name: "mysql", Version: "8.0.23"}},
+			},
+			Microsoft: &model.MicrosoftFeature{
+				SQLServer: &model.MicrosoftSQLServerFeature{
+					Instances: []model.MicrosoftSQLServerInstance{{Name: "MSSQLSERVER", Version: "15.0.2000.5"}},
+				},
+			},
+		},
+	}
+	current := model.HostDataBE{
+		Hostname: "foobar",
+		Features: model.Features{
+			MySQL: &model.MySQLFeature{
+				Instances: []model.MySQLInstance{{Name: "mysql", Version: "8.0.26"}},
+			},
+			Microsoft: &model.MicrosoftFeature{
+				SQLServer: &model.MicrosoftSQLServerFeature{
+					Instances: []model.MicrosoftSQLServerInstance{{Name: "MSSQLSERVER", Version: "15.0.2000.5"}},
+				},
+			},
+		},
+	}
+
+	actual := detectMySQLInstancesDrift(&previous, &current)
+	assert.Len(t, actual, 1)
+	assert.Equal(t, model.AlertCodeDatabaseVersionChanged, actual[0].alertCode)
+	assert.Equal(t, model.TechnologyOracleMySQLPrt, actual[0].technology)
+	assert.Equal(t, `The version of the database mysql on foobar changed: from "8.0.23" to "8.0.26"`, actual[0].description)
+
+	assert.Empty(t, detectSqlServerInstancesDrift(&previous, &current))
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+// canonicalHostname return the canonical hostname of the host that has hostname as alias,
+// so the hostdata sent with an old name or with the FQDN are stored with the canonical one
+func (hds *HostDataService) canonicalHostname(hostname string) (string, error) {
+	identity, err := hds.Database.FindHostIdentityByAlias(hostname)
+	if err != nil {
+		return "", err
+	}
+
+	if identity == nil {
+		return hostname, nil
+	}
+
+	hds.Log.Debugf("Received hostdata of %s, alias of %s", hostname, identity.Hostname)
+
+	return identity.Hostname, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+// compactArchivedHostData store the hostdata just archived as delta from the previous one of the same host,
+// unless a new full snapshot is due
+func (hds *HostDataService) compactArchivedHostData(id primitive.ObjectID) error {
+	conf := hds.Config.DataService.HostDataHistory
+	if conf.Mode != model.HostDataHistoryModeDelta {
+		return nil
+	}
+
+	archived, err := hds.Database.FindFullHostData(id)
+	if err != nil {
+		return err
+	}
+
+	previous, err := hds.Database.FindMostRecentHostDataOlderThan(archived.Hostname, archived.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if previous == nil {
+		return nil
+	}
+
+	snapshotCreatedAt := previous.CreatedAt
+	if previous.HistoryDelta != nil {
+		snapshotCreatedAt = previous.HistoryDelta.SnapshotCreatedAt
+	}
+
+	if archived.CreatedAt.Sub(snapshotCreatedAt) >= time.Duration(conf.SnapshotIntervalHours)*time.Hour {
+		return nil
+	}
+
+	base := previous
+	if previous.HistoryDelta != nil {
+		if base, err = hds.Database.FindFullHostData(previous.ID); err != nil {
+			return err
+		}
+	}
+
+	delta, err := model.NewHostDataHistoryDelta(*base, *archived, snapshotCreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return hds.Database.CompactHostData(*archived, *delta)
+}
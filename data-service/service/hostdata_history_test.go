@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestCompactArchivedHostData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	hds := HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				HostDataHistory: config.HostDataHistory{
+					Mode:                  model.HostDataHistoryModeDelta,
+					SnapshotIntervalHours: 72,
+				},
+			},
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	archived := model.HostDataBE{
+		ID:        utils.Str2oid("5ef9d239a1d25d1e8703c4d4"),
+		Hostname:  "foobar",
+		Archived:  true,
+		CreatedAt: utils.P("2019-11-05T14:02:03Z"),
+		Info:      model.Host{MemoryTotal: 16},
+	}
+	snapshot := model.HostDataBE{
+		ID:        utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		Hostname:  "foobar",
+		Archived:  true,
+		CreatedAt: utils.P("2019-11-04T14:02:03Z"),
+		Info:      model.Host{MemoryTotal: 32},
+	}
+
+	t.Run("First hostdata", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().FindFullHostData(archived.ID).Return(&archived, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", archived.CreatedAt).Return(nil, nil),
+		)
+
+		require.NoError(t, hds.compactArchivedHostData(archived.ID))
+	})
+
+	t.Run("Delta from snapshot", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().FindFullHostData(archived.ID).Return(&archived, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", archived.CreatedAt).Return(&snapshot, nil),
+			db.EXPECT().CompactHostData(archived, gomock.Any()).
+				Do(func(_ model.HostDataBE, delta model.HostDataHistoryDelta) {
+					assert.Equal(t, snapshot.ID, delta.BaseID)
+					assert.Equal(t, snapshot.CreatedAt, delta.SnapshotCreatedAt)
+
+					actual, err := delta.Apply(snapshot)
+					require.NoError(t, err)
+					assert.Equal(t, archived, *actual)
+				}).Return(nil),
+		)
+
+		require.NoError(t, hds.compactArchivedHostData(archived.ID))
+	})
+
+	t.Run("Delta from delta", func(t *testing.T) {
+		previous := snapshot
+		previous.HistoryDelta = &model.HostDataHistoryDelta{
+			BaseID:            utils.Str2oid("5ef9d239a1d25d1e8703c4d2"),
+			SnapshotCreatedAt: utils.P("2019-11-03T14:02:03Z"),
+		}
+
+		gomock.InOrder(
+			db.EXPECT().FindFullHostData(archived.ID).Return(&archived, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", archived.CreatedAt).Return(&previous, nil),
+			db.EXPECT().FindFullHostData(previous.ID).Return(&snapshot, nil),
+			db.EXPECT().CompactHostData(archived, gomock.Any()).
+				Do(func(_ model.HostDataBE, delta model.HostDataHistoryDelta) {
+					assert.Equal(t, snapshot.ID, delta.BaseID)
+					assert.Equal(t, utils.P("2019-11-03T14:02:03Z"), delta.SnapshotCreatedAt)
+				}).Return(nil),
+		)
+
+		require.NoError(t, hds.compactArchivedHostData(archived.ID))
+	})
+
+	t.Run("Snapshot due", func(t *testing.T) {
+		previous := snapshot
+		previous.HistoryDelta = &model.HostDataHistoryDelta{
+			BaseID:            utils.Str2oid("5ef9d239a1d25d1e8703c4d2"),
+			SnapshotCreatedAt: utils.P("2019-11-02T14:02:03Z"),
+		}
+
+		gomock.InOrder(
+			db.EXPECT().FindFullHostData(archived.ID).Return(&archived, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", archived.CreatedAt).Return(&previous, nil),
+		)
+
+		require.NoError(t, hds.compactArchivedHostData(archived.ID))
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().FindFullHostData(archived.ID).Return(nil, aerrMock)
+
+		require.Equal(t, aerrMock, hds.compactArchivedHostData(archived.ID))
+	})
+
+	t.Run("Full mode", func(t *testing.T) {
+		fullHds := hds
+		fullHds.Config.DataService.HostDataHistory.Mode = model.HostDataHistoryModeFull
+
+		require.NoError(t, fullHds.compactArchivedHostData(archived.ID))
+	})
+}
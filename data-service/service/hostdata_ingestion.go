@@ -0,0 +1,236 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	alertservice_client "github.com/ercole-io/ercole/v2/alert-service/client"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+const ingestionCleaningInterval = time.Minute
+
+func (hds *HostDataService) EnqueueHostData(hostdata model.HostDataBE) (*model.HostDataIngestion, error) {
+	raw, err := json.Marshal(hostdata)
+	if err != nil {
+		return nil, utils.NewError(err, "Can't marshal hostdata")
+	}
+
+	hash := sha256.Sum256(raw)
+	payloadHash := hex.EncodeToString(hash[:])
+	now := hds.TimeNow()
+
+	ingestion := model.HostDataIngestion{
+		ID:             primitive.NewObjectID(),
+		IdempotencyKey: hostdata.Hostname + ":" + payloadHash,
+		Hostname:       hostdata.Hostname,
+		PayloadHash:    payloadHash,
+		Status:         model.HostDataIngestionStatusQueued,
+		Attempts:       0,
+		CreatedAt:      now,
+		NextAttemptAt:  now,
+		HostData:       hostdata,
+	}
+
+	queued, created, err := hds.Database.EnqueueHostDataIngestion(ingestion)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		hds.Log.Infof("Hostdata of %s already received, duplicate discarded", hostdata.Hostname)
+		return queued, nil
+	}
+
+	select {
+	case hds.ingestionSignal <- struct{}{}:
+	default:
+	}
+
+	return queued, nil
+}
+
+func (hds *HostDataService) GetHostDataIngestion(id primitive.ObjectID) (*model.HostDataIngestion, error) {
+	return hds.Database.FindHostDataIngestion(id)
+}
+
+func (hds *HostDataService) StartIngestionWorkers() {
+	conf := hds.Config.DataService.IngestionQueue
+	pollInterval := time.Duration(conf.PollIntervalSeconds) * time.Second
+
+	hds.ingestionSignal = make(chan struct{}, 1)
+
+	for i := 0; i < conf.Workers; i++ {
+		go hds.runIngestionWorker(pollInterval)
+	}
+
+	go hds.runIngestionCleaner()
+
+	hds.Log.Infof("Started %d hostdata ingestion workers", conf.Workers)
+}
+
+func (hds *HostDataService) runIngestionWorker(pollInterval time.Duration) {
+	for {
+		if hds.processNextIngestion() {
+			continue
+		}
+
+		select {
+		case <-hds.ingestionSignal:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// processNextIngestion process the next hostdata in the queue.
+// It returns false if there wasn't any hostdata ready to be processed
+func (hds *HostDataService) processNextIngestion() bool {
+	conf := hds.Config.DataService.IngestionQueue
+	now := hds.TimeNow()
+
+	ingestion, err := hds.Database.ClaimHostDataIngestion(now, now.Add(time.Duration(conf.LeaseSeconds)*time.Second))
+	if err != nil {
+		hds.Log.Error(err)
+		return false
+	}
+
+	if ingestion == nil {
+		return false
+	}
+
+	if ingestion.StoredAt != nil {
+		hds.Log.Warnf("Hostdata of %s already stored by a previous attempt, ingestion completed", ingestion.Hostname)
+
+		if err := hds.Database.CompleteHostDataIngestion(ingestion.ID, hds.TimeNow()); err != nil {
+			hds.Log.Error(err)
+		}
+
+		return true
+	}
+
+	insertErr := hds.insertQueuedHostData(ingestion)
+	if insertErr == nil {
+		if err := hds.Database.CompleteHostDataIngestion(ingestion.ID, hds.TimeNow()); err != nil {
+			hds.Log.Error(err)
+		}
+
+		return true
+	}
+
+	hds.Log.Errorf("Can't insert hostdata of %s, attempt %d of %d: %s",
+		ingestion.Hostname, ingestion.Attempts, conf.MaxAttempts, insertErr)
+
+	if ingestion.Attempts >= conf.MaxAttempts {
+		err = hds.Database.FailHostDataIngestion(ingestion.ID, hds.TimeNow(), insertErr.Error())
+	} else {
+		delay := time.Duration(conf.RetryDelaySeconds) * time.Second << (ingestion.Attempts - 1)
+		err = hds.Database.RetryHostDataIngestion(ingestion.ID, hds.TimeNow().Add(delay), insertErr.Error())
+	}
+
+	if err != nil {
+		hds.Log.Error(err)
+	}
+
+	return true
+}
+
+// insertQueuedHostData insert the hostdata of the ingestion, turning a panic into an error so it doesn't stop the worker.
+// The alerts thrown and the storing of the hostdata are recorded in the ingestion, so a retry doesn't repeat them
+func (hds *HostDataService) insertQueuedHostData(ingestion *model.HostDataIngestion) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while inserting hostdata: %v", r)
+		}
+	}()
+
+	attempt := *hds
+	attempt.AlertSvcClient = &ingestionAlertClient{
+		AlertSvcClientInterface: hds.AlertSvcClient,
+		hds:                     hds,
+		ingestion:               ingestion,
+	}
+
+	return attempt.insertHostData(ingestion.HostData, func() {
+		if err := hds.Database.SetHostDataIngestionStored(ingestion.ID, hds.TimeNow()); err != nil {
+			hds.Log.Error(err)
+		}
+	})
+}
+
+// ingestionAlertClient throw the alerts of an ingestion, skipping the ones already thrown by its previous attempts
+type ingestionAlertClient struct {
+	alertservice_client.AlertSvcClientInterface
+	hds       *HostDataService
+	ingestion *model.HostDataIngestion
+}
+
+func (c *ingestionAlertClient) ThrowNewAlert(alert model.Alert) error {
+	key, err := ingestionAlertKey(alert)
+	if err != nil {
+		return err
+	}
+
+	if utils.Contains(c.ingestion.ThrownAlerts, key) {
+		return nil
+	}
+
+	if err := c.AlertSvcClientInterface.ThrowNewAlert(alert); err != nil {
+		return err
+	}
+
+	c.ingestion.ThrownAlerts = append(c.ingestion.ThrownAlerts, key)
+
+	if err := c.hds.Database.AddHostDataIngestionThrownAlert(c.ingestion.ID, key); err != nil {
+		c.hds.Log.Error(err)
+	}
+
+	return nil
+}
+
+// ingestionAlertKey return the key that identifies the alert among the ones of an ingestion, regardless of when it's thrown
+func ingestionAlertKey(alert model.Alert) (string, error) {
+	raw, err := json.Marshal(struct {
+		Code        string
+		Description string
+		OtherInfo   map[string]interface{}
+	}{alert.AlertCode, alert.Description, alert.OtherInfo})
+	if err != nil {
+		return "", utils.NewError(err, "Can't marshal alert")
+	}
+
+	hash := sha256.Sum256(raw)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// runIngestionCleaner periodically delete the processed hostdata older than the idempotency window,
+// so the same hostdata can be accepted again
+func (hds *HostDataService) runIngestionCleaner() {
+	window := time.Duration(hds.Config.DataService.IngestionQueue.IdempotencyWindowMinutes) * time.Minute
+
+	for range time.Tick(ingestionCleaningInterval) {
+		if err := hds.Database.DeleteHostDataIngestionsDoneBefore(hds.TimeNow().Add(-window)); err != nil {
+			hds.Log.Error(err)
+		}
+	}
+}
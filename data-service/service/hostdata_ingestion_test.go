@@ -0,0 +1,233 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package service
+
+import (
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func newIngestionQueueTestService(db *MockMongoDatabaseInterface) HostDataService {
+	return HostDataService{
+		Config: config.Configuration{
+			DataService: config.DataService{
+				IngestionQueue: config.IngestionQueue{
+					Enabled:           true,
+					Workers:           1,
+					MaxAttempts:       3,
+					RetryDelaySeconds: 30,
+					LeaseSeconds:      600,
+				},
+			},
+		},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+	}
+}
+
+func TestEnqueueHostData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	hds := newIngestionQueueTestService(db)
+
+	hostdata := model.HostDataBE{Hostname: "foobar"}
+
+	t.Run("New hostdata", func(t *testing.T) {
+		var queued model.HostDataIngestion
+
+		db.EXPECT().EnqueueHostDataIngestion(gomock.Any()).
+			DoAndReturn(func(ingestion model.HostDataIngestion) (*model.HostDataIngestion, bool, error) {
+				queued = ingestion
+				return &ingestion, true, nil
+			})
+
+		actual, err := hds.EnqueueHostData(hostdata)
+		require.NoError(t, err)
+
+		assert.Equal(t, queued, *actual)
+		assert.Equal(t, "foobar", actual.Hostname)
+		assert.Equal(t, model.HostDataIngestionStatusQueued, actual.Status)
+		assert.Equal(t, "foobar:"+actual.PayloadHash, actual.IdempotencyKey)
+		assert.Len(t, actual.PayloadHash, 64)
+		assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), actual.NextAttemptAt)
+		assert.Equal(t, hostdata, actual.HostData)
+	})
+
+	t.Run("Duplicated hostdata", func(t *testing.T) {
+		previous := model.HostDataIngestion{
+			ID:     utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+			Status: model.HostDataIngestionStatusDone,
+		}
+		db.EXPECT().EnqueueHostDataIngestion(gomock.Any()).Return(&previous, false, nil)
+
+		actual, err := hds.EnqueueHostData(hostdata)
+		require.NoError(t, err)
+		assert.Equal(t, &previous, actual)
+	})
+
+	t.Run("Same payload, same key", func(t *testing.T) {
+		keys := make([]string, 0)
+
+		db.EXPECT().EnqueueHostDataIngestion(gomock.Any()).
+			DoAndReturn(func(ingestion model.HostDataIngestion) (*model.HostDataIngestion, bool, error) {
+				keys = append(keys, ingestion.IdempotencyKey)
+				return &ingestion, true, nil
+			}).Times(2)
+
+		_, err := hds.EnqueueHostData(hostdata)
+		require.NoError(t, err)
+		_, err = hds.EnqueueHostData(hostdata)
+		require.NoError(t, err)
+
+		assert.Equal(t, keys[0], keys[1])
+	})
+
+	t.Run("Database error", func(t *testing.T) {
+		db.EXPECT().EnqueueHostDataIngestion(gomock.Any()).Return(nil, false, aerrMock)
+
+		_, err := hds.EnqueueHostData(hostdata)
+		require.Equal(t, aerrMock, err)
+	})
+}
+
+func TestProcessNextIngestion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	hds := newIngestionQueueTestService(db)
+
+	now := utils.P("2019-11-05T14:02:03Z")
+	leaseExpiresAt := utils.P("2019-11-05T14:12:03Z")
+
+	t.Run("Empty queue", func(t *testing.T) {
+		db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(nil, nil)
+
+		assert.False(t, hds.processNextIngestion())
+	})
+
+	t.Run("Claim error", func(t *testing.T) {
+		db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(nil, aerrMock)
+
+		assert.False(t, hds.processNextIngestion())
+	})
+
+	ingestion := model.HostDataIngestion{
+		ID:       utils.Str2oid("5ef9d239a1d25d1e8703c4d3"),
+		Hostname: "foobar",
+		Status:   model.HostDataIngestionStatusProcessing,
+		Attempts: 2,
+		HostData: model.HostDataBE{Hostname: "foobar"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(&ingestion, nil),
+			db.EXPECT().FindHostIdentityByAlias("foobar").Return(nil, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", now).Return(&model.HostDataBE{}, nil),
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(nil),
+			db.EXPECT().SetHostDataIngestionStored(ingestion.ID, now).Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost("foobar", now).Return(nil),
+			db.EXPECT().CompleteHostDataIngestion(ingestion.ID, now).Return(nil),
+		)
+
+		assert.True(t, hds.processNextIngestion())
+	})
+
+	t.Run("Retry doesn't throw the alerts again", func(t *testing.T) {
+		asc := NewMockAlertSvcClientInterface(mockCtrl)
+		hds.AlertSvcClient = asc
+
+		var thrownAlert string
+
+		gomock.InOrder(
+			db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(&ingestion, nil),
+			db.EXPECT().FindHostIdentityByAlias("foobar").Return(nil, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", now).Return(nil, nil),
+			asc.EXPECT().ThrowNewAlert(gomock.Any()).Return(nil),
+			db.EXPECT().AddHostDataIngestionThrownAlert(ingestion.ID, gomock.Any()).
+				DoAndReturn(func(id primitive.ObjectID, key string) error {
+					thrownAlert = key
+					return nil
+				}),
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(aerrMock),
+			db.EXPECT().RetryHostDataIngestion(ingestion.ID, utils.P("2019-11-05T14:03:03Z"), aerrMock.Error()).Return(nil),
+		)
+
+		assert.True(t, hds.processNextIngestion())
+
+		retry := ingestion
+		retry.ThrownAlerts = []string{thrownAlert}
+
+		gomock.InOrder(
+			db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(&retry, nil),
+			db.EXPECT().FindHostIdentityByAlias("foobar").Return(nil, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", now).Return(nil, nil),
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(nil),
+			db.EXPECT().SetHostDataIngestionStored(ingestion.ID, now).Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost("foobar", now).Return(nil),
+			db.EXPECT().CompleteHostDataIngestion(ingestion.ID, now).Return(nil),
+		)
+
+		assert.True(t, hds.processNextIngestion())
+	})
+
+	t.Run("Hostdata already stored", func(t *testing.T) {
+		stored := ingestion
+		stored.StoredAt = &now
+
+		gomock.InOrder(
+			db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(&stored, nil),
+			db.EXPECT().CompleteHostDataIngestion(ingestion.ID, now).Return(nil),
+		)
+
+		assert.True(t, hds.processNextIngestion())
+	})
+
+	t.Run("Retry with backoff", func(t *testing.T) {
+		gomock.InOrder(
+			db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(&ingestion, nil),
+			db.EXPECT().FindHostIdentityByAlias("foobar").Return(nil, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", now).Return(nil, aerrMock),
+			db.EXPECT().RetryHostDataIngestion(ingestion.ID, utils.P("2019-11-05T14:03:03Z"), aerrMock.Error()).Return(nil),
+		)
+
+		assert.True(t, hds.processNextIngestion())
+	})
+
+	t.Run("Dead letter", func(t *testing.T) {
+		lastAttempt := ingestion
+		lastAttempt.Attempts = 3
+
+		gomock.InOrder(
+			db.EXPECT().ClaimHostDataIngestion(now, leaseExpiresAt).Return(&lastAttempt, nil),
+			db.EXPECT().FindHostIdentityByAlias("foobar").Return(nil, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("foobar", now).Return(nil, aerrMock),
+			db.EXPECT().FailHostDataIngestion(ingestion.ID, now, aerrMock.Error()).Return(nil),
+		)
+
+		assert.True(t, hds.processNextIngestion())
+	})
+}
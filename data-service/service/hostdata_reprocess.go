@@ -0,0 +1,216 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/jsonpatch"
+)
+
+// hostDataReprocessor reruns the computations of the ingestion on the stored hostdata,
+// retrieving the license types and the primary databases once per reprocess
+type hostDataReprocessor struct {
+	hds *HostDataService
+
+	oracleLicenseTypes    map[string][]model.OracleDatabaseLicenseType
+	sqlServerLicenseTypes []model.SqlServerDatabaseLicenseType
+	mySqlLicenseTypes     []model.MySqlLicenseType
+	primaryDbs            []model.OracleDatabase
+}
+
+// ReprocessHostData recompute licenses, secondary databases and clusters of the selected current hostdata,
+// saving them unless it's a dry run. The run is aborted if the license types can't be retrieved
+func (hds *HostDataService) ReprocessHostData(req dto.HostDataReprocessRequest) (*dto.HostDataReprocessReport, error) {
+	hosts, err := hds.Database.FindCurrentHostData(req.Hostnames, req.Location, req.Environment, req.Technology)
+	if err != nil {
+		return nil, err
+	}
+
+	report := dto.HostDataReprocessReport{
+		DryRun: req.DryRun,
+		Hosts:  make([]dto.HostDataReprocessResult, 0, len(hosts)),
+	}
+
+	reprocessor := hostDataReprocessor{
+		hds:                hds,
+		oracleLicenseTypes: make(map[string][]model.OracleDatabaseLicenseType),
+	}
+
+	for i := range hosts {
+		hostdata := &hosts[i]
+		result := dto.HostDataReprocessResult{
+			Hostname: hostdata.Hostname,
+			Diff:     make([]jsonpatch.Operation, 0),
+		}
+
+		if err := reprocessor.loadLicenseTypes(hostdata); err != nil {
+			return nil, err
+		}
+
+		diff, err := reprocessor.reprocess(hostdata)
+		if err != nil {
+			hds.Log.Error(err)
+			result.Error = err.Error()
+			report.Hosts = append(report.Hosts, result)
+
+			continue
+		}
+
+		report.Processed++
+
+		if len(diff) > 0 {
+			result.Changed = true
+			result.Diff = diff
+			report.Changed++
+
+			if !req.DryRun {
+				if err := hds.Database.ReplaceHostData(*hostdata); err != nil {
+					hds.Log.Error(err)
+					result.Error = err.Error()
+				}
+			}
+		}
+
+		report.Hosts = append(report.Hosts, result)
+	}
+
+	return &report, nil
+}
+
+// reprocess update the hostdata and return its differences from the stored one
+func (r *hostDataReprocessor) reprocess(hostdata *model.HostDataBE) ([]jsonpatch.Operation, error) {
+	before, err := json.Marshal(hostdata)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't marshal hostdata %s: %w", hostdata.Hostname, err)
+	}
+
+	if hasOracleDatabases(hostdata) {
+		r.reprocessSecondaryDbs(hostdata)
+		resetLicenseTypeIDs(hostdata)
+		r.hds.setLicenseTypes(hostdata, r.oracleLicenseTypes[hostdata.Environment])
+	}
+
+	if hasSqlServerInstances(hostdata) {
+		r.hds.setSqlServerLicenseTypes(hostdata, r.sqlServerLicenseTypes)
+	}
+
+	if hasMySqlInstances(hostdata) {
+		r.hds.setMySqlLicenseTypes(hostdata, r.mySqlLicenseTypes)
+	}
+
+	if hostdata.Clusters != nil {
+		r.hds.clusterInfoChecks(hostdata.Clusters)
+	}
+
+	after, err := json.Marshal(hostdata)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't marshal hostdata %s: %w", hostdata.Hostname, err)
+	}
+
+	diff, err := jsonpatch.Diff(before, after)
+	if err != nil {
+		return nil, utils.NewErrorf("Can't compare hostdata %s: %w", hostdata.Hostname, err)
+	}
+
+	return diff, nil
+}
+
+// reprocessSecondaryDbs copy the licenses of the primary databases to the secondary ones,
+// without throwing the alerts of the missing primary databases already thrown by the ingestion
+func (r *hostDataReprocessor) reprocessSecondaryDbs(hostdata *model.HostDataBE) {
+	for i := range hostdata.Features.Oracle.Database.Databases {
+		db := &hostdata.Features.Oracle.Database.Databases[i]
+
+		if !isLicensableSecondaryDb(db) {
+			continue
+		}
+
+		if r.primaryDbs == nil {
+			dbs, err := r.hds.getPrimaryOpenOracleDatabases()
+			if err != nil {
+				r.hds.Log.Errorf("Can't get primary open oracle databases: %s", err)
+				return
+			}
+
+			r.primaryDbs = dbs
+		}
+
+		if primaryDb := findPrimaryDb(r.primaryDbs, db); primaryDb != nil {
+			r.hds.copyPrimaryDbLicenses(hostdata.Info, hostdata.CoreFactor(), primaryDb, db)
+		}
+	}
+}
+
+// resetLicenseTypeIDs clear the license types of the Oracle licenses, so that the ones whose alias
+// has been moved or removed are mapped again
+func resetLicenseTypeIDs(hostdata *model.HostDataBE) {
+	for i := range hostdata.Features.Oracle.Database.Databases {
+		licenses := hostdata.Features.Oracle.Database.Databases[i].Licenses
+		for j := range licenses {
+			licenses[j].LicenseTypeID = ""
+		}
+	}
+}
+
+// loadLicenseTypes retrieve the license types needed by the hostdata that haven't been retrieved yet
+func (r *hostDataReprocessor) loadLicenseTypes(hostdata *model.HostDataBE) error {
+	if _, ok := r.oracleLicenseTypes[hostdata.Environment]; !ok && hasOracleDatabases(hostdata) {
+		licenseTypes, err := r.hds.getOracleDatabaseLicenseTypes(hostdata.Environment)
+		if err != nil {
+			return err
+		}
+
+		r.oracleLicenseTypes[hostdata.Environment] = licenseTypes
+	}
+
+	if r.sqlServerLicenseTypes == nil && hasSqlServerInstances(hostdata) {
+		licenseTypes, err := r.hds.getSqlServerDatabaseLicenseTypes()
+		if err != nil {
+			return err
+		}
+
+		r.sqlServerLicenseTypes = licenseTypes
+	}
+
+	if r.mySqlLicenseTypes == nil && hasMySqlInstances(hostdata) {
+		licenseTypes, err := r.hds.getMySqlDatabaseLicenseTypes()
+		if err != nil {
+			return err
+		}
+
+		r.mySqlLicenseTypes = licenseTypes
+	}
+
+	return nil
+}
+
+func hasOracleDatabases(hostdata *model.HostDataBE) bool {
+	return hostdata.Features.Oracle != nil && hostdata.Features.Oracle.Database != nil &&
+		hostdata.Features.Oracle.Database.Databases != nil
+}
+
+func hasSqlServerInstances(hostdata *model.HostDataBE) bool {
+	return hostdata.Features.Microsoft != nil && hostdata.Features.Microsoft.SQLServer != nil &&
+		hostdata.Features.Microsoft.SQLServer.Instances != nil
+}
+
+func hasMySqlInstances(hostdata *model.HostDataBE) bool {
+	return hostdata.Features.MySQL != nil && hostdata.Features.MySQL.Instances != nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/config"
+	"github.com/ercole-io/ercole/v2/data-service/dto"
+	"github.com/ercole-io/ercole/v2/logger"
+	"github.com/ercole-io/ercole/v2/model"
+	"github.com/ercole-io/ercole/v2/utils"
+	"github.com/ercole-io/ercole/v2/utils/jsonpatch"
+)
+
+func TestReprocessHostData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	apisc := NewMockApiSvcClientInterface(mockCtrl)
+	hds := HostDataService{
+		Config:       config.Configuration{},
+		Database:     db,
+		ApiSvcClient: apisc,
+		TimeNow:      utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:          logger.NewLogger("TEST"),
+	}
+
+	licenseTypes := []model.MySqlLicenseType{{ID: "B64911", ItemDescription: "MySQL Enterprise Edition"}}
+
+	getHosts := func() []model.HostDataBE {
+		return []model.HostDataBE{
+			{
+				ID:       utils.Str2oid("5dc3f534db7e81a98b726a52"),
+				Hostname: "foo",
+				Features: model.Features{
+					MySQL: &model.MySQLFeature{
+						Instances: []model.MySQLInstance{{Name: "mysql", Edition: model.MySQLEditionEnterprise}},
+					},
+				},
+			},
+			{
+				ID:       utils.Str2oid("5dc3f534db7e81a98b726a53"),
+				Hostname: "bar",
+				Features: model.Features{
+					MySQL: &model.MySQLFeature{
+						Instances: []model.MySQLInstance{{
+							Name:    "mysql",
+							Edition: model.MySQLEditionEnterprise,
+							License: model.MySQLLicense{LicenseTypeID: "B64911", Name: "MySQL Enterprise Edition", Count: 1},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	expectedDiff := []jsonpatch.Operation{
+		{Op: jsonpatch.OpReplace, Path: "/features/mysql/instances/0/license/count", Value: float64(1)},
+		{Op: jsonpatch.OpReplace, Path: "/features/mysql/instances/0/license/licenseTypeID", Value: "B64911"},
+		{Op: jsonpatch.OpReplace, Path: "/features/mysql/instances/0/license/name", Value: "MySQL Enterprise Edition"},
+	}
+
+	t.Run("Dry run", func(t *testing.T) {
+		req := dto.HostDataReprocessRequest{Technology: model.TechnologyOracleMySQL, DryRun: true}
+
+		db.EXPECT().FindCurrentHostData(nil, "", "", model.TechnologyOracleMySQL).Return(getHosts(), nil)
+		apisc.EXPECT().GetMySqlDatabaseLicenseTypes().Return(licenseTypes, nil).Times(1)
+
+		actual, err := hds.ReprocessHostData(req)
+		require.NoError(t, err)
+
+		expected := &dto.HostDataReprocessReport{
+			DryRun:    true,
+			Processed: 2,
+			Changed:   1,
+			Hosts: []dto.HostDataReprocessResult{
+				{Hostname: "foo", Changed: true, Diff: expectedDiff},
+				{Hostname: "bar", Changed: false, Diff: []jsonpatch.Operation{}},
+			},
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Save changed hostdata", func(t *testing.T) {
+		req := dto.HostDataReprocessRequest{Hostnames: []string{"foo", "bar"}}
+
+		db.EXPECT().FindCurrentHostData([]string{"foo", "bar"}, "", "", "").Return(getHosts(), nil)
+		apisc.EXPECT().GetMySqlDatabaseLicenseTypes().Return(licenseTypes, nil).Times(1)
+
+		expectedHost := getHosts()[0]
+		expectedHost.Features.MySQL.Instances[0].License = model.MySQLLicense{
+			LicenseTypeID: "B64911",
+			Name:          "MySQL Enterprise Edition",
+			Count:         1,
+		}
+		db.EXPECT().ReplaceHostData(expectedHost).Return(nil).Times(1)
+
+		actual, err := hds.ReprocessHostData(req)
+		require.NoError(t, err)
+
+		assert.False(t, actual.DryRun)
+		assert.Equal(t, 2, actual.Processed)
+		assert.Equal(t, 1, actual.Changed)
+		assert.Equal(t, expectedDiff, actual.Hosts[0].Diff)
+		assert.Empty(t, actual.Hosts[0].Error)
+	})
+
+	t.Run("Error saving hostdata", func(t *testing.T) {
+		db.EXPECT().FindCurrentHostData(nil, "", "", "").Return(getHosts()[:1], nil)
+		apisc.EXPECT().GetMySqlDatabaseLicenseTypes().Return(licenseTypes, nil)
+		db.EXPECT().ReplaceHostData(gomock.Any()).Return(utils.ErrHostNotFound)
+
+		actual, err := hds.ReprocessHostData(dto.HostDataReprocessRequest{})
+		require.NoError(t, err)
+
+		assert.Equal(t, utils.ErrHostNotFound.Error(), actual.Hosts[0].Error)
+	})
+
+	t.Run("Invalid technology", func(t *testing.T) {
+		db.EXPECT().FindCurrentHostData(nil, "", "", "Foo/Bar").
+			Return(nil, utils.NewErrorf("%w: unsupported technology %q", utils.ErrInvalidHostDataReprocess, "Foo/Bar"))
+
+		_, err := hds.ReprocessHostData(dto.HostDataReprocessRequest{Technology: "Foo/Bar"})
+		assert.True(t, errors.Is(err, utils.ErrInvalidHostDataReprocess))
+	})
+}
+
+func TestReprocessHostData_OracleLicenseTypes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := NewMockMongoDatabaseInterface(mockCtrl)
+	hds := HostDataService{
+		Config:   config.Configuration{},
+		Database: db,
+		TimeNow:  utils.Btc(utils.P("2019-11-05T14:02:03Z")),
+		Log:      logger.NewLogger("TEST"),
+	}
+
+	getHosts := func() []model.HostDataBE {
+		return []model.HostDataBE{
+			{
+				Hostname:    "foo",
+				Environment: "PROD",
+				Features: model.Features{
+					Oracle: &model.OracleFeature{
+						Database: &model.OracleDatabaseFeature{
+							Databases: []model.OracleDatabase{{
+								Name: "pippo",
+								Licenses: []model.OracleDatabaseLicense{
+									{LicenseTypeID: "A90570", Name: "Partitioning", Count: 2},
+									{LicenseTypeID: "A90649", Name: "Tuning Pack", Count: 1},
+								},
+							}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Alias moved to another license type", func(t *testing.T) {
+		licenseTypes := []model.OracleDatabaseLicenseType{
+			{ID: "A90570", ItemDescription: "Partitioning", Aliases: []string{}},
+			{ID: "L76084", ItemDescription: "Partitioning Named User Plus", Aliases: []string{"Partitioning"}},
+			{ID: "A90649", ItemDescription: "Tuning Pack", Aliases: []string{}},
+		}
+
+		db.EXPECT().FindCurrentHostData(nil, "", "", "").Return(getHosts(), nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(licenseTypes, nil).Times(1)
+
+		actual, err := hds.ReprocessHostData(dto.HostDataReprocessRequest{DryRun: true})
+		require.NoError(t, err)
+
+		expectedDiff := []jsonpatch.Operation{
+			{Op: jsonpatch.OpReplace, Path: "/features/oracle/database/databases/0/licenses/0/licenseTypeID", Value: "L76084"},
+			{Op: jsonpatch.OpReplace, Path: "/features/oracle/database/databases/0/licenses/1/licenseTypeID", Value: ""},
+		}
+		assert.Equal(t, 1, actual.Changed)
+		assert.ElementsMatch(t, expectedDiff, actual.Hosts[0].Diff)
+	})
+
+	t.Run("License types not retrieved", func(t *testing.T) {
+		db.EXPECT().FindCurrentHostData(nil, "", "", "").Return(getHosts(), nil)
+		db.EXPECT().GetOracleDatabaseLicenseTypes().Return(nil, errMock)
+
+		actual, err := hds.ReprocessHostData(dto.HostDataReprocessRequest{})
+		assert.ErrorIs(t, err, errMock)
+		assert.Nil(t, actual)
+	})
+}
+
+func TestIsLicensableSecondaryDb(t *testing.T) {
+	testCases := []struct {
+		db       model.OracleDatabase
+		expected bool
+	}{
+		{model.OracleDatabase{Status: "MOUNTED", Role: model.OracleDatabaseRolePrimary}, false},
+		{model.OracleDatabase{Status: "OPEN", Role: "PHYSICAL STANDBY"}, false},
+		{model.OracleDatabase{Status: "MOUNTED", Role: "PHYSICAL STANDBY"}, true},
+		{model.OracleDatabase{Status: "MOUNTED", Role: "PHYSICAL STANDBY", Dataguard: true}, false},
+		{model.OracleDatabase{Status: "MOUNTED", Role: "PHYSICAL STANDBY", Dataguard: true, IsRAC: true}, true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isLicensableSecondaryDb(&tc.db))
+	}
+}
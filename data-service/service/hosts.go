@@ -24,8 +24,24 @@ import (
 
 // UpdateHostInfo saves the hostdata
 func (hds *HostDataService) InsertHostData(hostdata model.HostDataBE) error {
+	return hds.insertHostData(hostdata, nil)
+}
+
+// insertHostData insert the hostdata, calling stored, if not nil, as soon as the hostdata is stored
+func (hds *HostDataService) insertHostData(hostdata model.HostDataBE, stored func()) error {
 	var err error
 
+	hostdata.Hostname, err = hds.canonicalHostname(hostdata.Hostname)
+	if err != nil {
+		hds.Log.Error(err)
+		return err
+	}
+
+	if err := hds.applyCmdbOverrides(&hostdata); err != nil {
+		hds.Log.Error(err)
+		return err
+	}
+
 	hostdata.ServerVersion = hds.ServerVersion
 	hostdata.Archived = false
 	hostdata.CreatedAt = hds.TimeNow()
@@ -50,6 +66,8 @@ func (hds *HostDataService) InsertHostData(hostdata model.HostDataBE) error {
 		}
 	}
 
+	hds.hostDriftChecks(previousHostdata, &hostdata)
+
 	if hostdata.Features.Oracle != nil {
 		hds.oracleDatabasesChecks(previousHostdata, &hostdata)
 	}
@@ -66,21 +84,32 @@ func (hds *HostDataService) InsertHostData(hostdata model.HostDataBE) error {
 		hds.clusterInfoChecks(hostdata.Clusters)
 	}
 
-	err = hds.Database.DismissHost(hostdata.Hostname)
-	if err != nil {
-		return err
-	}
-
 	if hds.Config.DataService.LogInsertingHostdata {
 		hds.Log.Info(utils.ToJSON(hostdata))
 	}
 
-	err = hds.Database.InsertHostData(hostdata)
+	err = hds.Database.ArchiveAndInsertHostData(hostdata)
 	if err != nil {
 		return err
 	}
 
-	if err := hds.Database.DeleteNoDataAlertByHost(hostdata.Hostname); err != nil {
+	if stored != nil {
+		stored()
+	}
+
+	if previousHostdata != nil {
+		if err := hds.compactArchivedHostData(previousHostdata.ID); err != nil {
+			hds.Log.Error(err)
+		}
+	}
+
+	if metrics := hostdata.ConsumptionMetrics(); len(metrics) > 0 {
+		if err := hds.Database.InsertConsumptionMetrics(metrics); err != nil {
+			hds.Log.Error(err)
+		}
+	}
+
+	if err := hds.Database.ResolveNoDataAlertsByHost(hostdata.Hostname, hds.TimeNow()); err != nil {
 		hds.Log.Error(err)
 	}
 
@@ -58,12 +58,12 @@ func TestInsertHostData_Success(t *testing.T) {
 
 	t.Run("New host", func(t *testing.T) {
 		gomock.InOrder(
+			db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
 			db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil, nil),
 			asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
 				assert.Equal(t, "The host rac1_x was added to ercole", a.Description)
 			}).Return(nil),
-			db.EXPECT().DismissHost("rac1_x").Return(nil),
-			db.EXPECT().InsertHostData(gomock.Any()).
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).
 				Do(func(newHD model.HostDataBE) {
 					assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.ID.Timestamp())
 					assert.False(t, newHD.Archived)
@@ -75,24 +75,50 @@ func TestInsertHostData_Success(t *testing.T) {
 					//I assume that other fields are correct
 				}).
 				Return(nil),
-			db.EXPECT().DeleteNoDataAlertByHost(hd.Hostname).Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil),
 		)
 
 		err := hds.InsertHostData(hd)
 		require.NoError(t, err)
 	})
 
+	t.Run("New host with consumptions", func(t *testing.T) {
+		start, end := utils.P("2019-11-04T00:00:00Z"), utils.P("2019-11-05T00:00:00Z")
+		cpu := 1.5
+		hdWithConsumptions := hd
+		hdWithConsumptions.CpuConsumptions = []model.CpuConsumption{{TimeStart: &start, TimeEnd: &end, CpuAvg: &cpu}}
+
+		gomock.InOrder(
+			db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil, nil),
+			asc.EXPECT().ThrowNewAlert(gomock.Any()).Return(nil),
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(nil),
+			db.EXPECT().InsertConsumptionMetrics([]model.ConsumptionMetric{
+				{
+					Timestamp: start,
+					Meta:      model.ConsumptionMetricMeta{Hostname: hd.Hostname},
+					TimeEnd:   end,
+					CpuAvg:    &cpu,
+				},
+			}).Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil),
+		)
+
+		err := hds.InsertHostData(hdWithConsumptions)
+		require.NoError(t, err)
+	})
+
 	t.Run("Update dismissed host", func(t *testing.T) {
 		previousHostdata := &model.HostDataBE{Archived: true} // it's dismissed!
 
 		gomock.InOrder(
+			db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
 			db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).
 				Return(previousHostdata, nil),
 			asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
 				assert.Equal(t, "The host rac1_x was added to ercole", a.Description)
 			}).Return(nil),
-			db.EXPECT().DismissHost("rac1_x").Return(nil),
-			db.EXPECT().InsertHostData(gomock.Any()).
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).
 				Do(func(newHD model.HostDataBE) {
 					assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.ID.Timestamp())
 					assert.False(t, newHD.Archived)
@@ -104,7 +130,7 @@ func TestInsertHostData_Success(t *testing.T) {
 					//I assume that other fields are correct
 				}).
 				Return(nil),
-			db.EXPECT().DeleteNoDataAlertByHost(hd.Hostname).Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil),
 		)
 
 		err := hds.InsertHostData(hd)
@@ -114,10 +140,10 @@ func TestInsertHostData_Success(t *testing.T) {
 		previousHostdata := &model.HostDataBE{Archived: false}
 
 		gomock.InOrder(
+			db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
 			db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).
 				Return(previousHostdata, nil),
-			db.EXPECT().DismissHost("rac1_x").Return(nil),
-			db.EXPECT().InsertHostData(gomock.Any()).
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).
 				Do(func(newHD model.HostDataBE) {
 					assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.ID.Timestamp())
 					assert.False(t, newHD.Archived)
@@ -129,7 +155,27 @@ func TestInsertHostData_Success(t *testing.T) {
 					//I assume that other fields are correct
 				}).
 				Return(nil),
-			db.EXPECT().DeleteNoDataAlertByHost(hd.Hostname).Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil),
+		)
+
+		err := hds.InsertHostData(hd)
+		require.NoError(t, err)
+	})
+
+	t.Run("Host sent with an alias", func(t *testing.T) {
+		previousHostdata := &model.HostDataBE{Archived: false}
+		identity := &model.HostIdentity{Hostname: "rac1_y", Aliases: []string{hd.Hostname}}
+
+		gomock.InOrder(
+			db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(identity, nil),
+			db.EXPECT().FindMostRecentHostDataOlderThan("rac1_y", utils.P("2019-11-05T14:02:03Z")).
+				Return(previousHostdata, nil),
+			db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).
+				Do(func(newHD model.HostDataBE) {
+					assert.Equal(t, "rac1_y", newHD.Hostname)
+				}).
+				Return(nil),
+			db.EXPECT().ResolveNoDataAlertsByHost("rac1_y", utils.P("2019-11-05T14:02:03Z")).Return(nil),
 		)
 
 		err := hds.InsertHostData(hd)
@@ -158,11 +204,12 @@ func TestInsertHostData_DatabaseError1(t *testing.T) {
 	hd := mongoutils.LoadFixtureHostData(t, "../../fixture/test_dataservice_hostdata_v1_00.json")
 
 	gomock.InOrder(
+		db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
 		db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil, nil),
 		asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
 			assert.Equal(t, "The host rac1_x was added to ercole", a.Description)
 		}).Return(nil),
-		db.EXPECT().DismissHost("rac1_x").Return(aerrMock),
+		db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(aerrMock),
 	)
 
 	err := hds.InsertHostData(hd)
@@ -190,12 +237,12 @@ func TestInsertHostData_DatabaseError2(t *testing.T) {
 	hd := mongoutils.LoadFixtureHostData(t, "../../fixture/test_dataservice_hostdata_v1_00.json")
 
 	gomock.InOrder(
+		db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
 		db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil, nil),
 		asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
 			assert.Equal(t, "The host rac1_x was added to ercole", a.Description)
 		}).Return(nil),
-		db.EXPECT().DismissHost("rac1_x").Return(nil),
-		db.EXPECT().InsertHostData(gomock.Any()).Return(aerrMock).Do(func(newHD model.HostDataBE) {
+		db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(aerrMock).Do(func(newHD model.HostDataBE) {
 			assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.ID.Timestamp())
 			assert.False(t, newHD.Archived)
 			assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.CreatedAt)
@@ -232,12 +279,12 @@ func TestInsertHostData_HttpError(t *testing.T) {
 	hd := mongoutils.LoadFixtureHostData(t, "../../fixture/test_dataservice_hostdata_v1_00.json")
 
 	gomock.InOrder(
+		db.EXPECT().FindHostIdentityByAlias(hd.Hostname).Return(nil, nil),
 		db.EXPECT().FindMostRecentHostDataOlderThan(hd.Hostname, utils.P("2019-11-05T14:02:03Z")).Return(nil, nil),
 		asc.EXPECT().ThrowNewAlert(gomock.Any()).Do(func(a model.Alert) {
 			assert.Equal(t, "The host rac1_x was added to ercole", a.Description)
 		}).Return(nil),
-		db.EXPECT().DismissHost("rac1_x").Return(nil),
-		db.EXPECT().InsertHostData(gomock.Any()).Return(aerrMock).Do(func(newHD model.HostDataBE) {
+		db.EXPECT().ArchiveAndInsertHostData(gomock.Any()).Return(aerrMock).Do(func(newHD model.HostDataBE) {
 			assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.ID.Timestamp())
 			assert.False(t, newHD.Archived)
 			assert.Equal(t, utils.P("2019-11-05T14:02:03Z"), newHD.CreatedAt)
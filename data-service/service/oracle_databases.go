@@ -101,17 +101,19 @@ func (hds *HostDataService) checkSecondaryDbs(hostdata *model.HostDataBE) {
 	for i := range hostdata.Features.Oracle.Database.Databases {
 		db := &hostdata.Features.Oracle.Database.Databases[i]
 
-		if utils.Contains(model.OracleDatabaseStatusMounted, db.Status) &&
-			db.Role != model.OracleDatabaseRolePrimary {
-			if db.Dataguard && !db.IsRAC {
-				continue
-			}
-
+		if isLicensableSecondaryDb(db) {
 			hds.addLicensesToSecondaryDb(hostdata.Info, hostdata.CoreFactor(), db)
 		}
 	}
 }
 
+// isLicensableSecondaryDb return true if the db is a mounted secondary db that needs the licenses of its primary
+func isLicensableSecondaryDb(db *model.OracleDatabase) bool {
+	return utils.Contains(model.OracleDatabaseStatusMounted, db.Status) &&
+		db.Role != model.OracleDatabaseRolePrimary &&
+		(!db.Dataguard || db.IsRAC)
+}
+
 func (hds *HostDataService) addLicensesToSecondaryDb(hostInfo model.Host, hostCoreFactor float64, secondaryDb *model.OracleDatabase) {
 	dbs, err := hds.getPrimaryOpenOracleDatabases()
 	if err != nil {
@@ -119,15 +121,7 @@ func (hds *HostDataService) addLicensesToSecondaryDb(hostInfo model.Host, hostCo
 		return
 	}
 
-	var primaryDb *model.OracleDatabase
-
-	for i, db := range dbs {
-		if db.DbID == secondaryDb.DbID && db.Name == secondaryDb.Name {
-			primaryDb = &dbs[i]
-			break
-		}
-	}
-
+	primaryDb := findPrimaryDb(dbs, secondaryDb)
 	if primaryDb == nil {
 		if err := hds.ackOldMissingPrimaryDbAlerts(hostInfo.Hostname, secondaryDb.Name); err != nil {
 			hds.Log.Errorf("Can't ack MissingPrimaryDatabase alerts by filter: %s", err)
@@ -141,6 +135,21 @@ func (hds *HostDataService) addLicensesToSecondaryDb(hostInfo model.Host, hostCo
 		return
 	}
 
+	hds.copyPrimaryDbLicenses(hostInfo, hostCoreFactor, primaryDb, secondaryDb)
+}
+
+func findPrimaryDb(primaryDbs []model.OracleDatabase, secondaryDb *model.OracleDatabase) *model.OracleDatabase {
+	for i, db := range primaryDbs {
+		if db.DbID == secondaryDb.DbID && db.Name == secondaryDb.Name {
+			return &primaryDbs[i]
+		}
+	}
+
+	return nil
+}
+
+func (hds *HostDataService) copyPrimaryDbLicenses(hostInfo model.Host, hostCoreFactor float64,
+	primaryDb, secondaryDb *model.OracleDatabase) {
 	coreFactor, err := secondaryDb.CoreFactor(hostInfo, hostCoreFactor)
 	if err != nil {
 		hds.Log.Error(err.Error())
@@ -19,6 +19,8 @@ package service
 import (
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
 	"github.com/ercole-io/ercole/v2/config"
 	"github.com/ercole-io/ercole/v2/logger"
 	"github.com/ercole-io/ercole/v2/model"
@@ -31,8 +33,17 @@ import (
 
 type HostDataServiceInterface interface {
 	InsertHostData(hostdata model.HostDataBE) error
+	// EnqueueHostData queue the hostdata to be inserted asynchronously by the ingestion workers
+	EnqueueHostData(hostdata model.HostDataBE) (*model.HostDataIngestion, error)
+	GetHostDataIngestion(id primitive.ObjectID) (*model.HostDataIngestion, error)
+	// StartIngestionWorkers start the goroutines that process the queued hostdata
+	StartIngestionWorkers()
 	AlertInvalidHostData(validationErr error, hostdata *model.HostDataBE)
+	// ReprocessHostData recompute licenses, secondary databases and clusters of the selected current hostdata
+	ReprocessHostData(req dto.HostDataReprocessRequest) (*dto.HostDataReprocessReport, error)
 	CompareCmdbInfo(cmdbInfo dto.CmdbInfo) error
+	// SyncCmdbs synchronise the hosts with the configured CMDBs, or only with the one named source if it isn't empty
+	SyncCmdbs(source string) ([]dto.CmdbSyncReport, error)
 	InsertOracleLicenseTypes(licenseTypes []model.OracleDatabaseLicenseType) error
 	SanitizeLicenseTypes(raw []byte) ([]model.OracleDatabaseLicenseType, error)
 	SaveExadata(exadata *model.OracleExadataInstance) error
@@ -46,4 +57,7 @@ type HostDataService struct {
 	ApiSvcClient   apiservice_client.ApiSvcClientInterface
 	TimeNow        func() time.Time
 	Log            logger.Logger
+
+	// ingestionSignal wakes up an idle ingestion worker when a hostdata is queued
+	ingestionSignal chan struct{}
 }
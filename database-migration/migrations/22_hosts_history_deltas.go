@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package migrations
+
+import (
+	"context"
+	"time"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/model"
+)
+
+func init() {
+	err := migrate.Register(compact_archived_hostdata, nil)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// compact_archived_hostdata store the archived hostdata as deltas between full snapshots
+func compact_archived_hostdata(db *mongo.Database) error {
+	if _, err := db.Collection("hosts").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "historyDelta.baseID", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}); err != nil {
+		return err
+	}
+
+	hostnames, err := db.Collection("hosts").Distinct(context.TODO(), "hostname", bson.M{"archived": true})
+	if err != nil {
+		return err
+	}
+
+	for _, hostname := range hostnames {
+		if err := compactArchivedHostdataOfHost(db, hostname); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compactArchivedHostdataOfHost(db *mongo.Database, hostname interface{}) error {
+	interval := time.Duration(model.DefaultHostDataSnapshotIntervalHours) * time.Hour
+
+	cur, err := db.Collection("hosts").Find(context.TODO(),
+		bson.M{"hostname": hostname},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(context.TODO())
+
+	var previous *model.HostDataBE
+
+	var snapshotCreatedAt time.Time
+
+	for cur.Next(context.TODO()) {
+		var hostdata model.HostDataBE
+		if err := cur.Decode(&hostdata); err != nil {
+			return err
+		}
+
+		if hostdata.HistoryDelta != nil {
+			if previous == nil {
+				continue
+			}
+
+			full, err := hostdata.HistoryDelta.Apply(*previous)
+			if err != nil {
+				return err
+			}
+
+			previous, snapshotCreatedAt = full, hostdata.HistoryDelta.SnapshotCreatedAt
+
+			continue
+		}
+
+		if previous == nil || hostdata.CreatedAt.Sub(snapshotCreatedAt) >= interval {
+			previous, snapshotCreatedAt = &hostdata, hostdata.CreatedAt
+			continue
+		}
+
+		if !hostdata.Archived {
+			previous = &hostdata
+			continue
+		}
+
+		delta, err := model.NewHostDataHistoryDelta(*previous, hostdata, snapshotCreatedAt)
+		if err != nil {
+			return err
+		}
+
+		compacted, err := model.CompactHostData(hostdata, *delta)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.Collection("hosts").ReplaceOne(context.TODO(), bson.M{"_id": hostdata.ID}, compacted); err != nil {
+			return err
+		}
+
+		previous = &hostdata
+	}
+
+	return cur.Err()
+}
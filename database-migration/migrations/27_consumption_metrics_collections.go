@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package migrations
+
+import (
+	"context"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func init() {
+	err := migrate.Register(create_consumption_metrics_collections, nil)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// create_consumption_metrics_collections create the time-series collections of the consumption samples and of their
+// daily rollups; the retentions are applied by the data-service
+func create_consumption_metrics_collections(db *mongo.Database) error {
+	ctx := context.TODO()
+
+	cols, err := db.ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range []string{"consumption_metrics", "consumption_metrics_daily"} {
+		if utils.Contains(cols, collection) {
+			continue
+		}
+
+		timeSeries := options.TimeSeries().
+			SetTimeField("timestamp").
+			SetMetaField("meta").
+			SetGranularity("hours")
+
+		if err := db.CreateCollection(ctx, collection, options.CreateCollection().SetTimeSeriesOptions(timeSeries)); err != nil {
+			return err
+		}
+
+		if _, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "meta.hostname", Value: 1},
+				{Key: "meta.databaseName", Value: 1},
+				{Key: "timestamp", Value: 1},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package migrations
+
+import (
+	"context"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	err := migrate.Register(create_index_hosts_history_cache, nil)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// create_index_hosts_history_cache expire after a day the hostdata rebuilt from the deltas for the reads at a past date
+func create_index_hosts_history_cache(db *mongo.Database) error {
+	if _, err := db.Collection("hosts_history_cache").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "cachedAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(24 * 60 * 60),
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -32,8 +32,33 @@ type Alert struct {
 	Description             string                 `json:"description" bson:"description"`
 	Date                    time.Time              `json:"date" bson:"date"`
 	OtherInfo               map[string]interface{} `json:"otherInfo" bson:"otherInfo"`
+	Assignee                string                 `json:"assignee,omitempty" bson:"assignee,omitempty"`
+	SnoozedUntil            *time.Time             `json:"snoozedUntil,omitempty" bson:"snoozedUntil,omitempty"`
+	History                 []AlertHistoryEntry    `json:"history,omitempty" bson:"history,omitempty"`
 }
 
+// AlertHistoryEntry holds a comment or a change of status or assignee of an alert
+type AlertHistoryEntry struct {
+	Date     time.Time `json:"date" bson:"date"`
+	Username string    `json:"username" bson:"username"`
+	Action   string    `json:"action" bson:"action"`
+	Status   string    `json:"status,omitempty" bson:"status,omitempty"`
+	Assignee string    `json:"assignee,omitempty" bson:"assignee,omitempty"`
+	Severity string    `json:"severity,omitempty" bson:"severity,omitempty"`
+	Comment  string    `json:"comment,omitempty" bson:"comment,omitempty"`
+}
+
+// Alert history actions
+const (
+	AlertActionStatusChange   string = "STATUS_CHANGE"
+	AlertActionAssign         string = "ASSIGN"
+	AlertActionComment        string = "COMMENT"
+	AlertActionSeverityChange string = "SEVERITY_CHANGE"
+)
+
+// AlertSystemUsername is the username of the history entries made by ercole itself
+const AlertSystemUsername string = "ercole"
+
 const (
 	AlertCategoryEngine  string = "ENGINE"
 	AlertCategoryAgent   string = "AGENT"
@@ -55,6 +80,28 @@ const (
 	AlertCodeAgentError              string = "AGENT_ERROR"
 	AlertCodeDismissHost             string = "DISMISSED_HOST"
 
+	AlertCodeOSChanged                  string = "OS_CHANGED"
+	AlertCodeKernelChanged              string = "KERNEL_CHANGED"
+	AlertCodeDecreasedMemory            string = "DECREASED_MEMORY"
+	AlertCodeDecreasedSwap              string = "DECREASED_SWAP"
+	AlertCodeHardwareAbstractionChanged string = "HARDWARE_ABSTRACTION_CHANGED"
+	AlertCodeClusterMembershipChanged   string = "CLUSTER_MEMBERSHIP_CHANGED"
+	AlertCodeMissingFilesystem          string = "MISSING_FILESYSTEM"
+	AlertCodeDatabaseVersionChanged     string = "DATABASE_VERSION_CHANGED"
+	AlertCodeArchivelogDisabled         string = "ARCHIVELOG_DISABLED"
+	AlertCodeDataguardDisabled          string = "DATAGUARD_DISABLED"
+
+	AlertCodeTablespaceExhaustion string = "TABLESPACE_EXHAUSTION"
+	AlertCodeFilesystemExhaustion string = "FILESYSTEM_EXHAUSTION"
+	AlertCodeDatabaseExhaustion   string = "DATABASE_EXHAUSTION"
+
+	AlertCodeBackupMissing           string = "BACKUP_MISSING"
+	AlertCodeArchivelogBackupMissing string = "ARCHIVELOG_BACKUP_MISSING"
+	AlertCodeNoArchivelogMode        string = "NOARCHIVELOG_MODE"
+	AlertCodeBackupPolicyViolation   string = "BACKUP_POLICY_VIOLATION"
+
+	AlertCodeOraclePatchOutdated string = "ORACLE_PATCH_OUTDATED"
+
 	// AGENT
 
 	AlertCodeNoData string = "NO_DATA"
@@ -73,6 +120,35 @@ func getAlertCodes() []string {
 		AlertCodeNewServer, AlertCodeUnlistedRunningDatabase, AlertCodeMissingPrimaryDatabase, AlertCodeMissingHostInErcole, AlertCodeMissingHostInCmdb, AlertCodeAgentError,
 		AlertCodeNoData,
 		AlertCodeNewDatabase, AlertCodeNewLicense, AlertCodeNewOption, AlertCodeIncreasedCPUCores, AlertCodeMissingDatabase, AlertCodeDismissHost,
+		AlertCodeOSChanged, AlertCodeKernelChanged, AlertCodeDecreasedMemory, AlertCodeDecreasedSwap, AlertCodeHardwareAbstractionChanged,
+		AlertCodeClusterMembershipChanged, AlertCodeMissingFilesystem, AlertCodeDatabaseVersionChanged, AlertCodeArchivelogDisabled, AlertCodeDataguardDisabled,
+		AlertCodeTablespaceExhaustion, AlertCodeFilesystemExhaustion, AlertCodeDatabaseExhaustion,
+		AlertCodeBackupMissing, AlertCodeArchivelogBackupMissing, AlertCodeNoArchivelogMode, AlertCodeBackupPolicyViolation,
+		AlertCodeOraclePatchOutdated,
+	}
+}
+
+// CapacityExhaustionAlertCodes contains the code of the alert raised on the projected exhaustion of each kind of capacity forecast
+var CapacityExhaustionAlertCodes = map[string]string{
+	CapacityForecastKindTablespace: AlertCodeTablespaceExhaustion,
+	CapacityForecastKindFilesystem: AlertCodeFilesystemExhaustion,
+	CapacityForecastKindDatabase:   AlertCodeDatabaseExhaustion,
+}
+
+// BackupViolationAlertCodes contains the code of the alert raised on each violation of a backup policy
+var BackupViolationAlertCodes = map[string]string{
+	BackupViolationNoBackups:           AlertCodeBackupMissing,
+	BackupViolationNoArchivelogBackups: AlertCodeArchivelogBackupMissing,
+	BackupViolationNoArchivelogMode:    AlertCodeNoArchivelogMode,
+	BackupViolationMissingSchedule:     AlertCodeBackupPolicyViolation,
+	BackupViolationShortRetention:      AlertCodeBackupPolicyViolation,
+}
+
+// GetHostDriftAlertCodes return the codes of the alerts raised on host configuration drift
+func GetHostDriftAlertCodes() []string {
+	return []string{
+		AlertCodeOSChanged, AlertCodeKernelChanged, AlertCodeDecreasedMemory, AlertCodeDecreasedSwap, AlertCodeHardwareAbstractionChanged,
+		AlertCodeClusterMembershipChanged, AlertCodeMissingFilesystem, AlertCodeDatabaseVersionChanged, AlertCodeArchivelogDisabled, AlertCodeDataguardDisabled,
 	}
 }
 
@@ -86,6 +162,17 @@ func getAlertSeverities() []string {
 	return []string{AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical}
 }
 
+// IsValidAlertSeverity return true if severity is a valid alert severity
+func IsValidAlertSeverity(severity string) bool {
+	for _, s := range getAlertSeverities() {
+		if s == severity {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Alert status
 const (
 	// New contains string "NEW"
@@ -94,10 +181,40 @@ const (
 	AlertStatusAck string = "ACK"
 	// Dismissed contains string DISMISSED
 	AlertStatusDismissed string = "DISMISSED"
+	// Resolved contains string RESOLVED
+	AlertStatusResolved string = "RESOLVED"
+	// Snoozed contains string SNOOZED
+	AlertStatusSnoozed string = "SNOOZED"
 )
 
 func getAlertStatuses() []string {
-	return []string{AlertStatusNew, AlertStatusAck}
+	return []string{AlertStatusNew, AlertStatusAck, AlertStatusDismissed, AlertStatusResolved, AlertStatusSnoozed}
+}
+
+// IsValidAlertStatus return true if status is a valid alert status
+func IsValidAlertStatus(status string) bool {
+	for _, s := range getAlertStatuses() {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsOpenAlertStatus return true if an alert with the status is still waiting to be handled
+func IsOpenAlertStatus(status string) bool {
+	return status == AlertStatusNew || status == AlertStatusAck || status == AlertStatusSnoozed
+}
+
+// CanChangeStatus return true if the alert can be moved to the status newStatus.
+// Dismissed alerts can't change status, the other alerts can move to any other status
+func (alert Alert) CanChangeStatus(newStatus string) bool {
+	if !IsValidAlertStatus(newStatus) || alert.AlertStatus == newStatus {
+		return false
+	}
+
+	return alert.AlertStatus != AlertStatusDismissed
 }
 
 func (alert Alert) IsValid() bool {
@@ -0,0 +1,26 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import "time"
+
+// AlertGroup holds the alerts with the same code and host waiting to be notified together
+type AlertGroup struct {
+	// Key is the grouping key, made by the code and the host
+	Key     string    `json:"key" bson:"_id"`
+	Alerts  []Alert   `json:"alerts" bson:"alerts"`
+	FlushAt time.Time `json:"flushAt" bson:"flushAt"`
+}
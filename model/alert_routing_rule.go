@@ -0,0 +1,129 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"path"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+// AlertRoutingRule holds the conditions used to route the matching alerts to a set of notification targets.
+// Every condition left empty matches all the alerts
+type AlertRoutingRule struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description" bson:"description"`
+	Enabled     bool               `json:"enabled" bson:"enabled"`
+
+	Severities   []string `json:"severities" bson:"severities"`
+	Categories   []string `json:"categories" bson:"categories"`
+	Codes        []string `json:"codes" bson:"codes"`
+	Technologies []string `json:"technologies" bson:"technologies"`
+	// Hostnames contains shell patterns (i.e. "db-*.example.com") matched against the hostname of the alert
+	Hostnames    []string `json:"hostnames" bson:"hostnames"`
+	Locations    []string `json:"locations" bson:"locations"`
+	Environments []string `json:"environments" bson:"environments"`
+
+	// Notifiers contains the names of the notifiers configured in the alert service
+	Notifiers []string `json:"notifiers" bson:"notifiers"`
+	// EmailRecipients contains the email addresses that receive the alerts
+	EmailRecipients []string `json:"emailRecipients" bson:"emailRecipients"`
+}
+
+// IsValid return true if the rule has a name, at least a target and valid conditions
+func (rule AlertRoutingRule) IsValid() bool {
+	if strings.TrimSpace(rule.Name) == "" {
+		return false
+	}
+
+	if len(rule.Notifiers) == 0 && len(rule.EmailRecipients) == 0 {
+		return false
+	}
+
+	conditions := []struct {
+		values      []string
+		validValues []string
+	}{
+		{rule.Severities, getAlertSeverities()},
+		{rule.Categories, getAlertCategories()},
+		{rule.Codes, getAlertCodes()},
+	}
+
+	for _, c := range conditions {
+		for _, v := range c.values {
+			if !utils.Contains(c.validValues, v) {
+				return false
+			}
+		}
+	}
+
+	for _, pattern := range rule.Hostnames {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsHostInfo return true if the rule has conditions on the location or on the environment of the host
+func (rule AlertRoutingRule) NeedsHostInfo() bool {
+	return len(rule.Locations) > 0 || len(rule.Environments) > 0
+}
+
+// Matches return true if the alert, raised on a host with location and environment, satisfies all the conditions
+func (rule AlertRoutingRule) Matches(alert Alert, location, environment string) bool {
+	if !rule.Enabled {
+		return false
+	}
+
+	technology := ""
+	if alert.AlertAffectedTechnology != nil {
+		technology = *alert.AlertAffectedTechnology
+	}
+
+	hostname, _ := alert.OtherInfo["hostname"].(string)
+
+	return matchesAny(rule.Severities, alert.AlertSeverity) &&
+		matchesAny(rule.Categories, alert.AlertCategory) &&
+		matchesAny(rule.Codes, alert.AlertCode) &&
+		matchesAny(rule.Technologies, technology) &&
+		matchesAnyPattern(rule.Hostnames, hostname) &&
+		matchesAny(rule.Locations, location) &&
+		matchesAny(rule.Environments, environment)
+}
+
+func matchesAny(values []string, value string) bool {
+	return len(values) == 0 || utils.Contains(values, value)
+}
+
+func matchesAnyPattern(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value)); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertRoutingRuleIsValid(t *testing.T) {
+	valid := AlertRoutingRule{
+		Name:       "licensing",
+		Severities: []string{AlertSeverityCritical},
+		Categories: []string{AlertCategoryLicense},
+		Codes:      []string{AlertCodeNewOption},
+		Hostnames:  []string{"db-*"},
+		Notifiers:  []string{"licensing"},
+	}
+	assert.True(t, valid.IsValid())
+
+	noName := valid
+	noName.Name = " "
+	assert.False(t, noName.IsValid())
+
+	noTargets := valid
+	noTargets.Notifiers = nil
+	assert.False(t, noTargets.IsValid())
+
+	wrongCode := valid
+	wrongCode.Codes = []string{"PIPPO"}
+	assert.False(t, wrongCode.IsValid())
+
+	wrongPattern := valid
+	wrongPattern.Hostnames = []string{"db-["}
+	assert.False(t, wrongPattern.IsValid())
+}
+
+func TestAlertRoutingRuleMatches(t *testing.T) {
+	alert := Alert{
+		AlertCategory:           AlertCategoryEngine,
+		AlertAffectedTechnology: TechnologyOracleDatabasePtr,
+		AlertCode:               AlertCodeUnlistedRunningDatabase,
+		AlertSeverity:           AlertSeverityWarning,
+		OtherInfo:               map[string]interface{}{"hostname": "DB-01.example.com"},
+	}
+
+	rule := AlertRoutingRule{
+		Enabled:      true,
+		Categories:   []string{AlertCategoryAgent, AlertCategoryEngine},
+		Technologies: []string{TechnologyOracleDatabase},
+		Hostnames:    []string{"db-*.example.com"},
+		Locations:    []string{"Italy"},
+	}
+
+	assert.True(t, rule.Matches(alert, "Italy", "PRD"))
+	assert.False(t, rule.Matches(alert, "Germany", "PRD"))
+
+	disabled := rule
+	disabled.Enabled = false
+	assert.False(t, disabled.Matches(alert, "Italy", "PRD"))
+
+	otherHost := rule
+	otherHost.Hostnames = []string{"web-*"}
+	assert.False(t, otherHost.Matches(alert, "Italy", "PRD"))
+
+	assert.True(t, AlertRoutingRule{Enabled: true}.Matches(alert, "", ""))
+	assert.True(t, rule.NeedsHostInfo())
+	assert.False(t, AlertRoutingRule{}.NeedsHostInfo())
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertIsValid_Statuses(t *testing.T) {
+	alert := Alert{
+		AlertCategory: AlertCategoryAgent,
+		AlertCode:     AlertCodeNoData,
+		AlertSeverity: AlertSeverityCritical,
+	}
+
+	for _, status := range []string{AlertStatusNew, AlertStatusAck, AlertStatusDismissed, AlertStatusResolved, AlertStatusSnoozed} {
+		alert.AlertStatus = status
+		assert.True(t, alert.IsValid(), status)
+	}
+
+	alert.AlertStatus = "FOOBAR"
+	assert.False(t, alert.IsValid())
+}
+
+func TestAlertCanChangeStatus(t *testing.T) {
+	alert := Alert{AlertStatus: AlertStatusNew}
+	assert.True(t, alert.CanChangeStatus(AlertStatusSnoozed))
+	assert.True(t, alert.CanChangeStatus(AlertStatusResolved))
+	assert.False(t, alert.CanChangeStatus(AlertStatusNew))
+	assert.False(t, alert.CanChangeStatus("FOOBAR"))
+
+	resolved := Alert{AlertStatus: AlertStatusResolved}
+	assert.True(t, resolved.CanChangeStatus(AlertStatusNew))
+
+	dismissed := Alert{AlertStatus: AlertStatusDismissed}
+	assert.False(t, dismissed.CanChangeStatus(AlertStatusNew))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIToken is a long-lived and revocable token used by the automations to authenticate to the api-service.
+// Only the hash of the token is stored
+type APIToken struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description" bson:"description"`
+	TokenHash   string             `json:"-" bson:"tokenHash"`
+	// Groups contains the groups granted to the token
+	Groups     []string   `json:"groups" bson:"groups"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	CreatedBy  string     `json:"createdBy" bson:"createdBy"`
+}
+
+// IsExpired return true if the token has an expiration date not after now
+func (token APIToken) IsExpired(now time.Time) bool {
+	return token.ExpiresAt != nil && !token.ExpiresAt.After(now)
+}
+
+// Username return the name of the user impersonated by the token
+func (token APIToken) Username() string {
+	return APITokenUsernamePrefix + token.Name
+}
+
+// APITokenUsernamePrefix is the prefix of the username of the requests authenticated by an API token
+const APITokenUsernamePrefix = "token:"
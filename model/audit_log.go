@@ -0,0 +1,212 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audited entity types
+const (
+	AuditEntityOracleDatabaseContract    = "ORACLE_DATABASE_CONTRACT"
+	AuditEntitySqlServerDatabaseContract = "MICROSOFT_SQLSERVER_DATABASE_CONTRACT"
+	AuditEntityMySQLContract             = "MYSQL_CONTRACT"
+	AuditEntityOracleDatabaseLicenseType = "ORACLE_DATABASE_LICENSE_TYPE"
+	AuditEntityOracleDatabaseLicense     = "ORACLE_DATABASE_LICENSE"
+	AuditEntityMySQLLicense              = "MYSQL_LICENSE"
+	AuditEntitySqlServerLicense          = "MICROSOFT_SQLSERVER_LICENSE"
+	AuditEntityHost                      = "HOST"
+	AuditEntityHostIdentity              = "HOST_IDENTITY"
+	AuditEntityHostMetadata              = "HOST_METADATA"
+	AuditEntityConfig                    = "CONFIG"
+	AuditEntityUser                      = "USER"
+	AuditEntityGroup                     = "GROUP"
+	AuditEntityRole                      = "ROLE"
+	AuditEntityOraclePatchCatalogue      = "ORACLE_PATCH_CATALOGUE"
+	AuditEntityAPIToken                  = "API_TOKEN"
+	AuditEntityAlertRoutingRule          = "ALERT_ROUTING_RULE"
+	AuditEntityAlert                     = "ALERT"
+)
+
+// Audited actions
+const (
+	AuditActionCreate        = "CREATE"
+	AuditActionUpdate        = "UPDATE"
+	AuditActionDelete        = "DELETE"
+	AuditActionDismiss       = "DISMISS"
+	AuditActionMerge         = "MERGE"
+	AuditActionResetPassword = "RESET_PASSWORD"
+)
+
+// auditMask replaces the values of the secret fields in the audit log
+const auditMask = "***"
+
+// auditURLUserInfoRegex match the user info, i.e. the credentials, of the URLs in the values of the audit log
+var auditURLUserInfoRegex = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.-]*://)[^/?#@\s]+@`)
+
+// AuditActor contains who made a change and from where
+type AuditActor struct {
+	Username string `json:"username" bson:"username"`
+	SourceIP string `json:"sourceIP" bson:"sourceIP"`
+	Method   string `json:"method" bson:"method"`
+	Endpoint string `json:"endpoint" bson:"endpoint"`
+}
+
+// AuditLogEntry contains a change made by a user
+type AuditLogEntry struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	Date       time.Time          `json:"date" bson:"date"`
+	AuditActor `bson:",inline"`
+	EntityType string        `json:"entityType" bson:"entityType"`
+	EntityID   string        `json:"entityID" bson:"entityID"`
+	Action     string        `json:"action" bson:"action"`
+	Changes    []AuditChange `json:"changes" bson:"changes"`
+}
+
+// AuditChange contains the JSON encoded values, before and after the change, of a field of the entity.
+// The nested fields are joined by dots
+type AuditChange struct {
+	Field  string `json:"field" bson:"field"`
+	Before string `json:"before,omitempty" bson:"before,omitempty"`
+	After  string `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// IgnoredLicenseChange contains the new values of the ignored fields of a license
+type IgnoredLicenseChange struct {
+	Ignored        bool   `json:"ignored"`
+	IgnoredComment string `json:"ignoredComment"`
+}
+
+// NewAuditChanges return the fields that differ between before and after, sorted by name.
+// Either of them can be nil, respectively when the entity is created or deleted.
+// The values of the fields that look like secrets are masked
+func NewAuditChanges(before, after interface{}) []AuditChange {
+	beforeFields := flattenAuditFields(before)
+	afterFields := flattenAuditFields(after)
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	changes := make([]AuditChange, 0)
+
+	for _, name := range names {
+		b, a := beforeFields[name], afterFields[name]
+		if b == a {
+			continue
+		}
+
+		if isAuditSecret(name) {
+			if b != "" {
+				b = auditMask
+			}
+
+			if a != "" {
+				a = auditMask
+			}
+		}
+
+		changes = append(changes, AuditChange{Field: name, Before: b, After: a})
+	}
+
+	return changes
+}
+
+// flattenAuditFields return the JSON encoded values of the leaf fields of v by their dotted names
+func flattenAuditFields(v interface{}) map[string]string {
+	fields := make(map[string]string)
+
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		fields[""] = fmt.Sprintf("%q", err.Error())
+		return fields
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		fields[""] = string(raw)
+		return fields
+	}
+
+	flattenAuditValue(fields, "", generic)
+
+	return fields
+}
+
+func flattenAuditValue(fields map[string]string, prefix string, v interface{}) {
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+
+		return prefix + "." + name
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for name, inner := range value {
+			flattenAuditValue(fields, join(name), inner)
+		}
+	case []interface{}:
+		for i, inner := range value {
+			flattenAuditValue(fields, join(fmt.Sprint(i)), inner)
+		}
+	case string:
+		raw, _ := json.Marshal(auditURLUserInfoRegex.ReplaceAllString(value, "${1}"+auditMask+"@"))
+		fields[prefix] = string(raw)
+	default:
+		raw, _ := json.Marshal(value)
+		fields[prefix] = string(raw)
+	}
+}
+
+// isAuditSecret return true if the values of the field are secrets, like passwords and the URIs and URLs,
+// that may contain credentials
+func isAuditSecret(field string) bool {
+	field = strings.ToLower(field)
+
+	for _, secret := range []string{"password", "secret", "token", "privatekey", "salt"} {
+		if strings.Contains(field, secret) {
+			return true
+		}
+	}
+
+	name := field[strings.LastIndex(field, ".")+1:]
+
+	return strings.HasSuffix(name, "uri") || strings.HasSuffix(name, "url")
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import "time"
+
+// Violations of a backup policy
+const (
+	// BackupViolationNoBackups is the violation of a database without backups
+	BackupViolationNoBackups = "NO_BACKUPS"
+	// BackupViolationNoArchivelogBackups is the violation of a database without archivelog backups
+	BackupViolationNoArchivelogBackups = "NO_ARCHIVELOG_BACKUPS"
+	// BackupViolationNoArchivelogMode is the violation of a database not in archivelog mode
+	BackupViolationNoArchivelogMode = "NOARCHIVELOG_MODE"
+	// BackupViolationMissingSchedule is the violation of a database whose backups aren't scheduled as required
+	BackupViolationMissingSchedule = "MISSING_SCHEDULE"
+	// BackupViolationShortRetention is the violation of a database whose backups aren't retained enough
+	BackupViolationShortRetention = "SHORT_RETENTION"
+)
+
+// BackupViolations contains the valid violations of a backup policy
+var BackupViolations = []string{
+	BackupViolationNoBackups,
+	BackupViolationNoArchivelogBackups,
+	BackupViolationNoArchivelogMode,
+	BackupViolationMissingSchedule,
+	BackupViolationShortRetention,
+}
+
+// BackupCompliance contains the evaluation of the backups of an Oracle database against the backup policy of its host
+type BackupCompliance struct {
+	Hostname     string            `json:"hostname" bson:"hostname"`
+	Location     string            `json:"location" bson:"location"`
+	Environment  string            `json:"environment" bson:"environment"`
+	DatabaseName string            `json:"databaseName" bson:"databaseName"`
+	Policy       string            `json:"policy" bson:"policy"`
+	Compliant    bool              `json:"compliant" bson:"compliant"`
+	Violations   []BackupViolation `json:"violations" bson:"violations"`
+	EvaluatedAt  time.Time         `json:"evaluatedAt" bson:"evaluatedAt"`
+}
+
+// BackupViolation contains a violation of a backup policy
+type BackupViolation struct {
+	Code        string `json:"code" bson:"code"`
+	Description string `json:"description" bson:"description"`
+}
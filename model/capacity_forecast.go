@@ -0,0 +1,161 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Kinds of the items whose capacity is forecasted
+const (
+	CapacityForecastKindTablespace = "TABLESPACE"
+	CapacityForecastKindFilesystem = "FILESYSTEM"
+	CapacityForecastKindDatabase   = "DATABASE"
+)
+
+// CapacityForecastKinds contains the valid kinds of a capacity forecast
+var CapacityForecastKinds = []string{CapacityForecastKindTablespace, CapacityForecastKindFilesystem, CapacityForecastKindDatabase}
+
+// maxCapacityForecastDays is the farthest exhaustion that is projected, the slower growths never exhaust the capacity
+const maxCapacityForecastDays = 36500
+
+// CapacityItem identifies the tablespace, the filesystem or the database of a host whose capacity is forecasted
+type CapacityItem struct {
+	Hostname string `json:"hostname" bson:"hostname"`
+	Kind     string `json:"kind" bson:"kind"`
+	// DatabaseName contains the database of the tablespace, or the database itself
+	DatabaseName string `json:"databaseName,omitempty" bson:"databaseName,omitempty"`
+	// Name contains the name of the tablespace or of the database, or the mount point of the filesystem
+	Name string `json:"name" bson:"name"`
+}
+
+// CapacitySample contains the used space and the capacity of an item in a hostdata, in the units of the hostdata
+type CapacitySample struct {
+	Date     time.Time `json:"date" bson:"date"`
+	Used     float64   `json:"used" bson:"used"`
+	Capacity float64   `json:"capacity" bson:"capacity"`
+}
+
+// CapacityForecast contains the growth trend of the used space of an item and its projected exhaustion
+type CapacityForecast struct {
+	CapacityItem `bson:",inline"`
+	Location     string `json:"location" bson:"location"`
+	Environment  string `json:"environment" bson:"environment"`
+	// Used and Capacity are the ones of the last sample
+	Used     float64 `json:"used" bson:"used"`
+	Capacity float64 `json:"capacity" bson:"capacity"`
+	// GrowthPerDay is the slope of the linear trend of the used space
+	GrowthPerDay float64 `json:"growthPerDay" bson:"growthPerDay"`
+	// DaysToFull and ExhaustionDate are nil if the item isn't projected to be full
+	DaysToFull     *float64         `json:"daysToFull" bson:"daysToFull"`
+	ExhaustionDate *time.Time       `json:"exhaustionDate" bson:"exhaustionDate"`
+	Samples        []CapacitySample `json:"samples,omitempty" bson:"samples"`
+	ComputedAt     time.Time        `json:"computedAt" bson:"computedAt"`
+}
+
+// CapacitySamples return the samples of the filesystems, the Oracle databases and their tablespaces of the hostdata
+func CapacitySamples(hostdata HostDataBE) map[CapacityItem]CapacitySample {
+	samples := make(map[CapacityItem]CapacitySample)
+
+	for _, fs := range hostdata.Filesystems {
+		item := CapacityItem{Hostname: hostdata.Hostname, Kind: CapacityForecastKindFilesystem, Name: fs.MountedOn}
+		samples[item] = CapacitySample{Date: hostdata.CreatedAt, Used: float64(fs.UsedSpace), Capacity: float64(fs.Size)}
+	}
+
+	if hostdata.Features.Oracle == nil || hostdata.Features.Oracle.Database == nil {
+		return samples
+	}
+
+	for _, db := range hostdata.Features.Oracle.Database.Databases {
+		item := CapacityItem{Hostname: hostdata.Hostname, Kind: CapacityForecastKindDatabase, DatabaseName: db.Name, Name: db.Name}
+		samples[item] = CapacitySample{Date: hostdata.CreatedAt, Used: db.SegmentsSize, Capacity: db.DatafileSize}
+
+		for _, ts := range db.Tablespaces {
+			item := CapacityItem{Hostname: hostdata.Hostname, Kind: CapacityForecastKindTablespace, DatabaseName: db.Name, Name: ts.Name}
+			samples[item] = CapacitySample{Date: hostdata.CreatedAt, Used: ts.Used, Capacity: math.Max(ts.MaxSize, ts.Total)}
+		}
+	}
+
+	return samples
+}
+
+// NewCapacityForecast fit the linear trend of the used space of the samples, that mustn't be empty,
+// and project when the item will be full at that rate
+func NewCapacityForecast(item CapacityItem, samples []CapacitySample, now time.Time) CapacityForecast {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Date.Before(samples[j].Date)
+	})
+
+	last := samples[len(samples)-1]
+
+	forecast := CapacityForecast{
+		CapacityItem: item,
+		Used:         last.Used,
+		Capacity:     last.Capacity,
+		GrowthPerDay: capacityGrowthPerDay(samples),
+		Samples:      samples,
+		ComputedAt:   now,
+	}
+
+	if last.Capacity <= 0 {
+		return forecast
+	}
+
+	var exhaustionDate time.Time
+
+	switch {
+	case last.Used >= last.Capacity:
+		exhaustionDate = last.Date
+	case forecast.GrowthPerDay > 0:
+		days := (last.Capacity - last.Used) / forecast.GrowthPerDay
+		if days > maxCapacityForecastDays {
+			return forecast
+		}
+
+		exhaustionDate = last.Date.Add(time.Duration(days * float64(24*time.Hour)))
+	default:
+		return forecast
+	}
+
+	daysToFull := math.Max(0, exhaustionDate.Sub(now).Hours()/24)
+	forecast.DaysToFull = &daysToFull
+	forecast.ExhaustionDate = &exhaustionDate
+
+	return forecast
+}
+
+// capacityGrowthPerDay return the slope of the least squares line of the used space by day
+func capacityGrowthPerDay(samples []CapacitySample) float64 {
+	n := float64(len(samples))
+
+	var sumX, sumY, sumXY, sumXX float64
+
+	for _, s := range samples {
+		x := s.Date.Sub(samples[0].Date).Hours() / 24
+		sumX += x
+		sumY += s.Used
+		sumXY += x * s.Used
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+
+	return (n*sumXY - sumX*sumY) / denominator
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func TestCapacitySamples(t *testing.T) {
+	hostdata := HostDataBE{
+		Hostname:  "test-db",
+		CreatedAt: utils.P("2020-12-05T14:02:03Z"),
+		Filesystems: []Filesystem{
+			{MountedOn: "/", Size: 1000, UsedSpace: 400},
+		},
+		Features: Features{
+			Oracle: &OracleFeature{
+				Database: &OracleDatabaseFeature{
+					Databases: []OracleDatabase{
+						{
+							Name:         "ERCOLE",
+							DatafileSize: 100,
+							SegmentsSize: 60,
+							Tablespaces: []OracleDatabaseTablespace{
+								{Name: "USERS", Total: 50, MaxSize: 200, Used: 40},
+								{Name: "SYSTEM", Total: 80, MaxSize: 0, Used: 70},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := map[CapacityItem]CapacitySample{
+		{Hostname: "test-db", Kind: CapacityForecastKindFilesystem, Name: "/"}: {
+			Date: hostdata.CreatedAt, Used: 400, Capacity: 1000,
+		},
+		{Hostname: "test-db", Kind: CapacityForecastKindDatabase, DatabaseName: "ERCOLE", Name: "ERCOLE"}: {
+			Date: hostdata.CreatedAt, Used: 60, Capacity: 100,
+		},
+		{Hostname: "test-db", Kind: CapacityForecastKindTablespace, DatabaseName: "ERCOLE", Name: "USERS"}: {
+			Date: hostdata.CreatedAt, Used: 40, Capacity: 200,
+		},
+		{Hostname: "test-db", Kind: CapacityForecastKindTablespace, DatabaseName: "ERCOLE", Name: "SYSTEM"}: {
+			Date: hostdata.CreatedAt, Used: 70, Capacity: 80,
+		},
+	}
+
+	assert.Equal(t, expected, CapacitySamples(hostdata))
+}
+
+func TestNewCapacityForecast(t *testing.T) {
+	item := CapacityItem{Hostname: "test-db", Kind: CapacityForecastKindFilesystem, Name: "/"}
+	now := utils.P("2020-12-10T00:00:00Z")
+
+	t.Run("Growing", func(t *testing.T) {
+		samples := []CapacitySample{
+			{Date: utils.P("2020-12-09T00:00:00Z"), Used: 520, Capacity: 1000},
+			{Date: utils.P("2020-12-01T00:00:00Z"), Used: 440, Capacity: 1000},
+			{Date: utils.P("2020-12-05T00:00:00Z"), Used: 480, Capacity: 1000},
+		}
+
+		actual := NewCapacityForecast(item, samples, now)
+
+		assert.Equal(t, item, actual.CapacityItem)
+		assert.Equal(t, 520.0, actual.Used)
+		assert.Equal(t, 1000.0, actual.Capacity)
+		assert.InDelta(t, 10, actual.GrowthPerDay, 1e-9)
+		assert.Equal(t, utils.P("2020-12-01T00:00:00Z"), actual.Samples[0].Date)
+		require.NotNil(t, actual.DaysToFull)
+		assert.InDelta(t, 47, *actual.DaysToFull, 1e-9)
+		assert.Equal(t, utils.P("2021-01-26T00:00:00Z"), *actual.ExhaustionDate)
+	})
+
+	t.Run("AlreadyFull", func(t *testing.T) {
+		samples := []CapacitySample{
+			{Date: utils.P("2020-12-01T00:00:00Z"), Used: 1000, Capacity: 1000},
+			{Date: utils.P("2020-12-09T00:00:00Z"), Used: 1000, Capacity: 1000},
+		}
+
+		actual := NewCapacityForecast(item, samples, now)
+
+		require.NotNil(t, actual.DaysToFull)
+		assert.Equal(t, 0.0, *actual.DaysToFull)
+		assert.Equal(t, utils.P("2020-12-09T00:00:00Z"), *actual.ExhaustionDate)
+	})
+
+	t.Run("NotGrowing", func(t *testing.T) {
+		samples := []CapacitySample{
+			{Date: utils.P("2020-12-01T00:00:00Z"), Used: 600, Capacity: 1000},
+			{Date: utils.P("2020-12-09T00:00:00Z"), Used: 500, Capacity: 1000},
+		}
+
+		actual := NewCapacityForecast(item, samples, now)
+
+		assert.InDelta(t, -12.5, actual.GrowthPerDay, 1e-9)
+		assert.Nil(t, actual.DaysToFull)
+		assert.Nil(t, actual.ExhaustionDate)
+	})
+
+	t.Run("TooSlow", func(t *testing.T) {
+		samples := []CapacitySample{
+			{Date: utils.P("2020-12-01T00:00:00Z"), Used: 500, Capacity: 1000},
+			{Date: utils.P("2020-12-09T00:00:00Z"), Used: 500.0001, Capacity: 1000},
+		}
+
+		actual := NewCapacityForecast(item, samples, now)
+
+		assert.Nil(t, actual.DaysToFull)
+	})
+
+	t.Run("SameDate", func(t *testing.T) {
+		samples := []CapacitySample{
+			{Date: utils.P("2020-12-09T00:00:00Z"), Used: 500, Capacity: 0},
+		}
+
+		actual := NewCapacityForecast(item, samples, now)
+
+		assert.Equal(t, 0.0, actual.GrowthPerDay)
+		assert.Nil(t, actual.DaysToFull)
+		assert.Equal(t, now, actual.ComputedAt)
+	})
+}
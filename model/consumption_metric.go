@@ -0,0 +1,219 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Resolutions of the consumption metrics
+const (
+	// ConsumptionResolutionRaw are the samples as received from the agents
+	ConsumptionResolutionRaw = "RAW"
+	// ConsumptionResolutionDaily are the daily rollups of the samples
+	ConsumptionResolutionDaily = "DAILY"
+)
+
+// ConsumptionResolutions contains the valid resolutions of the consumption metrics
+var ConsumptionResolutions = []string{ConsumptionResolutionRaw, ConsumptionResolutionDaily}
+
+// ConsumptionMetricMeta identifies the series of a consumption metric: a host, or a database of the host
+type ConsumptionMetricMeta struct {
+	Hostname string `json:"hostname" bson:"hostname"`
+	// DatabaseName is empty in the samples of the hosts
+	DatabaseName string `json:"databaseName,omitempty" bson:"databaseName,omitempty"`
+}
+
+// ConsumptionMetric contains the CPU and IO consumption of a host or a database in an interval
+type ConsumptionMetric struct {
+	// Timestamp contains the start of the interval
+	Timestamp time.Time             `json:"timestamp" bson:"timestamp"`
+	Meta      ConsumptionMetricMeta `json:"meta" bson:"meta"`
+	TimeEnd   time.Time             `json:"timeEnd" bson:"timeEnd"`
+	CpuAvg    *float64              `json:"cpuAvg,omitempty" bson:"cpuAvg,omitempty"`
+	CpuMax    *float64              `json:"cpuMax,omitempty" bson:"cpuMax,omitempty"`
+	IopsAvg   *float64              `json:"iopsAvg,omitempty" bson:"iopsAvg,omitempty"`
+	IopsMax   *float64              `json:"iopsMax,omitempty" bson:"iopsMax,omitempty"`
+	IombAvg   *float64              `json:"iombAvg,omitempty" bson:"iombAvg,omitempty"`
+	IombMax   *float64              `json:"iombMax,omitempty" bson:"iombMax,omitempty"`
+	// Samples contains the number of samples rolled up in a daily metric
+	Samples int `json:"samples,omitempty" bson:"samples,omitempty"`
+}
+
+// ConsumptionRollup contains the statistics of a consumption over a range of metrics
+type ConsumptionRollup struct {
+	// Samples contains the number of metrics with the consumption, the statistics are zero if there are none
+	Samples int     `json:"samples" bson:"samples"`
+	Avg     float64 `json:"avg" bson:"avg"`
+	P95     float64 `json:"p95" bson:"p95"`
+	Max     float64 `json:"max" bson:"max"`
+}
+
+// ConsumptionRollups contains the statistics of the CPU, IOPS and IO MB/s over a range of metrics
+type ConsumptionRollups struct {
+	Cpu  ConsumptionRollup `json:"cpu" bson:"cpu"`
+	Iops ConsumptionRollup `json:"iops" bson:"iops"`
+	Iomb ConsumptionRollup `json:"iomb" bson:"iomb"`
+}
+
+// ConsumptionMetrics return the metrics of the host and of its Oracle databases in the hostdata.
+// The samples spanning more days, which summarize the others, are skipped
+func (hd *HostDataBE) ConsumptionMetrics() []ConsumptionMetric {
+	metrics := make([]ConsumptionMetric, 0)
+	hostMetrics := make(map[time.Time]int)
+
+	hostMetric := func(start, end *time.Time) *ConsumptionMetric {
+		if i, ok := hostMetrics[*start]; ok {
+			return &metrics[i]
+		}
+
+		metrics = append(metrics, ConsumptionMetric{
+			Timestamp: *start,
+			Meta:      ConsumptionMetricMeta{Hostname: hd.Hostname},
+			TimeEnd:   *end,
+		})
+		hostMetrics[*start] = len(metrics) - 1
+
+		return &metrics[len(metrics)-1]
+	}
+
+	for _, c := range hd.CpuConsumptions {
+		if !isConsumptionSample(c.TimeStart, c.TimeEnd) {
+			continue
+		}
+
+		hostMetric(c.TimeStart, c.TimeEnd).CpuAvg = c.CpuAvg
+	}
+
+	for _, c := range hd.DiskConsumptions {
+		if !isConsumptionSample(c.TimeStart, c.TimeEnd) {
+			continue
+		}
+
+		metric := hostMetric(c.TimeStart, c.TimeEnd)
+		metric.IopsAvg = c.IopsHostDayAvg
+		metric.IombAvg = c.IombHostDayAvg
+	}
+
+	if hd.Features.Oracle == nil || hd.Features.Oracle.Database == nil {
+		return metrics
+	}
+
+	for _, db := range hd.Features.Oracle.Database.Databases {
+		for _, c := range db.CpuDiskConsumptions {
+			if !isConsumptionSample(c.TimeStart, c.TimeEnd) {
+				continue
+			}
+
+			metrics = append(metrics, ConsumptionMetric{
+				Timestamp: *c.TimeStart,
+				Meta:      ConsumptionMetricMeta{Hostname: hd.Hostname, DatabaseName: db.Name},
+				TimeEnd:   *c.TimeEnd,
+				CpuAvg:    c.CpuDbAvg,
+				CpuMax:    c.CpuDbMax,
+				IopsAvg:   c.IopsAvg,
+				IopsMax:   c.IopsMax,
+				IombAvg:   c.IombAvg,
+				IombMax:   c.IombMax,
+			})
+		}
+	}
+
+	return metrics
+}
+
+func isConsumptionSample(start, end *time.Time) bool {
+	if start == nil || end == nil {
+		return false
+	}
+
+	sample := CpuDiskConsumption{TimeStart: start, TimeEnd: end}
+
+	return !sample.IsRange()
+}
+
+// NewConsumptionRollups return the statistics of the consumptions of the metrics.
+// The average and the 95th percentile are computed on the averages, the maximum also on the maximums
+func NewConsumptionRollups(metrics []ConsumptionMetric) ConsumptionRollups {
+	cpu := make([]float64, 0, len(metrics))
+	iops := make([]float64, 0, len(metrics))
+	iomb := make([]float64, 0, len(metrics))
+	cpuMax, iopsMax, iombMax := 0.0, 0.0, 0.0
+
+	for _, m := range metrics {
+		cpu = appendConsumption(cpu, m.CpuAvg, m.CpuMax, &cpuMax)
+		iops = appendConsumption(iops, m.IopsAvg, m.IopsMax, &iopsMax)
+		iomb = appendConsumption(iomb, m.IombAvg, m.IombMax, &iombMax)
+	}
+
+	return ConsumptionRollups{
+		Cpu:  newConsumptionRollup(cpu, cpuMax),
+		Iops: newConsumptionRollup(iops, iopsMax),
+		Iomb: newConsumptionRollup(iomb, iombMax),
+	}
+}
+
+func appendConsumption(values []float64, avg, max *float64, maxValue *float64) []float64 {
+	if avg != nil {
+		values = append(values, *avg)
+		*maxValue = math.Max(*maxValue, *avg)
+	}
+
+	if max != nil {
+		*maxValue = math.Max(*maxValue, *max)
+	}
+
+	return values
+}
+
+func newConsumptionRollup(values []float64, max float64) ConsumptionRollup {
+	if len(values) == 0 {
+		return ConsumptionRollup{}
+	}
+
+	sort.Float64s(values)
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return ConsumptionRollup{
+		Samples: len(values),
+		Avg:     sum / float64(len(values)),
+		P95:     Percentile(values, 95),
+		Max:     max,
+	}
+}
+
+// Percentile return the p-th percentile of the sorted values with the nearest-rank method, zero if there aren't values
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
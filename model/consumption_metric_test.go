@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func consumptionTime(s string) *time.Time {
+	t := utils.P(s)
+	return &t
+}
+
+func consumptionValue(v float64) *float64 {
+	return &v
+}
+
+func TestHostDataBEConsumptionMetrics(t *testing.T) {
+	hostdata := HostDataBE{
+		Hostname: "test-db",
+		CpuConsumptions: []CpuConsumption{
+			{TimeStart: consumptionTime("2022-05-01T00:00:00Z"), TimeEnd: consumptionTime("2022-05-02T00:00:00Z"), CpuAvg: consumptionValue(2.5)},
+			{TimeStart: consumptionTime("2022-04-01T00:00:00Z"), TimeEnd: consumptionTime("2022-05-02T00:00:00Z"), CpuAvg: consumptionValue(2)},
+		},
+		DiskConsumptions: []DiskConsumption{
+			{TimeStart: consumptionTime("2022-05-01T00:00:00Z"), TimeEnd: consumptionTime("2022-05-02T00:00:00Z"), IopsHostDayAvg: consumptionValue(150), IombHostDayAvg: consumptionValue(12)},
+			{TimeStart: consumptionTime("2022-05-02T00:00:00Z"), TimeEnd: consumptionTime("2022-05-03T00:00:00Z"), IopsHostDayAvg: consumptionValue(100)},
+			{TimeEnd: consumptionTime("2022-05-03T00:00:00Z")},
+		},
+		Features: Features{Oracle: &OracleFeature{Database: &OracleDatabaseFeature{Databases: []OracleDatabase{
+			{
+				Name: "ERCOLE",
+				CpuDiskConsumptions: []CpuDiskConsumption{
+					{TimeStart: consumptionTime("2022-05-01T00:00:00Z"), TimeEnd: consumptionTime("2022-05-02T00:00:00Z"), CpuDbAvg: consumptionValue(1), CpuDbMax: consumptionValue(3)},
+				},
+			},
+		}}}},
+	}
+
+	expected := []ConsumptionMetric{
+		{
+			Timestamp: utils.P("2022-05-01T00:00:00Z"),
+			Meta:      ConsumptionMetricMeta{Hostname: "test-db"},
+			TimeEnd:   utils.P("2022-05-02T00:00:00Z"),
+			CpuAvg:    consumptionValue(2.5),
+			IopsAvg:   consumptionValue(150),
+			IombAvg:   consumptionValue(12),
+		},
+		{
+			Timestamp: utils.P("2022-05-02T00:00:00Z"),
+			Meta:      ConsumptionMetricMeta{Hostname: "test-db"},
+			TimeEnd:   utils.P("2022-05-03T00:00:00Z"),
+			IopsAvg:   consumptionValue(100),
+		},
+		{
+			Timestamp: utils.P("2022-05-01T00:00:00Z"),
+			Meta:      ConsumptionMetricMeta{Hostname: "test-db", DatabaseName: "ERCOLE"},
+			TimeEnd:   utils.P("2022-05-02T00:00:00Z"),
+			CpuAvg:    consumptionValue(1),
+			CpuMax:    consumptionValue(3),
+		},
+	}
+
+	assert.Equal(t, expected, hostdata.ConsumptionMetrics())
+	assert.Empty(t, (&HostDataBE{Hostname: "test-db"}).ConsumptionMetrics())
+}
+
+func TestNewConsumptionRollups(t *testing.T) {
+	metrics := make([]ConsumptionMetric, 0, 20)
+	for i := 1; i <= 20; i++ {
+		metrics = append(metrics, ConsumptionMetric{CpuAvg: consumptionValue(float64(i))})
+	}
+
+	metrics[0].CpuMax = consumptionValue(42)
+	metrics[0].IopsAvg = consumptionValue(100)
+
+	rollups := NewConsumptionRollups(metrics)
+
+	assert.Equal(t, ConsumptionRollup{Samples: 20, Avg: 10.5, P95: 19, Max: 42}, rollups.Cpu)
+	assert.Equal(t, ConsumptionRollup{Samples: 1, Avg: 100, P95: 100, Max: 100}, rollups.Iops)
+	assert.Equal(t, ConsumptionRollup{}, rollups.Iomb)
+}
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, Percentile(nil, 95))
+	assert.Equal(t, 5.0, Percentile([]float64{5}, 95))
+	assert.Equal(t, 2.0, Percentile([]float64{1, 2, 3, 4}, 50))
+	assert.Equal(t, 4.0, Percentile([]float64{1, 2, 3, 4}, 100))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HostIdentity contains the stable identity of a host: the canonical hostname, used to store its hostdata,
+// and the other names by which the host is or was known, like old names or the FQDN
+type HostIdentity struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Hostname  string             `json:"hostname" bson:"hostname"`
+	Aliases   []string           `json:"aliases" bson:"aliases"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// HasAlias return true if alias is one of the aliases of the host
+func (identity HostIdentity) HasAlias(alias string) bool {
+	for _, a := range identity.Aliases {
+		if a == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddAlias add alias to the aliases of the host, if it isn't the canonical hostname or already an alias
+func (identity *HostIdentity) AddAlias(alias string) {
+	if identity.Hostname == alias || identity.HasAlias(alias) {
+		return
+	}
+
+	identity.Aliases = append(identity.Aliases, alias)
+}
+
+// RemoveAlias remove alias from the aliases of the host. It return false if it wasn't an alias
+func (identity *HostIdentity) RemoveAlias(alias string) bool {
+	for i, a := range identity.Aliases {
+		if a == alias {
+			identity.Aliases = append(identity.Aliases[:i], identity.Aliases[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reasons of the suggestion of an alias
+const (
+	// HostAliasReasonSameShortHostname means that the hostnames are the same without the domain
+	HostAliasReasonSameShortHostname = "SAME_SHORT_HOSTNAME"
+	// HostAliasReasonSameOracleDatabase means that the hosts have an Oracle database with the same name and DBID
+	HostAliasReasonSameOracleDatabase = "SAME_ORACLE_DATABASE"
+	// HostAliasReasonSameHardware means that the hosts have the same hardware and the source stopped sending data.
+	// It's never the only reason of a suggestion
+	HostAliasReasonSameHardware = "SAME_HARDWARE"
+)
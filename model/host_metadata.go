@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import "time"
+
+// Host criticalities
+const (
+	HostCriticalityLow      = "LOW"
+	HostCriticalityMedium   = "MEDIUM"
+	HostCriticalityHigh     = "HIGH"
+	HostCriticalityCritical = "CRITICAL"
+)
+
+// HostCriticalities contains the valid criticalities of a host
+var HostCriticalities = []string{HostCriticalityLow, HostCriticalityMedium, HostCriticalityHigh, HostCriticalityCritical}
+
+// HostMetadata contains the informations about a host that aren't sent by its agent
+type HostMetadata struct {
+	Hostname string `json:"hostname" bson:"hostname"`
+	// OwnerTeam, ContactEmail, BusinessApplication, CostCenter, Criticality and Labels are edited by the users
+	OwnerTeam           string    `json:"ownerTeam" bson:"ownerTeam"`
+	ContactEmail        string    `json:"contactEmail" bson:"contactEmail"`
+	BusinessApplication string    `json:"businessApplication" bson:"businessApplication"`
+	CostCenter          string    `json:"costCenter" bson:"costCenter"`
+	Criticality         string    `json:"criticality" bson:"criticality"`
+	Labels              []string  `json:"labels" bson:"labels"`
+	UpdatedAt           time.Time `json:"updatedAt" bson:"updatedAt"`
+	// Cmdb contains the record of the host in the CMDB, if any
+	Cmdb *CmdbRecord `json:"cmdb,omitempty" bson:"cmdb,omitempty"`
+}
+
+// CmdbRecord contains the informations about a host read from a CMDB
+type CmdbRecord struct {
+	// Source contains the name of the CMDB
+	Source string `json:"source" bson:"source"`
+	// Hostname contains the hostname in the CMDB, that can differ from the one in Ercole
+	Hostname        string    `json:"hostname" bson:"hostname"`
+	Environment     string    `json:"environment" bson:"environment"`
+	Location        string    `json:"location" bson:"location"`
+	Owner           string    `json:"owner" bson:"owner"`
+	BusinessService string    `json:"businessService" bson:"businessService"`
+	Decommissioned  bool      `json:"decommissioned" bson:"decommissioned"`
+	SyncedAt        time.Time `json:"syncedAt" bson:"syncedAt"`
+}
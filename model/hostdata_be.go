@@ -49,6 +49,9 @@ type HostDataBE struct {
 	Errors                  []AgentError            `json:"errors" bson:"errors"`
 	CpuConsumptions            []CpuConsumption        `json:"cpuConsumptions"`
 	DiskConsumptions        []DiskConsumption       `json:"diskConsumptions"`
+
+	// HistoryDelta is set in the archived hostdata stored as the differences from the previous one
+	HistoryDelta *HostDataHistoryDelta `json:"historyDelta,omitempty" bson:"historyDelta,omitempty"`
 }
 
 func (v *HostDataBE) GetClusterCores(hostdatasPerHostname map[string]*HostDataBE) (int, error) {
@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/utils/jsonpatch"
+)
+
+// Storage modes of the archived hostdata
+const (
+	// HostDataHistoryModeFull stores every archived hostdata as it is
+	HostDataHistoryModeFull = "full"
+	// HostDataHistoryModeDelta stores a full snapshot periodically and, between them,
+	// the differences from the previous hostdata
+	HostDataHistoryModeDelta = "delta"
+)
+
+// DefaultHostDataSnapshotIntervalHours is the default interval between two full snapshots
+const DefaultHostDataSnapshotIntervalHours = 168
+
+// HostDataHistoryDelta contains the differences of an archived hostdata from the previous one of the same host
+type HostDataHistoryDelta struct {
+	// BaseID is the id of the previous hostdata, that can be a delta too
+	BaseID primitive.ObjectID `json:"baseID" bson:"baseID"`
+	// SnapshotCreatedAt is the creation date of the full snapshot at the start of the chain of deltas
+	SnapshotCreatedAt time.Time `json:"snapshotCreatedAt" bson:"snapshotCreatedAt"`
+	// Patch is the JSON patch that changes the base hostdata into this one
+	Patch string `json:"patch" bson:"patch"`
+}
+
+// hostDataHistoryHeaderFields are the fields kept in a compacted hostdata, to filter and list the archived hostdata
+var hostDataHistoryHeaderFields = []string{
+	"_id", "archived", "createdAt", "dismissedAt", "serverVersion", "serverSchemaVersion", "period",
+	"hostname", "location", "environment", "agentVersion", "tags", "info", "clusterMembershipStatus",
+}
+
+// hostDataHistoryDatabaseFields are the fields of the Oracle databases kept in a compacted hostdata,
+// used to show the growth of the databases
+var hostDataHistoryDatabaseFields = []string{
+	"name", "datafileSize", "segmentsSize", "allocable", "dailyCPUUsage",
+}
+
+// NewHostDataHistoryDelta return the differences of hostdata from base
+func NewHostDataHistoryDelta(base, hostdata HostDataBE, snapshotCreatedAt time.Time) (*HostDataHistoryDelta, error) {
+	base.HistoryDelta, hostdata.HistoryDelta = nil, nil
+
+	rawBase, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+
+	rawHostdata, err := json.Marshal(hostdata)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := jsonpatch.Diff(rawBase, rawHostdata)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostDataHistoryDelta{
+		BaseID:            base.ID,
+		SnapshotCreatedAt: snapshotCreatedAt,
+		Patch:             string(patch),
+	}, nil
+}
+
+// Apply return the full hostdata obtained applying the delta to its base
+func (delta HostDataHistoryDelta) Apply(base HostDataBE) (*HostDataBE, error) {
+	base.HistoryDelta = nil
+
+	rawBase, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []jsonpatch.Operation
+	if err := json.Unmarshal([]byte(delta.Patch), &ops); err != nil {
+		return nil, err
+	}
+
+	raw, err := jsonpatch.Apply(rawBase, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostdata HostDataBE
+	if err := json.Unmarshal(raw, &hostdata); err != nil {
+		return nil, err
+	}
+
+	return &hostdata, nil
+}
+
+// CompactHostData return the document that replaces an archived hostdata stored as delta:
+// the header fields, the sizes of the Oracle databases and the delta
+func CompactHostData(hostdata HostDataBE, delta HostDataHistoryDelta) (bson.M, error) {
+	hostdata.HistoryDelta = nil
+
+	raw, err := bson.Marshal(hostdata)
+	if err != nil {
+		return nil, err
+	}
+
+	var full bson.M
+	if err := bson.Unmarshal(raw, &full); err != nil {
+		return nil, err
+	}
+
+	compacted := bson.M{"historyDelta": delta}
+
+	for _, field := range hostDataHistoryHeaderFields {
+		if value, ok := full[field]; ok {
+			compacted[field] = value
+		}
+	}
+
+	if hostdata.Features.Oracle != nil && hostdata.Features.Oracle.Database != nil {
+		databases := bson.A{}
+
+		rawDatabases := full["features"].(bson.M)["oracle"].(bson.M)["database"].(bson.M)["databases"]
+		if rawDatabases, ok := rawDatabases.(bson.A); ok {
+			for _, rawDb := range rawDatabases {
+				db := bson.M{}
+
+				for _, field := range hostDataHistoryDatabaseFields {
+					db[field] = rawDb.(bson.M)[field]
+				}
+
+				databases = append(databases, db)
+			}
+		}
+
+		compacted["features"] = bson.M{"oracle": bson.M{"database": bson.M{"databases": databases}}}
+	}
+
+	return compacted, nil
+}
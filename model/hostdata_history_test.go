@@ -0,0 +1,99 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func loadHostDataForHistoryTest(t *testing.T) HostDataBE {
+	raw, err := os.ReadFile("../fixture/test_dataservice_hostdata_v1_04.json")
+	require.NoError(t, err)
+
+	var hostdata HostDataBE
+	require.NoError(t, json.Unmarshal(raw, &hostdata))
+
+	hostdata.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d3")
+	hostdata.CreatedAt = utils.P("2020-12-05T14:02:03Z")
+
+	return hostdata
+}
+
+func TestHostDataHistoryDelta(t *testing.T) {
+	base := loadHostDataForHistoryTest(t)
+
+	hostdata := loadHostDataForHistoryTest(t)
+	hostdata.ID = utils.Str2oid("5ef9d239a1d25d1e8703c4d4")
+	hostdata.CreatedAt = utils.P("2020-12-06T14:02:03Z")
+	hostdata.Archived = true
+	hostdata.Info.MemoryTotal = 64
+	hostdata.Features.Oracle.Database.Databases[0].DatafileSize = 42
+	hostdata.Features.Oracle.Database.Databases = append(hostdata.Features.Oracle.Database.Databases,
+		OracleDatabase{Name: "NEWDB"})
+
+	delta, err := NewHostDataHistoryDelta(base, hostdata, base.CreatedAt)
+	require.NoError(t, err)
+	assert.Equal(t, base.ID, delta.BaseID)
+	assert.Equal(t, base.CreatedAt, delta.SnapshotCreatedAt)
+
+	actual, err := delta.Apply(base)
+	require.NoError(t, err)
+	assert.Equal(t, hostdata, *actual)
+}
+
+func TestCompactHostData(t *testing.T) {
+	hostdata := loadHostDataForHistoryTest(t)
+	hostdata.Archived = true
+
+	delta := HostDataHistoryDelta{
+		BaseID:            primitive.NewObjectID(),
+		SnapshotCreatedAt: utils.P("2020-12-01T14:02:03Z"),
+		Patch:             "[]",
+	}
+
+	actual, err := CompactHostData(hostdata, delta)
+	require.NoError(t, err)
+
+	assert.Equal(t, hostdata.ID, actual["_id"])
+	assert.Equal(t, hostdata.Hostname, actual["hostname"])
+	assert.Equal(t, true, actual["archived"])
+	assert.Equal(t, delta, actual["historyDelta"])
+	assert.NotContains(t, actual, "filesystems")
+
+	raw, err := bson.Marshal(actual)
+	require.NoError(t, err)
+
+	var compacted HostDataBE
+	require.NoError(t, bson.Unmarshal(raw, &compacted))
+
+	assert.Equal(t, hostdata.Info, compacted.Info)
+	require.Len(t, compacted.Features.Oracle.Database.Databases, len(hostdata.Features.Oracle.Database.Databases))
+
+	expectedDb := hostdata.Features.Oracle.Database.Databases[0]
+	actualDb := compacted.Features.Oracle.Database.Databases[0]
+	assert.Equal(t, expectedDb.Name, actualDb.Name)
+	assert.Equal(t, expectedDb.DatafileSize, actualDb.DatafileSize)
+	assert.Equal(t, expectedDb.SegmentsSize, actualDb.SegmentsSize)
+	assert.Empty(t, actualDb.Tablespaces)
+}
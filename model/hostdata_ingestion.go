@@ -0,0 +1,53 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Statuses of a hostdata ingestion
+const (
+	HostDataIngestionStatusQueued     = "QUEUED"
+	HostDataIngestionStatusProcessing = "PROCESSING"
+	HostDataIngestionStatusDone       = "DONE"
+	// HostDataIngestionStatusFailed is the status of the ingestions moved to the dead letter
+	// after all the attempts failed
+	HostDataIngestionStatusFailed = "FAILED"
+)
+
+// HostDataIngestion is a hostdata received by the data service and waiting to be processed
+type HostDataIngestion struct {
+	ID primitive.ObjectID `json:"id" bson:"_id"`
+	// IdempotencyKey is made of hostname and payload hash, it's removed when the ingestion fails
+	// so the same hostdata can be sent again
+	IdempotencyKey string     `json:"idempotencyKey,omitempty" bson:"idempotencyKey,omitempty"`
+	Hostname       string     `json:"hostname" bson:"hostname"`
+	PayloadHash    string     `json:"payloadHash" bson:"payloadHash"`
+	Status         string     `json:"status" bson:"status"`
+	Attempts       int        `json:"attempts" bson:"attempts"`
+	LastError      string     `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt" bson:"createdAt"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	LeaseExpiresAt *time.Time `json:"leaseExpiresAt,omitempty" bson:"leaseExpiresAt,omitempty"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+	// ThrownAlerts are the keys of the alerts already thrown by the attempts, so a retry doesn't throw them again
+	ThrownAlerts []string `json:"-" bson:"thrownAlerts,omitempty"`
+	// StoredAt is set when an attempt stored the hostdata, so a retry doesn't store it again
+	StoredAt *time.Time `json:"storedAt,omitempty" bson:"storedAt,omitempty"`
+	HostData HostDataBE `json:"-" bson:"hostData"`
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types of the Oracle patch releases
+const (
+	// OraclePatchTypeRU is a Release Update
+	OraclePatchTypeRU = "RU"
+	// OraclePatchTypeRUR is a Release Update Revision
+	OraclePatchTypeRUR = "RUR"
+	// OraclePatchTypePSU is a Patch Set Update
+	OraclePatchTypePSU = "PSU"
+)
+
+// OraclePatchTypes contains the valid types of the Oracle patch releases
+var OraclePatchTypes = []string{OraclePatchTypeRU, OraclePatchTypeRUR, OraclePatchTypePSU}
+
+// Support levels of the Oracle versions
+const (
+	OracleSupportPremier     = "PREMIER"
+	OracleSupportExtended    = "EXTENDED"
+	OracleSupportUnsupported = "UNSUPPORTED"
+	OracleSupportUnknown     = "UNKNOWN"
+)
+
+// OraclePatchRelease contains a patch release of the catalogue
+type OraclePatchRelease struct {
+	PatchNumber string `json:"patchNumber" bson:"patchNumber"`
+	Type        string `json:"type" bson:"type"`
+	// Version contains the version patched by the release, e.g. 19 or 12.2.0.1
+	Version string `json:"version" bson:"version"`
+	// Release contains the release number, e.g. 19.15.0.0.220419
+	Release     string    `json:"release" bson:"release"`
+	ReleaseDate time.Time `json:"releaseDate" bson:"releaseDate"`
+	Description string    `json:"description" bson:"description"`
+}
+
+// OracleVersionSupport contains the end of the support of an Oracle version, zero if it isn't announced
+type OracleVersionSupport struct {
+	Version            string    `json:"version" bson:"version"`
+	PremierSupportEnd  time.Time `json:"premierSupportEnd" bson:"premierSupportEnd"`
+	ExtendedSupportEnd time.Time `json:"extendedSupportEnd" bson:"extendedSupportEnd"`
+}
+
+// OraclePatchCatalogue contains the patch releases and the support of the Oracle versions
+type OraclePatchCatalogue struct {
+	Releases []OraclePatchRelease   `json:"releases" bson:"releases"`
+	Versions []OracleVersionSupport `json:"versions" bson:"versions"`
+}
+
+// OraclePatchAdvice contains the position of the patches installed on a database in the catalogue
+type OraclePatchAdvice struct {
+	// CatalogueVersion contains the version of the catalogue matching the database, empty if none matches
+	CatalogueVersion string `json:"catalogueVersion" bson:"catalogueVersion"`
+	// InstalledRelease contains the release of the catalogue installed on the database, if it's found
+	InstalledRelease *OraclePatchRelease `json:"installedRelease" bson:"installedRelease"`
+	// LatestRelease contains the latest release of the catalogue for the version
+	LatestRelease *OraclePatchRelease `json:"latestRelease" bson:"latestRelease"`
+	// ReleasesBehind contains the number of releases newer than the installed one, nil if the version isn't in the catalogue
+	ReleasesBehind *int   `json:"releasesBehind" bson:"releasesBehind"`
+	SupportStatus  string `json:"supportStatus" bson:"supportStatus"`
+}
+
+var (
+	oraclePatchNumberRegexp     = regexp.MustCompile(`\((\d+)\)`)
+	oraclePatchReleaseRegexp    = regexp.MustCompile(`\d+(\.\d+){3,4}`)
+	oracleDatabaseVersionRegexp = regexp.MustCompile(`^\d+(\.\d+)*`)
+)
+
+// Advise return the position in the catalogue of the patch with the description, installed on a database with the version.
+// When the installed release isn't in the catalogue, the releases newer than the installation date are counted
+func (c *OraclePatchCatalogue) Advise(databaseVersion, patchDescription string, patchDate, now time.Time) OraclePatchAdvice {
+	advice := OraclePatchAdvice{SupportStatus: OracleSupportUnknown}
+
+	version := oracleDatabaseVersionRegexp.FindString(strings.TrimSpace(databaseVersion))
+	if version == "" {
+		return advice
+	}
+
+	for _, release := range c.Releases {
+		if len(release.Version) > len(advice.CatalogueVersion) && oracleVersionMatches(version, release.Version) {
+			advice.CatalogueVersion = release.Version
+		}
+	}
+
+	for _, support := range c.Versions {
+		if len(support.Version) > len(advice.CatalogueVersion) && oracleVersionMatches(version, support.Version) {
+			advice.CatalogueVersion = support.Version
+		}
+	}
+
+	if advice.CatalogueVersion == "" {
+		return advice
+	}
+
+	advice.SupportStatus = c.supportStatus(advice.CatalogueVersion, now)
+
+	releases := c.versionReleases(advice.CatalogueVersion)
+	if len(releases) == 0 {
+		return advice
+	}
+
+	advice.InstalledRelease = findInstalledOracleRelease(releases, patchDescription)
+
+	behind := 0
+
+	for i := range releases {
+		release := &releases[i]
+
+		if advice.InstalledRelease != nil {
+			if !sameOracleReleaseTrack(release.Type, advice.InstalledRelease.Type) ||
+				!release.ReleaseDate.After(advice.InstalledRelease.ReleaseDate) {
+				continue
+			}
+		} else if release.Type == OraclePatchTypeRUR || (!patchDate.IsZero() && !release.ReleaseDate.After(patchDate)) {
+			continue
+		}
+
+		behind++
+	}
+
+	advice.ReleasesBehind = &behind
+
+	for i := range releases {
+		if releases[i].Type == OraclePatchTypeRUR && (advice.InstalledRelease == nil || advice.InstalledRelease.Type != OraclePatchTypeRUR) {
+			continue
+		}
+
+		advice.LatestRelease = &releases[i]
+	}
+
+	return advice
+}
+
+// oracleVersionMatches return true if the catalogue version is the database version or one of its prefixes
+func oracleVersionMatches(databaseVersion, catalogueVersion string) bool {
+	return databaseVersion == catalogueVersion || strings.HasPrefix(databaseVersion, catalogueVersion+".")
+}
+
+// sameOracleReleaseTrack return true if the releases of the types are counted together: the RURs only with the RURs
+func sameOracleReleaseTrack(a, b string) bool {
+	return (a == OraclePatchTypeRUR) == (b == OraclePatchTypeRUR)
+}
+
+// versionReleases return the releases of the version sorted by release date
+func (c *OraclePatchCatalogue) versionReleases(version string) []OraclePatchRelease {
+	releases := make([]OraclePatchRelease, 0)
+
+	for _, release := range c.Releases {
+		if release.Version == version {
+			releases = append(releases, release)
+		}
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].ReleaseDate.Before(releases[j].ReleaseDate)
+	})
+
+	return releases
+}
+
+func (c *OraclePatchCatalogue) supportStatus(version string, now time.Time) string {
+	for _, support := range c.Versions {
+		if support.Version != version {
+			continue
+		}
+
+		switch {
+		case support.PremierSupportEnd.IsZero() && support.ExtendedSupportEnd.IsZero():
+			return OracleSupportUnknown
+		case support.PremierSupportEnd.IsZero() || now.Before(support.PremierSupportEnd):
+			return OracleSupportPremier
+		case support.ExtendedSupportEnd.IsZero() || now.Before(support.ExtendedSupportEnd):
+			return OracleSupportExtended
+		default:
+			return OracleSupportUnsupported
+		}
+	}
+
+	return OracleSupportUnknown
+}
+
+// findInstalledOracleRelease return the release with the patch number or the release number in the description
+func findInstalledOracleRelease(releases []OraclePatchRelease, description string) *OraclePatchRelease {
+	if match := oraclePatchNumberRegexp.FindStringSubmatch(description); match != nil {
+		for i := range releases {
+			if releases[i].PatchNumber == match[1] {
+				return &releases[i]
+			}
+		}
+	}
+
+	if number := oraclePatchReleaseRegexp.FindString(description); number != "" {
+		for i := range releases {
+			if releases[i].Release == number {
+				return &releases[i]
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
+func testOraclePatchCatalogue() OraclePatchCatalogue {
+	return OraclePatchCatalogue{
+		Releases: []OraclePatchRelease{
+			{PatchNumber: "33806152", Type: OraclePatchTypeRU, Version: "19", Release: "19.15.0.0.220419", ReleaseDate: utils.P("2022-04-19T00:00:00Z")},
+			{PatchNumber: "30125133", Type: OraclePatchTypeRU, Version: "19", Release: "19.5.0.0.191015", ReleaseDate: utils.P("2019-10-15T00:00:00Z")},
+			{PatchNumber: "30557433", Type: OraclePatchTypeRU, Version: "19", Release: "19.6.0.0.200114", ReleaseDate: utils.P("2020-01-14T00:00:00Z")},
+			{PatchNumber: "30593149", Type: OraclePatchTypeRUR, Version: "19", Release: "19.5.1.0.200114", ReleaseDate: utils.P("2020-01-14T00:00:00Z")},
+			{PatchNumber: "16902043", Type: OraclePatchTypePSU, Version: "11.2.0.3", Release: "11.2.0.3.8", ReleaseDate: utils.P("2013-10-15T00:00:00Z")},
+			{PatchNumber: "16619892", Type: OraclePatchTypePSU, Version: "11.2.0.3", Release: "11.2.0.3.7", ReleaseDate: utils.P("2013-07-16T00:00:00Z")},
+		},
+		Versions: []OracleVersionSupport{
+			{Version: "19", PremierSupportEnd: utils.P("2024-04-30T00:00:00Z"), ExtendedSupportEnd: utils.P("2027-04-30T00:00:00Z")},
+			{Version: "11.2.0.3", PremierSupportEnd: utils.P("2015-01-31T00:00:00Z"), ExtendedSupportEnd: utils.P("2015-08-27T00:00:00Z")},
+			{Version: "12.2.0.1"},
+		},
+	}
+}
+
+func TestOraclePatchCatalogueAdvise(t *testing.T) {
+	catalogue := testOraclePatchCatalogue()
+	now := utils.P("2023-01-01T00:00:00Z")
+
+	t.Run("InstalledRU", func(t *testing.T) {
+		advice := catalogue.Advise("19.0.0.0.0 Enterprise Edition", "Database Release Update : 19.5.0.0.191015 (30125133)", time.Time{}, now)
+
+		assert.Equal(t, "19", advice.CatalogueVersion)
+		require.NotNil(t, advice.InstalledRelease)
+		assert.Equal(t, "30125133", advice.InstalledRelease.PatchNumber)
+		require.NotNil(t, advice.LatestRelease)
+		assert.Equal(t, "33806152", advice.LatestRelease.PatchNumber)
+		require.NotNil(t, advice.ReleasesBehind)
+		assert.Equal(t, 2, *advice.ReleasesBehind)
+		assert.Equal(t, OracleSupportPremier, advice.SupportStatus)
+	})
+
+	t.Run("InstalledPSUByRelease", func(t *testing.T) {
+		advice := catalogue.Advise("11.2.0.3.0", "PSU 11.2.0.3.7", time.Time{}, now)
+
+		assert.Equal(t, "11.2.0.3", advice.CatalogueVersion)
+		require.NotNil(t, advice.InstalledRelease)
+		assert.Equal(t, "16619892", advice.InstalledRelease.PatchNumber)
+		require.NotNil(t, advice.ReleasesBehind)
+		assert.Equal(t, 1, *advice.ReleasesBehind)
+		assert.Equal(t, OracleSupportUnsupported, advice.SupportStatus)
+	})
+
+	t.Run("UnknownReleaseByDate", func(t *testing.T) {
+		advice := catalogue.Advise("19.0.0.0.0", "", utils.P("2019-12-01T00:00:00Z"), utils.P("2025-01-01T00:00:00Z"))
+
+		assert.Nil(t, advice.InstalledRelease)
+		require.NotNil(t, advice.ReleasesBehind)
+		assert.Equal(t, 2, *advice.ReleasesBehind)
+		assert.Equal(t, "33806152", advice.LatestRelease.PatchNumber)
+		assert.Equal(t, OracleSupportExtended, advice.SupportStatus)
+	})
+
+	t.Run("VersionWithoutReleases", func(t *testing.T) {
+		advice := catalogue.Advise("12.2.0.1.0 Enterprise Edition", "", time.Time{}, now)
+
+		assert.Equal(t, "12.2.0.1", advice.CatalogueVersion)
+		assert.Nil(t, advice.ReleasesBehind)
+		assert.Nil(t, advice.LatestRelease)
+		assert.Equal(t, OracleSupportUnknown, advice.SupportStatus)
+	})
+
+	t.Run("VersionNotInCatalogue", func(t *testing.T) {
+		advice := catalogue.Advise("18.2.0.1.0", "", time.Time{}, now)
+
+		assert.Equal(t, OraclePatchAdvice{SupportStatus: OracleSupportUnknown}, advice)
+	})
+}
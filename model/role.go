@@ -15,6 +15,12 @@
 
 package model
 
+import (
+	"strings"
+
+	"github.com/ercole-io/ercole/v2/utils"
+)
+
 const (
 	AdminPermission = "admin"
 	ReadPermission  = "read"
@@ -22,9 +28,111 @@ const (
 	AllLocation     = "All"
 )
 
+// Resources identified by id whose access is restricted to the locations of their hosts
+const (
+	AlertResource                     = "alert"
+	OracleDatabaseContractResource    = "oracle-database-contract"
+	MySQLContractResource             = "mysql-contract"
+	SqlServerDatabaseContractResource = "sqlserver-database-contract"
+)
+
 type Role struct {
 	Name        string `json:"name" bson:"name"`
 	Description string `json:"description" bson:"description"`
 	Location    string `json:"location" bson:"location"`
 	Permission  string `json:"permission" bson:"permission"`
 }
+
+// permissionLevels orders the permissions, from the lowest
+var permissionLevels = map[string]int{
+	ReadPermission:  1,
+	WritePermission: 2,
+	AdminPermission: 3,
+}
+
+// HasPermission return true if the permission is valid and grants at least the required one
+func HasPermission(permission, required string) bool {
+	level, ok := permissionLevels[permission]
+
+	return ok && level >= permissionLevels[required]
+}
+
+// UserAuthorization contains the locations granted to a user by the roles of its groups and the permission on each of them
+type UserAuthorization struct {
+	AllLocations bool     `json:"allLocations"`
+	Locations    []string `json:"locations"`
+	// Permissions contains the highest permission granted on each location, by AllLocation for the roles on all the locations
+	Permissions map[string]string `json:"permissions"`
+}
+
+// Grant adds the permission on the location, keeping the highest permission granted on it
+func (a *UserAuthorization) Grant(location, permission string) {
+	if a.Permissions == nil {
+		a.Permissions = make(map[string]string)
+	}
+
+	if location == AllLocation {
+		a.AllLocations = true
+	} else if !utils.Contains(a.Locations, location) {
+		a.Locations = append(a.Locations, location)
+	}
+
+	if !HasPermission(a.Permissions[location], permission) {
+		a.Permissions[location] = permission
+	}
+}
+
+// PermissionOn return the highest permission granted on the location
+func (a UserAuthorization) PermissionOn(location string) string {
+	permission := a.Permissions[AllLocation]
+
+	if p, ok := a.Permissions[location]; ok && !HasPermission(permission, p) {
+		permission = p
+	}
+
+	return permission
+}
+
+// CanAccessLocation return true if the user can access the data of the location
+func (a UserAuthorization) CanAccessLocation(location string) bool {
+	return a.AllLocations || utils.Contains(a.Locations, location)
+}
+
+// CanWrite return true if the user can modify the data of at least one location
+func (a UserAuthorization) CanWrite() bool {
+	for _, permission := range a.Permissions {
+		if HasPermission(permission, WritePermission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanWriteLocation return true if the user can modify the data of the location
+func (a UserAuthorization) CanWriteLocation(location string) bool {
+	return HasPermission(a.PermissionOn(location), WritePermission)
+}
+
+// CanWriteAllLocations return true if the user can modify the data of every location
+func (a UserAuthorization) CanWriteAllLocations() bool {
+	return HasPermission(a.Permissions[AllLocation], WritePermission)
+}
+
+// ScopeLocations return the requested locations, comma separated, restricted to the granted ones.
+// If no location is requested, it return all the granted ones
+func (a UserAuthorization) ScopeLocations(requested string) []string {
+	if requested == "" {
+		return a.Locations
+	}
+
+	locations := make([]string, 0)
+
+	for _, l := range strings.Split(requested, ",") {
+		if a.CanAccessLocation(l) {
+			locations = append(locations, l)
+		}
+	}
+
+	return locations
+}
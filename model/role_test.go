@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	assert.True(t, HasPermission(AdminPermission, WritePermission))
+	assert.True(t, HasPermission(WritePermission, WritePermission))
+	assert.True(t, HasPermission(ReadPermission, ReadPermission))
+	assert.False(t, HasPermission(ReadPermission, WritePermission))
+	assert.False(t, HasPermission("", ReadPermission))
+	assert.False(t, HasPermission("pippo", ""))
+}
+
+func TestUserAuthorization_ScopeLocations(t *testing.T) {
+	authorization := UserAuthorization{
+		Locations:   []string{"Italy", "France"},
+		Permissions: map[string]string{"Italy": ReadPermission, "France": ReadPermission},
+	}
+
+	assert.Equal(t, []string{"Italy", "France"}, authorization.ScopeLocations(""))
+	assert.Equal(t, []string{"France"}, authorization.ScopeLocations("France,Germany"))
+	assert.Equal(t, []string{}, authorization.ScopeLocations("Germany"))
+	assert.False(t, authorization.CanWrite())
+
+	all := UserAuthorization{AllLocations: true, Permissions: map[string]string{AllLocation: WritePermission}}
+
+	assert.Equal(t, []string{"Germany"}, all.ScopeLocations("Germany"))
+	assert.True(t, all.CanAccessLocation("Germany"))
+	assert.True(t, all.CanWrite())
+}
+
+func TestUserAuthorization_Grant(t *testing.T) {
+	authorization := UserAuthorization{Locations: []string{}}
+
+	authorization.Grant("Italy", ReadPermission)
+	authorization.Grant("Germany", WritePermission)
+	authorization.Grant("Germany", ReadPermission)
+
+	assert.Equal(t, []string{"Italy", "Germany"}, authorization.Locations)
+	assert.True(t, authorization.CanWrite())
+	assert.False(t, authorization.CanWriteLocation("Italy"))
+	assert.True(t, authorization.CanWriteLocation("Germany"))
+	assert.False(t, authorization.CanWriteAllLocations())
+
+	authorization.Grant(AllLocation, ReadPermission)
+
+	assert.True(t, authorization.AllLocations)
+	assert.Equal(t, ReadPermission, authorization.PermissionOn("France"))
+	assert.Equal(t, WritePermission, authorization.PermissionOn("Germany"))
+
+	authorization.Grant(AllLocation, AdminPermission)
+
+	assert.Equal(t, AdminPermission, authorization.PermissionOn("Germany"))
+	assert.True(t, authorization.CanWriteAllLocations())
+}
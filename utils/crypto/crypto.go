@@ -17,7 +17,9 @@ package crypto
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -39,8 +41,12 @@ const (
 	minUpperCase   = 2
 	passwordLength = 16
 	usernameLength = 16
+	apiTokenLength = 32
 )
 
+// APITokenPrefix is the prefix of the API tokens, used to tell them apart from the JWT tokens
+const APITokenPrefix = "ercole_"
+
 var sample = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
 type Params struct {
@@ -125,3 +131,21 @@ func SuggestUsername() string {
 
 	return string(b)
 }
+
+// GenerateAPIToken return a new random API token, starting with APITokenPrefix
+func GenerateAPIToken() (string, error) {
+	b := make([]byte, apiTokenLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return APITokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashAPIToken return the hash of the API token, as it's stored in the database
+func HashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -30,6 +30,9 @@ var ErrUserNotFound = errors.New("User not found")
 // ErrEventEnqueue contains "Failed to enqueue event" error
 var ErrEventEnqueue = errors.New("Failed to enqueue event")
 
+// ErrHostDataIngestionNotFound contains "Hostdata ingestion not found" error
+var ErrHostDataIngestionNotFound = errors.New("Hostdata ingestion not found")
+
 // ErrLicenseNotFound contains "License not found" error
 var ErrLicenseNotFound = errors.New("License not found")
 
@@ -64,8 +67,20 @@ var ErrInvalidUser = errors.New("Invalid user")
 
 var ErrInvalidAck = errors.New("Alert(s) cannot be acknowledged")
 
+var ErrInvalidAlertRoutingRule = errors.New("Invalid alert routing rule")
+
+var ErrInvalidAlertStatusChange = errors.New("Alert status cannot be changed")
+
+var ErrInvalidAlertComment = errors.New("Invalid alert comment")
+
 var ErrInvalidToken = errors.New("invalid token")
 
+var ErrInvalidAPIToken = errors.New("Invalid API token")
+
+var ErrInvalidLicenseSimulation = errors.New("Invalid license simulation")
+
+var ErrInvalidHostDataReprocess = errors.New("Invalid hostdata reprocess request")
+
 // ErrHostNotInCluster
 var ErrHostNotInCluster = errors.New("host not in cluster")
 
@@ -92,3 +107,23 @@ var ErrSuperUserCannotBeDeleted = errors.New("Super User cannot be deleted")
 var ErrPermissionDenied = "Permission denied"
 
 var ErrInvalidExadata = errors.New("invalid exadata")
+
+var ErrHostIdentityNotFound = errors.New("Host identity not found")
+
+var ErrInvalidHostIdentity = errors.New("Invalid host identity")
+
+var ErrHostAliasInUse = errors.New("Host alias already in use")
+
+var ErrCmdbSourceNotFound = errors.New("CMDB source not found")
+
+var ErrInvalidHostMetadata = errors.New("Invalid host metadata")
+
+var ErrInvalidListQuery = errors.New("Invalid list query")
+
+var ErrInvalidCapacityForecastFilter = errors.New("Invalid capacity forecast filter")
+
+var ErrInvalidBackupComplianceFilter = errors.New("Invalid backup compliance filter")
+
+var ErrInvalidOraclePatchCatalogue = errors.New("Invalid Oracle patch catalogue")
+
+var ErrInvalidConsolidationRequest = errors.New("Invalid consolidation request")
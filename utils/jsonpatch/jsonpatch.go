@@ -0,0 +1,248 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+// Package jsonpatch computes and applies the differences between JSON documents,
+// as a subset (add, remove and replace operations) of JSON Patch (RFC 6902)
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operations of a patch
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Operation is a single change of a patch
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Diff return the operations that change the JSON document before into after
+func Diff(before, after []byte) ([]Operation, error) {
+	var b, a interface{}
+
+	if err := json.Unmarshal(before, &b); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(after, &a); err != nil {
+		return nil, err
+	}
+
+	return diff("", b, a, make([]Operation, 0)), nil
+}
+
+func diff(path string, before, after interface{}, ops []Operation) []Operation {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			return append(ops, Operation{Op: OpReplace, Path: path, Value: after})
+		}
+
+		for _, k := range sortedKeys(b) {
+			if av, ok := a[k]; ok {
+				ops = diff(path+"/"+escape(k), b[k], av, ops)
+			} else {
+				ops = append(ops, Operation{Op: OpRemove, Path: path + "/" + escape(k)})
+			}
+		}
+
+		for _, k := range sortedKeys(a) {
+			if _, ok := b[k]; !ok {
+				ops = append(ops, Operation{Op: OpAdd, Path: path + "/" + escape(k), Value: a[k]})
+			}
+		}
+
+		return ops
+
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok {
+			return append(ops, Operation{Op: OpReplace, Path: path, Value: after})
+		}
+
+		common := len(b)
+		if len(a) < common {
+			common = len(a)
+		}
+
+		for i := 0; i < common; i++ {
+			ops = diff(path+"/"+strconv.Itoa(i), b[i], a[i], ops)
+		}
+
+		for i := common; i < len(a); i++ {
+			ops = append(ops, Operation{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), Value: a[i]})
+		}
+
+		for i := len(b) - 1; i >= len(a); i-- {
+			ops = append(ops, Operation{Op: OpRemove, Path: path + "/" + strconv.Itoa(i)})
+		}
+
+		return ops
+
+	default:
+		if !reflect.DeepEqual(before, after) {
+			ops = append(ops, Operation{Op: OpReplace, Path: path, Value: after})
+		}
+
+		return ops
+	}
+}
+
+// Apply return the JSON document doc changed by the operations
+func Apply(doc []byte, ops []Operation) ([]byte, error) {
+	var value interface{}
+
+	if err := json.Unmarshal(doc, &value); err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		var err error
+
+		value, err = apply(value, parsePath(op.Path), op)
+		if err != nil {
+			return nil, fmt.Errorf("Can't apply %s %q: %w", op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(value)
+}
+
+func apply(value interface{}, tokens []string, op Operation) (interface{}, error) {
+	if len(tokens) == 0 {
+		if op.Op == OpRemove {
+			return nil, fmt.Errorf("can't remove the whole document")
+		}
+
+		return op.Value, nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		key := tokens[0]
+		child, exists := v[key]
+
+		if len(tokens) > 1 {
+			if !exists {
+				return nil, fmt.Errorf("missing key %q", key)
+			}
+
+			newChild, err := apply(child, tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = newChild
+
+			return v, nil
+		}
+
+		switch op.Op {
+		case OpAdd:
+			v[key] = op.Value
+		case OpReplace:
+			if !exists {
+				return nil, fmt.Errorf("missing key %q", key)
+			}
+
+			v[key] = op.Value
+		case OpRemove:
+			if !exists {
+				return nil, fmt.Errorf("missing key %q", key)
+			}
+
+			delete(v, key)
+		default:
+			return nil, fmt.Errorf("unknown operation %q", op.Op)
+		}
+
+		return v, nil
+
+	case []interface{}:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i > len(v) || (i == len(v) && (len(tokens) > 1 || op.Op != OpAdd)) {
+			return nil, fmt.Errorf("invalid index %q", tokens[0])
+		}
+
+		if len(tokens) > 1 {
+			newChild, err := apply(v[i], tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = newChild
+
+			return v, nil
+		}
+
+		switch op.Op {
+		case OpAdd:
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = op.Value
+		case OpReplace:
+			v[i] = op.Value
+		case OpRemove:
+			v = append(v[:i], v[i+1:]...)
+		default:
+			return nil, fmt.Errorf("unknown operation %q", op.Op)
+		}
+
+		return v, nil
+
+	default:
+		return nil, fmt.Errorf("%q isn't an object or an array", tokens[0])
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func parsePath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+
+	return tokens
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAndApply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		before   string
+		after    string
+		expected []Operation
+	}{
+		{
+			name:     "Equal",
+			before:   `{"a": 1, "b": [1, 2], "c": {"d": "e"}}`,
+			after:    `{"a": 1, "b": [1, 2], "c": {"d": "e"}}`,
+			expected: []Operation{},
+		},
+		{
+			name:   "Changed fields",
+			before: `{"a": 1, "b": "foo", "c": {"d": "e"}, "f": true}`,
+			after:  `{"a": 2, "c": {"d": "e", "g/h": null}, "f": false, "i": [1]}`,
+			expected: []Operation{
+				{Op: OpReplace, Path: "/a", Value: float64(2)},
+				{Op: OpRemove, Path: "/b"},
+				{Op: OpAdd, Path: "/c/g~1h"},
+				{Op: OpReplace, Path: "/f", Value: false},
+				{Op: OpAdd, Path: "/i", Value: []interface{}{float64(1)}},
+			},
+		},
+		{
+			name:   "Longer array",
+			before: `{"dbs": [{"name": "a", "size": 1}]}`,
+			after:  `{"dbs": [{"name": "a", "size": 2}, {"name": "b", "size": 3}]}`,
+			expected: []Operation{
+				{Op: OpReplace, Path: "/dbs/0/size", Value: float64(2)},
+				{Op: OpAdd, Path: "/dbs/1", Value: map[string]interface{}{"name": "b", "size": float64(3)}},
+			},
+		},
+		{
+			name:   "Shorter array",
+			before: `{"dbs": ["a", "b", "c"]}`,
+			after:  `{"dbs": ["x"]}`,
+			expected: []Operation{
+				{Op: OpReplace, Path: "/dbs/0", Value: "x"},
+				{Op: OpRemove, Path: "/dbs/2"},
+				{Op: OpRemove, Path: "/dbs/1"},
+			},
+		},
+		{
+			name:   "Changed type",
+			before: `{"a": {"b": 1}}`,
+			after:  `{"a": [1]}`,
+			expected: []Operation{
+				{Op: OpReplace, Path: "/a", Value: []interface{}{float64(1)}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ops, err := Diff([]byte(tc.before), []byte(tc.after))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ops)
+
+			actual, err := Apply([]byte(tc.before), ops)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.after, string(actual))
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		doc  string
+		op   Operation
+	}{
+		{name: "Missing key", doc: `{"a": 1}`, op: Operation{Op: OpReplace, Path: "/b", Value: 1}},
+		{name: "Missing parent", doc: `{"a": 1}`, op: Operation{Op: OpAdd, Path: "/b/c", Value: 1}},
+		{name: "Index out of range", doc: `{"a": [1]}`, op: Operation{Op: OpRemove, Path: "/a/1"}},
+		{name: "Not a container", doc: `{"a": 1}`, op: Operation{Op: OpAdd, Path: "/a/b", Value: 1}},
+		{name: "Remove root", doc: `{"a": 1}`, op: Operation{Op: OpRemove, Path: ""}},
+		{name: "Unknown operation", doc: `{"a": 1}`, op: Operation{Op: "move", Path: "/a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Apply([]byte(tc.doc), []Operation{tc.op})
+			assert.Error(t, err)
+		})
+	}
+}
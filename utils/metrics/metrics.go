@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package metrics contains the service-internal metrics exported in the OpenMetrics format
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace is the prefix of the names of all the ercole metrics
+const Namespace = "ercole"
+
+// Registry contains the service-internal metrics shared by all the services of the process
+var Registry = prometheus.NewRegistry()
+
+var httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Name:      "http_request_duration_seconds",
+	Help:      "Duration of the HTTP requests by service, route, method and status code",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"service", "route", "method", "code"})
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+	)
+}
+
+// Middleware return a middleware that measures the latency of the requests served by the routes of service
+func Middleware(service string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := ""
+			if current := mux.CurrentRoute(r); current != nil {
+				route, _ = current.GetPathTemplate()
+			}
+
+			m := httpsnoop.CaptureMetrics(next, w, r)
+
+			httpRequestDuration.
+				WithLabelValues(service, route, r.Method, strconv.Itoa(m.Code)).
+				Observe(m.Duration.Seconds())
+		})
+	}
+}
+
+// RegisterGaugeFunc registers in Registry a gauge whose value is returned by function.
+// A gauge with the same name already registered is replaced
+func RegisterGaugeFunc(name, help string, function func() float64) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      name,
+		Help:      help,
+	}, function)
+
+	if err := Registry.Register(gauge); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			Registry.Unregister(are.ExistingCollector)
+			Registry.MustRegister(gauge)
+		}
+	}
+}
+
+// Handler return an handler that exports the metrics of the gatherers and of Registry
+func Handler(gatherers ...prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(
+		append(prometheus.Gatherers{Registry}, gatherers...),
+		promhttp.HandlerOpts{EnableOpenMetrics: true},
+	)
+}
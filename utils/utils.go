@@ -196,6 +196,16 @@ func NewAPIUrlNoParams(baseURL string, username string, password string, path st
 	return u
 }
 
+// SetAPIToken authenticates the request with the API token, if any, instead of the credentials of the url
+func SetAPIToken(req *http.Request, token string) {
+	if token == "" {
+		return
+	}
+
+	req.URL.User = nil
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
 // FindNamedMatches return the map of the groups of str
 func FindNamedMatches(regex *regexp.Regexp, str string) map[string]string {
 	match := regex.FindStringSubmatch(str)